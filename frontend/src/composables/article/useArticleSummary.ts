@@ -9,6 +9,10 @@ interface SummarySettings {
   triggerMode: string;
 }
 
+export type SummaryStyle = 'standard' | 'tldr' | 'bullets' | 'quotes' | 'eli5';
+
+export const summaryStyles: SummaryStyle[] = ['standard', 'tldr', 'bullets', 'quotes', 'eli5'];
+
 interface SummaryResult {
   summary: string;
   html?: string;
@@ -28,6 +32,7 @@ export function useArticleSummary() {
     provider: 'local',
     triggerMode: 'auto',
   });
+  const summaryStyle = ref<SummaryStyle>('standard');
   const summaryCache: Ref<Map<number, SummaryResult>> = ref(new Map());
   const loadingSummaries: Ref<Set<number>> = ref(new Set());
   const abortControllers: Ref<Map<number, any>> = ref(new Map());
@@ -95,6 +100,7 @@ export function useArticleSummary() {
         body: JSON.stringify({
           article_id: article.id,
           length: summarySettings.value.length,
+          style: summaryStyle.value,
           content: content,
         }),
         signal: controller?.signal,
@@ -196,6 +202,12 @@ export function useArticleSummary() {
     }
   }
 
+  // Switch the summary style, the stored summaries of the new style are fetched on next request
+  function setSummaryStyle(style: SummaryStyle): void {
+    summaryStyle.value = style;
+    clearSummaryCache();
+  }
+
   // Update summary settings from event
   function handleSummarySettingsChange(
     enabled: boolean,
@@ -215,6 +227,7 @@ export function useArticleSummary() {
 
   return {
     summarySettings,
+    summaryStyle,
     loadingSummaries,
     loadSummarySettings,
     generateSummary,
@@ -223,5 +236,6 @@ export function useArticleSummary() {
     clearSummaryCache,
     cancelSummaryGeneration,
     handleSummarySettingsChange,
+    setSummaryStyle,
   };
 }
@@ -2,10 +2,16 @@ import { ref, type Ref } from 'vue';
 import { useI18n } from 'vue-i18n';
 import type { Article } from '@/types/models';
 
+// Delay before translating queued titles, so that articles coming into view together share a request
+const BATCH_DELAY_MS = 100;
+// Titles translated in one request, the server accepts up to 100
+const MAX_BATCH_SIZE = 50;
+
 interface TranslationSettings {
   enabled: boolean;
   targetLang: string;
   translationOnlyMode: boolean;
+  fullArticleTranslation: boolean;
 }
 
 export function useArticleTranslation() {
@@ -14,8 +20,11 @@ export function useArticleTranslation() {
     enabled: false,
     targetLang: 'en',
     translationOnlyMode: false,
+    fullArticleTranslation: false,
   });
   const translatingArticles: Ref<Set<number>> = ref(new Set());
+  const pendingArticles: Article[] = [];
+  let flushTimer: ReturnType<typeof setTimeout> | null = null;
   let observer: IntersectionObserver | null = null;
 
   // Load translation settings
@@ -27,6 +36,7 @@ export function useArticleTranslation() {
         enabled: data.translation_enabled === 'true',
         targetLang: data.target_language || 'en',
         translationOnlyMode: data.translation_only_mode === 'true',
+        fullArticleTranslation: data.full_article_translation === 'true',
       };
     } catch (e) {
       console.error('Error loading translation settings:', e);
@@ -70,20 +80,47 @@ export function useArticleTranslation() {
     }
   }
 
-  // Translate an article
-  async function translateArticle(article: Article): Promise<void> {
+  // Whether a detected language is the target language, ignoring regions ("zh" matches "zh-CN")
+  function isTargetLanguage(language: string | undefined): boolean {
+    if (!language) return false;
+    const base = (code: string) => code.split('-')[0].toLowerCase();
+    return base(language) === base(translationSettings.value.targetLang);
+  }
+
+  // Queue an article for title translation. Articles that come into view together are
+  // translated in one request.
+  function translateArticle(article: Article): void {
     if (translatingArticles.value.has(article.id)) return;
 
+    // Articles detected in the target language when they were saved need no request
+    if (isTargetLanguage(article.language)) {
+      article.translated_title = article.title;
+      return;
+    }
+
     translatingArticles.value.add(article.id);
+    pendingArticles.push(article);
+    if (!flushTimer) {
+      flushTimer = setTimeout(flushTranslations, BATCH_DELAY_MS);
+    }
+  }
+
+  // Translate the titles of the queued articles
+  async function flushTranslations(): Promise<void> {
+    flushTimer = null;
+    const articles = pendingArticles.splice(0, MAX_BATCH_SIZE);
+    if (pendingArticles.length > 0) {
+      flushTimer = setTimeout(flushTranslations, 0);
+    }
+    if (articles.length === 0) return;
 
     try {
       const requestBody = {
-        article_id: article.id,
-        title: article.title,
+        articles: articles.map((article) => ({ article_id: article.id, title: article.title })),
         target_language: translationSettings.value.targetLang,
       };
 
-      const res = await fetch('/api/articles/translate', {
+      const res = await fetch('/api/articles/translate-batch', {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify(requestBody),
@@ -92,22 +129,28 @@ export function useArticleTranslation() {
       if (res.ok) {
         const data = await res.json();
 
-        // Update the article in the store
-        article.translated_title = data.translated_title;
+        // Update the articles in the store
+        const articlesById = new Map(articles.map((article) => [article.id, article]));
+        for (const translation of data.translations || []) {
+          const article = articlesById.get(translation.article_id);
+          if (article) {
+            article.translated_title = translation.translated_title;
+          }
+        }
 
         // Show notification if AI limit was reached
         if (data.limit_reached) {
           window.showToast(t('aiLimitReached'), 'warning');
         }
       } else {
-        console.error('Error translating article:', res.status);
+        console.error('Error translating articles:', res.status);
         window.showToast(t('errorTranslatingTitle'), 'error');
       }
     } catch (e) {
-      console.error('Error translating article:', e);
+      console.error('Error translating articles:', e);
       window.showToast(t('errorTranslating'), 'error');
     } finally {
-      translatingArticles.value.delete(article.id);
+      articles.forEach((article) => translatingArticles.value.delete(article.id));
     }
   }
 
@@ -138,6 +181,12 @@ export function useArticleTranslation() {
 
   // Cleanup
   function cleanup(): void {
+    if (flushTimer) {
+      clearTimeout(flushTimer);
+      flushTimer = null;
+    }
+    pendingArticles.forEach((article) => translatingArticles.value.delete(article.id));
+    pendingArticles.length = 0;
     if (observer) {
       observer.disconnect();
       observer = null;
@@ -15,15 +15,25 @@ export function generateInitialSettings(): SettingsData {
   return {
     ai_api_key: settingsDefaults.ai_api_key,
     ai_chat_enabled: settingsDefaults.ai_chat_enabled,
+    ai_chat_tools_enabled: settingsDefaults.ai_chat_tools_enabled,
+    ai_classification_enabled: settingsDefaults.ai_classification_enabled,
+    ai_classification_topics: settingsDefaults.ai_classification_topics,
     ai_custom_headers: settingsDefaults.ai_custom_headers,
+    ai_embedding_enabled: settingsDefaults.ai_embedding_enabled,
+    ai_embedding_endpoint: settingsDefaults.ai_embedding_endpoint,
+    ai_embedding_model: settingsDefaults.ai_embedding_model,
     ai_endpoint: settingsDefaults.ai_endpoint,
     ai_model: settingsDefaults.ai_model,
     ai_summary_prompt: settingsDefaults.ai_summary_prompt,
+    ai_translation_google_fallback: settingsDefaults.ai_translation_google_fallback,
     ai_translation_prompt: settingsDefaults.ai_translation_prompt,
     ai_usage_limit: settingsDefaults.ai_usage_limit,
     ai_usage_tokens: settingsDefaults.ai_usage_tokens,
     auto_cleanup_enabled: settingsDefaults.auto_cleanup_enabled,
     auto_show_all_content: settingsDefaults.auto_show_all_content,
+    background_jobs_concurrency: settingsDefaults.background_jobs_concurrency,
+    background_summarize_categories: settingsDefaults.background_summarize_categories,
+    background_translate_categories: settingsDefaults.background_translate_categories,
     baidu_app_id: settingsDefaults.baidu_app_id,
     baidu_secret_key: settingsDefaults.baidu_secret_key,
     close_to_tray: settingsDefaults.close_to_tray,
@@ -50,6 +60,7 @@ export function generateInitialSettings(): SettingsData {
     freshrss_server_url: settingsDefaults.freshrss_server_url,
     freshrss_sync_on_startup: settingsDefaults.freshrss_sync_on_startup,
     freshrss_username: settingsDefaults.freshrss_username,
+    full_article_translation: settingsDefaults.full_article_translation,
     full_text_fetch_enabled: settingsDefaults.full_text_fetch_enabled,
     google_translate_endpoint: settingsDefaults.google_translate_endpoint,
     hover_mark_as_read: settingsDefaults.hover_mark_as_read,
@@ -57,6 +68,8 @@ export function generateInitialSettings(): SettingsData {
     language: settingsDefaults.language,
     last_global_refresh: settingsDefaults.last_global_refresh,
     last_network_test: settingsDefaults.last_network_test,
+    libretranslate_api_key: settingsDefaults.libretranslate_api_key,
+    libretranslate_endpoint: settingsDefaults.libretranslate_endpoint,
     max_article_age_days: settingsDefaults.max_article_age_days,
     max_cache_size_mb: settingsDefaults.max_cache_size_mb,
     max_concurrent_refreshes: settingsDefaults.max_concurrent_refreshes,
@@ -113,15 +126,29 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
   return {
     ai_api_key: data.ai_api_key || settingsDefaults.ai_api_key,
     ai_chat_enabled: data.ai_chat_enabled === 'true',
+    ai_chat_tools_enabled: data.ai_chat_tools_enabled === 'true',
+    ai_classification_enabled: data.ai_classification_enabled === 'true',
+    ai_classification_topics:
+      data.ai_classification_topics || settingsDefaults.ai_classification_topics,
     ai_custom_headers: data.ai_custom_headers || settingsDefaults.ai_custom_headers,
+    ai_embedding_enabled: data.ai_embedding_enabled === 'true',
+    ai_embedding_endpoint: data.ai_embedding_endpoint || settingsDefaults.ai_embedding_endpoint,
+    ai_embedding_model: data.ai_embedding_model || settingsDefaults.ai_embedding_model,
     ai_endpoint: data.ai_endpoint || settingsDefaults.ai_endpoint,
     ai_model: data.ai_model || settingsDefaults.ai_model,
     ai_summary_prompt: data.ai_summary_prompt || settingsDefaults.ai_summary_prompt,
+    ai_translation_google_fallback: data.ai_translation_google_fallback === 'true',
     ai_translation_prompt: data.ai_translation_prompt || settingsDefaults.ai_translation_prompt,
     ai_usage_limit: data.ai_usage_limit || settingsDefaults.ai_usage_limit,
     ai_usage_tokens: data.ai_usage_tokens || settingsDefaults.ai_usage_tokens,
     auto_cleanup_enabled: data.auto_cleanup_enabled === 'true',
     auto_show_all_content: data.auto_show_all_content === 'true',
+    background_jobs_concurrency:
+      parseInt(data.background_jobs_concurrency) || settingsDefaults.background_jobs_concurrency,
+    background_summarize_categories:
+      data.background_summarize_categories || settingsDefaults.background_summarize_categories,
+    background_translate_categories:
+      data.background_translate_categories || settingsDefaults.background_translate_categories,
     baidu_app_id: data.baidu_app_id || settingsDefaults.baidu_app_id,
     baidu_secret_key: data.baidu_secret_key || settingsDefaults.baidu_secret_key,
     close_to_tray: data.close_to_tray === 'true',
@@ -158,6 +185,7 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
     freshrss_server_url: data.freshrss_server_url || settingsDefaults.freshrss_server_url,
     freshrss_sync_on_startup: data.freshrss_sync_on_startup === 'true',
     freshrss_username: data.freshrss_username || settingsDefaults.freshrss_username,
+    full_article_translation: data.full_article_translation === 'true',
     full_text_fetch_enabled: data.full_text_fetch_enabled === 'true',
     google_translate_endpoint:
       data.google_translate_endpoint || settingsDefaults.google_translate_endpoint,
@@ -166,6 +194,9 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
     language: data.language || settingsDefaults.language,
     last_global_refresh: data.last_global_refresh || settingsDefaults.last_global_refresh,
     last_network_test: data.last_network_test || settingsDefaults.last_network_test,
+    libretranslate_api_key: data.libretranslate_api_key || settingsDefaults.libretranslate_api_key,
+    libretranslate_endpoint:
+      data.libretranslate_endpoint || settingsDefaults.libretranslate_endpoint,
     max_article_age_days:
       parseInt(data.max_article_age_days) || settingsDefaults.max_article_age_days,
     max_cache_size_mb: parseInt(data.max_cache_size_mb) || settingsDefaults.max_cache_size_mb,
@@ -229,10 +260,28 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     ai_chat_enabled: (
       settingsRef.value.ai_chat_enabled ?? settingsDefaults.ai_chat_enabled
     ).toString(),
+    ai_chat_tools_enabled: (
+      settingsRef.value.ai_chat_tools_enabled ?? settingsDefaults.ai_chat_tools_enabled
+    ).toString(),
+    ai_classification_enabled: (
+      settingsRef.value.ai_classification_enabled ?? settingsDefaults.ai_classification_enabled
+    ).toString(),
+    ai_classification_topics:
+      settingsRef.value.ai_classification_topics ?? settingsDefaults.ai_classification_topics,
     ai_custom_headers: settingsRef.value.ai_custom_headers ?? settingsDefaults.ai_custom_headers,
+    ai_embedding_enabled: (
+      settingsRef.value.ai_embedding_enabled ?? settingsDefaults.ai_embedding_enabled
+    ).toString(),
+    ai_embedding_endpoint:
+      settingsRef.value.ai_embedding_endpoint ?? settingsDefaults.ai_embedding_endpoint,
+    ai_embedding_model: settingsRef.value.ai_embedding_model ?? settingsDefaults.ai_embedding_model,
     ai_endpoint: settingsRef.value.ai_endpoint ?? settingsDefaults.ai_endpoint,
     ai_model: settingsRef.value.ai_model ?? settingsDefaults.ai_model,
     ai_summary_prompt: settingsRef.value.ai_summary_prompt ?? settingsDefaults.ai_summary_prompt,
+    ai_translation_google_fallback: (
+      settingsRef.value.ai_translation_google_fallback ??
+      settingsDefaults.ai_translation_google_fallback
+    ).toString(),
     ai_translation_prompt:
       settingsRef.value.ai_translation_prompt ?? settingsDefaults.ai_translation_prompt,
     ai_usage_limit: settingsRef.value.ai_usage_limit ?? settingsDefaults.ai_usage_limit,
@@ -243,6 +292,15 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     auto_show_all_content: (
       settingsRef.value.auto_show_all_content ?? settingsDefaults.auto_show_all_content
     ).toString(),
+    background_jobs_concurrency: (
+      settingsRef.value.background_jobs_concurrency ?? settingsDefaults.background_jobs_concurrency
+    ).toString(),
+    background_summarize_categories:
+      settingsRef.value.background_summarize_categories ??
+      settingsDefaults.background_summarize_categories,
+    background_translate_categories:
+      settingsRef.value.background_translate_categories ??
+      settingsDefaults.background_translate_categories,
     baidu_app_id: settingsRef.value.baidu_app_id ?? settingsDefaults.baidu_app_id,
     baidu_secret_key: settingsRef.value.baidu_secret_key ?? settingsDefaults.baidu_secret_key,
     close_to_tray: (settingsRef.value.close_to_tray ?? settingsDefaults.close_to_tray).toString(),
@@ -290,6 +348,9 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
       settingsRef.value.freshrss_sync_on_startup ?? settingsDefaults.freshrss_sync_on_startup
     ).toString(),
     freshrss_username: settingsRef.value.freshrss_username ?? settingsDefaults.freshrss_username,
+    full_article_translation: (
+      settingsRef.value.full_article_translation ?? settingsDefaults.full_article_translation
+    ).toString(),
     full_text_fetch_enabled: (
       settingsRef.value.full_text_fetch_enabled ?? settingsDefaults.full_text_fetch_enabled
     ).toString(),
@@ -303,6 +364,10 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     ).toString(),
     language: settingsRef.value.language ?? settingsDefaults.language,
     last_network_test: settingsRef.value.last_network_test ?? settingsDefaults.last_network_test,
+    libretranslate_api_key:
+      settingsRef.value.libretranslate_api_key ?? settingsDefaults.libretranslate_api_key,
+    libretranslate_endpoint:
+      settingsRef.value.libretranslate_endpoint ?? settingsDefaults.libretranslate_endpoint,
     max_article_age_days: (
       settingsRef.value.max_article_age_days ?? settingsDefaults.max_article_age_days
     ).toString(),
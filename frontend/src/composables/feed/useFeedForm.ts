@@ -50,6 +50,11 @@ export function useFeedForm(feed?: Feed) {
 
   // Auto expand content mode
   const autoExpandContent = ref<'global' | 'enabled' | 'disabled'>('global');
+  const fullTextOnIngest = ref(false);
+  const backgroundTranslate = ref(false);
+  const backgroundSummarize = ref(false);
+  const summaryPromptId = ref(0);
+  const translationPromptId = ref(0);
 
   // Proxy settings
   const proxyMode = ref<ProxyMode>('global');
@@ -235,6 +240,11 @@ export function useFeedForm(feed?: Feed) {
     // Initialize auto expand content mode
     autoExpandContent.value =
       (feed.auto_expand_content as 'global' | 'enabled' | 'disabled') || 'global';
+    fullTextOnIngest.value = feed.full_text_on_ingest || false;
+    backgroundTranslate.value = feed.background_translate || false;
+    backgroundSummarize.value = feed.background_summarize || false;
+    summaryPromptId.value = feed.summary_prompt_id || 0;
+    translationPromptId.value = feed.translation_prompt_id || 0;
 
     // Determine feed type based on feed properties
     if (feed.script_path) {
@@ -325,6 +335,11 @@ export function useFeedForm(feed?: Feed) {
     emailFolder.value = 'INBOX';
     articleViewMode.value = 'global';
     autoExpandContent.value = 'global';
+    fullTextOnIngest.value = false;
+    backgroundTranslate.value = false;
+    backgroundSummarize.value = false;
+    summaryPromptId.value = 0;
+    translationPromptId.value = 0;
     proxyMode.value = 'global';
     proxyType.value = 'http';
     proxyHost.value = '';
@@ -391,6 +406,11 @@ export function useFeedForm(feed?: Feed) {
     emailFolder,
     articleViewMode,
     autoExpandContent,
+    fullTextOnIngest,
+    backgroundTranslate,
+    backgroundSummarize,
+    summaryPromptId,
+    translationPromptId,
     proxyMode,
     proxyType,
     proxyHost,
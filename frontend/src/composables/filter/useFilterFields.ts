@@ -23,6 +23,10 @@ export function useFilterFields() {
     { value: 'is_read', labelKey: 'readStatus', multiSelect: false, booleanField: true },
     { value: 'is_favorite', labelKey: 'favoriteStatus', multiSelect: false, booleanField: true },
     { value: 'is_read_later', labelKey: 'readLaterStatus', multiSelect: false, booleanField: true },
+    { value: 'topic', labelKey: 'articleTopic', multiSelect: true },
+    { value: 'entity', labelKey: 'articleEntity', multiSelect: true },
+    { value: 'language', labelKey: 'articleLanguage', multiSelect: true },
+    { value: 'keyword', labelKey: 'articleKeyword', multiSelect: false },
   ];
 
   /**
@@ -109,7 +113,14 @@ export function useFilterFields() {
    * Check if field supports multiple values
    */
   function isMultiSelectField(field: string): boolean {
-    return field === 'feed_name' || field === 'feed_category' || field === 'feed_type';
+    return (
+      field === 'feed_name' ||
+      field === 'feed_category' ||
+      field === 'feed_type' ||
+      field === 'topic' ||
+      field === 'entity' ||
+      field === 'language'
+    );
   }
 
   /**
@@ -1,4 +1,4 @@
-import { computed, type ComputedRef } from 'vue';
+import { computed, ref, type ComputedRef } from 'vue';
 import { useAppStore } from '@/stores/app';
 
 export interface Condition {
@@ -23,8 +23,38 @@ export interface ActionOption {
   labelKey: string;
 }
 
+// Topics and entities assigned by AI classification and the languages detected when articles
+// were saved, loaded once and shared by all conditions
+const articleTopics = ref<string[]>([]);
+const articleEntities = ref<string[]>([]);
+const articleLanguages = ref<string[]>([]);
+let articleTagsLoaded = false;
+
+async function fetchArticleTags(kind: 'topic' | 'entity' | 'language'): Promise<string[]> {
+  const response = await fetch(`/api/articles/tags?kind=${kind}`);
+  if (!response.ok) return [];
+  const tags: Array<{ value: string }> = await response.json();
+  return tags.map((tag) => tag.value);
+}
+
+async function loadArticleTags(): Promise<void> {
+  if (articleTagsLoaded) return;
+  articleTagsLoaded = true;
+  try {
+    [articleTopics.value, articleEntities.value, articleLanguages.value] = await Promise.all([
+      fetchArticleTags('topic'),
+      fetchArticleTags('entity'),
+      fetchArticleTags('language'),
+    ]);
+  } catch (error) {
+    articleTagsLoaded = false;
+    console.error('Failed to load article tags:', error);
+  }
+}
+
 export function useRuleOptions() {
   const store = useAppStore();
+  loadArticleTags();
 
   // Field options for conditions
   const fieldOptions: FieldOption[] = [
@@ -44,6 +74,12 @@ export function useRuleOptions() {
     { value: 'is_favorite', labelKey: 'favoriteStatus', multiSelect: false, booleanField: true },
     { value: 'is_hidden', labelKey: 'hiddenStatus', multiSelect: false, booleanField: true },
     { value: 'is_read_later', labelKey: 'readLaterStatus', multiSelect: false, booleanField: true },
+    { value: 'relevance_above', labelKey: 'relevanceAbove', multiSelect: false },
+    { value: 'relevance_below', labelKey: 'relevanceBelow', multiSelect: false },
+    { value: 'topic', labelKey: 'articleTopic', multiSelect: true },
+    { value: 'entity', labelKey: 'articleEntity', multiSelect: true },
+    { value: 'language', labelKey: 'articleLanguage', multiSelect: true },
+    { value: 'keyword', labelKey: 'articleKeyword', multiSelect: false },
   ];
 
   // Operator options for article title
@@ -122,16 +158,35 @@ export function useRuleOptions() {
     feedNames,
     feedCategories,
     feedTypes,
+    articleTopics,
+    articleEntities,
+    articleLanguages,
   };
 }
 
+// Display name of a detected language code in the language itself, like the language settings
+export function getLanguageLabel(code: string): string {
+  try {
+    return new Intl.DisplayNames([code], { type: 'language' }).of(code) || code;
+  } catch {
+    return code;
+  }
+}
+
 // Helper functions for field types
 export function isDateField(field: string): boolean {
   return field === 'published_after' || field === 'published_before';
 }
 
 export function isMultiSelectField(field: string): boolean {
-  return field === 'feed_name' || field === 'feed_category' || field === 'feed_type';
+  return (
+    field === 'feed_name' ||
+    field === 'feed_category' ||
+    field === 'feed_type' ||
+    field === 'topic' ||
+    field === 'entity' ||
+    field === 'language'
+  );
 }
 
 export function isBooleanField(field: string): boolean {
@@ -29,9 +29,33 @@ const en: TranslationMessages = {
   aiApiKeyDesc: 'API key for AI services (optional)',
   aiApiKeyMissing: 'API key is missing or invalid. Please configure it in settings.',
   aiApiKeyPlaceholder: 'Enter your API key',
+  aiBudgetAdd: 'Add Budget',
+  aiBudgetAllFeatures: 'All features',
+  aiBudgetMaxCost: 'Max cost (USD)',
+  aiBudgetMaxTokens: 'Max tokens',
+  aiBudgetPeriod_daily: 'Daily',
+  aiBudgetPeriod_monthly: 'Monthly',
+  aiBudgets: 'Budgets',
+  aiBudgetsDesc:
+    'Limit tokens or estimated cost per day or month. AI features over their budget fall back or pause until the next period.',
   aiChat: 'AI Chat',
   aiChatEnabled: 'AI Chat',
   aiChatEnabledDesc: 'Chat with AI for answers to article-related questions',
+  aiChatToolsEnabled: 'Let Chat Act on Articles',
+  aiChatToolsEnabledDesc:
+    'Allow chat to search articles, fetch their content, mark them read or favorite, add them to read later and create rules',
+  chatHistory: 'Chat History',
+  chatHistoryDesc:
+    'Chat sessions of every article, newest first. Pinned sessions are kept when their article is cleaned up.',
+  chatHistoryExportedToObsidian: 'Chat exported to Obsidian',
+  chatHistoryExportMarkdown: 'Export to Markdown',
+  chatHistoryIncludeArticle: 'Export article details',
+  chatHistoryIncludeThinking: 'Export thinking',
+  chatHistoryMessages: '{count} messages',
+  chatHistoryNoMatches: 'No messages found',
+  chatHistoryPin: 'Pin',
+  chatHistorySearch: 'Search all chat messages...',
+  chatHistoryUnpin: 'Unpin',
   clearAllChats: 'Clear Chat History',
   clearAllChatsDesc: 'Delete all AI chat sessions',
   clearAllChatsButton: 'Clear',
@@ -66,6 +90,17 @@ const en: TranslationMessages = {
   aiEndpoint: 'API Endpoint',
   aiEndpointDesc: 'Full API endpoint URL including path',
   aiEndpointPlaceholder: 'https://api.openai.com/v1/chat/completions',
+  aiEmbeddingEndpoint: 'Embedding Endpoint',
+  aiEmbeddingEndpointDesc: 'Endpoint for embeddings, leave empty to use the API endpoint above',
+  aiEmbeddingEndpointPlaceholder: 'Same as API endpoint',
+  aiEmbeddingModel: 'Embedding Model',
+  aiEmbeddingModelDesc: 'Model used to embed articles, e.g. text-embedding-3-small or nomic-embed-text',
+  aiClassificationEnabled: 'Article Classification',
+  aiClassificationEnabledDesc:
+    'Sort new articles into your topics, extract the people and organizations they mention and add a line on why they matter',
+  aiClassificationTopics: 'Topics',
+  aiClassificationTopicsDesc: 'One topic per line or separated by commas, usable in rules and filters',
+  aiClassificationTopicsPlaceholder: 'Space\nElections\nOpen source',
   aiFeatures: 'AI Features',
   aiIsDanger:
     'Using AI services may incur costs, and some features may consume a significant number of tokens. Please ensure you understand the associated cost structure and monitor the usage accordingly.',
@@ -81,6 +116,24 @@ const en: TranslationMessages = {
   aiCustomHeadersValue: 'Header Value',
   aiCustomHeadersRemove: 'Remove',
   aiCustomHeadersInvalid: 'Invalid JSON format for custom headers',
+  aiProfileAdd: 'Add Profile',
+  aiProfileDeleteConfirm: 'Delete the AI profile "{name}"? Tasks routed to it fall back to their other profiles.',
+  aiProfileHeadersPlaceholder: 'Custom headers as a JSON object (optional)',
+  aiProfileName: 'Profile name',
+  aiProfileSaveError: 'Failed to save AI profile',
+  aiProfileSaved: 'AI profile saved',
+  aiProfiles: 'Provider Profiles',
+  aiProfilesDesc: 'Named AI providers that features can be routed to, each with its own model, key and usage limit',
+  aiProfilesEmpty: 'No profiles yet. All AI features use the settings above.',
+  aiRouteAddProfile: 'Add profile…',
+  aiRouteDefault: 'Uses the settings above',
+  aiRouteTask_chat: 'Chat',
+  aiRouteTask_classification: 'Classification',
+  aiRouteTask_embedding: 'Embeddings',
+  aiRouteTask_summary: 'Summaries',
+  aiRouteTask_translation: 'Translation',
+  aiRouting: 'Routing',
+  aiRoutingDesc: 'Profiles are tried in order; the next one is used when a profile fails or reaches its usage limit',
   aiSettings: 'AI Settings',
   aiSettingsConfiguredInAITab:
     'AI API Key, Endpoint, and Model are configured in the AI tab. This feature can consume a significant number of tokens, please use with caution.',
@@ -97,11 +150,17 @@ const en: TranslationMessages = {
   aiSystemPromptPlaceholder:
     'Default: You are a translator. Translate the given text accurately. Output ONLY the translated text, nothing else.',
   aiTranslation: 'AI Translation',
+  aiTranslationGoogleFallback: 'Fall back to Google Translate',
+  aiTranslationGoogleFallbackDesc:
+    'Use Google Translate when every AI profile fails or reaches its usage limit',
   aiTranslationPrompt: 'Translation Prompt',
   aiTranslationPromptDesc: 'Custom system prompt for AI translation',
   aiTranslationPromptPlaceholder:
     'You are a translator. Translate the given text accurately. Output ONLY the translated text, nothing else.',
   aiUsage: 'AI Usage',
+  aiUsageByFeature: 'By Feature',
+  aiUsageByModel: 'By Model',
+  aiUsageByProvider: 'By Provider',
   aiUsageFallback: 'Using free alternative due to AI limit',
   aiUsageLimit: 'Usage Limit',
   aiUsageLimitDesc:
@@ -145,6 +204,10 @@ const en: TranslationMessages = {
   articles: 'Articles',
   articleSummary: 'Article Summary',
   articleTitle: 'Article Title',
+  articleTopic: 'Topic',
+  articleEntity: 'Entity',
+  articleLanguage: 'Language',
+  whyItMatters: 'Why it matters',
   audioPlaybackError:
     'Failed to play audio. The file may be unavailable or in an unsupported format.',
   auto: 'Auto (Follow System)',
@@ -157,6 +220,22 @@ const en: TranslationMessages = {
   backToRss: 'Back to RSS',
   backToSimple: 'Back to Simple',
   backToUrl: 'Back to URL',
+  backgroundCategoriesPlaceholder: 'e.g. Tech, Science',
+  backgroundJobs: 'Background jobs',
+  backgroundJobsConcurrency: 'Concurrent Background Jobs',
+  backgroundJobsConcurrencyDesc:
+    'Number of background translations and summaries run at the same time',
+  backgroundJobsStatus: '{pending} pending, {running} running, {failed} failed',
+  backgroundSummarize: 'Summarize in Background',
+  backgroundSummarizeCategories: 'Background Summary Categories',
+  backgroundSummarizeCategoriesDesc:
+    'Summarize new articles of these categories, separated by commas, as soon as they are fetched',
+  backgroundSummarizeDesc: 'Generate summaries of new articles as soon as they are fetched',
+  backgroundTranslate: 'Translate Titles in Background',
+  backgroundTranslateCategories: 'Background Translation Categories',
+  backgroundTranslateCategoriesDesc:
+    'Translate the titles of new articles of these categories, separated by commas, as soon as they are fetched',
+  backgroundTranslateDesc: 'Translate the titles of new articles as soon as they are fetched',
   baiduAppId: 'Baidu App ID',
   baiduAppIdDesc: 'Enter the Baidu Translate App ID',
   baiduAppIdPlaceholder: 'Enter your App ID',
@@ -310,6 +389,9 @@ const en: TranslationMessages = {
   enableTranslationDesc: 'Automatically translate article titles to the preferred language',
   translationOnlyMode: 'Translation Only Mode',
   translationOnlyModeDesc: 'Show only translated text, hide original content',
+  fullArticleTranslation: 'Full Article Translation',
+  fullArticleTranslationDesc:
+    'Translate the whole article on the server block by block, reusing cached blocks when it is re-fetched',
   english: 'English',
   enterCategoryName: 'Enter new category name:',
   errorAddingFeed: 'Error adding feed',
@@ -380,9 +462,14 @@ const en: TranslationMessages = {
   syncFeedStarted: 'Feed sync started',
   feeds: 'Feeds',
   feedDiscovery: 'Feed Discovery',
+  feedPromptInherit: 'Category or default template',
+  feedPromptTemplatesDesc:
+    'Prompt templates for this feed, instead of the ones of its category or the defaults',
   feedsDeletedSuccess: 'Feeds deleted successfully',
   feedsMovedSuccess: 'Feeds moved successfully',
   feedSource: 'Feed Source',
+  feedSummaryPrompt: 'Summary Prompt',
+  feedTranslationPrompt: 'Translation Prompt',
   feedsSubscribedPartial: '{successful} feeds subscribed, {failed} failed',
   feedsSubscribedSuccess: '{count} feeds subscribed successfully',
   feedsWord: 'feeds',
@@ -472,6 +559,18 @@ const en: TranslationMessages = {
   generatingSummaryTime: '{seconds} seconds elapsed',
   regenerateSummary: 'Regenerate',
   german: 'deutsch',
+  glossary: 'Glossary',
+  glossaryAdd: 'Add Term',
+  glossaryAllLanguages: 'All languages',
+  glossaryDeleteConfirm: 'Delete the glossary term "{term}"? Cached translations containing it are translated again.',
+  glossaryDesc: 'Terms every translation provider translates the same way, or keeps untranslated. Cached translations containing a term are refreshed when it changes.',
+  glossaryEmpty: 'No glossary terms yet.',
+  glossaryKeepAsIs: 'kept as-is',
+  glossaryProtected: 'Do not translate',
+  glossarySaveError: 'Failed to save glossary term',
+  glossarySaved: 'Glossary term saved',
+  glossaryTerm: 'Term',
+  glossaryTranslation: 'Translation',
   googleTranslate: 'Google Translate',
   googleTranslateEndpoint: 'Google Translate Endpoint',
   googleTranslateEndpointAlternate: 'Alternate (clients5.google.com)',
@@ -529,6 +628,13 @@ const en: TranslationMessages = {
   loading: 'Loading',
   latencyMs: 'ms',
   latestVersion: 'Latest version',
+  libreTranslate: 'LibreTranslate',
+  libreTranslateApiKey: 'API Key',
+  libreTranslateApiKeyDesc: 'Only needed if your LibreTranslate server requires keys',
+  libreTranslateApiKeyPlaceholder: 'Optional',
+  libreTranslateEndpoint: 'Server URL',
+  libreTranslateEndpointDesc: 'LibreTranslate server URL. Local servers are reached without the proxy.',
+  libreTranslateEndpointPlaceholder: 'http://localhost:5000',
   light: 'Light',
   loadingContent: 'Loading content',
   loadingFeeds: 'Loading feeds',
@@ -558,6 +664,7 @@ const en: TranslationMessages = {
   mediaCacheMaxSizeDesc: 'Maximum media cache size',
   articleContentCacheCleanup: 'Clean Article Content Cache',
   articleContentCacheCleanupDesc: 'Clear all cached article content',
+  articleKeyword: 'Keyword',
   cleanupArticleContentCache: 'Clean Now',
   currentCachedArticles: 'Current cached articles',
   minutesAgo: '{count} min ago',
@@ -579,6 +686,7 @@ const en: TranslationMessages = {
   no: 'No',
   noActionsSelected: 'Please select at least one action',
   noArticles: 'No articles found.',
+  noArticleTags: 'No articles classified yet',
   noContent: 'No content available for this article',
   noContentAvailable: 'No content available',
   noFeedsDiscovered: 'No feeds discovered',
@@ -630,6 +738,25 @@ const en: TranslationMessages = {
   previousArticle: 'Previous Article',
   processingFeed: 'Processing feed {current} of {total}',
   progress: 'Progress: ',
+  promptKind_chat_action: 'Chat quick action',
+  promptKind_summary: 'Summary',
+  promptKind_translation: 'Translation',
+  promptTemplateAdd: 'Add Template',
+  promptTemplateCategories: 'Categories',
+  promptTemplateCategoriesPlaceholder: 'Feed categories, comma separated (e.g. Tech, News/World)',
+  promptTemplateDefault: 'Default',
+  promptTemplateDeleteConfirm: 'Delete the prompt template "{name}"?',
+  promptTemplateName: 'Template name',
+  promptTemplatePlaceholder: 'Prompt text, with variables in double braces',
+  promptTemplatePreview: 'Preview',
+  promptTemplatePreviewArticle: 'Rendered for "{title}"',
+  promptTemplates: 'Prompt Templates',
+  promptTemplateSaved: 'Prompt template saved',
+  promptTemplateSaveError: 'Failed to save prompt template',
+  promptTemplatesDesc:
+    'Reusable prompts for summaries, translations and chat quick actions. A feed uses its own template, then the template of its most specific category, then the default one.',
+  promptTemplatesEmpty: 'No templates yet',
+  promptTemplateVariables: 'Available variables',
   proxyCredentialsRequired: 'Proxy requires valid host and port',
   proxyHost: 'Proxy Host',
   proxyHostDesc: 'Proxy server hostname or IP address',
@@ -654,6 +781,9 @@ const en: TranslationMessages = {
   readLater: 'Read Later',
   readingAndDisplay: 'Reading & Display',
   readLaterStatus: 'Read Later Status',
+  relevanceAbove: 'Relevance At Least',
+  relevanceBelow: 'Relevance Below',
+  relevanceScorePlaceholder: 'Score from 0 to 100',
   readStatus: 'Read Status',
   recentArticles: 'Recent Articles',
   reDetectNetwork: 'Re-detect',
@@ -685,6 +815,9 @@ const en: TranslationMessages = {
   viewAsRendered: 'View as Rendered Content',
   autoExpandContent: 'Auto Expand Content',
   autoExpandContentDesc: 'Override global full-text fetch and auto-expand settings for this feed',
+  fullTextOnIngest: 'Fetch Full Text on Refresh',
+  fullTextOnIngestDesc:
+    'Extract the full text of new articles in the background so it is ready to read offline',
   enabled: 'Enabled',
   disabled: 'Disabled',
   required: 'Required',
@@ -723,6 +856,10 @@ const en: TranslationMessages = {
   searchFeeds: 'Search feeds...',
   feedsWithFilter: '{filter} - Feeds',
   searchingFriendLinks: 'Searching for friend links',
+  semanticIndexedArticles: '{count} articles indexed',
+  semanticSearch: 'Semantic Search',
+  semanticSearchDesc:
+    'Embed articles in the background to find related coverage by meaning, even when the wording differs',
   selectActions: 'Select Actions',
   selectAll: 'Select All',
   selectArticle: 'Select an article to start reading',
@@ -736,6 +873,8 @@ const en: TranslationMessages = {
   setUsageLimit: 'Set Usage Limit',
   setUsageLimitDesc: 'Maximum number of tokens allowed (set to 0 for unlimited)',
   showOnlyUnread: 'Show only unread articles',
+  sortByRelevance: 'Sorted by relevance, learned from your reading (click to sort by date)',
+  sortByPublished: 'Sorted by date (click to sort by relevance)',
   shortcutArticles: 'Articles',
   shortcutCleared: 'Shortcut cleared',
   shortcutConflict: 'This shortcut is already in use',
@@ -797,6 +936,12 @@ const en: TranslationMessages = {
   summaryProcessingTip: 'AI summary processing may take some time, please be patient',
   summaryProvider: 'Summary Provider',
   summaryProviderDesc: 'Choose how to generate article summaries',
+  summaryStyle: 'Summary style',
+  summaryStyleBullets: 'Key points',
+  summaryStyleEli5: 'Explain simply',
+  summaryStyleQuotes: 'Key quotes',
+  summaryStyleStandard: 'Standard',
+  summaryStyleTldr: 'TL;DR',
   summaryTooShort: 'Article is too short to generate a meaningful summary',
   summaryTriggerMode: 'Trigger Mode',
   summaryTriggerModeAuto: 'Auto Trigger',
@@ -835,9 +980,36 @@ const en: TranslationMessages = {
   translating: 'Translating...',
   translatingContent: 'Translating content...',
   translation: 'Translation',
+  translationCache: 'Translation Cache',
+  translationCacheDeleteEntryConfirm: 'Delete this cached translation?',
+  translationCacheDeleteGroupConfirm:
+    'Delete the {count} cached {provider} translations to {lang}, corrections included?',
+  translationCacheDesc:
+    'Cached translations per provider and language. Corrected translations are kept when texts are translated again, and the cache can be shared as TMX.',
+  translationCacheEdited: 'Corrected',
+  translationCacheEmpty: 'No cached translations yet.',
+  translationCacheExport: 'Export TMX',
+  translationCacheExportError: 'Failed to export the translation cache',
+  translationCacheImport: 'Import TMX',
+  translationCacheImportError: 'Failed to import the TMX file',
+  translationCacheImported: 'Imported {count} translations',
+  translationCacheSaveError: 'Failed to save the correction',
+  translationCacheSaved: 'Correction saved',
+  translationCacheSearch: 'Search cached translations...',
+  translationCacheStats: '{entries} entries · {hitRate}% hits · {size}',
   translationCredentialsRequired: 'Translation service requires API key or credentials',
   translationProvider: 'Translation Provider',
   translationProviderDesc: 'Choose the translation service to use',
+  trendingAllFeeds: 'All feeds',
+  trendingExtract: 'Extract keywords',
+  trendingExtracted: 'Extracted keywords of {count} articles',
+  trendingExtractFailed: 'Failed to extract keywords',
+  trendingExtractHint: 'Extract the keywords of articles saved before keyword extraction',
+  trendingExtracting: 'Extracting...',
+  trendingLastDays: 'Last {days} days',
+  trendingNoTerms: 'No keywords in this period',
+  trendingPrevious: '{count} in the previous period',
+  trendingTerms: 'Trending terms',
   customTranslation: 'Custom API',
   customTranslationInfo: 'Configure a custom HTTP translation API endpoint',
   customTranslationTemplate: 'Preset Templates',
@@ -29,9 +29,30 @@ const zh: TranslationMessages = {
   aiApiKeyDesc: 'AI 服务的 API 密钥（可选）',
   aiApiKeyMissing: 'API 密钥缺失或无效。请在设置中配置。',
   aiApiKeyPlaceholder: '输入您的 API 密钥',
+  aiBudgetAdd: '添加预算',
+  aiBudgetAllFeatures: '所有功能',
+  aiBudgetMaxCost: '最高费用（美元）',
+  aiBudgetMaxTokens: '最大 Token 数',
+  aiBudgetPeriod_daily: '每日',
+  aiBudgetPeriod_monthly: '每月',
+  aiBudgets: '预算',
+  aiBudgetsDesc: '按日或按月限制 Token 数或预估费用。超出预算的 AI 功能将回退或暂停，直到下一个周期。',
   aiChat: 'AI 聊天',
   aiChatEnabled: 'AI 聊天',
   aiChatEnabledDesc: '和 AI 聊天，回答有关文章的问题',
+  aiChatToolsEnabled: '允许聊天操作文章',
+  aiChatToolsEnabledDesc: '允许聊天搜索文章、获取文章内容、标记已读或收藏、加入稍后阅读以及创建规则',
+  chatHistory: '对话历史',
+  chatHistoryDesc: '所有文章的对话，按最近更新排序。置顶的对话在文章被清理后仍会保留。',
+  chatHistoryExportedToObsidian: '对话已导出到 Obsidian',
+  chatHistoryExportMarkdown: '导出为 Markdown',
+  chatHistoryIncludeArticle: '导出文章信息',
+  chatHistoryIncludeThinking: '导出思考过程',
+  chatHistoryMessages: '{count} 条消息',
+  chatHistoryNoMatches: '未找到消息',
+  chatHistoryPin: '置顶',
+  chatHistorySearch: '搜索所有对话消息...',
+  chatHistoryUnpin: '取消置顶',
   clearAllChats: '清空对话记录',
   clearAllChatsDesc: '删除所有 AI 对话记录',
   clearAllChatsButton: '清空',
@@ -63,6 +84,17 @@ const zh: TranslationMessages = {
   aiEndpoint: 'API 端点',
   aiEndpointDesc: '完整的 API 端点 URL，包括路径',
   aiEndpointPlaceholder: 'https://api.openai.com/v1/chat/completions',
+  aiEmbeddingEndpoint: '嵌入端点',
+  aiEmbeddingEndpointDesc: '用于生成向量嵌入的端点，留空则使用上面的 API 端点',
+  aiEmbeddingEndpointPlaceholder: '与 API 端点相同',
+  aiEmbeddingModel: '嵌入模型',
+  aiEmbeddingModelDesc: '用于嵌入文章的模型，例如 text-embedding-3-small 或 nomic-embed-text',
+  aiClassificationEnabled: '文章分类',
+  aiClassificationEnabledDesc:
+    '将新文章归入你的主题，提取其中提到的人物和机构，并生成一句话说明其重要性',
+  aiClassificationTopics: '主题',
+  aiClassificationTopicsDesc: '每行一个主题或以逗号分隔，可用于规则和筛选',
+  aiClassificationTopicsPlaceholder: '航天\n选举\n开源',
   aiFeatures: 'AI 功能',
   aiIsDanger:
     '使用 AI 服务可能会产生费用，部分功能可能消耗 Token 较多，请确保您了解相关费用结构并实时监控使用情况。',
@@ -78,6 +110,24 @@ const zh: TranslationMessages = {
   aiCustomHeadersValue: '请求头内容',
   aiCustomHeadersRemove: '删除',
   aiCustomHeadersInvalid: '自定义请求头的 JSON 格式无效',
+  aiProfileAdd: '添加配置',
+  aiProfileDeleteConfirm: '删除 AI 配置“{name}”？使用它的功能将改用其余配置。',
+  aiProfileHeadersPlaceholder: '自定义请求头，JSON 对象格式（可选）',
+  aiProfileName: '配置名称',
+  aiProfileSaveError: '保存 AI 配置失败',
+  aiProfileSaved: 'AI 配置已保存',
+  aiProfiles: '服务商配置',
+  aiProfilesDesc: '可供各功能使用的命名 AI 服务，每个配置有独立的模型、密钥和用量限制',
+  aiProfilesEmpty: '暂无配置，所有 AI 功能使用上方设置。',
+  aiRouteAddProfile: '添加配置…',
+  aiRouteDefault: '使用上方设置',
+  aiRouteTask_chat: '对话',
+  aiRouteTask_classification: '分类',
+  aiRouteTask_embedding: '向量嵌入',
+  aiRouteTask_summary: '摘要',
+  aiRouteTask_translation: '翻译',
+  aiRouting: '路由',
+  aiRoutingDesc: '按顺序尝试各配置，当某个配置失败或达到用量限制时使用下一个',
   aiSettings: 'AI 设置',
   aiSettingsConfiguredInAITab:
     'AI API 密钥、端点和模型在 AI 标签页中配置。该功能消耗 Token 较多，请谨慎使用。',
@@ -94,11 +144,16 @@ const zh: TranslationMessages = {
   aiSystemPromptPlaceholder:
     '默认：你是一个翻译器。准确翻译给定的文本。只输出翻译的文本，不要输出其他内容。',
   aiTranslation: 'AI 翻译',
+  aiTranslationGoogleFallback: '回退到谷歌翻译',
+  aiTranslationGoogleFallbackDesc: '所有 AI 配置均失败或达到用量上限时使用谷歌翻译',
   aiTranslationPrompt: '翻译提示词',
   aiTranslationPromptDesc: 'AI 翻译的自定义系统提示词',
   aiTranslationPromptPlaceholder:
     '你是一个翻译器。准确翻译给定的文本。只输出翻译的文本，不要输出其他内容。',
   aiUsage: 'AI 使用量',
+  aiUsageByFeature: '按功能',
+  aiUsageByModel: '按模型',
+  aiUsageByProvider: '按服务商',
   aiUsageFallback: '由于 AI 限制，正在使用免费替代方案',
   aiUsageLimit: '使用上限',
   aiUsageLimitDesc: '允许的最大 Token 数（0 = 无限制）。达到上限后将回退到免费替代方案。',
@@ -141,6 +196,10 @@ const zh: TranslationMessages = {
   articles: '文章',
   articleSummary: '文章摘要',
   articleTitle: '文章标题',
+  articleTopic: '主题',
+  articleEntity: '实体',
+  articleLanguage: '语言',
+  whyItMatters: '为何重要',
   audioPlaybackError: '无法播放音频。文件可能不可用或格式不受支持。',
   auto: '自动（跟随系统）',
   autoCleanup: '自动清理',
@@ -152,6 +211,19 @@ const zh: TranslationMessages = {
   backToRss: '返回 RSS',
   backToSimple: '返回简单模式',
   backToUrl: '返回 URL 模式',
+  backgroundCategoriesPlaceholder: '例如：科技, 科学',
+  backgroundJobs: '后台任务',
+  backgroundJobsConcurrency: '后台任务并发数',
+  backgroundJobsConcurrencyDesc: '同时执行的后台翻译和摘要任务数',
+  backgroundJobsStatus: '{pending} 个等待，{running} 个执行中，{failed} 个失败',
+  backgroundSummarize: '后台生成摘要',
+  backgroundSummarizeCategories: '后台摘要分类',
+  backgroundSummarizeCategoriesDesc: '获取到这些分类（用逗号分隔）的新文章后立即生成摘要',
+  backgroundSummarizeDesc: '获取到新文章后立即生成摘要',
+  backgroundTranslate: '后台翻译标题',
+  backgroundTranslateCategories: '后台翻译分类',
+  backgroundTranslateCategoriesDesc: '获取到这些分类（用逗号分隔）的新文章后立即翻译标题',
+  backgroundTranslateDesc: '获取到新文章后立即翻译标题',
   baiduAppId: '百度 App ID',
   baiduAppIdDesc: '百度翻译 App ID',
   baiduAppIdPlaceholder: '输入您的 App ID',
@@ -304,6 +376,8 @@ const zh: TranslationMessages = {
   enableTranslationDesc: '自动将文章标题翻译为首选语言',
   translationOnlyMode: '仅译文模式',
   translationOnlyModeDesc: '仅显示翻译后的文本，隐藏原始内容',
+  fullArticleTranslation: '全文翻译',
+  fullArticleTranslationDesc: '在服务器端逐段翻译整篇文章，文章重新获取时复用已缓存的段落',
   english: 'English',
   enterCategoryName: '输入新的分类名称：',
   errorAddingFeed: '添加订阅时出错',
@@ -373,9 +447,13 @@ const zh: TranslationMessages = {
   syncFeedStarted: '订阅源同步已开始',
   feeds: '订阅源',
   feedDiscovery: '订阅发现',
+  feedPromptInherit: '分类或默认模板',
+  feedPromptTemplatesDesc: '此订阅源使用的提示词模板，代替分类或默认模板',
   feedsDeletedSuccess: '订阅删除成功',
   feedsMovedSuccess: '订阅移动成功',
   feedSource: '订阅源类型',
+  feedSummaryPrompt: '摘要提示词',
+  feedTranslationPrompt: '翻译提示词',
   feedsSubscribedPartial: '成功订阅 {successful} 个，失败 {failed} 个',
   feedsSubscribedSuccess: '成功订阅 {count} 个订阅源',
   feedsWord: '个订阅源',
@@ -466,6 +544,18 @@ const zh: TranslationMessages = {
   generatingSummaryTime: '已耗时 {seconds} 秒',
   regenerateSummary: '重新生成',
   german: 'deutsch',
+  glossary: '术语表',
+  glossaryAdd: '添加术语',
+  glossaryAllLanguages: '所有语言',
+  glossaryDeleteConfirm: '删除术语“{term}”？包含它的缓存翻译将重新翻译。',
+  glossaryDesc: '所有翻译服务都按统一译法翻译或保持原文的术语。术语变更时，包含它的缓存翻译会被刷新。',
+  glossaryEmpty: '暂无术语。',
+  glossaryKeepAsIs: '保持原文',
+  glossaryProtected: '不翻译',
+  glossarySaveError: '保存术语失败',
+  glossarySaved: '术语已保存',
+  glossaryTerm: '术语',
+  glossaryTranslation: '译文',
   googleTranslate: '谷歌翻译',
   googleTranslateEndpoint: '谷歌翻译端点',
   googleTranslateEndpointAlternate: '备用 (clients5.google.com)',
@@ -521,6 +611,13 @@ const zh: TranslationMessages = {
   loading: '加载中',
   latencyMs: '毫秒',
   latestVersion: '最新版本',
+  libreTranslate: 'LibreTranslate',
+  libreTranslateApiKey: 'API 密钥',
+  libreTranslateApiKeyDesc: '仅当 LibreTranslate 服务器要求密钥时需要',
+  libreTranslateApiKeyPlaceholder: '可选',
+  libreTranslateEndpoint: '服务器地址',
+  libreTranslateEndpointDesc: 'LibreTranslate 服务器地址，本地服务器不经过代理直接访问',
+  libreTranslateEndpointPlaceholder: 'http://localhost:5000',
   light: '亮色',
   loadingContent: '加载内容中',
   loadingFeeds: '正在加载订阅源',
@@ -549,6 +646,7 @@ const zh: TranslationMessages = {
   mediaCacheMaxSizeDesc: '媒体缓存最大大小',
   articleContentCacheCleanup: '清理文章内容缓存',
   articleContentCacheCleanupDesc: '清空所有缓存的文章正文内容',
+  articleKeyword: '关键词',
   cleanupArticleContentCache: '立即清理',
   currentCachedArticles: '当前缓存文章数',
   minutesAgo: '{count}分钟前',
@@ -569,6 +667,7 @@ const zh: TranslationMessages = {
   no: '否',
   noActionsSelected: '请至少选择一个操作',
   noArticles: '未找到文章。',
+  noArticleTags: '尚无已分类的文章',
   noContent: '此文章没有内容',
   noContentAvailable: '无可用内容',
   noFiltersApplied: '未应用过滤条件',
@@ -620,6 +719,25 @@ const zh: TranslationMessages = {
   previousArticle: '上一篇文章',
   processingFeed: '正在处理第 {current}/{total} 个订阅源',
   progress: '进度：',
+  promptKind_chat_action: '对话快捷操作',
+  promptKind_summary: '摘要',
+  promptKind_translation: '翻译',
+  promptTemplateAdd: '添加模板',
+  promptTemplateCategories: '分类',
+  promptTemplateCategoriesPlaceholder: '订阅源分类，以逗号分隔（如 Tech, News/World）',
+  promptTemplateDefault: '默认',
+  promptTemplateDeleteConfirm: '删除提示词模板「{name}」？',
+  promptTemplateName: '模板名称',
+  promptTemplatePlaceholder: '提示词内容，变量写在双花括号中',
+  promptTemplatePreview: '预览',
+  promptTemplatePreviewArticle: '以「{title}」渲染',
+  promptTemplates: '提示词模板',
+  promptTemplateSaved: '提示词模板已保存',
+  promptTemplateSaveError: '保存提示词模板失败',
+  promptTemplatesDesc:
+    '可复用的摘要、翻译和对话快捷操作提示词。订阅源优先使用自身模板，其次是最具体分类的模板，最后是默认模板。',
+  promptTemplatesEmpty: '暂无模板',
+  promptTemplateVariables: '可用变量',
   proxyCredentialsRequired: '代理需要有效的主机和端口',
   proxyHost: '代理主机',
   proxyHostDesc: '代理服务器主机名或 IP 地址',
@@ -644,6 +762,9 @@ const zh: TranslationMessages = {
   readLater: '稍后阅读',
   readingAndDisplay: '阅读与显示',
   readLaterStatus: '稍后阅读状态',
+  relevanceAbove: '相关度不低于',
+  relevanceBelow: '相关度低于',
+  relevanceScorePlaceholder: '0 到 100 的分数',
   readStatus: '已读状态',
   recentArticles: '最近文章',
   reDetectNetwork: '重新检测',
@@ -680,6 +801,8 @@ const zh: TranslationMessages = {
   viewAsRendered: '作为渲染内容查看',
   autoExpandContent: '自动展开内容',
   autoExpandContentDesc: '覆盖此订阅源的全局全文提取和自动展开设置',
+  fullTextOnIngest: '刷新时获取全文',
+  fullTextOnIngestDesc: '在后台提取新文章的全文，便于离线阅读',
   enabled: '启用',
   disabled: '禁用',
   required: '必填',
@@ -716,6 +839,9 @@ const zh: TranslationMessages = {
   searchFeeds: '搜索订阅源...',
   feedsWithFilter: '{filter} - 订阅源',
   searchingFriendLinks: '正在搜索友链',
+  semanticIndexedArticles: '已索引 {count} 篇文章',
+  semanticSearch: '语义搜索',
+  semanticSearchDesc: '在后台为文章生成向量嵌入，即使措辞不同也能按含义找到相关报道',
   selectActions: '选择操作',
   selectAll: '全选',
   selectArticle: '选择一篇文章开始阅读',
@@ -729,6 +855,8 @@ const zh: TranslationMessages = {
   setUsageLimit: '设置使用上限',
   setUsageLimitDesc: 'Token 使用数量上限（设置为 0 时则无限制）',
   showOnlyUnread: '仅显示未读文章',
+  sortByRelevance: '按相关度排序，根据你的阅读习惯学习（点击按时间排序）',
+  sortByPublished: '按时间排序（点击按相关度排序）',
   shortcutArticles: '文章',
   shortcutCleared: '快捷键已清除',
   shortcutConflict: '此快捷键已被使用',
@@ -790,6 +918,12 @@ const zh: TranslationMessages = {
   summaryProcessingTip: 'AI摘要处理可能需要一些时间，请耐心等待',
   summaryProvider: '摘要提供商',
   summaryProviderDesc: '选择如何生成文章摘要',
+  summaryStyle: '摘要风格',
+  summaryStyleBullets: '要点',
+  summaryStyleEli5: '通俗解释',
+  summaryStyleQuotes: '关键引述',
+  summaryStyleStandard: '标准',
+  summaryStyleTldr: '一句话概括',
   summaryTooShort: '文章太短，无法生成有效摘要',
   summaryTriggerMode: '触发方式',
   summaryTriggerModeAuto: '自动触发',
@@ -829,9 +963,36 @@ const zh: TranslationMessages = {
   translating: '翻译中...',
   translatingContent: '正在翻译内容...',
   translation: '翻译',
+  translationCache: '翻译缓存',
+  translationCacheDeleteEntryConfirm: '删除这条缓存的翻译？',
+  translationCacheDeleteGroupConfirm:
+    '删除 {provider} 翻译为 {lang} 的 {count} 条缓存（包括已修正的翻译）？',
+  translationCacheDesc:
+    '按提供商和语言缓存的翻译。修正后的翻译在重新翻译时会被保留，缓存可以导出为 TMX 与他人共享。',
+  translationCacheEdited: '已修正',
+  translationCacheEmpty: '暂无缓存的翻译。',
+  translationCacheExport: '导出 TMX',
+  translationCacheExportError: '导出翻译缓存失败',
+  translationCacheImport: '导入 TMX',
+  translationCacheImportError: '导入 TMX 文件失败',
+  translationCacheImported: '已导入 {count} 条翻译',
+  translationCacheSaveError: '保存修正失败',
+  translationCacheSaved: '修正已保存',
+  translationCacheSearch: '搜索缓存的翻译...',
+  translationCacheStats: '{entries} 条 · 命中率 {hitRate}% · {size}',
   translationCredentialsRequired: '翻译服务需要提供 API 密钥或凭据',
   translationProvider: '翻译提供商',
   translationProviderDesc: '选择要使用的翻译服务',
+  trendingAllFeeds: '全部订阅源',
+  trendingExtract: '提取关键词',
+  trendingExtracted: '已提取 {count} 篇文章的关键词',
+  trendingExtractFailed: '提取关键词失败',
+  trendingExtractHint: '为启用关键词提取之前保存的文章提取关键词',
+  trendingExtracting: '正在提取...',
+  trendingLastDays: '最近 {days} 天',
+  trendingNoTerms: '此期间没有关键词',
+  trendingPrevious: '上一期间 {count} 篇',
+  trendingTerms: '热门词',
   customTranslation: '自定义 API',
   customTranslationInfo: '配置自定义 HTTP 翻译 API 端点',
   customTranslationTemplate: '预设模板',
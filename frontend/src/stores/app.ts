@@ -1,5 +1,5 @@
 import { defineStore } from 'pinia';
-import { ref, computed, type Ref } from 'vue';
+import { ref, computed, watch, type Ref } from 'vue';
 import type { Article, Feed, UnreadCounts, RefreshProgress } from '@/types/models';
 import { useSettings } from '@/composables/core/useSettings';
 
@@ -30,6 +30,7 @@ export interface AppState {
   theme: Ref<Theme>;
   refreshProgress: Ref<RefreshProgress>;
   showOnlyUnread: Ref<boolean>;
+  sortByRelevance: Ref<boolean>;
 }
 
 export interface AppActions {
@@ -51,6 +52,7 @@ export interface AppActions {
   checkForAppUpdates: () => Promise<void>;
   startAutoRefresh: (minutes: number) => void;
   toggleShowOnlyUnread: () => void;
+  toggleSortByRelevance: () => void;
 }
 
 export const useAppStore = defineStore('app', () => {
@@ -84,6 +86,7 @@ export const useAppStore = defineStore('app', () => {
   );
   const theme = ref<Theme>('light');
   const showOnlyUnread = ref<boolean>(localStorage.getItem('showOnlyUnread') === 'true');
+  const sortByRelevance = ref<boolean>(localStorage.getItem('sortByRelevance') === 'true');
 
   // Article view mode preferences (persisted across component mounts)
   const articleViewModePreferences = ref<Map<number, 'original' | 'rendered'>>(new Map());
@@ -148,6 +151,7 @@ export const useAppStore = defineStore('app', () => {
     if (currentFilter.value) url += `&filter=${currentFilter.value}`;
     if (currentFeedId.value) url += `&feed_id=${currentFeedId.value}`;
     if (currentCategory.value) url += `&category=${encodeURIComponent(currentCategory.value)}`;
+    if (sortByRelevance.value) url += '&sort=relevance';
 
     try {
       const res = await fetch(url);
@@ -642,6 +646,47 @@ export const useAppStore = defineStore('app', () => {
     localStorage.setItem('showOnlyUnread', String(showOnlyUnread.value));
   }
 
+  function toggleSortByRelevance(): void {
+    sortByRelevance.value = !sortByRelevance.value;
+    localStorage.setItem('sortByRelevance', String(sortByRelevance.value));
+    fetchArticles();
+  }
+
+  // Time spent reading the open article, reported as a relevance signal when it is
+  // closed, another article is opened or the window is hidden
+  let dwellArticleId: number | null = null;
+  let dwellStart = 0;
+
+  function reportDwell(): void {
+    if (dwellArticleId === null) return;
+    const seconds = Math.round((Date.now() - dwellStart) / 1000);
+    if (seconds > 0) {
+      fetch(`/api/articles/dwell?id=${dwellArticleId}&seconds=${seconds}`, {
+        method: 'POST',
+      }).catch(() => {
+        // Dwell time is best effort
+      });
+    }
+    dwellArticleId = null;
+  }
+
+  function startDwell(articleId: number | null): void {
+    reportDwell();
+    if (articleId) {
+      dwellArticleId = articleId;
+      dwellStart = Date.now();
+    }
+  }
+
+  watch(currentArticleId, (id) => startDwell(id));
+  document.addEventListener('visibilitychange', () => {
+    if (document.visibilityState === 'hidden') {
+      reportDwell();
+    } else {
+      startDwell(currentArticleId.value);
+    }
+  });
+
   async function fetchTaskDetails(): Promise<void> {
     try {
       const res = await fetch('/api/progress/task-details');
@@ -651,6 +696,7 @@ export const useAppStore = defineStore('app', () => {
           ...refreshProgress.value,
           pool_tasks: data.pool_tasks,
           queue_tasks: data.queue_tasks,
+          background_jobs: data.background_jobs,
         };
       }
     } catch (e) {
@@ -678,6 +724,7 @@ export const useAppStore = defineStore('app', () => {
     theme,
     refreshProgress,
     showOnlyUnread,
+    sortByRelevance,
     articleViewModePreferences,
 
     // Actions
@@ -702,6 +749,7 @@ export const useAppStore = defineStore('app', () => {
     checkForAppUpdates,
     startAutoRefresh,
     toggleShowOnlyUnread,
+    toggleSortByRelevance,
     fetchTaskDetails,
   };
 });
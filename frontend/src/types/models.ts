@@ -19,6 +19,12 @@ export interface Article {
   is_read_later: boolean;
   summary?: string; // Cached AI-generated summary
   freshrss_item_id?: string; // FreshRSS/Google Reader item ID
+  relevance_score?: number; // Learned relevance from 0 to 100, absent until scored
+  topics?: string[]; // User topics assigned by AI classification
+  entities?: string[]; // Named entities extracted by AI classification
+  why_it_matters?: string; // Short AI-written line on why the article matters
+  language?: string; // Language detected when the article was saved (ISO 639-1)
+  language_confidence?: number; // Confidence of the language detection from 0 to 1
 }
 
 export interface Feed {
@@ -52,6 +58,12 @@ export interface Feed {
   xpath_item_uid?: string;
   article_view_mode?: string; // Article view mode override ('global', 'webpage', 'rendered')
   auto_expand_content?: string; // Auto expand content mode ('global', 'enabled', 'disabled')
+  full_text_on_ingest?: boolean; // Fetch full text of new articles when the feed is refreshed
+  background_translate?: boolean; // Translate titles of new articles in the background
+  background_summarize?: boolean; // Summarize new articles in the background
+  summary_prompt_id?: number; // Summary prompt template overriding the category and default ones
+  translation_prompt_id?: number; // Translation prompt template overriding the category and default ones
+  language?: string; // Dominant detected language of the feed's recent articles (ISO 639-1)
   // Email/Newsletter support
   email_address?: string;
   email_imap_server?: string;
@@ -81,6 +93,13 @@ export interface RefreshProgress {
   queue_task_count?: number; // Tasks in queue
   pool_tasks?: PoolTaskInfo[]; // Detailed pool task information
   queue_tasks?: QueueTaskInfo[]; // Detailed queue task information (max 3)
+  background_jobs?: BackgroundJobProgress; // Background translation and summary jobs
+}
+
+export interface BackgroundJobProgress {
+  pending: number;
+  running: number;
+  failed: number;
 }
 
 export interface PoolTaskInfo {
@@ -7,15 +7,25 @@
 export interface SettingsData {
   ai_api_key: string;
   ai_chat_enabled: boolean;
+  ai_chat_tools_enabled: boolean;
+  ai_classification_enabled: boolean;
+  ai_classification_topics: string;
   ai_custom_headers: string;
+  ai_embedding_enabled: boolean;
+  ai_embedding_endpoint: string;
+  ai_embedding_model: string;
   ai_endpoint: string;
   ai_model: string;
   ai_summary_prompt: string;
+  ai_translation_google_fallback: boolean;
   ai_translation_prompt: string;
   ai_usage_limit: string;
   ai_usage_tokens: string;
   auto_cleanup_enabled: boolean;
   auto_show_all_content: boolean;
+  background_jobs_concurrency: number;
+  background_summarize_categories: string;
+  background_translate_categories: string;
   baidu_app_id: string;
   baidu_secret_key: string;
   close_to_tray: boolean;
@@ -42,6 +52,7 @@ export interface SettingsData {
   freshrss_server_url: string;
   freshrss_sync_on_startup: boolean;
   freshrss_username: string;
+  full_article_translation: boolean;
   full_text_fetch_enabled: boolean;
   google_translate_endpoint: string;
   hover_mark_as_read: boolean;
@@ -49,6 +60,8 @@ export interface SettingsData {
   language: string;
   last_global_refresh: string;
   last_network_test: string;
+  libretranslate_api_key: string;
+  libretranslate_endpoint: string;
   max_article_age_days: number;
   max_cache_size_mb: number;
   max_concurrent_refreshes: string;
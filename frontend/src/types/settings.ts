@@ -27,6 +27,75 @@ export interface AITestInfo {
   error_message?: string;
 }
 
+export interface AIProfile {
+  id: number;
+  name: string;
+  endpoint: string;
+  model: string;
+  api_key: string;
+  custom_headers: string;
+  usage_limit: number;
+  usage_tokens: number;
+  enabled: boolean;
+}
+
+export interface AIRoutes {
+  tasks: string[];
+  routes: Record<string, number[]>;
+}
+
+export type PromptKind = 'summary' | 'translation' | 'chat_action';
+
+export interface PromptTemplate {
+  id: number;
+  name: string;
+  kind: PromptKind;
+  template: string;
+  categories: string;
+  is_default: boolean;
+}
+
+export interface PromptPreview {
+  rendered: string;
+  problems: string[] | null;
+  variables: string[] | null;
+  article_id: number;
+  article_title: string;
+}
+
+export interface ChatSessionSummary {
+  id: number;
+  article_id: number;
+  title: string;
+  created_at: string;
+  updated_at: string;
+  message_count: number;
+  pinned: boolean;
+  article_title?: string;
+  article_url?: string;
+}
+
+export interface ChatMessageMatch {
+  message_id: number;
+  session_id: number;
+  session_title: string;
+  article_id: number;
+  article_title?: string;
+  role: 'user' | 'assistant';
+  content: string;
+  created_at: string;
+}
+
+export interface AIBudgetStatus {
+  feature: string;
+  period: 'daily' | 'monthly';
+  max_tokens: number;
+  max_cost: number;
+  used_tokens: number;
+  used_cost: number;
+  exceeded: boolean;
+}
+
 export interface UpdateInfo {
   has_update: boolean;
   current_version: string;
@@ -57,3 +126,33 @@ export type TabName =
   | 'shortcuts'
   | 'statistics'
   | 'about';
+
+export interface GlossaryEntry {
+  id: number;
+  target_lang: string;
+  term: string;
+  translation: string;
+  protected: boolean;
+}
+
+export interface TranslationCacheStats {
+  provider: string;
+  target_lang: string;
+  entries: number;
+  edited: number;
+  hits: number;
+  misses: number;
+  hit_rate: number;
+  bytes: number;
+}
+
+export interface TranslationCacheEntry {
+  id: number;
+  source_text: string;
+  target_lang: string;
+  translated_text: string;
+  provider: string;
+  hits: number;
+  edited: boolean;
+  created_at: string;
+}
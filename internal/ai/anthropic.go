@@ -108,6 +108,8 @@ func (h *AnthropicHandler) BuildRequest(config RequestConfig) (map[string]interf
 		request["metadata"] = metadata
 	}
 
+	addAnthropicTools(request, config)
+
 	return request, nil
 }
 
@@ -118,15 +120,18 @@ func (h *AnthropicHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		Type    string `json:"type"`
 		Role    string `json:"role"`
 		Content []struct {
-			Type string `json:"type"`
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			ID    string          `json:"id"`    // tool_use
+			Name  string          `json:"name"`  // tool_use
+			Input json.RawMessage `json:"input"` // tool_use
 		} `json:"content"`
 		Model        string `json:"model"`
 		StopReason   string `json:"stop_reason"`
 		StopSequence string `json:"stop_sequence"`
 		Usage        struct {
-			InputTokens  int `json:"input_tokens"`
-			OutputTokens int `json:"output_tokens"`
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
 		} `json:"usage"`
 		Error struct {
 			Type    string `json:"type"`
@@ -146,6 +151,7 @@ func (h *AnthropicHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	// Extract content
 	var contentBuilder strings.Builder
 	var thinkingContent string
+	var toolCalls []ToolCall
 
 	for _, content := range response.Content {
 		switch content.Type {
@@ -154,6 +160,8 @@ func (h *AnthropicHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		case "thinking":
 			// Extended thinking content
 			thinkingContent = content.Text
+		case "tool_use":
+			toolCalls = append(toolCalls, ToolCall{ID: content.ID, Name: content.Name, Arguments: toolArguments(content.Input)})
 		}
 	}
 
@@ -161,11 +169,82 @@ func (h *AnthropicHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		Content:    contentBuilder.String(),
 		Thinking:   thinkingContent,
 		FormatUsed: FormatTypeAnthropic,
+		Usage: TokenUsage{
+			InputTokens:  response.Usage.InputTokens,
+			OutputTokens: response.Usage.OutputTokens,
+		},
+		ToolCalls: toolCalls,
 	}
 
 	return result, nil
 }
 
+// BuildStreamRequest constructs an Anthropic API request with streaming enabled
+func (h *AnthropicHandler) BuildStreamRequest(config RequestConfig) (map[string]interface{}, error) {
+	request, err := h.BuildRequest(config)
+	if err != nil {
+		return nil, err
+	}
+	request["stream"] = true
+	return request, nil
+}
+
+// FormatStreamEndpoint returns the Messages API endpoint, streaming uses the same URL
+func (h *AnthropicHandler) FormatStreamEndpoint(endpoint, model string) string {
+	return h.FormatEndpoint(endpoint, model)
+}
+
+// ParseStreamEvent parses a Messages API stream event.
+// text_delta events carry content and thinking_delta events carry extended thinking.
+// Input tokens are reported by message_start and output tokens by message_delta.
+func (h *AnthropicHandler) ParseStreamEvent(data []byte) (StreamChunk, error) {
+	type usage struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	}
+	var event struct {
+		Type  string `json:"type"`
+		Delta struct {
+			Type     string `json:"type"`
+			Text     string `json:"text"`
+			Thinking string `json:"thinking"`
+		} `json:"delta"`
+		Message struct {
+			Usage usage `json:"usage"`
+		} `json:"message"`
+		Usage usage `json:"usage"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return StreamChunk{}, fmt.Errorf("failed to parse Anthropic stream event: %w", err)
+	}
+
+	switch event.Type {
+	case "error":
+		return StreamChunk{}, fmt.Errorf("Anthropic API error (%s): %s", event.Error.Type, event.Error.Message)
+	case "content_block_delta":
+		switch event.Delta.Type {
+		case "text_delta":
+			return StreamChunk{Content: event.Delta.Text}, nil
+		case "thinking_delta":
+			return StreamChunk{Thinking: event.Delta.Thinking}, nil
+		}
+	case "message_start":
+		return StreamChunk{Usage: TokenUsage{InputTokens: event.Message.Usage.InputTokens}}, nil
+	case "message_delta":
+		return StreamChunk{Usage: TokenUsage{OutputTokens: event.Usage.OutputTokens}}, nil
+	case "message_stop":
+		return StreamChunk{Done: true}, nil
+	}
+
+	// content_block_start/stop and ping carry no text
+	return StreamChunk{}, nil
+}
+
 // ValidateResponse checks if the response is valid
 func (h *AnthropicHandler) ValidateResponse(statusCode int, body []byte) error {
 	var response struct {
@@ -3,12 +3,14 @@ package ai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -48,9 +50,18 @@ func NewClientWithHTTPClient(config ClientConfig, httpClient *http.Client) *Clie
 	}
 }
 
+// workingFormats remembers the format that last succeeded for each endpoint, so the
+// format fallback chain is only walked until a working format has been found
+var workingFormats sync.Map // endpoint -> FormatType
+
 // Request makes an AI request with automatic format detection and fallback
 func (c *Client) Request(systemPrompt, userPrompt string) (string, error) {
-	result, err := c.RequestWithThinking(systemPrompt, userPrompt)
+	return c.RequestWithContext(context.Background(), systemPrompt, userPrompt)
+}
+
+// RequestWithContext makes an AI request that is cancelled with ctx
+func (c *Client) RequestWithContext(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	result, err := c.RequestWithThinkingContext(ctx, systemPrompt, userPrompt)
 	if err != nil {
 		return "", err
 	}
@@ -59,6 +70,11 @@ func (c *Client) Request(systemPrompt, userPrompt string) (string, error) {
 
 // RequestWithThinking makes an AI request and returns both content and thinking
 func (c *Client) RequestWithThinking(systemPrompt, userPrompt string) (ResponseResult, error) {
+	return c.RequestWithThinkingContext(context.Background(), systemPrompt, userPrompt)
+}
+
+// RequestWithThinkingContext makes an AI request that is cancelled with ctx and returns both content and thinking
+func (c *Client) RequestWithThinkingContext(ctx context.Context, systemPrompt, userPrompt string) (ResponseResult, error) {
 	config := RequestConfig{
 		Model:        c.config.Model,
 		SystemPrompt: systemPrompt,
@@ -67,11 +83,16 @@ func (c *Client) RequestWithThinking(systemPrompt, userPrompt string) (ResponseR
 		MaxTokens:    2048,
 	}
 
-	return c.RequestWithConfig(config)
+	return c.RequestWithConfigContext(ctx, config)
 }
 
 // RequestWithMessages makes an AI request using messages format
 func (c *Client) RequestWithMessages(messages []map[string]string) (ResponseResult, error) {
+	return c.RequestWithMessagesContext(context.Background(), messages)
+}
+
+// RequestWithMessagesContext makes an AI request using messages format that is cancelled with ctx
+func (c *Client) RequestWithMessagesContext(ctx context.Context, messages []map[string]string) (ResponseResult, error) {
 	config := RequestConfig{
 		Model:       c.config.Model,
 		Messages:    messages,
@@ -79,71 +100,88 @@ func (c *Client) RequestWithMessages(messages []map[string]string) (ResponseResu
 		MaxTokens:   2048,
 	}
 
-	return c.RequestWithConfig(config)
+	return c.RequestWithConfigContext(ctx, config)
 }
 
 // RequestWithConfig makes an AI request with full configuration
 func (c *Client) RequestWithConfig(config RequestConfig) (ResponseResult, error) {
-	provider := DetectAPIProvider(c.config.Endpoint)
-
-	// Try provider-specific format first based on endpoint detection
-	switch provider {
-	case "gemini":
-		result, err := c.tryFormat(NewGeminiHandler(), config)
-		if err == nil {
-			return result, nil
-		}
-		// Fall through to other formats
+	return c.RequestWithConfigContext(context.Background(), config)
+}
 
-	case "anthropic":
-		result, err := c.tryFormat(&AnthropicHandler{}, config)
-		if err == nil {
-			return result, nil
+// RequestWithConfigContext makes an AI request with full configuration.
+// Cancelling ctx aborts the in-flight request and skips the remaining fallback formats.
+func (c *Client) RequestWithConfigContext(ctx context.Context, config RequestConfig) (ResponseResult, error) {
+	for _, handler := range c.candidateHandlers() {
+		if err := ctx.Err(); err != nil {
+			return ResponseResult{}, err
 		}
-		// Fall through to other formats
 
-	case "deepseek":
-		result, err := c.tryFormat(&DeepSeekHandler{}, config)
+		result, err := c.tryFormat(ctx, handler, config)
 		if err == nil {
+			c.rememberFormat(result.FormatUsed)
+			c.recordUsage(ctx, result)
 			return result, nil
 		}
-		// Fall through to other formats
+	}
 
-	case "ollama":
-		result, err := c.tryFormat(NewOllamaHandler(), config)
-		if err == nil {
-			return result, nil
-		}
-		// Fall through to other formats
+	if err := ctx.Err(); err != nil {
+		return ResponseResult{}, err
 	}
 
-	// Try OpenAI format (most common, good fallback)
-	result, err := c.tryFormat(NewOpenAIHandler(), config)
-	if err == nil {
-		return result, nil
+	// All formats failed
+	return ResponseResult{}, fmt.Errorf("all API formats failed")
+}
+
+// candidateHandlers returns the format handlers to try, in order.
+// A format that worked before for the endpoint comes first, then the format matching
+// the detected provider, followed by OpenAI (most common, good fallback) and the
+// remaining generic formats.
+func (c *Client) candidateHandlers() []FormatHandler {
+	provider := DetectAPIProvider(c.config.Endpoint)
+
+	var handlers []FormatHandler
+	switch provider {
+	case "gemini":
+		handlers = append(handlers, NewGeminiHandler())
+	case "anthropic":
+		handlers = append(handlers, &AnthropicHandler{})
+	case "deepseek":
+		handlers = append(handlers, &DeepSeekHandler{})
+	case "ollama":
+		handlers = append(handlers, NewOllamaHandler())
 	}
 
-	// Try other formats as fallback
+	handlers = append(handlers, NewOpenAIHandler())
 	if provider != "gemini" {
-		result, err = c.tryFormat(NewGeminiHandler(), config)
-		if err == nil {
-			return result, nil
-		}
+		handlers = append(handlers, NewGeminiHandler())
 	}
-
 	if provider != "ollama" {
-		result, err = c.tryFormat(NewOllamaHandler(), config)
-		if err == nil {
-			return result, nil
+		handlers = append(handlers, NewOllamaHandler())
+	}
+
+	remembered, ok := workingFormats.Load(c.config.Endpoint)
+	if !ok {
+		return handlers
+	}
+	for i, handler := range handlers {
+		if formatTypeOf(handler) == remembered.(FormatType) {
+			// Move the remembered format to the front, keeping the others as fallback
+			ordered := append([]FormatHandler{handler}, handlers[:i]...)
+			return append(ordered, handlers[i+1:]...)
 		}
 	}
+	return handlers
+}
 
-	// All formats failed
-	return ResponseResult{}, fmt.Errorf("all API formats failed")
+// rememberFormat records the format that worked for the client's endpoint
+func (c *Client) rememberFormat(format FormatType) {
+	if format != "" {
+		workingFormats.Store(c.config.Endpoint, format)
+	}
 }
 
 // tryFormat attempts to make a request using a specific format handler
-func (c *Client) tryFormat(handler FormatHandler, config RequestConfig) (ResponseResult, error) {
+func (c *Client) tryFormat(ctx context.Context, handler FormatHandler, config RequestConfig) (ResponseResult, error) {
 	// Build request body
 	requestBody, err := handler.BuildRequest(config)
 	if err != nil {
@@ -165,7 +203,7 @@ func (c *Client) tryFormat(handler FormatHandler, config RequestConfig) (Respons
 	}
 
 	// Send request with formatted endpoint and handler
-	resp, err := c.sendRequestToEndpointWithHandler(jsonBody, formattedEndpoint, handler)
+	resp, err := c.sendRequestWithContext(ctx, jsonBody, formattedEndpoint, handler)
 	if err != nil {
 		return ResponseResult{}, fmt.Errorf("request failed: %w", err)
 	}
@@ -185,6 +223,7 @@ func (c *Client) tryFormat(handler FormatHandler, config RequestConfig) (Respons
 	if err != nil {
 		return ResponseResult{}, fmt.Errorf("failed to parse response: %w", err)
 	}
+	result.FormatUsed = formatTypeOf(handler)
 
 	return result, nil
 }
@@ -201,6 +240,11 @@ func (c *Client) sendRequestToEndpoint(jsonBody []byte, apiURL string) (*http.Re
 
 // sendRequestToEndpointWithHandler sends the HTTP request to a specific endpoint with handler-specific headers
 func (c *Client) sendRequestToEndpointWithHandler(jsonBody []byte, apiURL string, handler FormatHandler) (*http.Response, error) {
+	return c.sendRequestWithContext(context.Background(), jsonBody, apiURL, handler)
+}
+
+// sendRequestWithContext sends the HTTP request bound to ctx with handler-specific headers
+func (c *Client) sendRequestWithContext(ctx context.Context, jsonBody []byte, apiURL string, handler FormatHandler) (*http.Response, error) {
 	// Validate endpoint URL to prevent SSRF attacks
 	parsedURL, err := url.Parse(apiURL)
 	if err != nil {
@@ -224,7 +268,7 @@ func (c *Client) sendRequestToEndpointWithHandler(jsonBody []byte, apiURL string
 		apiURL = parsedURL.String()
 	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestWithConfig_RemembersWorkingFormat(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// The loopback endpoint is detected as Ollama, but only the OpenAI format works
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"pong"}}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL + "/v1/chat/completions", Model: "test"})
+
+	result, err := client.RequestWithThinking("", "ping")
+	if err != nil {
+		t.Fatalf("first request error: %v", err)
+	}
+	if result.Content != "pong" || result.FormatUsed != FormatTypeOpenAI {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if n := atomic.LoadInt32(&requests); n < 2 {
+		t.Fatalf("expected the first request to fall back to OpenAI, got %d requests", n)
+	}
+
+	atomic.StoreInt32(&requests, 0)
+	if _, err := client.Request("", "ping"); err != nil {
+		t.Fatalf("second request error: %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected the remembered format to be tried first, got %d requests", n)
+	}
+}
+
+func TestRequestWithContext_Cancelled(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Read the body so the server notices when the client disconnects
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL, Model: "test"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RequestWithContext(ctx, "", "ping")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("request was not cancelled promptly (%v)", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected no fallback formats after cancellation, got %d requests", n)
+	}
+}
+
+func TestRequestWithContext_CollectsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"pong"}}],"usage":{"prompt_tokens":11,"completion_tokens":4}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL + "/v1/chat/completions", Model: "gpt-test"})
+	ctx, collector := WithUsageCollector(context.Background())
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.RequestWithContext(ctx, "", "ping"); err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+	}
+
+	calls := collector.Calls()
+	if len(calls) != 2 || calls[0].Model != "gpt-test" || calls[0].Provider != "ollama" || calls[0].Format != FormatTypeOpenAI {
+		t.Fatalf("unexpected calls: %+v", calls)
+	}
+	if total := collector.Total(); total.InputTokens != 22 || total.OutputTokens != 8 {
+		t.Errorf("unexpected total usage: %+v", total)
+	}
+	if !collector.Reported() {
+		t.Error("expected usage to be reported by the provider")
+	}
+}
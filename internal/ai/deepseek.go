@@ -85,14 +85,18 @@ func (h *DeepSeekHandler) BuildRequest(config RequestConfig) (map[string]interfa
 		request["presence_penalty"] = config.PresencePenalty
 	}
 
-	// Response format (JSON mode)
-	if config.ResponseFormat != nil {
+	// Response format (JSON mode), JSON schemas aren't supported
+	if _, ok := responseSchema(config.ResponseFormat); ok {
+		request["response_format"] = map[string]interface{}{"type": "json_object"}
+	} else if config.ResponseFormat != nil {
 		request["response_format"] = config.ResponseFormat
 	}
 
 	// Stream (always false for now)
 	request["stream"] = false
 
+	addOpenAITools(request, config, true)
+
 	return request, nil
 }
 
@@ -106,17 +110,18 @@ func (h *DeepSeekHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		Choices []struct {
 			Index   int `json:"index"`
 			Message struct {
-				Role    string `json:"role"`
-				Content string `json:"content"`
+				Role      string           `json:"role"`
+				Content   string           `json:"content"`
+				ToolCalls []openAIToolCall `json:"tool_calls"`
 			} `json:"message"`
 			FinishReason string `json:"finish_reason"`
 		} `json:"choices"`
 		Usage struct {
-			PromptTokens          int `json:"prompt_tokens"`
-			CompletionTokens      int `json:"completion_tokens"`
-			TotalTokens           int `json:"total_tokens"`
-			PromptCacheHitTokens  int `json:"prompt_cache_hit_tokens"`
-			PromptCacheMissTokens int `json:"prompt_cache_miss_tokens"`
+			PromptTokens          int64 `json:"prompt_tokens"`
+			CompletionTokens      int64 `json:"completion_tokens"`
+			TotalTokens           int64 `json:"total_tokens"`
+			PromptCacheHitTokens  int64 `json:"prompt_cache_hit_tokens"`
+			PromptCacheMissTokens int64 `json:"prompt_cache_miss_tokens"`
 		} `json:"usage"`
 		Error struct {
 			Message string `json:"message"`
@@ -144,6 +149,11 @@ func (h *DeepSeekHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	result := ResponseResult{
 		Content:    content,
 		FormatUsed: FormatTypeDeepSeek,
+		Usage: TokenUsage{
+			InputTokens:  response.Usage.PromptTokens,
+			OutputTokens: response.Usage.CompletionTokens,
+		},
+		ToolCalls: parseOpenAIToolCalls(response.Choices[0].Message.ToolCalls),
 	}
 
 	// DeepSeek doesn't have separate thinking content in standard mode
@@ -152,6 +162,28 @@ func (h *DeepSeekHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	return result, nil
 }
 
+// BuildStreamRequest constructs a DeepSeek API request with streaming enabled
+func (h *DeepSeekHandler) BuildStreamRequest(config RequestConfig) (map[string]interface{}, error) {
+	request, err := h.BuildRequest(config)
+	if err != nil {
+		return nil, err
+	}
+	request["stream"] = true
+	request["stream_options"] = map[string]interface{}{"include_usage": true}
+	return request, nil
+}
+
+// FormatStreamEndpoint returns the chat completions endpoint, streaming uses the same URL
+func (h *DeepSeekHandler) FormatStreamEndpoint(endpoint, model string) string {
+	return h.FormatEndpoint(endpoint, model)
+}
+
+// ParseStreamEvent parses a chat completion chunk of a DeepSeek stream.
+// deepseek-reasoner sends its chain of thought as reasoning_content deltas.
+func (h *DeepSeekHandler) ParseStreamEvent(data []byte) (StreamChunk, error) {
+	return parseChatCompletionChunk(data, "DeepSeek")
+}
+
 // ValidateResponse checks if the response is valid
 func (h *DeepSeekHandler) ValidateResponse(statusCode int, body []byte) error {
 	var response struct {
@@ -160,7 +192,8 @@ func (h *DeepSeekHandler) ValidateResponse(statusCode int, body []byte) error {
 		} `json:"error"`
 		Choices []struct {
 			Message struct {
-				Content string `json:"content"`
+				Content   string            `json:"content"`
+				ToolCalls []json.RawMessage `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
 	}
@@ -177,7 +210,7 @@ func (h *DeepSeekHandler) ValidateResponse(statusCode int, body []byte) error {
 		return fmt.Errorf("no choices in response")
 	}
 
-	if response.Choices[0].Message.Content == "" {
+	if response.Choices[0].Message.Content == "" && len(response.Choices[0].Message.ToolCalls) == 0 {
 		return fmt.Errorf("empty content in response")
 	}
 
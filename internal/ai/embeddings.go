@@ -0,0 +1,174 @@
+// Package ai provides text embeddings through OpenAI-compatible and Ollama APIs
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// workingEmbeddingFormats remembers the embedding format that last succeeded for each endpoint
+var workingEmbeddingFormats sync.Map // endpoint -> FormatType
+
+// Embed returns one embedding vector per text, in the order of texts.
+// The OpenAI-compatible /embeddings API and Ollama's /api/embed are supported;
+// the format matching the endpoint is tried first, the other one as fallback.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return [][]float32{}, nil
+	}
+
+	var lastErr error
+	for _, format := range c.embeddingFormats() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var vectors [][]float32
+		var usage TokenUsage
+		var err error
+		if format == FormatTypeOllama {
+			vectors, usage, err = c.embedOllama(ctx, texts)
+		} else {
+			vectors, usage, err = c.embedOpenAI(ctx, texts)
+		}
+		if err == nil {
+			workingEmbeddingFormats.Store(c.config.Endpoint, format)
+			c.recordUsage(ctx, ResponseResult{FormatUsed: format, Usage: usage})
+			return vectors, nil
+		}
+		lastErr = err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("all embedding formats failed: %w", lastErr)
+}
+
+// embeddingFormats returns the embedding formats to try, in order
+func (c *Client) embeddingFormats() []FormatType {
+	formats := []FormatType{FormatTypeOpenAI, FormatTypeOllama}
+	if DetectAPIProvider(c.config.Endpoint) == "ollama" {
+		formats = []FormatType{FormatTypeOllama, FormatTypeOpenAI}
+	}
+	if remembered, ok := workingEmbeddingFormats.Load(c.config.Endpoint); ok && remembered.(FormatType) != formats[0] {
+		formats[0], formats[1] = formats[1], formats[0]
+	}
+	return formats
+}
+
+// embedOpenAI requests embeddings from an OpenAI-compatible /embeddings endpoint
+func (c *Client) embedOpenAI(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	body, err := c.postEmbeddingRequest(ctx, OpenAIEmbeddingsEndpoint(c.config.Endpoint), texts)
+	if err != nil {
+		return nil, TokenUsage{}, err
+	}
+
+	var response struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		Usage openAIUsage `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, TokenUsage{}, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	if len(response.Data) != len(texts) {
+		return nil, TokenUsage{}, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(response.Data))
+	}
+
+	sort.SliceStable(response.Data, func(i, j int) bool { return response.Data[i].Index < response.Data[j].Index })
+	vectors := make([][]float32, len(response.Data))
+	for i, item := range response.Data {
+		if len(item.Embedding) == 0 {
+			return nil, TokenUsage{}, fmt.Errorf("empty embedding in response")
+		}
+		vectors[i] = item.Embedding
+	}
+	return vectors, response.Usage.tokenUsage(), nil
+}
+
+// embedOllama requests embeddings from Ollama's /api/embed endpoint
+func (c *Client) embedOllama(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	body, err := c.postEmbeddingRequest(ctx, OllamaEmbeddingsEndpoint(c.config.Endpoint), texts)
+	if err != nil {
+		return nil, TokenUsage{}, err
+	}
+
+	var response struct {
+		Embeddings [][]float32 `json:"embeddings"`
+		Error      string      `json:"error,omitempty"`
+		ollamaUsage
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, TokenUsage{}, fmt.Errorf("failed to decode Ollama embeddings response: %w", err)
+	}
+	if response.Error != "" {
+		return nil, TokenUsage{}, fmt.Errorf("Ollama API error: %s", response.Error)
+	}
+	if len(response.Embeddings) != len(texts) {
+		return nil, TokenUsage{}, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(response.Embeddings))
+	}
+	return response.Embeddings, response.tokenUsage(), nil
+}
+
+// postEmbeddingRequest sends the texts to an embeddings endpoint and returns the response body
+func (c *Client) postEmbeddingRequest(ctx context.Context, endpoint string, texts []string) ([]byte, error) {
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"model": c.config.Model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.sendRequestWithContext(ctx, jsonBody, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings API returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
+
+// OpenAIEmbeddingsEndpoint derives the /embeddings URL from a chat completions endpoint,
+// e.g. "https://api.openai.com/v1/chat/completions" becomes "https://api.openai.com/v1/embeddings"
+func OpenAIEmbeddingsEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	switch {
+	case strings.HasSuffix(endpoint, "/embeddings"):
+		return endpoint
+	case strings.HasSuffix(endpoint, "/chat/completions"):
+		return strings.TrimSuffix(endpoint, "/chat/completions") + "/embeddings"
+	case strings.HasSuffix(endpoint, "/completions"):
+		return strings.TrimSuffix(endpoint, "/completions") + "/embeddings"
+	}
+	return endpoint + "/embeddings"
+}
+
+// OllamaEmbeddingsEndpoint derives the /api/embed URL from an Ollama endpoint,
+// e.g. "http://localhost:11434/api/generate" becomes "http://localhost:11434/api/embed"
+func OllamaEmbeddingsEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	for _, suffix := range []string{"/api/embed", "/api/generate", "/api/chat", "/api"} {
+		if strings.HasSuffix(endpoint, suffix) {
+			endpoint = strings.TrimSuffix(endpoint, suffix)
+			break
+		}
+	}
+	return endpoint + "/api/embed"
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEmbed_OpenAIFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model != "embed-model" || len(request.Input) != 2 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		// Items may come back out of order
+		fmt.Fprint(w, `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}],"usage":{"prompt_tokens":7}}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL + "/v1/chat/completions", Model: "embed-model"})
+	ctx, collector := WithUsageCollector(context.Background())
+
+	vectors, err := client.Embed(ctx, []string{"first", "second"})
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("unexpected vectors: %v", vectors)
+	}
+	if usage := collector.Total(); usage.InputTokens != 7 {
+		t.Errorf("expected 7 input tokens to be collected, got %+v", usage)
+	}
+}
+
+func TestEmbed_OllamaFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/embed" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"embeddings":[[0.5,0.5]],"prompt_eval_count":3}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL + "/api/generate", Model: "nomic-embed-text"})
+
+	vectors, err := client.Embed(context.Background(), []string{"text"})
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	if len(vectors) != 1 || len(vectors[0]) != 2 {
+		t.Errorf("unexpected vectors: %v", vectors)
+	}
+	if formats := client.embeddingFormats(); formats[0] != FormatTypeOllama {
+		t.Errorf("expected the Ollama format to be remembered, got %v", formats)
+	}
+}
+
+func TestEmbeddingsEndpoints(t *testing.T) {
+	openAICases := map[string]string{
+		"https://api.openai.com/v1/chat/completions": "https://api.openai.com/v1/embeddings",
+		"https://api.openai.com/v1/":                 "https://api.openai.com/v1/embeddings",
+		"https://example.com/v1/embeddings":          "https://example.com/v1/embeddings",
+	}
+	for endpoint, want := range openAICases {
+		if got := OpenAIEmbeddingsEndpoint(endpoint); got != want {
+			t.Errorf("OpenAIEmbeddingsEndpoint(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+
+	ollamaCases := map[string]string{
+		"http://localhost:11434/api/generate": "http://localhost:11434/api/embed",
+		"http://localhost:11434/api/chat":     "http://localhost:11434/api/embed",
+		"http://localhost:11434":              "http://localhost:11434/api/embed",
+	}
+	for endpoint, want := range ollamaCases {
+		if got := OllamaEmbeddingsEndpoint(endpoint); got != want {
+			t.Errorf("OllamaEmbeddingsEndpoint(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
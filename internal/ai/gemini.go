@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 )
@@ -75,6 +76,9 @@ func (h *GeminiHandler) BuildRequest(config RequestConfig) (map[string]interface
 	if config.Temperature > 0 {
 		genConfig["temperature"] = config.Temperature
 	}
+	if _, ok := responseSchema(config.ResponseFormat); ok {
+		genConfig["responseMimeType"] = "application/json"
+	}
 	if config.MaxTokens > 0 {
 		genConfig["maxOutputTokens"] = config.MaxTokens
 	}
@@ -115,6 +119,8 @@ func (h *GeminiHandler) BuildRequest(config RequestConfig) (map[string]interface
 		request["thinkingConfig"] = config.ThinkingConfig
 	}
 
+	addGeminiTools(request, config)
+
 	return request, nil
 }
 
@@ -137,7 +143,11 @@ func (h *GeminiHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		Candidates []struct {
 			Content struct {
 				Parts []struct {
-					Text string `json:"text"`
+					Text         string `json:"text"`
+					FunctionCall *struct {
+						Name string          `json:"name"`
+						Args json.RawMessage `json:"args"`
+					} `json:"functionCall,omitempty"`
 				} `json:"parts"`
 			} `json:"content"`
 			FinishReason string `json:"finishReason"`
@@ -145,6 +155,7 @@ func (h *GeminiHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		PromptFeedback struct {
 			BlockReason string `json:"blockReason,omitempty"`
 		} `json:"promptFeedback"`
+		UsageMetadata geminiUsage `json:"usageMetadata"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -178,13 +189,109 @@ func (h *GeminiHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		return ResponseResult{}, fmt.Errorf("response blocked for image safety reasons")
 	}
 
+	// Function calls come as separate parts, possibly after a text part
+	var toolCalls []ToolCall
+	for _, part := range candidate.Content.Parts {
+		if part.FunctionCall != nil {
+			toolCalls = append(toolCalls, ToolCall{ID: generatedCallID(len(toolCalls)), Name: part.FunctionCall.Name, Arguments: toolArguments(part.FunctionCall.Args)})
+		}
+	}
+
 	content := strings.TrimSpace(candidate.Content.Parts[0].Text)
 	return ResponseResult{
 		Content:    content,
 		FormatUsed: FormatTypeGemini,
+		Usage:      response.UsageMetadata.tokenUsage(),
+		ToolCalls:  toolCalls,
 	}, nil
 }
 
+// geminiUsage is the usageMetadata object of GenerateContentResponse.
+// Thinking tokens are billed as output.
+type geminiUsage struct {
+	PromptTokenCount     int64 `json:"promptTokenCount"`
+	CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+	ThoughtsTokenCount   int64 `json:"thoughtsTokenCount"`
+}
+
+func (u geminiUsage) tokenUsage() TokenUsage {
+	return TokenUsage{InputTokens: u.PromptTokenCount, OutputTokens: u.CandidatesTokenCount + u.ThoughtsTokenCount}
+}
+
+// BuildStreamRequest builds a Gemini API request for streaming.
+// Gemini selects streaming through the endpoint, so the body is unchanged.
+func (h *GeminiHandler) BuildStreamRequest(config RequestConfig) (map[string]interface{}, error) {
+	return h.BuildRequest(config)
+}
+
+// FormatStreamEndpoint formats the streamGenerateContent endpoint, requesting Server-Sent Events
+func (h *GeminiHandler) FormatStreamEndpoint(endpoint, model string) string {
+	formatted := strings.Replace(FormatGeminiEndpoint(endpoint, model), ":generateContent", ":streamGenerateContent", 1)
+
+	parsed, err := url.Parse(formatted)
+	if err != nil {
+		return formatted
+	}
+	query := parsed.Query()
+	query.Set("alt", "sse")
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// ParseStreamEvent parses a streamed GenerateContentResponse.
+// Parts flagged as thoughts are returned as thinking.
+func (h *GeminiHandler) ParseStreamEvent(data []byte) (StreamChunk, error) {
+	var event struct {
+		Candidates []struct {
+			Content struct {
+				Parts []struct {
+					Text    string `json:"text"`
+					Thought bool   `json:"thought"`
+				} `json:"parts"`
+			} `json:"content"`
+			FinishReason string `json:"finishReason"`
+		} `json:"candidates"`
+		PromptFeedback struct {
+			BlockReason string `json:"blockReason,omitempty"`
+		} `json:"promptFeedback"`
+		UsageMetadata geminiUsage `json:"usageMetadata"`
+		Error         struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return StreamChunk{}, fmt.Errorf("failed to decode Gemini stream event: %w", err)
+	}
+	if event.Error.Code != 0 {
+		return StreamChunk{}, fmt.Errorf("Gemini API error (code %d): %s", event.Error.Code, event.Error.Message)
+	}
+	if event.PromptFeedback.BlockReason != "" {
+		return StreamChunk{}, fmt.Errorf("prompt blocked: %s", event.PromptFeedback.BlockReason)
+	}
+	usage := event.UsageMetadata.tokenUsage()
+	if len(event.Candidates) == 0 {
+		return StreamChunk{Usage: usage}, nil
+	}
+
+	candidate := event.Candidates[0]
+	switch candidate.FinishReason {
+	case "SAFETY", "RECITATION", "IMAGE_SAFETY":
+		return StreamChunk{}, fmt.Errorf("response blocked (%s)", strings.ToLower(candidate.FinishReason))
+	}
+
+	chunk := StreamChunk{Usage: usage}
+	for _, part := range candidate.Content.Parts {
+		if part.Thought {
+			chunk.Thinking += part.Text
+		} else {
+			chunk.Content += part.Text
+		}
+	}
+	return chunk, nil
+}
+
 // ValidateResponse validates the HTTP response status
 func (h *GeminiHandler) ValidateResponse(statusCode int, body []byte) error {
 	switch statusCode {
@@ -76,10 +76,17 @@ func (h *OllamaHandler) BuildRequest(config RequestConfig) (map[string]interface
 	}
 
 	// Add format for structured outputs (JSON schema)
-	if config.ResponseFormat != nil {
+	if schema, ok := responseSchema(config.ResponseFormat); ok {
+		request["format"] = schema
+	} else if config.ResponseFormat != nil {
 		request["format"] = config.ResponseFormat
 	}
 
+	// Tools are only supported by /api/chat
+	if useChat {
+		addOpenAITools(request, config, false)
+	}
+
 	return request, nil
 }
 
@@ -88,14 +95,16 @@ func (h *OllamaHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	// Try parsing as chat response first (new format)
 	var chatResponse struct {
 		Message struct {
-			Role    string `json:"role"`
-			Content string `json:"content"`
+			Role      string           `json:"role"`
+			Content   string           `json:"content"`
+			ToolCalls []ollamaToolCall `json:"tool_calls"`
 		} `json:"message"`
 		Done  bool   `json:"done"`
 		Error string `json:"error,omitempty"`
+		ollamaUsage
 	}
 
-	if err := json.Unmarshal(body, &chatResponse); err == nil && chatResponse.Message.Content != "" {
+	if err := json.Unmarshal(body, &chatResponse); err == nil && (chatResponse.Message.Content != "" || len(chatResponse.Message.ToolCalls) > 0) {
 		// Check for Ollama error
 		if chatResponse.Error != "" {
 			return ResponseResult{}, fmt.Errorf("Ollama API error: %s", chatResponse.Error)
@@ -107,13 +116,16 @@ func (h *OllamaHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		}
 
 		content := strings.TrimSpace(chatResponse.Message.Content)
-		if content == "" {
+		toolCalls := parseOllamaToolCalls(chatResponse.Message.ToolCalls)
+		if content == "" && len(toolCalls) == 0 {
 			return ResponseResult{}, fmt.Errorf("empty response from Ollama")
 		}
 
 		return ResponseResult{
 			Content:    content,
 			FormatUsed: FormatTypeOllama,
+			Usage:      chatResponse.tokenUsage(),
+			ToolCalls:  toolCalls,
 		}, nil
 	}
 
@@ -122,6 +134,7 @@ func (h *OllamaHandler) ParseResponse(body []byte) (ResponseResult, error) {
 		Response string `json:"response"`
 		Done     bool   `json:"done"`
 		Error    string `json:"error,omitempty"`
+		ollamaUsage
 	}
 
 	if err := json.Unmarshal(body, &generateResponse); err != nil {
@@ -146,6 +159,63 @@ func (h *OllamaHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	return ResponseResult{
 		Content:    content,
 		FormatUsed: FormatTypeOllama,
+		Usage:      generateResponse.tokenUsage(),
+	}, nil
+}
+
+// ollamaUsage holds the token counts Ollama reports on its final response
+type ollamaUsage struct {
+	PromptEvalCount int64 `json:"prompt_eval_count"`
+	EvalCount       int64 `json:"eval_count"`
+}
+
+func (u ollamaUsage) tokenUsage() TokenUsage {
+	return TokenUsage{InputTokens: u.PromptEvalCount, OutputTokens: u.EvalCount}
+}
+
+// BuildStreamRequest builds an Ollama API request with streaming enabled
+func (h *OllamaHandler) BuildStreamRequest(config RequestConfig) (map[string]interface{}, error) {
+	request, err := h.BuildRequest(config)
+	if err != nil {
+		return nil, err
+	}
+	request["stream"] = true
+	return request, nil
+}
+
+// FormatStreamEndpoint returns the same endpoint as non-streaming requests
+func (h *OllamaHandler) FormatStreamEndpoint(endpoint, model string) string {
+	return h.FormatEndpoint(endpoint, model)
+}
+
+// ParseStreamEvent parses one line of Ollama's newline-delimited JSON stream.
+// Both /api/chat (message) and /api/generate (response) lines are supported,
+// with the thinking field of reasoning models returned as thinking.
+func (h *OllamaHandler) ParseStreamEvent(data []byte) (StreamChunk, error) {
+	var line struct {
+		Message struct {
+			Content  string `json:"content"`
+			Thinking string `json:"thinking"`
+		} `json:"message"`
+		Response string `json:"response"`
+		Thinking string `json:"thinking"`
+		Done     bool   `json:"done"`
+		Error    string `json:"error,omitempty"`
+		ollamaUsage
+	}
+
+	if err := json.Unmarshal(data, &line); err != nil {
+		return StreamChunk{}, fmt.Errorf("failed to decode Ollama stream line: %w", err)
+	}
+	if line.Error != "" {
+		return StreamChunk{}, fmt.Errorf("Ollama API error: %s", line.Error)
+	}
+
+	return StreamChunk{
+		Content:  line.Message.Content + line.Response,
+		Thinking: line.Message.Thinking + line.Thinking,
+		Done:     line.Done,
+		Usage:    line.tokenUsage(),
 	}, nil
 }
 
@@ -85,6 +85,8 @@ func (h *OpenAIHandler) BuildRequest(config RequestConfig) (map[string]interface
 		request["seed"] = config.Seed
 	}
 
+	addOpenAITools(request, config, true)
+
 	return request, nil
 }
 
@@ -93,9 +95,11 @@ func (h *OpenAIHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	var response struct {
 		Choices []struct {
 			Message struct {
-				Content string `json:"content"`
+				Content   string           `json:"content"`
+				ToolCalls []openAIToolCall `json:"tool_calls"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage openAIUsage `json:"usage"`
 		Error *struct {
 			Message string `json:"message"`
 			Type    string `json:"type"`
@@ -117,16 +121,94 @@ func (h *OpenAIHandler) ParseResponse(body []byte) (ResponseResult, error) {
 	}
 
 	content := strings.TrimSpace(response.Choices[0].Message.Content)
-	if content == "" {
+	toolCalls := parseOpenAIToolCalls(response.Choices[0].Message.ToolCalls)
+	if content == "" && len(toolCalls) == 0 {
 		return ResponseResult{}, fmt.Errorf("empty content in OpenAI response")
 	}
 
 	return ResponseResult{
 		Content:    content,
 		FormatUsed: FormatTypeOpenAI,
+		Usage:      response.Usage.tokenUsage(),
+		ToolCalls:  toolCalls,
 	}, nil
 }
 
+// openAIUsage is the usage object of OpenAI-compatible chat completions
+type openAIUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+}
+
+func (u openAIUsage) tokenUsage() TokenUsage {
+	return TokenUsage{InputTokens: u.PromptTokens, OutputTokens: u.CompletionTokens}
+}
+
+// BuildStreamRequest builds an OpenAI-compatible API request with streaming enabled.
+// Usage is requested so that the final chunk reports token counts.
+func (h *OpenAIHandler) BuildStreamRequest(config RequestConfig) (map[string]interface{}, error) {
+	request, err := h.BuildRequest(config)
+	if err != nil {
+		return nil, err
+	}
+	request["stream"] = true
+	request["stream_options"] = map[string]interface{}{"include_usage": true}
+	return request, nil
+}
+
+// FormatStreamEndpoint returns the endpoint as-is, streaming uses the same URL
+func (h *OpenAIHandler) FormatStreamEndpoint(endpoint, model string) string {
+	return h.FormatEndpoint(endpoint, model)
+}
+
+// ParseStreamEvent parses a chat completion chunk of an OpenAI-compatible stream.
+// Reasoning deltas (reasoning_content or reasoning) are returned as thinking.
+func (h *OpenAIHandler) ParseStreamEvent(data []byte) (StreamChunk, error) {
+	return parseChatCompletionChunk(data, "OpenAI")
+}
+
+// parseChatCompletionChunk parses a chat.completion.chunk event shared by OpenAI-compatible APIs
+func parseChatCompletionChunk(data []byte, provider string) (StreamChunk, error) {
+	var chunk struct {
+		Choices []struct {
+			Delta struct {
+				Content          string `json:"content"`
+				ReasoningContent string `json:"reasoning_content"`
+				Reasoning        string `json:"reasoning"`
+			} `json:"delta"`
+		} `json:"choices"`
+		Usage *openAIUsage `json:"usage,omitempty"`
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"error,omitempty"`
+	}
+
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return StreamChunk{}, fmt.Errorf("failed to decode %s stream event: %w", provider, err)
+	}
+	if chunk.Error != nil {
+		return StreamChunk{}, fmt.Errorf("%s API error: %s (type: %s)", provider, chunk.Error.Message, chunk.Error.Type)
+	}
+
+	var usage TokenUsage
+	if chunk.Usage != nil {
+		usage = chunk.Usage.tokenUsage()
+	}
+
+	// Usage-only chunks at the end of the stream have no choices
+	if len(chunk.Choices) == 0 {
+		return StreamChunk{Usage: usage}, nil
+	}
+
+	delta := chunk.Choices[0].Delta
+	thinking := delta.ReasoningContent
+	if thinking == "" {
+		thinking = delta.Reasoning
+	}
+	return StreamChunk{Content: delta.Content, Thinking: thinking, Usage: usage}, nil
+}
+
 // ValidateResponse validates the HTTP response status
 func (h *OpenAIHandler) ValidateResponse(statusCode int, body []byte) error {
 	switch statusCode {
@@ -0,0 +1,368 @@
+// Package ai provides streaming support for AI responses
+package ai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxStreamLineSize is the largest single SSE/NDJSON line accepted from a provider
+const maxStreamLineSize = 1 << 20
+
+// errStreamDone is returned by a stream reader callback to stop reading
+var errStreamDone = errors.New("stream done")
+
+// StreamChunk is an incremental piece of a streamed response
+type StreamChunk struct {
+	Content  string `json:"content,omitempty"`  // Content delta
+	Thinking string `json:"thinking,omitempty"` // Thinking/reasoning delta
+	Done     bool   `json:"done,omitempty"`     // Set on the final event of the stream
+
+	// Usage carries token counts reported by the provider, usually on the last events.
+	// Counts are cumulative, so the largest value seen for each field is kept.
+	Usage TokenUsage `json:"-"`
+}
+
+// StreamCallback receives response deltas as they arrive.
+// Returning an error aborts the stream.
+type StreamCallback func(chunk StreamChunk) error
+
+// StreamHandler is implemented by format handlers that support streaming responses.
+// Streams are read as Server-Sent Events ("data: ..." lines) or newline-delimited JSON,
+// and each event payload is passed to ParseStreamEvent.
+type StreamHandler interface {
+	FormatHandler
+
+	// BuildStreamRequest builds the request body with streaming enabled
+	BuildStreamRequest(config RequestConfig) (map[string]interface{}, error)
+
+	// FormatStreamEndpoint formats the endpoint URL for streaming requests
+	FormatStreamEndpoint(endpoint, model string) string
+
+	// ParseStreamEvent parses a single event payload into a delta
+	ParseStreamEvent(data []byte) (StreamChunk, error)
+}
+
+// StreamWithMessages makes a streaming AI request using messages format
+func (c *Client) StreamWithMessages(ctx context.Context, messages []map[string]string, onChunk StreamCallback) (ResponseResult, error) {
+	config := RequestConfig{
+		Model:       c.config.Model,
+		Messages:    messages,
+		Temperature: 0.3,
+		MaxTokens:   2048,
+	}
+
+	return c.StreamWithConfig(ctx, config, onChunk)
+}
+
+// StreamWithThinking makes a streaming AI request from a system and user prompt
+func (c *Client) StreamWithThinking(ctx context.Context, systemPrompt, userPrompt string, onChunk StreamCallback) (ResponseResult, error) {
+	config := RequestConfig{
+		Model:        c.config.Model,
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		Temperature:  0.3,
+		MaxTokens:    2048,
+	}
+
+	return c.StreamWithConfig(ctx, config, onChunk)
+}
+
+// StreamWithConfig makes a streaming AI request with full configuration.
+// onChunk is called for every content or thinking delta, with inline <think> blocks
+// routed to Thinking. Formats are tried in the same order as RequestWithConfig, but
+// only until the first delta has been delivered. The accumulated result is returned.
+func (c *Client) StreamWithConfig(ctx context.Context, config RequestConfig, onChunk StreamCallback) (ResponseResult, error) {
+	var lastErr error
+	for _, handler := range c.candidateHandlers() {
+		streamHandler, ok := handler.(StreamHandler)
+		if !ok {
+			continue
+		}
+
+		result, delivered, err := c.tryStream(ctx, streamHandler, config, onChunk)
+		if err == nil {
+			c.rememberFormat(result.FormatUsed)
+			c.recordUsage(ctx, result)
+			return result, nil
+		}
+		// Don't retry once the caller has seen output, or when the request was cancelled
+		if delivered || ctx.Err() != nil {
+			return result, err
+		}
+		lastErr = err
+	}
+
+	if lastErr != nil {
+		return ResponseResult{}, fmt.Errorf("all API formats failed: %w", lastErr)
+	}
+	return ResponseResult{}, fmt.Errorf("all API formats failed")
+}
+
+// tryStream attempts a streaming request using a specific format handler.
+// It reports whether any delta was delivered to onChunk.
+func (c *Client) tryStream(ctx context.Context, handler StreamHandler, config RequestConfig, onChunk StreamCallback) (ResponseResult, bool, error) {
+	requestBody, err := handler.BuildStreamRequest(config)
+	if err != nil {
+		return ResponseResult{}, false, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return ResponseResult{}, false, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	formattedEndpoint := handler.FormatStreamEndpoint(c.config.Endpoint, c.config.Model)
+
+	// Special handling for Ollama: use /api/chat if messages are provided
+	if _, ok := handler.(*OllamaHandler); ok && len(config.Messages) > 0 {
+		formattedEndpoint = strings.Replace(formattedEndpoint, "/api/generate", "/api/chat", 1)
+	}
+
+	resp, err := c.sendStreamRequest(ctx, jsonBody, formattedEndpoint, handler)
+	if err != nil {
+		return ResponseResult{}, false, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxStreamLineSize))
+		if err := handler.ValidateResponse(resp.StatusCode, body); err != nil {
+			return ResponseResult{}, false, err
+		}
+		return ResponseResult{}, false, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	var content, thinking strings.Builder
+	var usage TokenUsage
+	delivered := false
+	splitter := &thinkTagSplitter{}
+
+	emit := func(chunk StreamChunk) error {
+		if chunk.Content == "" && chunk.Thinking == "" {
+			return nil
+		}
+		content.WriteString(chunk.Content)
+		thinking.WriteString(chunk.Thinking)
+		delivered = true
+		if onChunk != nil {
+			return onChunk(chunk)
+		}
+		return nil
+	}
+
+	err = readStream(resp.Body, func(data []byte) error {
+		chunk, err := handler.ParseStreamEvent(data)
+		if err != nil {
+			return err
+		}
+		usage.InputTokens = max(usage.InputTokens, chunk.Usage.InputTokens)
+		usage.OutputTokens = max(usage.OutputTokens, chunk.Usage.OutputTokens)
+
+		text, inlineThinking := splitter.Feed(chunk.Content)
+		if err := emit(StreamChunk{Content: text, Thinking: chunk.Thinking + inlineThinking}); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return errStreamDone
+		}
+		return nil
+	})
+	if err == nil || err == errStreamDone {
+		text, inlineThinking := splitter.Flush()
+		err = emit(StreamChunk{Content: text, Thinking: inlineThinking})
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	result := ResponseResult{
+		Content:    strings.TrimSpace(content.String()),
+		Thinking:   strings.TrimSpace(thinking.String()),
+		FormatUsed: formatTypeOf(handler),
+		Usage:      usage,
+	}
+	if err != nil {
+		return result, delivered, err
+	}
+	if result.Content == "" {
+		return result, delivered, fmt.Errorf("empty content in streamed response")
+	}
+	if onChunk != nil {
+		if err := onChunk(StreamChunk{Done: true}); err != nil {
+			return result, delivered, err
+		}
+	}
+	return result, delivered, nil
+}
+
+// sendStreamRequest sends a streaming request. The request is bound to ctx instead of
+// the client's overall timeout, which would cut off long responses.
+func (c *Client) sendStreamRequest(ctx context.Context, jsonBody []byte, apiURL string, handler FormatHandler) (*http.Response, error) {
+	streamClient := *c.client
+	streamClient.Timeout = 0
+
+	client := &Client{config: c.config, client: &streamClient}
+	return client.sendRequestWithContext(ctx, jsonBody, apiURL, handler)
+}
+
+// readStream reads Server-Sent Events or newline-delimited JSON from r and calls
+// onEvent with each event payload. "[DONE]" markers end the stream.
+func readStream(r io.Reader, onEvent func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+
+	var data bytes.Buffer
+	dispatch := func() error {
+		if data.Len() == 0 {
+			return nil
+		}
+		payload := bytes.TrimSpace(data.Bytes())
+		data.Reset()
+		if string(payload) == "[DONE]" {
+			return errStreamDone
+		}
+		return onEvent(payload)
+	}
+
+	for scanner.Scan() {
+		line := bytes.TrimRight(scanner.Bytes(), "\r")
+
+		switch {
+		case len(line) == 0:
+			// Blank line ends an SSE event
+			if err := dispatch(); err != nil {
+				return err
+			}
+		case bytes.HasPrefix(line, []byte("data:")):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.Write(bytes.TrimPrefix(bytes.TrimPrefix(line, []byte("data:")), []byte(" ")))
+		case line[0] == ':' || bytes.HasPrefix(line, []byte("event:")) ||
+			bytes.HasPrefix(line, []byte("id:")) || bytes.HasPrefix(line, []byte("retry:")):
+			// SSE comments and fields other than data carry no payload we need
+		default:
+			// Newline-delimited JSON (Ollama)
+			if err := dispatch(); err != nil {
+				return err
+			}
+			if err := onEvent(bytes.TrimSpace(line)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stream: %w", err)
+	}
+	return dispatch()
+}
+
+// formatTypeOf returns the format type of a handler
+func formatTypeOf(handler FormatHandler) FormatType {
+	switch handler.(type) {
+	case *GeminiHandler:
+		return FormatTypeGemini
+	case *AnthropicHandler:
+		return FormatTypeAnthropic
+	case *DeepSeekHandler:
+		return FormatTypeDeepSeek
+	case *OllamaHandler:
+		return FormatTypeOllama
+	default:
+		return FormatTypeOpenAI
+	}
+}
+
+// thinkTagSplitter separates inline <think>/<thinking> blocks from streamed content.
+// Tags may be split across deltas, so a possible partial tag is held back until
+// the next delta arrives.
+type thinkTagSplitter struct {
+	pending string
+	inThink bool
+}
+
+var (
+	thinkOpenTags  = []string{"<thinking>", "<think>"}
+	thinkCloseTags = []string{"</thinking>", "</think>"}
+)
+
+// Feed adds a content delta and returns the parts that can be emitted
+func (s *thinkTagSplitter) Feed(delta string) (content, thinking string) {
+	s.pending += delta
+	var contentOut, thinkingOut strings.Builder
+
+	for s.pending != "" {
+		tags := thinkOpenTags
+		if s.inThink {
+			tags = thinkCloseTags
+		}
+
+		index, tagLen := indexTag(s.pending, tags)
+		if index >= 0 {
+			s.write(&contentOut, &thinkingOut, s.pending[:index])
+			s.pending = s.pending[index+tagLen:]
+			s.inThink = !s.inThink
+			continue
+		}
+
+		// Hold back a trailing partial tag
+		keep := partialTagSuffix(s.pending, tags)
+		s.write(&contentOut, &thinkingOut, s.pending[:len(s.pending)-keep])
+		s.pending = s.pending[len(s.pending)-keep:]
+		break
+	}
+
+	return contentOut.String(), thinkingOut.String()
+}
+
+// Flush returns any held back text at the end of the stream
+func (s *thinkTagSplitter) Flush() (content, thinking string) {
+	var contentOut, thinkingOut strings.Builder
+	s.write(&contentOut, &thinkingOut, s.pending)
+	s.pending = ""
+	return contentOut.String(), thinkingOut.String()
+}
+
+// write appends text to the content or thinking output depending on the current state
+func (s *thinkTagSplitter) write(content, thinking *strings.Builder, text string) {
+	if s.inThink {
+		thinking.WriteString(text)
+	} else {
+		content.WriteString(text)
+	}
+}
+
+// indexTag returns the position and length of the first of tags in text (case-insensitive)
+func indexTag(text string, tags []string) (int, int) {
+	lower := strings.ToLower(text)
+	bestIndex, bestLen := -1, 0
+	for _, tag := range tags {
+		if i := strings.Index(lower, tag); i >= 0 && (bestIndex < 0 || i < bestIndex) {
+			bestIndex, bestLen = i, len(tag)
+		}
+	}
+	return bestIndex, bestLen
+}
+
+// partialTagSuffix returns the length of the longest suffix of text that is a prefix of one of tags
+func partialTagSuffix(text string, tags []string) int {
+	lt := strings.LastIndex(text, "<")
+	if lt < 0 {
+		return 0
+	}
+	suffix := strings.ToLower(text[lt:])
+	for _, tag := range tags {
+		if len(suffix) < len(tag) && strings.HasPrefix(tag, suffix) {
+			return len(suffix)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,174 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadStream_SSEAndNDJSON(t *testing.T) {
+	sse := "event: message\ndata: {\"a\":1}\n\n: keep-alive\n\ndata: {\"a\":\ndata: 2}\n\ndata: [DONE]\n\ndata: {\"a\":3}\n\n"
+	var events []string
+	err := readStream(strings.NewReader(sse), func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	})
+	if err != errStreamDone {
+		t.Fatalf("expected errStreamDone, got %v", err)
+	}
+	if len(events) != 2 || events[0] != `{"a":1}` || events[1] != "{\"a\":\n2}" {
+		t.Errorf("unexpected SSE events: %q", events)
+	}
+
+	ndjson := "{\"a\":1}\r\n{\"a\":2}\n"
+	events = nil
+	if err := readStream(strings.NewReader(ndjson), func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	}); err != nil {
+		t.Fatalf("readStream error: %v", err)
+	}
+	if len(events) != 2 || events[1] != `{"a":2}` {
+		t.Errorf("unexpected NDJSON events: %q", events)
+	}
+}
+
+func TestThinkTagSplitter(t *testing.T) {
+	splitter := &thinkTagSplitter{}
+	var content, thinking strings.Builder
+	for _, delta := range []string{"<thi", "nk>plan", "ning</TH", "INK>Hello", " <", "b>world</b>"} {
+		c, th := splitter.Feed(delta)
+		content.WriteString(c)
+		thinking.WriteString(th)
+	}
+	c, th := splitter.Flush()
+	content.WriteString(c)
+	thinking.WriteString(th)
+
+	if content.String() != "Hello <b>world</b>" {
+		t.Errorf("content = %q", content.String())
+	}
+	if thinking.String() != "planning" {
+		t.Errorf("thinking = %q", thinking.String())
+	}
+}
+
+func TestParseStreamEvent_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler StreamHandler
+		data    string
+		want    StreamChunk
+		wantErr bool
+	}{
+		{"openai content", NewOpenAIHandler(), `{"choices":[{"delta":{"content":"Hi"}}]}`, StreamChunk{Content: "Hi"}, false},
+		{"openai usage", NewOpenAIHandler(), `{"choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`, StreamChunk{Usage: TokenUsage{InputTokens: 3, OutputTokens: 2}}, false},
+		{"openai error", NewOpenAIHandler(), `{"error":{"message":"bad","type":"x"}}`, StreamChunk{}, true},
+		{"deepseek reasoning", &DeepSeekHandler{}, `{"choices":[{"delta":{"reasoning_content":"hmm"}}]}`, StreamChunk{Thinking: "hmm"}, false},
+		{"anthropic text", &AnthropicHandler{}, `{"type":"content_block_delta","delta":{"type":"text_delta","text":"Hi"}}`, StreamChunk{Content: "Hi"}, false},
+		{"anthropic thinking", &AnthropicHandler{}, `{"type":"content_block_delta","delta":{"type":"thinking_delta","thinking":"hmm"}}`, StreamChunk{Thinking: "hmm"}, false},
+		{"anthropic start", &AnthropicHandler{}, `{"type":"message_start","message":{"usage":{"input_tokens":12,"output_tokens":1}}}`, StreamChunk{Usage: TokenUsage{InputTokens: 12}}, false},
+		{"anthropic delta", &AnthropicHandler{}, `{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":15}}`, StreamChunk{Usage: TokenUsage{OutputTokens: 15}}, false},
+		{"anthropic stop", &AnthropicHandler{}, `{"type":"message_stop"}`, StreamChunk{Done: true}, false},
+		{"anthropic error", &AnthropicHandler{}, `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`, StreamChunk{}, true},
+		{"gemini parts", NewGeminiHandler(), `{"candidates":[{"content":{"parts":[{"text":"hmm","thought":true},{"text":"Hi"}]}}]}`, StreamChunk{Content: "Hi", Thinking: "hmm"}, false},
+		{"gemini usage", NewGeminiHandler(), `{"candidates":[{"content":{"parts":[{"text":"!"}]}}],"usageMetadata":{"promptTokenCount":4,"candidatesTokenCount":6,"thoughtsTokenCount":2}}`, StreamChunk{Content: "!", Usage: TokenUsage{InputTokens: 4, OutputTokens: 8}}, false},
+		{"gemini safety", NewGeminiHandler(), `{"candidates":[{"content":{"parts":[]},"finishReason":"SAFETY"}]}`, StreamChunk{}, true},
+		{"ollama chat", NewOllamaHandler(), `{"message":{"content":"Hi","thinking":"hmm"},"done":false}`, StreamChunk{Content: "Hi", Thinking: "hmm"}, false},
+		{"ollama generate done", NewOllamaHandler(), `{"response":"","done":true,"prompt_eval_count":7,"eval_count":9}`, StreamChunk{Done: true, Usage: TokenUsage{InputTokens: 7, OutputTokens: 9}}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.handler.ParseStreamEvent([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGeminiFormatStreamEndpoint(t *testing.T) {
+	got := NewGeminiHandler().FormatStreamEndpoint("https://generativelanguage.googleapis.com/v1beta", "gemini-2.0-flash")
+	want := "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:streamGenerateContent?alt=sse"
+	if got != want {
+		t.Errorf("FormatStreamEndpoint = %q, want %q", got, want)
+	}
+}
+
+func TestStreamWithMessages_FallsBackToOpenAI(t *testing.T) {
+	var streamRequested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The loopback endpoint is detected as Ollama, whose /api/generate path doesn't exist here
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		streamRequested = body["stream"] == true && body["stream_options"] != nil
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{`{"reasoning_content":"plan"}`, `{"content":"Hel"}`, `{"content":"lo"}`} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"delta\":%s}]}\n\n", delta)
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":8,\"completion_tokens\":3}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL + "/v1/chat/completions", Model: "test"})
+	ctx, collector := WithUsageCollector(context.Background())
+
+	var chunks []StreamChunk
+	result, err := client.StreamWithMessages(ctx, []map[string]string{{"role": "user", "content": "hi"}}, func(chunk StreamChunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamWithMessages error: %v", err)
+	}
+
+	if !streamRequested {
+		t.Error("expected the request body to enable streaming with usage")
+	}
+	if want := (TokenUsage{InputTokens: 8, OutputTokens: 3}); result.Usage != want || collector.Total() != want {
+		t.Errorf("expected usage %+v, got %+v (collected %+v)", want, result.Usage, collector.Total())
+	}
+	if result.Content != "Hello" || result.Thinking != "plan" || result.FormatUsed != FormatTypeOpenAI {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(chunks) != 4 || chunks[1].Content != "Hel" || !chunks[3].Done {
+		t.Errorf("unexpected chunks: %+v", chunks)
+	}
+}
+
+func TestStreamWithMessages_CallbackAbort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{\"message\":{\"content\":\"one\"},\"done\":false}\n{\"message\":{\"content\":\"two\"},\"done\":true}\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(ClientConfig{Endpoint: server.URL, Model: "llama3"})
+	abort := fmt.Errorf("client went away")
+
+	calls := 0
+	_, err := client.StreamWithMessages(context.Background(), []map[string]string{{"role": "user", "content": "hi"}}, func(chunk StreamChunk) error {
+		calls++
+		return abort
+	})
+	if err != abort {
+		t.Fatalf("expected the callback error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retries after output was delivered, got %d callback calls", calls)
+	}
+}
@@ -0,0 +1,54 @@
+// Package ai provides structured (JSON) output for the supported API formats
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSONSchemaFormat returns a ResponseFormat asking for output matching a JSON schema.
+// It has the shape of OpenAI's response_format; the other formats are adapted from it:
+// Ollama gets the schema itself, DeepSeek JSON mode and Gemini a JSON response MIME type.
+func JSONSchemaFormat(name string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "json_schema",
+		"json_schema": map[string]interface{}{
+			"name":   name,
+			"schema": schema,
+			"strict": true,
+		},
+	}
+}
+
+// responseSchema returns the JSON schema of a response format built with JSONSchemaFormat
+func responseSchema(format map[string]interface{}) (map[string]interface{}, bool) {
+	if format["type"] != "json_schema" {
+		return nil, false
+	}
+	jsonSchema, _ := format["json_schema"].(map[string]interface{})
+	schema, ok := jsonSchema["schema"].(map[string]interface{})
+	return schema, ok
+}
+
+// DecodeJSON decodes the JSON value in a model response into v.
+// Markdown code fences and text around the value, which some models add, are ignored.
+func DecodeJSON(content string, v interface{}) error {
+	content = strings.TrimSpace(content)
+	start := strings.IndexAny(content, "{[")
+	if start < 0 {
+		return fmt.Errorf("no JSON value in response")
+	}
+	closing := "}"
+	if content[start] == '[' {
+		closing = "]"
+	}
+	end := strings.LastIndex(content, closing)
+	if end < start {
+		return fmt.Errorf("unterminated JSON value in response")
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), v); err != nil {
+		return fmt.Errorf("invalid JSON in response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildRequest_JSONSchemaFormat(t *testing.T) {
+	schema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{"topics": map[string]interface{}{"type": "array"}}}
+	config := RequestConfig{
+		Model:          "m",
+		UserPrompt:     "Classify",
+		ResponseFormat: JSONSchemaFormat("classification", schema),
+	}
+
+	cases := []struct {
+		name    string
+		handler FormatHandler
+		want    string
+	}{
+		{"openai", NewOpenAIHandler(), `"response_format":{"json_schema":{"name":"classification","schema":{"properties":{"topics":{"type":"array"}},"type":"object"},"strict":true},"type":"json_schema"}`},
+		{"ollama", NewOllamaHandler(), `"format":{"properties":{"topics":{"type":"array"}},"type":"object"}`},
+		{"deepseek", &DeepSeekHandler{}, `"response_format":{"type":"json_object"}`},
+		{"gemini", NewGeminiHandler(), `"responseMimeType":"application/json"`},
+	}
+	for _, c := range cases {
+		request, err := c.handler.BuildRequest(config)
+		if err != nil {
+			t.Fatalf("%s: BuildRequest error: %v", c.name, err)
+		}
+		if body := marshal(t, request); !strings.Contains(body, c.want) {
+			t.Errorf("%s: expected %s in %s", c.name, c.want, body)
+		}
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	var v struct {
+		Topics []string `json:"topics"`
+	}
+	for _, content := range []string{
+		`{"topics":["go"]}`,
+		"```json\n{\"topics\":[\"go\"]}\n```",
+		`Here you go: {"topics":["go"]} Hope this helps.`,
+	} {
+		v.Topics = nil
+		if err := DecodeJSON(content, &v); err != nil || len(v.Topics) != 1 || v.Topics[0] != "go" {
+			t.Errorf("DecodeJSON(%q) = %+v, %v", content, v, err)
+		}
+	}
+	if err := DecodeJSON("no json here", &v); err == nil {
+		t.Errorf("expected an error without a JSON value")
+	}
+}
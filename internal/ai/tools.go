@@ -0,0 +1,271 @@
+// Package ai provides function/tool calling for the supported API formats
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Tool describes a function the model may call
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  map[string]interface{} // JSON schema of the arguments object
+}
+
+// ToolCall is a call of a tool requested by the model
+type ToolCall struct {
+	ID        string          `json:"id"` // Generated for formats without call IDs (Gemini, Ollama)
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"` // JSON object
+}
+
+// ToolExchange is one round of tool use: the calls the model requested, with the text it
+// returned alongside them, and the result of each call in the order of Calls
+type ToolExchange struct {
+	Content string
+	Calls   []ToolCall
+	Results []string
+}
+
+// RequestWithToolsContext makes a messages request offering tools to the model.
+// The result either has ToolCalls, to be answered with another request that appends
+// the calls and their results to exchanges, or the final Content.
+// Tool calls are only supported by non-streamed requests.
+func (c *Client) RequestWithToolsContext(ctx context.Context, messages []map[string]string, tools []Tool, exchanges []ToolExchange) (ResponseResult, error) {
+	config := RequestConfig{
+		Model:         c.config.Model,
+		Messages:      messages,
+		Temperature:   0.3,
+		MaxTokens:     2048,
+		Tools:         tools,
+		ToolExchanges: exchanges,
+	}
+
+	return c.RequestWithConfigContext(ctx, config)
+}
+
+// toolArguments returns the arguments of a call as a JSON object, "{}" when empty
+func toolArguments(args json.RawMessage) json.RawMessage {
+	if len(args) == 0 || string(args) == "null" {
+		return json.RawMessage("{}")
+	}
+	return args
+}
+
+// generatedCallID returns an ID for the i-th call of a response without call IDs
+func generatedCallID(i int) string {
+	return fmt.Sprintf("call_%d", i)
+}
+
+// toolResult returns the result of the i-th call of an exchange
+func (e ToolExchange) toolResult(i int) string {
+	if i < len(e.Results) {
+		return e.Results[i]
+	}
+	return ""
+}
+
+// appendChatMessages appends messages to the messages of a request as generic JSON objects
+func appendChatMessages(existing interface{}, messages ...map[string]interface{}) []interface{} {
+	var result []interface{}
+	switch m := existing.(type) {
+	case []map[string]string:
+		for _, msg := range m {
+			result = append(result, msg)
+		}
+	case []map[string]interface{}:
+		for _, msg := range m {
+			result = append(result, msg)
+		}
+	case []interface{}:
+		result = append(result, m...)
+	}
+	for _, msg := range messages {
+		result = append(result, msg)
+	}
+	return result
+}
+
+// addOpenAITools adds tools and tool exchanges to an OpenAI-compatible chat completion request.
+// Ollama's /api/chat uses the same shape, with arguments as objects instead of strings.
+func addOpenAITools(request map[string]interface{}, config RequestConfig, argumentsAsString bool) {
+	if len(config.Tools) > 0 {
+		tools := make([]map[string]interface{}, len(config.Tools))
+		for i, tool := range config.Tools {
+			tools[i] = map[string]interface{}{
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":        tool.Name,
+					"description": tool.Description,
+					"parameters":  tool.Parameters,
+				},
+			}
+		}
+		request["tools"] = tools
+	}
+
+	if len(config.ToolExchanges) == 0 {
+		return
+	}
+	var messages []map[string]interface{}
+	for _, exchange := range config.ToolExchanges {
+		calls := make([]map[string]interface{}, len(exchange.Calls))
+		for i, call := range exchange.Calls {
+			var arguments interface{} = toolArguments(call.Arguments)
+			if argumentsAsString {
+				arguments = string(toolArguments(call.Arguments))
+			}
+			calls[i] = map[string]interface{}{
+				"id":   call.ID,
+				"type": "function",
+				"function": map[string]interface{}{
+					"name":      call.Name,
+					"arguments": arguments,
+				},
+			}
+		}
+		messages = append(messages, map[string]interface{}{
+			"role":       "assistant",
+			"content":    exchange.Content,
+			"tool_calls": calls,
+		})
+		for i, call := range exchange.Calls {
+			messages = append(messages, map[string]interface{}{
+				"role":         "tool",
+				"tool_call_id": call.ID,
+				"name":         call.Name,
+				"content":      exchange.toolResult(i),
+			})
+		}
+	}
+	request["messages"] = appendChatMessages(request["messages"], messages...)
+}
+
+// openAIToolCall is a tool call of an OpenAI-compatible chat completion message
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// parseOpenAIToolCalls converts the tool calls of a chat completion message
+func parseOpenAIToolCalls(calls []openAIToolCall) []ToolCall {
+	var result []ToolCall
+	for i, call := range calls {
+		id := call.ID
+		if id == "" {
+			id = generatedCallID(i)
+		}
+		result = append(result, ToolCall{ID: id, Name: call.Function.Name, Arguments: toolArguments(json.RawMessage(call.Function.Arguments))})
+	}
+	return result
+}
+
+// ollamaToolCall is a tool call of an Ollama chat message, with arguments as an object
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// parseOllamaToolCalls converts the tool calls of an Ollama chat message
+func parseOllamaToolCalls(calls []ollamaToolCall) []ToolCall {
+	var result []ToolCall
+	for i, call := range calls {
+		result = append(result, ToolCall{ID: generatedCallID(i), Name: call.Function.Name, Arguments: toolArguments(call.Function.Arguments)})
+	}
+	return result
+}
+
+// addAnthropicTools adds tools and tool exchanges to a Messages API request.
+// Calls are tool_use blocks of an assistant message, answered by tool_result blocks of a user message.
+func addAnthropicTools(request map[string]interface{}, config RequestConfig) {
+	if len(config.Tools) > 0 {
+		tools := make([]map[string]interface{}, len(config.Tools))
+		for i, tool := range config.Tools {
+			tools[i] = map[string]interface{}{
+				"name":         tool.Name,
+				"description":  tool.Description,
+				"input_schema": tool.Parameters,
+			}
+		}
+		request["tools"] = tools
+	}
+
+	if len(config.ToolExchanges) == 0 {
+		return
+	}
+	var messages []map[string]interface{}
+	for _, exchange := range config.ToolExchanges {
+		var content, results []map[string]interface{}
+		if exchange.Content != "" {
+			content = append(content, map[string]interface{}{"type": "text", "text": exchange.Content})
+		}
+		for i, call := range exchange.Calls {
+			content = append(content, map[string]interface{}{
+				"type":  "tool_use",
+				"id":    call.ID,
+				"name":  call.Name,
+				"input": toolArguments(call.Arguments),
+			})
+			results = append(results, map[string]interface{}{
+				"type":        "tool_result",
+				"tool_use_id": call.ID,
+				"content":     exchange.toolResult(i),
+			})
+		}
+		messages = append(messages,
+			map[string]interface{}{"role": "assistant", "content": content},
+			map[string]interface{}{"role": "user", "content": results},
+		)
+	}
+	request["messages"] = appendChatMessages(request["messages"], messages...)
+}
+
+// addGeminiTools adds function declarations and tool exchanges to a Gemini request.
+// Calls are functionCall parts of a model turn, answered by functionResponse parts.
+func addGeminiTools(request map[string]interface{}, config RequestConfig) {
+	if len(config.Tools) > 0 {
+		declarations := make([]map[string]interface{}, len(config.Tools))
+		for i, tool := range config.Tools {
+			declarations[i] = map[string]interface{}{
+				"name":        tool.Name,
+				"description": tool.Description,
+				"parameters":  tool.Parameters,
+			}
+		}
+		request["tools"] = []map[string]interface{}{{"functionDeclarations": declarations}}
+	}
+
+	if len(config.ToolExchanges) == 0 {
+		return
+	}
+	contents, _ := request["contents"].([]map[string]interface{})
+	for _, exchange := range config.ToolExchanges {
+		var calls, responses []map[string]interface{}
+		if exchange.Content != "" {
+			calls = append(calls, map[string]interface{}{"text": exchange.Content})
+		}
+		for i, call := range exchange.Calls {
+			calls = append(calls, map[string]interface{}{
+				"functionCall": map[string]interface{}{"name": call.Name, "args": toolArguments(call.Arguments)},
+			})
+			responses = append(responses, map[string]interface{}{
+				"functionResponse": map[string]interface{}{
+					"name":     call.Name,
+					"response": map[string]interface{}{"content": exchange.toolResult(i)},
+				},
+			})
+		}
+		contents = append(contents,
+			map[string]interface{}{"role": "model", "parts": calls},
+			map[string]interface{}{"role": "user", "parts": responses},
+		)
+	}
+	request["contents"] = contents
+}
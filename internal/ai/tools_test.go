@@ -0,0 +1,123 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testTools = []Tool{{
+	Name:        "mark_read",
+	Description: "Mark articles as read",
+	Parameters: map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"article_ids": map[string]interface{}{"type": "array"}},
+	},
+}}
+
+var testExchanges = []ToolExchange{{
+	Content: "Marking it.",
+	Calls:   []ToolCall{{ID: "call_a", Name: "mark_read", Arguments: json.RawMessage(`{"article_ids":[4]}`)}},
+	Results: []string{`{"updated":1}`},
+}}
+
+func toolConfig() RequestConfig {
+	return RequestConfig{
+		Model:         "m",
+		Messages:      []map[string]string{{"role": "user", "content": "Mark article 4 as read"}},
+		Tools:         testTools,
+		ToolExchanges: testExchanges,
+	}
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	return string(data)
+}
+
+func TestBuildRequest_Tools(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler FormatHandler
+		want    []string
+	}{
+		{"openai", NewOpenAIHandler(), []string{
+			`"tools":[{"function":{"description":"Mark articles as read","name":"mark_read"`,
+			`{"content":"Marking it.","role":"assistant","tool_calls":[{"function":{"arguments":"{\"article_ids\":[4]}","name":"mark_read"},"id":"call_a","type":"function"}]}`,
+			`{"content":"{\"updated\":1}","name":"mark_read","role":"tool","tool_call_id":"call_a"}`,
+		}},
+		{"deepseek", &DeepSeekHandler{}, []string{`"tool_call_id":"call_a"`, `"arguments":"{\"article_ids\":[4]}"`}},
+		{"ollama", NewOllamaHandler(), []string{
+			`"tools":[{"function":`,
+			`"arguments":{"article_ids":[4]}`,
+			`"role":"tool"`,
+		}},
+		{"anthropic", &AnthropicHandler{}, []string{
+			`"tools":[{"description":"Mark articles as read","input_schema":`,
+			`{"content":[{"text":"Marking it.","type":"text"},{"id":"call_a","input":{"article_ids":[4]},"name":"mark_read","type":"tool_use"}],"role":"assistant"}`,
+			`{"content":[{"content":"{\"updated\":1}","tool_use_id":"call_a","type":"tool_result"}],"role":"user"}`,
+		}},
+		{"gemini", NewGeminiHandler(), []string{
+			`"tools":[{"functionDeclarations":[{"description":"Mark articles as read","name":"mark_read"`,
+			`{"parts":[{"text":"Marking it."},{"functionCall":{"args":{"article_ids":[4]},"name":"mark_read"}}],"role":"model"}`,
+			`{"parts":[{"functionResponse":{"name":"mark_read","response":{"content":"{\"updated\":1}"}}}],"role":"user"}`,
+		}},
+	}
+
+	for _, c := range cases {
+		request, err := c.handler.BuildRequest(toolConfig())
+		if err != nil {
+			t.Fatalf("%s: BuildRequest error: %v", c.name, err)
+		}
+		body := marshal(t, request)
+		for _, want := range c.want {
+			if !strings.Contains(body, want) {
+				t.Errorf("%s: expected %s in %s", c.name, want, body)
+			}
+		}
+	}
+}
+
+func TestParseResponse_ToolCalls(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler FormatHandler
+		body    string
+		id      string
+	}{
+		{"openai", NewOpenAIHandler(),
+			`{"choices":[{"message":{"content":null,"tool_calls":[{"id":"c1","type":"function","function":{"name":"mark_read","arguments":"{\"article_ids\":[4]}"}}]}}]}`, "c1"},
+		{"deepseek", &DeepSeekHandler{},
+			`{"choices":[{"message":{"content":"","tool_calls":[{"id":"c1","type":"function","function":{"name":"mark_read","arguments":"{\"article_ids\":[4]}"}}]}}]}`, "c1"},
+		{"ollama", NewOllamaHandler(),
+			`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"mark_read","arguments":{"article_ids":[4]}}}]},"done":true}`, "call_0"},
+		{"anthropic", &AnthropicHandler{},
+			`{"content":[{"type":"tool_use","id":"toolu_1","name":"mark_read","input":{"article_ids":[4]}}],"stop_reason":"tool_use"}`, "toolu_1"},
+		{"gemini", NewGeminiHandler(),
+			`{"candidates":[{"content":{"parts":[{"functionCall":{"name":"mark_read","args":{"article_ids":[4]}}}]},"finishReason":"STOP"}]}`, "call_0"},
+	}
+
+	for _, c := range cases {
+		if err := c.handler.ValidateResponse(200, []byte(c.body)); err != nil {
+			t.Errorf("%s: ValidateResponse error: %v", c.name, err)
+			continue
+		}
+		result, err := c.handler.ParseResponse([]byte(c.body))
+		if err != nil {
+			t.Errorf("%s: ParseResponse error: %v", c.name, err)
+			continue
+		}
+		if len(result.ToolCalls) != 1 {
+			t.Errorf("%s: expected one tool call, got %+v", c.name, result.ToolCalls)
+			continue
+		}
+		call := result.ToolCalls[0]
+		if call.ID != c.id || call.Name != "mark_read" || string(call.Arguments) != `{"article_ids":[4]}` {
+			t.Errorf("%s: unexpected tool call %+v (%s)", c.name, call, call.Arguments)
+		}
+	}
+}
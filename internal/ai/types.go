@@ -29,6 +29,8 @@ type RequestConfig struct {
 	TopP                float64                // Top-p sampling
 	TopK                int                    // Top-k sampling (Gemini/Ollama)
 	Seed                int                    // Seed for reproducible outputs
+	Tools               []Tool                 // Functions the model may call instead of answering
+	ToolExchanges       []ToolExchange         // Earlier tool calls and their results, following Messages
 }
 
 // ResponseResult holds the result from an AI API call
@@ -36,6 +38,24 @@ type ResponseResult struct {
 	Content    string     // The main response content
 	Thinking   string     // Optional thinking/reasoning content (for models that support it)
 	FormatUsed FormatType // Which format was successful
+	Usage      TokenUsage // Token counts reported by the provider, zero if it reported none
+	ToolCalls  []ToolCall // Tools the model wants called, Content may be empty when set
+}
+
+// TokenUsage holds the prompt and completion token counts of an AI call
+type TokenUsage struct {
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+}
+
+// Total returns the sum of input and output tokens
+func (u TokenUsage) Total() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+// IsZero reports whether no token counts are set
+func (u TokenUsage) IsZero() bool {
+	return u.InputTokens == 0 && u.OutputTokens == 0
 }
 
 // FormatHandler defines the interface for handling different API formats
@@ -0,0 +1,100 @@
+// Package ai provides token usage collection for AI calls
+package ai
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// UsageCall describes the token usage of one successful AI call
+type UsageCall struct {
+	Provider string
+	Model    string
+	Format   FormatType
+	Usage    TokenUsage
+}
+
+// UsageCollector gathers the token usage of the AI calls made with a context.
+// It lets callers account for every request made on their behalf, e.g. the
+// several calls of a chunked translation, without threading results through.
+type UsageCollector struct {
+	mu    sync.Mutex
+	calls []UsageCall
+}
+
+type usageCollectorKey struct{}
+
+// WithUsageCollector returns a context whose AI calls are recorded in the returned collector
+func WithUsageCollector(ctx context.Context) (context.Context, *UsageCollector) {
+	collector := &UsageCollector{}
+	return context.WithValue(ctx, usageCollectorKey{}, collector), collector
+}
+
+// usageCollectorFrom returns the collector bound to ctx, or nil
+func usageCollectorFrom(ctx context.Context) *UsageCollector {
+	collector, _ := ctx.Value(usageCollectorKey{}).(*UsageCollector)
+	return collector
+}
+
+// add records a call
+func (c *UsageCollector) add(call UsageCall) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, call)
+}
+
+// Calls returns the recorded calls in the order they completed
+func (c *UsageCollector) Calls() []UsageCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]UsageCall(nil), c.calls...)
+}
+
+// Total returns the summed token usage of all recorded calls
+func (c *UsageCollector) Total() TokenUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var total TokenUsage
+	for _, call := range c.calls {
+		total.InputTokens += call.Usage.InputTokens
+		total.OutputTokens += call.Usage.OutputTokens
+	}
+	return total
+}
+
+// Reported reports whether calls were made and every one of them carried token counts
+func (c *UsageCollector) Reported() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, call := range c.calls {
+		if call.Usage.IsZero() {
+			return false
+		}
+	}
+	return len(c.calls) > 0
+}
+
+// recordUsage adds a successful call's usage to the collector bound to ctx, if any
+func (c *Client) recordUsage(ctx context.Context, result ResponseResult) {
+	if collector := usageCollectorFrom(ctx); collector != nil {
+		collector.add(UsageCall{
+			Provider: ProviderName(c.config.Endpoint),
+			Model:    c.config.Model,
+			Format:   result.FormatUsed,
+			Usage:    result.Usage,
+		})
+	}
+}
+
+// ProviderName names the provider of an endpoint for usage accounting: the detected
+// provider, or the endpoint host for other OpenAI-compatible services
+func ProviderName(endpoint string) string {
+	if provider := DetectAPIProvider(endpoint); provider != "unknown" {
+		return provider
+	}
+	if u, err := url.Parse(endpoint); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "unknown"
+}
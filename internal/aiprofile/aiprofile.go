@@ -0,0 +1,181 @@
+// Package aiprofile routes AI features to named provider profiles with ordered failover.
+//
+// Each AI task (chat, summary, translation, classification) has a route: an ordered list of profiles.
+// Requests go to the first usable profile and fail over to the next one when a profile
+// errors or has reached its usage limit. Tasks without a route use the global AI settings.
+package aiprofile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/config"
+	"MrRSS/internal/database"
+)
+
+// AI tasks that can be routed to profiles
+const (
+	TaskChat           = "chat"
+	TaskSummary        = "summary"
+	TaskTranslation    = "translation"
+	TaskClassification = "classification"
+)
+
+// Tasks lists all routable AI tasks
+var Tasks = []string{TaskChat, TaskSummary, TaskTranslation, TaskClassification}
+
+// ErrLimitReached is returned when every profile routed to a task has reached its usage limit
+var ErrLimitReached = errors.New("all AI profiles have reached their usage limit")
+
+// IsValidTask reports whether task is a routable AI task
+func IsValidTask(task string) bool {
+	for _, t := range Tasks {
+		if t == task {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultProfile returns the profile described by the global AI settings.
+// It has no ID and no usage limit of its own; the global usage limit applies to it.
+func DefaultProfile(db *database.DB) database.AIProfile {
+	apiKey, _ := db.GetEncryptedSetting("ai_api_key")
+	endpoint, _ := db.GetSetting("ai_endpoint")
+	model, _ := db.GetSetting("ai_model")
+	customHeaders, _ := db.GetSetting("ai_custom_headers")
+
+	defaults := config.Get()
+	if endpoint == "" {
+		endpoint = defaults.AIEndpoint
+	}
+	if model == "" {
+		model = defaults.AIModel
+	}
+
+	return database.AIProfile{
+		Name:          "Default",
+		Endpoint:      endpoint,
+		Model:         model,
+		APIKey:        apiKey,
+		CustomHeaders: customHeaders,
+		Enabled:       true,
+	}
+}
+
+// Resolve returns the enabled profiles routed to task, in failover order.
+// Tasks without a route resolve to the default profile.
+func Resolve(db *database.DB, task string) ([]database.AIProfile, error) {
+	ids, err := db.GetAIRoute(task)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []database.AIProfile{DefaultProfile(db)}, nil
+	}
+
+	profiles := make([]database.AIProfile, 0, len(ids))
+	for _, id := range ids {
+		profile, err := db.GetAIProfile(id)
+		if err != nil {
+			log.Printf("Skipping AI profile %d routed to %s: %v", id, task, err)
+			continue
+		}
+		if profile.Enabled {
+			profiles = append(profiles, *profile)
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no enabled AI profile is routed to %s", task)
+	}
+	return profiles, nil
+}
+
+// Run calls fn with each profile routed to task until one succeeds and returns that profile.
+// Profiles that have reached their usage limit are skipped. Failover stops when ctx is done
+// or fn returns an error wrapped with Final.
+func Run(ctx context.Context, db *database.DB, task string, fn func(ctx context.Context, profile database.AIProfile) error) (database.AIProfile, error) {
+	profiles, err := Resolve(db, task)
+	if err != nil {
+		return database.AIProfile{}, err
+	}
+
+	var lastErr error
+	for _, profile := range profiles {
+		if profile.LimitReached() {
+			log.Printf("AI profile %q reached its usage limit, skipping", profile.Name)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return profile, err
+		}
+
+		err := fn(ctx, profile)
+		if err == nil {
+			return profile, nil
+		}
+
+		var final *finalError
+		if errors.As(err, &final) {
+			return profile, final.err
+		}
+		if ctx.Err() != nil {
+			return profile, err
+		}
+
+		log.Printf("AI profile %q failed for %s: %v", profile.Name, task, err)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return database.AIProfile{}, ErrLimitReached
+	}
+	if len(profiles) == 1 {
+		return database.AIProfile{}, lastErr
+	}
+	return database.AIProfile{}, fmt.Errorf("all AI profiles failed: %w", lastErr)
+}
+
+// Final wraps an error returned by a Run callback so that no other profile is tried,
+// e.g. when part of a streamed response has already been delivered
+func Final(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &finalError{err: err}
+}
+
+// finalError marks an error that ends failover
+type finalError struct {
+	err error
+}
+
+func (e *finalError) Error() string { return e.err.Error() }
+
+func (e *finalError) Unwrap() error { return e.err }
+
+// RecordUsage adds tokens to a profile's usage counter.
+// The default profile is covered by the global usage tracker instead.
+func RecordUsage(db *database.DB, profile database.AIProfile, tokens int64) {
+	if profile.ID == 0 {
+		return
+	}
+	if err := db.AddAIProfileUsage(profile.ID, tokens); err != nil {
+		log.Printf("Warning: failed to track usage of AI profile %q: %v", profile.Name, err)
+	}
+}
+
+// ClientConfig returns the AI client configuration for a profile
+func ClientConfig(profile database.AIProfile, timeout time.Duration) ai.ClientConfig {
+	return ai.ClientConfig{
+		APIKey:        profile.APIKey,
+		Endpoint:      profile.Endpoint,
+		Model:         profile.Model,
+		CustomHeaders: profile.CustomHeaders,
+		Timeout:       timeout,
+	}
+}
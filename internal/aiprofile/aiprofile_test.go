@@ -0,0 +1,97 @@
+package aiprofile
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"MrRSS/internal/database"
+)
+
+func setupDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	return db
+}
+
+func TestResolve_DefaultProfile(t *testing.T) {
+	db := setupDB(t)
+	db.SetSetting("ai_endpoint", "http://localhost:11434/api/generate")
+	db.SetSetting("ai_model", "llama3")
+
+	profiles, err := Resolve(db, TaskChat)
+	if err != nil {
+		t.Fatalf("Resolve error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].ID != 0 || profiles[0].Model != "llama3" {
+		t.Errorf("expected the default profile, got %+v", profiles)
+	}
+}
+
+func TestRun_FailsOverInRouteOrder(t *testing.T) {
+	db := setupDB(t)
+	exhausted, _ := db.SaveAIProfile(&database.AIProfile{Name: "Exhausted", UsageLimit: 10, Enabled: true})
+	db.AddAIProfileUsage(exhausted, 10)
+	disabled, _ := db.SaveAIProfile(&database.AIProfile{Name: "Disabled", Enabled: false})
+	broken, _ := db.SaveAIProfile(&database.AIProfile{Name: "Broken", Enabled: true})
+	working, _ := db.SaveAIProfile(&database.AIProfile{Name: "Working", Enabled: true})
+	db.SetAIRoute(TaskTranslation, []int64{exhausted, disabled, broken, working})
+
+	var tried []string
+	profile, err := Run(context.Background(), db, TaskTranslation, func(ctx context.Context, p database.AIProfile) error {
+		tried = append(tried, p.Name)
+		if p.Name == "Broken" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run error: %v", err)
+	}
+	if profile.ID != working {
+		t.Errorf("expected the working profile, got %q", profile.Name)
+	}
+	if len(tried) != 2 || tried[0] != "Broken" || tried[1] != "Working" {
+		t.Errorf("unexpected profiles tried: %v", tried)
+	}
+
+	RecordUsage(db, profile, 25)
+	if p, _ := db.GetAIProfile(working); p.UsageTokens != 25 {
+		t.Errorf("expected usage to be recorded, got %d", p.UsageTokens)
+	}
+}
+
+func TestRun_LimitReachedAndFinal(t *testing.T) {
+	db := setupDB(t)
+	first, _ := db.SaveAIProfile(&database.AIProfile{Name: "First", UsageLimit: 1, Enabled: true})
+	db.AddAIProfileUsage(first, 1)
+	db.SetAIRoute(TaskChat, []int64{first})
+
+	if _, err := Run(context.Background(), db, TaskChat, func(ctx context.Context, p database.AIProfile) error {
+		t.Error("profiles over their limit should not be called")
+		return nil
+	}); err != ErrLimitReached {
+		t.Errorf("expected ErrLimitReached, got %v", err)
+	}
+
+	second, _ := db.SaveAIProfile(&database.AIProfile{Name: "Second", Enabled: true})
+	third, _ := db.SaveAIProfile(&database.AIProfile{Name: "Third", Enabled: true})
+	db.SetAIRoute(TaskChat, []int64{second, third})
+
+	streamErr := errors.New("stream interrupted")
+	calls := 0
+	_, err := Run(context.Background(), db, TaskChat, func(ctx context.Context, p database.AIProfile) error {
+		calls++
+		return Final(streamErr)
+	})
+	if err != streamErr || calls != 1 {
+		t.Errorf("expected failover to stop on a final error, got %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,273 @@
+// Package aitasks translates and summarizes with the AI profiles routed to each task, trying
+// them in failover order and recording the usage of the profile that succeeded. It is shared by
+// the HTTP handlers and the background jobs.
+//
+// Translations are cached apart for each model and prompt template. When every profile fails
+// or has reached its usage limit, translations requested by the client fall back to Google
+// Translate if the ai_translation_google_fallback setting is enabled; otherwise the failover
+// error is returned.
+package aitasks
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/prompts"
+	"MrRSS/internal/summary"
+	"MrRSS/internal/translation"
+)
+
+const (
+	// ProviderAI is the provider of translations made by an AI profile
+	ProviderAI = "ai"
+	// ProviderGoogle is the provider of translations made by the Google Translate fallback
+	ProviderGoogle = "google"
+)
+
+// Runner runs AI tasks
+type Runner struct {
+	db             *database.DB
+	tracker        *aiusage.Tracker
+	googleFallback bool // Fall back to Google Translate when enabled in the settings
+}
+
+// Outcome describes how a translation was made
+type Outcome struct {
+	Provider     string // ProviderAI or ProviderGoogle
+	LimitReached bool   // An AI usage limit caused the fallback to Google Translate
+}
+
+// NewRunner creates a runner recording AI usage with tracker. Failed translations return the
+// failover error.
+func NewRunner(db *database.DB, tracker *aiusage.Tracker) *Runner {
+	return &Runner{db: db, tracker: tracker}
+}
+
+// WithGoogleFallback returns a runner whose failed translations fall back to Google Translate
+// when the ai_translation_google_fallback setting is enabled
+func (r *Runner) WithGoogleFallback() *Runner {
+	fallback := *r
+	fallback.googleFallback = true
+	return &fallback
+}
+
+// CacheProvider returns the translation cache provider of the translations made by a profile,
+// following the prompt template when it is set
+func CacheProvider(profile database.AIProfile, prompt *prompts.Prompt) string {
+	return prompt.CacheProvider(ProviderAI + ":" + profile.Model)
+}
+
+// Translate translates text, following the prompt template when it is set
+func (r *Runner) Translate(ctx context.Context, text, targetLang string, prompt *prompts.Prompt) (string, Outcome, error) {
+	var translated string
+	outcome, err := r.failover(ctx, func() error {
+		return r.run(ctx, aiprofile.TaskTranslation, text, func(ctx context.Context, profile database.AIProfile) (string, error) {
+			translator := r.cachedTranslator(profile, prompt, targetLang)
+
+			// Use markdown-preserving translation for better list structure
+			var err error
+			translated, err = translation.TranslateMarkdownAIPrompt(text, translation.BindContext(ctx, translator), targetLang)
+			return translated, err
+		})
+	}, func(google translation.Translator) error {
+		var err error
+		translated, err = translation.TranslateMarkdownPreservingStructure(text, google, targetLang)
+		return err
+	})
+	return translated, outcome, err
+}
+
+// TranslateBatch translates texts in as few requests as possible, following the prompt template
+// when it is set
+func (r *Runner) TranslateBatch(ctx context.Context, texts []string, targetLang string, prompt *prompts.Prompt) ([]string, Outcome, error) {
+	var translated []string
+	outcome, err := r.failover(ctx, func() error {
+		return r.run(ctx, aiprofile.TaskTranslation, strings.Join(texts, "\n"), func(ctx context.Context, profile database.AIProfile) (string, error) {
+			var err error
+			if translated, err = r.cachedTranslator(profile, prompt, targetLang).TranslateBatch(ctx, texts, targetLang); err != nil {
+				return "", err
+			}
+			return strings.Join(translated, "\n"), nil
+		})
+	}, func(google translation.Translator) error {
+		var err error
+		translated, err = translation.TranslateBatch(ctx, google, texts, targetLang)
+		return err
+	})
+	return translated, outcome, err
+}
+
+// TranslateHTML translates an article's body segment by segment, following the prompt template
+// when it is set. With refresh, cached segments are translated again.
+func (r *Runner) TranslateHTML(ctx context.Context, content, targetLang string, refresh bool, prompt *prompts.Prompt) (*translation.ArticleTranslation, Outcome, error) {
+	var result *translation.ArticleTranslation
+	outcome, err := r.failover(ctx, func() error {
+		return r.run(ctx, aiprofile.TaskTranslation, content, func(ctx context.Context, profile database.AIProfile) (string, error) {
+			aiTranslator := r.newTranslator(profile, prompt, targetLang)
+			if prompt != nil {
+				// The template's instructions come on top of the segment markers' rules
+				aiTranslator.SetSystemPrompt(aiTranslator.SystemPrompt + "\n\n" + translation.SegmentSystemPrompt)
+			} else if aiTranslator.SystemPrompt == "" {
+				aiTranslator.SetSystemPrompt(translation.SegmentSystemPrompt)
+			}
+			segmentTranslator := translation.NewSegmentTranslator(translation.WithGlossary(aiTranslator, r.db), r.db, CacheProvider(profile, prompt))
+			segmentTranslator.SetRefresh(refresh)
+
+			var err error
+			if result, err = segmentTranslator.TranslateHTML(ctx, content, targetLang); err != nil {
+				return "", err
+			}
+			return result.Content, nil
+		})
+	}, func(google translation.Translator) error {
+		segmentTranslator := translation.NewSegmentTranslator(google, r.db, ProviderGoogle)
+		segmentTranslator.SetRefresh(refresh)
+
+		var err error
+		result, err = segmentTranslator.TranslateHTML(ctx, content, targetLang)
+		return err
+	})
+	return result, outcome, err
+}
+
+// Summarize summarizes an article's content in a style
+func (r *Runner) Summarize(ctx context.Context, articleID int64, content string, length summary.SummaryLength, style summary.SummaryStyle) (summary.SummaryResult, error) {
+	var result summary.SummaryResult
+	err := r.run(ctx, aiprofile.TaskSummary, content, func(ctx context.Context, profile database.AIProfile) (string, error) {
+		var err error
+		result, err = r.newSummarizer(profile, articleID, style).SummarizeWithContext(ctx, content, length)
+		return result.Summary, err
+	})
+	if err != nil {
+		return summary.SummaryResult{}, err
+	}
+	_ = r.db.IncrementStat("ai_summary")
+	return result, nil
+}
+
+// SummarizeStream summarizes an article's content like Summarize, passing the content and
+// thinking deltas to onChunk as they arrive. Once output has started, failover stops.
+func (r *Runner) SummarizeStream(ctx context.Context, articleID int64, content string, length summary.SummaryLength, style summary.SummaryStyle, onChunk ai.StreamCallback) (summary.SummaryResult, error) {
+	var result summary.SummaryResult
+	started := false
+	err := r.run(ctx, aiprofile.TaskSummary, content, func(ctx context.Context, profile database.AIProfile) (string, error) {
+		var err error
+		result, err = r.newSummarizer(profile, articleID, style).SummarizeStream(ctx, content, length, func(chunk ai.StreamChunk) error {
+			if chunk.Done {
+				return nil
+			}
+			started = true
+			return onChunk(chunk)
+		})
+		if err != nil && started {
+			// Output has started, another profile can't continue it
+			return "", aiprofile.Final(err)
+		}
+		return result.Summary, err
+	})
+	if err != nil {
+		return summary.SummaryResult{}, err
+	}
+	_ = r.db.IncrementStat("ai_summary")
+	return result, nil
+}
+
+// failover runs translateAI and, when it fails and the Google Translate fallback is enabled,
+// translateGoogle
+func (r *Runner) failover(ctx context.Context, translateAI func() error, translateGoogle func(google translation.Translator) error) (Outcome, error) {
+	err := translateAI()
+	if err == nil {
+		return Outcome{Provider: ProviderAI}, nil
+	}
+	if ctx.Err() != nil || !r.googleFallback || !r.settingEnabled("ai_translation_google_fallback") {
+		return Outcome{}, err
+	}
+
+	log.Printf("AI translation failed, falling back to Google Translate: %v", err)
+	outcome := Outcome{Provider: ProviderGoogle, LimitReached: errors.Is(err, aiprofile.ErrLimitReached)}
+	google := translation.WithGlossary(translation.NewGoogleFreeTranslatorWithDB(r.db), r.db)
+	return outcome, translateGoogle(google)
+}
+
+// run calls fn with the profiles routed to task in failover order, after waiting for the rate
+// limit, and records the usage of the profile that succeeded. fn returns its output, which
+// estimates the usage with input when the provider reports none.
+func (r *Runner) run(ctx context.Context, task, input string, fn func(ctx context.Context, profile database.AIProfile) (string, error)) error {
+	if r.tracker.IsFeatureLimitReached(task) {
+		return aiprofile.ErrLimitReached
+	}
+	r.tracker.WaitForRateLimit()
+
+	ctx, collector := ai.WithUsageCollector(ctx)
+	var output string
+	profile, err := aiprofile.Run(ctx, r.db, task, func(ctx context.Context, profile database.AIProfile) error {
+		var err error
+		output, err = fn(ctx, profile)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Cached results made no AI call and are not counted
+	estimate := ai.TokenUsage{InputTokens: aiusage.EstimateTokens(input), OutputTokens: aiusage.EstimateTokens(output)}
+	tokens := r.tracker.RecordCalls(task, profile.Name, collector.Calls(), estimate)
+	aiprofile.RecordUsage(r.db, profile, tokens)
+	return nil
+}
+
+// cachedTranslator creates a caching AI translator for a profile, applying the glossary
+func (r *Runner) cachedTranslator(profile database.AIProfile, prompt *prompts.Prompt, targetLang string) *translation.CachedTranslator {
+	translator := translation.WithGlossary(r.newTranslator(profile, prompt, targetLang), r.db)
+	return translation.NewCachedTranslator(translator, r.db, CacheProvider(profile, prompt))
+}
+
+// newTranslator creates an AI translator for a profile, instructed by the translation prompt
+// template when it is set and by the global translation prompt otherwise
+func (r *Runner) newTranslator(profile database.AIProfile, prompt *prompts.Prompt, targetLang string) *translation.AITranslator {
+	aiTranslator := translation.NewAITranslatorWithDB(profile.APIKey, profile.Endpoint, profile.Model, r.db)
+	if prompt != nil {
+		aiTranslator.SetSystemPrompt(prompt.Render(targetLang))
+	} else if systemPrompt := r.setting("ai_translation_prompt"); systemPrompt != "" {
+		aiTranslator.SetSystemPrompt(systemPrompt)
+	}
+	if profile.CustomHeaders != "" {
+		aiTranslator.SetCustomHeaders(profile.CustomHeaders)
+	}
+	return aiTranslator
+}
+
+// newSummarizer creates an AI summarizer for a profile, requesting an article's summary in a
+// style with the summary prompt template applying to it if there is one
+func (r *Runner) newSummarizer(profile database.AIProfile, articleID int64, style summary.SummaryStyle) *summary.AISummarizer {
+	aiSummarizer := summary.NewAISummarizerWithDB(profile.APIKey, profile.Endpoint, profile.Model, r.db)
+	if systemPrompt := r.setting("ai_summary_prompt"); systemPrompt != "" {
+		aiSummarizer.SetSystemPrompt(systemPrompt)
+	}
+	if profile.CustomHeaders != "" {
+		aiSummarizer.SetCustomHeaders(profile.CustomHeaders)
+	}
+	if language := r.setting("language"); language != "" {
+		aiSummarizer.SetLanguage(language)
+	}
+	if prompt := prompts.ForArticle(r.db, articleID, database.PromptKindSummary); prompt != nil {
+		aiSummarizer.SetUserPrompt(prompt.SummaryPrompt(aiSummarizer.Language))
+	}
+	aiSummarizer.SetStyle(style)
+	return aiSummarizer
+}
+
+func (r *Runner) setting(key string) string {
+	value, _ := r.db.GetSetting(key)
+	return value
+}
+
+func (r *Runner) settingEnabled(key string) bool {
+	return r.setting(key) == "true"
+}
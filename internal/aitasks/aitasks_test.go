@@ -0,0 +1,90 @@
+package aitasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+)
+
+func setupDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	return db
+}
+
+func TestTranslate_CachesPerModel(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": map[string]string{"role": "assistant", "content": "Bonjour " + body.Model},
+			"done":    true,
+		})
+	}))
+	defer server.Close()
+
+	db := setupDB(t)
+	first, _ := db.SaveAIProfile(&database.AIProfile{Name: "First", Endpoint: server.URL, Model: "first", Enabled: true})
+	second, _ := db.SaveAIProfile(&database.AIProfile{Name: "Second", Endpoint: server.URL, Model: "second", Enabled: true})
+	runner := NewRunner(db, aiusage.NewTracker(db))
+
+	translate := func(route int64) string {
+		t.Helper()
+		db.SetAIRoute(aiprofile.TaskTranslation, []int64{route})
+		translated, outcome, err := runner.Translate(context.Background(), "Hello", "fr", nil)
+		if err != nil {
+			t.Fatalf("Translate error: %v", err)
+		}
+		if outcome.Provider != ProviderAI {
+			t.Errorf("expected an AI translation, got %+v", outcome)
+		}
+		return translated
+	}
+
+	if got := translate(first); got != "Bonjour first" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+	if got := translate(first); got != "Bonjour first" || atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("expected the cached translation, got %q after %d requests", got, requests)
+	}
+	// Another model doesn't reuse the first model's translation
+	if got := translate(second); got != "Bonjour second" {
+		t.Errorf("unexpected translation of the second model: %q", got)
+	}
+}
+
+func TestTranslate_GoogleFallbackDisabled(t *testing.T) {
+	db := setupDB(t)
+	exhausted, _ := db.SaveAIProfile(&database.AIProfile{Name: "Exhausted", UsageLimit: 10, Enabled: true})
+	db.AddAIProfileUsage(exhausted, 10)
+	db.SetAIRoute(aiprofile.TaskTranslation, []int64{exhausted})
+	db.SetSetting("ai_translation_google_fallback", "false")
+
+	runner := NewRunner(db, aiusage.NewTracker(db)).WithGoogleFallback()
+	translated, outcome, err := runner.Translate(context.Background(), "Hello", "fr", nil)
+	if !errors.Is(err, aiprofile.ErrLimitReached) {
+		t.Fatalf("expected the failover error, got %q, %v", translated, err)
+	}
+	if outcome.Provider != "" || outcome.LimitReached {
+		t.Errorf("expected no fallback, got %+v", outcome)
+	}
+}
@@ -0,0 +1,176 @@
+package aiusage
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/database"
+	"MrRSS/internal/metrics"
+)
+
+// Usage describes the token usage of one AI call (or one feature request) to record.
+type Usage struct {
+	Feature      string // "chat", "summary", "translation"
+	Provider     string
+	Model        string
+	Profile      string
+	InputTokens  int64
+	OutputTokens int64
+	Estimated    bool // Token counts are heuristic, the provider reported none
+}
+
+// BudgetStatus is a budget together with the usage of its current period.
+type BudgetStatus struct {
+	database.AIBudget
+	UsedTokens int64   `json:"used_tokens"`
+	UsedCost   float64 `json:"used_cost"`
+	Exceeded   bool    `json:"exceeded"`
+}
+
+// Record adds usage to the global usage counter, the usage ledger and per-feature metrics.
+func (t *Tracker) Record(u Usage) {
+	tokens := u.InputTokens + u.OutputTokens
+	metrics.AIRequestsTotal.Inc(u.Feature)
+	metrics.AITokensTotal.Add(float64(tokens), u.Feature, strconv.FormatBool(u.Estimated))
+
+	if err := t.AddUsage(tokens); err != nil {
+		log.Printf("Warning: failed to track AI usage: %v", err)
+	}
+
+	if t.ledger == nil {
+		return
+	}
+	record := &database.AIUsageRecord{
+		Feature:      u.Feature,
+		Provider:     u.Provider,
+		Model:        u.Model,
+		Profile:      u.Profile,
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		Estimated:    u.Estimated,
+		Cost:         EstimateCost(u.Provider, u.Model, u.InputTokens, u.OutputTokens),
+	}
+	if err := t.ledger.AddAIUsageRecord(record); err != nil {
+		log.Printf("Warning: failed to record AI usage: %v", err)
+	}
+}
+
+// RecordCalls records the AI calls made for one feature request and returns the tokens recorded.
+// Calls are recorded per provider and model with the token counts the provider reported.
+// If any call reported none, estimate is recorded instead, attributed to the last call.
+// Nothing is recorded when no call was made, e.g. for cached results.
+func (t *Tracker) RecordCalls(feature, profile string, calls []ai.UsageCall, estimate ai.TokenUsage) int64 {
+	if len(calls) == 0 {
+		return 0
+	}
+
+	reported := true
+	for _, call := range calls {
+		if call.Usage.IsZero() {
+			reported = false
+			break
+		}
+	}
+	if !reported {
+		last := calls[len(calls)-1]
+		t.Record(Usage{
+			Feature:      feature,
+			Provider:     last.Provider,
+			Model:        last.Model,
+			Profile:      profile,
+			InputTokens:  estimate.InputTokens,
+			OutputTokens: estimate.OutputTokens,
+			Estimated:    true,
+		})
+		return estimate.Total()
+	}
+
+	// Group calls by provider and model, keeping the order they were made in
+	type key struct{ provider, model string }
+	var order []key
+	grouped := make(map[key]*ai.TokenUsage)
+	for _, call := range calls {
+		k := key{call.Provider, call.Model}
+		if grouped[k] == nil {
+			grouped[k] = &ai.TokenUsage{}
+			order = append(order, k)
+		}
+		grouped[k].InputTokens += call.Usage.InputTokens
+		grouped[k].OutputTokens += call.Usage.OutputTokens
+	}
+
+	var total int64
+	for _, k := range order {
+		usage := grouped[k]
+		t.Record(Usage{
+			Feature:      feature,
+			Provider:     k.provider,
+			Model:        k.model,
+			Profile:      profile,
+			InputTokens:  usage.InputTokens,
+			OutputTokens: usage.OutputTokens,
+		})
+		total += usage.Total()
+	}
+	return total
+}
+
+// IsFeatureLimitReached checks the global usage limit and the daily and monthly budgets
+// that apply to a feature (its own and those covering all features).
+func (t *Tracker) IsFeatureLimitReached(feature string) bool {
+	if t.IsLimitReached() {
+		return true
+	}
+
+	statuses, err := t.BudgetStatuses()
+	if err != nil {
+		log.Printf("Warning: failed to check AI budgets: %v", err)
+		return false
+	}
+	for _, status := range statuses {
+		if status.Exceeded && (status.Feature == feature || status.Feature == database.AIBudgetAllFeatures) {
+			log.Printf("AI %s budget for %s exceeded", status.Period, status.Feature)
+			return true
+		}
+	}
+	return false
+}
+
+// BudgetStatuses returns all budgets with the usage of their current period.
+func (t *Tracker) BudgetStatuses() ([]BudgetStatus, error) {
+	if t.ledger == nil {
+		return []BudgetStatus{}, nil
+	}
+
+	budgets, err := t.ledger.GetAIBudgets()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	statuses := make([]BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		tokens, cost, err := t.ledger.GetAIUsageTotals(budget.Feature, PeriodStart(budget.Period, now))
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, BudgetStatus{
+			AIBudget:   budget,
+			UsedTokens: tokens,
+			UsedCost:   cost,
+			Exceeded: (budget.MaxTokens > 0 && tokens >= budget.MaxTokens) ||
+				(budget.MaxCost > 0 && cost >= budget.MaxCost),
+		})
+	}
+	return statuses, nil
+}
+
+// PeriodStart returns the first day (YYYY-MM-DD) of the budget period containing now.
+func PeriodStart(period string, now time.Time) string {
+	if period == database.AIBudgetMonthly {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	return now.Format("2006-01-02")
+}
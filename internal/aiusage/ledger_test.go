@@ -0,0 +1,105 @@
+package aiusage
+
+import (
+	"math"
+	"testing"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/database"
+	"MrRSS/internal/metrics"
+)
+
+func setupTracker(t *testing.T) (*Tracker, *database.DB) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	return NewTracker(db), db
+}
+
+func TestRecordCalls_ReportedUsage(t *testing.T) {
+	tracker, db := setupTracker(t)
+
+	calls := []ai.UsageCall{
+		{Provider: "openai", Model: "gpt-4o-mini", Usage: ai.TokenUsage{InputTokens: 1000000, OutputTokens: 0}},
+		{Provider: "openai", Model: "gpt-4o-mini", Usage: ai.TokenUsage{InputTokens: 0, OutputTokens: 1000000}},
+	}
+	before := metrics.AITokensTotal.Value("translation", "false")
+	tokens := tracker.RecordCalls("translation", "Default", calls, ai.TokenUsage{InputTokens: 1, OutputTokens: 1})
+	if tokens != 2000000 {
+		t.Errorf("expected reported tokens to be recorded, got %d", tokens)
+	}
+	if counted := metrics.AITokensTotal.Value("translation", "false") - before; counted != 2000000 {
+		t.Errorf("expected the reported tokens to be counted as not estimated, got %v", counted)
+	}
+
+	breakdown, err := db.GetAIUsageBreakdown("", "")
+	if err != nil {
+		t.Fatalf("GetAIUsageBreakdown error: %v", err)
+	}
+	if len(breakdown) != 1 || breakdown[0].Calls != 1 || math.Abs(breakdown[0].Cost-0.75) > 1e-9 {
+		t.Errorf("expected one grouped record costing $0.75, got %+v", breakdown)
+	}
+	if usage, _ := tracker.GetCurrentUsage(); usage != 2000000 {
+		t.Errorf("expected the global counter to be updated, got %d", usage)
+	}
+}
+
+func TestRecordCalls_EstimateAndCacheHit(t *testing.T) {
+	tracker, db := setupTracker(t)
+
+	if tokens := tracker.RecordCalls("summary", "Default", nil, ai.TokenUsage{InputTokens: 5}); tokens != 0 {
+		t.Errorf("expected nothing to be recorded without calls, got %d", tokens)
+	}
+
+	calls := []ai.UsageCall{{Provider: "ollama", Model: "llama3"}}
+	before := metrics.AITokensTotal.Value("summary", "true")
+	if tokens := tracker.RecordCalls("summary", "Local", calls, ai.TokenUsage{InputTokens: 40, OutputTokens: 10}); tokens != 50 {
+		t.Errorf("expected the estimate to be recorded, got %d", tokens)
+	}
+	if counted := metrics.AITokensTotal.Value("summary", "true") - before; counted != 50 {
+		t.Errorf("expected the estimate to be counted as estimated, got %v", counted)
+	}
+
+	var estimated bool
+	var profile string
+	if err := db.QueryRow(`SELECT estimated, profile FROM ai_usage_ledger`).Scan(&estimated, &profile); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if !estimated || profile != "Local" {
+		t.Errorf("expected an estimated record for profile Local, got estimated=%v profile=%q", estimated, profile)
+	}
+}
+
+func TestIsFeatureLimitReached_Budgets(t *testing.T) {
+	tracker, db := setupTracker(t)
+	db.SetSetting("ai_usage_limit", "0")
+	db.SetAIBudget(database.AIBudget{Feature: database.AIBudgetAllFeatures, Period: database.AIBudgetMonthly, MaxCost: 1})
+
+	tracker.Record(Usage{Feature: "chat", Provider: "openai", Model: "gpt-4o", InputTokens: 200000, OutputTokens: 40000})
+	if tracker.IsFeatureLimitReached("translation") {
+		t.Fatal("budget should not be exceeded yet")
+	}
+
+	tracker.Record(Usage{Feature: "chat", Provider: "openai", Model: "gpt-4o", InputTokens: 200000, OutputTokens: 20000})
+	if !tracker.IsFeatureLimitReached("translation") {
+		t.Error("expected the monthly budget for all features to be exceeded")
+	}
+}
+
+func TestLookupPrice(t *testing.T) {
+	if price, ok := LookupPrice("openai", "openai/gpt-4o-mini-2024-07-18"); !ok || price.Input != 0.15 {
+		t.Errorf("expected gpt-4o-mini pricing, got %+v %v", price, ok)
+	}
+	if price, ok := LookupPrice("ollama", "gpt-4o"); !ok || price.Input != 0 {
+		t.Errorf("expected local models to be free, got %+v", price)
+	}
+	if _, ok := LookupPrice("example.com", "my-model"); ok {
+		t.Error("expected unknown models to have no price")
+	}
+}
@@ -0,0 +1,74 @@
+package aiusage
+
+import "strings"
+
+// ModelPrice is the price of a model in USD per million tokens
+type ModelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// modelPrices lists list prices of common hosted models, matched by model name prefix.
+// Prices change over time; costs derived from them are estimates.
+var modelPrices = map[string]ModelPrice{
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.60},
+	"gpt-4o":            {Input: 2.50, Output: 10.00},
+	"gpt-4.1-nano":      {Input: 0.10, Output: 0.40},
+	"gpt-4.1-mini":      {Input: 0.40, Output: 1.60},
+	"gpt-4.1":           {Input: 2.00, Output: 8.00},
+	"gpt-4-turbo":       {Input: 10.00, Output: 30.00},
+	"gpt-3.5-turbo":     {Input: 0.50, Output: 1.50},
+	"o1-mini":           {Input: 1.10, Output: 4.40},
+	"o1":                {Input: 15.00, Output: 60.00},
+	"o3-mini":           {Input: 1.10, Output: 4.40},
+	"o4-mini":           {Input: 1.10, Output: 4.40},
+	"o3":                {Input: 2.00, Output: 8.00},
+	"deepseek-chat":     {Input: 0.27, Output: 1.10},
+	"deepseek-reasoner": {Input: 0.55, Output: 2.19},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25},
+	"claude-3-5-haiku":  {Input: 0.80, Output: 4.00},
+	"claude-3-5-sonnet": {Input: 3.00, Output: 15.00},
+	"claude-3-7-sonnet": {Input: 3.00, Output: 15.00},
+	"claude-sonnet-4":   {Input: 3.00, Output: 15.00},
+	"claude-3-opus":     {Input: 15.00, Output: 75.00},
+	"claude-opus-4":     {Input: 15.00, Output: 75.00},
+	"gemini-1.5-flash":  {Input: 0.075, Output: 0.30},
+	"gemini-1.5-pro":    {Input: 1.25, Output: 5.00},
+	"gemini-2.0-flash":  {Input: 0.10, Output: 0.40},
+	"gemini-2.5-flash":  {Input: 0.30, Output: 2.50},
+	"gemini-2.5-pro":    {Input: 1.25, Output: 10.00},
+}
+
+// LookupPrice returns the price of a model, matching the longest known name prefix.
+// Local providers are free. The second result is false for unknown models.
+func LookupPrice(provider, model string) (ModelPrice, bool) {
+	if provider == "ollama" {
+		return ModelPrice{}, true
+	}
+
+	// Strip vendor prefixes used by routers, e.g. "openai/gpt-4o"
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	best := ""
+	for prefix := range modelPrices {
+		if strings.HasPrefix(name, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return modelPrices[best], true
+}
+
+// EstimateCost returns the estimated cost in USD of a call, or 0 for unknown models
+func EstimateCost(provider, model string, inputTokens, outputTokens int64) float64 {
+	price, ok := LookupPrice(provider, model)
+	if !ok {
+		return 0
+	}
+	return (float64(inputTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6
+}
@@ -2,11 +2,12 @@
 package aiusage
 
 import (
-	"log"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"MrRSS/internal/database"
 )
 
 // SettingsProvider is an interface for retrieving and storing settings.
@@ -15,18 +16,29 @@ type SettingsProvider interface {
 	SetSetting(key, value string) error
 }
 
+// Ledger is implemented by stores that keep a per-call usage ledger and budgets.
+type Ledger interface {
+	AddAIUsageRecord(record *database.AIUsageRecord) error
+	GetAIUsageTotals(feature, startDate string) (int64, float64, error)
+	GetAIBudgets() ([]database.AIBudget, error)
+}
+
 // Tracker tracks AI usage (tokens) and enforces rate limits.
 type Tracker struct {
 	settings    SettingsProvider
+	ledger      Ledger // nil if the settings provider keeps no ledger
 	mu          sync.RWMutex
 	lastRequest time.Time
 	minInterval time.Duration // Minimum interval between AI requests
 }
 
 // NewTracker creates a new AI usage tracker.
+// If settings also implements Ledger, every call is recorded in the ledger and budgets are enforced.
 func NewTracker(settings SettingsProvider) *Tracker {
+	ledger, _ := settings.(Ledger)
 	return &Tracker{
 		settings:    settings,
+		ledger:      ledger,
 		minInterval: 500 * time.Millisecond, // Default: max 2 requests per second
 	}
 }
@@ -249,26 +261,27 @@ func isCJK(r rune) bool {
 	return false
 }
 
-// TrackTranslation tracks token usage for a translation operation.
+// TrackTranslation tracks estimated token usage for a translation operation.
 func (t *Tracker) TrackTranslation(sourceText, translatedText string) {
-	// Estimate tokens for both input and output
-	inputTokens := EstimateTokens(sourceText)
-	outputTokens := EstimateTokens(translatedText)
-
-	totalTokens := inputTokens + outputTokens
-	if err := t.AddUsage(totalTokens); err != nil {
-		log.Printf("Warning: failed to track AI usage: %v", err)
-	}
+	t.Record(Usage{
+		Feature:      "translation",
+		InputTokens:  EstimateTokens(sourceText),
+		OutputTokens: EstimateTokens(translatedText),
+		Estimated:    true,
+	})
 }
 
-// TrackSummary tracks token usage for a summarization operation.
+// TrackSummary tracks estimated token usage for a summarization operation.
 func (t *Tracker) TrackSummary(content, summary string) {
-	// Estimate tokens for both input and output
-	inputTokens := EstimateTokens(content)
-	outputTokens := EstimateTokens(summary)
+	t.Record(Usage{
+		Feature:      "summary",
+		InputTokens:  EstimateTokens(content),
+		OutputTokens: EstimateTokens(summary),
+		Estimated:    true,
+	})
+}
 
-	totalTokens := inputTokens + outputTokens
-	if err := t.AddUsage(totalTokens); err != nil {
-		log.Printf("Warning: failed to track AI usage: %v", err)
-	}
+// TrackChat tracks token usage for a chat request whose tokens were already estimated.
+func (t *Tracker) TrackChat(tokens int64) {
+	t.Record(Usage{Feature: "chat", InputTokens: tokens, Estimated: true})
 }
@@ -0,0 +1,404 @@
+// Package classify sorts new articles into the user's topics with the configured AI model.
+//
+// Articles are sent to the model in small batches with a JSON schema for structured output.
+// For each article the model picks the matching topics, names the entities it is about and
+// writes a short line on why it matters. Results are stored as article tags that rules and
+// filters match on. Articles with the same text reuse an earlier result, and batches stop
+// once the usage limit or a classification budget is reached.
+package classify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/semantic"
+	"MrRSS/internal/utils"
+)
+
+// Feature is the name classification usage is recorded under in the usage ledger and budgets
+const Feature = aiprofile.TaskClassification
+
+const (
+	// batchSize is the number of articles classified per request
+	batchSize = 8
+	// maxArticlesPerRun bounds the articles classified after one feed refresh, newest first
+	maxArticlesPerRun = 40
+	// maxTextChars caps the text sent per article
+	maxTextChars = 1500
+	// maxEntities caps the entities kept per article
+	maxEntities = 8
+	// maxWhyChars caps the "why it matters" line
+	maxWhyChars = 240
+	// requestTimeout is the timeout of one classification request
+	requestTimeout = 90 * time.Second
+)
+
+const systemPrompt = `You classify news articles for a feed reader.
+For each numbered article, return:
+- topics: the topics from the given list the article is about, or none. Use the topic names exactly as listed.
+- entities: up to 8 named people, organizations, products or places the article is mainly about.
+- why_it_matters: one short sentence on why the article matters to a reader, in the article's language.
+Return only JSON matching the schema, with one entry per article and its number as id.`
+
+// Requester sends a request to an AI model, see ai.Client.RequestWithConfigContext
+type Requester interface {
+	RequestWithConfigContext(ctx context.Context, config ai.RequestConfig) (ai.ResponseResult, error)
+}
+
+// Service classifies articles
+type Service struct {
+	db           *database.DB
+	tracker      *aiusage.Tracker
+	newRequester func(profile database.AIProfile) Requester
+}
+
+// NewService creates a classification service. Usage is recorded with tracker, which may be nil.
+func NewService(db *database.DB, tracker *aiusage.Tracker) *Service {
+	s := &Service{db: db, tracker: tracker}
+	s.newRequester = s.newClient
+	return s
+}
+
+// Enabled reports whether classification is enabled in the settings
+func (s *Service) Enabled() bool {
+	enabled, _ := s.db.GetSetting("ai_classification_enabled")
+	return enabled == "true"
+}
+
+// Topics returns the user's topics, one per line or separated by commas
+func (s *Service) Topics() []string {
+	value, _ := s.db.GetSetting("ai_classification_topics")
+	return ParseTopics(value)
+}
+
+// ParseTopics splits a topic list on newlines and commas, dropping blanks and duplicates
+func ParseTopics(value string) []string {
+	topics := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, topic := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == ',' }) {
+		topic = strings.TrimSpace(topic)
+		key := strings.ToLower(topic)
+		if topic == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// TopicsHash identifies a topic list, so articles are classified again when it changes
+func TopicsHash(topics []string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.Join(topics, "\n"))))
+	return hex.EncodeToString(sum[:8])
+}
+
+// pendingArticle is an article waiting to be sent to the model
+type pendingArticle struct {
+	database.ClassificationCandidate
+	text        string
+	contentHash string
+}
+
+// ClassifyArticles classifies the articles that haven't been classified against the current
+// topics yet and sets their topics, entities and "why it matters" line. It returns how many
+// articles were classified; when a batch fails, the earlier ones are kept and the error returned.
+func (s *Service) ClassifyArticles(ctx context.Context, articles []models.Article) (int, error) {
+	if !s.Enabled() || len(articles) == 0 {
+		return 0, nil
+	}
+
+	topics := s.Topics()
+	topicsHash := TopicsHash(topics)
+	ids := make([]int64, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	candidates, err := s.db.GetClassificationCandidates(ids, topicsHash)
+	if err != nil {
+		return 0, err
+	}
+	if len(candidates) > maxArticlesPerRun {
+		candidates = candidates[:maxArticlesPerRun]
+	}
+
+	results := make(map[int64]database.ArticleClassification)
+	var pending []pendingArticle
+	for _, c := range candidates {
+		text := articleText(c)
+		hash := contentHash(text)
+		cached, err := s.db.GetClassificationByContent(hash, topicsHash)
+		if err != nil {
+			return 0, err
+		}
+		if cached != nil {
+			cached.ArticleID = c.ArticleID
+			if err := s.db.SaveArticleClassification(*cached); err != nil {
+				return 0, err
+			}
+			results[c.ArticleID] = *cached
+			continue
+		}
+		pending = append(pending, pendingArticle{ClassificationCandidate: c, text: text, contentHash: hash})
+	}
+
+	for start := 0; start < len(pending) && err == nil; start += batchSize {
+		if s.tracker != nil && s.tracker.IsFeatureLimitReached(Feature) {
+			err = aiprofile.ErrLimitReached
+			break
+		}
+		batch := pending[start:min(start+batchSize, len(pending))]
+
+		var classified []database.ArticleClassification
+		classified, err = s.classifyBatch(ctx, topics, batch)
+		for _, c := range classified {
+			c.TopicsHash = topicsHash
+			if saveErr := s.db.SaveArticleClassification(c); saveErr != nil {
+				return len(results), saveErr
+			}
+			results[c.ArticleID] = c
+		}
+	}
+
+	for i := range articles {
+		if c, ok := results[articles[i].ID]; ok {
+			articles[i].Topics = c.Topics
+			articles[i].Entities = c.Entities
+			articles[i].WhyItMatters = c.WhyItMatters
+		}
+	}
+	return len(results), err
+}
+
+// classifyBatch asks the model to classify a batch of articles and records the usage
+func (s *Service) classifyBatch(ctx context.Context, topics []string, batch []pendingArticle) ([]database.ArticleClassification, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	ctx, collector := ai.WithUsageCollector(ctx)
+
+	userPrompt := buildPrompt(topics, batch)
+	config := ai.RequestConfig{
+		SystemPrompt:   systemPrompt,
+		UserPrompt:     userPrompt,
+		Temperature:    0.1,
+		MaxTokens:      200 + 150*len(batch),
+		ResponseFormat: ai.JSONSchemaFormat("article_classifications", responseSchema(topics)),
+	}
+
+	var content string
+	var classified []database.ArticleClassification
+	profile, err := aiprofile.Run(ctx, s.db, Feature, func(ctx context.Context, profile database.AIProfile) error {
+		config.Model = profile.Model
+		result, err := s.newRequester(profile).RequestWithConfigContext(ctx, config)
+		if err != nil {
+			return err
+		}
+		// Output that doesn't parse is treated like a failed request and tried with the next profile
+		parsed, err := parseResponse(result.Content, topics, batch)
+		if err != nil {
+			return err
+		}
+		content = result.Content
+		classified = parsed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.tracker != nil {
+		estimate := ai.TokenUsage{
+			InputTokens:  aiusage.EstimateTokens(systemPrompt + userPrompt),
+			OutputTokens: aiusage.EstimateTokens(content),
+		}
+		tokens := s.tracker.RecordCalls(Feature, profile.Name, collector.Calls(), estimate)
+		aiprofile.RecordUsage(s.db, profile, tokens)
+	}
+	for i := range classified {
+		classified[i].Model = profile.Model
+	}
+	return classified, nil
+}
+
+// buildPrompt lists the topics and the numbered articles of a batch
+func buildPrompt(topics []string, batch []pendingArticle) string {
+	var b strings.Builder
+	if len(topics) > 0 {
+		b.WriteString("Topics:\n")
+		for _, topic := range topics {
+			b.WriteString("- " + topic + "\n")
+		}
+	} else {
+		b.WriteString("Topics: none, leave topics empty.\n")
+	}
+	for i, article := range batch {
+		fmt.Fprintf(&b, "\n[%d]", i+1)
+		if article.FeedTitle != "" {
+			b.WriteString(" (" + article.FeedTitle + ")")
+		}
+		b.WriteString("\n" + article.text + "\n")
+	}
+	return b.String()
+}
+
+// responseSchema returns the JSON schema of the response, restricting topics to the user's
+func responseSchema(topics []string) map[string]interface{} {
+	topicItems := map[string]interface{}{"type": "string"}
+	if len(topics) > 0 {
+		topicItems["enum"] = topics
+	}
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"articles": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":             map[string]interface{}{"type": "integer"},
+						"topics":         map[string]interface{}{"type": "array", "items": topicItems},
+						"entities":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+						"why_it_matters": map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"id", "topics", "entities", "why_it_matters"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"articles"},
+		"additionalProperties": false,
+	}
+}
+
+// response is the structured output of a classification request
+type response struct {
+	Articles []struct {
+		ID           flexibleInt `json:"id"`
+		Topics       []string    `json:"topics"`
+		Entities     []string    `json:"entities"`
+		WhyItMatters string      `json:"why_it_matters"`
+	} `json:"articles"`
+}
+
+// flexibleInt decodes a number that some models return as a string
+type flexibleInt int
+
+func (n *flexibleInt) UnmarshalJSON(data []byte) error {
+	value, err := strconv.Atoi(strings.Trim(string(data), `"[]`))
+	if err != nil {
+		return fmt.Errorf("invalid article id %s", data)
+	}
+	*n = flexibleInt(value)
+	return nil
+}
+
+// parseResponse maps a model response back to the articles of a batch. Topics that aren't
+// in the user's list are dropped. Articles the model left out are not classified.
+func parseResponse(content string, topics []string, batch []pendingArticle) ([]database.ArticleClassification, error) {
+	var r response
+	if err := ai.DecodeJSON(content, &r); err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]string, len(topics))
+	for _, topic := range topics {
+		allowed[strings.ToLower(topic)] = topic
+	}
+
+	classified := make([]database.ArticleClassification, 0, len(r.Articles))
+	seen := make(map[int]bool)
+	for _, entry := range r.Articles {
+		index := int(entry.ID) - 1
+		if index < 0 || index >= len(batch) || seen[index] {
+			continue
+		}
+		seen[index] = true
+
+		c := database.ArticleClassification{
+			ArticleID:    batch[index].ArticleID,
+			ContentHash:  batch[index].contentHash,
+			Topics:       make([]string, 0),
+			WhyItMatters: truncate(strings.TrimSpace(entry.WhyItMatters), maxWhyChars),
+		}
+		for _, topic := range entry.Topics {
+			if canonical, ok := allowed[strings.ToLower(strings.TrimSpace(topic))]; ok && !contains(c.Topics, canonical) {
+				c.Topics = append(c.Topics, canonical)
+			}
+		}
+		c.Entities = cleanEntities(entry.Entities)
+		classified = append(classified, c)
+	}
+	if len(classified) == 0 {
+		return nil, fmt.Errorf("no article classifications in response")
+	}
+	return classified, nil
+}
+
+// cleanEntities trims entities and drops blanks and duplicates, keeping at most maxEntities
+func cleanEntities(entities []string) []string {
+	cleaned := make([]string, 0, len(entities))
+	seen := make(map[string]bool)
+	for _, entity := range entities {
+		entity = strings.TrimSpace(entity)
+		key := strings.ToLower(entity)
+		if entity == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, entity)
+		if len(cleaned) == maxEntities {
+			break
+		}
+	}
+	return cleaned
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, maxChars int) string {
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+	return string(runes[:maxChars])
+}
+
+// articleText returns the text sent to the model for an article
+func articleText(c database.ClassificationCandidate) string {
+	return truncate(semantic.ArticleText(c.Title, c.Summary, c.Content), maxTextChars)
+}
+
+// contentHash identifies the text of an article, so duplicates reuse a classification
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// newClient creates an AI client for a profile, using the global proxy if configured
+func (s *Service) newClient(profile database.AIProfile) Requester {
+	config := aiprofile.ClientConfig(profile, requestTimeout)
+	httpClient, err := utils.CreateHTTPClientWithProxy(s.db, requestTimeout)
+	if err != nil {
+		log.Printf("Failed to create HTTP client with proxy: %v", err)
+		return ai.NewClient(config)
+	}
+	return ai.NewClientWithHTTPClient(config, httpClient)
+}
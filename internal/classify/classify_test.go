@@ -0,0 +1,152 @@
+package classify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+// fakeRequester answers classification requests by looking for topic words in each article
+type fakeRequester struct {
+	calls int
+}
+
+func (f *fakeRequester) RequestWithConfigContext(ctx context.Context, config ai.RequestConfig) (ai.ResponseResult, error) {
+	f.calls++
+	var entries []string
+	for i, part := range strings.Split(config.UserPrompt, "\n[")[1:] {
+		topics := `[]`
+		if strings.Contains(part, "rocket") {
+			topics = `["space"]`
+		}
+		entries = append(entries, fmt.Sprintf(`{"id":%d,"topics":%s,"entities":["NASA"," NASA ",""],"why_it_matters":"It matters."}`, i+1, topics))
+	}
+	return ai.ResponseResult{Content: "```json\n{\"articles\":[" + strings.Join(entries, ",") + "]}\n```"}, nil
+}
+
+func setupService(t *testing.T) (*Service, *database.DB, *fakeRequester) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	db.SetSetting("ai_classification_enabled", "true")
+	db.SetSetting("ai_classification_topics", "Space\nSports, space")
+
+	requester := &fakeRequester{}
+	s := NewService(db, aiusage.NewTracker(db))
+	s.newRequester = func(database.AIProfile) Requester { return requester }
+	return s, db, requester
+}
+
+func saveArticles(t *testing.T, db *database.DB, feed string, titles ...string) []models.Article {
+	t.Helper()
+	feedID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://example.com/" + feed})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	for i, title := range titles {
+		if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: title, URL: fmt.Sprintf("https://example.com/%s/%d", feed, i), PublishedAt: time.Now()}); err != nil {
+			t.Fatalf("SaveArticle error: %v", err)
+		}
+	}
+	articles, err := db.GetArticles("", feedID, "", false, len(titles), 0)
+	if err != nil || len(articles) != len(titles) {
+		t.Fatalf("expected %d articles, got %d (%v)", len(titles), len(articles), err)
+	}
+	return articles
+}
+
+func TestParseTopics(t *testing.T) {
+	got := ParseTopics("Space\n Sports , space\n\n,AI")
+	if strings.Join(got, "|") != "Space|Sports|AI" {
+		t.Errorf("unexpected topics: %q", got)
+	}
+}
+
+func TestClassifyArticles(t *testing.T) {
+	s, db, requester := setupService(t)
+
+	titles := make([]string, batchSize+2)
+	for i := range titles {
+		titles[i] = fmt.Sprintf("Weather report %d", i)
+	}
+	titles[0] = "New rocket launch"
+	articles := saveArticles(t, db, "news", titles...)
+
+	n, err := s.ClassifyArticles(context.Background(), articles)
+	if err != nil || n != len(titles) {
+		t.Fatalf("expected %d articles to be classified, got %d (%v)", len(titles), n, err)
+	}
+	if requester.calls != 2 {
+		t.Errorf("expected 2 batched requests, got %d", requester.calls)
+	}
+
+	var rocket models.Article
+	for _, a := range articles {
+		if a.Title == "New rocket launch" {
+			rocket = a
+		}
+	}
+	if strings.Join(rocket.Topics, ",") != "Space" || strings.Join(rocket.Entities, ",") != "NASA" || rocket.WhyItMatters != "It matters." {
+		t.Errorf("unexpected classification: %+v", rocket)
+	}
+
+	counts, err := db.GetTagCounts(database.TagKindTopic, 10)
+	if err != nil || len(counts) != 1 || counts[0].Value != "Space" || counts[0].Count != 1 {
+		t.Errorf("unexpected topic counts: %+v (%v)", counts, err)
+	}
+
+	// Classified articles and duplicates of them are not sent again
+	if n, err := s.ClassifyArticles(context.Background(), articles); err != nil || n != 0 {
+		t.Errorf("expected nothing to classify again, got %d (%v)", n, err)
+	}
+	duplicate := saveArticles(t, db, "mirror", "New rocket launch")
+	if n, err := s.ClassifyArticles(context.Background(), duplicate); err != nil || n != 1 {
+		t.Fatalf("expected the duplicate to be classified from cache, got %d (%v)", n, err)
+	}
+	if requester.calls != 2 || strings.Join(duplicate[0].Topics, ",") != "Space" {
+		t.Errorf("expected a cached classification, got %d calls and %+v", requester.calls, duplicate[0])
+	}
+
+	// Changing the topics classifies articles again
+	db.SetSetting("ai_classification_topics", "Space, Weather")
+	if n, err := s.ClassifyArticles(context.Background(), articles[:1]); err != nil || n != 1 || requester.calls != 3 {
+		t.Errorf("expected a new classification after the topics changed, got %d (%v)", n, err)
+	}
+}
+
+func TestClassifyArticles_LimitReached(t *testing.T) {
+	s, db, requester := setupService(t)
+	db.SetSetting("ai_usage_limit", "10")
+	db.SetSetting("ai_usage_tokens", "10")
+
+	articles := saveArticles(t, db, "news", "New rocket launch")
+	if _, err := s.ClassifyArticles(context.Background(), articles); err == nil {
+		t.Fatalf("expected an error once the usage limit is reached")
+	}
+	if requester.calls != 0 {
+		t.Errorf("expected no request once the usage limit is reached, got %d", requester.calls)
+	}
+}
+
+func TestClassifyArticles_Disabled(t *testing.T) {
+	s, db, requester := setupService(t)
+	db.SetSetting("ai_classification_enabled", "false")
+
+	articles := saveArticles(t, db, "news", "New rocket launch")
+	if n, err := s.ClassifyArticles(context.Background(), articles); err != nil || n != 0 || requester.calls != 0 {
+		t.Errorf("expected nothing to be classified while disabled, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+)
+
+func runArticles(a *App, args []string) error {
+	if len(args) == 0 {
+		return usageError("missing articles subcommand")
+	}
+
+	switch args[0] {
+	case "list":
+		return a.articlesList(args[1:])
+	case "mark":
+		return a.articlesMark(args[1:])
+	case "mark-all-read":
+		return a.articlesMarkAllRead(args[1:])
+	default:
+		return usageError("unknown articles subcommand: %s", args[0])
+	}
+}
+
+func (a *App) articlesList(args []string) error {
+	fs := a.newFlagSet("articles list")
+	filter := fs.String("filter", "all", "Filter: all, unread, favorites or readLater")
+	feedID := fs.Int64("feed", 0, "Only list articles of this feed")
+	category := fs.String("category", "", "Only list articles in this category")
+	limit := fs.Int("limit", 50, "Maximum number of articles")
+	offset := fs.Int("offset", 0, "Number of articles to skip")
+	showHidden := fs.Bool("hidden", false, "Include hidden articles")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch *filter {
+	case "all", "unread", "favorites", "readLater":
+	default:
+		return usageError("unknown filter: %s", *filter)
+	}
+	if *limit <= 0 {
+		return usageError("limit must be positive")
+	}
+
+	articles, err := a.DB.GetArticles(*filter, *feedID, *category, *showHidden, *limit, *offset)
+	if err != nil {
+		return err
+	}
+	if articles == nil {
+		articles = []models.Article{}
+	}
+	return a.printJSON(articles)
+}
+
+// articlesMark changes the state of one or more articles.
+// Read and favorite changes of FreshRSS articles are queued and pushed on the next sync.
+func (a *App) articlesMark(args []string) error {
+	fs := a.newFlagSet("articles mark")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return usageError("expected an action (read, unread, favorite, unfavorite, read-later, no-read-later) and at least one article ID")
+	}
+
+	action := fs.Arg(0)
+	ids, err := parseIDs(fs.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	queued := 0
+	for _, id := range ids {
+		var syncReq *database.SyncRequest
+		switch action {
+		case "read", "unread":
+			syncReq, err = a.DB.MarkArticleReadWithSync(id, action == "read")
+		case "favorite", "unfavorite":
+			syncReq, err = a.DB.SetArticleFavoriteWithSync(id, action == "favorite")
+		case "read-later", "no-read-later":
+			err = a.DB.SetArticleReadLater(id, action == "read-later")
+		default:
+			return usageError("unknown action: %s", action)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to mark article %d as %s: %w", id, action, err)
+		}
+
+		if syncReq != nil {
+			if err := a.DB.EnqueueSyncChange(syncReq.ArticleID, syncReq.ArticleURL, syncReq.Action); err != nil {
+				return fmt.Errorf("failed to queue FreshRSS sync for article %d: %w", id, err)
+			}
+			queued++
+		}
+	}
+
+	return a.printJSON(map[string]interface{}{
+		"action":          action,
+		"updated":         ids,
+		"queued_for_sync": queued,
+	})
+}
+
+func (a *App) articlesMarkAllRead(args []string) error {
+	fs := a.newFlagSet("articles mark-all-read")
+	feedID := fs.Int64("feed", 0, "Only mark articles of this feed")
+	category := fs.String("category", "", "Only mark articles in this category")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *feedID > 0 && *category != "" {
+		return usageError("-feed and -category are mutually exclusive")
+	}
+
+	var err error
+	switch {
+	case *feedID > 0:
+		err = a.DB.MarkAllAsReadForFeed(*feedID)
+	case *category != "":
+		err = a.DB.MarkAllAsReadForCategory(*category)
+	default:
+		err = a.DB.MarkAllAsRead()
+	}
+	if err != nil {
+		return err
+	}
+
+	unread, err := a.DB.GetTotalUnreadCount()
+	if err != nil {
+		return err
+	}
+	return a.printJSON(map[string]interface{}{"status": "ok", "unread": unread})
+}
+
+func runFavorites(a *App, args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return usageError("expected favorites subcommand: export")
+	}
+
+	fs := a.newFlagSet("favorites export")
+	output := fs.String("o", "", "Output file (defaults to stdout)")
+	withContent := fs.Bool("content", false, "Include cached article content")
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+
+	// Favorites are never cleaned up, so a large limit returns all of them
+	articles, err := a.DB.GetArticles("favorites", 0, "", true, 1000000, 0)
+	if err != nil {
+		return err
+	}
+
+	contents := make(map[int64]string)
+	if *withContent {
+		for _, article := range articles {
+			if content, found, err := a.DB.GetArticleContent(article.ID); err == nil && found {
+				contents[article.ID] = content
+			}
+		}
+	}
+
+	return a.writeOutput(*output, []byte(favoritesMarkdown(articles, contents, time.Now())))
+}
+
+// favoritesMarkdown renders favorite articles as a Markdown document grouped by feed
+func favoritesMarkdown(articles []models.Article, contents map[int64]string, now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("# MrRSS Favorites\n\n")
+	fmt.Fprintf(&sb, "Exported %s, %d articles.\n", now.Format("2006-01-02 15:04"), len(articles))
+
+	// Group by feed while keeping the newest-first order within each feed
+	sorted := append([]models.Article(nil), articles...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].FeedTitle < sorted[j].FeedTitle
+	})
+
+	converter := md.NewConverter("", true, nil)
+	currentFeed := ""
+	for i, article := range sorted {
+		if i == 0 || article.FeedTitle != currentFeed {
+			currentFeed = article.FeedTitle
+			fmt.Fprintf(&sb, "\n## %s\n", currentFeed)
+		}
+
+		fmt.Fprintf(&sb, "\n### [%s](%s)\n\n", escapeMarkdownText(article.Title), article.URL)
+		if !article.PublishedAt.IsZero() {
+			fmt.Fprintf(&sb, "*%s*\n\n", article.PublishedAt.Format("2006-01-02"))
+		}
+		if article.Summary != "" {
+			fmt.Fprintf(&sb, "> %s\n\n", strings.ReplaceAll(strings.TrimSpace(article.Summary), "\n", "\n> "))
+		}
+		if content := contents[article.ID]; content != "" {
+			if markdown, err := converter.ConvertString(html.UnescapeString(content)); err == nil {
+				sb.WriteString(strings.TrimSpace(markdown))
+				sb.WriteString("\n\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// escapeMarkdownText escapes characters that would break a Markdown link label
+func escapeMarkdownText(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,198 @@
+// Package cli implements the MrRSS command-line interface.
+// Subcommands operate directly on the local database (the same one used by the
+// desktop app and server mode) and print JSON so they can be used from cron jobs
+// and shell pipelines.
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/utils"
+)
+
+// App holds the dependencies shared by all subcommands
+type App struct {
+	DB      *database.DB
+	Fetcher *feed.Fetcher
+	Stdout  io.Writer
+	Stderr  io.Writer
+}
+
+// command describes a single top-level subcommand
+type command struct {
+	usage   string
+	summary string
+	run     func(a *App, args []string) error
+}
+
+// commands maps subcommand names to their implementation.
+// It is populated in init to allow the help command to reference it.
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"feeds":     {"feeds <list|add|remove> [options]", "List, add or remove feed subscriptions", runFeeds},
+		"import":    {"import [-refresh] <file.opml|file.json>", "Import subscriptions from an OPML or JSON file", runImport},
+		"export":    {"export [-format opml|json] [-o file]", "Export local subscriptions as OPML or JSON", runExport},
+		"refresh":   {"refresh [-feed id] [-timeout 10m]", "Refresh one feed or all feeds and wait for completion", runRefresh},
+		"articles":  {"articles <list|mark|mark-all-read> [options]", "List articles or change their read/favorite state", runArticles},
+		"favorites": {"favorites export [-content] [-o file]", "Export favorite articles to Markdown", runFavorites},
+		"cleanup":   {"cleanup [-content | -all]", "Run database cleanup", runCleanup},
+		"sync":      {"sync", "Run a FreshRSS synchronization", runSync},
+		"stats":     {"stats [-period week|month|year|all]", "Print usage statistics", runStats},
+		"help":      {"help", "Show this help", runHelp},
+	}
+}
+
+// errUsage signals an invocation error; the message is printed together with usage information
+var errUsage = errors.New("usage error")
+
+// usageError returns an error that makes Run print the command usage
+func usageError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// IsCommand reports whether name is a CLI subcommand
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Main opens the application database and runs the subcommand in args.
+// It returns the process exit code.
+func Main(args []string) int {
+	// Library code logs progress via the standard logger; keep it out of the JSON output
+	if os.Getenv("MRRSS_DEBUG") != "" {
+		log.SetOutput(os.Stderr)
+	} else {
+		log.SetOutput(io.Discard)
+	}
+
+	if len(args) > 0 && args[0] == "help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	dbPath, err := utils.GetDBPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting database path: %v\n", err)
+		return 1
+	}
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+	if err := db.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing database: %v\n", err)
+		return 1
+	}
+
+	fetcher := feed.NewFetcher(db)
+	app := &App{DB: db, Fetcher: fetcher, Stdout: os.Stdout, Stderr: os.Stderr}
+	code := app.Run(args)
+
+	// Let background work started by the command finish before exiting
+	fetcher.WaitForPostProcessing()
+	fetcher.GetCleanupManager().Stop()
+	return code
+}
+
+// Run executes the subcommand in args and returns the process exit code
+func (a *App) Run(args []string) int {
+	if len(args) == 0 {
+		printUsage(a.Stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(a.Stderr, "Unknown command: %s\n\n", args[0])
+		printUsage(a.Stderr)
+		return 2
+	}
+
+	if err := cmd.run(a, args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(a.Stderr, "%v\nUsage: mrrss %s\n", err, cmd.usage)
+			return 2
+		}
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(a.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printJSON writes v as indented JSON to stdout
+func (a *App) printJSON(v interface{}) error {
+	enc := json.NewEncoder(a.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// newFlagSet creates a flag set that reports errors to stderr instead of exiting
+func (a *App) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("mrrss "+name, flag.ContinueOnError)
+	fs.SetOutput(a.Stderr)
+	return fs
+}
+
+// parseFlags parses args, turning flag errors into usage errors
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError("%v", err)
+	}
+	return nil
+}
+
+// writeOutput writes data to the named file, or to stdout when path is empty or "-"
+func (a *App) writeOutput(path string, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := a.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return a.printJSON(map[string]interface{}{"file": path, "bytes": len(data)})
+}
+
+func runHelp(a *App, args []string) error {
+	printUsage(a.Stdout)
+	return nil
+}
+
+func printUsage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("Usage: mrrss <command> [options]\n\n")
+	sb.WriteString("Commands operate on the local MrRSS database and print JSON.\n\n")
+	for _, name := range names {
+		cmd := commands[name]
+		fmt.Fprintf(&sb, "  %-48s %s\n", cmd.usage, cmd.summary)
+	}
+	sb.WriteString("\nSet MRRSS_DEBUG=1 to print log output to stderr.\n")
+	fmt.Fprint(w, sb.String())
+}
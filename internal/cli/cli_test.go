@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/models"
+)
+
+const testRSS = `<?xml version="1.0"?><rss><channel><title>CLI Test</title>` +
+	`<item><title>first</title><link>https://example.com/1</link><guid>1</guid><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>` +
+	`<item><title>second</title><link>https://example.com/2</link><guid>2</guid><pubDate>Tue, 03 Jan 2006 15:04:05 GMT</pubDate></item>` +
+	`</channel></rss>`
+
+func setupApp(t *testing.T) (*App, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	return &App{DB: db, Fetcher: feed.NewFetcher(db), Stdout: stdout, Stderr: stderr}, stdout, stderr
+}
+
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testRSS))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRun_UnknownCommandAndUsageErrors(t *testing.T) {
+	app, _, stderr := setupApp(t)
+
+	if code := app.Run(nil); code != 2 {
+		t.Errorf("Run(nil) = %d, want 2", code)
+	}
+	if code := app.Run([]string{"bogus"}); code != 2 {
+		t.Errorf("Run(bogus) = %d, want 2", code)
+	}
+	stderr.Reset()
+	if code := app.Run([]string{"feeds", "remove", "abc"}); code != 2 {
+		t.Errorf("feeds remove abc = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "Usage: mrrss feeds") {
+		t.Errorf("expected usage on stderr, got %q", stderr.String())
+	}
+	if code := app.Run([]string{"stats", "-period", "decade"}); code != 2 {
+		t.Errorf("stats -period decade = %d, want 2", code)
+	}
+}
+
+func TestIsCommand(t *testing.T) {
+	if !IsCommand("feeds") || !IsCommand("refresh") {
+		t.Error("expected feeds and refresh to be commands")
+	}
+	if IsCommand("-server") || IsCommand("") {
+		t.Error("flags must not be treated as commands")
+	}
+}
+
+func TestFeedsListImportExportRemove(t *testing.T) {
+	app, stdout, stderr := setupApp(t)
+
+	opmlPath := filepath.Join(t.TempDir(), "subs.opml")
+	opmlContent := `<?xml version="1.0"?><opml version="1.0"><body>` +
+		`<outline text="Tech"><outline type="rss" text="A" title="A" xmlUrl="https://a.example.com/feed"/></outline>` +
+		`<outline type="rss" text="B" title="B" xmlUrl="https://b.example.com/feed"/>` +
+		`</body></opml>`
+	if err := os.WriteFile(opmlPath, []byte(opmlContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := app.Run([]string{"import", opmlPath}); code != 0 {
+		t.Fatalf("import exit code %d, stderr: %s", code, stderr.String())
+	}
+	var imported struct {
+		Imported int     `json:"imported"`
+		FeedIDs  []int64 `json:"feed_ids"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &imported); err != nil {
+		t.Fatalf("decode import output: %v (%s)", err, stdout.String())
+	}
+	if imported.Imported != 2 {
+		t.Fatalf("expected 2 imported feeds, got %d", imported.Imported)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"feeds", "list", "-category", "Tech"}); code != 0 {
+		t.Fatalf("feeds list exit code %d, stderr: %s", code, stderr.String())
+	}
+	var feeds []models.Feed
+	if err := json.Unmarshal(stdout.Bytes(), &feeds); err != nil {
+		t.Fatalf("decode feeds: %v", err)
+	}
+	if len(feeds) != 1 || feeds[0].URL != "https://a.example.com/feed" {
+		t.Errorf("unexpected feeds in category: %+v", feeds)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"export", "-format", "json"}); code != 0 {
+		t.Fatalf("export exit code %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "https://b.example.com/feed") {
+		t.Errorf("expected exported JSON to contain feed B, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	id := strconv.FormatInt(imported.FeedIDs[0], 10)
+	if code := app.Run([]string{"feeds", "remove", id}); code != 0 {
+		t.Fatalf("feeds remove exit code %d, stderr: %s", code, stderr.String())
+	}
+	all, _ := app.DB.GetFeeds()
+	if len(all) != 1 {
+		t.Errorf("expected 1 feed after removal, got %d", len(all))
+	}
+
+	if code := app.Run([]string{"feeds", "remove", id}); code != 1 {
+		t.Errorf("removing a missing feed should fail, got exit code %d", code)
+	}
+}
+
+func TestRefreshMarkAndFavoritesExport(t *testing.T) {
+	app, stdout, stderr := setupApp(t)
+	srv := newFeedServer(t)
+
+	feedID, err := app.DB.AddFeed(&models.Feed{Title: "CLI Test", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+
+	if code := app.Run([]string{"refresh", "-feed", strconv.FormatInt(feedID, 10), "-timeout", "30s"}); code != 0 {
+		t.Fatalf("refresh exit code %d, stderr: %s", code, stderr.String())
+	}
+	var result refreshResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("decode refresh output: %v", err)
+	}
+	if !result.Completed || len(result.Errors) != 0 {
+		t.Fatalf("unexpected refresh result: %+v", result)
+	}
+	app.Fetcher.WaitForPostProcessing()
+
+	stdout.Reset()
+	if code := app.Run([]string{"articles", "list", "-filter", "unread"}); code != 0 {
+		t.Fatalf("articles list exit code %d, stderr: %s", code, stderr.String())
+	}
+	var articles []models.Article
+	if err := json.Unmarshal(stdout.Bytes(), &articles); err != nil {
+		t.Fatalf("decode articles: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("expected 2 unread articles, got %d", len(articles))
+	}
+
+	id := strconv.FormatInt(articles[0].ID, 10)
+	if code := app.Run([]string{"articles", "mark", "favorite", id}); code != 0 {
+		t.Fatalf("articles mark exit code %d, stderr: %s", code, stderr.String())
+	}
+	if code := app.Run([]string{"articles", "mark", "read", id}); code != 0 {
+		t.Fatalf("articles mark exit code %d, stderr: %s", code, stderr.String())
+	}
+	unread, _ := app.DB.GetTotalUnreadCount()
+	if unread != 1 {
+		t.Errorf("expected 1 unread article, got %d", unread)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"favorites", "export"}); code != 0 {
+		t.Fatalf("favorites export exit code %d, stderr: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "# MrRSS Favorites") || !strings.Contains(out, "## CLI Test") ||
+		!strings.Contains(out, "("+articles[0].URL+")") {
+		t.Errorf("unexpected favorites markdown:\n%s", out)
+	}
+
+	stdout.Reset()
+	if code := app.Run([]string{"stats"}); code != 0 {
+		t.Fatalf("stats exit code %d, stderr: %s", code, stderr.String())
+	}
+	var stats map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if stats["articles"].(float64) != 2 || stats["favorites"].(float64) != 1 || stats["unread"].(float64) != 1 {
+		t.Errorf("unexpected stats: %v", stats)
+	}
+}
+
+func TestFavoritesMarkdown_GroupsByFeedAndEscapes(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)
+	articles := []models.Article{
+		{ID: 1, Title: "Zeta [draft]", URL: "https://z.example.com", FeedTitle: "Zed"},
+		{ID: 2, Title: "Alpha", URL: "https://a.example.com", FeedTitle: "Ay", Summary: "line one\nline two"},
+		{ID: 3, Title: "Zeta 2", URL: "https://z2.example.com", FeedTitle: "Zed"},
+	}
+	out := favoritesMarkdown(articles, map[int64]string{2: "<p>Hello <b>world</b></p>"}, now)
+
+	if strings.Index(out, "## Ay") > strings.Index(out, "## Zed") {
+		t.Error("expected feeds to be sorted by title")
+	}
+	if strings.Count(out, "## Zed") != 1 {
+		t.Error("expected articles of the same feed to be grouped")
+	}
+	for _, want := range []string{`Zeta \[draft\]`, "> line one\n> line two", "Hello **world**", "Exported 2025-01-02 03:04, 3 articles."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/jsonimport"
+	"MrRSS/internal/models"
+	"MrRSS/internal/opml"
+)
+
+// defaultRefreshTimeout bounds how long commands wait for feed refreshes to finish
+const defaultRefreshTimeout = 10 * time.Minute
+
+func runFeeds(a *App, args []string) error {
+	if len(args) == 0 {
+		return usageError("missing feeds subcommand")
+	}
+
+	switch args[0] {
+	case "list":
+		return a.feedsList(args[1:])
+	case "add":
+		return a.feedsAdd(args[1:])
+	case "remove", "rm":
+		return a.feedsRemove(args[1:])
+	default:
+		return usageError("unknown feeds subcommand: %s", args[0])
+	}
+}
+
+func (a *App) feedsList(args []string) error {
+	fs := a.newFlagSet("feeds list")
+	category := fs.String("category", "", "Only list feeds in this category")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	feeds, err := a.DB.GetFeeds()
+	if err != nil {
+		return err
+	}
+
+	result := make([]models.Feed, 0, len(feeds))
+	for _, f := range feeds {
+		if *category != "" && f.Category != *category && !strings.HasPrefix(f.Category, *category+"/") {
+			continue
+		}
+		// Never print credentials
+		f.EmailPassword = ""
+		result = append(result, f)
+	}
+	return a.printJSON(result)
+}
+
+func (a *App) feedsAdd(args []string) error {
+	fs := a.newFlagSet("feeds add")
+	category := fs.String("category", "", "Category of the new feed")
+	title := fs.String("title", "", "Custom title (defaults to the feed title)")
+	refresh := fs.Bool("refresh", false, "Fetch articles of the new feed and wait for completion")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("expected exactly one feed URL")
+	}
+
+	feedID, err := a.Fetcher.AddSubscription(fs.Arg(0), *category, *title)
+	if err != nil {
+		return err
+	}
+
+	result := map[string]interface{}{"id": feedID, "url": fs.Arg(0)}
+	if *refresh {
+		result["refresh"] = a.refreshFeedsByID(context.Background(), []int64{feedID}, defaultRefreshTimeout)
+	}
+	return a.printJSON(result)
+}
+
+func (a *App) feedsRemove(args []string) error {
+	fs := a.newFlagSet("feeds remove")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError("expected at least one feed ID")
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	removed := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, err := a.DB.GetFeedByID(id); err != nil {
+			return fmt.Errorf("feed %d not found", id)
+		}
+		if err := a.DB.DeleteFeed(id); err != nil {
+			return fmt.Errorf("failed to remove feed %d: %w", id, err)
+		}
+		removed = append(removed, id)
+	}
+	return a.printJSON(map[string]interface{}{"removed": removed})
+}
+
+func runImport(a *App, args []string) error {
+	fs := a.newFlagSet("import")
+	refresh := fs.Bool("refresh", false, "Fetch articles of imported feeds and wait for completion")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("expected exactly one file")
+	}
+
+	path := fs.Arg(0)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Same format detection as the import dialog: JSON by extension, OPML otherwise
+	var feeds []models.Feed
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		feeds, err = jsonimport.Parse(file)
+	} else {
+		feeds, err = opml.Parse(file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	feedIDs := make([]int64, 0, len(feeds))
+	failed := make([]map[string]string, 0)
+	for _, f := range feeds {
+		var feedID int64
+		if f.Type == "HTML+XPath" || f.Type == "XML+XPath" {
+			feedID, err = a.Fetcher.AddXPathSubscription(
+				f.URL, f.Category, f.Title, f.Type,
+				f.XPathItem, f.XPathItemTitle, f.XPathItemContent, f.XPathItemUri,
+				f.XPathItemAuthor, f.XPathItemTimestamp, f.XPathItemTimeFormat,
+				f.XPathItemThumbnail, f.XPathItemCategories, f.XPathItemUid,
+			)
+		} else {
+			feedID, err = a.Fetcher.ImportSubscription(f.Title, f.URL, f.Category)
+		}
+		if err != nil {
+			failed = append(failed, map[string]string{"url": f.URL, "error": err.Error()})
+			continue
+		}
+		feedIDs = append(feedIDs, feedID)
+	}
+
+	result := map[string]interface{}{
+		"imported": len(feedIDs),
+		"feed_ids": feedIDs,
+		"failed":   failed,
+	}
+	if *refresh && len(feedIDs) > 0 {
+		result["refresh"] = a.refreshFeedsByID(context.Background(), feedIDs, defaultRefreshTimeout)
+	}
+	return a.printJSON(result)
+}
+
+func runExport(a *App, args []string) error {
+	fs := a.newFlagSet("export")
+	format := fs.String("format", "opml", "Export format: opml or json")
+	output := fs.String("o", "", "Output file (defaults to stdout)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	feeds, err := a.DB.GetFeeds()
+	if err != nil {
+		return err
+	}
+
+	// Only export local feeds; FreshRSS feeds are managed by the sync
+	localFeeds := make([]models.Feed, 0, len(feeds))
+	for _, f := range feeds {
+		if !f.IsFreshRSSSource {
+			localFeeds = append(localFeeds, f)
+		}
+	}
+
+	var data []byte
+	switch *format {
+	case "opml":
+		data, err = opml.Generate(localFeeds)
+	case "json":
+		data, err = jsonimport.Generate(localFeeds)
+	default:
+		return usageError("unknown export format: %s", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	return a.writeOutput(*output, data)
+}
+
+// parseIDs parses a list of numeric IDs
+func parseIDs(values []string) ([]int64, error) {
+	ids := make([]int64, 0, len(values))
+	for _, v := range values {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, usageError("invalid ID: %s", v)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
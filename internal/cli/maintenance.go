@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/freshrss"
+	"MrRSS/internal/statistics"
+)
+
+// refreshResult summarises a refresh run
+type refreshResult struct {
+	Completed bool             `json:"completed"` // False if the timeout expired before all feeds were refreshed
+	Errors    map[int64]string `json:"errors"`    // Feed ID -> error message
+}
+
+func runRefresh(a *App, args []string) error {
+	fs := a.newFlagSet("refresh")
+	feedID := fs.Int64("feed", 0, "Only refresh the feed with this ID")
+	timeout := fs.Duration("timeout", defaultRefreshTimeout, "Maximum time to wait for the refresh to finish")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if *feedID > 0 {
+		if _, err := a.DB.GetFeedByID(*feedID); err != nil {
+			return fmt.Errorf("feed %d not found", *feedID)
+		}
+		return a.printJSON(a.refreshFeedsByID(ctx, []int64{*feedID}, *timeout))
+	}
+
+	a.Fetcher.FetchAll(ctx)
+	return a.printJSON(a.waitForRefresh(*timeout))
+}
+
+// refreshFeedsByID queues the given feeds with manual priority and waits for them to finish
+func (a *App) refreshFeedsByID(ctx context.Context, feedIDs []int64, timeout time.Duration) refreshResult {
+	a.Fetcher.FetchFeedsByIDs(ctx, feedIDs)
+	return a.waitForRefresh(timeout)
+}
+
+// waitForRefresh waits until the task manager is idle and collects per-feed errors
+func (a *App) waitForRefresh(timeout time.Duration) refreshResult {
+	tm := a.Fetcher.GetTaskManager()
+	completed := tm.Wait(timeout)
+
+	errors := make(map[int64]string)
+	for id, msg := range tm.GetProgress().Errors {
+		errors[id] = msg
+	}
+	return refreshResult{Completed: completed, Errors: errors}
+}
+
+func runCleanup(a *App, args []string) error {
+	fs := a.newFlagSet("cleanup")
+	contentOnly := fs.Bool("content", false, "Clear the whole article content cache")
+	all := fs.Bool("all", false, "Delete all articles and cached contents (feeds and settings are kept)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *contentOnly && *all {
+		return usageError("-content and -all are mutually exclusive")
+	}
+
+	sizeBefore, _ := a.DB.GetDatabaseSizeMB()
+	result := map[string]interface{}{"size_mb_before": sizeBefore}
+
+	switch {
+	case *all:
+		contents, err := a.DB.CleanupAllArticleContents()
+		if err != nil {
+			return err
+		}
+		articles, err := a.DB.DeleteAllArticles()
+		if err != nil {
+			return err
+		}
+		result["type"] = "all"
+		result["contents"] = contents
+		result["articles"] = articles
+	case *contentOnly:
+		contents, err := a.DB.CleanupAllArticleContents()
+		if err != nil {
+			return err
+		}
+		result["type"] = "content"
+		result["contents"] = contents
+	default:
+		// Same size-based cleanup that runs automatically after refreshes
+		result["type"] = "auto"
+		result["removed"] = a.Fetcher.GetCleanupManager().RunCleanup()
+	}
+
+	sizeAfter, _ := a.DB.GetDatabaseSizeMB()
+	result["size_mb_after"] = sizeAfter
+	return a.printJSON(result)
+}
+
+func runSync(a *App, args []string) error {
+	fs := a.newFlagSet("sync")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	enabled, err := a.DB.GetSetting("freshrss_enabled")
+	if err != nil {
+		return err
+	}
+	if enabled != "true" {
+		return fmt.Errorf("FreshRSS sync is disabled")
+	}
+
+	serverURL, _ := a.DB.GetSetting("freshrss_server_url")
+	username, _ := a.DB.GetSetting("freshrss_username")
+	password, _ := a.DB.GetEncryptedSetting("freshrss_api_password")
+	if serverURL == "" || username == "" || password == "" {
+		return fmt.Errorf("FreshRSS settings incomplete")
+	}
+
+	syncService := freshrss.NewBidirectionalSyncService(serverURL, username, password, a.DB)
+	result, err := syncService.Sync(context.Background())
+	_ = a.DB.SetSetting("freshrss_last_sync_time", time.Now().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("FreshRSS sync failed: %w", err)
+	}
+
+	pending, _ := syncService.GetPendingCount()
+	return a.printJSON(map[string]interface{}{
+		"pull_success":    result.PullSuccess,
+		"pull_changes":    result.PullChangesCount,
+		"push_success":    result.PushSuccess,
+		"push_changes":    result.PushChangesCount,
+		"errors":          result.Errors,
+		"duration_ms":     result.Duration.Milliseconds(),
+		"pending_changes": pending,
+	})
+}
+
+func runStats(a *App, args []string) error {
+	fs := a.newFlagSet("stats")
+	period := fs.String("period", "all", "Statistics period: week, month, year or all")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	switch statistics.StatPeriod(*period) {
+	case statistics.PeriodWeek, statistics.PeriodMonth, statistics.PeriodYear, statistics.PeriodAll:
+	default:
+		return usageError("unknown period: %s", *period)
+	}
+
+	summary, err := statistics.NewService(a.DB).GetStatistics(statistics.StatPeriod(*period), 0)
+	if err != nil {
+		return err
+	}
+
+	feeds, err := a.DB.GetFeeds()
+	if err != nil {
+		return err
+	}
+	var articleCount, favoriteCount int
+	if err := a.DB.QueryRow("SELECT COUNT(*), COALESCE(SUM(is_favorite), 0) FROM articles").Scan(&articleCount, &favoriteCount); err != nil {
+		return err
+	}
+	unread, err := a.DB.GetTotalUnreadCount()
+	if err != nil {
+		return err
+	}
+	sizeMB, _ := a.DB.GetDatabaseSizeMB()
+
+	return a.printJSON(map[string]interface{}{
+		"feeds":            len(feeds),
+		"articles":         articleCount,
+		"unread":           unread,
+		"favorites":        favoriteCount,
+		"database_size_mb": sizeMB,
+		"usage":            summary,
+	})
+}
@@ -20,15 +20,25 @@ var defaultsJSON []byte
 type Defaults struct {
 	AIAPIKey                      string `json:"ai_api_key"`
 	AIChatEnabled                 bool   `json:"ai_chat_enabled"`
+	AIChatToolsEnabled            bool   `json:"ai_chat_tools_enabled"`
+	AIClassificationEnabled       bool   `json:"ai_classification_enabled"`
+	AIClassificationTopics        string `json:"ai_classification_topics"`
 	AICustomHeaders               string `json:"ai_custom_headers"`
+	AIEmbeddingEnabled            bool   `json:"ai_embedding_enabled"`
+	AIEmbeddingEndpoint           string `json:"ai_embedding_endpoint"`
+	AIEmbeddingModel              string `json:"ai_embedding_model"`
 	AIEndpoint                    string `json:"ai_endpoint"`
 	AIModel                       string `json:"ai_model"`
 	AISummaryPrompt               string `json:"ai_summary_prompt"`
+	AITranslationGoogleFallback   bool   `json:"ai_translation_google_fallback"`
 	AITranslationPrompt           string `json:"ai_translation_prompt"`
 	AIUsageLimit                  string `json:"ai_usage_limit"`
 	AIUsageTokens                 string `json:"ai_usage_tokens"`
 	AutoCleanupEnabled            bool   `json:"auto_cleanup_enabled"`
 	AutoShowAllContent            bool   `json:"auto_show_all_content"`
+	BackgroundJobsConcurrency     int    `json:"background_jobs_concurrency"`
+	BackgroundSummarizeCategories string `json:"background_summarize_categories"`
+	BackgroundTranslateCategories string `json:"background_translate_categories"`
 	BaiduAppId                    string `json:"baidu_app_id"`
 	BaiduSecretKey                string `json:"baidu_secret_key"`
 	CloseToTray                   bool   `json:"close_to_tray"`
@@ -55,6 +65,7 @@ type Defaults struct {
 	FreshRSSServerUrl             string `json:"freshrss_server_url"`
 	FreshRSSSyncOnStartup         bool   `json:"freshrss_sync_on_startup"`
 	FreshRSSUsername              string `json:"freshrss_username"`
+	FullArticleTranslation        bool   `json:"full_article_translation"`
 	FullTextFetchEnabled          bool   `json:"full_text_fetch_enabled"`
 	GoogleTranslateEndpoint       string `json:"google_translate_endpoint"`
 	HoverMarkAsRead               bool   `json:"hover_mark_as_read"`
@@ -62,6 +73,8 @@ type Defaults struct {
 	Language                      string `json:"language"`
 	LastGlobalRefresh             string `json:"last_global_refresh"`
 	LastNetworkTest               string `json:"last_network_test"`
+	LibretranslateAPIKey          string `json:"libretranslate_api_key"`
+	LibretranslateEndpoint        string `json:"libretranslate_endpoint"`
 	MaxArticleAgeDays             int    `json:"max_article_age_days"`
 	MaxCacheSizeMb                int    `json:"max_cache_size_mb"`
 	MaxConcurrentRefreshes        string `json:"max_concurrent_refreshes"`
@@ -129,14 +142,28 @@ func GetString(key string) string {
 		return defaults.AIAPIKey
 	case "ai_chat_enabled":
 		return strconv.FormatBool(defaults.AIChatEnabled)
+	case "ai_chat_tools_enabled":
+		return strconv.FormatBool(defaults.AIChatToolsEnabled)
+	case "ai_classification_enabled":
+		return strconv.FormatBool(defaults.AIClassificationEnabled)
+	case "ai_classification_topics":
+		return defaults.AIClassificationTopics
 	case "ai_custom_headers":
 		return defaults.AICustomHeaders
+	case "ai_embedding_enabled":
+		return strconv.FormatBool(defaults.AIEmbeddingEnabled)
+	case "ai_embedding_endpoint":
+		return defaults.AIEmbeddingEndpoint
+	case "ai_embedding_model":
+		return defaults.AIEmbeddingModel
 	case "ai_endpoint":
 		return defaults.AIEndpoint
 	case "ai_model":
 		return defaults.AIModel
 	case "ai_summary_prompt":
 		return defaults.AISummaryPrompt
+	case "ai_translation_google_fallback":
+		return strconv.FormatBool(defaults.AITranslationGoogleFallback)
 	case "ai_translation_prompt":
 		return defaults.AITranslationPrompt
 	case "ai_usage_limit":
@@ -147,6 +174,12 @@ func GetString(key string) string {
 		return strconv.FormatBool(defaults.AutoCleanupEnabled)
 	case "auto_show_all_content":
 		return strconv.FormatBool(defaults.AutoShowAllContent)
+	case "background_jobs_concurrency":
+		return strconv.Itoa(defaults.BackgroundJobsConcurrency)
+	case "background_summarize_categories":
+		return defaults.BackgroundSummarizeCategories
+	case "background_translate_categories":
+		return defaults.BackgroundTranslateCategories
 	case "baidu_app_id":
 		return defaults.BaiduAppId
 	case "baidu_secret_key":
@@ -199,6 +232,8 @@ func GetString(key string) string {
 		return strconv.FormatBool(defaults.FreshRSSSyncOnStartup)
 	case "freshrss_username":
 		return defaults.FreshRSSUsername
+	case "full_article_translation":
+		return strconv.FormatBool(defaults.FullArticleTranslation)
 	case "full_text_fetch_enabled":
 		return strconv.FormatBool(defaults.FullTextFetchEnabled)
 	case "google_translate_endpoint":
@@ -213,6 +248,10 @@ func GetString(key string) string {
 		return defaults.LastGlobalRefresh
 	case "last_network_test":
 		return defaults.LastNetworkTest
+	case "libretranslate_api_key":
+		return defaults.LibretranslateAPIKey
+	case "libretranslate_endpoint":
+		return defaults.LibretranslateEndpoint
 	case "max_article_age_days":
 		return strconv.Itoa(defaults.MaxArticleAgeDays)
 	case "max_cache_size_mb":
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"MrRSS/internal/crypto"
+)
+
+// AIProfile is a named AI provider configuration that AI features can be routed to
+type AIProfile struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	Endpoint      string    `json:"endpoint"`
+	Model         string    `json:"model"`
+	APIKey        string    `json:"api_key"`        // Stored encrypted
+	CustomHeaders string    `json:"custom_headers"` // JSON object of extra request headers
+	UsageLimit    int64     `json:"usage_limit"`    // Token limit, 0 for unlimited
+	UsageTokens   int64     `json:"usage_tokens"`   // Tokens used so far
+	Enabled       bool      `json:"enabled"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// LimitReached reports whether the profile has used up its token limit
+func (p *AIProfile) LimitReached() bool {
+	return p.UsageLimit > 0 && p.UsageTokens >= p.UsageLimit
+}
+
+// InitAIProfilesTables creates the ai_profiles and ai_profile_routes tables if they don't exist
+func InitAIProfilesTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS ai_profiles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		endpoint TEXT DEFAULT '',
+		model TEXT DEFAULT '',
+		api_key TEXT DEFAULT '',
+		custom_headers TEXT DEFAULT '',
+		usage_limit INTEGER DEFAULT 0,
+		usage_tokens INTEGER DEFAULT 0,
+		enabled BOOLEAN DEFAULT 1,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS ai_profile_routes (
+		task TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		profile_id INTEGER NOT NULL,
+		PRIMARY KEY (task, position)
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// SaveAIProfile creates a profile, or updates it when profile.ID is set, and returns its ID.
+// The usage counter is left unchanged on update.
+func (db *DB) SaveAIProfile(profile *AIProfile) (int64, error) {
+	db.WaitForReady()
+
+	name := strings.TrimSpace(profile.Name)
+	if name == "" {
+		return 0, fmt.Errorf("name is required")
+	}
+
+	apiKey := profile.APIKey
+	if apiKey != "" {
+		encrypted, err := crypto.Encrypt(apiKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt API key: %w", err)
+		}
+		apiKey = encrypted
+	}
+
+	now := time.Now().Unix()
+	if profile.ID > 0 {
+		result, err := db.Exec(`
+			UPDATE ai_profiles SET name = ?, endpoint = ?, model = ?, api_key = ?, custom_headers = ?,
+				usage_limit = ?, enabled = ?, updated_at = ?
+			WHERE id = ?`,
+			name, profile.Endpoint, profile.Model, apiKey, profile.CustomHeaders,
+			profile.UsageLimit, profile.Enabled, now, profile.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update AI profile: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return 0, sql.ErrNoRows
+		}
+		return profile.ID, nil
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO ai_profiles (name, endpoint, model, api_key, custom_headers, usage_limit, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		name, profile.Endpoint, profile.Model, apiKey, profile.CustomHeaders,
+		profile.UsageLimit, profile.Enabled, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create AI profile: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// GetAIProfiles returns all AI profiles ordered by name
+func (db *DB) GetAIProfiles() ([]AIProfile, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT id, name, endpoint, model, api_key, custom_headers, usage_limit, usage_tokens,
+			enabled, created_at, updated_at
+		FROM ai_profiles
+		ORDER BY name ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := make([]AIProfile, 0)
+	for rows.Next() {
+		profile, err := scanAIProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, *profile)
+	}
+	return profiles, rows.Err()
+}
+
+// GetAIProfile returns a single AI profile, or sql.ErrNoRows if it doesn't exist
+func (db *DB) GetAIProfile(id int64) (*AIProfile, error) {
+	db.WaitForReady()
+	row := db.QueryRow(`
+		SELECT id, name, endpoint, model, api_key, custom_headers, usage_limit, usage_tokens,
+			enabled, created_at, updated_at
+		FROM ai_profiles
+		WHERE id = ?`, id)
+	return scanAIProfile(row)
+}
+
+// DeleteAIProfile removes an AI profile and its routes
+func (db *DB) DeleteAIProfile(id int64) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM ai_profile_routes WHERE profile_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM ai_profiles WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// AddAIProfileUsage adds tokens to a profile's usage counter
+func (db *DB) AddAIProfileUsage(id, tokens int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE ai_profiles SET usage_tokens = usage_tokens + ? WHERE id = ?`, tokens, id)
+	return err
+}
+
+// ResetAIProfileUsage resets a profile's usage counter to zero
+func (db *DB) ResetAIProfileUsage(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE ai_profiles SET usage_tokens = 0 WHERE id = ?`, id)
+	return err
+}
+
+// GetAIRoutes returns the profile IDs routed to each task, in failover order
+func (db *DB) GetAIRoutes() (map[string][]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT task, profile_id FROM ai_profile_routes ORDER BY task, position`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI routes: %w", err)
+	}
+	defer rows.Close()
+
+	routes := make(map[string][]int64)
+	for rows.Next() {
+		var task string
+		var profileID int64
+		if err := rows.Scan(&task, &profileID); err != nil {
+			return nil, err
+		}
+		routes[task] = append(routes[task], profileID)
+	}
+	return routes, rows.Err()
+}
+
+// GetAIRoute returns the profile IDs routed to a task, in failover order
+func (db *DB) GetAIRoute(task string) ([]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT profile_id FROM ai_profile_routes WHERE task = ? ORDER BY position`, task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI route: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// SetAIRoute replaces the profiles routed to a task. An empty list removes the route.
+func (db *DB) SetAIRoute(task string, profileIDs []int64) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM ai_profile_routes WHERE task = ?`, task); err != nil {
+		return fmt.Errorf("failed to clear AI route: %w", err)
+	}
+	for i, id := range profileIDs {
+		if _, err := tx.Exec(`INSERT INTO ai_profile_routes (task, position, profile_id) VALUES (?, ?, ?)`, task, i, id); err != nil {
+			return fmt.Errorf("failed to save AI route: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// scanAIProfile scans a profile row and decrypts its API key
+func scanAIProfile(row interface{ Scan(...interface{}) error }) (*AIProfile, error) {
+	var profile AIProfile
+	var createdAt, updatedAt int64
+	if err := row.Scan(
+		&profile.ID, &profile.Name, &profile.Endpoint, &profile.Model, &profile.APIKey, &profile.CustomHeaders,
+		&profile.UsageLimit, &profile.UsageTokens, &profile.Enabled, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	profile.CreatedAt = time.Unix(createdAt, 0)
+	profile.UpdatedAt = time.Unix(updatedAt, 0)
+
+	if crypto.IsEncrypted(profile.APIKey) {
+		decrypted, err := crypto.Decrypt(profile.APIKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt API key for %s: %w", profile.Name, err)
+		}
+		profile.APIKey = decrypted
+	}
+	return &profile, nil
+}
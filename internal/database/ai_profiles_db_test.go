@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"MrRSS/internal/crypto"
+)
+
+func TestAIProfiles_SaveAndUsage(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	id, err := db.SaveAIProfile(&AIProfile{Name: " Local ", Endpoint: "http://localhost:11434", Model: "llama3", APIKey: "secret", UsageLimit: 100, Enabled: true})
+	if err != nil {
+		t.Fatalf("SaveAIProfile error: %v", err)
+	}
+
+	var stored string
+	if err := db.QueryRow(`SELECT api_key FROM ai_profiles WHERE id = ?`, id).Scan(&stored); err != nil {
+		t.Fatalf("query error: %v", err)
+	}
+	if !crypto.IsEncrypted(stored) {
+		t.Error("API key should be stored encrypted")
+	}
+
+	if err := db.AddAIProfileUsage(id, 60); err != nil {
+		t.Fatalf("AddAIProfileUsage error: %v", err)
+	}
+	if err := db.AddAIProfileUsage(id, 50); err != nil {
+		t.Fatalf("AddAIProfileUsage error: %v", err)
+	}
+
+	profile, err := db.GetAIProfile(id)
+	if err != nil {
+		t.Fatalf("GetAIProfile error: %v", err)
+	}
+	if profile.Name != "Local" || profile.APIKey != "secret" || profile.UsageTokens != 110 || !profile.LimitReached() {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+
+	// Updates keep the usage counter
+	profile.UsageLimit = 0
+	if _, err := db.SaveAIProfile(profile); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	profile, _ = db.GetAIProfile(id)
+	if profile.UsageTokens != 110 || profile.LimitReached() {
+		t.Errorf("unexpected profile after update: %+v", profile)
+	}
+
+	if _, err := db.SaveAIProfile(&AIProfile{ID: 999, Name: "Missing"}); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows updating a missing profile, got %v", err)
+	}
+	if _, err := db.SaveAIProfile(&AIProfile{Name: "  "}); err == nil {
+		t.Error("expected an error for an empty name")
+	}
+}
+
+func TestAIProfiles_Routes(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	local, _ := db.SaveAIProfile(&AIProfile{Name: "Local", Enabled: true})
+	cloud, _ := db.SaveAIProfile(&AIProfile{Name: "Cloud", Enabled: true})
+
+	if err := db.SetAIRoute("chat", []int64{cloud, local}); err != nil {
+		t.Fatalf("SetAIRoute error: %v", err)
+	}
+	if err := db.SetAIRoute("translation", []int64{local}); err != nil {
+		t.Fatalf("SetAIRoute error: %v", err)
+	}
+
+	route, err := db.GetAIRoute("chat")
+	if err != nil || len(route) != 2 || route[0] != cloud || route[1] != local {
+		t.Fatalf("GetAIRoute = %v, %v", route, err)
+	}
+
+	// Deleting a profile removes it from all routes
+	if err := db.DeleteAIProfile(local); err != nil {
+		t.Fatalf("DeleteAIProfile error: %v", err)
+	}
+	routes, err := db.GetAIRoutes()
+	if err != nil {
+		t.Fatalf("GetAIRoutes error: %v", err)
+	}
+	if len(routes["chat"]) != 1 || routes["chat"][0] != cloud || len(routes["translation"]) != 0 {
+		t.Errorf("unexpected routes after delete: %v", routes)
+	}
+
+	if err := db.SetAIRoute("chat", nil); err != nil {
+		t.Fatalf("SetAIRoute error: %v", err)
+	}
+	if route, _ := db.GetAIRoute("chat"); len(route) != 0 {
+		t.Errorf("expected the route to be removed, got %v", route)
+	}
+}
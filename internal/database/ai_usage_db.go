@@ -0,0 +1,225 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AIUsageRecord is one entry of the AI usage ledger
+type AIUsageRecord struct {
+	ID           int64     `json:"id"`
+	Feature      string    `json:"feature"`  // "chat", "summary", "translation"
+	Provider     string    `json:"provider"` // e.g. "openai", "ollama", or the endpoint host
+	Model        string    `json:"model"`
+	Profile      string    `json:"profile"` // Name of the AI profile that served the request
+	InputTokens  int64     `json:"input_tokens"`
+	OutputTokens int64     `json:"output_tokens"`
+	Estimated    bool      `json:"estimated"`  // Token counts are heuristic, the provider reported none
+	Cost         float64   `json:"cost"`       // Estimated cost in USD
+	EventDate    string    `json:"event_date"` // Format: YYYY-MM-DD (local time)
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AIUsageBreakdown aggregates ledger entries of one feature, provider and model
+type AIUsageBreakdown struct {
+	Feature      string  `json:"feature"`
+	Provider     string  `json:"provider"`
+	Model        string  `json:"model"`
+	Calls        int64   `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
+}
+
+// AIBudget limits the AI usage of a feature per day or month.
+// Feature "all" applies to the combined usage of every feature.
+type AIBudget struct {
+	Feature   string  `json:"feature"`
+	Period    string  `json:"period"`     // "daily" or "monthly"
+	MaxTokens int64   `json:"max_tokens"` // 0 for no token budget
+	MaxCost   float64 `json:"max_cost"`   // 0 for no cost budget (USD)
+}
+
+// Budget periods
+const (
+	AIBudgetDaily   = "daily"
+	AIBudgetMonthly = "monthly"
+)
+
+// AIBudgetAllFeatures is the budget feature that covers all features together
+const AIBudgetAllFeatures = "all"
+
+// InitAIUsageTables creates the ai_usage_ledger and ai_budgets tables if they don't exist
+func InitAIUsageTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS ai_usage_ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feature TEXT NOT NULL,
+		provider TEXT DEFAULT '',
+		model TEXT DEFAULT '',
+		profile TEXT DEFAULT '',
+		input_tokens INTEGER DEFAULT 0,
+		output_tokens INTEGER DEFAULT 0,
+		estimated BOOLEAN DEFAULT 0,
+		cost REAL DEFAULT 0,
+		event_date TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_ai_usage_ledger_date ON ai_usage_ledger(event_date, feature);
+
+	CREATE TABLE IF NOT EXISTS ai_budgets (
+		feature TEXT NOT NULL,
+		period TEXT NOT NULL,
+		max_tokens INTEGER DEFAULT 0,
+		max_cost REAL DEFAULT 0,
+		PRIMARY KEY (feature, period)
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// AddAIUsageRecord appends an entry to the AI usage ledger.
+// EventDate and CreatedAt default to the current time.
+func (db *DB) AddAIUsageRecord(record *AIUsageRecord) error {
+	db.WaitForReady()
+
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	if record.EventDate == "" {
+		record.EventDate = record.CreatedAt.Format("2006-01-02")
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO ai_usage_ledger (feature, provider, model, profile, input_tokens, output_tokens,
+			estimated, cost, event_date, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		record.Feature, record.Provider, record.Model, record.Profile, record.InputTokens, record.OutputTokens,
+		record.Estimated, record.Cost, record.EventDate, record.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	record.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// GetAIUsageTotals returns the tokens and cost recorded for a feature from startDate (YYYY-MM-DD) on.
+// Feature AIBudgetAllFeatures sums all features.
+func (db *DB) GetAIUsageTotals(feature, startDate string) (int64, float64, error) {
+	db.WaitForReady()
+
+	query := `
+	SELECT COALESCE(SUM(input_tokens + output_tokens), 0), COALESCE(SUM(cost), 0)
+	FROM ai_usage_ledger
+	WHERE event_date >= ? AND (? = ? OR feature = ?)
+	`
+	var tokens int64
+	var cost float64
+	err := db.QueryRow(query, startDate, feature, AIBudgetAllFeatures, feature).Scan(&tokens, &cost)
+	return tokens, cost, err
+}
+
+// GetAIUsageBreakdown aggregates the ledger by feature, provider and model for a date range.
+// Empty dates leave the range open.
+func (db *DB) GetAIUsageBreakdown(startDate, endDate string) ([]AIUsageBreakdown, error) {
+	db.WaitForReady()
+
+	query := `
+	SELECT feature, provider, model, COUNT(*), SUM(input_tokens), SUM(output_tokens), SUM(cost)
+	FROM ai_usage_ledger
+	WHERE (? = '' OR event_date >= ?) AND (? = '' OR event_date <= ?)
+	GROUP BY feature, provider, model
+	ORDER BY SUM(input_tokens + output_tokens) DESC
+	`
+	rows, err := db.Query(query, startDate, startDate, endDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	breakdown := make([]AIUsageBreakdown, 0)
+	for rows.Next() {
+		var b AIUsageBreakdown
+		if err := rows.Scan(&b.Feature, &b.Provider, &b.Model, &b.Calls, &b.InputTokens, &b.OutputTokens, &b.Cost); err != nil {
+			return nil, err
+		}
+		breakdown = append(breakdown, b)
+	}
+	return breakdown, rows.Err()
+}
+
+// GetDailyAIUsage returns the tokens used per day and feature for a date range.
+// Empty dates leave the range open.
+func (db *DB) GetDailyAIUsage(startDate, endDate string) (map[string]map[string]int64, error) {
+	db.WaitForReady()
+
+	query := `
+	SELECT event_date, feature, SUM(input_tokens + output_tokens)
+	FROM ai_usage_ledger
+	WHERE (? = '' OR event_date >= ?) AND (? = '' OR event_date <= ?)
+	GROUP BY event_date, feature
+	`
+	rows, err := db.Query(query, startDate, startDate, endDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Result structure: map[date][feature]tokens
+	result := make(map[string]map[string]int64)
+	for rows.Next() {
+		var eventDate, feature string
+		var tokens int64
+		if err := rows.Scan(&eventDate, &feature, &tokens); err != nil {
+			return nil, err
+		}
+		if result[eventDate] == nil {
+			result[eventDate] = make(map[string]int64)
+		}
+		result[eventDate][feature] = tokens
+	}
+	return result, rows.Err()
+}
+
+// GetAIBudgets returns all AI budgets
+func (db *DB) GetAIBudgets() ([]AIBudget, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT feature, period, max_tokens, max_cost FROM ai_budgets ORDER BY feature, period`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI budgets: %w", err)
+	}
+	defer rows.Close()
+
+	budgets := make([]AIBudget, 0)
+	for rows.Next() {
+		var b AIBudget
+		if err := rows.Scan(&b.Feature, &b.Period, &b.MaxTokens, &b.MaxCost); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+// SetAIBudget creates or replaces a budget. A budget without limits is removed.
+func (db *DB) SetAIBudget(budget AIBudget) error {
+	db.WaitForReady()
+
+	if budget.MaxTokens <= 0 && budget.MaxCost <= 0 {
+		_, err := db.Exec(`DELETE FROM ai_budgets WHERE feature = ? AND period = ?`, budget.Feature, budget.Period)
+		return err
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO ai_budgets (feature, period, max_tokens, max_cost)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(feature, period) DO UPDATE SET max_tokens = excluded.max_tokens, max_cost = excluded.max_cost`,
+		budget.Feature, budget.Period, budget.MaxTokens, budget.MaxCost)
+	if err != nil {
+		return fmt.Errorf("failed to save AI budget: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAIUsageLedger_TotalsAndBreakdown(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	records := []AIUsageRecord{
+		{Feature: "chat", Provider: "openai", Model: "gpt-4o-mini", InputTokens: 100, OutputTokens: 50, Cost: 0.01},
+		{Feature: "chat", Provider: "openai", Model: "gpt-4o-mini", InputTokens: 20, OutputTokens: 10, Cost: 0.002},
+		{Feature: "translation", Provider: "ollama", Model: "llama3", InputTokens: 300, OutputTokens: 300, Estimated: true},
+		{Feature: "chat", Provider: "openai", Model: "gpt-4o-mini", InputTokens: 1000, OutputTokens: 0, Cost: 0.1, CreatedAt: yesterday},
+	}
+	for i := range records {
+		if err := db.AddAIUsageRecord(&records[i]); err != nil {
+			t.Fatalf("AddAIUsageRecord error: %v", err)
+		}
+	}
+
+	today := time.Now().Format("2006-01-02")
+	tokens, cost, err := db.GetAIUsageTotals("chat", today)
+	if err != nil {
+		t.Fatalf("GetAIUsageTotals error: %v", err)
+	}
+	if tokens != 180 || cost < 0.0119 || cost > 0.0121 {
+		t.Errorf("unexpected chat totals for today: %d tokens, %f cost", tokens, cost)
+	}
+	if tokens, _, _ := db.GetAIUsageTotals(AIBudgetAllFeatures, today); tokens != 780 {
+		t.Errorf("expected 780 tokens for all features, got %d", tokens)
+	}
+
+	breakdown, err := db.GetAIUsageBreakdown("", "")
+	if err != nil {
+		t.Fatalf("GetAIUsageBreakdown error: %v", err)
+	}
+	if len(breakdown) != 2 || breakdown[0].Feature != "chat" || breakdown[0].Calls != 3 || breakdown[0].InputTokens != 1120 {
+		t.Errorf("unexpected breakdown: %+v", breakdown)
+	}
+
+	daily, err := db.GetDailyAIUsage(yesterday.Format("2006-01-02"), today)
+	if err != nil {
+		t.Fatalf("GetDailyAIUsage error: %v", err)
+	}
+	if daily[today]["translation"] != 600 || daily[yesterday.Format("2006-01-02")]["chat"] != 1000 {
+		t.Errorf("unexpected daily usage: %v", daily)
+	}
+}
+
+func TestAIBudgets_SetAndRemove(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	if err := db.SetAIBudget(AIBudget{Feature: "chat", Period: AIBudgetDaily, MaxTokens: 1000}); err != nil {
+		t.Fatalf("SetAIBudget error: %v", err)
+	}
+	if err := db.SetAIBudget(AIBudget{Feature: "chat", Period: AIBudgetDaily, MaxCost: 2.5}); err != nil {
+		t.Fatalf("SetAIBudget update error: %v", err)
+	}
+
+	budgets, err := db.GetAIBudgets()
+	if err != nil {
+		t.Fatalf("GetAIBudgets error: %v", err)
+	}
+	if len(budgets) != 1 || budgets[0].MaxTokens != 0 || budgets[0].MaxCost != 2.5 {
+		t.Errorf("unexpected budgets: %+v", budgets)
+	}
+
+	if err := db.SetAIBudget(AIBudget{Feature: "chat", Period: AIBudgetDaily}); err != nil {
+		t.Fatalf("SetAIBudget remove error: %v", err)
+	}
+	if budgets, _ := db.GetAIBudgets(); len(budgets) != 0 {
+		t.Errorf("expected the empty budget to be removed, got %+v", budgets)
+	}
+}
@@ -39,6 +39,49 @@ func (db *DB) SetArticleContent(articleID int64, content string) error {
 	return err
 }
 
+// SetArticleFullText stores content extracted from the article's original page.
+// Full text is kept when the feed is refreshed, see HasArticleFullText.
+func (db *DB) SetArticleFullText(articleID int64, content string) error {
+	db.WaitForReady()
+	_, err := db.Exec(
+		`INSERT OR REPLACE INTO article_contents (article_id, content, fetched_at, is_full_text)
+		 VALUES (?, ?, CURRENT_TIMESTAMP, 1)`,
+		articleID, content,
+	)
+	return err
+}
+
+// HasArticleFullText reports whether the cached content of an article is extracted full text
+func (db *DB) HasArticleFullText(articleID int64) (bool, error) {
+	db.WaitForReady()
+	var isFullText bool
+	err := db.QueryRow(
+		`SELECT COALESCE(is_full_text, 0) FROM article_contents WHERE article_id = ?`,
+		articleID,
+	).Scan(&isFullText)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return isFullText, err
+}
+
+// GetArticleFullText returns the cached full text of an article, if any
+func (db *DB) GetArticleFullText(articleID int64) (string, bool, error) {
+	db.WaitForReady()
+	var content string
+	err := db.QueryRow(
+		`SELECT content FROM article_contents WHERE article_id = ? AND is_full_text = 1`,
+		articleID,
+	).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return content, true, nil
+}
+
 // DeleteArticleContent removes cached content for an article
 func (db *DB) DeleteArticleContent(articleID int64) error {
 	db.WaitForReady()
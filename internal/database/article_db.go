@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"MrRSS/internal/metrics"
 	"MrRSS/internal/models"
 	"MrRSS/internal/utils"
 )
@@ -17,12 +18,13 @@ func (db *DB) SaveArticle(article *models.Article) error {
 
 	// Generate unique_id for deduplication
 	uniqueID := utils.GenerateArticleUniqueID(article.Title, article.FeedID, article.PublishedAt, article.HasValidPublishedTime)
-	query := `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(query, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID)
+	query := `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id, language, language_confidence) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID, article.Language, article.LanguageConfidence)
 	return err
 }
 
-// SaveArticles saves multiple articles in a transaction.
+// SaveArticles saves multiple articles in a transaction, setting the ID of the articles inserted.
+// Articles already saved are ignored and keep their ID.
 // Includes progressive cleanup check to prevent database from exceeding size limit during refresh.
 func (db *DB) SaveArticles(ctx context.Context, articles []*models.Article) error {
 	db.WaitForReady()
@@ -50,12 +52,13 @@ func (db *DB) SaveArticles(ctx context.Context, articles []*models.Article) erro
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id, language, language_confidence) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
+	var inserted int64
 	for _, article := range articles {
 		// Check context before each insert
 		select {
@@ -66,21 +69,45 @@ func (db *DB) SaveArticles(ctx context.Context, articles []*models.Article) erro
 
 		// Generate unique_id for deduplication
 		uniqueID := utils.GenerateArticleUniqueID(article.Title, article.FeedID, article.PublishedAt, article.HasValidPublishedTime)
-		_, err := stmt.ExecContext(ctx, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID)
+		result, err := stmt.ExecContext(ctx, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID, article.Language, article.LanguageConfidence)
 		if err != nil {
 			log.Println("Error saving article in batch:", err)
 			// Continue even if one fails
+			continue
+		}
+		// INSERT OR IGNORE affects zero rows for duplicates
+		if n, err := result.RowsAffected(); err == nil && n > 0 {
+			inserted += n
+			if id, err := result.LastInsertId(); err == nil {
+				article.ID = id
+			}
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	metrics.ArticlesSavedTotal.Add(float64(inserted))
+	return nil
 }
 
-// GetArticles retrieves articles with filtering, pagination, and sorting.
+// Article sort orders of GetArticlesSorted
+const (
+	SortByDate      = "date"      // Newest first
+	SortByRelevance = "relevance" // Highest relevance score first, unscored articles last
+)
+
+// GetArticles retrieves articles with filtering and pagination, newest first.
 func (db *DB) GetArticles(filter string, feedID int64, category string, showHidden bool, limit, offset int) ([]models.Article, error) {
+	return db.GetArticlesSorted(filter, feedID, category, showHidden, SortByDate, limit, offset)
+}
+
+// GetArticlesSorted retrieves articles with filtering, pagination, and sorting.
+func (db *DB) GetArticlesSorted(filter string, feedID int64, category string, showHidden bool, sort string, limit, offset int) ([]models.Article, error) {
 	db.WaitForReady()
 	baseQuery := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.relevance_score, COALESCE(a.language, ''), COALESCE(a.language_confidence, 0), f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 	`
@@ -128,7 +155,11 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 			query += " AND " + whereClauses[i]
 		}
 	}
-	query += " ORDER BY a.published_at DESC LIMIT ? OFFSET ?"
+	if sort == SortByRelevance {
+		query += " ORDER BY a.relevance_score IS NULL, a.relevance_score DESC, a.published_at DESC LIMIT ? OFFSET ?"
+	} else {
+		query += " ORDER BY a.published_at DESC LIMIT ? OFFSET ?"
+	}
 	args = append(args, limit, offset)
 
 	rows, err := db.Query(query, args...)
@@ -141,8 +172,9 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 	for rows.Next() {
 		var a models.Article
 		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+		var relevanceScore sql.NullFloat64
 		var publishedAt sql.NullTime
-		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle); err != nil {
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &relevanceScore, &a.Language, &a.LanguageConfidence, &a.FeedTitle); err != nil {
 			log.Println("Error scanning article:", err)
 			continue
 		}
@@ -157,6 +189,9 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 		a.TranslatedTitle = translatedTitle.String
 		a.Summary = summary.String
 		a.FreshRSSItemID = freshrssItemID.String
+		if relevanceScore.Valid {
+			a.RelevanceScore = &relevanceScore.Float64
+		}
 		articles = append(articles, a)
 	}
 	return articles, nil
@@ -167,7 +202,7 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 	db.WaitForReady()
 	query := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.relevance_score, COALESCE(a.language, ''), COALESCE(a.language_confidence, 0), f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 		WHERE a.id = ?
@@ -176,8 +211,9 @@ func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 
 	var a models.Article
 	var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+	var relevanceScore sql.NullFloat64
 	var publishedAt sql.NullTime
-	if err := row.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle); err != nil {
+	if err := row.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &relevanceScore, &a.Language, &a.LanguageConfidence, &a.FeedTitle); err != nil {
 		return nil, err
 	}
 	a.ImageURL = imageURL.String
@@ -191,6 +227,9 @@ func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 	a.TranslatedTitle = translatedTitle.String
 	a.Summary = summary.String
 	a.FreshRSSItemID = freshrssItemID.String
+	if relevanceScore.Valid {
+		a.RelevanceScore = &relevanceScore.Float64
+	}
 	return &a, nil
 }
 
@@ -210,7 +249,7 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 	}
 
 	query := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.relevance_score, COALESCE(a.language, ''), COALESCE(a.language_confidence, 0), f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 		WHERE a.id IN (` + strings.Join(placeholders, ",") + `)
@@ -226,9 +265,10 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 	for rows.Next() {
 		var a models.Article
 		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+		var relevanceScore sql.NullFloat64
 		var publishedAt sql.NullTime
 
-		err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle)
+		err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &relevanceScore, &a.Language, &a.LanguageConfidence, &a.FeedTitle)
 		if err != nil {
 			return nil, err
 		}
@@ -244,6 +284,9 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 		a.TranslatedTitle = translatedTitle.String
 		a.Summary = summary.String
 		a.FreshRSSItemID = freshrssItemID.String
+		if relevanceScore.Valid {
+			a.RelevanceScore = &relevanceScore.Float64
+		}
 
 		articles = append(articles, a)
 	}
@@ -297,17 +340,23 @@ func (db *DB) UpdateArticleTranslation(id int64, translatedTitle string) error {
 	return err
 }
 
-// ClearAllTranslations clears all translated titles from articles.
+// ClearAllTranslations clears all translated titles and bodies of articles.
 func (db *DB) ClearAllTranslations() error {
 	db.WaitForReady()
-	_, err := db.Exec("UPDATE articles SET translated_title = ''")
+	if _, err := db.Exec("UPDATE articles SET translated_title = ''"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DELETE FROM article_translations")
 	return err
 }
 
 // ClearAllSummaries clears all summaries from articles.
 func (db *DB) ClearAllSummaries() error {
 	db.WaitForReady()
-	_, err := db.Exec("UPDATE articles SET summary = ''")
+	if _, err := db.Exec("UPDATE articles SET summary = ''"); err != nil {
+		return err
+	}
+	_, err := db.Exec("DELETE FROM article_summaries")
 	return err
 }
 
@@ -102,6 +102,47 @@ func (db *DB) SetArticleFavoriteWithSync(id int64, favorite bool) (*SyncRequest,
 	return nil, nil
 }
 
+// SetArticleReadLaterWithSync sets the read later status and returns sync request if FreshRSS is
+// enabled, as adding an article to read later marks it unread
+func (db *DB) SetArticleReadLaterWithSync(id int64, readLater bool) (*SyncRequest, error) {
+	// Get article URL and feed_id first
+	var url string
+	var feedID int64
+	err := db.QueryRow("SELECT url, feed_id FROM articles WHERE id = ?", id).Scan(&url, &feedID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set read later
+	err = db.SetArticleReadLater(id, readLater)
+	if err != nil {
+		return nil, err
+	}
+	if !readLater {
+		return nil, nil
+	}
+
+	// Check if this article belongs to a FreshRSS feed
+	var isFreshRSSFeed bool
+	err = db.QueryRow("SELECT COALESCE(is_freshrss_source, 0) FROM feeds WHERE id = ?", feedID).Scan(&isFreshRSSFeed)
+	if err != nil {
+		return nil, err
+	}
+
+	// Return sync request only if FreshRSS is enabled and this is a FreshRSS feed
+	enabled, _ := db.GetSetting("freshrss_enabled")
+	if enabled == "true" && isFreshRSSFeed {
+		log.Printf("[FreshRSS Sync] Article %d needs sync: %s", id, SyncActionMarkUnread)
+		return &SyncRequest{
+			ArticleID:  id,
+			ArticleURL: url,
+			Action:     SyncActionMarkUnread,
+		}, nil
+	}
+
+	return nil, nil
+}
+
 // ToggleFavoriteWithSync toggles favorite status and returns sync request if FreshRSS is enabled
 func (db *DB) ToggleFavoriteWithSync(id int64) (*SyncRequest, error) {
 	// Get current state, URL, and feed_id
@@ -224,6 +224,31 @@ func TestSaveArticlesBatchContextCancel(t *testing.T) {
 	}
 }
 
+func TestSaveArticlesSetsInsertedIDs(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	_ = db.QueryRow(`SELECT id FROM feeds WHERE url = ?`, "https://example.com/feed").Scan(&feedID)
+
+	// A back-dated article is inserted like any other
+	old := &models.Article{FeedID: feedID, Title: "Old", URL: "https://example.com/old", PublishedAt: time.Now().AddDate(-1, 0, 0)}
+	if err := db.SaveArticles(context.Background(), []*models.Article{old}); err != nil {
+		t.Fatalf("SaveArticles error: %v", err)
+	}
+	if old.ID == 0 {
+		t.Fatal("expected the inserted article to get its ID")
+	}
+
+	again := &models.Article{FeedID: feedID, Title: "Old", URL: "https://example.com/old", PublishedAt: old.PublishedAt}
+	recent := &models.Article{FeedID: feedID, Title: "New", URL: "https://example.com/new", PublishedAt: time.Now()}
+	if err := db.SaveArticles(context.Background(), []*models.Article{again, recent}); err != nil {
+		t.Fatalf("SaveArticles error: %v", err)
+	}
+	if again.ID != 0 || recent.ID == 0 || recent.ID == old.ID {
+		t.Errorf("expected only the new article to get an ID, got %d and %d", again.ID, recent.ID)
+	}
+}
+
 func TestArticleDeduplicationByUniqueID(t *testing.T) {
 	db := setupDBWithFeed(t)
 
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SummaryVariant identifies one of the summaries of an article
+type SummaryVariant struct {
+	Style    string `json:"style"`    // "standard", "tldr", "bullets", "quotes" or "eli5"
+	Length   string `json:"length"`   // "short", "medium" or "long"
+	Language string `json:"language"` // Language the summary is written in, empty if unknown
+	Provider string `json:"provider"` // "local", "ai", or "ai#<template ID>" with a prompt template
+}
+
+// ArticleSummary is a summary of an article in one variant
+type ArticleSummary struct {
+	ArticleID int64 `json:"article_id"`
+	SummaryVariant
+	ContentHash string    `json:"content_hash"` // Hash of the article content that was summarized
+	Summary     string    `json:"summary"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// InitArticleSummariesTable creates the article summaries table if it doesn't exist
+func InitArticleSummariesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS article_summaries (
+		article_id INTEGER NOT NULL,
+		style TEXT NOT NULL,
+		length TEXT NOT NULL,
+		language TEXT NOT NULL DEFAULT '',
+		provider TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(article_id, style, length, language, provider),
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// GetArticleSummary returns the stored summary of an article in a variant, or nil if there is none
+func (db *DB) GetArticleSummary(articleID int64, variant SummaryVariant) (*ArticleSummary, error) {
+	db.WaitForReady()
+	s := &ArticleSummary{ArticleID: articleID, SummaryVariant: variant}
+	var updatedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT content_hash, summary, updated_at
+		FROM article_summaries
+		WHERE article_id = ? AND style = ? AND length = ? AND language = ? AND provider = ?`,
+		articleID, variant.Style, variant.Length, variant.Language, variant.Provider).
+		Scan(&s.ContentHash, &s.Summary, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.UpdatedAt = updatedAt.Time
+	return s, nil
+}
+
+// GetArticleSummaries returns the stored summaries of an article, most recent first
+func (db *DB) GetArticleSummaries(articleID int64) ([]ArticleSummary, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT style, length, language, provider, content_hash, summary, updated_at
+		FROM article_summaries
+		WHERE article_id = ?
+		ORDER BY updated_at DESC, style`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []ArticleSummary{}
+	for rows.Next() {
+		s := ArticleSummary{ArticleID: articleID}
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&s.Style, &s.Length, &s.Language, &s.Provider, &s.ContentHash, &s.Summary, &updatedAt); err != nil {
+			return nil, err
+		}
+		s.UpdatedAt = updatedAt.Time
+		summaries = append(summaries, s)
+	}
+	return summaries, rows.Err()
+}
+
+// SaveArticleSummary stores or replaces the summary of an article in its variant
+func (db *DB) SaveArticleSummary(s ArticleSummary) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO article_summaries
+		(article_id, style, length, language, provider, content_hash, summary, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		s.ArticleID, s.Style, s.Length, s.Language, s.Provider, s.ContentHash, s.Summary)
+	if err != nil {
+		return fmt.Errorf("failed to save article summary: %w", err)
+	}
+	return nil
+}
+
+// PruneArticleSummaries removes the summaries of deleted articles
+func (db *DB) PruneArticleSummaries() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_summaries WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArticleSummaries(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, unique_id) VALUES (1, 'Launch', 'https://example.com/launch', ?, 'launch')`, time.Now())
+	if err != nil {
+		t.Fatalf("insert article error: %v", err)
+	}
+	articleID, _ := res.LastInsertId()
+
+	tldr := SummaryVariant{Style: "tldr", Length: "short", Language: "en", Provider: "ai"}
+	if stored, err := db.GetArticleSummary(articleID, tldr); err != nil || stored != nil {
+		t.Fatalf("expected no summary, got %+v (%v)", stored, err)
+	}
+
+	for _, s := range []ArticleSummary{
+		{ArticleID: articleID, SummaryVariant: tldr, ContentHash: "h1", Summary: "A rocket launched."},
+		{ArticleID: articleID, SummaryVariant: tldr, ContentHash: "h2", Summary: "A rocket launched at dawn."},
+		{ArticleID: articleID, SummaryVariant: SummaryVariant{Style: "tldr", Length: "short", Language: "en", Provider: "local"}, ContentHash: "h2", Summary: "Launch."},
+		{ArticleID: articleID, SummaryVariant: SummaryVariant{Style: "bullets", Length: "short", Language: "en", Provider: "ai"}, ContentHash: "h2", Summary: "- Rocket\n- Dawn"},
+	} {
+		if err := db.SaveArticleSummary(s); err != nil {
+			t.Fatalf("SaveArticleSummary error: %v", err)
+		}
+	}
+
+	stored, err := db.GetArticleSummary(articleID, tldr)
+	if err != nil || stored == nil || stored.ContentHash != "h2" || stored.Summary != "A rocket launched at dawn." {
+		t.Fatalf("expected the replaced summary, got %+v (%v)", stored, err)
+	}
+	if stored, err := db.GetArticleSummary(articleID, SummaryVariant{Style: "tldr", Length: "long", Language: "en", Provider: "ai"}); err != nil || stored != nil {
+		t.Errorf("expected no summary of another length, got %+v (%v)", stored, err)
+	}
+	if summaries, err := db.GetArticleSummaries(articleID); err != nil || len(summaries) != 3 {
+		t.Errorf("expected a summary per variant, got %+v (%v)", summaries, err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, articleID); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if removed, err := db.PruneArticleSummaries(); err != nil || removed != 3 {
+		t.Errorf("expected the deleted article's summaries to be pruned, got %d (%v)", removed, err)
+	}
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ArticleTranslation is the translated body of an article in one language
+type ArticleTranslation struct {
+	ArticleID  int64
+	TargetLang string
+	SourceHash string // Hash of the article content that was translated
+	Provider   string // Translation provider, "ai#<template ID>" for AI translations with a prompt template
+	Content    string // Body with each segment replaced by its translation
+	Bilingual  string // Body with each segment followed by its translation
+	UpdatedAt  time.Time
+}
+
+// InitArticleTranslationsTable creates the article translations table if it doesn't exist
+func InitArticleTranslationsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS article_translations (
+		article_id INTEGER NOT NULL,
+		target_lang TEXT NOT NULL,
+		source_hash TEXT NOT NULL,
+		provider TEXT NOT NULL DEFAULT '',
+		content TEXT NOT NULL,
+		bilingual TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(article_id, target_lang),
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// GetArticleTranslation returns the stored translation of an article's body, or nil if there is none
+func (db *DB) GetArticleTranslation(articleID int64, targetLang string) (*ArticleTranslation, error) {
+	db.WaitForReady()
+	t := &ArticleTranslation{ArticleID: articleID, TargetLang: targetLang}
+	var updatedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT source_hash, provider, content, bilingual, updated_at
+		FROM article_translations
+		WHERE article_id = ? AND target_lang = ?`, articleID, targetLang).
+		Scan(&t.SourceHash, &t.Provider, &t.Content, &t.Bilingual, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.UpdatedAt = updatedAt.Time
+	return t, nil
+}
+
+// SaveArticleTranslation stores or replaces the translation of an article's body
+func (db *DB) SaveArticleTranslation(t ArticleTranslation) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO article_translations
+		(article_id, target_lang, source_hash, provider, content, bilingual, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		t.ArticleID, t.TargetLang, t.SourceHash, t.Provider, t.Content, t.Bilingual)
+	if err != nil {
+		return fmt.Errorf("failed to save article translation: %w", err)
+	}
+	return nil
+}
+
+// PruneArticleTranslations removes the translated bodies of deleted articles
+func (db *DB) PruneArticleTranslations() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_translations WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
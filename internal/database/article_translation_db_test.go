@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArticleTranslations(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, unique_id) VALUES (1, 'Launch', 'https://example.com/launch', ?, 'launch')`, time.Now())
+	if err != nil {
+		t.Fatalf("insert article error: %v", err)
+	}
+	articleID, _ := res.LastInsertId()
+
+	if stored, err := db.GetArticleTranslation(articleID, "zh"); err != nil || stored != nil {
+		t.Fatalf("expected no translation, got %+v (%v)", stored, err)
+	}
+
+	translation := ArticleTranslation{
+		ArticleID: articleID, TargetLang: "zh", SourceHash: "h1", Provider: "google",
+		Content: "<p>发射</p>", Bilingual: `<p>Launch</p><div class="translation-text">发射</div>`,
+	}
+	if err := db.SaveArticleTranslation(translation); err != nil {
+		t.Fatalf("SaveArticleTranslation error: %v", err)
+	}
+	translation.SourceHash, translation.Content = "h2", "<p>发射日</p>"
+	if err := db.SaveArticleTranslation(translation); err != nil {
+		t.Fatalf("SaveArticleTranslation error: %v", err)
+	}
+
+	stored, err := db.GetArticleTranslation(articleID, "zh")
+	if err != nil || stored == nil || stored.SourceHash != "h2" || stored.Content != "<p>发射日</p>" || stored.Provider != "google" {
+		t.Fatalf("expected the replaced translation, got %+v (%v)", stored, err)
+	}
+	if stored, err := db.GetArticleTranslation(articleID, "fr"); err != nil || stored != nil {
+		t.Errorf("expected no translation for another language, got %+v (%v)", stored, err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, articleID); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if removed, err := db.PruneArticleTranslations(); err != nil || removed != 1 {
+		t.Errorf("expected the deleted article's translation to be pruned, got %d (%v)", removed, err)
+	}
+}
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Background job kinds
+const (
+	JobTranslateTitle = "translate_title"
+	JobSummarize      = "summarize"
+)
+
+// Background job statuses. Finished jobs are deleted.
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobFailed  = "failed" // Out of retries, kept so the failure can be seen
+)
+
+// BackgroundJob is a queued pre-translation or pre-summarization of an article
+type BackgroundJob struct {
+	ID           int64     `json:"id"`
+	ArticleID    int64     `json:"article_id"`
+	ArticleTitle string    `json:"article_title"`
+	Kind         string    `json:"kind"`
+	Status       string    `json:"status"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"last_error,omitempty"`
+	RunAfter     time.Time `json:"run_after"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BackgroundJobCount is the number of queued jobs of one kind and status
+type BackgroundJobCount struct {
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// InitBackgroundJobsTable creates the background_jobs table if it doesn't exist
+func InitBackgroundJobsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS background_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		article_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		run_after INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL,
+		UNIQUE(article_id, kind)
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_background_jobs_due ON background_jobs(status, run_after);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// EnqueueBackgroundJobs queues a job of the given kind for each article.
+// Articles that already have a job of that kind, including a failed one, are skipped.
+// It returns the number of jobs queued.
+func (db *DB) EnqueueBackgroundJobs(kind string, articleIDs []int64) (int64, error) {
+	db.WaitForReady()
+	if len(articleIDs) == 0 {
+		return 0, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR IGNORE INTO background_jobs (article_id, kind, status, run_after, updated_at)
+		VALUES (?, ?, 'pending', ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	now := time.Now().Unix()
+	var queued int64
+	for _, id := range articleIDs {
+		result, err := stmt.Exec(id, kind, now, now)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := result.RowsAffected()
+		queued += n
+	}
+	return queued, tx.Commit()
+}
+
+// ClaimBackgroundJobs marks up to limit pending jobs that are due as running and returns them,
+// oldest first
+func (db *DB) ClaimBackgroundJobs(limit int) ([]BackgroundJob, error) {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	rows, err := tx.Query(`
+		SELECT j.id, j.article_id, COALESCE(a.title, ''), j.kind, j.status, j.attempts, j.last_error, j.run_after, j.updated_at
+		FROM background_jobs j
+		LEFT JOIN articles a ON a.id = j.article_id
+		WHERE j.status = 'pending' AND j.run_after <= ?
+		ORDER BY j.run_after, j.id
+		LIMIT ?`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := scanBackgroundJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range jobs {
+		if _, err := tx.Exec(`UPDATE background_jobs SET status = 'running', updated_at = ? WHERE id = ?`, now, jobs[i].ID); err != nil {
+			return nil, err
+		}
+		jobs[i].Status = JobRunning
+		jobs[i].UpdatedAt = time.Unix(now, 0)
+	}
+	return jobs, tx.Commit()
+}
+
+// CompleteBackgroundJob removes a finished job from the queue
+func (db *DB) CompleteBackgroundJob(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`DELETE FROM background_jobs WHERE id = ?`, id)
+	return err
+}
+
+// RescheduleBackgroundJob puts a job back in the queue to run after runAfter, recording the
+// number of attempts made so far and why it didn't finish
+func (db *DB) RescheduleBackgroundJob(id int64, attempts int, lastError string, runAfter time.Time) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE background_jobs SET status = 'pending', attempts = ?, last_error = ?, run_after = ?, updated_at = ?
+		WHERE id = ?`, attempts, lastError, runAfter.Unix(), time.Now().Unix(), id)
+	return err
+}
+
+// FailBackgroundJob marks a job that ran out of retries as failed
+func (db *DB) FailBackgroundJob(id int64, attempts int, lastError string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE background_jobs SET status = 'failed', attempts = ?, last_error = ?, updated_at = ?
+		WHERE id = ?`, attempts, lastError, time.Now().Unix(), id)
+	return err
+}
+
+// ResetRunningBackgroundJobs returns jobs left running, by a run interrupted at shutdown,
+// to the queue. It returns the number of jobs reset.
+func (db *DB) ResetRunningBackgroundJobs() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`UPDATE background_jobs SET status = 'pending', updated_at = ? WHERE status = 'running'`, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RetryFailedBackgroundJobs returns failed jobs to the queue with their attempts reset.
+// It returns the number of jobs queued again.
+func (db *DB) RetryFailedBackgroundJobs() (int64, error) {
+	db.WaitForReady()
+	now := time.Now().Unix()
+	result, err := db.Exec(`
+		UPDATE background_jobs SET status = 'pending', attempts = 0, run_after = ?, updated_at = ?
+		WHERE status = 'failed'`, now, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetBackgroundJobCounts returns the number of queued jobs per kind and status
+func (db *DB) GetBackgroundJobCounts() ([]BackgroundJobCount, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT kind, status, COUNT(*) FROM background_jobs GROUP BY kind, status ORDER BY kind, status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]BackgroundJobCount, 0)
+	for rows.Next() {
+		var c BackgroundJobCount
+		if err := rows.Scan(&c.Kind, &c.Status, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetBackgroundJobs returns up to limit jobs with the given status, most recently updated first
+func (db *DB) GetBackgroundJobs(status string, limit int) ([]BackgroundJob, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT j.id, j.article_id, COALESCE(a.title, ''), j.kind, j.status, j.attempts, j.last_error, j.run_after, j.updated_at
+		FROM background_jobs j
+		LEFT JOIN articles a ON a.id = j.article_id
+		WHERE j.status = ?
+		ORDER BY j.updated_at DESC, j.id DESC
+		LIMIT ?`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanBackgroundJobs(rows)
+}
+
+// PruneBackgroundJobs removes the jobs of deleted articles
+func (db *DB) PruneBackgroundJobs() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM background_jobs WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// scanBackgroundJobs reads background jobs from rows and closes them
+func scanBackgroundJobs(rows *sql.Rows) ([]BackgroundJob, error) {
+	defer rows.Close()
+	jobs := make([]BackgroundJob, 0)
+	for rows.Next() {
+		var j BackgroundJob
+		var runAfter, updatedAt int64
+		if err := rows.Scan(&j.ID, &j.ArticleID, &j.ArticleTitle, &j.Kind, &j.Status, &j.Attempts, &j.LastError, &runAfter, &updatedAt); err != nil {
+			return nil, err
+		}
+		j.RunAfter = time.Unix(runAfter, 0)
+		j.UpdatedAt = time.Unix(updatedAt, 0)
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
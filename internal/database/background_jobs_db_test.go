@@ -0,0 +1,94 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestBackgroundJobs(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	var ids []int64
+	for _, url := range []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"} {
+		if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: url, URL: url, PublishedAt: time.Now()}); err != nil {
+			t.Fatalf("SaveArticle error: %v", err)
+		}
+		article, err := db.GetArticleByURL(url)
+		if err != nil {
+			t.Fatalf("GetArticleByURL error: %v", err)
+		}
+		ids = append(ids, article.ID)
+	}
+
+	queued, err := db.EnqueueBackgroundJobs(JobTranslateTitle, ids)
+	if err != nil || queued != 3 {
+		t.Fatalf("expected 3 jobs queued, got %d (%v)", queued, err)
+	}
+	if queued, _ := db.EnqueueBackgroundJobs(JobTranslateTitle, ids[:1]); queued != 0 {
+		t.Errorf("expected articles with a job to be skipped, got %d queued", queued)
+	}
+	if _, err := db.EnqueueBackgroundJobs(JobSummarize, ids[:1]); err != nil {
+		t.Fatalf("EnqueueBackgroundJobs error: %v", err)
+	}
+
+	jobs, err := db.ClaimBackgroundJobs(2)
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("expected 2 claimed jobs, got %+v (%v)", jobs, err)
+	}
+	if jobs[0].Status != JobRunning || jobs[0].ArticleTitle == "" {
+		t.Errorf("expected a running job with its article title, got %+v", jobs[0])
+	}
+
+	// A retried job waits for its delay, a failed one is not claimed again
+	if err := db.RescheduleBackgroundJob(jobs[0].ID, 1, "timeout", time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("RescheduleBackgroundJob error: %v", err)
+	}
+	if err := db.FailBackgroundJob(jobs[1].ID, 5, "bad gateway"); err != nil {
+		t.Fatalf("FailBackgroundJob error: %v", err)
+	}
+	rest, err := db.ClaimBackgroundJobs(10)
+	if err != nil || len(rest) != 2 {
+		t.Fatalf("expected the 2 remaining due jobs, got %+v (%v)", rest, err)
+	}
+
+	// Jobs left running by an interrupted run are queued again
+	if n, err := db.ResetRunningBackgroundJobs(); err != nil || n != 2 {
+		t.Errorf("expected 2 running jobs reset, got %d (%v)", n, err)
+	}
+	if err := db.CompleteBackgroundJob(rest[0].ID); err != nil {
+		t.Fatalf("CompleteBackgroundJob error: %v", err)
+	}
+
+	counts, err := db.GetBackgroundJobCounts()
+	if err != nil {
+		t.Fatalf("GetBackgroundJobCounts error: %v", err)
+	}
+	total := map[string]int64{}
+	for _, c := range counts {
+		total[c.Status] += c.Count
+	}
+	if total[JobPending] != 2 || total[JobFailed] != 1 {
+		t.Errorf("unexpected job counts: %+v", counts)
+	}
+
+	failed, err := db.GetBackgroundJobs(JobFailed, 10)
+	if err != nil || len(failed) != 1 || failed[0].LastError != "bad gateway" || failed[0].Attempts != 5 {
+		t.Errorf("unexpected failed jobs: %+v (%v)", failed, err)
+	}
+	if n, err := db.RetryFailedBackgroundJobs(); err != nil || n != 1 {
+		t.Errorf("expected 1 failed job retried, got %d (%v)", n, err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, ids[0]); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if n, err := db.PruneBackgroundJobs(); err != nil || n == 0 {
+		t.Errorf("expected the jobs of the deleted article to be pruned, got %d (%v)", n, err)
+	}
+}
@@ -2,35 +2,77 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
-// ChatSession represents a chat session for an article
+// ChatSession represents a chat session for an article, or for several articles, feeds,
+// categories or the whole archive when ArticleID is 0
 type ChatSession struct {
-	ID           int64     `json:"id"`
-	ArticleID    int64     `json:"article_id"`
-	Title        string    `json:"title"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
-	MessageCount int       `json:"message_count"`
+	ID            int64        `json:"id"`
+	ArticleID     int64        `json:"article_id"`
+	Title         string       `json:"title"`
+	Retrieval     string       `json:"retrieval,omitempty"`      // Archive retrieval per question: "", "keyword", "semantic" or "auto"
+	RetrievalDays int          `json:"retrieval_days,omitempty"` // Only retrieve articles published in the last N days, 0 for all
+	Sources       []ChatSource `json:"sources,omitempty"`        // Articles, feeds and categories attached to the session
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	MessageCount  int          `json:"message_count"`
+	Pinned        bool         `json:"pinned"`                  // Pinned sessions are kept when their article is cleaned up
+	ArticleTitle  string       `json:"article_title,omitempty"` // Title of the article when the session was created
+	ArticleURL    string       `json:"article_url,omitempty"`
+}
+
+// ChatSource is an article, feed, category, topic or entity attached to a chat session
+type ChatSource struct {
+	Type  string `json:"type"`  // "article", "feed", "category", "topic" or "entity"
+	Value string `json:"value"` // Article or feed ID, or category, topic or entity name
 }
 
+// Chat source types
+const (
+	ChatSourceArticle  = "article"
+	ChatSourceFeed     = "feed"
+	ChatSourceCategory = "category"
+	ChatSourceTopic    = TagKindTopic
+	ChatSourceEntity   = TagKindEntity
+)
+
 // ChatMessage represents a message in a chat session
 type ChatMessage struct {
 	ID        int64     `json:"id"`
 	SessionID int64     `json:"session_id"`
 	Role      string    `json:"role"` // "user" or "assistant"
 	Content   string    `json:"content"`
-	Thinking  string    `json:"thinking,omitempty"` // AI thinking process (optional)
+	Thinking  string    `json:"thinking,omitempty"`  // AI thinking process (optional)
+	Citations []int64   `json:"citations,omitempty"` // IDs of the articles an assistant answer cites
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// InitChatSourcesTable creates the chat_session_sources table if it doesn't exist
+func InitChatSourcesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS chat_session_sources (
+		session_id INTEGER NOT NULL,
+		source_type TEXT NOT NULL,
+		source_value TEXT NOT NULL,
+		PRIMARY KEY (session_id, source_type, source_value),
+		FOREIGN KEY(session_id) REFERENCES chat_sessions(id) ON DELETE CASCADE
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
 // CreateChatSession creates a new chat session for an article
 func (db *DB) CreateChatSession(articleID int64, title string) (int64, error) {
 	result, err := db.Exec(
-		`INSERT INTO chat_sessions (article_id, title, created_at, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
-		articleID, title,
+		`INSERT INTO chat_sessions (article_id, title, article_title, article_url, created_at, updated_at)
+		 VALUES (?, ?, COALESCE((SELECT title FROM articles WHERE id = ?), ''), COALESCE((SELECT url FROM articles WHERE id = ?), ''),
+		         CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		articleID, title, articleID, articleID,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create chat session: %w", err)
@@ -38,18 +80,67 @@ func (db *DB) CreateChatSession(articleID int64, title string) (int64, error) {
 	return result.LastInsertId()
 }
 
-// GetChatSession retrieves a chat session by ID
-func (db *DB) GetChatSession(sessionID int64) (*ChatSession, error) {
+// CreateArchiveChatSession creates a chat session that is not bound to a single article.
+// Its context is built from the attached sources and, if retrieval is set, from archive
+// articles retrieved for each question.
+func (db *DB) CreateArchiveChatSession(title string, sources []ChatSource, retrieval string, retrievalDays int) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create chat session: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO chat_sessions (article_id, title, retrieval, retrieval_days, created_at, updated_at)
+		 VALUES (0, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		title, retrieval, retrievalDays,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create chat session: %w", err)
+	}
+	sessionID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, source := range sources {
+		if _, err := tx.Exec(
+			`INSERT OR IGNORE INTO chat_session_sources (session_id, source_type, source_value) VALUES (?, ?, ?)`,
+			sessionID, source.Type, source.Value,
+		); err != nil {
+			return 0, fmt.Errorf("failed to add chat session source: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to create chat session: %w", err)
+	}
+	return sessionID, nil
+}
+
+// chatSessionColumns are the columns scanned by scanChatSession
+const chatSessionColumns = `id, article_id, title, COALESCE(retrieval, ''), COALESCE(retrieval_days, 0), created_at, updated_at,
+		       (SELECT COUNT(*) FROM chat_messages WHERE session_id = chat_sessions.id) as message_count,
+		       COALESCE(pinned, 0), COALESCE(article_title, ''), COALESCE(article_url, '')`
+
+// scanChatSession scans a row selected with chatSessionColumns
+func scanChatSession(row interface{ Scan(...interface{}) error }) (ChatSession, error) {
 	var session ChatSession
-	err := db.QueryRow(`
-		SELECT id, article_id, title, created_at, updated_at,
-		       (SELECT COUNT(*) FROM chat_messages WHERE session_id = chat_sessions.id) as message_count
-		FROM chat_sessions
-		WHERE id = ?
-	`, sessionID).Scan(
-		&session.ID, &session.ArticleID, &session.Title,
+	err := row.Scan(
+		&session.ID, &session.ArticleID, &session.Title, &session.Retrieval, &session.RetrievalDays,
 		&session.CreatedAt, &session.UpdatedAt, &session.MessageCount,
+		&session.Pinned, &session.ArticleTitle, &session.ArticleURL,
 	)
+	return session, err
+}
+
+// GetChatSession retrieves a chat session by ID, including its sources
+func (db *DB) GetChatSession(sessionID int64) (*ChatSession, error) {
+	session, err := scanChatSession(db.QueryRow(`
+		SELECT `+chatSessionColumns+`
+		FROM chat_sessions
+		WHERE id = ?
+	`, sessionID))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -57,18 +148,40 @@ func (db *DB) GetChatSession(sessionID int64) (*ChatSession, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chat session: %w", err)
 	}
+
+	session.Sources, err = db.GetChatSessionSources(sessionID)
+	if err != nil {
+		return nil, err
+	}
 	return &session, nil
 }
 
-// GetChatSessionsByArticle retrieves all chat sessions for an article, ordered by updated_at desc
+// GetChatSessionsByArticle retrieves all chat sessions for an article, ordered by updated_at desc.
+// Article ID 0 lists the sessions that are not bound to a single article.
 func (db *DB) GetChatSessionsByArticle(articleID int64) ([]ChatSession, error) {
-	rows, err := db.Query(`
-		SELECT id, article_id, title, created_at, updated_at,
-		       (SELECT COUNT(*) FROM chat_messages WHERE session_id = chat_sessions.id) as message_count
+	return db.queryChatSessions(`
+		SELECT `+chatSessionColumns+`
 		FROM chat_sessions
 		WHERE article_id = ?
 		ORDER BY updated_at DESC
 	`, articleID)
+}
+
+// GetAllChatSessions retrieves the chat sessions of every article and the archive sessions,
+// ordered by updated_at desc
+func (db *DB) GetAllChatSessions(limit, offset int) ([]ChatSession, error) {
+	return db.queryChatSessions(`
+		SELECT `+chatSessionColumns+`
+		FROM chat_sessions
+		ORDER BY updated_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+}
+
+// queryChatSessions runs a query selecting chatSessionColumns, and loads the sources of the
+// archive sessions it returns
+func (db *DB) queryChatSessions(query string, args ...interface{}) ([]ChatSession, error) {
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chat sessions: %w", err)
 	}
@@ -76,20 +189,49 @@ func (db *DB) GetChatSessionsByArticle(articleID int64) ([]ChatSession, error) {
 
 	sessions := make([]ChatSession, 0)
 	for rows.Next() {
-		var session ChatSession
-		err := rows.Scan(
-			&session.ID, &session.ArticleID, &session.Title,
-			&session.CreatedAt, &session.UpdatedAt, &session.MessageCount,
-		)
+		session, err := scanChatSession(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan chat session: %w", err)
 		}
 		sessions = append(sessions, session)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	for i := range sessions {
+		if sessions[i].ArticleID != 0 {
+			continue
+		}
+		if sessions[i].Sources, err = db.GetChatSessionSources(sessions[i].ID); err != nil {
+			return nil, err
+		}
+	}
 	return sessions, nil
 }
 
+// GetChatSessionSources returns the sources attached to a chat session
+func (db *DB) GetChatSessionSources(sessionID int64) ([]ChatSource, error) {
+	rows, err := db.Query(
+		`SELECT source_type, source_value FROM chat_session_sources WHERE session_id = ? ORDER BY source_type, source_value`,
+		sessionID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat session sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []ChatSource
+	for rows.Next() {
+		var source ChatSource
+		if err := rows.Scan(&source.Type, &source.Value); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, rows.Err()
+}
+
 // UpdateChatSessionTitle updates the title of a chat session
 func (db *DB) UpdateChatSessionTitle(sessionID int64, title string) error {
 	_, err := db.Exec(
@@ -102,6 +244,19 @@ func (db *DB) UpdateChatSessionTitle(sessionID int64, title string) error {
 	return nil
 }
 
+// SetChatSessionPinned pins or unpins a chat session. It returns sql.ErrNoRows if the session
+// doesn't exist.
+func (db *DB) SetChatSessionPinned(sessionID int64, pinned bool) error {
+	result, err := db.Exec(`UPDATE chat_sessions SET pinned = ? WHERE id = ?`, pinned, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to pin chat session: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 // UpdateChatSessionTimestamp updates the updated_at timestamp of a chat session
 func (db *DB) UpdateChatSessionTimestamp(sessionID int64) error {
 	_, err := db.Exec(
@@ -120,6 +275,10 @@ func (db *DB) DeleteChatSession(sessionID int64) error {
 	if err != nil {
 		return fmt.Errorf("failed to delete chat messages: %w", err)
 	}
+	_, err = db.Exec(`DELETE FROM chat_session_sources WHERE session_id = ?`, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete chat session sources: %w", err)
+	}
 	_, err = db.Exec(`DELETE FROM chat_sessions WHERE id = ?`, sessionID)
 	if err != nil {
 		return fmt.Errorf("failed to delete chat session: %w", err)
@@ -129,9 +288,23 @@ func (db *DB) DeleteChatSession(sessionID int64) error {
 
 // CreateChatMessage creates a new chat message in a session
 func (db *DB) CreateChatMessage(sessionID int64, role, content, thinking string) (int64, error) {
+	return db.CreateChatMessageWithCitations(sessionID, role, content, thinking, nil)
+}
+
+// CreateChatMessageWithCitations creates a new chat message that cites articles
+func (db *DB) CreateChatMessageWithCitations(sessionID int64, role, content, thinking string, citations []int64) (int64, error) {
+	var citationsJSON string
+	if len(citations) > 0 {
+		encoded, err := json.Marshal(citations)
+		if err != nil {
+			return 0, err
+		}
+		citationsJSON = string(encoded)
+	}
+
 	result, err := db.Exec(
-		`INSERT INTO chat_messages (session_id, role, content, thinking, created_at) VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)`,
-		sessionID, role, content, thinking,
+		`INSERT INTO chat_messages (session_id, role, content, thinking, citations, created_at) VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		sessionID, role, content, thinking, citationsJSON,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to create chat message: %w", err)
@@ -146,10 +319,10 @@ func (db *DB) CreateChatMessage(sessionID int64, role, content, thinking string)
 // GetChatMessages retrieves all messages for a session, ordered by created_at asc
 func (db *DB) GetChatMessages(sessionID int64) ([]ChatMessage, error) {
 	rows, err := db.Query(`
-		SELECT id, session_id, role, content, thinking, created_at
+		SELECT id, session_id, role, content, thinking, COALESCE(citations, ''), created_at
 		FROM chat_messages
 		WHERE session_id = ?
-		ORDER BY created_at ASC
+		ORDER BY created_at ASC, id ASC
 	`, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chat messages: %w", err)
@@ -160,9 +333,10 @@ func (db *DB) GetChatMessages(sessionID int64) ([]ChatMessage, error) {
 	for rows.Next() {
 		var msg ChatMessage
 		var thinking sql.NullString
+		var citations string
 		err := rows.Scan(
 			&msg.ID, &msg.SessionID, &msg.Role, &msg.Content,
-			&thinking, &msg.CreatedAt,
+			&thinking, &citations, &msg.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan chat message: %w", err)
@@ -170,6 +344,9 @@ func (db *DB) GetChatMessages(sessionID int64) ([]ChatMessage, error) {
 		if thinking.Valid {
 			msg.Thinking = thinking.String
 		}
+		if citations != "" {
+			_ = json.Unmarshal([]byte(citations), &msg.Citations)
+		}
 		messages = append(messages, msg)
 	}
 
@@ -197,20 +374,94 @@ func (db *DB) DeleteChatMessage(messageID int64) error {
 	return nil
 }
 
-// CleanupOldChatSessions removes chat sessions older than maxAgeDays
+// ChatMessageMatch is a chat message matching a search, with the session it belongs to
+type ChatMessageMatch struct {
+	MessageID    int64     `json:"message_id"`
+	SessionID    int64     `json:"session_id"`
+	SessionTitle string    `json:"session_title"`
+	ArticleID    int64     `json:"article_id"`
+	ArticleTitle string    `json:"article_title,omitempty"`
+	Role         string    `json:"role"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SearchChatMessages returns the chat messages containing every term of query, case-insensitively,
+// newest first
+func (db *DB) SearchChatMessages(query string, limit int) ([]ChatMessageMatch, error) {
+	terms := strings.Fields(query)
+	matches := make([]ChatMessageMatch, 0)
+	if len(terms) == 0 {
+		return matches, nil
+	}
+
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, 0, len(terms)+1)
+	for i, term := range terms {
+		conditions[i] = `m.content LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(term)+"%")
+	}
+	args = append(args, limit)
+
+	rows, err := db.Query(`
+		SELECT m.id, m.session_id, s.title, s.article_id, COALESCE(s.article_title, ''), m.role, m.content, m.created_at
+		FROM chat_messages m
+		JOIN chat_sessions s ON s.id = m.session_id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m ChatMessageMatch
+		if err := rows.Scan(&m.MessageID, &m.SessionID, &m.SessionTitle, &m.ArticleID, &m.ArticleTitle,
+			&m.Role, &m.Content, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat message: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// CleanupOrphanedChatSessions removes the chat sessions of deleted articles, except pinned ones
+func (db *DB) CleanupOrphanedChatSessions() (int64, error) {
+	orphaned := `SELECT id FROM chat_sessions
+		WHERE article_id > 0 AND COALESCE(pinned, 0) = 0
+		AND NOT EXISTS (SELECT 1 FROM articles WHERE articles.id = chat_sessions.article_id)`
+
+	if _, err := db.Exec(`DELETE FROM chat_messages WHERE session_id IN (` + orphaned + `)`); err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned chat messages: %w", err)
+	}
+	result, err := db.Exec(`DELETE FROM chat_sessions WHERE id IN (` + orphaned + `)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned chat sessions: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// CleanupOldChatSessions removes chat sessions older than maxAgeDays, except pinned ones
 func (db *DB) CleanupOldChatSessions(maxAgeDays int) (int64, error) {
+	old := `SELECT id FROM chat_sessions WHERE created_at < datetime('now', ?) AND COALESCE(pinned, 0) = 0`
+
 	// First delete messages
 	_, err := db.Exec(
-		`DELETE FROM chat_messages WHERE session_id IN (SELECT id FROM chat_sessions WHERE created_at < datetime('now', ?))`,
+		`DELETE FROM chat_messages WHERE session_id IN (`+old+`)`,
 		fmt.Sprintf("-%d days", maxAgeDays),
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete old chat messages: %w", err)
 	}
+	_, _ = db.Exec(
+		`DELETE FROM chat_session_sources WHERE session_id IN (`+old+`)`,
+		fmt.Sprintf("-%d days", maxAgeDays),
+	)
 
 	// Then delete sessions
 	result, err := db.Exec(
-		`DELETE FROM chat_sessions WHERE created_at < datetime('now', ?)`,
+		`DELETE FROM chat_sessions WHERE id IN (`+old+`)`,
 		fmt.Sprintf("-%d days", maxAgeDays),
 	)
 	if err != nil {
@@ -227,6 +478,7 @@ func (db *DB) DeleteAllChatSessions() (int64, error) {
 	if err != nil {
 		return 0, fmt.Errorf("failed to delete all chat messages: %w", err)
 	}
+	_, _ = db.Exec(`DELETE FROM chat_session_sources`)
 
 	// Then delete all sessions
 	result, err = db.Exec(`DELETE FROM chat_sessions`)
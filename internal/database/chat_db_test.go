@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestDeleteFeed_CleansUpUnpinnedChatSessions(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	result, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at) VALUES (?, 'Headline', 'https://example.com/1', datetime('now'))`, feedID)
+	if err != nil {
+		t.Fatalf("insert article error: %v", err)
+	}
+	articleID, _ := result.LastInsertId()
+
+	pinned, err := db.CreateChatSession(articleID, "Pinned")
+	if err != nil {
+		t.Fatalf("CreateChatSession error: %v", err)
+	}
+	if err := db.SetChatSessionPinned(pinned, true); err != nil {
+		t.Fatalf("SetChatSessionPinned error: %v", err)
+	}
+	unpinned, err := db.CreateChatSession(articleID, "Unpinned")
+	if err != nil {
+		t.Fatalf("CreateChatSession error: %v", err)
+	}
+	if _, err := db.CreateChatMessage(unpinned, "user", "What happened?", ""); err != nil {
+		t.Fatalf("CreateChatMessage error: %v", err)
+	}
+
+	if err := db.DeleteFeed(feedID); err != nil {
+		t.Fatalf("DeleteFeed error: %v", err)
+	}
+	if session, err := db.GetChatSession(pinned); err != nil || session == nil {
+		t.Errorf("expected the pinned session to survive, got %v (%v)", session, err)
+	}
+	if session, err := db.GetChatSession(unpinned); err != nil || session != nil {
+		t.Errorf("expected the unpinned session to be removed, got %+v (%v)", session, err)
+	}
+	var messages int
+	db.QueryRow(`SELECT COUNT(*) FROM chat_messages WHERE session_id = ?`, unpinned).Scan(&messages)
+	if messages != 0 {
+		t.Errorf("expected the messages of the removed session to be removed, got %d", messages)
+	}
+}
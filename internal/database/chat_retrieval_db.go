@@ -0,0 +1,178 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// ArticleScope restricts archive queries to feeds, categories, tags and a publication window.
+// An empty scope covers all visible articles.
+type ArticleScope struct {
+	FeedIDs    []int64
+	Categories []string   // Matches the category and its subcategories
+	Tags       []ScopeTag // Articles classified with the topic or entity
+	Since      time.Time  // Zero for no lower bound
+}
+
+// ScopeTag is a topic or entity of an article scope, matched case-insensitively
+type ScopeTag struct {
+	Kind  string // TagKindTopic or TagKindEntity
+	Value string
+}
+
+// HasSources reports whether the scope is restricted to feeds, categories or tags
+func (s ArticleScope) HasSources() bool {
+	return len(s.FeedIDs) > 0 || len(s.Categories) > 0 || len(s.Tags) > 0
+}
+
+// where returns the SQL conditions and arguments of the scope for articles a joined with feeds f
+func (s ArticleScope) where() (string, []interface{}) {
+	clauses := []string{"a.is_hidden = 0"}
+	var args []interface{}
+
+	var sources []string
+	for _, feedID := range s.FeedIDs {
+		sources = append(sources, "a.feed_id = ?")
+		args = append(args, feedID)
+	}
+	for _, category := range s.Categories {
+		sources = append(sources, "(f.category = ? OR f.category LIKE ?)")
+		args = append(args, category, category+"/%")
+	}
+	for _, tag := range s.Tags {
+		sources = append(sources, "EXISTS (SELECT 1 FROM article_tags t WHERE t.article_id = a.id AND t.kind = ? AND t.value = ? COLLATE NOCASE)")
+		args = append(args, tag.Kind, tag.Value)
+	}
+	if len(sources) > 0 {
+		clauses = append(clauses, "("+strings.Join(sources, " OR ")+")")
+	}
+
+	if !s.Since.IsZero() {
+		clauses = append(clauses, "a.published_at >= ?")
+		args = append(args, s.Since)
+	}
+	return strings.Join(clauses, " AND "), args
+}
+
+// articleColumns are the columns scanned by scanArticles, see GetArticles
+const articleColumns = `a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, COALESCE(a.language, ''), COALESCE(a.language_confidence, 0), f.title`
+
+// GetRecentArticlesInScope returns the newest articles in scope
+func (db *DB) GetRecentArticlesInScope(scope ArticleScope, limit int) ([]models.Article, error) {
+	db.WaitForReady()
+	where, args := scope.where()
+	rows, err := db.Query(`
+		SELECT `+articleColumns+`
+		FROM articles a
+		JOIN feeds f ON a.feed_id = f.id
+		WHERE `+where+`
+		ORDER BY a.published_at DESC
+		LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+// GetArticleIDsInScope returns which of the articles with the given IDs are in scope
+func (db *DB) GetArticleIDsInScope(ids []int64, scope ArticleScope) (map[int64]bool, error) {
+	db.WaitForReady()
+	inScope := make(map[int64]bool)
+	if len(ids) == 0 {
+		return inScope, nil
+	}
+
+	where, args := scope.where()
+	rows, err := db.Query(`
+		SELECT a.id
+		FROM articles a
+		JOIN feeds f ON a.feed_id = f.id
+		WHERE a.id IN (`+placeholders(len(ids))+`) AND `+where, append(int64Args(ids), args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		inScope[id] = true
+	}
+	return inScope, rows.Err()
+}
+
+// SearchArticlesByKeywords returns the articles in scope that mention the most terms,
+// weighting matches in the title over the summary and cached content
+func (db *DB) SearchArticlesByKeywords(terms []string, scope ArticleScope, limit int) ([]models.Article, error) {
+	db.WaitForReady()
+	if len(terms) == 0 {
+		return []models.Article{}, nil
+	}
+
+	var scores []string
+	var scoreArgs []interface{}
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		scores = append(scores, `(CASE WHEN a.title LIKE ? ESCAPE '\' THEN 3 ELSE 0 END)
+			+ (CASE WHEN a.summary LIKE ? ESCAPE '\' THEN 2 ELSE 0 END)
+			+ (CASE WHEN c.content LIKE ? ESCAPE '\' THEN 1 ELSE 0 END)`)
+		scoreArgs = append(scoreArgs, pattern, pattern, pattern)
+	}
+
+	where, whereArgs := scope.where()
+	args := append(scoreArgs, whereArgs...)
+	args = append(args, limit)
+	rows, err := db.Query(`
+		SELECT `+articleColumns+`
+		FROM (
+			SELECT a.id AS article_id, `+strings.Join(scores, " + ")+` AS score
+			FROM articles a
+			JOIN feeds f ON a.feed_id = f.id
+			LEFT JOIN article_contents c ON c.article_id = a.id
+			WHERE `+where+`
+		) ranked
+		JOIN articles a ON a.id = ranked.article_id
+		JOIN feeds f ON a.feed_id = f.id
+		WHERE ranked.score > 0
+		ORDER BY ranked.score DESC, a.published_at DESC
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanArticles(rows)
+}
+
+// escapeLike escapes the LIKE wildcards in s for use with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// scanArticles scans rows selected with articleColumns
+func scanArticles(rows *sql.Rows) ([]models.Article, error) {
+	articles := []models.Article{}
+	for rows.Next() {
+		var a models.Article
+		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.Language, &a.LanguageConfidence, &a.FeedTitle); err != nil {
+			return nil, err
+		}
+		a.ImageURL = imageURL.String
+		a.AudioURL = audioURL.String
+		a.VideoURL = videoURL.String
+		if publishedAt.Valid {
+			a.PublishedAt = publishedAt.Time
+		}
+		a.TranslatedTitle = translatedTitle.String
+		a.Summary = summary.String
+		a.FreshRSSItemID = freshrssItemID.String
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArchiveChatSessionsAndCitations(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	sources := []ChatSource{{Type: ChatSourceFeed, Value: "3"}, {Type: ChatSourceCategory, Value: "Tech"}}
+	sessionID, err := db.CreateArchiveChatSession("Archive", sources, "keyword", 30)
+	if err != nil {
+		t.Fatalf("CreateArchiveChatSession error: %v", err)
+	}
+	if _, err := db.CreateChatSession(42, "Article chat"); err != nil {
+		t.Fatalf("CreateChatSession error: %v", err)
+	}
+
+	session, err := db.GetChatSession(sessionID)
+	if err != nil || session == nil {
+		t.Fatalf("GetChatSession: %v %v", session, err)
+	}
+	if session.ArticleID != 0 || session.Retrieval != "keyword" || session.RetrievalDays != 30 || len(session.Sources) != 2 {
+		t.Errorf("unexpected session: %+v", session)
+	}
+
+	archive, err := db.GetChatSessionsByArticle(0)
+	if err != nil || len(archive) != 1 || len(archive[0].Sources) != 2 {
+		t.Fatalf("expected one archive session with sources, got %+v (%v)", archive, err)
+	}
+
+	if _, err := db.CreateChatMessage(sessionID, "user", "What happened?", ""); err != nil {
+		t.Fatalf("CreateChatMessage error: %v", err)
+	}
+	if _, err := db.CreateChatMessageWithCitations(sessionID, "assistant", "See [#7].", "", []int64{7}); err != nil {
+		t.Fatalf("CreateChatMessageWithCitations error: %v", err)
+	}
+	messages, err := db.GetChatMessages(sessionID)
+	if err != nil || len(messages) != 2 {
+		t.Fatalf("GetChatMessages: %v %v", messages, err)
+	}
+	if len(messages[0].Citations) != 0 || len(messages[1].Citations) != 1 || messages[1].Citations[0] != 7 {
+		t.Errorf("unexpected citations: %+v", messages)
+	}
+
+	if err := db.DeleteChatSession(sessionID); err != nil {
+		t.Fatalf("DeleteChatSession error: %v", err)
+	}
+	if sources, _ := db.GetChatSessionSources(sessionID); len(sources) != 0 {
+		t.Errorf("expected sources to be deleted with the session, got %v", sources)
+	}
+}
+
+func TestSearchArticlesByKeywords(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	techID, err := db.AddFeed(&models.Feed{Title: "Tech", URL: "https://tech.example.com/feed", Category: "Tech/Hardware"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	newsID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://news.example.com/feed", Category: "News"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+
+	now := time.Now()
+	articles := []*models.Article{
+		{FeedID: techID, Title: "New battery chemistry", Summary: "Solid state cells", URL: "https://tech.example.com/1", PublishedAt: now},
+		{FeedID: newsID, Title: "Elections", Summary: "Battery recycling plant approved", URL: "https://news.example.com/1", PublishedAt: now},
+		{FeedID: newsID, Title: "Old battery story", URL: "https://news.example.com/2", PublishedAt: now.AddDate(0, -3, 0)},
+		{FeedID: newsID, Title: "100% solar", URL: "https://news.example.com/3", PublishedAt: now},
+	}
+	for _, a := range articles {
+		if err := db.SaveArticle(a); err != nil {
+			t.Fatalf("SaveArticle error: %v", err)
+		}
+	}
+
+	found, err := db.SearchArticlesByKeywords([]string{"battery", "solid"}, ArticleScope{Since: now.AddDate(0, -1, 0)}, 10)
+	if err != nil {
+		t.Fatalf("SearchArticlesByKeywords error: %v", err)
+	}
+	if len(found) != 2 || found[0].Title != "New battery chemistry" || found[0].FeedTitle != "Tech" {
+		t.Errorf("expected the recent battery articles, best match first, got %+v", found)
+	}
+
+	found, _ = db.SearchArticlesByKeywords([]string{"battery"}, ArticleScope{Categories: []string{"Tech"}}, 10)
+	if len(found) != 1 || found[0].FeedID != techID {
+		t.Errorf("expected the category scope to include subcategories only, got %+v", found)
+	}
+
+	found, _ = db.SearchArticlesByKeywords([]string{"0%"}, ArticleScope{}, 10)
+	if len(found) != 1 || found[0].Title != "100% solar" {
+		t.Errorf("expected LIKE wildcards to be matched literally, got %+v", found)
+	}
+
+	recent, err := db.GetRecentArticlesInScope(ArticleScope{FeedIDs: []int64{newsID}}, 2)
+	if err != nil || len(recent) != 2 || recent[0].FeedID != newsID {
+		t.Errorf("unexpected recent articles: %+v (%v)", recent, err)
+	}
+}
+
+func TestArticleScopeTags(t *testing.T) {
+	db := setupExtractionTestDB(t)
+	feedID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://news.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+
+	var ids []int64
+	for _, title := range []string{"Cup final", "Chip export rules", "Weather"} {
+		res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, unique_id) VALUES (?, ?, ?, ?, ?)`,
+			feedID, title, "https://news.example.com/"+title, time.Now(), title)
+		if err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+		id, _ := res.LastInsertId()
+		ids = append(ids, id)
+	}
+	db.SaveArticleClassification(ArticleClassification{ArticleID: ids[0], Topics: []string{"Sports"}, TopicsHash: "v1", ContentHash: "h0"})
+	db.SaveArticleClassification(ArticleClassification{ArticleID: ids[1], Topics: []string{"Politics"}, Entities: []string{"Nvidia"}, TopicsHash: "v1", ContentHash: "h1"})
+
+	scope := ArticleScope{Tags: []ScopeTag{{Kind: TagKindTopic, Value: "sports"}, {Kind: TagKindEntity, Value: "NVIDIA"}}}
+	recent, err := db.GetRecentArticlesInScope(scope, 10)
+	if err != nil || len(recent) != 2 {
+		t.Errorf("expected the sports and Nvidia articles, got %+v (%v)", recent, err)
+	}
+
+	inScope, err := db.GetArticleIDsInScope(ids, ArticleScope{Tags: []ScopeTag{{Kind: TagKindTopic, Value: "Nvidia"}}})
+	if err != nil || len(inScope) != 0 {
+		t.Errorf("expected tag kinds to be told apart, got %v (%v)", inScope, err)
+	}
+	inScope, _ = db.GetArticleIDsInScope(ids, ArticleScope{Tags: []ScopeTag{{Kind: TagKindTopic, Value: "Politics"}}})
+	if len(inScope) != 1 || !inScope[ids[1]] {
+		t.Errorf("expected only the politics article in scope, got %v", inScope)
+	}
+}
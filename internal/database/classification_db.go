@@ -0,0 +1,280 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// Kinds of article tags
+const (
+	TagKindTopic  = "topic"
+	TagKindEntity = "entity"
+)
+
+// ArticleClassification is the AI classification of an article into the user's topics,
+// with the entities it mentions and a short line on why it matters
+type ArticleClassification struct {
+	ArticleID    int64    `json:"article_id"`
+	Topics       []string `json:"topics"`
+	Entities     []string `json:"entities"`
+	WhyItMatters string   `json:"why_it_matters"`
+	Model        string   `json:"model"`
+	TopicsHash   string   `json:"-"` // Hash of the topic list the article was classified against
+	ContentHash  string   `json:"-"` // Hash of the classified text, to reuse results for duplicates
+}
+
+// ClassificationCandidate is an article to classify, with its text
+type ClassificationCandidate struct {
+	ArticleID int64
+	FeedTitle string
+	Title     string
+	Summary   string
+	Content   string // Cached article content, empty if none
+}
+
+// TagCount is a tag value with the number of articles carrying it
+type TagCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// InitClassificationTables creates the article classification and tag tables if they don't exist
+func InitClassificationTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS article_classifications (
+		article_id INTEGER PRIMARY KEY,
+		topics_hash TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		why_it_matters TEXT NOT NULL DEFAULT '',
+		model TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_article_classifications_content ON article_classifications(content_hash, topics_hash);
+
+	CREATE TABLE IF NOT EXISTS article_tags (
+		article_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		value TEXT NOT NULL,
+		PRIMARY KEY(article_id, kind, value),
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_article_tags_kind_value ON article_tags(kind, value);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// SaveArticleClassification stores or replaces the classification of an article and its tags
+func (db *DB) SaveArticleClassification(c ArticleClassification) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT OR REPLACE INTO article_classifications (article_id, topics_hash, content_hash, why_it_matters, model, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		c.ArticleID, c.TopicsHash, c.ContentHash, c.WhyItMatters, c.Model, time.Now().Unix()); err != nil {
+		return fmt.Errorf("failed to save article classification: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM article_tags WHERE article_id = ?`, c.ArticleID); err != nil {
+		return err
+	}
+	for kind, values := range map[string][]string{TagKindTopic: c.Topics, TagKindEntity: c.Entities} {
+		for _, value := range values {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO article_tags (article_id, kind, value) VALUES (?, ?, ?)`,
+				c.ArticleID, kind, value); err != nil {
+				return fmt.Errorf("failed to save article tag: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// GetClassificationByContent returns a classification of any article with the same text
+// classified against the same topics, so that duplicates aren't sent to the model again
+func (db *DB) GetClassificationByContent(contentHash, topicsHash string) (*ArticleClassification, error) {
+	db.WaitForReady()
+	var articleID int64
+	err := db.QueryRow(`
+		SELECT article_id FROM article_classifications
+		WHERE content_hash = ? AND topics_hash = ?
+		LIMIT 1`, contentHash, topicsHash).Scan(&articleID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	classifications, err := db.GetArticleClassifications([]int64{articleID})
+	if err != nil {
+		return nil, err
+	}
+	return classifications[articleID], nil
+}
+
+// GetClassificationCandidates returns the articles among ids that have not been classified
+// against the topics with topicsHash yet
+func (db *DB) GetClassificationCandidates(ids []int64, topicsHash string) ([]ClassificationCandidate, error) {
+	db.WaitForReady()
+	candidates := make([]ClassificationCandidate, 0)
+	if len(ids) == 0 {
+		return candidates, nil
+	}
+
+	args := append([]interface{}{topicsHash}, int64Args(ids)...)
+	rows, err := db.Query(`
+		SELECT a.id, COALESCE(f.title, ''), COALESCE(a.title, ''), COALESCE(a.summary, ''), COALESCE(c.content, '')
+		FROM articles a
+		JOIN feeds f ON f.id = a.feed_id
+		LEFT JOIN article_contents c ON c.article_id = a.id
+		LEFT JOIN article_classifications ac ON ac.article_id = a.id
+		WHERE (ac.article_id IS NULL OR ac.topics_hash != ?) AND a.id IN (`+placeholders(len(ids))+`)
+		ORDER BY a.published_at DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c ClassificationCandidate
+		if err := rows.Scan(&c.ArticleID, &c.FeedTitle, &c.Title, &c.Summary, &c.Content); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// GetArticleClassifications returns the classifications of the articles with the given IDs,
+// keyed by article ID. Articles that aren't classified are left out.
+func (db *DB) GetArticleClassifications(ids []int64) (map[int64]*ArticleClassification, error) {
+	db.WaitForReady()
+	result := make(map[int64]*ArticleClassification)
+	for start := 0; start < len(ids); start += sqlIDChunk {
+		chunk := ids[start:min(start+sqlIDChunk, len(ids))]
+		if err := db.loadClassifications(chunk, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (db *DB) loadClassifications(ids []int64, result map[int64]*ArticleClassification) error {
+	rows, err := db.Query(`
+		SELECT article_id, topics_hash, content_hash, why_it_matters, model
+		FROM article_classifications
+		WHERE article_id IN (`+placeholders(len(ids))+`)`, int64Args(ids)...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		c := &ArticleClassification{Topics: []string{}, Entities: []string{}}
+		if err := rows.Scan(&c.ArticleID, &c.TopicsHash, &c.ContentHash, &c.WhyItMatters, &c.Model); err != nil {
+			rows.Close()
+			return err
+		}
+		result[c.ArticleID] = c
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = db.Query(`
+		SELECT article_id, kind, value FROM article_tags
+		WHERE article_id IN (`+placeholders(len(ids))+`)
+		ORDER BY rowid`, int64Args(ids)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var articleID int64
+		var kind, value string
+		if err := rows.Scan(&articleID, &kind, &value); err != nil {
+			return err
+		}
+		c := result[articleID]
+		if c == nil {
+			continue
+		}
+		if kind == TagKindTopic {
+			c.Topics = append(c.Topics, value)
+		} else {
+			c.Entities = append(c.Entities, value)
+		}
+	}
+	return rows.Err()
+}
+
+// AttachArticleClassifications sets the topics, entities and "why it matters" line of
+// classified articles
+func (db *DB) AttachArticleClassifications(articles []models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	classifications, err := db.GetArticleClassifications(ids)
+	if err != nil {
+		return err
+	}
+	for i := range articles {
+		if c := classifications[articles[i].ID]; c != nil {
+			articles[i].Topics = c.Topics
+			articles[i].Entities = c.Entities
+			articles[i].WhyItMatters = c.WhyItMatters
+		}
+	}
+	return nil
+}
+
+// GetTagCounts returns the values of a tag kind with their article counts, most used first
+func (db *DB) GetTagCounts(kind string, limit int) ([]TagCount, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT value, COUNT(*) AS count FROM article_tags
+		WHERE kind = ?
+		GROUP BY value
+		ORDER BY count DESC, value ASC
+		LIMIT ?`, kind, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]TagCount, 0)
+	for rows.Next() {
+		var c TagCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// PruneArticleClassifications removes the classifications and tags of deleted articles
+func (db *DB) PruneArticleClassifications() (int64, error) {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM article_tags WHERE article_id NOT IN (SELECT id FROM articles)`); err != nil {
+		return 0, err
+	}
+	result, err := db.Exec(`DELETE FROM article_classifications WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
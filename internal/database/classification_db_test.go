@@ -0,0 +1,93 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArticleClassifications(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO feeds (title, url) VALUES ('Feed', 'https://example.com/feed')`)
+	if err != nil {
+		t.Fatalf("insert feed error: %v", err)
+	}
+	feedID, _ := res.LastInsertId()
+
+	insert := func(title string) int64 {
+		t.Helper()
+		res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, unique_id) VALUES (?, ?, ?, ?, ?)`,
+			feedID, title, "https://example.com/"+title, time.Now(), title)
+		if err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+		id, _ := res.LastInsertId()
+		return id
+	}
+	launch := insert("Launch")
+	match := insert("Match")
+
+	candidates, err := db.GetClassificationCandidates([]int64{launch, match}, "v1")
+	if err != nil || len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v (%v)", candidates, err)
+	}
+
+	if err := db.SaveArticleClassification(ArticleClassification{
+		ArticleID: launch, Topics: []string{"Space"}, Entities: []string{"NASA", "SpaceX"},
+		WhyItMatters: "First crewed launch.", Model: "m", TopicsHash: "v1", ContentHash: "h1",
+	}); err != nil {
+		t.Fatalf("SaveArticleClassification error: %v", err)
+	}
+	if err := db.SaveArticleClassification(ArticleClassification{
+		ArticleID: match, Topics: []string{"Sports", "Space"}, Entities: []string{}, TopicsHash: "v1", ContentHash: "h2",
+	}); err != nil {
+		t.Fatalf("SaveArticleClassification error: %v", err)
+	}
+
+	candidates, err = db.GetClassificationCandidates([]int64{launch, match}, "v1")
+	if err != nil || len(candidates) != 0 {
+		t.Errorf("expected no candidates for the same topics, got %+v (%v)", candidates, err)
+	}
+	candidates, err = db.GetClassificationCandidates([]int64{launch, match}, "v2")
+	if err != nil || len(candidates) != 2 {
+		t.Errorf("expected all articles to be candidates for new topics, got %+v (%v)", candidates, err)
+	}
+
+	cached, err := db.GetClassificationByContent("h1", "v1")
+	if err != nil || cached == nil || cached.ArticleID != launch || len(cached.Entities) != 2 {
+		t.Errorf("expected the classification by content hash, got %+v (%v)", cached, err)
+	}
+	if cached, err := db.GetClassificationByContent("h1", "v2"); err != nil || cached != nil {
+		t.Errorf("expected no classification for other topics, got %+v (%v)", cached, err)
+	}
+
+	articles := []models.Article{{ID: launch}, {ID: match}, {ID: 999}}
+	if err := db.AttachArticleClassifications(articles); err != nil {
+		t.Fatalf("AttachArticleClassifications error: %v", err)
+	}
+	if len(articles[0].Topics) != 1 || articles[0].WhyItMatters != "First crewed launch." || len(articles[1].Topics) != 2 || articles[2].Topics != nil {
+		t.Errorf("unexpected attached classifications: %+v", articles)
+	}
+
+	counts, err := db.GetTagCounts(TagKindTopic, 10)
+	if err != nil || len(counts) != 2 || counts[0] != (TagCount{Value: "Space", Count: 2}) {
+		t.Errorf("unexpected topic counts: %+v (%v)", counts, err)
+	}
+
+	// Saving again replaces the tags
+	if err := db.SaveArticleClassification(ArticleClassification{ArticleID: match, Topics: []string{"Sports"}, TopicsHash: "v2", ContentHash: "h2"}); err != nil {
+		t.Fatalf("SaveArticleClassification error: %v", err)
+	}
+	if counts, err := db.GetTagCounts(TagKindTopic, 10); err != nil || len(counts) != 2 || counts[0].Count != 1 {
+		t.Errorf("expected the tags to be replaced, got %+v (%v)", counts, err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, match); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if removed, err := db.PruneArticleClassifications(); err != nil || removed != 1 {
+		t.Errorf("expected the deleted article's classification to be pruned, got %d (%v)", removed, err)
+	}
+}
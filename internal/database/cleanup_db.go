@@ -50,6 +50,7 @@ func (db *DB) CleanupOldArticles() (int64, error) {
 	// Also cleanup related caches with the same age limit
 	_, _ = db.CleanupTranslationCache(maxAgeDays)
 	_, _ = db.CleanupOldArticleContents(maxAgeDays)
+	_, _ = db.CleanupOrphanedChatSessions()
 
 	// Run VACUUM to reclaim space
 	_, _ = db.Exec("VACUUM")
@@ -75,6 +76,7 @@ func (db *DB) DeleteAllArticles() (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	_, _ = db.CleanupOrphanedChatSessions()
 	return result.RowsAffected()
 }
 
@@ -97,6 +99,7 @@ func (db *DB) CleanupUnimportantArticles() (int64, error) {
 	// Also cleanup related caches (remove entries older than 7 days)
 	_, _ = db.CleanupTranslationCache(7)
 	_, _ = db.CleanupOldArticleContents(7)
+	_, _ = db.CleanupOrphanedChatSessions()
 
 	// Run VACUUM to reclaim space
 	_, _ = db.Exec("VACUUM")
@@ -13,11 +13,16 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// sqlIDChunk bounds the number of IDs bound to one query, below SQLite's variable limit
+const sqlIDChunk = 500
+
 // DB wraps sql.DB with initialization state tracking.
 type DB struct {
 	*sql.DB
 	ready chan struct{}
 	once  sync.Once
+
+	translationCacheLookups translationCacheLookups
 }
 
 // NewDB creates a new database connection with optimized settings.
@@ -71,6 +76,71 @@ func (db *DB) Init() error {
 			return
 		}
 
+		// Initialize feed health and fetch history tables
+		if err = InitFeedHealthTables(db.DB); err != nil {
+			return
+		}
+
+		// Initialize per-site full-text extraction rules table
+		if err = InitExtractionRulesTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize AI provider profiles and per-task routing tables
+		if err = InitAIProfilesTables(db.DB); err != nil {
+			return
+		}
+
+		// Initialize AI usage ledger and budget tables
+		if err = InitAIUsageTables(db.DB); err != nil {
+			return
+		}
+
+		// Initialize article embeddings table for semantic search
+		if err = InitEmbeddingTables(db.DB); err != nil {
+			return
+		}
+
+		// Initialize chat session sources table for multi-article chat
+		if err = InitChatSourcesTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize article classification and tag tables
+		if err = InitClassificationTables(db.DB); err != nil {
+			return
+		}
+
+		// Initialize translated article bodies table
+		if err = InitArticleTranslationsTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize article summary variants table
+		if err = InitArticleSummariesTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize extracted article keywords table
+		if err = InitArticleKeywordsTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize translation glossary table
+		if err = InitGlossaryTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize background pre-translation and pre-summarization job queue
+		if err = InitBackgroundJobsTable(db.DB); err != nil {
+			return
+		}
+
+		// Initialize AI prompt template library
+		if err = InitPromptTemplatesTable(db.DB); err != nil {
+			return
+		}
+
 		// Create settings table if not exists
 		_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS settings (
 			key TEXT PRIMARY KEY,
@@ -326,6 +396,46 @@ func (db *DB) Init() error {
 				log.Printf("Error creating feeds_new table: %v", err)
 			}
 		}
+
+		// Migration: Add full_text_on_ingest column to feeds table for fetching full articles during refresh
+		// Runs after the feeds table rebuild above, which only copies the columns it knows about.
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN full_text_on_ingest BOOLEAN DEFAULT 0`)
+
+		// Migration: Add the dominant detected language of feeds, also after the feeds table rebuild
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN language TEXT DEFAULT ''`)
+
+		// Migration: Add background pre-translation and pre-summarization opt-ins to feeds, also after the rebuild
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN background_translate BOOLEAN DEFAULT 0`)
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN background_summarize BOOLEAN DEFAULT 0`)
+
+		// Migration: Add prompt template overrides to feeds, also after the rebuild
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN summary_prompt_id INTEGER DEFAULT 0`)
+		_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN translation_prompt_id INTEGER DEFAULT 0`)
+
+		// Migration: Add is_full_text column to article_contents to mark content extracted from the original page
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE article_contents ADD COLUMN is_full_text BOOLEAN DEFAULT 0`)
+
+		// Migration: Add archive retrieval settings to chat sessions and citations to chat messages
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE chat_sessions ADD COLUMN retrieval TEXT DEFAULT ''`)
+		_, _ = db.Exec(`ALTER TABLE chat_sessions ADD COLUMN retrieval_days INTEGER DEFAULT 0`)
+		_, _ = db.Exec(`ALTER TABLE chat_messages ADD COLUMN citations TEXT DEFAULT ''`)
+
+		// Migration: Add pinning and the title and URL of their article to chat sessions, so that
+		// pinned sessions outlive the cleanup of their article
+		// Error is ignored - if column exists, the operation fails harmlessly.
+		_, _ = db.Exec(`ALTER TABLE chat_sessions ADD COLUMN pinned BOOLEAN DEFAULT 0`)
+		_, _ = db.Exec(`ALTER TABLE chat_sessions ADD COLUMN article_title TEXT DEFAULT ''`)
+		_, _ = db.Exec(`ALTER TABLE chat_sessions ADD COLUMN article_url TEXT DEFAULT ''`)
+		_, _ = db.Exec(`UPDATE chat_sessions SET
+			article_title = COALESCE((SELECT title FROM articles WHERE articles.id = chat_sessions.article_id), ''),
+			article_url = COALESCE((SELECT url FROM articles WHERE articles.id = chat_sessions.article_id), '')
+			WHERE article_id > 0 AND article_title = ''`)
 	})
 	return err
 }
@@ -381,6 +491,15 @@ func initSchema(db *sql.DB) error {
 		UNIQUE(source_text_hash, target_lang, provider)
 	);
 
+	-- Translation cache lookups by provider and target language, for the hit rate
+	CREATE TABLE IF NOT EXISTS translation_cache_lookups (
+		provider TEXT NOT NULL,
+		target_lang TEXT NOT NULL,
+		hits INTEGER NOT NULL DEFAULT 0,
+		misses INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY(provider, target_lang)
+	);
+
 	-- Article content cache table to store full article content
 	CREATE TABLE IF NOT EXISTS article_contents (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -531,6 +650,18 @@ func runMigrations(db *sql.DB) error {
 	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN freshrss_stream_id TEXT DEFAULT ''`)
 	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN freshrss_item_id TEXT DEFAULT ''`)
 
+	// Migration: Add learned relevance score of articles (NULL until scored)
+	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN relevance_score REAL`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_articles_relevance_score ON articles(relevance_score DESC)`)
+
+	// Migration: Add detected language of articles ('' until detected)
+	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN language TEXT DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN language_confidence REAL DEFAULT 0`)
+
+	// Migration: Add hit counts and manual corrections to the translation cache
+	_, _ = db.Exec(`ALTER TABLE translation_cache ADD COLUMN hits INTEGER DEFAULT 0`)
+	_, _ = db.Exec(`ALTER TABLE translation_cache ADD COLUMN edited BOOLEAN DEFAULT 0`)
+
 	return nil
 }
 
@@ -545,39 +676,46 @@ type TranslationCache struct {
 	CreatedAt      string
 }
 
-// GetCachedTranslation retrieves a translation from cache if available
+// GetCachedTranslation retrieves a translation from cache if available, counting the hit or miss
 func (db *DB) GetCachedTranslation(sourceTextHash, targetLang, provider string) (string, bool, error) {
+	var id int64
 	var translatedText string
 	err := db.QueryRow(
-		`SELECT translated_text FROM translation_cache
+		`SELECT id, translated_text FROM translation_cache
 		 WHERE source_text_hash = ? AND target_lang = ? AND provider = ?`,
 		sourceTextHash, targetLang, provider,
-	).Scan(&translatedText)
+	).Scan(&id, &translatedText)
 
 	if err == sql.ErrNoRows {
+		db.countTranslationCacheLookup(0, provider, targetLang)
 		return "", false, nil
 	}
 	if err != nil {
 		return "", false, err
 	}
+	db.countTranslationCacheLookup(id, provider, targetLang)
 	return translatedText, true, nil
 }
 
-// SetCachedTranslation stores a translation in cache
+// SetCachedTranslation stores a translation in cache. A manually corrected translation of the
+// same text is kept.
 func (db *DB) SetCachedTranslation(sourceTextHash, sourceText, targetLang, translatedText, provider string) error {
 	_, err := db.Exec(
-		`INSERT OR REPLACE INTO translation_cache
+		`INSERT INTO translation_cache
 		 (source_text_hash, source_text, target_lang, translated_text, provider, created_at)
-		 VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		 VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(source_text_hash, target_lang, provider) DO UPDATE SET
+		 source_text = excluded.source_text, translated_text = excluded.translated_text, created_at = CURRENT_TIMESTAMP
+		 WHERE edited = 0`,
 		sourceTextHash, sourceText, targetLang, translatedText, provider,
 	)
 	return err
 }
 
-// CleanupTranslationCache removes cached translations older than maxAgeDays
+// CleanupTranslationCache removes cached translations older than maxAgeDays, except manual corrections
 func (db *DB) CleanupTranslationCache(maxAgeDays int) (int64, error) {
 	result, err := db.Exec(
-		`DELETE FROM translation_cache WHERE created_at < datetime('now', ?)`,
+		`DELETE FROM translation_cache WHERE created_at < datetime('now', ?) AND edited = 0`,
 		fmt.Sprintf("-%d days", maxAgeDays),
 	)
 	if err != nil {
@@ -585,3 +723,17 @@ func (db *DB) CleanupTranslationCache(maxAgeDays int) (int64, error) {
 	}
 	return result.RowsAffected()
 }
+
+// placeholders returns n comma-separated SQL placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// int64Args converts IDs to query arguments
+func int64Args(ids []int64) []interface{} {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return args
+}
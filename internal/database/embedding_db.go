@@ -0,0 +1,159 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// EmbeddingCandidate is an article that has no embedding for the current model yet,
+// with the text to embed
+type EmbeddingCandidate struct {
+	ArticleID int64
+	Title     string
+	Summary   string
+	Content   string // Cached article content, empty if none
+}
+
+// InitEmbeddingTables creates the article_embeddings table if it doesn't exist
+func InitEmbeddingTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS article_embeddings (
+		article_id INTEGER PRIMARY KEY,
+		model TEXT NOT NULL,
+		dims INTEGER NOT NULL,
+		vector BLOB NOT NULL,
+		created_at INTEGER NOT NULL,
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_article_embeddings_model ON article_embeddings(model);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// SaveArticleEmbedding stores or replaces the embedding of an article
+func (db *DB) SaveArticleEmbedding(articleID int64, model string, vector []float32) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO article_embeddings (article_id, model, dims, vector, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		articleID, model, len(vector), encodeVector(vector), time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to save article embedding: %w", err)
+	}
+	return nil
+}
+
+// GetArticleEmbedding returns the embedding of an article for a model
+func (db *DB) GetArticleEmbedding(articleID int64, model string) ([]float32, bool, error) {
+	db.WaitForReady()
+	var blob []byte
+	err := db.QueryRow(`SELECT vector FROM article_embeddings WHERE article_id = ? AND model = ?`,
+		articleID, model).Scan(&blob)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return decodeVector(blob), true, nil
+}
+
+// GetArticlesWithoutEmbedding returns up to limit articles, newest first, that have no
+// embedding for model
+func (db *DB) GetArticlesWithoutEmbedding(model string, limit int) ([]EmbeddingCandidate, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT a.id, COALESCE(a.title, ''), COALESCE(a.summary, ''), COALESCE(c.content, '')
+		FROM articles a
+		LEFT JOIN article_contents c ON c.article_id = a.id
+		LEFT JOIN article_embeddings e ON e.article_id = a.id
+		WHERE e.article_id IS NULL OR e.model != ?
+		ORDER BY a.published_at DESC
+		LIMIT ?`, model, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := make([]EmbeddingCandidate, 0)
+	for rows.Next() {
+		var c EmbeddingCandidate
+		if err := rows.Scan(&c.ArticleID, &c.Title, &c.Summary, &c.Content); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// ForEachArticleEmbedding calls fn with the embedding of every visible article for model.
+// Vectors are streamed so that the archive never has to be held in memory at once.
+func (db *DB) ForEachArticleEmbedding(model string, fn func(articleID int64, vector []float32)) error {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT e.article_id, e.vector
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.model = ? AND a.is_hidden = 0`, model)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int64
+		var blob []byte
+		if err := rows.Scan(&articleID, &blob); err != nil {
+			return err
+		}
+		fn(articleID, decodeVector(blob))
+	}
+	return rows.Err()
+}
+
+// CountArticleEmbeddings returns the number of articles embedded with model
+func (db *DB) CountArticleEmbeddings(model string) (int64, error) {
+	db.WaitForReady()
+	var count int64
+	err := db.QueryRow(`SELECT COUNT(*) FROM article_embeddings WHERE model = ?`, model).Scan(&count)
+	return count, err
+}
+
+// PruneArticleEmbeddings removes embeddings of deleted articles and of models other than model.
+// Nothing is removed while no model is configured.
+func (db *DB) PruneArticleEmbeddings(model string) (int64, error) {
+	if model == "" {
+		return 0, nil
+	}
+	db.WaitForReady()
+	result, err := db.Exec(`
+		DELETE FROM article_embeddings
+		WHERE model != ? OR article_id NOT IN (SELECT id FROM articles)`, model)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// encodeVector serializes a vector as little-endian float32 values
+func encodeVector(vector []float32) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeVector deserializes a vector written by encodeVector
+func decodeVector(buf []byte) []float32 {
+	vector := make([]float32, len(buf)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*i:]))
+	}
+	return vector
+}
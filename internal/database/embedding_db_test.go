@@ -0,0 +1,65 @@
+package database
+
+import "testing"
+
+func TestArticleEmbeddings(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO feeds (title, url) VALUES ('Feed', 'https://example.com/feed')`)
+	if err != nil {
+		t.Fatalf("insert feed error: %v", err)
+	}
+	feedID, _ := res.LastInsertId()
+	for i, title := range []string{"First", "Second", "Hidden"} {
+		if _, err := db.Exec(`INSERT INTO articles (feed_id, title, url, summary, published_at, is_hidden, unique_id) VALUES (?, ?, ?, 'sum', datetime('now'), ?, ?)`,
+			feedID, title, "https://example.com/"+title, i == 2, title); err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+	}
+
+	candidates, err := db.GetArticlesWithoutEmbedding("m1", 10)
+	if err != nil {
+		t.Fatalf("GetArticlesWithoutEmbedding error: %v", err)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("expected 3 candidates, got %d", len(candidates))
+	}
+
+	for _, c := range candidates {
+		if err := db.SaveArticleEmbedding(c.ArticleID, "m1", []float32{1, -0.5, 0.25}); err != nil {
+			t.Fatalf("SaveArticleEmbedding error: %v", err)
+		}
+	}
+	if candidates, _ := db.GetArticlesWithoutEmbedding("m1", 10); len(candidates) != 0 {
+		t.Errorf("expected no candidates after embedding, got %d", len(candidates))
+	}
+	if candidates, _ := db.GetArticlesWithoutEmbedding("m2", 10); len(candidates) != 3 {
+		t.Errorf("expected all articles to need an embedding for a new model, got %d", len(candidates))
+	}
+
+	vector, found, err := db.GetArticleEmbedding(candidates[0].ArticleID, "m1")
+	if err != nil || !found {
+		t.Fatalf("GetArticleEmbedding: found=%v err=%v", found, err)
+	}
+	if len(vector) != 3 || vector[1] != -0.5 || vector[2] != 0.25 {
+		t.Errorf("vector not round-tripped: %v", vector)
+	}
+
+	visible := 0
+	if err := db.ForEachArticleEmbedding("m1", func(int64, []float32) { visible++ }); err != nil {
+		t.Fatalf("ForEachArticleEmbedding error: %v", err)
+	}
+	if visible != 2 {
+		t.Errorf("expected hidden articles to be skipped, got %d embeddings", visible)
+	}
+
+	if removed, err := db.PruneArticleEmbeddings(""); err != nil || removed != 0 {
+		t.Errorf("expected nothing to be pruned without a model: removed=%d err=%v", removed, err)
+	}
+	if removed, err := db.PruneArticleEmbeddings("m2"); err != nil || removed != 3 {
+		t.Errorf("PruneArticleEmbeddings: removed=%d err=%v", removed, err)
+	}
+	if count, _ := db.CountArticleEmbeddings("m1"); count != 0 {
+		t.Errorf("expected embeddings of other models to be pruned, got %d", count)
+	}
+}
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"MrRSS/internal/crypto"
+)
+
+// ExtractionRule holds per-site settings for full-text extraction.
+// Selector fields contain one CSS selector or XPath expression per line.
+type ExtractionRule struct {
+	ID               int64     `json:"id"`
+	Domain           string    `json:"domain"`             // Matches the domain and all of its subdomains
+	KeepSelectors    string    `json:"keep_selectors"`     // Content to keep; readability is skipped when set
+	StripSelectors   string    `json:"strip_selectors"`    // Elements removed before extraction
+	UserAgent        string    `json:"user_agent"`         // Overrides the default user agent
+	Cookie           string    `json:"cookie"`             // Cookie header sent with every request, stored encrypted
+	NextPageSelector string    `json:"next_page_selector"` // Link to the next page of multi-page articles
+	MaxPages         int       `json:"max_pages"`          // Maximum pages to follow, 0 for the default
+	Enabled          bool      `json:"enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// InitExtractionRulesTable creates the extraction_rules table if it doesn't exist
+func InitExtractionRulesTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS extraction_rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		domain TEXT NOT NULL UNIQUE,
+		keep_selectors TEXT DEFAULT '',
+		strip_selectors TEXT DEFAULT '',
+		user_agent TEXT DEFAULT '',
+		cookie TEXT DEFAULT '',
+		next_page_selector TEXT DEFAULT '',
+		max_pages INTEGER DEFAULT 0,
+		enabled BOOLEAN DEFAULT 1,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// NormalizeRuleDomain lowercases a domain and strips schemes, paths and a leading "www."
+func NormalizeRuleDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	if i := strings.LastIndex(domain, ":"); i >= 0 {
+		domain = domain[:i]
+	}
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.TrimPrefix(domain, "www.")
+}
+
+// SaveExtractionRule creates a rule, or updates it when rule.ID is set, and returns its ID
+func (db *DB) SaveExtractionRule(rule *ExtractionRule) (int64, error) {
+	db.WaitForReady()
+
+	domain := NormalizeRuleDomain(rule.Domain)
+	if domain == "" {
+		return 0, fmt.Errorf("domain is required")
+	}
+
+	cookie := rule.Cookie
+	if cookie != "" {
+		encrypted, err := crypto.Encrypt(cookie)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt cookie: %w", err)
+		}
+		cookie = encrypted
+	}
+
+	now := time.Now().Unix()
+	if rule.ID > 0 {
+		result, err := db.Exec(`
+			UPDATE extraction_rules SET domain = ?, keep_selectors = ?, strip_selectors = ?, user_agent = ?,
+				cookie = ?, next_page_selector = ?, max_pages = ?, enabled = ?, updated_at = ?
+			WHERE id = ?`,
+			domain, rule.KeepSelectors, rule.StripSelectors, rule.UserAgent,
+			cookie, rule.NextPageSelector, rule.MaxPages, rule.Enabled, now, rule.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update extraction rule: %w", err)
+		}
+		if affected, _ := result.RowsAffected(); affected == 0 {
+			return 0, sql.ErrNoRows
+		}
+		return rule.ID, nil
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO extraction_rules (domain, keep_selectors, strip_selectors, user_agent, cookie,
+			next_page_selector, max_pages, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		domain, rule.KeepSelectors, rule.StripSelectors, rule.UserAgent, cookie,
+		rule.NextPageSelector, rule.MaxPages, rule.Enabled, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create extraction rule: %w", err)
+	}
+	return result.LastInsertId()
+}
+
+// GetExtractionRules returns all extraction rules ordered by domain
+func (db *DB) GetExtractionRules() ([]ExtractionRule, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT id, domain, keep_selectors, strip_selectors, user_agent, cookie,
+			next_page_selector, max_pages, enabled, created_at, updated_at
+		FROM extraction_rules
+		ORDER BY domain ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get extraction rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]ExtractionRule, 0)
+	for rows.Next() {
+		rule, err := scanExtractionRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+// GetExtractionRuleForHost returns the enabled rule with the most specific domain matching host,
+// or nil if no rule applies
+func (db *DB) GetExtractionRuleForHost(host string) (*ExtractionRule, error) {
+	host = NormalizeRuleDomain(host)
+	if host == "" {
+		return nil, nil
+	}
+
+	// Candidate domains: the host itself and each parent domain
+	candidates := []interface{}{host}
+	for i := strings.Index(host, "."); i >= 0; i = strings.Index(host, ".") {
+		host = host[i+1:]
+		if strings.Contains(host, ".") {
+			candidates = append(candidates, host)
+		}
+	}
+
+	db.WaitForReady()
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(candidates)), ",")
+	row := db.QueryRow(`
+		SELECT id, domain, keep_selectors, strip_selectors, user_agent, cookie,
+			next_page_selector, max_pages, enabled, created_at, updated_at
+		FROM extraction_rules
+		WHERE enabled = 1 AND domain IN (`+placeholders+`)
+		ORDER BY LENGTH(domain) DESC
+		LIMIT 1`, candidates...)
+
+	rule, err := scanExtractionRule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+// DeleteExtractionRule removes an extraction rule
+func (db *DB) DeleteExtractionRule(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`DELETE FROM extraction_rules WHERE id = ?`, id)
+	return err
+}
+
+// scanExtractionRule scans a rule row and decrypts its cookie
+func scanExtractionRule(row interface{ Scan(...interface{}) error }) (*ExtractionRule, error) {
+	var rule ExtractionRule
+	var createdAt, updatedAt int64
+	if err := row.Scan(
+		&rule.ID, &rule.Domain, &rule.KeepSelectors, &rule.StripSelectors, &rule.UserAgent, &rule.Cookie,
+		&rule.NextPageSelector, &rule.MaxPages, &rule.Enabled, &createdAt, &updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	rule.CreatedAt = time.Unix(createdAt, 0)
+	rule.UpdatedAt = time.Unix(updatedAt, 0)
+
+	if crypto.IsEncrypted(rule.Cookie) {
+		decrypted, err := crypto.Decrypt(rule.Cookie)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt cookie for %s: %w", rule.Domain, err)
+		}
+		rule.Cookie = decrypted
+	}
+	return &rule, nil
+}
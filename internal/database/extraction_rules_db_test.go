@@ -0,0 +1,144 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"MrRSS/internal/crypto"
+)
+
+func setupExtractionTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	return db
+}
+
+func TestNormalizeRuleDomain(t *testing.T) {
+	tests := map[string]string{
+		"Example.com":                     "example.com",
+		"https://www.example.com/a/b?c=d": "example.com",
+		"news.example.com:8080":           "news.example.com",
+		"*.example.com":                   "example.com",
+		"  ":                              "",
+	}
+	for input, want := range tests {
+		if got := NormalizeRuleDomain(input); got != want {
+			t.Errorf("NormalizeRuleDomain(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestExtractionRule_HostMatching(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	if _, err := db.SaveExtractionRule(&ExtractionRule{Domain: "example.com", KeepSelectors: "article", Enabled: true}); err != nil {
+		t.Fatalf("SaveExtractionRule error: %v", err)
+	}
+	if _, err := db.SaveExtractionRule(&ExtractionRule{Domain: "blog.example.com", KeepSelectors: ".post", Enabled: true}); err != nil {
+		t.Fatalf("SaveExtractionRule error: %v", err)
+	}
+	if _, err := db.SaveExtractionRule(&ExtractionRule{Domain: "disabled.org", Enabled: false}); err != nil {
+		t.Fatalf("SaveExtractionRule error: %v", err)
+	}
+
+	tests := map[string]string{
+		"example.com":          "example.com",
+		"www.example.com":      "example.com",
+		"news.example.com":     "example.com",
+		"blog.example.com":     "blog.example.com",
+		"a.blog.example.com":   "blog.example.com",
+		"notexample.com":       "",
+		"disabled.org":         "",
+		"example.com.evil.net": "",
+	}
+	for host, want := range tests {
+		rule, err := db.GetExtractionRuleForHost(host)
+		if err != nil {
+			t.Fatalf("GetExtractionRuleForHost(%q) error: %v", host, err)
+		}
+		got := ""
+		if rule != nil {
+			got = rule.Domain
+		}
+		if got != want {
+			t.Errorf("GetExtractionRuleForHost(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestExtractionRule_SaveUpdateDelete(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	id, err := db.SaveExtractionRule(&ExtractionRule{Domain: "https://www.Example.com/", Cookie: "session=secret", Enabled: true})
+	if err != nil {
+		t.Fatalf("SaveExtractionRule error: %v", err)
+	}
+
+	var stored string
+	if err := db.QueryRow("SELECT cookie FROM extraction_rules WHERE id = ?", id).Scan(&stored); err != nil {
+		t.Fatalf("query cookie: %v", err)
+	}
+	if !crypto.IsEncrypted(stored) {
+		t.Errorf("cookie should be stored encrypted, got %q", stored)
+	}
+
+	rules, err := db.GetExtractionRules()
+	if err != nil {
+		t.Fatalf("GetExtractionRules error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Domain != "example.com" || rules[0].Cookie != "session=secret" {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+
+	rule := rules[0]
+	rule.MaxPages = 3
+	if _, err := db.SaveExtractionRule(&rule); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	updated, err := db.GetExtractionRuleForHost("example.com")
+	if err != nil || updated == nil || updated.MaxPages != 3 {
+		t.Fatalf("expected updated rule, got %+v (err %v)", updated, err)
+	}
+
+	if _, err := db.SaveExtractionRule(&ExtractionRule{ID: id + 100, Domain: "missing.com"}); err != sql.ErrNoRows {
+		t.Errorf("updating a missing rule should return sql.ErrNoRows, got %v", err)
+	}
+
+	if err := db.DeleteExtractionRule(id); err != nil {
+		t.Fatalf("DeleteExtractionRule error: %v", err)
+	}
+	if rules, _ := db.GetExtractionRules(); len(rules) != 0 {
+		t.Errorf("expected no rules after delete, got %d", len(rules))
+	}
+}
+
+func TestArticleFullText_RoundTrip(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	if err := db.SetArticleFullText(1, "<p>full</p>"); err != nil {
+		t.Fatalf("SetArticleFullText error: %v", err)
+	}
+	if has, err := db.HasArticleFullText(1); err != nil || !has {
+		t.Fatalf("HasArticleFullText = %v, %v", has, err)
+	}
+	content, found, err := db.GetArticleFullText(1)
+	if err != nil || !found || content != "<p>full</p>" {
+		t.Fatalf("GetArticleFullText = %q, %v, %v", content, found, err)
+	}
+	if err := db.SetArticleContent(2, "<p>feed</p>"); err != nil {
+		t.Fatalf("SetArticleContent error: %v", err)
+	}
+	if has, _ := db.HasArticleFullText(2); has {
+		t.Error("feed content should not be reported as full text")
+	}
+	if _, found, _ := db.GetArticleFullText(2); found {
+		t.Error("GetArticleFullText should ignore feed content")
+	}
+}
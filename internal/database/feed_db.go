@@ -147,7 +147,14 @@ func (db *DB) DeleteFeed(id int64) error {
 		return err
 	}
 	_, err = db.Exec("DELETE FROM feeds WHERE id = ?", id)
-	return err
+	if err != nil {
+		return err
+	}
+	// Chats about the deleted articles go with them, unless pinned
+	if _, err := db.CleanupOrphanedChatSessions(); err != nil {
+		return err
+	}
+	return db.DeleteFeedHealth(id)
 }
 
 // GetFeeds returns all feeds ordered by category and position.
@@ -172,7 +179,10 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 			COALESCE(f.email_imap_port, 993), COALESCE(f.email_username, ''),
 			COALESCE(f.email_password, ''), COALESCE(f.email_folder, 'INBOX'),
 			COALESCE(f.email_last_uid, 0), COALESCE(f.is_freshrss_source, 0),
-			COALESCE(f.freshrss_stream_id, ''),
+			COALESCE(f.freshrss_stream_id, ''), COALESCE(f.full_text_on_ingest, 0),
+			COALESCE(f.background_translate, 0), COALESCE(f.background_summarize, 0),
+			COALESCE(f.summary_prompt_id, 0), COALESCE(f.translation_prompt_id, 0),
+			COALESCE(f.language, ''),
 			(SELECT MAX(a.published_at) FROM articles a WHERE a.feed_id = f.id) as latest_article_time,
 			CAST(COALESCE((
 				SELECT
@@ -214,7 +224,7 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 			&xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode,
 			&autoExpandContent, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort,
 			&emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID,
-			&f.IsFreshRSSSource, &freshRSSStreamID, &latestArticleTimeStr, &f.ArticlesPerMonth,
+			&f.IsFreshRSSSource, &freshRSSStreamID, &f.FullTextOnIngest, &f.BackgroundTranslate, &f.BackgroundSummarize, &f.SummaryPromptID, &f.TranslationPromptID, &f.Language, &latestArticleTimeStr, &f.ArticlesPerMonth,
 		); err != nil {
 			return nil, err
 		}
@@ -267,28 +277,8 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 
 		// Set latest article time from string
 		// Format from database: "2025-11-15 18:39:02 +0000 UTC" (Go's time.String() format)
-		if latestArticleTimeStr.Valid && latestArticleTimeStr.String != "" {
-			timeStr := latestArticleTimeStr.String
-			var parsedTime time.Time
-			var err error
-
-			// Try the format stored in database (Go's time.String() format with UTC zone)
-			// Format: "2006-01-02 15:04:05 +0000 UTC"
-			if parsedTime, err = time.Parse("2006-01-02 15:04:05 -0700 MST", timeStr); err == nil {
-				f.LatestArticleTime = &parsedTime
-			} else if parsedTime, err = time.Parse(time.RFC3339, timeStr); err == nil {
-				// RFC3339 format
-				f.LatestArticleTime = &parsedTime
-			} else if parsedTime, err = time.Parse("2006-01-02T15:04:05Z", timeStr); err == nil {
-				// RFC3339 variant with explicit Z
-				f.LatestArticleTime = &parsedTime
-			} else if parsedTime, err = time.Parse("2006-01-02T15:04:05", timeStr); err == nil {
-				// Without timezone
-				f.LatestArticleTime = &parsedTime
-			} else if parsedTime, err = time.Parse("2006-01-02 15:04:05", timeStr); err == nil {
-				// SQLite default format
-				f.LatestArticleTime = &parsedTime
-			}
+		if latestArticleTimeStr.Valid {
+			f.LatestArticleTime = parseStoredTime(latestArticleTimeStr.String)
 		}
 
 		// Determine last update status based on last_error
@@ -306,12 +296,12 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 // GetFeedByID retrieves a specific feed by its ID.
 func (db *DB) GetFeedByID(id int64) (*models.Feed, error) {
 	db.WaitForReady()
-	row := db.QueryRow("SELECT id, title, url, link, description, category, image_url, COALESCE(position, 0), last_updated, last_error, COALESCE(discovery_completed, 0), COALESCE(script_path, ''), COALESCE(hide_from_timeline, 0), COALESCE(proxy_url, ''), COALESCE(proxy_enabled, 0), COALESCE(refresh_interval, 0), COALESCE(is_image_mode, 0), COALESCE(type, ''), COALESCE(xpath_item, ''), COALESCE(xpath_item_title, ''), COALESCE(xpath_item_content, ''), COALESCE(xpath_item_uri, ''), COALESCE(xpath_item_author, ''), COALESCE(xpath_item_timestamp, ''), COALESCE(xpath_item_time_format, ''), COALESCE(xpath_item_thumbnail, ''), COALESCE(xpath_item_categories, ''), COALESCE(xpath_item_uid, ''), COALESCE(article_view_mode, 'global'), COALESCE(auto_expand_content, 'global'), COALESCE(email_address, ''), COALESCE(email_imap_server, ''), COALESCE(email_imap_port, 993), COALESCE(email_username, ''), COALESCE(email_password, ''), COALESCE(email_folder, 'INBOX'), COALESCE(email_last_uid, 0), COALESCE(is_freshrss_source, 0), COALESCE(freshrss_stream_id, '') FROM feeds WHERE id = ?", id)
+	row := db.QueryRow("SELECT id, title, url, link, description, category, image_url, COALESCE(position, 0), last_updated, last_error, COALESCE(discovery_completed, 0), COALESCE(script_path, ''), COALESCE(hide_from_timeline, 0), COALESCE(proxy_url, ''), COALESCE(proxy_enabled, 0), COALESCE(refresh_interval, 0), COALESCE(is_image_mode, 0), COALESCE(type, ''), COALESCE(xpath_item, ''), COALESCE(xpath_item_title, ''), COALESCE(xpath_item_content, ''), COALESCE(xpath_item_uri, ''), COALESCE(xpath_item_author, ''), COALESCE(xpath_item_timestamp, ''), COALESCE(xpath_item_time_format, ''), COALESCE(xpath_item_thumbnail, ''), COALESCE(xpath_item_categories, ''), COALESCE(xpath_item_uid, ''), COALESCE(article_view_mode, 'global'), COALESCE(auto_expand_content, 'global'), COALESCE(email_address, ''), COALESCE(email_imap_server, ''), COALESCE(email_imap_port, 993), COALESCE(email_username, ''), COALESCE(email_password, ''), COALESCE(email_folder, 'INBOX'), COALESCE(email_last_uid, 0), COALESCE(is_freshrss_source, 0), COALESCE(freshrss_stream_id, ''), COALESCE(full_text_on_ingest, 0), COALESCE(background_translate, 0), COALESCE(background_summarize, 0), COALESCE(summary_prompt_id, 0), COALESCE(translation_prompt_id, 0), COALESCE(language, '') FROM feeds WHERE id = ?", id)
 
 	var f models.Feed
 	var link, category, imageURL, lastError, scriptPath, proxyURL, feedType, xpathItem, xpathItemTitle, xpathItemContent, xpathItemUri, xpathItemAuthor, xpathItemTimestamp, xpathItemTimeFormat, xpathItemThumbnail, xpathItemCategories, xpathItemUid, articleViewMode, autoExpandContent, emailAddress, emailIMAPServer, emailUsername, emailPassword, emailFolder, freshRSSStreamID sql.NullString
 	var lastUpdated sql.NullTime
-	if err := row.Scan(&f.ID, &f.Title, &f.URL, &link, &f.Description, &category, &imageURL, &f.Position, &lastUpdated, &lastError, &f.DiscoveryCompleted, &scriptPath, &f.HideFromTimeline, &proxyURL, &f.ProxyEnabled, &f.RefreshInterval, &f.IsImageMode, &feedType, &xpathItem, &xpathItemTitle, &xpathItemContent, &xpathItemUri, &xpathItemAuthor, &xpathItemTimestamp, &xpathItemTimeFormat, &xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode, &autoExpandContent, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort, &emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID, &f.IsFreshRSSSource, &freshRSSStreamID); err != nil {
+	if err := row.Scan(&f.ID, &f.Title, &f.URL, &link, &f.Description, &category, &imageURL, &f.Position, &lastUpdated, &lastError, &f.DiscoveryCompleted, &scriptPath, &f.HideFromTimeline, &proxyURL, &f.ProxyEnabled, &f.RefreshInterval, &f.IsImageMode, &feedType, &xpathItem, &xpathItemTitle, &xpathItemContent, &xpathItemUri, &xpathItemAuthor, &xpathItemTimestamp, &xpathItemTimeFormat, &xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode, &autoExpandContent, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort, &emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID, &f.IsFreshRSSSource, &freshRSSStreamID, &f.FullTextOnIngest, &f.BackgroundTranslate, &f.BackgroundSummarize, &f.SummaryPromptID, &f.TranslationPromptID, &f.Language); err != nil {
 		return nil, err
 	}
 	f.Link = link.String
@@ -394,6 +384,29 @@ func (db *DB) UpdateFeedWithPosition(id int64, title, url, category, scriptPath
 	return err
 }
 
+// SetFeedFullTextOnIngest sets whether full article text is extracted when new articles of a feed are saved.
+func (db *DB) SetFeedFullTextOnIngest(id int64, enabled bool) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET full_text_on_ingest = ? WHERE id = ?", enabled, id)
+	return err
+}
+
+// SetFeedBackgroundJobs sets whether the titles of new articles of a feed are translated and their
+// summaries generated in the background when they are saved.
+func (db *DB) SetFeedBackgroundJobs(id int64, translate, summarize bool) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET background_translate = ?, background_summarize = ? WHERE id = ?", translate, summarize, id)
+	return err
+}
+
+// SetFeedPromptTemplates sets the prompt templates of a feed's AI summaries and translations,
+// 0 to use the template of its category or the default one.
+func (db *DB) SetFeedPromptTemplates(id, summaryPromptID, translationPromptID int64) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET summary_prompt_id = ?, translation_prompt_id = ? WHERE id = ?", summaryPromptID, translationPromptID, id)
+	return err
+}
+
 // UpdateFeedCategory updates a feed's category.
 func (db *DB) UpdateFeedCategory(id int64, category string) error {
 	db.WaitForReady()
@@ -422,11 +435,16 @@ func (db *DB) UpdateFeedError(id int64, errorMsg string) error {
 	return err
 }
 
-// ClearAllFeedErrors clears error messages for all feeds.
-func (db *DB) ClearAllFeedErrors() error {
+// ClearFeedErrors clears the error messages of the feeds with the given IDs.
+func (db *DB) ClearFeedErrors(ids []int64) error {
 	db.WaitForReady()
-	_, err := db.Exec("UPDATE feeds SET last_error = ''")
-	return err
+	for start := 0; start < len(ids); start += sqlIDChunk {
+		chunk := ids[start:min(start+sqlIDChunk, len(ids))]
+		if _, err := db.Exec("UPDATE feeds SET last_error = '' WHERE id IN ("+placeholders(len(chunk))+")", int64Args(chunk)...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // UpdateFeedLastUpdated updates a feed's last_updated timestamp.
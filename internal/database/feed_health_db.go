@@ -0,0 +1,357 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// maxFetchHistoryPerFeed limits how many fetch attempts are kept per feed
+const maxFetchHistoryPerFeed = 50
+
+// FetchHistoryEntry represents a single recorded feed fetch attempt
+type FetchHistoryEntry struct {
+	ID           int64     `json:"id"`
+	FeedID       int64     `json:"feed_id"`
+	FetchedAt    time.Time `json:"fetched_at"`
+	HTTPStatus   int       `json:"http_status,omitempty"` // 0 when the fetch did not go through HTTP or failed before a response
+	DurationMs   int64     `json:"duration_ms"`
+	ItemCount    int       `json:"item_count"`
+	ErrorClass   string    `json:"error_class,omitempty"` // Empty on success
+	ErrorMessage string    `json:"error_message,omitempty"`
+}
+
+// FeedHealthRecord holds the aggregated health state of a feed
+type FeedHealthRecord struct {
+	FeedID              int64
+	Title               string
+	URL                 string
+	Category            string
+	LastError           string
+	RefreshInterval     int
+	ConsecutiveFailures int
+	LastHTTPStatus      int
+	LastSuccessAt       *time.Time
+	NextRetryAt         *time.Time
+	PausedReason        string
+	LatestArticleTime   *time.Time
+	AvgDurationMs       int64 // Average duration of recent successful fetches
+}
+
+// InitFeedHealthTables creates the feed_fetch_history and feed_health tables if they don't exist
+func InitFeedHealthTables(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS feed_fetch_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		fetched_at INTEGER NOT NULL,
+		http_status INTEGER DEFAULT 0,
+		duration_ms INTEGER DEFAULT 0,
+		item_count INTEGER DEFAULT 0,
+		error_class TEXT DEFAULT '',
+		error_message TEXT DEFAULT ''
+	);
+
+	CREATE TABLE IF NOT EXISTS feed_health (
+		feed_id INTEGER PRIMARY KEY,
+		consecutive_failures INTEGER DEFAULT 0,
+		last_http_status INTEGER DEFAULT 0,
+		last_success_at INTEGER,
+		next_retry_at INTEGER,
+		paused_reason TEXT DEFAULT ''
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_feed_fetch_history_feed ON feed_fetch_history(feed_id, fetched_at DESC);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// RecordFetchAttempt stores a fetch attempt and trims old history for the feed
+func (db *DB) RecordFetchAttempt(entry FetchHistoryEntry) error {
+	db.WaitForReady()
+
+	if entry.FetchedAt.IsZero() {
+		entry.FetchedAt = time.Now()
+	}
+
+	_, err := db.Exec(`
+		INSERT INTO feed_fetch_history (feed_id, fetched_at, http_status, duration_ms, item_count, error_class, error_message)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.FeedID, entry.FetchedAt.Unix(), entry.HTTPStatus, entry.DurationMs, entry.ItemCount, entry.ErrorClass, entry.ErrorMessage,
+	)
+	if err != nil {
+		return fmt.Errorf("record fetch attempt: %w", err)
+	}
+
+	_, err = db.Exec(`
+		DELETE FROM feed_fetch_history
+		WHERE feed_id = ? AND id NOT IN (
+			SELECT id FROM feed_fetch_history WHERE feed_id = ? ORDER BY fetched_at DESC, id DESC LIMIT ?
+		)`,
+		entry.FeedID, entry.FeedID, maxFetchHistoryPerFeed,
+	)
+	if err != nil {
+		return fmt.Errorf("trim fetch history: %w", err)
+	}
+
+	if entry.HTTPStatus != 0 {
+		_, err = db.Exec(`
+			INSERT INTO feed_health (feed_id, last_http_status) VALUES (?, ?)
+			ON CONFLICT(feed_id) DO UPDATE SET last_http_status = excluded.last_http_status`,
+			entry.FeedID, entry.HTTPStatus,
+		)
+	}
+	return err
+}
+
+// GetFetchHistory returns the most recent fetch attempts for a feed, newest first
+func (db *DB) GetFetchHistory(feedID int64, limit int) ([]FetchHistoryEntry, error) {
+	db.WaitForReady()
+
+	if limit <= 0 {
+		limit = maxFetchHistoryPerFeed
+	}
+
+	rows, err := db.Query(`
+		SELECT id, feed_id, fetched_at, http_status, duration_ms, item_count, COALESCE(error_class, ''), COALESCE(error_message, '')
+		FROM feed_fetch_history
+		WHERE feed_id = ?
+		ORDER BY fetched_at DESC, id DESC
+		LIMIT ?`,
+		feedID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []FetchHistoryEntry
+	for rows.Next() {
+		var e FetchHistoryEntry
+		var fetchedAt int64
+		if err := rows.Scan(&e.ID, &e.FeedID, &fetchedAt, &e.HTTPStatus, &e.DurationMs, &e.ItemCount, &e.ErrorClass, &e.ErrorMessage); err != nil {
+			return nil, err
+		}
+		e.FetchedAt = time.Unix(fetchedAt, 0)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// RecordFeedSuccess resets the failure counter and backoff of a feed, and resumes it if it was
+// paused, such as after a manual refresh succeeded
+func (db *DB) RecordFeedSuccess(feedID int64) error {
+	db.WaitForReady()
+
+	_, err := db.Exec(`
+		INSERT INTO feed_health (feed_id, consecutive_failures, last_success_at, next_retry_at)
+		VALUES (?, 0, ?, NULL)
+		ON CONFLICT(feed_id) DO UPDATE SET
+			consecutive_failures = 0,
+			last_success_at = excluded.last_success_at,
+			next_retry_at = NULL,
+			paused_reason = ''`,
+		feedID, time.Now().Unix(),
+	)
+	return err
+}
+
+// RecordFeedFailure increments the failure counter of a feed and returns the new count
+func (db *DB) RecordFeedFailure(feedID int64) (int, error) {
+	db.WaitForReady()
+
+	_, err := db.Exec(`
+		INSERT INTO feed_health (feed_id, consecutive_failures) VALUES (?, 1)
+		ON CONFLICT(feed_id) DO UPDATE SET consecutive_failures = consecutive_failures + 1`,
+		feedID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures int
+	err = db.QueryRow("SELECT consecutive_failures FROM feed_health WHERE feed_id = ?", feedID).Scan(&failures)
+	return failures, err
+}
+
+// SetFeedNextRetry sets when a failing feed may be refreshed again by scheduled refreshes.
+// A zero time clears the backoff.
+func (db *DB) SetFeedNextRetry(feedID int64, next time.Time) error {
+	db.WaitForReady()
+
+	var value interface{}
+	if !next.IsZero() {
+		value = next.Unix()
+	}
+	_, err := db.Exec(`
+		INSERT INTO feed_health (feed_id, next_retry_at) VALUES (?, ?)
+		ON CONFLICT(feed_id) DO UPDATE SET next_retry_at = excluded.next_retry_at`,
+		feedID, value,
+	)
+	return err
+}
+
+// GetFeedBackoffs returns the feeds whose backoff has not yet expired, mapped to their next retry time
+func (db *DB) GetFeedBackoffs() (map[int64]time.Time, error) {
+	db.WaitForReady()
+
+	rows, err := db.Query("SELECT feed_id, next_retry_at FROM feed_health WHERE next_retry_at IS NOT NULL AND next_retry_at > ?", time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	backoffs := make(map[int64]time.Time)
+	for rows.Next() {
+		var feedID, next int64
+		if err := rows.Scan(&feedID, &next); err != nil {
+			return nil, err
+		}
+		backoffs[feedID] = time.Unix(next, 0)
+	}
+	return backoffs, rows.Err()
+}
+
+// UpdateFeedURL changes the URL of a feed (used when a feed permanently redirects).
+// The update is skipped if another feed already uses the new URL.
+func (db *DB) UpdateFeedURL(feedID int64, url string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE feeds SET url = ?
+		WHERE id = ? AND NOT EXISTS (SELECT 1 FROM feeds WHERE url = ? AND id != ?)`,
+		url, feedID, url, feedID,
+	)
+	return err
+}
+
+// PauseFeed stops scheduled refreshes of a feed and records why it was paused.
+// The refresh interval is kept, so that it applies again once the feed is resumed.
+func (db *DB) PauseFeed(feedID int64, reason string) error {
+	db.WaitForReady()
+
+	if _, err := db.Exec("UPDATE feeds SET last_error = ? WHERE id = ?", reason, feedID); err != nil {
+		return err
+	}
+	_, err := db.Exec(`
+		INSERT INTO feed_health (feed_id, paused_reason) VALUES (?, ?)
+		ON CONFLICT(feed_id) DO UPDATE SET paused_reason = excluded.paused_reason`,
+		feedID, reason,
+	)
+	return err
+}
+
+// ClearFeedPause resumes scheduled refreshes of a paused feed
+func (db *DB) ClearFeedPause(feedID int64) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feed_health SET paused_reason = '' WHERE feed_id = ?", feedID)
+	return err
+}
+
+// GetPausedFeeds returns the paused feeds, mapped to why they were paused
+func (db *DB) GetPausedFeeds() (map[int64]string, error) {
+	db.WaitForReady()
+
+	rows, err := db.Query("SELECT feed_id, paused_reason FROM feed_health WHERE paused_reason != ''")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	paused := make(map[int64]string)
+	for rows.Next() {
+		var feedID int64
+		var reason string
+		if err := rows.Scan(&feedID, &reason); err != nil {
+			return nil, err
+		}
+		paused[feedID] = reason
+	}
+	return paused, rows.Err()
+}
+
+// DeleteFeedHealth removes health state and fetch history of a feed
+func (db *DB) DeleteFeedHealth(feedID int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec("DELETE FROM feed_fetch_history WHERE feed_id = ?", feedID); err != nil {
+		return err
+	}
+	_, err := db.Exec("DELETE FROM feed_health WHERE feed_id = ?", feedID)
+	return err
+}
+
+// GetFeedHealthRecords returns the health state of all non-FreshRSS feeds
+func (db *DB) GetFeedHealthRecords() ([]FeedHealthRecord, error) {
+	db.WaitForReady()
+
+	rows, err := db.Query(`
+		SELECT
+			f.id, COALESCE(f.title, ''), COALESCE(f.url, ''), COALESCE(f.category, ''),
+			COALESCE(f.last_error, ''), COALESCE(f.refresh_interval, 0),
+			COALESCE(h.consecutive_failures, 0), COALESCE(h.last_http_status, 0),
+			h.last_success_at, h.next_retry_at, COALESCE(h.paused_reason, ''),
+			(SELECT MAX(a.published_at) FROM articles a WHERE a.feed_id = f.id),
+			(SELECT AVG(fh.duration_ms) FROM feed_fetch_history fh WHERE fh.feed_id = f.id AND COALESCE(fh.error_class, '') = '')
+		FROM feeds f
+		LEFT JOIN feed_health h ON h.feed_id = f.id
+		WHERE COALESCE(f.is_freshrss_source, 0) = 0
+		ORDER BY f.category ASC, f.position ASC, f.id ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []FeedHealthRecord
+	for rows.Next() {
+		var r FeedHealthRecord
+		var lastSuccess, nextRetry sql.NullInt64
+		var latestArticle sql.NullString
+		var avgDuration sql.NullFloat64
+		if err := rows.Scan(
+			&r.FeedID, &r.Title, &r.URL, &r.Category, &r.LastError, &r.RefreshInterval,
+			&r.ConsecutiveFailures, &r.LastHTTPStatus, &lastSuccess, &nextRetry, &r.PausedReason,
+			&latestArticle, &avgDuration,
+		); err != nil {
+			return nil, err
+		}
+		if lastSuccess.Valid {
+			t := time.Unix(lastSuccess.Int64, 0)
+			r.LastSuccessAt = &t
+		}
+		if nextRetry.Valid {
+			t := time.Unix(nextRetry.Int64, 0)
+			r.NextRetryAt = &t
+		}
+		if latestArticle.Valid {
+			r.LatestArticleTime = parseStoredTime(latestArticle.String)
+		}
+		if avgDuration.Valid {
+			r.AvgDurationMs = int64(avgDuration.Float64)
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// parseStoredTime parses a timestamp as stored by the SQLite driver.
+// Returns nil if the value cannot be parsed.
+func parseStoredTime(timeStr string) *time.Time {
+	if timeStr == "" {
+		return nil
+	}
+
+	layouts := []string{
+		"2006-01-02 15:04:05 -0700 MST", // Go's time.String() format used by the driver
+		time.RFC3339,
+		"2006-01-02T15:04:05Z",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05", // SQLite default format
+	}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, timeStr); err == nil {
+			return &parsed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func setupHealthTestDB(t *testing.T) (*DB, int64) {
+	t.Helper()
+	db, err := NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Health", URL: "https://example.com/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	return db, feedID
+}
+
+func TestFetchHistory_RecordAndTrim(t *testing.T) {
+	db, feedID := setupHealthTestDB(t)
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < maxFetchHistoryPerFeed+5; i++ {
+		entry := FetchHistoryEntry{
+			FeedID:     feedID,
+			FetchedAt:  base.Add(time.Duration(i) * time.Second),
+			HTTPStatus: 200,
+			DurationMs: int64(i),
+			ItemCount:  i,
+		}
+		if err := db.RecordFetchAttempt(entry); err != nil {
+			t.Fatalf("RecordFetchAttempt error: %v", err)
+		}
+	}
+
+	history, err := db.GetFetchHistory(feedID, 0)
+	if err != nil {
+		t.Fatalf("GetFetchHistory error: %v", err)
+	}
+	if len(history) != maxFetchHistoryPerFeed {
+		t.Fatalf("expected %d entries after trimming, got %d", maxFetchHistoryPerFeed, len(history))
+	}
+	if history[0].ItemCount != maxFetchHistoryPerFeed+4 {
+		t.Errorf("expected newest entry first, got item count %d", history[0].ItemCount)
+	}
+
+	limited, err := db.GetFetchHistory(feedID, 3)
+	if err != nil {
+		t.Fatalf("GetFetchHistory error: %v", err)
+	}
+	if len(limited) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(limited))
+	}
+}
+
+func TestFeedHealth_FailuresAndBackoff(t *testing.T) {
+	db, feedID := setupHealthTestDB(t)
+
+	for want := 1; want <= 3; want++ {
+		got, err := db.RecordFeedFailure(feedID)
+		if err != nil {
+			t.Fatalf("RecordFeedFailure error: %v", err)
+		}
+		if got != want {
+			t.Errorf("RecordFeedFailure = %d, want %d", got, want)
+		}
+	}
+
+	if err := db.SetFeedNextRetry(feedID, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("SetFeedNextRetry error: %v", err)
+	}
+	backoffs, err := db.GetFeedBackoffs()
+	if err != nil {
+		t.Fatalf("GetFeedBackoffs error: %v", err)
+	}
+	if _, ok := backoffs[feedID]; !ok {
+		t.Fatal("expected feed to be backing off")
+	}
+
+	if err := db.RecordFeedSuccess(feedID); err != nil {
+		t.Fatalf("RecordFeedSuccess error: %v", err)
+	}
+	backoffs, err = db.GetFeedBackoffs()
+	if err != nil {
+		t.Fatalf("GetFeedBackoffs error: %v", err)
+	}
+	if len(backoffs) != 0 {
+		t.Errorf("expected backoff to be cleared after success, got %v", backoffs)
+	}
+
+	records, err := db.GetFeedHealthRecords()
+	if err != nil {
+		t.Fatalf("GetFeedHealthRecords error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0].ConsecutiveFailures != 0 || records[0].LastSuccessAt == nil {
+		t.Errorf("unexpected record after success: %+v", records[0])
+	}
+}
+
+func TestFeedHealth_PauseAndURLUpdate(t *testing.T) {
+	db, feedID := setupHealthTestDB(t)
+
+	before, err := db.GetFeedByID(feedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID error: %v", err)
+	}
+	if err := db.PauseFeed(feedID, "gone"); err != nil {
+		t.Fatalf("PauseFeed error: %v", err)
+	}
+	feed, err := db.GetFeedByID(feedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID error: %v", err)
+	}
+	if feed.RefreshInterval != before.RefreshInterval || feed.LastError != "gone" {
+		t.Errorf("expected the refresh interval kept and the reason as error, got %d %q", feed.RefreshInterval, feed.LastError)
+	}
+	if paused, err := db.GetPausedFeeds(); err != nil || paused[feedID] != "gone" {
+		t.Errorf("expected feed to be paused, got %v (%v)", paused, err)
+	}
+
+	if err := db.UpdateFeedURL(feedID, "https://example.org/new.xml"); err != nil {
+		t.Fatalf("UpdateFeedURL error: %v", err)
+	}
+	feed, _ = db.GetFeedByID(feedID)
+	if feed.URL != "https://example.org/new.xml" {
+		t.Errorf("expected updated URL, got %s", feed.URL)
+	}
+
+	// The URL is not changed if another feed already uses it
+	otherID, err := db.AddFeed(&models.Feed{Title: "Other", URL: "https://example.net/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.UpdateFeedURL(otherID, "https://example.org/new.xml"); err != nil {
+		t.Fatalf("UpdateFeedURL error: %v", err)
+	}
+	other, _ := db.GetFeedByID(otherID)
+	if other.URL != "https://example.net/feed.xml" {
+		t.Errorf("expected URL to stay unchanged, got %s", other.URL)
+	}
+
+	records, err := db.GetFeedHealthRecords()
+	if err != nil {
+		t.Fatalf("GetFeedHealthRecords error: %v", err)
+	}
+	for _, r := range records {
+		if r.FeedID == feedID && r.PausedReason != "gone" {
+			t.Errorf("expected paused reason to be recorded, got %q", r.PausedReason)
+		}
+	}
+
+	if err := db.ClearFeedPause(feedID); err != nil {
+		t.Fatalf("ClearFeedPause error: %v", err)
+	}
+	if paused, err := db.GetPausedFeeds(); err != nil || len(paused) != 0 {
+		t.Errorf("expected feed to be resumed, got %v (%v)", paused, err)
+	}
+
+	if err := db.DeleteFeed(feedID); err != nil {
+		t.Fatalf("DeleteFeed error: %v", err)
+	}
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM feed_health WHERE feed_id = ?", feedID).Scan(&count)
+	if count != 0 {
+		t.Errorf("expected health rows to be deleted with the feed, got %d", count)
+	}
+}
+
+func TestParseStoredTime(t *testing.T) {
+	for _, s := range []string{
+		"2025-11-15 18:39:02 +0000 UTC",
+		"2025-11-15T18:39:02Z",
+		"2025-11-15T18:39:02",
+		"2025-11-15 18:39:02",
+	} {
+		if parseStoredTime(s) == nil {
+			t.Errorf("parseStoredTime(%q) returned nil", s)
+		}
+	}
+	if parseStoredTime("not a time") != nil {
+		t.Error("expected nil for invalid time")
+	}
+}
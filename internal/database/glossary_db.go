@@ -0,0 +1,174 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"MrRSS/internal/models"
+)
+
+// InitGlossaryTable creates the translation glossary table if it doesn't exist
+func InitGlossaryTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS translation_glossary (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		target_lang TEXT NOT NULL DEFAULT '',
+		term TEXT NOT NULL,
+		translation TEXT NOT NULL DEFAULT '',
+		protected BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(target_lang, term)
+	);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// normalizeGlossaryLang reduces a language code to its ISO 639-1 part ("zh-CN" -> "zh")
+func normalizeGlossaryLang(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if len(lang) > 2 {
+		lang = lang[:2]
+	}
+	return lang
+}
+
+// GetGlossaryEntries returns all glossary entries ordered by language and term
+func (db *DB) GetGlossaryEntries() ([]models.GlossaryEntry, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT id, target_lang, term, translation, protected
+		FROM translation_glossary
+		ORDER BY target_lang ASC, term ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get glossary: %w", err)
+	}
+	return scanGlossaryEntries(rows)
+}
+
+// GetGlossary returns the glossary entries applied when translating to targetLang: the entries of
+// the language, then the entries for every language
+func (db *DB) GetGlossary(targetLang string) ([]models.GlossaryEntry, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT id, target_lang, term, translation, protected
+		FROM translation_glossary
+		WHERE target_lang = ? OR target_lang = ''
+		ORDER BY target_lang DESC, term ASC`, normalizeGlossaryLang(targetLang))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get glossary: %w", err)
+	}
+	return scanGlossaryEntries(rows)
+}
+
+// SaveGlossaryEntry creates an entry, or updates it when entry.ID is set, and returns its ID.
+// The cached translations of texts containing the term, before and after an update, are invalidated.
+func (db *DB) SaveGlossaryEntry(entry *models.GlossaryEntry) (int64, error) {
+	db.WaitForReady()
+
+	term := strings.TrimSpace(entry.Term)
+	if term == "" {
+		return 0, fmt.Errorf("term is required")
+	}
+	targetLang := normalizeGlossaryLang(entry.TargetLang)
+	translation := strings.TrimSpace(entry.Translation)
+	if entry.Protected {
+		translation = ""
+	}
+
+	id := entry.ID
+	if id > 0 {
+		old, err := db.getGlossaryEntry(id)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := db.Exec(`
+			UPDATE translation_glossary SET target_lang = ?, term = ?, translation = ?, protected = ?
+			WHERE id = ?`,
+			targetLang, term, translation, entry.Protected, id); err != nil {
+			return 0, fmt.Errorf("failed to update glossary entry: %w", err)
+		}
+		if err := db.invalidateGlossaryTerm(old.TargetLang, old.Term); err != nil {
+			return 0, err
+		}
+	} else {
+		result, err := db.Exec(`
+			INSERT INTO translation_glossary (target_lang, term, translation, protected)
+			VALUES (?, ?, ?, ?)`,
+			targetLang, term, translation, entry.Protected)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create glossary entry: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, db.invalidateGlossaryTerm(targetLang, term)
+}
+
+// DeleteGlossaryEntry removes a glossary entry and invalidates the cached translations of texts
+// containing its term
+func (db *DB) DeleteGlossaryEntry(id int64) error {
+	db.WaitForReady()
+	entry, err := db.getGlossaryEntry(id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(`DELETE FROM translation_glossary WHERE id = ?`, id); err != nil {
+		return err
+	}
+	return db.invalidateGlossaryTerm(entry.TargetLang, entry.Term)
+}
+
+// getGlossaryEntry returns a glossary entry, or sql.ErrNoRows if there is none with the ID
+func (db *DB) getGlossaryEntry(id int64) (*models.GlossaryEntry, error) {
+	var entry models.GlossaryEntry
+	err := db.QueryRow(`
+		SELECT id, target_lang, term, translation, protected
+		FROM translation_glossary WHERE id = ?`, id).
+		Scan(&entry.ID, &entry.TargetLang, &entry.Term, &entry.Translation, &entry.Protected)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// invalidateGlossaryTerm removes the cached translations to targetLang, or to every language when
+// it is empty, whose source text contains term, along with the stored article translations
+// containing it, so they are translated again with the current glossary
+func (db *DB) invalidateGlossaryTerm(targetLang, term string) error {
+	langFilter, args := "", []interface{}{term}
+	if targetLang != "" {
+		langFilter = ` AND lower(substr(target_lang, 1, 2)) = ?`
+		args = append(args, targetLang)
+	}
+
+	// Manual corrections are kept, the user chose their wording
+	if _, err := db.Exec(`DELETE FROM translation_cache WHERE instr(source_text, ?) > 0 AND edited = 0`+langFilter, args...); err != nil {
+		return fmt.Errorf("failed to invalidate cached translations: %w", err)
+	}
+	// The bilingual body holds the original text next to its translation
+	if _, err := db.Exec(`DELETE FROM article_translations WHERE instr(bilingual, ?) > 0`+langFilter, args...); err != nil {
+		return fmt.Errorf("failed to invalidate article translations: %w", err)
+	}
+	return nil
+}
+
+// scanGlossaryEntries scans glossary entry rows and closes them
+func scanGlossaryEntries(rows *sql.Rows) ([]models.GlossaryEntry, error) {
+	defer rows.Close()
+	entries := make([]models.GlossaryEntry, 0)
+	for rows.Next() {
+		var entry models.GlossaryEntry
+		if err := rows.Scan(&entry.ID, &entry.TargetLang, &entry.Term, &entry.Translation, &entry.Protected); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestGlossary(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	if _, err := db.SaveGlossaryEntry(&models.GlossaryEntry{Term: "  "}); err == nil {
+		t.Fatal("expected an error for an empty term")
+	}
+	protectedID, err := db.SaveGlossaryEntry(&models.GlossaryEntry{Term: "Kubernetes", Translation: "ignored", Protected: true})
+	if err != nil {
+		t.Fatalf("SaveGlossaryEntry error: %v", err)
+	}
+	termID, err := db.SaveGlossaryEntry(&models.GlossaryEntry{TargetLang: "zh-CN", Term: " pull request ", Translation: "拉取请求"})
+	if err != nil {
+		t.Fatalf("SaveGlossaryEntry error: %v", err)
+	}
+	if _, err := db.SaveGlossaryEntry(&models.GlossaryEntry{TargetLang: "de", Term: "pull request", Translation: "Pull Request"}); err != nil {
+		t.Fatalf("SaveGlossaryEntry error: %v", err)
+	}
+
+	glossary, err := db.GetGlossary("zh")
+	if err != nil || len(glossary) != 2 {
+		t.Fatalf("expected the zh and all-language entries, got %+v (%v)", glossary, err)
+	}
+	if glossary[0].Term != "pull request" || glossary[0].TargetLang != "zh" || glossary[1].Term != "Kubernetes" || glossary[1].Translation != "" {
+		t.Errorf("expected normalized entries, language entries first, got %+v", glossary)
+	}
+
+	// Editing a term invalidates the cached translations containing it, in that language only
+	for _, c := range []struct{ text, lang string }{
+		{"Open a pull request", "zh"},
+		{"Open a pull request", "de"},
+		{"Deploy to Kubernetes", "fr"},
+		{"Unrelated", "zh"},
+	} {
+		if err := db.SetCachedTranslation(c.text+c.lang, c.text, c.lang, "translated", "google"); err != nil {
+			t.Fatalf("SetCachedTranslation error: %v", err)
+		}
+	}
+	if _, err := db.SaveGlossaryEntry(&models.GlossaryEntry{ID: termID, TargetLang: "zh", Term: "pull request", Translation: "合并请求"}); err != nil {
+		t.Fatalf("SaveGlossaryEntry update error: %v", err)
+	}
+	cached := func(text, lang string) bool {
+		_, found, _ := db.GetCachedTranslation(text+lang, lang, "google")
+		return found
+	}
+	if cached("Open a pull request", "zh") || !cached("Open a pull request", "de") || !cached("Unrelated", "zh") {
+		t.Error("expected only the zh translations containing the term to be invalidated")
+	}
+
+	// Protected terms apply to every language
+	if err := db.DeleteGlossaryEntry(protectedID); err != nil {
+		t.Fatalf("DeleteGlossaryEntry error: %v", err)
+	}
+	if cached("Deploy to Kubernetes", "fr") {
+		t.Error("expected the translations containing the deleted term to be invalidated")
+	}
+
+	if _, err := db.SaveGlossaryEntry(&models.GlossaryEntry{ID: 999, Term: "missing"}); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows when updating a missing entry, got %v", err)
+	}
+	entries, err := db.GetGlossaryEntries()
+	if err != nil || len(entries) != 2 || entries[0].TargetLang != "de" {
+		t.Errorf("unexpected glossary entries: %+v (%v)", entries, err)
+	}
+}
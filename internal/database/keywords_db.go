@@ -0,0 +1,225 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// ArticleKeyword is a key phrase extracted from an article
+type ArticleKeyword struct {
+	Keyword string  `json:"keyword"`
+	Score   float64 `json:"score"` // From 0 to 1, relative to the article's best keyword
+}
+
+// KeywordDocument is an article with the text its keywords are extracted from
+type KeywordDocument struct {
+	ArticleID int64
+	Language  string
+	Title     string
+	Content   string // Cached article content, or the summary from the feed when there is none
+}
+
+// TrendingKeyword is a keyword with the number of articles mentioning it in a time window
+// and in the window of equal length before it
+type TrendingKeyword struct {
+	Keyword       string `json:"keyword"`
+	Count         int    `json:"count"`
+	PreviousCount int    `json:"previous_count"`
+}
+
+// InitArticleKeywordsTable creates the article keywords table if it doesn't exist
+func InitArticleKeywordsTable(db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS article_keywords (
+		article_id INTEGER NOT NULL,
+		keyword TEXT NOT NULL,
+		score REAL NOT NULL DEFAULT 0,
+		PRIMARY KEY(article_id, keyword),
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
+	CREATE INDEX IF NOT EXISTS idx_article_keywords_keyword ON article_keywords(keyword);
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// SaveArticleKeywords replaces the keywords of an article
+func (db *DB) SaveArticleKeywords(articleID int64, keywords []ArticleKeyword) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM article_keywords WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to clear article keywords: %w", err)
+	}
+	for _, k := range keywords {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO article_keywords (article_id, keyword, score) VALUES (?, ?, ?)`,
+			articleID, k.Keyword, k.Score); err != nil {
+			return fmt.Errorf("failed to save article keyword: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetArticleKeywords returns the keywords of an article, best first
+func (db *DB) GetArticleKeywords(articleID int64) ([]ArticleKeyword, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT keyword, score FROM article_keywords
+		WHERE article_id = ?
+		ORDER BY score DESC, keyword`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := []ArticleKeyword{}
+	for rows.Next() {
+		var k ArticleKeyword
+		if err := rows.Scan(&k.Keyword, &k.Score); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords, rows.Err()
+}
+
+// AttachArticleKeywords sets the keywords of articles, best first
+func (db *DB) AttachArticleKeywords(articles []models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	db.WaitForReady()
+	index := make(map[int64]int, len(articles))
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		index[a.ID] = i
+		ids[i] = a.ID
+	}
+
+	for start := 0; start < len(ids); start += sqlIDChunk {
+		chunk := ids[start:min(start+sqlIDChunk, len(ids))]
+		rows, err := db.Query(`
+			SELECT article_id, keyword FROM article_keywords
+			WHERE article_id IN (`+placeholders(len(chunk))+`)
+			ORDER BY article_id, score DESC, keyword`, int64Args(chunk)...)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var articleID int64
+			var keyword string
+			if err := rows.Scan(&articleID, &keyword); err != nil {
+				rows.Close()
+				return err
+			}
+			if i, ok := index[articleID]; ok {
+				articles[i].Keywords = append(articles[i].Keywords, keyword)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const keywordDocumentColumns = `
+	a.id, COALESCE(a.language, ''), COALESCE(a.title, ''), COALESCE(NULLIF(c.content, ''), a.summary, '')
+	FROM articles a
+	LEFT JOIN article_contents c ON c.article_id = a.id`
+
+// GetKeywordDocuments returns the articles with the given IDs
+func (db *DB) GetKeywordDocuments(ids []int64) ([]KeywordDocument, error) {
+	db.WaitForReady()
+	var documents []KeywordDocument
+	for start := 0; start < len(ids); start += sqlIDChunk {
+		chunk := ids[start:min(start+sqlIDChunk, len(ids))]
+		chunkDocuments, err := db.queryKeywordDocuments(`
+			SELECT `+keywordDocumentColumns+`
+			WHERE a.id IN (`+placeholders(len(chunk))+`)`, int64Args(chunk)...)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, chunkDocuments...)
+	}
+	return documents, nil
+}
+
+// GetArticlesWithoutKeywords returns up to limit articles that have no keywords yet, newest first
+func (db *DB) GetArticlesWithoutKeywords(limit int) ([]KeywordDocument, error) {
+	db.WaitForReady()
+	return db.queryKeywordDocuments(`
+		SELECT `+keywordDocumentColumns+`
+		WHERE NOT EXISTS (SELECT 1 FROM article_keywords k WHERE k.article_id = a.id)
+		ORDER BY a.published_at DESC
+		LIMIT ?`, limit)
+}
+
+func (db *DB) queryKeywordDocuments(query string, args ...interface{}) ([]KeywordDocument, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := make([]KeywordDocument, 0)
+	for rows.Next() {
+		var d KeywordDocument
+		if err := rows.Scan(&d.ArticleID, &d.Language, &d.Title, &d.Content); err != nil {
+			return nil, err
+		}
+		documents = append(documents, d)
+	}
+	return documents, rows.Err()
+}
+
+// GetTrendingKeywords returns up to limit keywords of the articles published between since
+// and until, most mentioned first, with their counts in the window of equal length before.
+// A feedID of 0 counts articles of all feeds.
+func (db *DB) GetTrendingKeywords(feedID int64, since, until time.Time, limit int) ([]TrendingKeyword, error) {
+	db.WaitForReady()
+	previous := since.Add(-until.Sub(since))
+	rows, err := db.Query(`
+		SELECT k.keyword,
+			SUM(CASE WHEN a.published_at >= ? THEN 1 ELSE 0 END) AS count,
+			SUM(CASE WHEN a.published_at < ? THEN 1 ELSE 0 END) AS previous_count
+		FROM article_keywords k
+		JOIN articles a ON a.id = k.article_id
+		WHERE a.published_at >= ? AND a.published_at < ? AND (? = 0 OR a.feed_id = ?)
+		GROUP BY k.keyword
+		HAVING count > 0
+		ORDER BY count DESC, previous_count ASC, k.keyword
+		LIMIT ?`, since, since, previous, until, feedID, feedID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := make([]TrendingKeyword, 0)
+	for rows.Next() {
+		var k TrendingKeyword
+		if err := rows.Scan(&k.Keyword, &k.Count, &k.PreviousCount); err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, k)
+	}
+	return keywords, rows.Err()
+}
+
+// PruneArticleKeywords removes the keywords of deleted articles
+func (db *DB) PruneArticleKeywords() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_keywords WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArticleKeywords(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	now := time.Now()
+	insert := func(feedID int64, title string, publishedAt time.Time) int64 {
+		res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, unique_id) VALUES (?, ?, ?, ?, ?)`,
+			feedID, title, "https://example.com/"+title, publishedAt, title)
+		if err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+		id, _ := res.LastInsertId()
+		return id
+	}
+	recent := insert(1, "recent", now.Add(-24*time.Hour))
+	other := insert(2, "other", now.Add(-48*time.Hour))
+	older := insert(1, "older", now.Add(-10*24*time.Hour))
+	bare := insert(1, "bare", now.Add(-time.Hour))
+
+	if err := db.SaveArticleKeywords(recent, []ArticleKeyword{{"old phrase", 1}}); err != nil {
+		t.Fatalf("SaveArticleKeywords error: %v", err)
+	}
+	for id, keywords := range map[int64][]ArticleKeyword{
+		recent: {{"solid state batteries", 1}, {"electric cars", 0.4}},
+		other:  {{"electric cars", 1}},
+		older:  {{"solid state batteries", 1}, {"hydrogen", 0.5}},
+	} {
+		if err := db.SaveArticleKeywords(id, keywords); err != nil {
+			t.Fatalf("SaveArticleKeywords error: %v", err)
+		}
+	}
+
+	keywords, err := db.GetArticleKeywords(recent)
+	if err != nil || len(keywords) != 2 || keywords[0].Keyword != "solid state batteries" {
+		t.Fatalf("expected the replaced keywords best first, got %+v (%v)", keywords, err)
+	}
+
+	articles := []models.Article{{ID: recent}, {ID: bare}}
+	if err := db.AttachArticleKeywords(articles); err != nil {
+		t.Fatalf("AttachArticleKeywords error: %v", err)
+	}
+	if len(articles[0].Keywords) != 2 || articles[0].Keywords[1] != "electric cars" || articles[1].Keywords != nil {
+		t.Errorf("unexpected attached keywords: %+v", articles)
+	}
+
+	missing, err := db.GetArticlesWithoutKeywords(10)
+	if err != nil || len(missing) != 1 || missing[0].ArticleID != bare || missing[0].Title != "bare" {
+		t.Errorf("expected the article without keywords, got %+v (%v)", missing, err)
+	}
+
+	trending, err := db.GetTrendingKeywords(0, now.Add(-7*24*time.Hour), now, 10)
+	if err != nil {
+		t.Fatalf("GetTrendingKeywords error: %v", err)
+	}
+	if len(trending) != 2 || trending[0] != (TrendingKeyword{"electric cars", 2, 0}) || trending[1] != (TrendingKeyword{"solid state batteries", 1, 1}) {
+		t.Errorf("unexpected trending keywords: %+v", trending)
+	}
+	trending, err = db.GetTrendingKeywords(1, now.Add(-7*24*time.Hour), now, 10)
+	if err != nil || len(trending) != 2 || trending[0].Keyword != "electric cars" || trending[0].Count != 1 {
+		t.Errorf("unexpected trending keywords of feed 1: %+v (%v)", trending, err)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, older); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if n, err := db.PruneArticleKeywords(); err != nil || n != 2 {
+		t.Errorf("expected 2 keywords pruned, got %d (%v)", n, err)
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import "database/sql"
+
+// feedLanguageSample is the number of recent articles a feed's dominant language is computed from
+const feedLanguageSample = 50
+
+// UpdateFeedLanguage sets a feed's language to the most common detected language of its recent
+// articles and returns it. The language is left unchanged if none of them has been detected.
+func (db *DB) UpdateFeedLanguage(feedID int64) (string, error) {
+	db.WaitForReady()
+	var language string
+	err := db.QueryRow(`
+		SELECT language FROM (
+			SELECT language FROM articles
+			WHERE feed_id = ?
+			ORDER BY published_at DESC
+			LIMIT ?
+		)
+		WHERE language != ''
+		GROUP BY language
+		ORDER BY COUNT(*) DESC, language ASC
+		LIMIT 1`, feedID, feedLanguageSample).Scan(&language)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, err = db.Exec(`UPDATE feeds SET language = ? WHERE id = ?`, language, feedID)
+	return language, err
+}
+
+// GetLanguageCounts returns the detected article languages with their article counts, most used first
+func (db *DB) GetLanguageCounts(limit int) ([]TagCount, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT language, COUNT(*) AS count FROM articles
+		WHERE language != ''
+		GROUP BY language
+		ORDER BY count DESC, language ASC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]TagCount, 0)
+	for rows.Next() {
+		var c TagCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArticleLanguages(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO feeds (title, url, description) VALUES ('Feed', 'https://example.com/feed', '')`)
+	if err != nil {
+		t.Fatalf("insert feed error: %v", err)
+	}
+	feedID, _ := res.LastInsertId()
+
+	if language, err := db.UpdateFeedLanguage(feedID); err != nil || language != "" {
+		t.Fatalf("expected no language without articles, got %q (%v)", language, err)
+	}
+
+	now := time.Now()
+	articles := []*models.Article{
+		{FeedID: feedID, Title: "Launch", URL: "https://example.com/1", PublishedAt: now, HasValidPublishedTime: true, Language: "en", LanguageConfidence: 0.9},
+		{FeedID: feedID, Title: "Match", URL: "https://example.com/2", PublishedAt: now.Add(-time.Hour), HasValidPublishedTime: true, Language: "en", LanguageConfidence: 0.8},
+		{FeedID: feedID, Title: "Lancement", URL: "https://example.com/3", PublishedAt: now.Add(-2 * time.Hour), HasValidPublishedTime: true, Language: "fr", LanguageConfidence: 0.7},
+		{FeedID: feedID, Title: "?", URL: "https://example.com/4", PublishedAt: now.Add(-3 * time.Hour), HasValidPublishedTime: true},
+	}
+	if err := db.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles error: %v", err)
+	}
+
+	saved, err := db.GetArticles("", feedID, "", false, 10, 0)
+	if err != nil || len(saved) != 4 {
+		t.Fatalf("expected 4 articles, got %d (%v)", len(saved), err)
+	}
+	if saved[0].Language != "en" || saved[0].LanguageConfidence != 0.9 || saved[3].Language != "" {
+		t.Errorf("expected the detected languages to be stored, got %+v", saved)
+	}
+
+	if language, err := db.UpdateFeedLanguage(feedID); err != nil || language != "en" {
+		t.Fatalf("expected en to be the dominant language, got %q (%v)", language, err)
+	}
+	feed, err := db.GetFeedByID(feedID)
+	if err != nil || feed.Language != "en" {
+		t.Errorf("expected the feed language to be stored, got %+v (%v)", feed, err)
+	}
+
+	counts, err := db.GetLanguageCounts(10)
+	if err != nil || len(counts) != 2 || counts[0] != (TagCount{Value: "en", Count: 2}) || counts[1] != (TagCount{Value: "fr", Count: 1}) {
+		t.Errorf("unexpected language counts: %+v (%v)", counts, err)
+	}
+}
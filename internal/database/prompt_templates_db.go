@@ -0,0 +1,203 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/models"
+)
+
+// Prompt template kinds
+const (
+	PromptKindSummary     = "summary"
+	PromptKindTranslation = "translation"
+	PromptKindChatAction  = "chat_action"
+)
+
+// defaultChatActions are the quick actions added to the prompt library when it is created
+var defaultChatActions = []models.PromptTemplate{
+	{Name: "Extract action items", Template: "List the action items, decisions and deadlines in \"{{title}}\" as a checklist. Say so if there are none."},
+	{Name: "Key takeaways", Template: "Give the three most important takeaways of \"{{title}}\" in {{language}}, one sentence each."},
+	{Name: "Explain simply", Template: "Explain \"{{title}}\" in {{language}} to someone new to the topic, defining any jargon it uses."},
+}
+
+// InitPromptTemplatesTable creates the prompt_templates table if it doesn't exist, with the
+// default chat actions when it is created
+func InitPromptTemplatesTable(db *sql.DB) error {
+	var exists int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'prompt_templates'`).Scan(&exists); err != nil {
+		return err
+	}
+
+	query := `
+	CREATE TABLE IF NOT EXISTS prompt_templates (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		template TEXT NOT NULL,
+		categories TEXT NOT NULL DEFAULT '',
+		is_default BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(kind, name)
+	);
+	`
+	if _, err := db.Exec(query); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	for _, t := range defaultChatActions {
+		if _, err := db.Exec(`INSERT OR IGNORE INTO prompt_templates (name, kind, template) VALUES (?, ?, ?)`,
+			t.Name, PromptKindChatAction, t.Template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidPromptKind reports whether kind is a prompt template kind
+func ValidPromptKind(kind string) bool {
+	return kind == PromptKindSummary || kind == PromptKindTranslation || kind == PromptKindChatAction
+}
+
+// GetPromptTemplates returns the prompt templates of a kind, or of every kind when it is empty,
+// ordered by kind and name
+func (db *DB) GetPromptTemplates(kind string) ([]models.PromptTemplate, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT id, name, kind, template, categories, is_default
+		FROM prompt_templates
+		WHERE ? = '' OR kind = ?
+		ORDER BY kind ASC, name COLLATE NOCASE ASC`, kind, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt templates: %w", err)
+	}
+	defer rows.Close()
+
+	templates := make([]models.PromptTemplate, 0)
+	for rows.Next() {
+		var t models.PromptTemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.Kind, &t.Template, &t.Categories, &t.IsDefault); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// GetPromptTemplate returns a prompt template, or sql.ErrNoRows if there is none with the ID
+func (db *DB) GetPromptTemplate(id int64) (*models.PromptTemplate, error) {
+	db.WaitForReady()
+	var t models.PromptTemplate
+	err := db.QueryRow(`
+		SELECT id, name, kind, template, categories, is_default
+		FROM prompt_templates WHERE id = ?`, id).
+		Scan(&t.ID, &t.Name, &t.Kind, &t.Template, &t.Categories, &t.IsDefault)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SavePromptTemplate creates a template, or updates it when t.ID is set, and returns its ID.
+// A default template replaces the previous default of its kind. It returns sql.ErrNoRows if the
+// template to update doesn't exist.
+func (db *DB) SavePromptTemplate(t *models.PromptTemplate) (int64, error) {
+	db.WaitForReady()
+
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return 0, fmt.Errorf("name is required")
+	}
+	if !ValidPromptKind(t.Kind) {
+		return 0, fmt.Errorf("unknown prompt kind %q", t.Kind)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if t.IsDefault {
+		if _, err := tx.Exec(`UPDATE prompt_templates SET is_default = 0 WHERE kind = ? AND id != ?`, t.Kind, t.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	id := t.ID
+	if id > 0 {
+		var previous string
+		if err := tx.QueryRow(`SELECT template FROM prompt_templates WHERE id = ?`, id).Scan(&previous); err != nil {
+			return 0, err
+		}
+		if previous != t.Template {
+			if err := deletePromptTemplateResults(tx, id); err != nil {
+				return 0, err
+			}
+		}
+		result, err := tx.Exec(`
+			UPDATE prompt_templates SET name = ?, kind = ?, template = ?, categories = ?, is_default = ?
+			WHERE id = ?`,
+			name, t.Kind, t.Template, strings.TrimSpace(t.Categories), t.IsDefault, id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update prompt template: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return 0, sql.ErrNoRows
+		}
+	} else {
+		result, err := tx.Exec(`
+			INSERT INTO prompt_templates (name, kind, template, categories, is_default)
+			VALUES (?, ?, ?, ?, ?)`,
+			name, t.Kind, t.Template, strings.TrimSpace(t.Categories), t.IsDefault)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create prompt template: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit()
+}
+
+// DeletePromptTemplate removes a prompt template, its overrides from the feeds using it and the
+// translations and summaries made with it
+func (db *DB) DeletePromptTemplate(id int64) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM prompt_templates WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE feeds SET summary_prompt_id = 0 WHERE summary_prompt_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE feeds SET translation_prompt_id = 0 WHERE translation_prompt_id = ?`, id); err != nil {
+		return err
+	}
+	if err := deletePromptTemplateResults(tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deletePromptTemplateResults removes the cached translations, article translations and summaries
+// made with a prompt template, whose providers end with "#<template ID>"
+func deletePromptTemplateResults(tx *sql.Tx, id int64) error {
+	suffix := "%#" + strconv.FormatInt(id, 10)
+	for _, table := range []string{"translation_cache", "article_translations", "article_summaries"} {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE provider LIKE ?`, suffix); err != nil {
+			return fmt.Errorf("failed to clear %s of prompt template: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestPromptTemplates(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	actions, err := db.GetPromptTemplates(PromptKindChatAction)
+	if err != nil || len(actions) != len(defaultChatActions) {
+		t.Fatalf("expected the default chat actions, got %+v (%v)", actions, err)
+	}
+
+	firstID, err := db.SavePromptTemplate(&models.PromptTemplate{Name: "Formal", Kind: PromptKindTranslation, Template: "Use a formal tone.", IsDefault: true})
+	if err != nil {
+		t.Fatalf("SavePromptTemplate error: %v", err)
+	}
+	secondID, err := db.SavePromptTemplate(&models.PromptTemplate{Name: "Casual", Kind: PromptKindTranslation, Template: "Use a casual tone.", IsDefault: true})
+	if err != nil {
+		t.Fatalf("SavePromptTemplate error: %v", err)
+	}
+	if first, _ := db.GetPromptTemplate(firstID); first.IsDefault {
+		t.Error("expected the new default to replace the previous one")
+	}
+	if _, err := db.SavePromptTemplate(&models.PromptTemplate{Name: "Casual", Kind: "poem", Template: "x"}); err == nil {
+		t.Error("expected an error for an unknown kind")
+	}
+	if _, err := db.SavePromptTemplate(&models.PromptTemplate{ID: 9999, Name: "Missing", Kind: PromptKindSummary, Template: "x"}); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows for a missing template, got %v", err)
+	}
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "News", URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.SetFeedPromptTemplates(feedID, 0, secondID); err != nil {
+		t.Fatalf("SetFeedPromptTemplates error: %v", err)
+	}
+	if feed, _ := db.GetFeedByID(feedID); feed.TranslationPromptID != secondID {
+		t.Errorf("expected the feed's translation template %d, got %d", secondID, feed.TranslationPromptID)
+	}
+
+	if err := db.DeletePromptTemplate(secondID); err != nil {
+		t.Fatalf("DeletePromptTemplate error: %v", err)
+	}
+	if feed, _ := db.GetFeedByID(feedID); feed.TranslationPromptID != 0 {
+		t.Errorf("expected the deleted template removed from the feed, got %d", feed.TranslationPromptID)
+	}
+	if templates, _ := db.GetPromptTemplates(PromptKindTranslation); len(templates) != 1 || templates[0].ID != firstID {
+		t.Errorf("expected only the first translation template left, got %+v", templates)
+	}
+}
+
+func TestPromptTemplates_ClearResultsWhenChanged(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	id, err := db.SavePromptTemplate(&models.PromptTemplate{Name: "Formal", Kind: PromptKindTranslation, Template: "Use a formal tone."})
+	if err != nil {
+		t.Fatalf("SavePromptTemplate error: %v", err)
+	}
+	provider := fmt.Sprintf("ai:model#%d", id)
+	other := fmt.Sprintf("ai:model#%d0", id)
+	cached := func(provider string) bool {
+		t.Helper()
+		_, found, err := db.GetCachedTranslation("hash", "de", provider)
+		if err != nil {
+			t.Fatalf("GetCachedTranslation error: %v", err)
+		}
+		return found
+	}
+	db.SetCachedTranslation("hash", "Hello", "de", "Guten Tag", provider)
+	db.SetCachedTranslation("hash", "Hello", "de", "Hallo", other)
+
+	// Renaming the template keeps its translations
+	if _, err := db.SavePromptTemplate(&models.PromptTemplate{ID: id, Name: "Polite", Kind: PromptKindTranslation, Template: "Use a formal tone."}); err != nil {
+		t.Fatalf("SavePromptTemplate error: %v", err)
+	}
+	if !cached(provider) {
+		t.Error("expected the translation to be kept when the template text is unchanged")
+	}
+
+	if _, err := db.SavePromptTemplate(&models.PromptTemplate{ID: id, Name: "Polite", Kind: PromptKindTranslation, Template: "Use a very formal tone."}); err != nil {
+		t.Fatalf("SavePromptTemplate error: %v", err)
+	}
+	if cached(provider) || !cached(other) {
+		t.Error("expected only the translations of the changed template to be removed")
+	}
+
+	db.SetCachedTranslation("hash", "Hello", "de", "Guten Tag", provider)
+	if err := db.DeletePromptTemplate(id); err != nil {
+		t.Fatalf("DeletePromptTemplate error: %v", err)
+	}
+	if cached(provider) {
+		t.Error("expected the translations of the deleted template to be removed")
+	}
+}
@@ -0,0 +1,111 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RelevanceDocument is an article with its text and the behaviour signals relevance is learned from
+type RelevanceDocument struct {
+	ArticleID    int64
+	FeedID       int64
+	Title        string
+	Summary      string
+	Content      string // Cached article content, empty if none
+	PublishedAt  time.Time
+	IsRead       bool
+	IsFavorite   bool
+	IsReadLater  bool
+	IsHidden     bool
+	DwellSeconds int // Total time spent reading the article
+}
+
+const relevanceDocumentColumns = `
+	a.id, a.feed_id, COALESCE(a.title, ''), COALESCE(a.summary, ''), COALESCE(c.content, ''), a.published_at,
+	a.is_read, a.is_favorite, a.is_read_later, a.is_hidden, COALESCE(d.seconds, 0)
+	FROM articles a
+	LEFT JOIN article_contents c ON c.article_id = a.id
+	LEFT JOIN article_dwell d ON d.article_id = a.id`
+
+// GetRelevanceTrainingSet returns up to limit articles, newest first, that carry a signal of
+// interest: favorites, read later, read, hidden or read for a while, and unread articles
+// published before skippedBefore, which were passed over
+func (db *DB) GetRelevanceTrainingSet(skippedBefore time.Time, limit int) ([]RelevanceDocument, error) {
+	db.WaitForReady()
+	return db.queryRelevanceDocuments(`
+		SELECT `+relevanceDocumentColumns+`
+		WHERE a.is_favorite = 1 OR a.is_read_later = 1 OR a.is_read = 1 OR a.is_hidden = 1
+			OR d.seconds > 0 OR a.published_at < ?
+		ORDER BY a.id DESC
+		LIMIT ?`, skippedBefore, limit)
+}
+
+// GetRelevanceDocuments returns the articles with the given IDs
+func (db *DB) GetRelevanceDocuments(ids []int64) ([]RelevanceDocument, error) {
+	db.WaitForReady()
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return db.queryRelevanceDocuments(`
+		SELECT `+relevanceDocumentColumns+`
+		WHERE a.id IN (`+placeholders(len(ids))+`)`, int64Args(ids)...)
+}
+
+// GetUnreadRelevanceDocuments returns up to limit unread, visible articles, newest first
+func (db *DB) GetUnreadRelevanceDocuments(limit int) ([]RelevanceDocument, error) {
+	db.WaitForReady()
+	return db.queryRelevanceDocuments(`
+		SELECT `+relevanceDocumentColumns+`
+		WHERE a.is_read = 0 AND a.is_hidden = 0
+		ORDER BY a.published_at DESC
+		LIMIT ?`, limit)
+}
+
+func (db *DB) queryRelevanceDocuments(query string, args ...interface{}) ([]RelevanceDocument, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := make([]RelevanceDocument, 0)
+	for rows.Next() {
+		var d RelevanceDocument
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&d.ArticleID, &d.FeedID, &d.Title, &d.Summary, &d.Content, &publishedAt,
+			&d.IsRead, &d.IsFavorite, &d.IsReadLater, &d.IsHidden, &d.DwellSeconds); err != nil {
+			return nil, err
+		}
+		d.PublishedAt = publishedAt.Time
+		documents = append(documents, d)
+	}
+	return documents, rows.Err()
+}
+
+// SetRelevanceScores stores the relevance scores of articles, keyed by article ID
+func (db *DB) SetRelevanceScores(scores map[int64]float64) error {
+	db.WaitForReady()
+	if len(scores) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`UPDATE articles SET relevance_score = ? WHERE id = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for id, score := range scores {
+		if _, err := stmt.Exec(score, id); err != nil {
+			return fmt.Errorf("failed to save relevance score: %w", err)
+		}
+	}
+	return tx.Commit()
+}
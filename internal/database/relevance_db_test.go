@@ -0,0 +1,80 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelevanceDocumentsAndScores(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	res, err := db.Exec(`INSERT INTO feeds (title, url) VALUES ('Feed', 'https://example.com/feed')`)
+	if err != nil {
+		t.Fatalf("insert feed error: %v", err)
+	}
+	feedID, _ := res.LastInsertId()
+
+	now := time.Now()
+	insert := func(title string, published time.Time, read, favorite bool) int64 {
+		t.Helper()
+		res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, published_at, is_read, is_favorite, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			feedID, title, "https://example.com/"+title, published, read, favorite, title)
+		if err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+		id, _ := res.LastInsertId()
+		return id
+	}
+	favorite := insert("Favorite", now, true, true)
+	opened := insert("Opened", now, false, false)
+	fresh := insert("Fresh", now, false, false)
+	skipped := insert("Skipped", now.AddDate(0, -1, 0), false, false)
+
+	if err := db.RecordArticleDwell(opened, 30); err != nil {
+		t.Fatalf("RecordArticleDwell error: %v", err)
+	}
+	if err := db.RecordArticleDwell(opened, 15); err != nil {
+		t.Fatalf("RecordArticleDwell error: %v", err)
+	}
+
+	documents, err := db.GetRelevanceTrainingSet(now.AddDate(0, 0, -14), 10)
+	if err != nil {
+		t.Fatalf("GetRelevanceTrainingSet error: %v", err)
+	}
+	got := make(map[int64]RelevanceDocument)
+	for _, d := range documents {
+		got[d.ArticleID] = d
+	}
+	if len(got) != 3 || got[fresh].ArticleID != 0 {
+		t.Fatalf("expected the favorite, opened and skipped articles, got %+v", documents)
+	}
+	if !got[favorite].IsFavorite || got[opened].DwellSeconds != 45 || got[skipped].IsRead {
+		t.Errorf("unexpected signals: %+v", documents)
+	}
+
+	unread, err := db.GetUnreadRelevanceDocuments(10)
+	if err != nil || len(unread) != 3 {
+		t.Fatalf("expected three unread articles, got %+v (%v)", unread, err)
+	}
+
+	if err := db.SetRelevanceScores(map[int64]float64{fresh: 91.5, skipped: 12}); err != nil {
+		t.Fatalf("SetRelevanceScores error: %v", err)
+	}
+	articles, err := db.GetArticlesSorted("", 0, "", false, SortByRelevance, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticlesSorted error: %v", err)
+	}
+	if len(articles) != 4 || articles[0].ID != fresh || articles[1].ID != skipped || articles[2].RelevanceScore != nil {
+		t.Fatalf("expected scored articles first by score, got %+v", articles)
+	}
+	if *articles[0].RelevanceScore != 91.5 {
+		t.Errorf("expected score 91.5, got %v", *articles[0].RelevanceScore)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, opened); err != nil {
+		t.Fatalf("delete article error: %v", err)
+	}
+	if removed, err := db.PruneArticleDwell(); err != nil || removed != 1 {
+		t.Errorf("expected the dwell time of the deleted article to be pruned, got %d (%v)", removed, err)
+	}
+}
@@ -31,6 +31,15 @@ func InitStatisticsTable(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_statistics_event_date ON statistics(event_date);
 	CREATE INDEX IF NOT EXISTS idx_statistics_event_type ON statistics(event_type);
 	CREATE INDEX IF NOT EXISTS idx_statistics_date_type ON statistics(event_date, event_type);
+
+	-- Time spent reading each article, a signal of interest for relevance scoring
+	CREATE TABLE IF NOT EXISTS article_dwell (
+		article_id INTEGER PRIMARY KEY,
+		seconds INTEGER NOT NULL DEFAULT 0,
+		views INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY(article_id) REFERENCES articles(id) ON DELETE CASCADE
+	);
 	`
 	_, err := db.Exec(query)
 	return err
@@ -53,6 +62,32 @@ func (db *DB) IncrementStat(eventType string) error {
 	return err
 }
 
+// RecordArticleDwell adds seconds spent reading an article to its dwell time
+func (db *DB) RecordArticleDwell(articleID int64, seconds int) error {
+	db.WaitForReady()
+
+	query := `
+	INSERT INTO article_dwell (article_id, seconds, views)
+	VALUES (?, ?, 1)
+	ON CONFLICT(article_id) DO UPDATE SET
+		seconds = seconds + excluded.seconds,
+		views = views + 1,
+		updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, articleID, seconds)
+	return err
+}
+
+// PruneArticleDwell removes the dwell times of deleted articles
+func (db *DB) PruneArticleDwell() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_dwell WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // GetStatsByDateRange retrieves statistics for a specific date range
 func (db *DB) GetStatsByDateRange(startDate, endDate string) ([]StatRecord, error) {
 	db.WaitForReady()
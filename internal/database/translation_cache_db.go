@@ -0,0 +1,290 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/metrics"
+	"MrRSS/internal/models"
+)
+
+// translationCacheFlushInterval is how often the counted translation cache lookups are written
+const translationCacheFlushInterval = time.Minute
+
+// translationCacheLookups counts the hits and misses of translation cache lookups in memory, so
+// that looking up a translation doesn't write to the database. The counts are written at most
+// every translationCacheFlushInterval, before they are read and when the database is closed.
+type translationCacheLookups struct {
+	mu        sync.Mutex
+	hits      map[int64]int64                                 // By entry ID
+	counts    map[translationCacheKey]*translationCacheCounts // By provider and target language
+	lastFlush time.Time
+}
+
+type translationCacheKey struct {
+	provider   string
+	targetLang string
+}
+
+type translationCacheCounts struct {
+	hits   int64
+	misses int64
+}
+
+// countTranslationCacheLookup counts a hit of the entry with id, or a miss if id is 0
+func (db *DB) countTranslationCacheLookup(id int64, provider, targetLang string) {
+	l := &db.translationCacheLookups
+	l.mu.Lock()
+	if l.counts == nil {
+		l.counts = make(map[translationCacheKey]*translationCacheCounts)
+	}
+	key := translationCacheKey{provider: provider, targetLang: targetLang}
+	counts := l.counts[key]
+	if counts == nil {
+		counts = &translationCacheCounts{}
+		l.counts[key] = counts
+	}
+	if id != 0 {
+		if l.hits == nil {
+			l.hits = make(map[int64]int64)
+		}
+		l.hits[id]++
+		counts.hits++
+		metrics.TranslationCacheLookupsTotal.Inc(provider, "hit")
+	} else {
+		counts.misses++
+		metrics.TranslationCacheLookupsTotal.Inc(provider, "miss")
+	}
+	if l.lastFlush.IsZero() {
+		l.lastFlush = time.Now()
+	}
+	flush := time.Since(l.lastFlush) >= translationCacheFlushInterval
+	if flush {
+		l.lastFlush = time.Now()
+	}
+	l.mu.Unlock()
+
+	if flush {
+		go func() {
+			if err := db.flushTranslationCacheLookups(); err != nil {
+				log.Printf("Error saving translation cache hits: %v", err)
+			}
+		}()
+	}
+}
+
+// flushTranslationCacheLookups adds the counted hits and misses to the database
+func (db *DB) flushTranslationCacheLookups() error {
+	l := &db.translationCacheLookups
+	l.mu.Lock()
+	hits, counts := l.hits, l.counts
+	l.hits, l.counts = nil, nil
+	l.lastFlush = time.Now()
+	l.mu.Unlock()
+	if len(hits) == 0 && len(counts) == 0 {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for id, n := range hits {
+		if _, err := tx.Exec(`UPDATE translation_cache SET hits = hits + ? WHERE id = ?`, n, id); err != nil {
+			return err
+		}
+	}
+	for key, c := range counts {
+		if _, err := tx.Exec(`
+			INSERT INTO translation_cache_lookups (provider, target_lang, hits, misses) VALUES (?, ?, ?, ?)
+			ON CONFLICT(provider, target_lang) DO UPDATE SET
+			hits = hits + excluded.hits, misses = misses + excluded.misses`,
+			key.provider, key.targetLang, c.hits, c.misses); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Close writes the counted translation cache lookups and closes the database
+func (db *DB) Close() error {
+	if err := db.flushTranslationCacheLookups(); err != nil {
+		log.Printf("Error saving translation cache hits: %v", err)
+	}
+	return db.DB.Close()
+}
+
+// GetTranslationCacheStats returns the size and hit rate of the translation cache per provider and
+// target language, including the ones looked up without any cached translation left
+func (db *DB) GetTranslationCacheStats() ([]models.TranslationCacheStats, error) {
+	db.WaitForReady()
+	if err := db.flushTranslationCacheLookups(); err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`
+		SELECT k.provider, k.target_lang, COALESCE(c.entries, 0), COALESCE(c.edited, 0),
+			COALESCE(l.hits, 0), COALESCE(l.misses, 0), COALESCE(c.bytes, 0)
+		FROM (
+			SELECT provider, target_lang FROM translation_cache
+			UNION SELECT provider, target_lang FROM translation_cache_lookups
+		) k
+		LEFT JOIN (
+			SELECT provider, target_lang, COUNT(*) AS entries, SUM(edited) AS edited,
+				SUM(length(CAST(source_text AS BLOB)) + length(CAST(translated_text AS BLOB))) AS bytes
+			FROM translation_cache
+			GROUP BY provider, target_lang
+		) c ON c.provider = k.provider AND c.target_lang = k.target_lang
+		LEFT JOIN translation_cache_lookups l ON l.provider = k.provider AND l.target_lang = k.target_lang
+		ORDER BY k.provider, k.target_lang`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]models.TranslationCacheStats, 0)
+	for rows.Next() {
+		var s models.TranslationCacheStats
+		if err := rows.Scan(&s.Provider, &s.TargetLang, &s.Entries, &s.Edited, &s.Hits, &s.Misses, &s.Bytes); err != nil {
+			return nil, err
+		}
+		if lookups := s.Hits + s.Misses; lookups > 0 {
+			s.HitRate = float64(s.Hits) / float64(lookups)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+// GetTranslationCacheEntries returns cached translations, most recent first, filtered by provider,
+// target language and a text searched in the source and translated texts when they are set.
+// A limit of 0 or less returns every matching entry.
+func (db *DB) GetTranslationCacheEntries(provider, targetLang, search string, limit, offset int) ([]models.TranslationCacheEntry, error) {
+	db.WaitForReady()
+	if err := db.flushTranslationCacheLookups(); err != nil {
+		return nil, err
+	}
+	where, args := translationCacheFilter(provider, targetLang)
+	if search = strings.TrimSpace(search); search != "" {
+		where += ` AND (instr(lower(source_text), lower(?)) > 0 OR instr(lower(translated_text), lower(?)) > 0)`
+		args = append(args, search, search)
+	}
+	query := `
+		SELECT id, source_text, target_lang, translated_text, provider, COALESCE(hits, 0), COALESCE(edited, 0), COALESCE(created_at, '')
+		FROM translation_cache
+		WHERE ` + where + `
+		ORDER BY created_at DESC, id DESC`
+	if limit > 0 {
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, offset)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.TranslationCacheEntry, 0)
+	for rows.Next() {
+		var e models.TranslationCacheEntry
+		if err := rows.Scan(&e.ID, &e.SourceText, &e.TargetLang, &e.TranslatedText, &e.Provider, &e.Hits, &e.Edited, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// UpdateCachedTranslation replaces a cached translation with a manual correction, which is then
+// kept when the text is translated again, when the cache is cleaned up and when glossary terms
+// change. It returns sql.ErrNoRows if the entry doesn't exist.
+func (db *DB) UpdateCachedTranslation(id int64, translatedText string) error {
+	db.WaitForReady()
+	result, err := db.Exec(`UPDATE translation_cache SET translated_text = ?, edited = 1 WHERE id = ?`, translatedText, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteCachedTranslation removes a cached translation
+func (db *DB) DeleteCachedTranslation(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`DELETE FROM translation_cache WHERE id = ?`, id)
+	return err
+}
+
+// DeleteTranslationCache removes the cached translations of provider to targetLang, manual
+// corrections included, and resets their hit rate. An empty provider or language matches every one.
+// It returns the number of translations removed.
+func (db *DB) DeleteTranslationCache(provider, targetLang string) (int64, error) {
+	db.WaitForReady()
+	if err := db.flushTranslationCacheLookups(); err != nil {
+		return 0, err
+	}
+	where, args := translationCacheFilter(provider, targetLang)
+	result, err := db.Exec(`DELETE FROM translation_cache WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := db.Exec(`DELETE FROM translation_cache_lookups WHERE `+where, args...); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ImportCachedTranslations adds translations to the cache, replacing the cached translations of
+// the same texts except manual corrections. Entries must have their SourceTextHash set.
+// It returns the number of translations imported.
+func (db *DB) ImportCachedTranslations(entries []models.TranslationCacheEntry) (int64, error) {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO translation_cache (source_text_hash, source_text, target_lang, translated_text, provider, edited, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(source_text_hash, target_lang, provider) DO UPDATE SET
+		translated_text = excluded.translated_text, edited = excluded.edited, created_at = CURRENT_TIMESTAMP
+		WHERE edited = 0`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	var imported int64
+	for _, e := range entries {
+		result, err := stmt.Exec(e.SourceTextHash, e.SourceText, e.TargetLang, e.TranslatedText, e.Provider, e.Edited)
+		if err != nil {
+			return 0, err
+		}
+		n, _ := result.RowsAffected()
+		imported += n
+	}
+	return imported, tx.Commit()
+}
+
+// translationCacheFilter returns the WHERE clause and arguments matching the cached translations
+// of provider to targetLang, either matching every one when empty
+func translationCacheFilter(provider, targetLang string) (string, []interface{}) {
+	where, args := "1 = 1", []interface{}{}
+	if provider != "" {
+		where += ` AND provider = ?`
+		args = append(args, provider)
+	}
+	if targetLang != "" {
+		where += ` AND target_lang = ?`
+		args = append(args, targetLang)
+	}
+	return where, args
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"database/sql"
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestTranslationCacheManagement(t *testing.T) {
+	db := setupExtractionTestDB(t)
+
+	// Texts are looked up before they are translated and cached
+	for _, hash := range []string{"h1", "h2"} {
+		if _, found, _ := db.GetCachedTranslation(hash, "de", "google"); found {
+			t.Fatal("expected no cached translation")
+		}
+	}
+	// A language that was looked up but never cached still has a hit rate
+	db.GetCachedTranslation("h1", "ja", "google")
+	db.SetCachedTranslation("h1", "Hello", "de", "Hallo", "google")
+	db.SetCachedTranslation("h2", "World", "de", "Welt", "google")
+	db.SetCachedTranslation("h1", "Hello", "fr", "Bonjour", "deepl")
+
+	for i := 0; i < 3; i++ {
+		if _, found, _ := db.GetCachedTranslation("h1", "de", "google"); !found {
+			t.Fatal("expected a cached translation")
+		}
+	}
+
+	stats, err := db.GetTranslationCacheStats()
+	if err != nil || len(stats) != 3 {
+		t.Fatalf("expected stats for 3 provider languages, got %+v (%v)", stats, err)
+	}
+	google := stats[1]
+	if google.Provider != "google" || google.Entries != 2 || google.Hits != 3 || google.Misses != 2 || google.HitRate != 0.6 || google.Bytes == 0 {
+		t.Errorf("unexpected google stats: %+v", google)
+	}
+	if ja := stats[2]; ja.TargetLang != "ja" || ja.Entries != 0 || ja.Misses != 1 || ja.HitRate != 0 {
+		t.Errorf("unexpected stats of the uncached language: %+v", ja)
+	}
+
+	entries, err := db.GetTranslationCacheEntries("google", "de", "hal", 10, 0)
+	if err != nil || len(entries) != 1 || entries[0].SourceText != "Hello" {
+		t.Fatalf("expected the entry matching the search, got %+v (%v)", entries, err)
+	}
+
+	// A correction is kept when the text is translated again and when the cache is cleaned up
+	if err := db.UpdateCachedTranslation(entries[0].ID, "Servus"); err != nil {
+		t.Fatalf("UpdateCachedTranslation error: %v", err)
+	}
+	db.SetCachedTranslation("h1", "Hello", "de", "Hallo", "google")
+	if _, err := db.Exec(`UPDATE translation_cache SET created_at = datetime('now', '-30 days')`); err != nil {
+		t.Fatalf("failed to age the cache: %v", err)
+	}
+	if n, _ := db.CleanupTranslationCache(7); n != 2 {
+		t.Errorf("expected the 2 uncorrected translations to be cleaned up, got %d", n)
+	}
+	if stats, _ := db.GetTranslationCacheStats(); len(stats) != 2 || stats[0].Entries != 1 || stats[0].Hits != 3 || stats[0].Misses != 2 {
+		t.Errorf("expected the hit rate to outlive the cleaned up translations, got %+v", stats)
+	}
+	if text, found, _ := db.GetCachedTranslation("h1", "de", "google"); !found || text != "Servus" {
+		t.Errorf("expected the correction to be kept, got %q", text)
+	}
+	if err := db.UpdateCachedTranslation(9999, "x"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows for a missing entry, got %v", err)
+	}
+
+	imported, err := db.ImportCachedTranslations([]models.TranslationCacheEntry{
+		{SourceTextHash: "h1", SourceText: "Hello", TargetLang: "de", TranslatedText: "Grüß dich", Provider: "google"},
+		{SourceTextHash: "h3", SourceText: "Bye", TargetLang: "de", TranslatedText: "Tschüss", Provider: "google", Edited: true},
+	})
+	if err != nil || imported != 1 {
+		t.Fatalf("expected 1 imported translation next to the correction, got %d (%v)", imported, err)
+	}
+	if text, _, _ := db.GetCachedTranslation("h1", "de", "google"); text != "Servus" {
+		t.Errorf("expected the import to keep the correction, got %q", text)
+	}
+
+	if n, err := db.DeleteTranslationCache("google", ""); err != nil || n != 2 {
+		t.Errorf("expected the 2 google translations deleted, got %d (%v)", n, err)
+	}
+	if stats, _ := db.GetTranslationCacheStats(); len(stats) != 0 {
+		t.Errorf("expected an empty cache, got %+v", stats)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"MrRSS/internal/microformats"
 )
 
 func TestNewService(t *testing.T) {
@@ -286,3 +288,73 @@ func TestGetFaviconAndResolveURLAndExtractLinks(t *testing.T) {
 		t.Fatalf("resolveURL failed: %s", resolved)
 	}
 }
+
+func TestFindRSSFeed_JSONFeedLink(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		html := `<html><head><link rel="alternate" type="application/feed+json" href="/feed.json"></head><body></body></html>`
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(html))
+	})
+	mux.HandleFunc("/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"version": "https://jsonfeed.org/version/1.1", "title": "JSON", "items": []}`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := newServiceWithClient(srv.Client())
+	feedURL, err := s.findRSSFeed(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("findRSSFeed error: %v", err)
+	}
+	if !strings.HasSuffix(feedURL, "/feed.json") {
+		t.Fatalf("expected feed URL to end with /feed.json, got %s", feedURL)
+	}
+}
+
+func TestIsValidFeed_RejectsOtherJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(`{"posts": []}`))
+	}))
+	defer srv.Close()
+
+	s := newServiceWithClient(srv.Client())
+	if s.isValidFeed(context.Background(), srv.URL) {
+		t.Fatalf("expected plain JSON document not to be treated as a feed")
+	}
+}
+
+func TestDiscoverBlogRSS_HFeedFallback(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		html := `<html><head><title>Notes</title></head><body><div class="h-feed">` +
+			`<div class="h-entry"><a class="p-name u-url" href="/1">First note</a><time class="dt-published" datetime="2024-01-02"></time></div>` +
+			`</div></body></html>`
+		w.WriteHeader(200)
+		_, _ = w.Write([]byte(html))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	s := newServiceWithClient(srv.Client())
+	blog, err := s.discoverBlogRSS(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("discoverBlogRSS error: %v", err)
+	}
+	if blog.FeedType != microformats.FeedType || blog.RSSFeed != srv.URL || blog.Name != "Notes" {
+		t.Fatalf("unexpected discovered blog: %+v", blog)
+	}
+	if len(blog.RecentArticles) != 1 || blog.RecentArticles[0].Date != "2024-01-02" {
+		t.Errorf("unexpected recent articles: %+v", blog.RecentArticles)
+	}
+}
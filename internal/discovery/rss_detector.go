@@ -2,6 +2,7 @@ package discovery
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,8 @@ import (
 	"strings"
 	"sync"
 
+	"MrRSS/internal/microformats"
+
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -88,6 +91,10 @@ OuterLoop:
 func (s *Service) discoverBlogRSS(ctx context.Context, blogURL string) (DiscoveredBlog, error) {
 	// Try to find RSS feed URL
 	rssURL, err := s.findRSSFeed(ctx, blogURL)
+	if errors.Is(err, errRSSFeedNotFound) {
+		// Sites without a feed may still publish h-feed markup on their homepage
+		return s.discoverBlogHFeed(ctx, blogURL)
+	}
 	if err != nil {
 		return DiscoveredBlog{}, err
 	}
@@ -139,7 +146,7 @@ func (s *Service) findRSSFeed(ctx context.Context, blogURL string) (string, erro
 	doc, err := s.fetchHTML(ctx, blogURL)
 	if err == nil {
 		var foundFeed string
-		doc.Find("link[type='application/rss+xml'], link[type='application/atom+xml'], link[rel='alternate'][type*='xml']").Each(func(i int, sel *goquery.Selection) {
+		doc.Find("link[type='application/rss+xml'], link[type='application/atom+xml'], link[type='application/feed+json'], link[rel='alternate'][type*='xml'], link[rel='alternate'][type='application/json']").Each(func(i int, sel *goquery.Selection) {
 			if foundFeed != "" {
 				return
 			}
@@ -176,6 +183,7 @@ func (s *Service) findRSSFeed(ctx context.Context, blogURL string) (string, erro
 		"/rss2.xml",
 		"/feed.atom",
 		"/feed.rss",
+		"/feed.json", // JSON Feed
 	}
 
 	// Try common paths concurrently for faster discovery
@@ -217,7 +225,7 @@ func (s *Service) findRSSFeed(ctx context.Context, blogURL string) (string, erro
 	return "", errRSSFeedNotFound
 }
 
-// isValidFeed checks if a URL is a valid RSS/Atom feed
+// isValidFeed checks if a URL is a valid RSS/Atom feed or JSON Feed
 func (s *Service) isValidFeed(ctx context.Context, feedURL string) bool {
 	req, err := http.NewRequestWithContext(ctx, "HEAD", feedURL, nil)
 	if err != nil {
@@ -230,48 +238,90 @@ func (s *Service) isValidFeed(ctx context.Context, feedURL string) bool {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		// Try GET if HEAD doesn't work
-		req, err = http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-		if err != nil {
-			return false
+	if resp.StatusCode == http.StatusOK {
+		contentType := resp.Header.Get("Content-Type")
+		if strings.Contains(contentType, "xml") ||
+			strings.Contains(contentType, "rss") ||
+			strings.Contains(contentType, "atom") {
+			return true
 		}
-
-		resp2, err := s.client.Do(req)
-		if err != nil {
+		// JSON Feeds may be served as plain application/json, so the content
+		// has to be checked to tell them apart from other JSON documents
+		if !strings.Contains(contentType, "json") {
 			return false
 		}
-		defer resp2.Body.Close()
+	}
 
-		if resp2.StatusCode != http.StatusOK {
-			return false
-		}
+	// Try GET if HEAD doesn't work
+	req, err = http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return false
+	}
 
-		// Read first few bytes to check if it's XML
-		buf := make([]byte, 512)
-		n, err := io.ReadAtLeast(resp2.Body, buf, 1)
-		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
-			return false
-		}
-		if n == 0 {
-			return false
-		}
-		content := string(buf[:n])
+	resp2, err := s.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp2.Body.Close()
 
-		// Check for XML declaration and RSS/Atom tags
-		if strings.Contains(content, "<?xml") ||
-			strings.Contains(content, "<rss") ||
-			strings.Contains(content, "<feed") ||
-			strings.Contains(content, "<atom") {
-			return true
-		}
+	if resp2.StatusCode != http.StatusOK {
 		return false
 	}
 
-	contentType := resp.Header.Get("Content-Type")
-	return strings.Contains(contentType, "xml") ||
-		strings.Contains(contentType, "rss") ||
-		strings.Contains(contentType, "atom")
+	// Read the beginning of the document to check if it's a feed
+	buf, err := io.ReadAll(io.LimitReader(resp2.Body, 1024))
+	if err != nil || len(buf) == 0 {
+		return false
+	}
+	return looksLikeFeed(string(buf))
+}
+
+// looksLikeFeed checks the beginning of a document for RSS/Atom tags or a JSON Feed version
+func looksLikeFeed(content string) bool {
+	// Check for XML declaration and RSS/Atom tags
+	if strings.Contains(content, "<?xml") ||
+		strings.Contains(content, "<rss") ||
+		strings.Contains(content, "<feed") ||
+		strings.Contains(content, "<atom") {
+		return true
+	}
+	// JSON Feeds identify themselves with "version": "https://jsonfeed.org/version/1.1"
+	return strings.HasPrefix(strings.TrimSpace(content), "{") && strings.Contains(content, "jsonfeed.org/version")
+}
+
+// discoverBlogHFeed builds a discovered blog from the h-feed markup of its homepage
+func (s *Service) discoverBlogHFeed(ctx context.Context, blogURL string) (DiscoveredBlog, error) {
+	doc, err := s.fetchHTML(ctx, blogURL)
+	if err != nil {
+		return DiscoveredBlog{}, err
+	}
+
+	feed, err := microformats.ParseDocument(doc, blogURL)
+	if err != nil {
+		return DiscoveredBlog{}, errRSSFeedNotFound
+	}
+
+	var recentArticles []RecentArticle
+	for i := 0; i < len(feed.Items) && i < 3; i++ {
+		item := feed.Items[i]
+		dateStr := ""
+		if item.PublishedParsed != nil {
+			dateStr = item.PublishedParsed.Format("2006-01-02")
+		}
+		recentArticles = append(recentArticles, RecentArticle{
+			Title: item.Title,
+			Date:  dateStr,
+		})
+	}
+
+	return DiscoveredBlog{
+		Name:           feed.Title,
+		Homepage:       blogURL,
+		RSSFeed:        blogURL,
+		FeedType:       microformats.FeedType,
+		IconURL:        s.getFavicon(blogURL),
+		RecentArticles: recentArticles,
+	}, nil
 }
 
 // getFavicon gets the favicon URL for a blog
@@ -37,6 +37,7 @@ type DiscoveredBlog struct {
 	Name           string          `json:"name"`
 	Homepage       string          `json:"homepage"`
 	RSSFeed        string          `json:"rss_feed"`
+	FeedType       string          `json:"feed_type,omitempty"` // Empty for RSS/Atom/JSON Feed, microformats.FeedType for h-feed pages
 	IconURL        string          `json:"icon_url"`
 	RecentArticles []RecentArticle `json:"recent_articles"`
 }
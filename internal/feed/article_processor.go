@@ -2,6 +2,7 @@ package feed
 
 import (
 	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
 	"MrRSS/internal/utils"
 	"net/url"
 	"regexp"
@@ -83,6 +84,9 @@ func (f *Fetcher) processArticles(feed models.Feed, items []*gofeed.Item) []*Art
 		// Doing it here for all articles during refresh causes massive performance issues
 		translatedTitle := "" // Always empty - translation happens on-demand in frontend
 
+		// Language detection is cheap and local, unlike translation, so it is done once here
+		language, languageConfidence := translation.GetLanguageDetector().DetectArticleLanguage(title, content)
+
 		article := &models.Article{
 			FeedID:                feed.ID,
 			Title:                 title,
@@ -93,6 +97,8 @@ func (f *Fetcher) processArticles(feed models.Feed, items []*gofeed.Item) []*Art
 			PublishedAt:           published,
 			HasValidPublishedTime: hasValidPublishedTime,
 			TranslatedTitle:       translatedTitle,
+			Language:              language,
+			LanguageConfidence:    languageConfidence,
 		}
 
 		articlesWithContent = append(articlesWithContent, &ArticleWithContent{
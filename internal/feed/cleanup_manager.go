@@ -150,6 +150,12 @@ func (cm *CleanupManager) executeCleanup() {
 	}
 }
 
+// RunCleanup executes the layered cleanup synchronously and returns the number of removed items.
+// Unlike RequestCleanup it does not check for running tasks; callers must make sure no refresh is in progress.
+func (cm *CleanupManager) RunCleanup() int64 {
+	return cm.layeredCleanup(cm.getTargetSize() * 0.8)
+}
+
 // getTargetSize returns the target database size in MB
 func (cm *CleanupManager) getTargetSize() float64 {
 	maxSizeMBStr, _ := cm.fetcher.db.GetSetting("max_cache_size_mb")
@@ -255,6 +261,41 @@ func (cm *CleanupManager) layeredCleanup(targetSizeMB float64) int64 {
 		}
 	}
 
+	// Remove the classifications of deleted articles
+	if _, err := cm.fetcher.db.PruneArticleClassifications(); err != nil {
+		log.Printf("Error pruning article classifications: %v", err)
+	}
+
+	// Remove the translated bodies of deleted articles
+	if _, err := cm.fetcher.db.PruneArticleTranslations(); err != nil {
+		log.Printf("Error pruning article translations: %v", err)
+	}
+
+	// Remove the summaries of deleted articles
+	if _, err := cm.fetcher.db.PruneArticleSummaries(); err != nil {
+		log.Printf("Error pruning article summaries: %v", err)
+	}
+
+	// Remove the keywords of deleted articles
+	if _, err := cm.fetcher.db.PruneArticleKeywords(); err != nil {
+		log.Printf("Error pruning article keywords: %v", err)
+	}
+
+	// Remove the dwell times of deleted articles
+	if _, err := cm.fetcher.db.PruneArticleDwell(); err != nil {
+		log.Printf("Error pruning article dwell times: %v", err)
+	}
+
+	// Remove the background jobs of deleted articles
+	if _, err := cm.fetcher.db.PruneBackgroundJobs(); err != nil {
+		log.Printf("Error pruning background jobs: %v", err)
+	}
+
+	// Remove the chat sessions of deleted articles, except pinned ones
+	if _, err := cm.fetcher.db.CleanupOrphanedChatSessions(); err != nil {
+		log.Printf("Error cleaning up orphaned chat sessions: %v", err)
+	}
+
 	// Final size check
 	finalSizeMB, _ := cm.fetcher.db.GetDatabaseSizeMB()
 	log.Printf("Final size: %.2f MB (target was %.2f MB)", finalSizeMB, targetSizeMB)
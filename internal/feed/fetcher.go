@@ -2,7 +2,10 @@ package feed
 
 import (
 	"MrRSS/internal/database"
+	"MrRSS/internal/fulltext"
+	"MrRSS/internal/keywords"
 	"MrRSS/internal/models"
+	"MrRSS/internal/relevance"
 	"MrRSS/internal/rsshub"
 	"MrRSS/internal/rules"
 	"MrRSS/internal/utils"
@@ -34,8 +37,27 @@ type Fetcher struct {
 	refreshCalculator *IntelligentRefreshCalculator
 	taskManager       *TaskManager
 	cleanupManager    *CleanupManager
+	postProcessWG     sync.WaitGroup // Tracks asynchronous post-processing of saved articles
+	fullText          *fulltext.Extractor
+	relevance         *relevance.Service
+	keywords          *keywords.Service
+	classifier        ArticleClassifier
+	jobQueue          ArticleJobQueue
 }
 
+// ArticleClassifier classifies newly saved articles, see classify.Service
+type ArticleClassifier interface {
+	ClassifyArticles(ctx context.Context, articles []models.Article) (int, error)
+}
+
+// ArticleJobQueue queues background work for newly saved articles, see jobs.Worker
+type ArticleJobQueue interface {
+	EnqueueArticles(feed models.Feed, articles []models.Article) (int, error)
+}
+
+// classifyTimeout bounds the classification of the articles saved by one refresh
+const classifyTimeout = 5 * time.Minute
+
 func NewFetcher(db *database.DB) *Fetcher {
 	// Initialize script executor with scripts directory
 	scriptsDir, err := utils.GetScriptsDir()
@@ -72,6 +94,9 @@ func NewFetcher(db *database.DB) *Fetcher {
 		scriptExecutor:    executor,
 		emailFetcher:      NewEmailFetcher(db),
 		refreshCalculator: NewIntelligentRefreshCalculator(db),
+		fullText:          fulltext.NewExtractor(db),
+		relevance:         relevance.NewService(db),
+		keywords:          keywords.NewService(db),
 	}
 
 	// Initialize task manager with default capacity (increased from 5 to 10)
@@ -90,6 +115,22 @@ func (f *Fetcher) GetIntelligentRefreshCalculator() *IntelligentRefreshCalculato
 	return f.refreshCalculator
 }
 
+// Relevance returns the relevance service scoring new articles
+func (f *Fetcher) Relevance() *relevance.Service {
+	return f.relevance
+}
+
+// SetClassifier sets the classifier run on newly saved articles before rules are applied
+func (f *Fetcher) SetClassifier(classifier ArticleClassifier) {
+	f.classifier = classifier
+}
+
+// SetJobQueue sets the queue that background translation and summary jobs for newly saved
+// articles are added to
+func (f *Fetcher) SetJobQueue(queue ArticleJobQueue) {
+	f.jobQueue = queue
+}
+
 // GetStaggeredDelay calculates a staggered delay for feed refresh
 func (f *Fetcher) GetStaggeredDelay(feedID int64, totalFeeds int) time.Duration {
 	return GetStaggeredDelay(feedID, totalFeeds)
@@ -105,6 +146,13 @@ func (f *Fetcher) GetCleanupManager() *CleanupManager {
 	return f.cleanupManager
 }
 
+// WaitForPostProcessing blocks until asynchronous post-processing of saved articles
+// (content caching, classification and rule application) has finished.
+// Used by short-lived processes such as the command-line interface before exiting.
+func (f *Fetcher) WaitForPostProcessing() {
+	f.postProcessWG.Wait()
+}
+
 // transformRSSHubURL converts rsshub:// route to full URL
 func (f *Fetcher) transformRSSHubURL(url string) (string, error) {
 	if !rsshub.IsRSSHubURL(url) {
@@ -312,33 +360,120 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed models.Feed) {
 		} else {
 			// Cache article content from RSS feed
 			f.cacheArticleContents(articlesWithContent)
+			f.updateFeedLanguage(feed)
 
-			// Apply rules to newly saved articles
-			// We fetch the recent articles for this feed since SaveArticles doesn't return IDs
-			// This is limited to the number of articles we just saved
-			savedArticles, err := f.db.GetArticles("", feed.ID, "", false, len(articlesToSave), 0)
+			// Apply rules to the articles just inserted, SaveArticles set their IDs
+			savedArticles, err := f.db.GetArticlesByIDs(insertedArticleIDs(articlesToSave))
 			if err == nil && len(savedArticles) > 0 {
-				engine := rules.NewEngine(f.db)
-				affected, err := engine.ApplyRulesToArticles(savedArticles)
-				if err != nil {
-					log.Printf("Error applying rules for feed %s: %v", feed.Title, err)
-				} else if affected > 0 {
-					utils.DebugLog("Applied rules to %d articles in feed %s", affected, feed.Title)
-				}
+				// Classification may take minutes, so it runs asynchronously like in fetchFeedWithContext
+				f.postProcessWG.Add(1)
+				go func() {
+					defer f.postProcessWG.Done()
+					f.applyRules(feed, savedArticles)
+					f.enqueueBackgroundJobs(feed, savedArticles)
+				}()
 			}
 		}
 	}
 	utils.DebugLog("Updated feed: %s", feed.Title)
 }
 
+// insertedArticleIDs returns the IDs of the articles SaveArticles inserted, leaving out the ones
+// that were already saved
+func insertedArticleIDs(articles []*models.Article) []int64 {
+	ids := make([]int64, 0, len(articles))
+	for _, article := range articles {
+		if article.ID != 0 {
+			ids = append(ids, article.ID)
+		}
+	}
+	return ids
+}
+
+// applyRules applies the rules to newly saved articles after scoring, extracting keywords and
+// classifying them, so that rules can use the results
+func (f *Fetcher) applyRules(feed models.Feed, articles []models.Article) {
+	f.scoreArticles(feed, articles)
+	f.extractKeywords(feed, articles)
+	f.classifyArticles(feed, articles)
+
+	engine := rules.NewEngine(f.db)
+	affected, err := engine.ApplyRulesToArticles(articles)
+	if err != nil {
+		log.Printf("Error applying rules for feed %s: %v", feed.Title, err)
+	} else if affected > 0 {
+		utils.DebugLog("Applied rules to %d articles in feed %s", affected, feed.Title)
+	}
+}
+
+// enqueueBackgroundJobs queues background translation and summary jobs for newly saved
+// articles, if a job queue is set and the feed or its category opted in
+func (f *Fetcher) enqueueBackgroundJobs(feed models.Feed, articles []models.Article) {
+	if f.jobQueue == nil {
+		return
+	}
+	if n, err := f.jobQueue.EnqueueArticles(feed, articles); err != nil {
+		log.Printf("Error queueing background jobs for feed %s: %v", feed.Title, err)
+	} else if n > 0 {
+		utils.DebugLog("Queued %d background jobs for feed %s", n, feed.Title)
+	}
+}
+
+// updateFeedLanguage sets the feed's language to the dominant language of its recent articles
+func (f *Fetcher) updateFeedLanguage(feed models.Feed) {
+	if _, err := f.db.UpdateFeedLanguage(feed.ID); err != nil {
+		log.Printf("Error updating language of feed %s: %v", feed.Title, err)
+	}
+}
+
+// scoreArticles sets the learned relevance score of newly saved articles
+func (f *Fetcher) scoreArticles(feed models.Feed, articles []models.Article) {
+	if err := f.relevance.ScoreArticles(articles); err != nil {
+		log.Printf("Error scoring relevance for feed %s: %v", feed.Title, err)
+	}
+}
+
+// extractKeywords extracts the key phrases of newly saved articles
+func (f *Fetcher) extractKeywords(feed models.Feed, articles []models.Article) {
+	if _, err := f.keywords.ExtractArticles(articles); err != nil {
+		log.Printf("Error extracting keywords for feed %s: %v", feed.Title, err)
+	}
+}
+
+// classifyArticles sorts newly saved articles into the user's topics, if a classifier is set.
+// It is called from the asynchronous post-processing, so it doesn't hold up the refresh, and
+// is bounded by classifyTimeout.
+func (f *Fetcher) classifyArticles(feed models.Feed, articles []models.Article) {
+	if f.classifier == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), classifyTimeout)
+	defer cancel()
+	if n, err := f.classifier.ClassifyArticles(ctx, articles); err != nil {
+		log.Printf("Error classifying articles for feed %s: %v", feed.Title, err)
+	} else if n > 0 {
+		utils.DebugLog("Classified %d articles in feed %s", n, feed.Title)
+	}
+}
+
 // fetchFeedWithContext is the internal fetch method used by TaskManager
 // Returns error instead of storing in progress.Errors
-func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) error {
+func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) (err error) {
+	start := time.Now()
+	ctx, info := withFetchInfo(ctx)
+	itemCount := 0
+	defer func() {
+		duration := time.Since(start)
+		recordFetchMetrics(feed, duration, err)
+		f.recordFetchAttempt(feed, info, duration, itemCount, err)
+	}()
+
 	// Use ParseFeedWithFeed with normal priority for feed refresh
 	parsedFeed, err := f.ParseFeedWithFeed(ctx, &feed, false)
 	if err != nil {
 		return err
 	}
+	itemCount = len(parsedFeed.Items)
 
 	// Check context after parsing
 	select {
@@ -391,12 +526,16 @@ func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) er
 		// Post-processing operations (content caching and rule application)
 		// These are non-critical and run asynchronously to avoid blocking the feed refresh
 		// Even if they fail or are slow, the feed has already been successfully saved
+		f.postProcessWG.Add(1)
 		go func() {
+			defer f.postProcessWG.Done()
+
 			// Cache article content from RSS feed
 			f.cacheArticleContents(articlesWithContent)
+			f.updateFeedLanguage(feed)
 
-			// Apply rules to newly saved articles
-			savedArticles, err := f.db.GetArticles("", feed.ID, "", false, len(articlesToSave), 0)
+			// Apply rules to the articles just inserted, SaveArticles set their IDs
+			savedArticles, err := f.db.GetArticlesByIDs(insertedArticleIDs(articlesToSave))
 			if err != nil {
 				log.Printf("Error getting articles for rule application: %v", err)
 				return
@@ -405,13 +544,13 @@ func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) er
 				return
 			}
 
-			engine := rules.NewEngine(f.db)
-			affected, err := engine.ApplyRulesToArticles(savedArticles)
-			if err != nil {
-				log.Printf("Error applying rules for feed %s: %v", feed.Title, err)
-			} else if affected > 0 {
-				utils.DebugLog("Applied rules to %d articles in feed %s", affected, feed.Title)
-			}
+			f.applyRules(feed, savedArticles)
+
+			// Extract full text from the original pages if enabled for this feed
+			f.fetchFullTextForArticles(feed, savedArticles)
+
+			// Queue background translation and summaries, after full text so summaries can use it
+			f.enqueueBackgroundJobs(feed, savedArticles)
 		}()
 	}
 	return nil
@@ -475,6 +614,11 @@ func (f *Fetcher) cacheArticleContents(articlesWithContent []*ArticleWithContent
 			continue
 		}
 
+		// Don't replace full text extracted from the original page with the feed excerpt
+		if hasFullText, _ := f.db.HasArticleFullText(articleID); hasFullText {
+			continue
+		}
+
 		// Cache the content (this will overwrite any existing cache as required)
 		if err := f.db.SetArticleContent(articleID, awc.Content); err != nil {
 			log.Printf("Error caching content for article %d: %v", articleID, err)
@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
-	"time"
 
 	"MrRSS/internal/database"
 	"MrRSS/internal/models"
@@ -64,9 +63,7 @@ func TestFetchFeed_SavesArticlesAndAppliesRules(t *testing.T) {
 	}
 
 	ffetcher.FetchFeed(context.Background(), *feedRow)
-
-	// Allow a moment for DB writes
-	time.Sleep(50 * time.Millisecond)
+	ffetcher.WaitForPostProcessing()
 
 	articles, err := db.GetArticles("all", id, "", false, 10, 0)
 	if err != nil {
@@ -0,0 +1,82 @@
+package feed
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"MrRSS/internal/fulltext"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+const (
+	// maxFullTextPerRefresh limits how many articles get their full text extracted per feed refresh
+	maxFullTextPerRefresh = 20
+	// fullTextConcurrency limits parallel page downloads for a single feed
+	fullTextConcurrency = 3
+	// fullTextTimeout bounds the extraction of a single article, including follow-up pages
+	fullTextTimeout = 90 * time.Second
+)
+
+// GetFullTextExtractor returns the extractor used for full-text fetching
+func (f *Fetcher) GetFullTextExtractor() *fulltext.Extractor {
+	return f.fullText
+}
+
+// fetchFullTextForArticles extracts and caches the full text of newly saved articles
+// of feeds that have full-text fetching at ingest time enabled, and extracts their keywords
+// again from it.
+// Articles that already have full text are skipped, so each article is extracted once.
+func (f *Fetcher) fetchFullTextForArticles(feed models.Feed, articles []models.Article) {
+	if !feed.FullTextOnIngest || len(articles) == 0 {
+		return
+	}
+	if enabled, _ := f.db.GetSetting("full_text_fetch_enabled"); enabled != "true" {
+		return
+	}
+
+	pending := make([]models.Article, 0, len(articles))
+	for _, article := range articles {
+		if article.URL == "" {
+			continue
+		}
+		if hasFullText, err := f.db.HasArticleFullText(article.ID); err != nil || hasFullText {
+			continue
+		}
+		pending = append(pending, article)
+		if len(pending) >= maxFullTextPerRefresh {
+			break
+		}
+	}
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, fullTextConcurrency)
+	for _, article := range pending {
+		wg.Add(1)
+		go func(article models.Article) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), fullTextTimeout)
+			defer cancel()
+
+			result, err := f.fullText.Extract(ctx, article.URL)
+			if err != nil {
+				utils.DebugLog("Full-text extraction failed for %s: %v", article.URL, err)
+				return
+			}
+			if err := f.db.SetArticleFullText(article.ID, result.Content); err != nil {
+				log.Printf("Error caching full text for article %d: %v", article.ID, err)
+				return
+			}
+			// The keywords extracted when the article was saved only saw the feed's excerpt
+			if _, err := f.keywords.ExtractArticle(article.ID); err != nil {
+				log.Printf("Error extracting keywords for article %d: %v", article.ID, err)
+			}
+		}(article)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,121 @@
+package feed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestFetchFullTextForArticles(t *testing.T) {
+	requests := 0
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`<html><body><div class="content"><p>Complete article body.</p></div></body></html>`))
+	}))
+	defer site.Close()
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	defer db.Close()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	f := NewFetcher(db)
+
+	host := strings.TrimPrefix(site.URL, "http://")
+	if _, err := db.SaveExtractionRule(&database.ExtractionRule{Domain: host, KeepSelectors: ".content", Enabled: true}); err != nil {
+		t.Fatalf("SaveExtractionRule error: %v", err)
+	}
+
+	feed := models.Feed{ID: 1, Title: "Test"}
+	articles := []models.Article{{ID: 1, URL: site.URL + "/a"}, {ID: 2, URL: ""}}
+
+	// Disabled for the feed
+	f.fetchFullTextForArticles(feed, articles)
+	if requests != 0 {
+		t.Fatalf("expected no requests when disabled for the feed, got %d", requests)
+	}
+
+	// Enabled for the feed but disabled globally
+	feed.FullTextOnIngest = true
+	if err := db.SetSetting("full_text_fetch_enabled", "false"); err != nil {
+		t.Fatalf("SetSetting error: %v", err)
+	}
+	f.fetchFullTextForArticles(feed, articles)
+	if requests != 0 {
+		t.Fatalf("expected no requests when disabled globally, got %d", requests)
+	}
+
+	if err := db.SetSetting("full_text_fetch_enabled", "true"); err != nil {
+		t.Fatalf("SetSetting error: %v", err)
+	}
+	f.fetchFullTextForArticles(feed, articles)
+	if requests != 1 {
+		t.Fatalf("expected 1 request, got %d", requests)
+	}
+
+	content, found, err := db.GetArticleFullText(1)
+	if err != nil || !found {
+		t.Fatalf("GetArticleFullText = %v, %v", found, err)
+	}
+	if !strings.Contains(content, "Complete article body.") {
+		t.Errorf("unexpected content: %s", content)
+	}
+
+	// Articles with full text are not extracted again
+	f.fetchFullTextForArticles(feed, articles)
+	if requests != 1 {
+		t.Errorf("expected full text to be extracted once, got %d requests", requests)
+	}
+}
+
+func TestFetchFullTextForArticles_ExtractsKeywords(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><article><p>Solid state batteries reach production. Solid state batteries charge faster than lithium ion cells.</p></article></body></html>`))
+	}))
+	defer site.Close()
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	defer db.Close()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	db.SetSetting("full_text_fetch_enabled", "true")
+	f := NewFetcher(db)
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Test", URL: "https://example.com/feed", FullTextOnIngest: true})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: "Toyota news", URL: site.URL + "/a", PublishedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveArticle error: %v", err)
+	}
+	articles, err := db.GetArticles("", feedID, "", false, 10, 0)
+	if err != nil || len(articles) != 1 {
+		t.Fatalf("GetArticles = %+v, %v", articles, err)
+	}
+
+	f.fetchFullTextForArticles(models.Feed{ID: feedID, Title: "Test", FullTextOnIngest: true}, articles)
+
+	keywords, err := db.GetArticleKeywords(articles[0].ID)
+	if err != nil {
+		t.Fatalf("GetArticleKeywords error: %v", err)
+	}
+	found := false
+	for _, k := range keywords {
+		found = found || k.Keyword == "lithium ion cells"
+	}
+	if !found {
+		t.Errorf("expected keywords extracted from the full text, got %+v", keywords)
+	}
+}
@@ -0,0 +1,420 @@
+package feed
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Health thresholds and backoff limits
+const (
+	// BrokenFailureThreshold is the number of consecutive failed refreshes after which a feed is reported as broken
+	BrokenFailureThreshold = 3
+
+	baseBackoff = 30 * time.Minute
+	maxBackoff  = 24 * time.Hour
+)
+
+// Error classes recorded in the fetch history
+const (
+	ErrorClassTimeout    = "timeout"
+	ErrorClassDNS        = "dns"
+	ErrorClassTLS        = "tls"
+	ErrorClassConnection = "connection"
+	ErrorClassHTTP4xx    = "http_4xx"
+	ErrorClassHTTP5xx    = "http_5xx"
+	ErrorClassParse      = "parse"
+	ErrorClassScript     = "script"
+	ErrorClassCanceled   = "canceled"
+	ErrorClassOther      = "other"
+)
+
+// HTTPStatusError is returned when a feed responds with a non-200 status code
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Status)
+}
+
+// fetchInfo collects transport details of a fetch attempt that are not part of the parsed feed
+type fetchInfo struct {
+	mu                sync.Mutex
+	httpStatus        int
+	permanentRedirect string // Final URL if every redirect hop was permanent (301/308)
+}
+
+type fetchInfoKey struct{}
+
+// withFetchInfo attaches a new fetchInfo to the context
+func withFetchInfo(ctx context.Context) (context.Context, *fetchInfo) {
+	info := &fetchInfo{}
+	return context.WithValue(ctx, fetchInfoKey{}, info), info
+}
+
+// fetchInfoFromContext returns the fetchInfo attached to the context, or nil
+func fetchInfoFromContext(ctx context.Context) *fetchInfo {
+	info, _ := ctx.Value(fetchInfoKey{}).(*fetchInfo)
+	return info
+}
+
+func (i *fetchInfo) setResponse(status int, permanentRedirect string) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.httpStatus = status
+	i.permanentRedirect = permanentRedirect
+}
+
+func (i *fetchInfo) snapshot() (int, string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.httpStatus, i.permanentRedirect
+}
+
+// trackRedirects returns a copy of client that reports whether all redirects it follows are permanent.
+// The returned function yields the final URL when the request was redirected only by 301/308 responses.
+func trackRedirects(client *http.Client) (*http.Client, func(resp *http.Response) string) {
+	tracked := *client
+	redirected := false
+	permanent := true
+
+	previous := client.CheckRedirect
+	tracked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		redirected = true
+		if req.Response == nil || (req.Response.StatusCode != http.StatusMovedPermanently && req.Response.StatusCode != http.StatusPermanentRedirect) {
+			permanent = false
+		}
+		if previous != nil {
+			return previous(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+
+	return &tracked, func(resp *http.Response) string {
+		if !redirected || !permanent || resp == nil || resp.Request == nil || resp.StatusCode != http.StatusOK {
+			return ""
+		}
+		return resp.Request.URL.String()
+	}
+}
+
+// statusCodeFromError extracts an HTTP status code from a fetch error, or returns 0
+func statusCodeFromError(err error) int {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	var gofeedErr gofeed.HTTPError
+	if errors.As(err, &gofeedErr) {
+		return gofeedErr.StatusCode
+	}
+	return 0
+}
+
+// classifyFetchError maps a fetch error to a coarse error class
+func classifyFetchError(feed models.Feed, err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if status := statusCodeFromError(err); status != 0 {
+		if status >= 500 {
+			return ErrorClassHTTP5xx
+		}
+		return ErrorClassHTTP4xx
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ErrorClassCanceled
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorClassDNS
+	}
+
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var certInvalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &certInvalidErr) || errors.As(err, &recordErr) {
+		return ErrorClassTLS
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorClassTimeout
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorClassConnection
+	}
+
+	var scriptErr *ScriptError
+	if errors.As(err, &scriptErr) {
+		return ErrorClassScript
+	}
+
+	if errors.Is(err, gofeed.ErrFeedTypeNotDetected) {
+		return ErrorClassParse
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return ErrorClassTimeout
+	case strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:"):
+		return ErrorClassTLS
+	case strings.Contains(msg, "no such host"):
+		return ErrorClassDNS
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "connection reset") || strings.Contains(msg, "eof"):
+		return ErrorClassConnection
+	case strings.Contains(msg, "xml syntax error") || strings.Contains(msg, "failed to detect feed type") ||
+		strings.Contains(msg, "failed to parse") || strings.Contains(msg, "invalid character"):
+		return ErrorClassParse
+	}
+
+	if feed.ScriptPath != "" {
+		return ErrorClassScript
+	}
+	return ErrorClassOther
+}
+
+// backoffDuration returns how long scheduled refreshes should skip a feed after the given number of
+// consecutive failures. The first failure is not penalised; afterwards the delay doubles from 30 minutes
+// up to 24 hours.
+func backoffDuration(failures int) time.Duration {
+	if failures <= 1 {
+		return 0
+	}
+	backoff := baseBackoff
+	for i := 2; i < failures; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return backoff
+}
+
+// isPlainURLFeed reports whether a feed is fetched directly from its URL (so a redirect can update it)
+func isPlainURLFeed(feed models.Feed) bool {
+	return feedTypeLabel(feed) == "rss" && strings.HasPrefix(feed.URL, "http")
+}
+
+// recordFetchAttempt stores a single fetch attempt in the history and reacts to
+// 410 Gone (pausing the feed) and permanent redirects (updating the feed URL).
+func (f *Fetcher) recordFetchAttempt(feed models.Feed, info *fetchInfo, duration time.Duration, itemCount int, err error) {
+	status, redirectURL := info.snapshot()
+	if errStatus := statusCodeFromError(err); errStatus != 0 {
+		status = errStatus
+	}
+
+	entry := database.FetchHistoryEntry{
+		FeedID:     feed.ID,
+		FetchedAt:  time.Now(),
+		HTTPStatus: status,
+		DurationMs: duration.Milliseconds(),
+		ItemCount:  itemCount,
+		ErrorClass: classifyFetchError(feed, err),
+	}
+	if err != nil {
+		entry.ErrorMessage = err.Error()
+	}
+	if recordErr := f.db.RecordFetchAttempt(entry); recordErr != nil {
+		log.Printf("Failed to record fetch attempt for feed %s: %v", feed.Title, recordErr)
+	}
+
+	if status == http.StatusGone {
+		log.Printf("Feed %s returned 410 Gone, pausing refreshes", feed.Title)
+		if pauseErr := f.db.PauseFeed(feed.ID, "Feed is gone (HTTP 410), refreshing paused"); pauseErr != nil {
+			log.Printf("Failed to pause feed %s: %v", feed.Title, pauseErr)
+		}
+		return
+	}
+
+	if err == nil && redirectURL != "" && redirectURL != feed.URL && isPlainURLFeed(feed) {
+		log.Printf("Feed %s permanently moved to %s, updating URL", feed.Title, redirectURL)
+		if updateErr := f.db.UpdateFeedURL(feed.ID, redirectURL); updateErr != nil {
+			log.Printf("Failed to update URL of feed %s: %v", feed.Title, updateErr)
+		}
+	}
+}
+
+// recordRefreshOutcome updates the consecutive failure counter and backoff of a feed
+// after a refresh task (including its retry) has finished.
+func (tm *TaskManager) recordRefreshOutcome(feed models.Feed, err error) {
+	db := tm.fetcher.db
+	if err == nil {
+		if dbErr := db.RecordFeedSuccess(feed.ID); dbErr != nil {
+			log.Printf("Failed to record success for feed %s: %v", feed.Title, dbErr)
+		}
+		return
+	}
+
+	// Cancellation (e.g. shutdown) says nothing about the health of the feed
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	failures, dbErr := db.RecordFeedFailure(feed.ID)
+	if dbErr != nil {
+		log.Printf("Failed to record failure for feed %s: %v", feed.Title, dbErr)
+		return
+	}
+
+	if backoff := backoffDuration(failures); backoff > 0 {
+		log.Printf("Feed %s failed %d times in a row, backing off for %v", feed.Title, failures, backoff)
+		if dbErr := db.SetFeedNextRetry(feed.ID, time.Now().Add(backoff)); dbErr != nil {
+			log.Printf("Failed to set backoff for feed %s: %v", feed.Title, dbErr)
+		}
+	}
+}
+
+// filterBackedOffFeeds removes feeds that are paused or whose backoff period has not yet expired
+func (tm *TaskManager) filterBackedOffFeeds(feeds []models.Feed) []models.Feed {
+	backoffs, err := tm.fetcher.db.GetFeedBackoffs()
+	if err != nil {
+		log.Printf("Failed to load feed backoffs: %v", err)
+		return feeds
+	}
+	paused, err := tm.fetcher.db.GetPausedFeeds()
+	if err != nil {
+		log.Printf("Failed to load paused feeds: %v", err)
+		return feeds
+	}
+	if len(backoffs) == 0 && len(paused) == 0 {
+		return feeds
+	}
+
+	filtered := make([]models.Feed, 0, len(feeds))
+	skipped := 0
+	for _, feed := range feeds {
+		_, backingOff := backoffs[feed.ID]
+		_, isPaused := paused[feed.ID]
+		if backingOff || isPaused {
+			skipped++
+			continue
+		}
+		filtered = append(filtered, feed)
+	}
+	if skipped > 0 {
+		log.Printf("Skipped %d failing feeds that are paused or backing off", skipped)
+	}
+	return filtered
+}
+
+// FeedHealthEntry describes a single feed in the health report
+type FeedHealthEntry struct {
+	FeedID              int64      `json:"feed_id"`
+	Title               string     `json:"title"`
+	URL                 string     `json:"url"`
+	Category            string     `json:"category"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastHTTPStatus      int        `json:"last_http_status,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	NextRetryAt         *time.Time `json:"next_retry_at,omitempty"`
+	Paused              bool       `json:"paused"`
+	PausedReason        string     `json:"paused_reason,omitempty"`
+	LatestArticleTime   *time.Time `json:"latest_article_time,omitempty"`
+	AvgDurationMs       int64      `json:"avg_duration_ms"`
+}
+
+// FeedHealthReport groups feeds that need attention
+type FeedHealthReport struct {
+	Broken        []FeedHealthEntry `json:"broken"`
+	Stale         []FeedHealthEntry `json:"stale"`
+	Slow          []FeedHealthEntry `json:"slow"`
+	TotalFeeds    int               `json:"total_feeds"`
+	StaleDays     int               `json:"stale_days"`
+	SlowThreshold int64             `json:"slow_threshold_ms"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+}
+
+// BuildFeedHealthReport classifies feeds as broken (repeated failures or paused), stale (no new
+// articles for staleDays) and slow (average successful fetch slower than slowThreshold).
+// A feed can appear in more than one list.
+func BuildFeedHealthReport(db *database.DB, staleDays int, slowThreshold time.Duration) (*FeedHealthReport, error) {
+	records, err := db.GetFeedHealthRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &FeedHealthReport{
+		Broken:        []FeedHealthEntry{},
+		Stale:         []FeedHealthEntry{},
+		Slow:          []FeedHealthEntry{},
+		TotalFeeds:    len(records),
+		StaleDays:     staleDays,
+		SlowThreshold: slowThreshold.Milliseconds(),
+		GeneratedAt:   now,
+	}
+	staleBefore := now.AddDate(0, 0, -staleDays)
+
+	for _, r := range records {
+		entry := FeedHealthEntry{
+			FeedID:              r.FeedID,
+			Title:               r.Title,
+			URL:                 r.URL,
+			Category:            r.Category,
+			LastError:           r.LastError,
+			LastHTTPStatus:      r.LastHTTPStatus,
+			ConsecutiveFailures: r.ConsecutiveFailures,
+			LastSuccessAt:       r.LastSuccessAt,
+			NextRetryAt:         r.NextRetryAt,
+			Paused:              r.PausedReason != "",
+			PausedReason:        r.PausedReason,
+			LatestArticleTime:   r.LatestArticleTime,
+			AvgDurationMs:       r.AvgDurationMs,
+		}
+
+		if entry.Paused || r.ConsecutiveFailures >= BrokenFailureThreshold {
+			report.Broken = append(report.Broken, entry)
+		}
+		if staleDays > 0 && (r.LatestArticleTime == nil || r.LatestArticleTime.Before(staleBefore)) {
+			report.Stale = append(report.Stale, entry)
+		}
+		if slowThreshold > 0 && r.AvgDurationMs >= slowThreshold.Milliseconds() {
+			report.Slow = append(report.Slow, entry)
+		}
+	}
+
+	sort.SliceStable(report.Broken, func(i, j int) bool {
+		return report.Broken[i].ConsecutiveFailures > report.Broken[j].ConsecutiveFailures
+	})
+	sort.SliceStable(report.Slow, func(i, j int) bool {
+		return report.Slow[i].AvgDurationMs > report.Slow[j].AvgDurationMs
+	})
+
+	return report, nil
+}
@@ -0,0 +1,233 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 30 * time.Minute},
+		{3, time.Hour},
+		{4, 2 * time.Hour},
+		{10, 24 * time.Hour},
+		{100, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoffDuration(tt.failures); got != tt.want {
+			t.Errorf("backoffDuration(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyFetchError(t *testing.T) {
+	tests := []struct {
+		name string
+		feed models.Feed
+		err  error
+		want string
+	}{
+		{"nil", models.Feed{}, nil, ""},
+		{"404", models.Feed{}, &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}, ErrorClassHTTP4xx},
+		{"503 wrapped", models.Feed{}, fmt.Errorf("fetch: %w", &HTTPStatusError{StatusCode: 503}), ErrorClassHTTP5xx},
+		{"gofeed http error", models.Feed{}, gofeed.HTTPError{StatusCode: 410, Status: "410 Gone"}, ErrorClassHTTP4xx},
+		{"timeout", models.Feed{}, context.DeadlineExceeded, ErrorClassTimeout},
+		{"canceled", models.Feed{}, context.Canceled, ErrorClassCanceled},
+		{"dns", models.Feed{}, &net.DNSError{Err: "no such host", Name: "example.invalid"}, ErrorClassDNS},
+		{"connection", models.Feed{}, &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrorClassConnection},
+		{"parse", models.Feed{}, gofeed.ErrFeedTypeNotDetected, ErrorClassParse},
+		{"script", models.Feed{ScriptPath: "a.py"}, errors.New("exit status 1"), ErrorClassScript},
+		{"other", models.Feed{}, errors.New("something odd"), ErrorClassOther},
+	}
+	for _, tt := range tests {
+		if got := classifyFetchError(tt.feed, tt.err); got != tt.want {
+			t.Errorf("%s: classifyFetchError = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+const healthTestRSS = `<?xml version="1.0"?><rss><channel><title>Health</title>` +
+	`<item><title>one</title><link>/1</link><guid>1</guid></item>` +
+	`</channel></rss>`
+
+func TestFetchFeedWithContext_PermanentRedirectUpdatesURL(t *testing.T) {
+	db := setupDBForFeedTests(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(healthTestRSS))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	id, err := db.AddFeed(&models.Feed{Title: "moved", URL: srv.URL + "/old"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	feed, _ := db.GetFeedByID(id)
+
+	f := NewFetcher(db)
+	if err := f.fetchFeedWithContext(context.Background(), *feed); err != nil {
+		t.Fatalf("fetchFeedWithContext error: %v", err)
+	}
+
+	updated, _ := db.GetFeedByID(id)
+	if updated.URL != srv.URL+"/new" {
+		t.Errorf("expected URL to be updated to %s, got %s", srv.URL+"/new", updated.URL)
+	}
+
+	history, err := db.GetFetchHistory(id, 10)
+	if err != nil {
+		t.Fatalf("GetFetchHistory error: %v", err)
+	}
+	if len(history) != 1 || history[0].HTTPStatus != 200 || history[0].ItemCount != 1 || history[0].ErrorClass != "" {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestFetchFeedWithContext_TemporaryRedirectKeepsURL(t *testing.T) {
+	db := setupDBForFeedTests(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(healthTestRSS))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	id, _ := db.AddFeed(&models.Feed{Title: "temp", URL: srv.URL + "/old"})
+	feed, _ := db.GetFeedByID(id)
+
+	f := NewFetcher(db)
+	if err := f.fetchFeedWithContext(context.Background(), *feed); err != nil {
+		t.Fatalf("fetchFeedWithContext error: %v", err)
+	}
+
+	updated, _ := db.GetFeedByID(id)
+	if updated.URL != srv.URL+"/old" {
+		t.Errorf("expected URL to stay %s, got %s", srv.URL+"/old", updated.URL)
+	}
+}
+
+func TestFetchFeedWithContext_GonePausesFeed(t *testing.T) {
+	db := setupDBForFeedTests(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	id, _ := db.AddFeed(&models.Feed{Title: "gone", URL: srv.URL})
+	feed, _ := db.GetFeedByID(id)
+
+	f := NewFetcher(db)
+	f.fp = &MockParser{Err: gofeed.HTTPError{StatusCode: http.StatusGone, Status: "410 Gone"}}
+	if err := f.fetchFeedWithContext(context.Background(), *feed); err == nil {
+		t.Fatal("expected error for 410 response")
+	}
+
+	updated, _ := db.GetFeedByID(id)
+	if updated.RefreshInterval != feed.RefreshInterval {
+		t.Errorf("expected the refresh interval to be kept, got %d", updated.RefreshInterval)
+	}
+	tm := f.GetTaskManager()
+	if got := tm.filterBackedOffFeeds([]models.Feed{*updated}); len(got) != 0 {
+		t.Error("expected paused feed to be skipped")
+	}
+
+	// A successful refresh resumes the feed
+	tm.recordRefreshOutcome(*updated, nil)
+	if got := tm.filterBackedOffFeeds([]models.Feed{*updated}); len(got) != 1 {
+		t.Error("expected feed to be resumed after a successful refresh")
+	}
+
+	history, _ := db.GetFetchHistory(id, 10)
+	if len(history) != 1 || history[0].HTTPStatus != http.StatusGone || history[0].ErrorClass != ErrorClassHTTP4xx {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+func TestRecordRefreshOutcome_BackoffAndReport(t *testing.T) {
+	db := setupDBForFeedTests(t)
+	f := NewFetcher(db)
+	tm := f.GetTaskManager()
+
+	id, _ := db.AddFeed(&models.Feed{Title: "flaky", URL: "https://example.com/flaky.xml"})
+	feed, _ := db.GetFeedByID(id)
+
+	fetchErr := &HTTPStatusError{StatusCode: 500, Status: "500 Internal Server Error"}
+	tm.recordRefreshOutcome(*feed, fetchErr)
+	if got := tm.filterBackedOffFeeds([]models.Feed{*feed}); len(got) != 1 {
+		t.Fatal("expected no backoff after the first failure")
+	}
+
+	tm.recordRefreshOutcome(*feed, fetchErr)
+	tm.recordRefreshOutcome(*feed, fetchErr)
+	if got := tm.filterBackedOffFeeds([]models.Feed{*feed}); len(got) != 0 {
+		t.Fatal("expected feed to be skipped while backing off")
+	}
+
+	// Cancellation does not count as a failure
+	tm.recordRefreshOutcome(*feed, context.Canceled)
+
+	report, err := BuildFeedHealthReport(db, 30, 10*time.Second)
+	if err != nil {
+		t.Fatalf("BuildFeedHealthReport error: %v", err)
+	}
+	if len(report.Broken) != 1 || report.Broken[0].ConsecutiveFailures != 3 {
+		t.Errorf("expected feed to be reported broken with 3 failures, got %+v", report.Broken)
+	}
+	if len(report.Stale) != 1 {
+		t.Errorf("expected feed without articles to be reported stale, got %+v", report.Stale)
+	}
+
+	tm.recordRefreshOutcome(*feed, nil)
+	if got := tm.filterBackedOffFeeds([]models.Feed{*feed}); len(got) != 1 {
+		t.Fatal("expected backoff to be cleared after success")
+	}
+	report, _ = BuildFeedHealthReport(db, 30, 10*time.Second)
+	if len(report.Broken) != 0 {
+		t.Errorf("expected no broken feeds after success, got %+v", report.Broken)
+	}
+}
+
+func TestAddGlobalRefresh_KeepsErrorsOfSkippedFeeds(t *testing.T) {
+	db := setupDBForFeedTests(t)
+	f := NewFetcher(db)
+	tm := f.GetTaskManager()
+
+	id, _ := db.AddFeed(&models.Feed{Title: "gone", URL: "https://example.com/gone.xml"})
+	if err := db.PauseFeed(id, "Feed is gone"); err != nil {
+		t.Fatalf("PauseFeed error: %v", err)
+	}
+	feed, _ := db.GetFeedByID(id)
+
+	tm.AddGlobalRefresh(context.Background(), []models.Feed{*feed})
+
+	updated, _ := db.GetFeedByID(id)
+	if updated.LastError != "Feed is gone" {
+		t.Errorf("expected the error of the paused feed to be kept, got %q", updated.LastError)
+	}
+}
@@ -0,0 +1,41 @@
+package feed
+
+import (
+	"time"
+
+	"MrRSS/internal/metrics"
+	"MrRSS/internal/microformats"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rsshub"
+)
+
+// feedTypeLabel returns a low-cardinality feed type label used for metrics
+func feedTypeLabel(feed models.Feed) string {
+	switch {
+	case feed.Type == "email":
+		return "email"
+	case feed.ScriptPath != "":
+		return "script"
+	case feed.Type == "HTML+XPath":
+		return "html_xpath"
+	case feed.Type == "XML+XPath":
+		return "xml_xpath"
+	case feed.Type == microformats.FeedType:
+		return "microformats"
+	case rsshub.IsRSSHubURL(feed.URL):
+		return "rsshub"
+	default:
+		return "rss"
+	}
+}
+
+// recordFetchMetrics records the outcome and duration of a single fetch attempt
+func recordFetchMetrics(feed models.Feed, duration time.Duration, err error) {
+	feedType := feedTypeLabel(feed)
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metrics.FeedFetchTotal.Inc(feedType, result)
+	metrics.FeedFetchDuration.Observe(duration.Seconds(), feedType)
+}
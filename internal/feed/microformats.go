@@ -0,0 +1,139 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"MrRSS/internal/microformats"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// alternateFeedSelector matches <link> elements advertising RSS, Atom or JSON Feed documents
+const alternateFeedSelector = "link[rel~='alternate'][type='application/rss+xml'], " +
+	"link[rel~='alternate'][type='application/atom+xml'], " +
+	"link[rel~='alternate'][type='application/feed+json'], " +
+	"link[rel~='alternate'][type='application/json']"
+
+// parseFeedWithMicroformats fetches an HTML page and parses its h-feed
+func (f *Fetcher) parseFeedWithMicroformats(ctx context.Context, feed *models.Feed) (*gofeed.Feed, error) {
+	body, err := f.fetchAndSanitizeFeed(ctx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedFeed, err := microformats.Parse(strings.NewReader(body), feed.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse h-feed: %w", err)
+	}
+	return parsedFeed, nil
+}
+
+// looksLikeHTML reports whether content is an HTML page rather than a feed document
+func looksLikeHTML(content string) bool {
+	head := content
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	head = strings.ToLower(strings.TrimSpace(head))
+	return strings.HasPrefix(head, "<!doctype html") || strings.Contains(head, "<html")
+}
+
+// alternateFeedLinks returns the absolute URLs of feeds advertised in the <head> of a page
+func alternateFeedLinks(doc *goquery.Document, pageURL string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var links []string
+	seen := map[string]bool{pageURL: true}
+	doc.Find(alternateFeedSelector).Each(func(_ int, sel *goquery.Selection) {
+		href := strings.TrimSpace(sel.AttrOr("href", ""))
+		if href == "" {
+			return
+		}
+		resolved, err := base.Parse(href)
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return
+		}
+		link := resolved.String()
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	})
+	return links
+}
+
+// addSubscriptionFromHTML handles subscription URLs that point to an HTML page.
+// It subscribes to the first working RSS, Atom or JSON Feed advertised by the page
+// and otherwise falls back to the page's h-feed markup. handled is false when the
+// page offers neither, so the caller can continue with its other strategies.
+func (f *Fetcher) addSubscriptionFromHTML(ctx context.Context, pageURL, content, category, customTitle string) (feedID int64, handled bool, err error) {
+	if !looksLikeHTML(content) {
+		return 0, false, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return 0, false, nil
+	}
+
+	for _, link := range alternateFeedLinks(doc, pageURL) {
+		utils.DebugLog("AddSubscription: Trying feed advertised by %s: %s", pageURL, link)
+		body, fetchErr := f.fetchAndSanitizeFeed(ctx, link)
+		if fetchErr != nil {
+			continue
+		}
+		parsedFeed, parseErr := gofeed.NewParser().ParseString(body)
+		// Any JSON object parses as a JSON Feed, so make sure the document really is one
+		// (WordPress, for example, advertises its REST API as application/json)
+		if parseErr != nil || (parsedFeed.FeedType == "json" && !strings.Contains(parsedFeed.FeedVersion, "jsonfeed.org")) {
+			continue
+		}
+		feedID, err = f.db.AddFeed(feedFromParsed(link, parsedFeed, category, customTitle))
+		return feedID, true, err
+	}
+
+	if !microformats.HasEntries(doc) {
+		return 0, false, nil
+	}
+
+	parsedFeed, err := microformats.ParseDocument(doc, pageURL)
+	if err != nil {
+		return 0, false, nil
+	}
+	utils.DebugLog("AddSubscription: Using h-feed markup of %s (%d entries)", pageURL, len(parsedFeed.Items))
+
+	feed := feedFromParsed(pageURL, parsedFeed, category, customTitle)
+	feed.Type = microformats.FeedType
+	feedID, err = f.db.AddFeed(feed)
+	return feedID, true, err
+}
+
+// feedFromParsed builds a feed subscription from a parsed feed document
+func feedFromParsed(feedURL string, parsedFeed *gofeed.Feed, category, customTitle string) *models.Feed {
+	title := parsedFeed.Title
+	if customTitle != "" {
+		title = customTitle
+	}
+
+	feed := &models.Feed{
+		Title:       title,
+		URL:         feedURL,
+		Link:        parsedFeed.Link,
+		Description: parsedFeed.Description,
+		Category:    category,
+	}
+
+	if parsedFeed.Image != nil {
+		feed.ImageURL = parsedFeed.Image.URL
+	}
+	return feed
+}
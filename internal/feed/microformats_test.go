@@ -0,0 +1,131 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/microformats"
+)
+
+const testJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON Blog",
+  "home_page_url": "https://example.com/",
+  "items": [{"id": "1", "url": "https://example.com/1", "title": "First", "content_html": "<p>hi</p>"}]
+}`
+
+const testHFeedPage = `<!DOCTYPE html><html><head><title>Notes</title></head><body>
+<main class="h-feed"><h1 class="p-name">IndieWeb Notes</h1>
+<article class="h-entry"><a class="p-name u-url" href="/posts/1">Post one</a>
+<time class="dt-published" datetime="2024-05-01T08:00:00Z"></time><div class="e-content">Body one</div></article>
+<article class="h-entry"><a class="p-name u-url" href="/posts/2">Post two</a></article>
+</main></body></html>`
+
+func setupFetcherForMicroformatsTests(t *testing.T) (*Fetcher, *database.DB) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to init db: %v", err)
+	}
+	return NewFetcher(db), db
+}
+
+func TestAddSubscription_FollowsJSONFeedLink(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><head>` +
+			`<link rel="alternate" type="application/json" href="/wp-json/wp/v2/pages/1">` +
+			`<link rel="alternate" type="application/feed+json" href="/feed.json">` +
+			`</head><body></body></html>`))
+	})
+	mux.HandleFunc("/wp-json/wp/v2/pages/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "title": {"rendered": "About"}}`))
+	})
+	mux.HandleFunc("/feed.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json")
+		w.Write([]byte(testJSONFeed))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	fetcher, db := setupFetcherForMicroformatsTests(t)
+	feedID, err := fetcher.AddSubscription(srv.URL+"/", "", "")
+	if err != nil {
+		t.Fatalf("AddSubscription failed: %v", err)
+	}
+
+	feed, err := db.GetFeedByID(feedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID failed: %v", err)
+	}
+	if feed.URL != srv.URL+"/feed.json" || feed.Title != "JSON Blog" || feed.Type != "" {
+		t.Errorf("expected subscription to the JSON Feed, got url=%q title=%q type=%q", feed.URL, feed.Title, feed.Type)
+	}
+}
+
+func TestAddSubscription_HFeedPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(testHFeedPage))
+	}))
+	defer srv.Close()
+
+	fetcher, db := setupFetcherForMicroformatsTests(t)
+	feedID, err := fetcher.AddSubscription(srv.URL, "Blogs", "")
+	if err != nil {
+		t.Fatalf("AddSubscription failed: %v", err)
+	}
+
+	feed, err := db.GetFeedByID(feedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID failed: %v", err)
+	}
+	if feed.Type != microformats.FeedType || feed.Title != "IndieWeb Notes" || feed.URL != srv.URL {
+		t.Fatalf("unexpected feed: type=%q title=%q url=%q", feed.Type, feed.Title, feed.URL)
+	}
+	if label := feedTypeLabel(*feed); label != "microformats" {
+		t.Errorf("feedTypeLabel = %q", label)
+	}
+
+	parsed, err := fetcher.ParseFeedWithFeed(context.Background(), feed, false)
+	if err != nil {
+		t.Fatalf("ParseFeedWithFeed failed: %v", err)
+	}
+	if len(parsed.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(parsed.Items))
+	}
+	if parsed.Items[0].Link != srv.URL+"/posts/1" || parsed.Items[0].PublishedParsed == nil {
+		t.Errorf("unexpected first item: %+v", parsed.Items[0])
+	}
+}
+
+func TestParseFeedWithFeed_ImportedHFeedWithoutType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(testHFeedPage))
+	}))
+	defer srv.Close()
+
+	fetcher, db := setupFetcherForMicroformatsTests(t)
+	feedID, err := fetcher.ImportSubscription("Notes", srv.URL, "")
+	if err != nil {
+		t.Fatalf("ImportSubscription failed: %v", err)
+	}
+	feed, _ := db.GetFeedByID(feedID)
+
+	parsed, err := fetcher.ParseFeedWithFeed(context.Background(), feed, false)
+	if err != nil {
+		t.Fatalf("ParseFeedWithFeed failed: %v", err)
+	}
+	if len(parsed.Items) != 2 {
+		t.Errorf("expected 2 items from the h-feed fallback, got %d", len(parsed.Items))
+	}
+}
@@ -1,6 +1,7 @@
 package feed
 
 import (
+	"MrRSS/internal/microformats"
 	"MrRSS/internal/models"
 	"MrRSS/internal/rsshub"
 	"MrRSS/internal/utils"
@@ -142,6 +143,9 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("Upgrade-Insecure-Requests", "1")
 
+	// Track redirects so a permanently moved feed can be updated to its new URL
+	httpClient, permanentRedirectURL := trackRedirects(httpClient)
+
 	debugTimer.LogWithTime("Sending HTTP request to %s", feedURL)
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -150,10 +154,11 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 	}
 	defer resp.Body.Close()
 	debugTimer.Stage("HTTP request completed")
+	fetchInfoFromContext(ctx).setResponse(resp.StatusCode, permanentRedirectURL(resp))
 
 	if resp.StatusCode != http.StatusOK {
 		debugTimer.LogWithTime("HTTP status not OK: %d", resp.StatusCode)
-		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return "", &HTTPStatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	debugTimer.LogWithTime("Reading response body")
@@ -198,26 +203,14 @@ func (f *Fetcher) AddSubscription(url string, category string, customTitle strin
 		parsedFeed, parseErr := parser.ParseString(cleanedXML)
 		if parseErr == nil {
 			utils.DebugLog("AddSubscription: Successfully parsed sanitized feed for URL: %s", url)
-			title := parsedFeed.Title
-			if customTitle != "" {
-				title = customTitle
-			}
-
-			feed := &models.Feed{
-				Title:       title,
-				URL:         url,
-				Link:        parsedFeed.Link,
-				Description: parsedFeed.Description,
-				Category:    category,
-			}
-
-			if parsedFeed.Image != nil {
-				feed.ImageURL = parsedFeed.Image.URL
-			}
-
-			return f.db.AddFeed(feed)
+			return f.db.AddFeed(feedFromParsed(url, parsedFeed, category, customTitle))
 		}
 		utils.DebugLog("AddSubscription: Parsing sanitized feed failed: %v", parseErr)
+
+		// The URL may be a web page advertising a feed or publishing h-feed markup
+		if feedID, handled, err := f.addSubscriptionFromHTML(ctx, url, cleanedXML, category, customTitle); handled {
+			return feedID, err
+		}
 	}
 
 	// Fallback: Try standard parsing (for backward compatibility)
@@ -260,24 +253,7 @@ func (f *Fetcher) AddSubscription(url string, category string, customTitle strin
 		utils.DebugLog("AddSubscription: Standard RSS parsing succeeded for URL: %s", url)
 	}
 
-	title := parsedFeed.Title
-	if customTitle != "" {
-		title = customTitle
-	}
-
-	feed := &models.Feed{
-		Title:       title,
-		URL:         url,
-		Link:        parsedFeed.Link,
-		Description: parsedFeed.Description,
-		Category:    category,
-	}
-
-	if parsedFeed.Image != nil {
-		feed.ImageURL = parsedFeed.Image.URL
-	}
-
-	return f.db.AddFeed(feed)
+	return f.db.AddFeed(feedFromParsed(url, parsedFeed, category, customTitle))
 }
 
 // AddScriptSubscription adds a new feed subscription that uses a custom script
@@ -481,6 +457,7 @@ func (f *Fetcher) ParseFeed(ctx context.Context, url string) (*gofeed.Feed, erro
 // ParseFeedWithScript parses an RSS feed, using a custom script or XPath if specified.
 // If scriptPath is non-empty, it executes the script.
 // If feed.Type is "HTML+XPath" or "XML+XPath", it uses XPath parsing.
+// If feed.Type is "HTML+Microformats", it parses the h-entry markup of the page.
 // Otherwise, it fetches from the URL as normal.
 // priority: true for high-priority requests (like article content fetching), false for normal requests (like feed refresh)
 func (f *Fetcher) ParseFeedWithScript(ctx context.Context, url string, scriptPath string, priority bool) (*gofeed.Feed, error) {
@@ -559,6 +536,19 @@ func (f *Fetcher) parseFeedWithFeedInternal(ctx context.Context, feed *models.Fe
 		return f.parseFeedWithXPath(xpathCtx, feed)
 	}
 
+	// Check if this is an h-feed (microformats) page
+	if feed.Type == microformats.FeedType {
+		utils.DebugLog("parseFeedWithFeedInternal: Using h-feed parsing for %s", feed.URL)
+		mfCtx := ctx
+		if priority {
+			var cancel context.CancelFunc
+			mfCtx, cancel = context.WithTimeout(ctx, 15*time.Second) // Shorter timeout for content fetching
+			defer cancel()
+		}
+
+		return f.parseFeedWithMicroformats(mfCtx, feed)
+	}
+
 	debugTimer.Stage("Traditional URL fetching")
 	utils.DebugLog("parseFeedWithFeedInternal: Using traditional URL-based fetching for %s", feed.URL)
 	// Use traditional URL-based fetching
@@ -606,6 +596,14 @@ func (f *Fetcher) parseFeedWithFeedInternal(ctx context.Context, feed *models.Fe
 			return parsedFeed, nil
 		}
 		utils.DebugLog("parseFeedWithFeedInternal: Parsing sanitized feed failed: %v", err)
+
+		// Imported subscriptions don't carry the feed type, so also accept h-feed pages here
+		if looksLikeHTML(cleanedXML) {
+			if mfFeed, mfErr := microformats.Parse(strings.NewReader(cleanedXML), actualURL); mfErr == nil {
+				utils.DebugLog("parseFeedWithFeedInternal: Parsed h-feed markup for %s", actualURL)
+				return mfFeed, nil
+			}
+		}
 		// Fall through to standard parsing
 	} else {
 		debugTimer.LogWithTime("Sanitization failed, will try standard parsing")
@@ -239,6 +239,7 @@ func (tm *TaskManager) AddToQueueHead(ctx context.Context, feed models.Feed, rea
 
 // AddToQueueTail adds a task to the queue tail (lowest priority)
 // Used for: scheduled refresh with custom interval
+// Feeds that are paused or backing off after repeated failures are skipped.
 func (tm *TaskManager) AddToQueueTail(ctx context.Context, feed models.Feed, reason TaskReason) {
 	// Skip FreshRSS feeds - they are refreshed via sync, not standard refresh
 	if feed.IsFreshRSSSource {
@@ -246,6 +247,11 @@ func (tm *TaskManager) AddToQueueTail(ctx context.Context, feed models.Feed, rea
 		return
 	}
 
+	if len(tm.filterBackedOffFeeds([]models.Feed{feed})) == 0 {
+		log.Printf("Skipping feed %s (paused or backing off after repeated failures)", feed.Title)
+		return
+	}
+
 	tm.stateMutex.RLock()
 	isStopped := tm.isStopped
 	tm.stateMutex.RUnlock()
@@ -303,6 +309,7 @@ func (tm *TaskManager) AddToQueueTail(ctx context.Context, feed models.Feed, rea
 
 // AddGlobalRefresh adds multiple feeds to the queue tail for global refresh
 // Used for: scheduled global refresh
+// Feeds that are paused or backing off after repeated failures are skipped.
 func (tm *TaskManager) AddGlobalRefresh(ctx context.Context, feeds []models.Feed) {
 	tm.stateMutex.RLock()
 	isStopped := tm.isStopped
@@ -361,9 +368,16 @@ func (tm *TaskManager) AddGlobalRefresh(ctx context.Context, feeds []models.Feed
 		log.Printf("ERROR: Failed to track feed refresh: %v", err)
 	}
 
-	// Clear all feed error marks in database
-	if err := tm.fetcher.db.ClearAllFeedErrors(); err != nil {
-		log.Printf("Failed to clear all feed errors: %v", err)
+	// Skip feeds that are paused or backing off after repeated failures, keeping their errors
+	feeds = tm.filterBackedOffFeeds(feeds)
+
+	// Clear the error marks of the feeds being refreshed
+	feedIDs := make([]int64, len(feeds))
+	for i, feed := range feeds {
+		feedIDs[i] = feed.ID
+	}
+	if err := tm.fetcher.db.ClearFeedErrors(feedIDs); err != nil {
+		log.Printf("Failed to clear feed errors: %v", err)
 	}
 
 	// Add feeds to queue tail with deduplication
@@ -504,6 +518,7 @@ func (tm *TaskManager) ExecuteImmediately(ctx context.Context, feed models.Feed)
 		}
 
 		// Handle result
+		tm.recordRefreshOutcome(task.Feed, err)
 		if err != nil {
 			log.Printf("Failed to fetch feed %s (immediate): %v", task.Feed.Title, err)
 			tm.fetcher.db.UpdateFeedError(task.Feed.ID, err.Error())
@@ -653,6 +668,7 @@ func (tm *TaskManager) processTask(ctx context.Context, task *RefreshTask) {
 	}
 
 	// Handle result
+	tm.recordRefreshOutcome(task.Feed, err)
 	if err != nil {
 		log.Printf("Failed to fetch feed %s after retry: %v", task.Feed.Title, err)
 		tm.logOperation("FL", task.Feed.Title)
@@ -738,6 +754,13 @@ func (tm *TaskManager) GetStats() TaskStats {
 	return stats
 }
 
+// GetPoolCapacity returns the maximum number of tasks that can run concurrently
+func (tm *TaskManager) GetPoolCapacity() int {
+	tm.poolMutex.RLock()
+	defer tm.poolMutex.RUnlock()
+	return tm.poolCapacity
+}
+
 // GetActiveFeedNames returns the names of feeds currently being processed, sorted alphabetically
 func (tm *TaskManager) GetActiveFeedNames() []string {
 	tm.poolMutex.RLock()
@@ -10,6 +10,7 @@ import (
 
 	"MrRSS/internal/database"
 	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
 )
 
 // SyncResult represents the result of a sync operation
@@ -763,18 +764,21 @@ func (s *BidirectionalSyncService) saveArticlesFromServer(ctx context.Context, a
 
 		// Extract thumbnail from article content before creating the article
 		imageURL := extractImageURLFromHTML(article.Content)
+		language, languageConfidence := translation.GetLanguageDetector().DetectArticleLanguage(article.Title, article.Content)
 
 		// Create new article
 		mrssArticle := &models.Article{
-			FeedID:         feedID,
-			Title:          article.Title,
-			URL:            article.URL,
-			ImageURL:       imageURL,
-			Summary:        "",
-			PublishedAt:    article.Published,
-			IsRead:         isRead,
-			IsFavorite:     isStarred,
-			FreshRSSItemID: article.ID, // Save FreshRSS/Google Reader item ID
+			FeedID:             feedID,
+			Title:              article.Title,
+			URL:                article.URL,
+			ImageURL:           imageURL,
+			Summary:            "",
+			PublishedAt:        article.Published,
+			IsRead:             isRead,
+			IsFavorite:         isStarred,
+			FreshRSSItemID:     article.ID, // Save FreshRSS/Google Reader item ID
+			Language:           language,
+			LanguageConfidence: languageConfidence,
 		}
 
 		mrssArticles = append(mrssArticles, mrssArticle)
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
 )
 
 // Client represents a FreshRSS API client
@@ -667,15 +668,18 @@ func (s *SyncService) Sync(ctx context.Context) error {
 			continue
 		}
 
+		language, languageConfidence := translation.GetLanguageDetector().DetectArticleLanguage(freshArt.Title, freshArt.Content)
 		article := &models.Article{
-			FeedID:      freshRSSFeedID,
-			Title:       freshArt.Title,
-			URL:         freshArt.URL,
-			Summary:     freshArt.Content, // Store FreshRSS content as summary
-			PublishedAt: freshArt.Published,
-			IsRead:      false, // FreshRSS unread articles
-			IsFavorite:  false,
-			IsHidden:    false,
+			FeedID:             freshRSSFeedID,
+			Title:              freshArt.Title,
+			URL:                freshArt.URL,
+			Summary:            freshArt.Content, // Store FreshRSS content as summary
+			PublishedAt:        freshArt.Published,
+			IsRead:             false, // FreshRSS unread articles
+			IsFavorite:         false,
+			IsHidden:           false,
+			Language:           language,
+			LanguageConfidence: languageConfidence,
 		}
 		mrssArticles = append(mrssArticles, article)
 	}
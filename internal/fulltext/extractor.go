@@ -0,0 +1,390 @@
+// Package fulltext extracts the full text of articles from their original web pages.
+// Extraction uses readability by default and can be tuned per site with
+// database.ExtractionRule (selectors to keep or strip, request headers and
+// pagination for multi-page articles).
+package fulltext
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/utils"
+
+	"codeberg.org/readeck/go-readability/v2"
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+const (
+	// DefaultMaxPages is the number of pages followed when a rule does not set MaxPages
+	DefaultMaxPages = 5
+	// maxPagesLimit caps MaxPages to avoid crawling endless "next" chains
+	maxPagesLimit = 20
+	// maxPageSize limits how much of a page is read
+	maxPageSize = 10 << 20
+	// requestTimeout is the timeout for fetching a single page
+	requestTimeout = 30 * time.Second
+	// defaultUserAgent is sent unless a rule overrides it
+	defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+// Result is the outcome of a full-text extraction
+type Result struct {
+	URL             string   `json:"url"`
+	Title           string   `json:"title"`
+	Content         string   `json:"content"`               // Extracted HTML of all pages
+	Pages           []string `json:"pages"`                 // URLs of the pages that were fetched
+	RuleDomain      string   `json:"rule_domain,omitempty"` // Domain of the applied rule, empty if none
+	UsedReadability bool     `json:"used_readability"`      // False when keep selectors selected the content
+	TextLength      int      `json:"text_length"`
+}
+
+// Extractor fetches pages and extracts their main content
+type Extractor struct {
+	db *database.DB
+}
+
+// NewExtractor creates an extractor that looks up per-site rules and proxy settings in db
+func NewExtractor(db *database.DB) *Extractor {
+	return &Extractor{db: db}
+}
+
+// httpClient returns a client using the global proxy settings, read on each extraction so
+// that changes apply without a restart
+func (e *Extractor) httpClient() *http.Client {
+	var client *http.Client
+	var err error
+	if e.db != nil {
+		client, err = utils.CreateHTTPClientWithProxy(e.db, requestTimeout)
+	} else {
+		client, err = utils.CreateHTTPClient("", requestTimeout)
+	}
+	if err != nil {
+		return &http.Client{Timeout: requestTimeout}
+	}
+	return client
+}
+
+// Extract extracts the full text of pageURL using the rule configured for its domain
+func (e *Extractor) Extract(ctx context.Context, pageURL string) (*Result, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	var rule *database.ExtractionRule
+	if e.db != nil {
+		rule, err = e.db.GetExtractionRuleForHost(u.Hostname())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load extraction rule: %w", err)
+		}
+	}
+	return e.ExtractWithRule(ctx, pageURL, rule)
+}
+
+// ExtractWithRule extracts the full text of pageURL using the given rule, which may be nil.
+// It is also used to preview rules that have not been saved yet. The content is cleaned with
+// utils.CleanHTML, like feed content, whether it is extracted on ingest or on demand.
+func (e *Extractor) ExtractWithRule(ctx context.Context, pageURL string, rule *database.ExtractionRule) (*Result, error) {
+	if rule == nil {
+		rule = &database.ExtractionRule{}
+	}
+
+	maxPages := rule.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	if maxPages > maxPagesLimit {
+		maxPages = maxPagesLimit
+	}
+	if strings.TrimSpace(rule.NextPageSelector) == "" {
+		maxPages = 1
+	}
+
+	result := &Result{URL: pageURL, RuleDomain: rule.Domain, UsedReadability: splitSelectors(rule.KeepSelectors) == nil}
+	client := e.httpClient()
+	visited := make(map[string]bool)
+	var content strings.Builder
+
+	nextURL := pageURL
+	for len(result.Pages) < maxPages && nextURL != "" && !visited[nextURL] {
+		visited[nextURL] = true
+
+		page, err := e.extractPage(ctx, client, nextURL, rule)
+		if err != nil {
+			// A broken follow-up page shouldn't discard the pages already extracted
+			if len(result.Pages) > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		result.Pages = append(result.Pages, nextURL)
+		if result.Title == "" {
+			result.Title = page.title
+		}
+		content.WriteString(page.content)
+		result.TextLength += page.textLength
+		nextURL = page.nextURL
+	}
+
+	result.Content = utils.CleanHTML(content.String())
+	if strings.TrimSpace(result.Content) == "" {
+		return nil, fmt.Errorf("no content extracted from %s", pageURL)
+	}
+	return result, nil
+}
+
+// pageResult holds the content extracted from a single page
+type pageResult struct {
+	title      string
+	content    string
+	textLength int
+	nextURL    string
+}
+
+// extractPage fetches a single page and extracts its content according to rule
+func (e *Extractor) extractPage(ctx context.Context, client *http.Client, pageURL string, rule *database.ExtractionRule) (*pageResult, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	doc, err := e.fetchDocument(ctx, client, pageURL, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &pageResult{}
+	if titleNode := htmlquery.FindOne(doc, "//title"); titleNode != nil {
+		result.title = strings.TrimSpace(htmlquery.InnerText(titleNode))
+	}
+
+	// Find the next page before stripping, as pagination is often inside removed elements
+	if selector := strings.TrimSpace(rule.NextPageSelector); selector != "" {
+		nodes, err := selectNodes(doc, selector)
+		if err != nil {
+			return nil, err
+		}
+		if len(nodes) > 0 {
+			result.nextURL = resolveURL(base, linkTarget(nodes[0]))
+		}
+	}
+
+	for _, selector := range splitSelectors(rule.StripSelectors) {
+		nodes, err := selectNodes(doc, selector)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			if node.Parent != nil {
+				node.Parent.RemoveChild(node)
+			}
+		}
+	}
+
+	if keepSelectors := splitSelectors(rule.KeepSelectors); keepSelectors != nil {
+		var kept []*html.Node
+		for _, selector := range keepSelectors {
+			nodes, err := selectNodes(doc, selector)
+			if err != nil {
+				return nil, err
+			}
+			kept = append(kept, nodes...)
+		}
+
+		var buf bytes.Buffer
+		for _, node := range topLevelNodes(kept) {
+			absolutizeURLs(node, base)
+			if err := html.Render(&buf, node); err != nil {
+				return nil, fmt.Errorf("render HTML: %w", err)
+			}
+			result.textLength += len(strings.TrimSpace(htmlquery.InnerText(node)))
+		}
+		result.content = buf.String()
+		return result, nil
+	}
+
+	article, err := readability.FromDocument(doc, base)
+	if err != nil {
+		return nil, fmt.Errorf("readability parse: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := article.RenderHTML(&buf); err != nil {
+		return nil, fmt.Errorf("render HTML: %w", err)
+	}
+	result.content = buf.String()
+	if article.Title() != "" {
+		result.title = article.Title()
+	}
+	var text bytes.Buffer
+	if err := article.RenderText(&text); err == nil {
+		result.textLength = len(strings.TrimSpace(text.String()))
+	}
+	return result, nil
+}
+
+// fetchDocument downloads and parses an HTML page, applying the rule's request headers
+func (e *Extractor) fetchDocument(ctx context.Context, client *http.Client, pageURL string, rule *database.ExtractionRule) (*html.Node, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	userAgent := defaultUserAgent
+	if rule.UserAgent != "" {
+		userAgent = rule.UserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+	if rule.Cookie != "" {
+		req.Header.Set("Cookie", rule.Cookie)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch page: HTTP %d", resp.StatusCode)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	return doc, nil
+}
+
+// splitSelectors splits a rule field into one selector per line, ignoring blank lines
+func splitSelectors(value string) []string {
+	var selectors []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			selectors = append(selectors, line)
+		}
+	}
+	return selectors
+}
+
+// isXPath reports whether a selector is an XPath expression rather than a CSS selector
+func isXPath(selector string) bool {
+	return strings.HasPrefix(selector, "/") || strings.HasPrefix(selector, "./") || strings.HasPrefix(selector, "(")
+}
+
+// selectNodes returns the nodes matching a CSS selector or XPath expression
+func selectNodes(root *html.Node, selector string) ([]*html.Node, error) {
+	if isXPath(selector) {
+		nodes, err := htmlquery.QueryAll(root, selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XPath expression %q: %w", selector, err)
+		}
+		return nodes, nil
+	}
+
+	sel, err := cascadia.ParseGroup(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSS selector %q: %w", selector, err)
+	}
+	return cascadia.QueryAll(root, sel), nil
+}
+
+// ValidateSelectors checks that every selector in a rule field can be parsed
+func ValidateSelectors(value string) error {
+	empty := &html.Node{Type: html.DocumentNode}
+	for _, selector := range splitSelectors(value) {
+		if _, err := selectNodes(empty, selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topLevelNodes returns nodes in document order, dropping duplicates and nodes nested in other kept nodes
+func topLevelNodes(nodes []*html.Node) []*html.Node {
+	kept := make(map[*html.Node]bool, len(nodes))
+	for _, node := range nodes {
+		kept[node] = true
+	}
+
+	result := make([]*html.Node, 0, len(nodes))
+	seen := make(map[*html.Node]bool, len(nodes))
+	for _, node := range nodes {
+		if seen[node] {
+			continue
+		}
+		seen[node] = true
+
+		nested := false
+		for parent := node.Parent; parent != nil; parent = parent.Parent {
+			if kept[parent] {
+				nested = true
+				break
+			}
+		}
+		if !nested {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// linkTarget returns the URL a next-page element points to
+func linkTarget(node *html.Node) string {
+	// XPath attribute selections (e.g. //a[@rel='next']/@href) yield detached nodes holding the value
+	if node.Type == html.TextNode || node.Parent == nil {
+		return strings.TrimSpace(htmlquery.InnerText(node))
+	}
+	for _, attr := range []string{"href", "data-href", "value"} {
+		if value := htmlquery.SelectAttr(node, attr); value != "" {
+			return value
+		}
+	}
+	// The selector may match a container of the link
+	if a := htmlquery.FindOne(node, ".//a[@href]"); a != nil {
+		return htmlquery.SelectAttr(a, "href")
+	}
+	return ""
+}
+
+// resolveURL resolves ref against base, returning "" for non-HTTP links
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return ""
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+		return ""
+	}
+	resolved.Fragment = ""
+	return resolved.String()
+}
+
+// absolutizeURLs rewrites relative links and image sources below node to absolute URLs
+func absolutizeURLs(node *html.Node, base *url.URL) {
+	if node.Type == html.ElementNode {
+		for i, attr := range node.Attr {
+			if attr.Key != "href" && attr.Key != "src" && attr.Key != "poster" {
+				continue
+			}
+			if resolved, err := base.Parse(strings.TrimSpace(attr.Val)); err == nil && !strings.HasPrefix(attr.Val, "#") {
+				node.Attr[i].Val = resolved.String()
+			}
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		absolutizeURLs(child, base)
+	}
+}
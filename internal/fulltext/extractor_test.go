@@ -0,0 +1,159 @@
+package fulltext
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+)
+
+// newTestSite serves a two-page article at /article and /article?page=2
+func newTestSite(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private" {
+			if r.Header.Get("Cookie") != "session=abc" || r.Header.Get("User-Agent") != "TestAgent/1.0" {
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, `<html><head><title>Private</title></head><body><div class="body"><p>Members only text.</p></div></body></html>`)
+			return
+		}
+
+		page := r.URL.Query().Get("page")
+		next := `<nav class="pager"><a rel="next" href="/article?page=2">Next</a></nav>`
+		text := "First page paragraph."
+		if page == "2" {
+			next = ""
+			text = "Second page paragraph."
+		}
+		fmt.Fprintf(w, `<html><head><title>Story</title></head><body>
+			<div class="ad">Buy now</div>
+			<div class="body"><p>%s</p><img src="/img.png"><div class="ad">Inline ad</div></div>
+			%s
+		</body></html>`, text, next)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestExtractWithRule_KeepStripAndPagination(t *testing.T) {
+	server := newTestSite(t)
+	extractor := NewExtractor(nil)
+
+	rule := &database.ExtractionRule{
+		KeepSelectors:    ".body",
+		StripSelectors:   ".ad\n//nav",
+		NextPageSelector: "a[rel=next]",
+	}
+	result, err := extractor.ExtractWithRule(context.Background(), server.URL+"/article", rule)
+	if err != nil {
+		t.Fatalf("ExtractWithRule error: %v", err)
+	}
+
+	if len(result.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got %v", result.Pages)
+	}
+	if !strings.Contains(result.Content, "First page paragraph.") || !strings.Contains(result.Content, "Second page paragraph.") {
+		t.Errorf("content missing page text: %s", result.Content)
+	}
+	if strings.Contains(result.Content, "Inline ad") || strings.Contains(result.Content, "Buy now") {
+		t.Errorf("stripped elements should be removed: %s", result.Content)
+	}
+	if !strings.Contains(result.Content, server.URL+"/img.png") {
+		t.Errorf("relative image URLs should be absolutized: %s", result.Content)
+	}
+	if strings.Contains(result.Content, "class=") {
+		t.Errorf("extracted content should be cleaned like feed content: %s", result.Content)
+	}
+	if result.UsedReadability {
+		t.Error("keep selectors should bypass readability")
+	}
+	if result.Title != "Story" {
+		t.Errorf("title = %q, want Story", result.Title)
+	}
+}
+
+func TestExtractWithRule_MaxPages(t *testing.T) {
+	server := newTestSite(t)
+	extractor := NewExtractor(nil)
+
+	rule := &database.ExtractionRule{
+		KeepSelectors:    ".body",
+		NextPageSelector: "//a[@rel='next']/@href",
+		MaxPages:         1,
+	}
+	result, err := extractor.ExtractWithRule(context.Background(), server.URL+"/article", rule)
+	if err != nil {
+		t.Fatalf("ExtractWithRule error: %v", err)
+	}
+	if len(result.Pages) != 1 || strings.Contains(result.Content, "Second page") {
+		t.Errorf("MaxPages should limit extraction to one page, got %v", result.Pages)
+	}
+}
+
+func TestExtractWithRule_RequestHeaders(t *testing.T) {
+	server := newTestSite(t)
+	extractor := NewExtractor(nil)
+
+	if _, err := extractor.ExtractWithRule(context.Background(), server.URL+"/private", nil); err == nil {
+		t.Fatal("expected an error without the rule's cookie")
+	}
+
+	rule := &database.ExtractionRule{KeepSelectors: ".body", UserAgent: "TestAgent/1.0", Cookie: "session=abc"}
+	result, err := extractor.ExtractWithRule(context.Background(), server.URL+"/private", rule)
+	if err != nil {
+		t.Fatalf("ExtractWithRule error: %v", err)
+	}
+	if !strings.Contains(result.Content, "Members only text.") {
+		t.Errorf("unexpected content: %s", result.Content)
+	}
+}
+
+func TestExtract_UsesGlobalProxy(t *testing.T) {
+	// The proxy answers every request itself, so content only arrives through it
+	proxied := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.Host == "article.invalid"
+		fmt.Fprint(w, `<html><body><div class="body"><p>Proxied text.</p></div></body></html>`)
+	}))
+	defer proxy.Close()
+	host, port, _ := strings.Cut(strings.TrimPrefix(proxy.URL, "http://"), ":")
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	for key, value := range map[string]string{"proxy_enabled": "true", "proxy_type": "http", "proxy_host": host, "proxy_port": port} {
+		if err := db.SetSetting(key, value); err != nil {
+			t.Fatalf("SetSetting error: %v", err)
+		}
+	}
+
+	result, err := NewExtractor(db).ExtractWithRule(context.Background(), "http://article.invalid/story", &database.ExtractionRule{KeepSelectors: ".body"})
+	if err != nil {
+		t.Fatalf("ExtractWithRule error: %v", err)
+	}
+	if !proxied || !strings.Contains(result.Content, "Proxied text.") {
+		t.Errorf("expected the page to be fetched through the proxy, got %q", result.Content)
+	}
+}
+
+func TestValidateSelectors(t *testing.T) {
+	if err := ValidateSelectors("article .content\n//div[@id='main']\n\n"); err != nil {
+		t.Errorf("valid selectors rejected: %v", err)
+	}
+	if err := ValidateSelectors("div[["); err == nil {
+		t.Error("expected an error for an invalid CSS selector")
+	}
+	if err := ValidateSelectors("//div[@id="); err == nil {
+		t.Error("expected an error for an invalid XPath expression")
+	}
+}
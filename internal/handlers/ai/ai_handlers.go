@@ -1,15 +1,18 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"MrRSS/internal/ai"
-	"MrRSS/internal/config"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
 )
 
@@ -25,11 +28,13 @@ type TestResult struct {
 
 // HandleTestAIConfig handles POST /api/ai/test to test AI configuration
 // @Summary      Test AI configuration
-// @Description  Test AI service configuration (endpoint, API key, model availability)
+// @Description  Test AI service configuration (endpoint, API key, model availability) of the global settings or a saved profile
 // @Tags         ai
 // @Accept       json
 // @Produce      json
+// @Param        profile_id  query  int  false  "AI profile ID (defaults to the global AI settings)"
 // @Success      200  {object}  handlers.TestResult  "Test result (config_valid, connection_success, model_available, response_time_ms, test_time, error_message)"
+// @Failure      404  {object}  map[string]string  "AI profile not found"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /ai/test [post]
 func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request) {
@@ -38,23 +43,36 @@ func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	result := TestResult{
-		TestTime: time.Now().Format(time.RFC3339),
+	// Test a saved profile when its ID is given, otherwise the global AI settings
+	profile := aiprofile.DefaultProfile(h.DB)
+	if idStr := r.URL.Query().Get("profile_id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid profile ID", http.StatusBadRequest)
+			return
+		}
+		saved, err := h.DB.GetAIProfile(id)
+		if err == sql.ErrNoRows {
+			http.Error(w, "AI profile not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		profile = *saved
 	}
 
-	// Get AI settings
-	apiKey, _ := h.DB.GetEncryptedSetting("ai_api_key")
-	endpoint, _ := h.DB.GetSetting("ai_endpoint")
-	model, _ := h.DB.GetSetting("ai_model")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(testProfile(h, r, profile))
+}
 
-	// Use defaults if not set
-	defaults := config.Get()
-	if endpoint == "" {
-		endpoint = defaults.AIEndpoint
-	}
-	if model == "" {
-		model = defaults.AIModel
+// testProfile validates a profile and sends a simple test request with it
+func testProfile(h *core.Handler, r *http.Request, profile database.AIProfile) TestResult {
+	result := TestResult{
+		TestTime: time.Now().Format(time.RFC3339),
 	}
+	endpoint, model := profile.Endpoint, profile.Model
 
 	// Validate configuration
 	result.ConfigValid = true
@@ -72,9 +90,7 @@ func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request)
 
 	if !result.ConfigValid {
 		result.ErrorMessage = "Configuration incomplete: " + strings.Join(validationErrors, ", ")
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
-		return
+		return result
 	}
 
 	// Validate endpoint URL format
@@ -82,18 +98,14 @@ func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		result.ConfigValid = false
 		result.ErrorMessage = "Invalid endpoint URL: " + err.Error()
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
-		return
+		return result
 	}
 
 	// Both HTTP and HTTPS are allowed
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
 		result.ConfigValid = false
 		result.ErrorMessage = "API endpoint must use HTTP or HTTPS"
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
-		return
+		return result
 	}
 
 	// Test connection with a simple request
@@ -106,23 +118,15 @@ func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request)
 		result.ModelAvailable = false
 		result.ErrorMessage = fmt.Sprintf("Failed to create HTTP client: %v", err)
 		result.ResponseTimeMs = time.Since(startTime).Milliseconds()
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(result)
-		return
+		return result
 	}
 	httpClient.Timeout = 30 * time.Second
 
 	// Create AI client for testing
-	clientConfig := ai.ClientConfig{
-		APIKey:   apiKey,
-		Endpoint: endpoint,
-		Model:    model,
-		Timeout:  30 * time.Second,
-	}
-	client := ai.NewClientWithHTTPClient(clientConfig, httpClient)
+	client := ai.NewClientWithHTTPClient(aiprofile.ClientConfig(profile, 30*time.Second), httpClient)
 
 	// Try a simple test request
-	_, err = client.Request("", "test")
+	_, err = client.RequestWithContext(r.Context(), "", "test")
 
 	if err != nil {
 		result.ConnectionSuccess = false
@@ -134,9 +138,7 @@ func HandleTestAIConfig(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	}
 
 	result.ResponseTimeMs = time.Since(startTime).Milliseconds()
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	return result
 }
 
 // HandleGetAITestInfo handles GET /api/ai/test/info to get last test result
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+)
+
+var (
+	errProfileNameRequired = errors.New("name is required")
+	errEndpointRequired    = errors.New("endpoint is required")
+	errModelRequired       = errors.New("model is required")
+	errInvalidUsageLimit   = errors.New("usage limit must not be negative")
+	errInvalidBudget       = errors.New("budget limits must not be negative")
+)
+
+// AIRoutesResponse lists the routable AI tasks and the profile IDs routed to each of them
+type AIRoutesResponse struct {
+	Tasks  []string           `json:"tasks"`
+	Routes map[string][]int64 `json:"routes"`
+}
+
+// HandleAIProfiles lists AI provider profiles (GET) or creates/updates one (POST).
+// @Summary      List or save AI profiles
+// @Description  GET returns all AI provider profiles. POST creates a profile, or updates it when an ID is given (usage counter is kept).
+// @Tags         ai
+// @Accept       json
+// @Produce      json
+// @Param        profile  body      database.AIProfile  false  "Profile to save (POST)"
+// @Success      200  {array}   database.AIProfile  "AI profiles (GET), or the saved profile ID (POST)"
+// @Failure      400  {object}  map[string]string  "Invalid profile"
+// @Failure      404  {object}  map[string]string  "Profile not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/profiles [get]
+// @Router       /ai/profiles [post]
+func HandleAIProfiles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		profiles, err := h.DB.GetAIProfiles()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(profiles)
+
+	case http.MethodPost:
+		var profile database.AIProfile
+		if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validateProfile(&profile); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.DB.SaveAIProfile(&profile)
+		if err == sql.ErrNoRows {
+			http.Error(w, "AI profile not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving AI profile: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int64{"id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDeleteAIProfile deletes an AI profile and removes it from all routes.
+// @Summary      Delete AI profile
+// @Description  Delete an AI provider profile; tasks it was routed to fail over to their remaining profiles
+// @Tags         ai
+// @Produce      json
+// @Param        id  query  int  true  "Profile ID"
+// @Success      200  {object}  map[string]bool  "Success status"
+// @Failure      400  {object}  map[string]string  "Invalid profile ID"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/profiles/delete [post]
+func HandleDeleteAIProfile(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid profile ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.DeleteAIProfile(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// HandleResetAIProfileUsage resets the usage counter of an AI profile.
+// @Summary      Reset AI profile usage
+// @Description  Reset the token usage counter of an AI provider profile to zero
+// @Tags         ai
+// @Produce      json
+// @Param        id  query  int  true  "Profile ID"
+// @Success      200  {object}  map[string]bool  "Success status"
+// @Failure      400  {object}  map[string]string  "Invalid profile ID"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/profiles/reset-usage [post]
+func HandleResetAIProfileUsage(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid profile ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.ResetAIProfileUsage(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// HandleAIRoutes returns the per-task profile routing (GET) or sets the route of a task (POST).
+// @Summary      Get or set AI routes
+// @Description  GET returns the routable tasks and their profile IDs in failover order. POST {task, profile_ids} replaces a task's route; an empty list routes the task to the global AI settings.
+// @Tags         ai
+// @Accept       json
+// @Produce      json
+// @Param        route  body      object  false  "Route to save (task, profile_ids) (POST)"
+// @Success      200  {object}  handlers.AIRoutesResponse  "Tasks and routes"
+// @Failure      400  {object}  map[string]string  "Invalid task or profile"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/routes [get]
+// @Router       /ai/routes [post]
+func HandleAIRoutes(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req struct {
+			Task       string  `json:"task"`
+			ProfileIDs []int64 `json:"profile_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !aiprofile.IsValidTask(req.Task) {
+			http.Error(w, fmt.Sprintf("unknown task %q", req.Task), http.StatusBadRequest)
+			return
+		}
+
+		seen := make(map[int64]bool)
+		for _, id := range req.ProfileIDs {
+			if seen[id] {
+				http.Error(w, fmt.Sprintf("profile %d is listed twice", id), http.StatusBadRequest)
+				return
+			}
+			seen[id] = true
+			if _, err := h.DB.GetAIProfile(id); err != nil {
+				http.Error(w, fmt.Sprintf("profile %d not found", id), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if err := h.DB.SetAIRoute(req.Task, req.ProfileIDs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	routes, err := h.DB.GetAIRoutes()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(AIRoutesResponse{Tasks: aiprofile.Tasks, Routes: routes})
+}
+
+// validateProfile checks a profile before it is saved
+func validateProfile(profile *database.AIProfile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	profile.Endpoint = strings.TrimSpace(profile.Endpoint)
+	profile.Model = strings.TrimSpace(profile.Model)
+
+	if profile.Name == "" {
+		return errProfileNameRequired
+	}
+	if profile.Endpoint == "" {
+		return errEndpointRequired
+	}
+	if profile.Model == "" {
+		return errModelRequired
+	}
+	if profile.UsageLimit < 0 {
+		return errInvalidUsageLimit
+	}
+	if u, err := url.Parse(profile.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid endpoint URL %q", profile.Endpoint)
+	}
+	if profile.CustomHeaders != "" {
+		var headers map[string]string
+		if err := json.Unmarshal([]byte(profile.CustomHeaders), &headers); err != nil {
+			return fmt.Errorf("custom headers must be a JSON object: %w", err)
+		}
+	}
+	return nil
+}
+
+// HandleAIBudgets returns the AI budgets with their current usage (GET) or sets a budget (POST).
+// @Summary      Get or set AI budgets
+// @Description  GET returns the daily and monthly AI budgets with the usage of their current period. POST {feature, period, max_tokens, max_cost} sets a budget; a budget without limits is removed. Feature "all" covers all features together.
+// @Tags         ai
+// @Accept       json
+// @Produce      json
+// @Param        budget  body      database.AIBudget  false  "Budget to save (POST)"
+// @Success      200  {array}   aiusage.BudgetStatus  "Budgets with current usage"
+// @Failure      400  {object}  map[string]string  "Invalid budget"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/budgets [get]
+// @Router       /ai/budgets [post]
+func HandleAIBudgets(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var budget database.AIBudget
+		if err := json.NewDecoder(r.Body).Decode(&budget); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := validateBudget(budget); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.DB.SetAIBudget(budget); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	statuses, err := h.AITracker.BudgetStatuses()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// validateBudget checks a budget before it is saved
+func validateBudget(budget database.AIBudget) error {
+	if budget.Feature != database.AIBudgetAllFeatures && !aiprofile.IsValidTask(budget.Feature) {
+		return fmt.Errorf("unknown feature %q", budget.Feature)
+	}
+	if budget.Period != database.AIBudgetDaily && budget.Period != database.AIBudgetMonthly {
+		return fmt.Errorf("unknown period %q", budget.Period)
+	}
+	if budget.MaxTokens < 0 || budget.MaxCost < 0 {
+		return errInvalidBudget
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"MrRSS/internal/database"
+	ff "MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+)
+
+func setupHandler(t *testing.T) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	return core.NewHandler(db, ff.NewFetcher(db), nil)
+}
+
+func saveProfile(t *testing.T, h *core.Handler, profile database.AIProfile) int64 {
+	t.Helper()
+	body, _ := json.Marshal(profile)
+	rr := httptest.NewRecorder()
+	HandleAIProfiles(h, rr, httptest.NewRequest(http.MethodPost, "/ai/profiles", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("save: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var saved struct {
+		ID int64 `json:"id"`
+	}
+	json.NewDecoder(rr.Body).Decode(&saved)
+	return saved.ID
+}
+
+func TestHandleAIProfiles_SaveAndValidate(t *testing.T) {
+	h := setupHandler(t)
+
+	id := saveProfile(t, h, database.AIProfile{Name: "Local", Endpoint: "http://localhost:11434/api/generate", Model: "llama3", Enabled: true})
+
+	rr := httptest.NewRecorder()
+	HandleAIProfiles(h, rr, httptest.NewRequest(http.MethodGet, "/ai/profiles", nil))
+	var profiles []database.AIProfile
+	if err := json.NewDecoder(rr.Body).Decode(&profiles); err != nil {
+		t.Fatalf("list: decode error: %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].ID != id || profiles[0].Model != "llama3" {
+		t.Fatalf("list: unexpected profiles %+v", profiles)
+	}
+
+	invalid := map[string]database.AIProfile{
+		"missing name":     {Endpoint: "http://localhost", Model: "m"},
+		"missing endpoint": {Name: "A", Model: "m"},
+		"bad endpoint":     {Name: "A", Endpoint: "ftp://host", Model: "m"},
+		"negative limit":   {Name: "A", Endpoint: "http://localhost", Model: "m", UsageLimit: -1},
+		"bad headers":      {Name: "A", Endpoint: "http://localhost", Model: "m", CustomHeaders: "X-Key: 1"},
+	}
+	for name, profile := range invalid {
+		body, _ := json.Marshal(profile)
+		rr := httptest.NewRecorder()
+		HandleAIProfiles(h, rr, httptest.NewRequest(http.MethodPost, "/ai/profiles", bytes.NewReader(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 got %d", name, rr.Code)
+		}
+	}
+}
+
+func TestHandleAIRoutes(t *testing.T) {
+	h := setupHandler(t)
+	local := saveProfile(t, h, database.AIProfile{Name: "Local", Endpoint: "http://localhost:11434", Model: "llama3", Enabled: true})
+	cloud := saveProfile(t, h, database.AIProfile{Name: "Cloud", Endpoint: "https://api.openai.com/v1/chat/completions", Model: "gpt-4o", Enabled: true})
+
+	post := func(route map[string]interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(route)
+		rr := httptest.NewRecorder()
+		HandleAIRoutes(h, rr, httptest.NewRequest(http.MethodPost, "/ai/routes", bytes.NewReader(body)))
+		return rr
+	}
+
+	rr := post(map[string]interface{}{"task": "translation", "profile_ids": []int64{local, cloud}})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp AIRoutesResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if got := resp.Routes["translation"]; len(got) != 2 || got[0] != local || got[1] != cloud {
+		t.Errorf("unexpected translation route: %v", got)
+	}
+	if len(resp.Tasks) != 4 {
+		t.Errorf("unexpected tasks: %v", resp.Tasks)
+	}
+
+	if rr := post(map[string]interface{}{"task": "unknown", "profile_ids": []int64{local}}); rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown task: expected 400 got %d", rr.Code)
+	}
+	if rr := post(map[string]interface{}{"task": "chat", "profile_ids": []int64{local, local}}); rr.Code != http.StatusBadRequest {
+		t.Errorf("duplicate profile: expected 400 got %d", rr.Code)
+	}
+	if rr := post(map[string]interface{}{"task": "chat", "profile_ids": []int64{999}}); rr.Code != http.StatusBadRequest {
+		t.Errorf("missing profile: expected 400 got %d", rr.Code)
+	}
+}
+
+func TestHandleAIBudgets(t *testing.T) {
+	h := setupHandler(t)
+
+	post := func(budget string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		HandleAIBudgets(h, rr, httptest.NewRequest(http.MethodPost, "/ai/budgets", bytes.NewReader([]byte(budget))))
+		return rr
+	}
+
+	for _, invalid := range []string{
+		`{"feature":"images","period":"daily","max_tokens":10}`,
+		`{"feature":"chat","period":"weekly","max_tokens":10}`,
+		`{"feature":"chat","period":"daily","max_tokens":-1}`,
+	} {
+		if rr := post(invalid); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 got %d", invalid, rr.Code)
+		}
+	}
+
+	rr := post(`{"feature":"chat","period":"daily","max_tokens":100}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	h.DB.AddAIUsageRecord(&database.AIUsageRecord{Feature: "chat", InputTokens: 80, OutputTokens: 30})
+
+	rr = httptest.NewRecorder()
+	HandleAIBudgets(h, rr, httptest.NewRequest(http.MethodGet, "/ai/budgets", nil))
+	var statuses []struct {
+		Feature    string `json:"feature"`
+		UsedTokens int64  `json:"used_tokens"`
+		Exceeded   bool   `json:"exceeded"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&statuses); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(statuses) != 1 || statuses[0].UsedTokens != 110 || !statuses[0].Exceeded {
+		t.Errorf("unexpected budget statuses: %+v", statuses)
+	}
+	if !h.AITracker.IsFeatureLimitReached("chat") || h.AITracker.IsFeatureLimitReached("summary") {
+		t.Error("expected only chat to be over budget")
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/prompts"
+	"MrRSS/internal/summary"
+)
+
+// PromptPreviewRequest is a template to render for an article. A saved template is previewed by
+// ID; the latest article is used when no article is given.
+type PromptPreviewRequest struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"`
+	Template  string `json:"template"`
+	ArticleID int64  `json:"article_id"`
+}
+
+// PromptPreviewResponse is a template rendered for an article, with the problems of the template
+type PromptPreviewResponse struct {
+	Rendered     string   `json:"rendered"`
+	Problems     []string `json:"problems"`
+	Variables    []string `json:"variables"`
+	ArticleID    int64    `json:"article_id"`
+	ArticleTitle string   `json:"article_title"`
+}
+
+// HandlePromptTemplates lists prompt templates (GET) or creates/updates one (POST).
+// @Summary      List or save prompt templates
+// @Description  GET returns the prompt templates, optionally of one kind (summary, translation or chat_action). POST creates a template, or updates it when an ID is given; its variables are validated.
+// @Tags         ai
+// @Accept       json
+// @Produce      json
+// @Param        kind      query     string                 false  "Template kind (GET)"
+// @Param        template  body      models.PromptTemplate  false  "Template to save (POST)"
+// @Success      200  {array}   models.PromptTemplate  "Prompt templates (GET), or the saved template ID (POST)"
+// @Failure      400  {object}  map[string]string  "Invalid template"
+// @Failure      404  {object}  map[string]string  "Template not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/prompts [get]
+// @Router       /ai/prompts [post]
+func HandlePromptTemplates(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		kind := r.URL.Query().Get("kind")
+		if kind != "" && !database.ValidPromptKind(kind) {
+			http.Error(w, "Unknown prompt kind", http.StatusBadRequest)
+			return
+		}
+		templates, err := h.DB.GetPromptTemplates(kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(templates)
+
+	case http.MethodPost:
+		var t models.PromptTemplate
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(t.Name) == "" {
+			http.Error(w, "Name is required", http.StatusBadRequest)
+			return
+		}
+		if problems := prompts.Validate(t.Kind, t.Template); len(problems) > 0 {
+			http.Error(w, strings.Join(problems, "; "), http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.DB.SavePromptTemplate(&t)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Prompt template not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving prompt template: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]int64{"id": id})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDeletePromptTemplate deletes a prompt template.
+// @Summary      Delete prompt template
+// @Description  Delete a prompt template; feeds overriding their template with it use the category or default template again
+// @Tags         ai
+// @Produce      json
+// @Param        id  query  int  true  "Template ID"
+// @Success      200  {object}  map[string]bool  "Success status"
+// @Failure      400  {object}  map[string]string  "Invalid template ID"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/prompts/delete [post]
+func HandleDeletePromptTemplate(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.DeletePromptTemplate(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// HandlePreviewPromptTemplate renders a prompt template for an article.
+// @Summary      Preview prompt template
+// @Description  Render a saved or edited prompt template with the variables of an article (the latest article by default), and list the problems of the template
+// @Tags         ai
+// @Accept       json
+// @Produce      json
+// @Param        request  body      PromptPreviewRequest  true  "Template and article"
+// @Success      200  {object}  PromptPreviewResponse  "Rendered template"
+// @Failure      400  {object}  map[string]string  "Invalid request"
+// @Failure      404  {object}  map[string]string  "Template or article not found"
+// @Router       /ai/prompts/preview [post]
+func HandlePreviewPromptTemplate(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req PromptPreviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ID > 0 {
+		t, err := h.DB.GetPromptTemplate(req.ID)
+		if err != nil {
+			http.Error(w, "Prompt template not found", http.StatusNotFound)
+			return
+		}
+		req.Kind, req.Template = t.Kind, t.Template
+	}
+	if !database.ValidPromptKind(req.Kind) {
+		http.Error(w, "Unknown prompt kind", http.StatusBadRequest)
+		return
+	}
+
+	var article *models.Article
+	if req.ArticleID > 0 {
+		a, err := h.DB.GetArticleByID(req.ArticleID)
+		if err != nil {
+			http.Error(w, "Article not found", http.StatusNotFound)
+			return
+		}
+		article = a
+	} else if latest, err := h.DB.GetArticles("", 0, "", false, 1, 0); err == nil && len(latest) > 0 {
+		article = &latest[0]
+	}
+
+	prompt := &prompts.Prompt{Template: models.PromptTemplate{Kind: req.Kind, Template: req.Template}}
+	resp := PromptPreviewResponse{
+		Problems:  prompts.Validate(req.Kind, req.Template),
+		Variables: prompts.Variables(req.Kind),
+	}
+	if article != nil {
+		resp.ArticleID, resp.ArticleTitle = article.ID, article.Title
+		prompt.Vars.Title = article.Title
+		if feed, err := h.DB.GetFeedByID(article.FeedID); err == nil {
+			prompt.Vars.Feed = feed.Title
+		}
+		if content, _, err := h.GetArticleContent(article.ID); err == nil {
+			prompt.Vars.Content = summary.PlainText(content)
+		}
+	}
+
+	language, _ := h.DB.GetSetting("language")
+	switch req.Kind {
+	case database.PromptKindSummary:
+		length, _ := h.DB.GetSetting("summary_length")
+		targetWords := summary.TargetWordCount(summary.SummaryLength(length))
+		resp.Rendered = prompt.SummaryPrompt(language)(prompt.Vars.Content, targetWords)
+	case database.PromptKindTranslation:
+		targetLang, _ := h.DB.GetSetting("target_language")
+		resp.Rendered = prompt.Render(targetLang)
+	default:
+		resp.Rendered = prompt.Render(language)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
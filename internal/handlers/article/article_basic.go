@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
 )
 
@@ -20,7 +21,9 @@ import (
 // @Param        category  query     string  false  "Filter by category name"
 // @Param        page      query     int     false  "Page number (default: 1)"  minimum(1)
 // @Param        limit     query     int     false  "Items per page (default: 50, max: 500)"  minimum(1)  maximum(500)
+// @Param        sort      query     string  false  "Sort order: 'date' (default) or 'relevance'"  Enums(date, relevance)
 // @Success      200  {array}   models.Article  "List of articles"
+// @Failure      400  {object}  map[string]string  "Bad request"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /articles [get]
 func HandleArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
@@ -29,6 +32,14 @@ func HandleArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	category := r.URL.Query().Get("category")
 	pageStr := r.URL.Query().Get("page")
 	limitStr := r.URL.Query().Get("limit")
+	sort := r.URL.Query().Get("sort")
+	if sort == "" {
+		sort = database.SortByDate
+	}
+	if sort != database.SortByDate && sort != database.SortByRelevance {
+		http.Error(w, "Invalid sort order", http.StatusBadRequest)
+		return
+	}
 
 	var feedID int64
 	if feedIDStr != "" {
@@ -51,11 +62,14 @@ func HandleArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	showHiddenStr, _ := h.DB.GetSetting("show_hidden_articles")
 	showHidden := showHiddenStr == "true"
 
-	articles, err := h.DB.GetArticles(filter, feedID, category, showHidden, limit, offset)
+	articles, err := h.DB.GetArticlesSorted(filter, feedID, category, showHidden, sort, limit, offset)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := h.DB.AttachArticleClassifications(articles); err != nil {
+		log.Printf("Error loading article classifications: %v", err)
+	}
 	json.NewEncoder(w).Encode(articles)
 }
 
@@ -127,6 +141,92 @@ func HandleToggleReadLater(h *core.Handler, w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
 
+// maxDwellSeconds caps the dwell time recorded for one view, an article left open is not read
+const maxDwellSeconds = 30 * 60
+
+// HandleRecordArticleDwell records the time spent reading an article.
+// @Summary      Record article dwell time
+// @Description  Add the seconds an article was open to its dwell time, a signal for relevance scoring (capped at 30 minutes per view)
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id       query     int64   true  "Article ID"
+// @Param        seconds  query     int     true  "Seconds the article was open"  minimum(1)
+// @Success      200  {object}  map[string]bool  "Success status"
+// @Failure      400  {object}  map[string]string  "Bad request"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/dwell [post]
+func HandleRecordArticleDwell(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+	seconds, err := strconv.Atoi(r.URL.Query().Get("seconds"))
+	if err != nil || seconds <= 0 {
+		http.Error(w, "Invalid seconds", http.StatusBadRequest)
+		return
+	}
+	if seconds > maxDwellSeconds {
+		seconds = maxDwellSeconds
+	}
+
+	if err := h.DB.RecordArticleDwell(id, seconds); err != nil {
+		log.Printf("Error recording article dwell time: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// maxTagCounts caps the tags returned by HandleArticleTags
+const maxTagCounts = 200
+
+// tagKindLanguage lists the detected article languages in HandleArticleTags
+const tagKindLanguage = "language"
+
+// HandleArticleTags returns the topics or entities articles were classified with, or their detected languages.
+// @Summary      Get article tags
+// @Description  List the topics or entities assigned by AI classification, or the languages detected when articles were saved, with their article counts, most used first
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        kind  query     string  false  "Tag kind: 'topic' (default), 'entity' or 'language'"  Enums(topic, entity, language)
+// @Success      200  {array}   database.TagCount  "Tags with article counts"
+// @Failure      400  {object}  map[string]string  "Bad request"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/tags [get]
+func HandleArticleTags(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = database.TagKindTopic
+	}
+	if kind != database.TagKindTopic && kind != database.TagKindEntity && kind != tagKindLanguage {
+		http.Error(w, "Invalid tag kind", http.StatusBadRequest)
+		return
+	}
+
+	var counts []database.TagCount
+	var err error
+	if kind == tagKindLanguage {
+		counts, err = h.DB.GetLanguageCounts(maxTagCounts)
+	} else {
+		counts, err = h.DB.GetTagCounts(kind, maxTagCounts)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(counts)
+}
+
 // HandleImageGalleryArticles returns articles from image mode feeds with pagination.
 // @Summary      Get image gallery articles
 // @Description  Retrieve articles from image-mode feeds (visual/rss-gallery feeds) with pagination
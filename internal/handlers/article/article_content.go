@@ -113,12 +113,21 @@ func HandleFetchFullArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Fetch full content
-	fullContent, err := h.FetchFullArticleContent(article.URL)
-	if err != nil {
-		log.Printf("Error fetching full article content: %v", err)
-		http.Error(w, "Failed to fetch full article content", http.StatusInternalServerError)
-		return
+	// Use previously extracted full text (e.g. fetched at ingest time) if available
+	fullContent, found, err := h.DB.GetArticleFullText(articleID)
+	if err != nil || !found {
+		fullContent, err = h.FetchFullArticleContent(article.URL)
+		if err != nil {
+			log.Printf("Error fetching full article content: %v", err)
+			http.Error(w, "Failed to fetch full article content", http.StatusInternalServerError)
+			return
+		}
+
+		// Persist the extracted text so it survives restarts and feed refreshes
+		if err := h.SaveArticleFullText(articleID, fullContent); err != nil {
+			log.Printf("Error caching full article content: %v", err)
+		}
+		h.ContentCache.Set(articleID, fullContent)
 	}
 
 	// Get feed URL to use as referer for image proxying
@@ -12,6 +12,7 @@ import (
 
 	"MrRSS/internal/handlers/core"
 	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
 
 	md "github.com/JohannesKaufmann/html-to-markdown"
 )
@@ -57,26 +58,9 @@ func HandleExportToObsidian(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Check if Obsidian integration is enabled
-	obsidianEnabled, _ := h.DB.GetSetting("obsidian_enabled")
-	if obsidianEnabled != "true" {
-		http.Error(w, "Obsidian integration is not enabled", http.StatusBadRequest)
-		return
-	}
-
-	// Get vault path (required for direct file access)
-	vaultPath, _ := h.DB.GetSetting("obsidian_vault_path")
-	if vaultPath == "" {
-		http.Error(w, "Obsidian vault path is not configured", http.StatusBadRequest)
-		return
-	}
-
-	// Validate vault path exists and is a directory
-	if info, err := os.Stat(vaultPath); os.IsNotExist(err) {
-		http.Error(w, "Obsidian vault path does not exist", http.StatusBadRequest)
-		return
-	} else if !info.IsDir() {
-		http.Error(w, "Obsidian vault path is not a directory", http.StatusBadRequest)
+	vaultPath, err := h.ObsidianVaultPath()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -91,7 +75,7 @@ func HandleExportToObsidian(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	markdownContent := generateObsidianMarkdown(*article, content)
 
 	// Generate filename (sanitize title)
-	filename := sanitizeFilename(article.Title)
+	filename := utils.SanitizeFilename(article.Title)
 	if filename == "" {
 		filename = fmt.Sprintf("Article_%d", article.ID)
 	}
@@ -150,25 +134,6 @@ func generateObsidianMarkdown(article models.Article, content string) string {
 	return sb.String()
 }
 
-// sanitizeFilename creates a safe filename from a title
-func sanitizeFilename(title string) string {
-	// Replace invalid filename characters
-	invalidChars := []string{"<", ">", ":", "\"", "|", "?", "*", "\\", "/"}
-	result := title
-
-	for _, char := range invalidChars {
-		result = strings.ReplaceAll(result, char, "_")
-	}
-
-	// Trim spaces and limit length
-	result = strings.TrimSpace(result)
-	if len(result) > 100 {
-		result = result[:100]
-	}
-
-	return result
-}
-
 // sanitizeTag creates a safe tag from feed name
 func sanitizeTag(feedName string) string {
 	// Convert to lowercase, replace spaces with underscores
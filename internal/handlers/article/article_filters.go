@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
 )
 
 // FilterCondition represents a single filter condition from the frontend
@@ -14,7 +15,7 @@ type FilterCondition struct {
 	ID       int64    `json:"id"`
 	Logic    string   `json:"logic"`    // "and", "or" (null for first condition)
 	Negate   bool     `json:"negate"`   // NOT modifier for this condition
-	Field    string   `json:"field"`    // "feed_name", "feed_category", "article_title", "published_after", "published_before"
+	Field    string   `json:"field"`    // "feed_name", "feed_category", "article_title", "published_after", "published_before", "language"
 	Operator string   `json:"operator"` // "contains", "exact" (null for date fields and multi-select)
 	Value    string   `json:"value"`    // Single value for text/date fields
 	Values   []string `json:"values"`   // Multiple values for feed_name and feed_category
@@ -75,6 +76,26 @@ func matchMultiSelectContains(fieldValue string, values []string, singleValue st
 	return true
 }
 
+// usesTags reports whether filter conditions match on topics or entities
+func usesTags(conditions []FilterCondition) bool {
+	for _, condition := range conditions {
+		if rules.IsTagField(condition.Field) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesKeywords reports whether filter conditions match on extracted keywords
+func usesKeywords(conditions []FilterCondition) bool {
+	for _, condition := range conditions {
+		if rules.IsKeywordField(condition.Field) {
+			return true
+		}
+	}
+	return false
+}
+
 // evaluateSingleCondition evaluates a single filter condition for an article
 func evaluateSingleCondition(article models.Article, condition FilterCondition, feedCategories map[int64]string, feedTypes map[int64]string, feedIsImageMode map[int64]bool) bool {
 	var result bool
@@ -180,6 +201,22 @@ func evaluateSingleCondition(article models.Article, condition FilterCondition,
 			result = article.IsReadLater == wantReadLater
 		}
 
+	case "topic", "entity":
+		// Filter by the topics or entities articles were classified with
+		tags := article.Topics
+		if condition.Field == "entity" {
+			tags = article.Entities
+		}
+		result = rules.MatchTags(tags, condition.Values, condition.Value)
+
+	case "language":
+		// Filter by the language detected when the article was saved
+		result = rules.MatchTags([]string{article.Language}, condition.Values, condition.Value)
+
+	case "keyword":
+		// Filter by the key phrases extracted from articles
+		result = rules.MatchKeywords(article.Keywords, condition.Values, condition.Value)
+
 	default:
 		result = true
 	}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/jobs"
 	"MrRSS/internal/models"
 	"MrRSS/internal/rsshub"
 )
@@ -69,8 +70,9 @@ func HandleProgress(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 
 // TaskDetailsResponse contains detailed task information
 type TaskDetailsResponse struct {
-	PoolTasks  []PoolTaskInfo  `json:"pool_tasks"`
-	QueueTasks []QueueTaskInfo `json:"queue_tasks"`
+	PoolTasks      []PoolTaskInfo  `json:"pool_tasks"`
+	QueueTasks     []QueueTaskInfo `json:"queue_tasks"`
+	BackgroundJobs *jobs.Progress  `json:"background_jobs,omitempty"` // Background translation and summary jobs
 }
 
 // PoolTaskInfo contains information about a task in the pool
@@ -90,7 +92,7 @@ type QueueTaskInfo struct {
 
 // HandleTaskDetails returns detailed information about tasks in pool and queue
 // @Summary      Get task details
-// @Description  Get detailed information about tasks in pool and queue
+// @Description  Get detailed information about tasks in pool and queue, and the progress of background translation and summary jobs
 // @Tags         articles
 // @Accept       json
 // @Produce      json
@@ -131,6 +133,13 @@ func HandleTaskDetails(h *core.Handler, w http.ResponseWriter, r *http.Request)
 		PoolTasks:  poolTasks,
 		QueueTasks: queueTasks,
 	}
+	if h.Jobs != nil {
+		if progress, err := h.Jobs.Progress(); err != nil {
+			log.Printf("Error getting background job progress: %v", err)
+		} else {
+			response.BackgroundJobs = &progress
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -138,6 +147,37 @@ func HandleTaskDetails(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// HandleRetryBackgroundJobs queues failed background jobs again.
+// @Summary      Retry failed background jobs
+// @Description  Queue the background translation and summary jobs that ran out of retries again
+// @Tags         articles
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Number of jobs queued again (retried)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /progress/background-jobs/retry [post]
+func HandleRetryBackgroundJobs(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	retried, err := h.DB.RetryFailedBackgroundJobs()
+	if err != nil {
+		log.Printf("Error retrying background jobs: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if retried > 0 && h.Jobs != nil {
+		h.Jobs.Wake()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"retried": retried,
+	})
+}
+
 // HandleFilteredArticles returns articles filtered by advanced conditions from the database.
 // @Summary      Get filtered articles
 // @Description  Retrieve articles with advanced filtering conditions
@@ -202,6 +242,23 @@ func HandleFilteredArticles(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		feedIsImageMode[feed.ID] = feed.IsImageMode
 	}
 
+	// Topics and entities are loaded for all articles only when a condition needs them
+	filterByTags := usesTags(req.Conditions)
+	if filterByTags {
+		if err := h.DB.AttachArticleClassifications(articles); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Keywords are loaded for all articles only when a condition needs them
+	if usesKeywords(req.Conditions) {
+		if err := h.DB.AttachArticleKeywords(articles); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Apply filter conditions
 	if len(req.Conditions) > 0 {
 		var filteredArticles []models.Article
@@ -232,6 +289,12 @@ func HandleFilteredArticles(h *core.Handler, w http.ResponseWriter, r *http.Requ
 
 	hasMore := end < total
 
+	if !filterByTags {
+		if err := h.DB.AttachArticleClassifications(paginatedArticles); err != nil {
+			log.Printf("Error loading article classifications: %v", err)
+		}
+	}
+
 	response := FilterResponse{
 		Articles: paginatedArticles,
 		Total:    total,
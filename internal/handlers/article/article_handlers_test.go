@@ -15,6 +15,7 @@ import (
 	"MrRSS/internal/handlers/article"
 	"MrRSS/internal/handlers/core"
 	"MrRSS/internal/models"
+	"MrRSS/internal/semantic"
 )
 
 func setupHandler(t *testing.T) *core.Handler {
@@ -198,3 +199,149 @@ func TestHandleExportToObsidian(t *testing.T) {
 		t.Fatalf("Export not successful: %v", response)
 	}
 }
+
+func TestHandleSemanticSearchAndSimilar(t *testing.T) {
+	h := setupHandler(t)
+
+	// Embeddings server: one dimension per topic word
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		var request struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		type item struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}
+		data := make([]item, len(request.Input))
+		for i, text := range request.Input {
+			text = strings.ToLower(text)
+			vector := []float32{0, 0}
+			if strings.Contains(text, "climate") {
+				vector[0] = 1
+			}
+			if strings.Contains(text, "chess") {
+				vector[1] = 1
+			}
+			data[i] = item{Index: i, Embedding: vector}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	// Disabled by default
+	w := httptest.NewRecorder()
+	article.HandleSemanticSearch(h, w, httptest.NewRequest(http.MethodGet, "/api/articles/semantic-search?q=climate", nil))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 while disabled, got %d", w.Code)
+	}
+
+	h.DB.SetSetting("ai_embedding_enabled", "true")
+	h.DB.SetSetting("ai_embedding_endpoint", server.URL+"/v1")
+	h.DB.SetSetting("ai_embedding_model", "test-embed")
+
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "F", URL: "http://x"})
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	err = h.DB.SaveArticles(context.Background(), []*models.Article{
+		{FeedID: feedID, Title: "Climate summit ends", URL: "u1", PublishedAt: time.Now()},
+		{FeedID: feedID, Title: "Warming oceans", Summary: "A climate study", URL: "u2", PublishedAt: time.Now()},
+		{FeedID: feedID, Title: "Chess championship", URL: "u3", PublishedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	if n, err := semantic.NewService(h.DB, h.AITracker).IndexPending(context.Background(), 10); err != nil || n != 3 {
+		t.Fatalf("IndexPending: n=%d err=%v", n, err)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleSemanticSearch(h, w, httptest.NewRequest(http.MethodGet, "/api/articles/semantic-search?q=climate+policy&limit=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("search failed: %d %s", w.Code, w.Body.String())
+	}
+	var matches []semantic.Match
+	if err := json.NewDecoder(w.Body).Decode(&matches); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Score < 0.99 || matches[1].Score < 0.99 {
+		t.Fatalf("expected both climate articles, got %+v", matches)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleSimilarArticles(h, w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/similar?id=%d", matches[0].ID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("similar failed: %d %s", w.Code, w.Body.String())
+	}
+	var similar []semantic.Match
+	json.NewDecoder(w.Body).Decode(&similar)
+	if len(similar) != 2 || similar[0].ID != matches[1].ID {
+		t.Errorf("expected the other climate article first, got %+v", similar)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleSemanticStatus(h, w, httptest.NewRequest(http.MethodGet, "/api/articles/semantic-status", nil))
+	var status semantic.Status
+	json.NewDecoder(w.Body).Decode(&status)
+	if !status.Enabled || status.Indexed != 3 || status.Model != "test-embed" {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
+
+func TestHandleArticles_SortByRelevanceAndDwell(t *testing.T) {
+	h := setupHandler(t)
+
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "F", URL: "http://x"})
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	articles := []*models.Article{
+		{FeedID: feedID, Title: "newest", URL: "u1", PublishedAt: time.Now()},
+		{FeedID: feedID, Title: "relevant", URL: "u2", PublishedAt: time.Now().Add(-time.Hour)},
+	}
+	if err := h.DB.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	saved, _ := h.DB.GetArticles("", feedID, "", false, 2, 0)
+	relevantID := saved[1].ID
+	if err := h.DB.SetRelevanceScores(map[int64]float64{relevantID: 88}); err != nil {
+		t.Fatalf("SetRelevanceScores: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	article.HandleArticles(h, w, httptest.NewRequest(http.MethodGet, "/api/articles?sort=relevance", nil))
+	var got []models.Article
+	if err := json.NewDecoder(w.Result().Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != relevantID || got[0].RelevanceScore == nil || *got[0].RelevanceScore != 88 {
+		t.Fatalf("expected the scored article first, got %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleArticles(h, w, httptest.NewRequest(http.MethodGet, "/api/articles?sort=popular", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown sort order, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleRecordArticleDwell(h, w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/articles/dwell?id=%d&seconds=7200", relevantID), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("dwell: expected 200, got %d", w.Code)
+	}
+	var seconds int
+	if err := h.DB.QueryRow(`SELECT seconds FROM article_dwell WHERE article_id = ?`, relevantID).Scan(&seconds); err != nil || seconds != 30*60 {
+		t.Errorf("expected the dwell time to be capped at 30 minutes, got %d (%v)", seconds, err)
+	}
+
+	w = httptest.NewRecorder()
+	article.HandleRecordArticleDwell(h, w, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/articles/dwell?id=%d&seconds=0", relevantID), nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for zero seconds, got %d", w.Code)
+	}
+}
@@ -0,0 +1,141 @@
+package article
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/keywords"
+)
+
+const (
+	defaultTrendingDays  = 7
+	maxTrendingDays      = 365
+	defaultTrendingLimit = 30
+	maxTrendingLimit     = 200
+	// extractBatchSize is the number of articles without keywords extracted per request
+	extractBatchSize = 500
+)
+
+// HandleArticleKeywords returns the key phrases of an article, extracting them if needed.
+// @Summary      Article keywords
+// @Description  Get the key phrases extracted from an article, best first. They are extracted offline on demand when the article has none yet.
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        article_id  query     int64  true  "Article ID"
+// @Success      200  {array}   database.ArticleKeyword  "Keywords with their score from 0 to 1"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid article ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/keywords [get]
+func HandleArticleKeywords(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("article_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	stored, err := h.DB.GetArticleKeywords(articleID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(stored) == 0 {
+		stored, err = keywords.NewService(h.DB).ExtractArticle(articleID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stored)
+}
+
+// HandleTrendingKeywords returns the most mentioned keywords of recent articles.
+// @Summary      Trending terms
+// @Description  Count the articles mentioning each keyword over the last days, globally or for one feed, with the count over the same number of days before for comparison
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        feed_id  query     int64  false  "Feed ID (all feeds if omitted)"
+// @Param        days     query     int    false  "Window in days (default 7, max 365)"
+// @Param        limit    query     int    false  "Maximum number of keywords (default 30, max 200)"
+// @Success      200  {array}   database.TrendingKeyword  "Keywords, most mentioned first"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid feed ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /keywords/trending [get]
+func HandleTrendingKeywords(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	var feedID int64
+	if v := query.Get("feed_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+			return
+		}
+		feedID = id
+	}
+	days := boundedInt(query.Get("days"), defaultTrendingDays, maxTrendingDays)
+	limit := boundedInt(query.Get("limit"), defaultTrendingLimit, maxTrendingLimit)
+
+	until := time.Now()
+	since := until.AddDate(0, 0, -days)
+	trending, err := h.DB.GetTrendingKeywords(feedID, since, until, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(trending)
+}
+
+// HandleExtractKeywords extracts the keywords of articles that have none yet.
+// @Summary      Extract missing keywords
+// @Description  Extract the key phrases of up to 500 of the newest articles without keywords, such as articles saved before extraction existed
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]int  "Number of articles given keywords (extracted)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /keywords/extract [post]
+func HandleExtractKeywords(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	extracted, err := keywords.NewService(h.DB).ExtractMissing(extractBatchSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"extracted": extracted})
+}
+
+// boundedInt parses a positive query value, falling back to fallback and capped at limit
+func boundedInt(value string, fallback, limit int) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	if n > limit {
+		return limit
+	}
+	return n
+}
@@ -0,0 +1,143 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/semantic"
+)
+
+const (
+	defaultSemanticLimit = 20
+	maxSemanticLimit     = 100
+)
+
+// HandleSemanticSearch finds articles whose meaning matches a free-text query.
+// @Summary      Semantic article search
+// @Description  Rank embedded articles by cosine similarity to the query (requires ai_embedding_enabled setting)
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        q      query     string  true   "Search query"
+// @Param        limit  query     int     false  "Maximum number of results (default 20, max 100)"
+// @Success      200  {array}   semantic.Match  "Matching articles with their similarity score"
+// @Failure      400  {object}  map[string]string  "Bad request (missing query)"
+// @Failure      403  {object}  map[string]string  "Semantic search disabled"
+// @Failure      429  {object}  map[string]string  "AI usage limit reached"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/semantic-search [get]
+func HandleSemanticSearch(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Missing query", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := h.RequestContext(r, 90*time.Second)
+	defer cancel()
+
+	matches, err := semantic.NewService(h.DB, h.AITracker).Search(ctx, query, semanticLimit(r, defaultSemanticLimit))
+	if err != nil {
+		writeSemanticError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
+
+// HandleSimilarArticles finds articles related to an article ("more like this").
+// @Summary      Similar articles
+// @Description  Rank embedded articles by cosine similarity to an article's embedding (requires ai_embedding_enabled setting)
+// @Tags         articles
+// @Accept       json
+// @Produce      json
+// @Param        id     query     int64  true   "Article ID"
+// @Param        limit  query     int    false  "Maximum number of results (default 10, max 100)"
+// @Success      200  {array}   semantic.Match  "Similar articles with their similarity score (empty if the article is not embedded yet)"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid article ID)"
+// @Failure      403  {object}  map[string]string  "Semantic search disabled"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/similar [get]
+func HandleSimilarArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	matches, err := semantic.NewService(h.DB, h.AITracker).Similar(articleID, semanticLimit(r, 10))
+	if err != nil {
+		writeSemanticError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
+
+// HandleSemanticStatus returns the state of the article embedding index.
+// @Summary      Semantic index status
+// @Description  Whether embeddings are enabled, the embedding model and how many articles are indexed
+// @Tags         articles
+// @Produce      json
+// @Success      200  {object}  semantic.Status  "Index status"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/semantic-status [get]
+func HandleSemanticStatus(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := semantic.NewService(h.DB, h.AITracker).Status()
+	if err != nil {
+		log.Printf("Error getting semantic index status: %v", err)
+		http.Error(w, "Failed to get index status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// semanticLimit parses the limit query parameter, clamped to maxSemanticLimit
+func semanticLimit(r *http.Request, fallback int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	if limit > maxSemanticLimit {
+		return maxSemanticLimit
+	}
+	return limit
+}
+
+// writeSemanticError maps semantic search errors to HTTP responses
+func writeSemanticError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, semantic.ErrDisabled):
+		http.Error(w, "Semantic search is disabled", http.StatusForbidden)
+	case errors.Is(err, aiprofile.ErrLimitReached):
+		http.Error(w, "AI usage limit reached", http.StatusTooManyRequests)
+	default:
+		log.Printf("Semantic search failed: %v", err)
+		http.Error(w, "Semantic search failed", http.StatusInternalServerError)
+	}
+}
@@ -1,13 +1,9 @@
 package article
 
 import (
-	"context"
-	"log"
 	"net/http"
 	"strconv"
 
-	"MrRSS/internal/database"
-	"MrRSS/internal/freshrss"
 	"MrRSS/internal/handlers/core"
 )
 
@@ -43,7 +39,7 @@ func HandleMarkReadWithImmediateSync(h *core.Handler, w http.ResponseWriter, r *
 
 	// Immediately sync to FreshRSS if needed
 	if syncReq != nil {
-		go performImmediateSync(h, syncReq)
+		go h.SyncArticleStatus(syncReq)
 	}
 }
 
@@ -72,36 +68,6 @@ func HandleToggleFavoriteWithImmediateSync(h *core.Handler, w http.ResponseWrite
 
 	// Immediately sync to FreshRSS if needed
 	if syncReq != nil {
-		go performImmediateSync(h, syncReq)
-	}
-}
-
-// performImmediateSync performs an immediate sync to FreshRSS in a background goroutine
-func performImmediateSync(h *core.Handler, syncReq *database.SyncRequest) {
-	// Check if FreshRSS is enabled and configured
-	enabled, _ := h.DB.GetSetting("freshrss_enabled")
-	if enabled != "true" {
-		return
-	}
-
-	serverURL, username, password, err := h.DB.GetFreshRSSConfig()
-	if err != nil || serverURL == "" || username == "" || password == "" {
-		log.Printf("[Immediate Sync] FreshRSS not configured, skipping sync")
-		return
-	}
-
-	// Create sync service
-	syncService := freshrss.NewBidirectionalSyncService(serverURL, username, password, h.DB)
-
-	// Perform immediate sync
-	ctx := context.Background()
-	err = syncService.SyncArticleStatus(ctx, syncReq.ArticleID, syncReq.ArticleURL, syncReq.Action)
-	if err != nil {
-		log.Printf("[Immediate Sync] Failed for article %d: %v", syncReq.ArticleID, err)
-		// Enqueue for retry during next global sync
-		_ = h.DB.EnqueueSyncChange(syncReq.ArticleID, syncReq.ArticleURL, syncReq.Action)
-		log.Printf("[Immediate Sync] Enqueued article %d for retry", syncReq.ArticleID)
-	} else {
-		log.Printf("[Immediate Sync] Success for article %d: %s", syncReq.ArticleID, syncReq.Action)
+		go h.SyncArticleStatus(syncReq)
 	}
 }
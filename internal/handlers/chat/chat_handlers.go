@@ -1,38 +1,87 @@
 package chat
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/semantic"
 	"MrRSS/internal/utils"
 )
 
+const (
+	// chatRequestTimeout bounds a non-streamed chat answer
+	chatRequestTimeout = 2 * time.Minute
+	// chatStreamTimeout bounds a streamed chat answer
+	chatStreamTimeout = 5 * time.Minute
+)
+
 // ChatMessage represents a message in the chat conversation
 type ChatMessage struct {
 	Role    string `json:"role"` // "system", "user", or "assistant"
 	Content string `json:"content"`
 }
 
-// ChatRequest represents the incoming chat request
+// ChatRequest represents the incoming chat request.
+// A chat is about one article (ArticleTitle, ArticleURL, ArticleContent) unless it attaches
+// articles, feeds or categories or retrieves archive articles; the scope of an archive
+// session, if SessionID refers to one, takes precedence over the scope fields.
 type ChatRequest struct {
 	Messages       []ChatMessage `json:"messages"`
 	ArticleTitle   string        `json:"article_title,omitempty"`
 	ArticleURL     string        `json:"article_url,omitempty"`
 	ArticleContent string        `json:"article_content,omitempty"`
 	IsFirstMessage bool          `json:"is_first_message,omitempty"`
+	SessionID      int64         `json:"session_id,omitempty"` // Session the question and answer are saved to
+	ArticleIDs     []int64       `json:"article_ids,omitempty"`
+	FeedIDs        []int64       `json:"feed_ids,omitempty"`
+	Categories     []string      `json:"categories,omitempty"`
+	Topics         []string      `json:"topics,omitempty"`
+	Entities       []string      `json:"entities,omitempty"`
+	Retrieval      string        `json:"retrieval,omitempty"`      // "", "keyword", "semantic" or "auto"
+	RetrievalDays  int           `json:"retrieval_days,omitempty"` // Only use articles published in the last N days, 0 for all
 }
 
 // ChatResponse represents the response from the AI chat
 type ChatResponse struct {
-	Response string `json:"response"`
-	HTML     string `json:"html,omitempty"` // Rendered HTML version of markdown response
+	Response  string       `json:"response"`
+	HTML      string       `json:"html,omitempty"` // Rendered HTML version of markdown response
+	Citations []Citation   `json:"citations,omitempty"`
+	SessionID int64        `json:"session_id,omitempty"`
+	Actions   []ChatAction `json:"actions,omitempty"` // Tools called while answering
+}
+
+// sources returns the chat sources described by the request's scope fields
+func (req ChatRequest) sources() []database.ChatSource {
+	var sources []database.ChatSource
+	for _, id := range req.ArticleIDs {
+		sources = append(sources, database.ChatSource{Type: database.ChatSourceArticle, Value: strconv.FormatInt(id, 10)})
+	}
+	for _, id := range req.FeedIDs {
+		sources = append(sources, database.ChatSource{Type: database.ChatSourceFeed, Value: strconv.FormatInt(id, 10)})
+	}
+	for _, category := range req.Categories {
+		sources = append(sources, database.ChatSource{Type: database.ChatSourceCategory, Value: category})
+	}
+	for _, topic := range req.Topics {
+		sources = append(sources, database.ChatSource{Type: database.ChatSourceTopic, Value: topic})
+	}
+	for _, entity := range req.Entities {
+		sources = append(sources, database.ChatSource{Type: database.ChatSourceEntity, Value: entity})
+	}
+	return sources
 }
 
 // HandleAIChat handles chat requests for article discussions
@@ -72,7 +121,7 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if AI usage limit is reached
-	if h.AITracker.IsLimitReached() {
+	if h.AITracker.IsFeatureLimitReached(aiprofile.TaskChat) {
 		log.Printf("AI usage limit reached for chat")
 		json.NewEncoder(w).Encode(map[string]string{
 			"error": "AI usage limit reached",
@@ -83,50 +132,37 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	// Apply rate limiting for AI requests
 	h.AITracker.WaitForRateLimit()
 
-	// Get AI settings
-	apiKey, _ := h.DB.GetEncryptedSetting("ai_api_key")
-	endpoint, _ := h.DB.GetSetting("ai_endpoint")
-	model, _ := h.DB.GetSetting("ai_model")
+	// Send the chat request to the profiles routed to chat; it is cancelled if the client goes away
+	ctx, cancel := h.RequestContext(r, chatRequestTimeout)
+	defer cancel()
+	ctx, collector := ai.WithUsageCollector(ctx)
 
-	if endpoint == "" {
-		endpoint = "https://api.openai.com/v1/chat/completions"
-	}
-	if model == "" {
-		model = "gpt-4o-mini"
+	turn, err := buildChatMessages(ctx, h, req)
+	if err != nil {
+		writeChatScopeError(w, err)
+		return
 	}
 
-	// Optimize context to reduce token usage
-	optimizedMessages := optimizeChatContext(req.Messages, req.ArticleTitle, req.ArticleURL, req.ArticleContent, req.IsFirstMessage)
-
-	// Convert messages to map format
-	messagesMap := make([]map[string]string, len(optimizedMessages))
-	for i, msg := range optimizedMessages {
-		messagesMap[i] = map[string]string{
-			"role":    msg.Role,
-			"content": msg.Content,
+	var result ai.ResponseResult
+	profile, err := aiprofile.Run(ctx, h.DB, aiprofile.TaskChat, func(ctx context.Context, profile database.AIProfile) error {
+		var err error
+		client := newChatClient(h, profile)
+		if turn.tools != nil {
+			result, err = answerWithTools(ctx, h, client, turn, nil)
+			return err
 		}
+		result, err = client.RequestWithMessagesContext(ctx, turn.messagesMap)
+		return err
+	})
+	if r.Context().Err() != nil {
+		// The client went away, nobody is waiting for the answer
+		return
 	}
-
-	// Create HTTP client with proxy support if configured
-	httpClient, err := createHTTPClientWithProxy(h)
-	if err != nil {
-		log.Printf("Failed to create HTTP client with proxy: %v", err)
-		httpClient = &http.Client{Timeout: 60 * time.Second}
-	} else {
-		httpClient.Timeout = 60 * time.Second
-	}
-
-	// Create AI client
-	clientConfig := ai.ClientConfig{
-		APIKey:   apiKey,
-		Endpoint: endpoint,
-		Model:    model,
-		Timeout:  60 * time.Second,
+	if errors.Is(err, aiprofile.ErrLimitReached) {
+		log.Printf("AI usage limit reached for chat")
+		json.NewEncoder(w).Encode(map[string]string{"error": "AI usage limit reached"})
+		return
 	}
-	client := ai.NewClientWithHTTPClient(clientConfig, httpClient)
-
-	// Send chat request using universal client
-	result, err := client.RequestWithMessages(messagesMap)
 	if err != nil {
 		log.Printf("AI chat request failed: %v", err)
 		w.Header().Set("Content-Type", "application/json")
@@ -148,17 +184,246 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		log.Printf("AI chat thinking: %s", thinking)
 	}
 
-	// Track AI usage (estimate tokens from input and output)
-	estimatedTokens := estimateChatTokens(optimizedMessages, response)
-	if err := h.AITracker.AddUsage(int64(estimatedTokens)); err != nil {
-		log.Printf("Warning: failed to track AI usage: %v", err)
+	trackChatUsage(h, profile, collector, turn.messages, response)
+
+	citations := extractCitations(response, turn.articles)
+	saveChatTurn(h, req, response, citations)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ChatResponse{
+		Response:  response,
+		HTML:      htmlResponse,
+		Citations: citations,
+		SessionID: req.SessionID,
+		Actions:   turn.actions,
+	})
+}
+
+// HandleAIChatStream handles chat requests like HandleAIChat, streaming the answer as
+// Server-Sent Events: "delta" events carry content and thinking deltas, "tool" events report
+// tools called while answering, a final "done" event carries the full response and its HTML,
+// and "error" events report failures.
+// @Summary      AI chat with article (streaming)
+// @Description  Send messages to AI and stream the answer as Server-Sent Events (delta, tool, done, error)
+// @Tags         chat
+// @Accept       json
+// @Produce      text/event-stream
+// @Param        request  body      chat.ChatRequest  true  "Chat request (messages, article info)"
+// @Success      200  {string}  string  "Event stream"
+// @Failure      400  {object}  map[string]string  "Bad request (missing messages)"
+// @Failure      403  {object}  map[string]string  "AI chat is disabled"
+// @Failure      429  {object}  map[string]string  "AI usage limit reached"
+// @Router       /ai-chat/stream [post]
+func HandleAIChatStream(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
 
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Messages) == 0 {
+		http.Error(w, "Missing messages", http.StatusBadRequest)
+		return
+	}
+
+	chatEnabled, _ := h.DB.GetSetting("ai_chat_enabled")
+	if chatEnabled != "true" {
+		http.Error(w, "AI chat is disabled", http.StatusForbidden)
+		return
+	}
+
+	if h.AITracker.IsFeatureLimitReached(aiprofile.TaskChat) {
+		log.Printf("AI usage limit reached for chat")
+		http.Error(w, "AI usage limit reached", http.StatusTooManyRequests)
+		return
+	}
+
+	h.AITracker.WaitForRateLimit()
+
+	ctx, cancel := h.RequestContext(r, chatStreamTimeout)
+	defer cancel()
+	ctx, collector := ai.WithUsageCollector(ctx)
+
+	turn, err := buildChatMessages(ctx, h, req)
+	if err != nil {
+		writeChatScopeError(w, err)
+		return
+	}
+
+	events := core.NewSSEWriter(w)
+	var result ai.ResponseResult
+	profile, err := aiprofile.Run(ctx, h.DB, aiprofile.TaskChat, func(ctx context.Context, profile database.AIProfile) error {
+		client := newChatClient(h, profile)
+		if turn.tools != nil {
+			// Tool calls are not streamed: tool events report progress, the answer comes as one delta
+			var err error
+			result, err = answerWithTools(ctx, h, client, turn, func(action ChatAction) error {
+				return events.Send("tool", action)
+			})
+			if err != nil {
+				return err
+			}
+			return events.Send("delta", ai.StreamChunk{Content: result.Content, Thinking: result.Thinking})
+		}
+
+		delivered := false
+		var err error
+		result, err = client.StreamWithMessages(ctx, turn.messagesMap, func(chunk ai.StreamChunk) error {
+			if chunk.Done {
+				return nil
+			}
+			delivered = true
+			return events.Send("delta", chunk)
+		})
+		if err != nil && delivered {
+			// Part of the answer was already sent, another profile can't continue it
+			return aiprofile.Final(err)
+		}
+		return err
+	})
+	if errors.Is(err, aiprofile.ErrLimitReached) {
+		log.Printf("AI usage limit reached for chat")
+		events.Send("error", map[string]string{"error": "AI usage limit reached"})
+		return
+	}
+	if err != nil {
+		log.Printf("AI chat stream failed: %v", err)
+		events.Send("error", map[string]string{"error": "No response from AI"})
+		return
+	}
+
+	trackChatUsage(h, profile, collector, turn.messages, result.Content)
+
+	citations := extractCitations(result.Content, turn.articles)
+	saveChatTurn(h, req, result.Content, citations)
+
+	events.Send("done", ChatResponse{
+		Response:  result.Content,
+		HTML:      utils.ConvertMarkdownToHTML(result.Content),
+		Citations: citations,
+		SessionID: req.SessionID,
+		Actions:   turn.actions,
+	})
+}
+
+// chatTurn is the context sent to the AI for one question
+type chatTurn struct {
+	messages    []ChatMessage
+	messagesMap []map[string]string
+	articles    []models.Article // Archive articles in context or seen through tools, which the answer may cite
+	tools       []ai.Tool        // Offered when ai_chat_tools_enabled is set
+	actions     []ChatAction     // Tools called while answering
+}
+
+// buildChatMessages optimizes the chat context and converts it to the AI client's message format.
+// Archive chats get the articles attached to or retrieved for the latest question in a system
+// message, rebuilt for every question; single-article chats get the article on the first message.
+// The chat toolset is offered when enabled.
+func buildChatMessages(ctx context.Context, h *core.Handler, req ChatRequest) (*chatTurn, error) {
+	scope, err := resolveChatScope(h, req)
+	if err != nil {
+		return nil, err
+	}
+
+	turn := &chatTurn{}
+	if scope.isArchive() {
+		turn.articles, err = gatherArticles(ctx, h, scope, lastUserMessage(req.Messages))
+		if err != nil {
+			return nil, err
+		}
+		systemMsg := ChatMessage{Role: "system", Content: buildArchiveContext(h, turn.articles)}
+		turn.messages = append([]ChatMessage{systemMsg}, optimizeChatContext(req.Messages, "", "", "", false)...)
+	} else {
+		// Optimize context to reduce token usage
+		turn.messages = optimizeChatContext(req.Messages, req.ArticleTitle, req.ArticleURL, req.ArticleContent, req.IsFirstMessage)
+	}
+	if chatToolsEnabled(h) {
+		turn.withTools()
+	}
+
+	// Convert messages to map format
+	turn.messagesMap = make([]map[string]string, len(turn.messages))
+	for i, msg := range turn.messages {
+		turn.messagesMap[i] = map[string]string{
+			"role":    msg.Role,
+			"content": msg.Content,
+		}
+	}
+	return turn, nil
+}
+
+// writeChatScopeError maps errors from building an archive chat's context to HTTP responses
+func writeChatScopeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errInvalidScope):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, semantic.ErrDisabled):
+		http.Error(w, "Semantic search is disabled", http.StatusForbidden)
+	case errors.Is(err, aiprofile.ErrLimitReached):
+		http.Error(w, "AI usage limit reached", http.StatusTooManyRequests)
+	default:
+		log.Printf("Failed to gather chat articles: %v", err)
+		http.Error(w, "Failed to gather articles", http.StatusInternalServerError)
+	}
+}
+
+// lastUserMessage returns the content of the latest user message
+func lastUserMessage(messages []ChatMessage) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Content
+		}
+	}
+	return ""
+}
+
+// saveChatTurn saves the latest question and its answer to the request's session, if any
+func saveChatTurn(h *core.Handler, req ChatRequest, response string, citations []Citation) {
+	if req.SessionID <= 0 {
+		return
+	}
+	if question := lastUserMessage(req.Messages); question != "" {
+		if _, err := h.DB.CreateChatMessage(req.SessionID, "user", question, ""); err != nil {
+			log.Printf("Failed to save chat message: %v", err)
+			return
+		}
+	}
+	if _, err := h.DB.CreateChatMessageWithCitations(req.SessionID, "assistant", response, "", citationIDs(citations)); err != nil {
+		log.Printf("Failed to save chat message: %v", err)
+	}
+}
+
+// newChatClient creates an AI client for a profile
+func newChatClient(h *core.Handler, profile database.AIProfile) *ai.Client {
+	// Create HTTP client with proxy support if configured
+	httpClient, err := createHTTPClientWithProxy(h)
+	if err != nil {
+		log.Printf("Failed to create HTTP client with proxy: %v", err)
+		httpClient = &http.Client{Timeout: 60 * time.Second}
+	} else {
+		httpClient.Timeout = 60 * time.Second
+	}
+
+	return ai.NewClientWithHTTPClient(aiprofile.ClientConfig(profile, 60*time.Second), httpClient)
+}
+
+// trackChatUsage records AI usage and statistics for a chat answer.
+// Token counts reported by the provider are used, with an estimate from input and output as fallback.
+func trackChatUsage(h *core.Handler, profile database.AIProfile, collector *ai.UsageCollector, messages []ChatMessage, response string) {
+	estimate := ai.TokenUsage{
+		InputTokens:  int64(estimateChatTokens(messages, "")),
+		OutputTokens: int64(estimateChatTokens(nil, response)),
+	}
+	tokens := h.AITracker.RecordCalls(aiprofile.TaskChat, profile.Name, collector.Calls(), estimate)
+	aiprofile.RecordUsage(h.DB, profile, tokens)
+
 	// Track statistics
 	_ = h.DB.IncrementStat("ai_chat")
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(ChatResponse{Response: response, HTML: htmlResponse})
 }
 
 // optimizeChatContext reduces the chat context to save tokens while preserving important information
@@ -0,0 +1,254 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/database"
+	ff "MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
+)
+
+func setupHandler(t *testing.T, aiURL string) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	db.SetSetting("ai_chat_enabled", "true")
+	db.SetSetting("ai_endpoint", aiURL)
+	db.SetSetting("ai_model", "llama3")
+	return core.NewHandler(db, ff.NewFetcher(db), nil)
+}
+
+func addArticle(t *testing.T, db *database.DB, feedID int64, title, summary string, published time.Time) int64 {
+	t.Helper()
+	if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: title, Summary: summary, URL: "https://example.com/" + title, PublishedAt: published}); err != nil {
+		t.Fatalf("SaveArticle error: %v", err)
+	}
+	var id int64
+	if err := db.QueryRow(`SELECT id FROM articles WHERE title = ?`, title).Scan(&id); err != nil {
+		t.Fatalf("article lookup error: %v", err)
+	}
+	return id
+}
+
+func TestHandleAIChat_ArchiveSession(t *testing.T) {
+	var prompt string
+	var answer string
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompt = string(body)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": map[string]string{"role": "assistant", "content": answer},
+			"done":    true,
+		})
+	}))
+	defer aiServer.Close()
+
+	h := setupHandler(t, aiServer.URL)
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "Energy", URL: "https://energy.example.com/feed", Category: "Science"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	battery := addArticle(t, h.DB, feedID, "Battery breakthrough", "Solid state battery cells", time.Now())
+	addArticle(t, h.DB, feedID, "Wind farm opens", "Offshore turbines", time.Now())
+	addArticle(t, h.DB, feedID, "Old battery news", "Lead acid", time.Now().AddDate(0, -2, 0))
+
+	// Create an archive session over the category with keyword retrieval in the last month
+	body, _ := json.Marshal(CreateSessionRequest{
+		Title:         "Energy",
+		Sources:       []database.ChatSource{{Type: database.ChatSourceCategory, Value: "Science"}},
+		Retrieval:     RetrievalKeyword,
+		RetrievalDays: 30,
+	})
+	rr := httptest.NewRecorder()
+	HandleCreateSession(h, rr, httptest.NewRequest(http.MethodPost, "/ai/chat/session/create", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("create session: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var session database.ChatSession
+	json.NewDecoder(rr.Body).Decode(&session)
+
+	answer = fmt.Sprintf("Solid state cells were announced [#%d]. See also [#999].", battery)
+	body, _ = json.Marshal(ChatRequest{
+		SessionID: session.ID,
+		Messages:  []ChatMessage{{Role: "user", Content: "What did my feeds say about batteries?"}},
+	})
+	rr = httptest.NewRecorder()
+	HandleAIChat(h, rr, httptest.NewRequest(http.MethodPost, "/ai-chat", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("chat: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp ChatResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+
+	if !strings.Contains(prompt, fmt.Sprintf("[#%d] Battery breakthrough", battery)) {
+		t.Errorf("expected the retrieved article in the prompt, got %s", prompt)
+	}
+	if strings.Contains(prompt, "Wind farm") || strings.Contains(prompt, "Old battery news") {
+		t.Errorf("expected unrelated and old articles to be left out, got %s", prompt)
+	}
+	if len(resp.Citations) != 1 || resp.Citations[0].ArticleID != battery || resp.Citations[0].FeedTitle != "Energy" {
+		t.Errorf("expected one citation of the battery article, got %+v", resp.Citations)
+	}
+	if resp.SessionID != session.ID {
+		t.Errorf("expected session %d, got %d", session.ID, resp.SessionID)
+	}
+
+	messages, err := h.DB.GetChatMessages(session.ID)
+	if err != nil || len(messages) != 2 {
+		t.Fatalf("expected the question and answer to be saved, got %+v (%v)", messages, err)
+	}
+	if len(messages[1].Citations) != 1 || messages[1].Citations[0] != battery {
+		t.Errorf("expected the answer's citations to be saved, got %+v", messages[1])
+	}
+}
+
+func TestHandleCreateSession_InvalidScope(t *testing.T) {
+	h := setupHandler(t, "http://localhost:11434")
+
+	for name, req := range map[string]CreateSessionRequest{
+		"no article or scope": {Title: "Chat"},
+		"unknown retrieval":   {Retrieval: "magic"},
+		"unknown source":      {Sources: []database.ChatSource{{Type: "tag", Value: "go"}}},
+		"invalid feed ID":     {Sources: []database.ChatSource{{Type: database.ChatSourceFeed, Value: "abc"}}},
+	} {
+		body, _ := json.Marshal(req)
+		rr := httptest.NewRecorder()
+		HandleCreateSession(h, rr, httptest.NewRequest(http.MethodPost, "/ai/chat/session/create", bytes.NewReader(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 got %d", name, rr.Code)
+		}
+	}
+}
+
+func TestExtractCitations(t *testing.T) {
+	articles := []models.Article{{ID: 3, Title: "Three"}, {ID: 7, Title: "Seven"}, {ID: 12, Title: "Twelve"}}
+
+	citations := extractCitations("First [#7, #3], again [#7], unknown [#5], plain #12 and [#12]", articles)
+	if len(citations) != 3 || citations[0].ArticleID != 7 || citations[1].ArticleID != 3 || citations[2].ArticleID != 12 {
+		t.Errorf("unexpected citations: %+v", citations)
+	}
+	if citations := extractCitations("No references here", articles); len(citations) != 0 {
+		t.Errorf("expected no citations, got %+v", citations)
+	}
+}
+
+func TestQuestionTerms(t *testing.T) {
+	terms := questionTerms("What did the feeds say about batteries and chips this week?")
+	want := []string{"batteries", "battery", "chips", "chip"}
+	if strings.Join(terms, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, terms)
+	}
+}
+
+func TestHandleAIChat_Tools(t *testing.T) {
+	var requests []string
+	var replies []string
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		fmt.Fprint(w, replies[len(requests)-1])
+	}))
+	defer aiServer.Close()
+
+	h := setupHandler(t, aiServer.URL)
+	h.DB.SetSetting("ai_chat_tools_enabled", "true")
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "Cloud", URL: "https://cloud.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	k8s := addArticle(t, h.DB, feedID, "Kubernetes 2.0 released", "The container orchestrator", time.Now().Add(-20*time.Hour))
+	addArticle(t, h.DB, feedID, "Rust in the kernel", "Memory safety", time.Now())
+
+	replies = []string{
+		`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"search_articles","arguments":{"query":"kubernetes","days":2}}}]},"done":true}`,
+		fmt.Sprintf(`{"message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"add_to_read_later","arguments":{"article_ids":[%d]}}}]},"done":true}`, k8s),
+		fmt.Sprintf(`{"message":{"role":"assistant","content":"Added [#%d] to read later."},"done":true}`, k8s),
+	}
+
+	body, _ := json.Marshal(ChatRequest{Messages: []ChatMessage{{Role: "user", Content: "Put yesterday's Kubernetes posts in read later"}}})
+	rr := httptest.NewRecorder()
+	HandleAIChat(h, rr, httptest.NewRequest(http.MethodPost, "/ai-chat", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("chat: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp ChatResponse
+	json.NewDecoder(rr.Body).Decode(&resp)
+
+	if len(requests) != 3 {
+		t.Fatalf("expected three AI requests, got %d", len(requests))
+	}
+	if !strings.Contains(requests[0], `"name":"create_rule"`) {
+		t.Errorf("expected the toolset to be offered, got %s", requests[0])
+	}
+	if !strings.Contains(requests[1], `"role":"tool"`) || !strings.Contains(requests[1], "Kubernetes 2.0 released") || strings.Contains(requests[1], "Rust in the kernel") {
+		t.Errorf("expected the search result to be sent back, got %s", requests[1])
+	}
+
+	article, _ := h.DB.GetArticleByID(k8s)
+	if !article.IsReadLater {
+		t.Errorf("expected the article to be added to read later")
+	}
+	if len(resp.Actions) != 2 || resp.Actions[1].Tool != "add_to_read_later" || resp.Actions[1].Error != "" {
+		t.Errorf("unexpected actions: %+v", resp.Actions)
+	}
+	if len(resp.Citations) != 1 || resp.Citations[0].ArticleID != k8s {
+		t.Errorf("expected the found article to be citable, got %+v", resp.Citations)
+	}
+}
+
+func TestRunTool_UpdateMissingArticles(t *testing.T) {
+	h := setupHandler(t, "")
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "Cloud", URL: "https://cloud.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	id := addArticle(t, h.DB, feedID, "Kubernetes 2.0 released", "The container orchestrator", time.Now())
+
+	for _, tool := range []string{"mark_read", "set_favorite", "add_to_read_later"} {
+		call := ai.ToolCall{Name: tool, Arguments: json.RawMessage(fmt.Sprintf(`{"article_ids":[%d,9999]}`, id))}
+		result, err := runTool(context.Background(), h, &chatTurn{}, call)
+		if err != nil {
+			t.Fatalf("%s error: %v", tool, err)
+		}
+		if !strings.Contains(result, `"updated":1`) || !strings.Contains(result, `"failed":[9999]`) {
+			t.Errorf("%s: expected the missing article to fail, got %s", tool, result)
+		}
+	}
+}
+
+func TestRunTool_CreateRuleDisabled(t *testing.T) {
+	h := setupHandler(t, "")
+	call := ai.ToolCall{Name: "create_rule", Arguments: json.RawMessage(`{"name":"Hide sports","conditions":[{"field":"topic","value":"Sports"}],"actions":["mark_read"]}`)}
+	result, err := runTool(context.Background(), h, &chatTurn{}, call)
+	if err != nil {
+		t.Fatalf("create_rule error: %v", err)
+	}
+	if !strings.Contains(result, `"enabled":false`) {
+		t.Errorf("expected the result to report the rule as disabled, got %s", result)
+	}
+
+	var saved []rules.Rule
+	rulesJSON, _ := h.DB.GetSetting("rules")
+	json.Unmarshal([]byte(rulesJSON), &saved)
+	if len(saved) != 1 || saved[0].Enabled {
+		t.Errorf("expected one disabled rule, got %+v", saved)
+	}
+}
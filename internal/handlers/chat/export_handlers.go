@@ -0,0 +1,292 @@
+package chat
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/utils"
+)
+
+// ExportOptions selects what a chat session export includes besides the messages
+type ExportOptions struct {
+	IncludeArticle  bool `json:"include_article"`  // Title, link, feed and publication date of the article, or the sources of an archive session
+	IncludeThinking bool `json:"include_thinking"` // Thinking of the assistant answers
+}
+
+// ExportToObsidianRequest represents the request to export a chat session to Obsidian
+type ExportToObsidianRequest struct {
+	SessionID int64 `json:"session_id"`
+	ExportOptions
+}
+
+// HandleSearchMessages searches the messages of every chat session.
+// @Summary      Search chat messages
+// @Description  Find the chat messages containing every word of a query, across all sessions, newest first
+// @Tags         chat
+// @Produce      json
+// @Param        q      query     string  true   "Search query"
+// @Param        limit  query     int     false  "Maximum number of messages (default 50)"
+// @Success      200  {array}   database.ChatMessageMatch  "Matching messages with their session"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/chat/search [get]
+func HandleSearchMessages(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	matches, err := h.DB.SearchChatMessages(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to search messages: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
+
+// HandlePinSession pins or unpins a chat session.
+// @Summary      Pin chat session
+// @Description  Pin a chat session so that it is kept when its article is cleaned up, or unpin it
+// @Tags         chat
+// @Produce      json
+// @Param        session_id  query     int64  true   "Session ID"
+// @Param        pinned      query     bool   false  "false to unpin (default true)"
+// @Success      200  {object}  database.ChatSession  "Updated chat session"
+// @Failure      400  {object}  map[string]string  "Bad request (missing or invalid session_id)"
+// @Failure      404  {object}  map[string]string  "Session not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/chat/session/pin [post]
+func HandlePinSession(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(r.URL.Query().Get("session_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session_id", http.StatusBadRequest)
+		return
+	}
+	pinned := r.URL.Query().Get("pinned") != "false"
+
+	if err := h.DB.SetChatSessionPinned(sessionID, pinned); err == sql.ErrNoRows {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to pin session: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	session, err := h.DB.GetChatSession(sessionID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to get session: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// HandleExportSession downloads a chat session as Markdown.
+// @Summary      Export chat session to Markdown
+// @Description  Download a chat session as a Markdown file, optionally with the article metadata and the assistant's thinking
+// @Tags         chat
+// @Produce      text/markdown
+// @Param        session_id        query  int64  true   "Session ID"
+// @Param        include_article   query  bool   false  "Include the article metadata"
+// @Param        include_thinking  query  bool   false  "Include the assistant's thinking"
+// @Success      200  {string}  string  "Markdown document"
+// @Failure      400  {object}  map[string]string  "Bad request (missing or invalid session_id)"
+// @Failure      404  {object}  map[string]string  "Session not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/chat/session/export [get]
+func HandleExportSession(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(r.URL.Query().Get("session_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session_id", http.StatusBadRequest)
+		return
+	}
+	opts := ExportOptions{
+		IncludeArticle:  r.URL.Query().Get("include_article") == "true",
+		IncludeThinking: r.URL.Query().Get("include_thinking") == "true",
+	}
+
+	session, markdown, status, err := exportSession(h, sessionID, opts)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	filename := utils.SanitizeFilename(session.Title)
+	if filename == "" {
+		filename = fmt.Sprintf("Chat_%d", session.ID)
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".md"))
+	w.Write([]byte(markdown))
+}
+
+// HandleExportSessionToObsidian exports a chat session to the Obsidian vault.
+// @Summary      Export chat session to Obsidian
+// @Description  Write a chat session as a Markdown note to the Obsidian vault (requires obsidian_enabled and obsidian_vault_path settings)
+// @Tags         chat
+// @Accept       json
+// @Produce      json
+// @Param        request  body      chat.ExportToObsidianRequest  true  "Session export request"
+// @Success      200  {object}  map[string]string  "Export result (success, file_path, message)"
+// @Failure      400  {object}  map[string]string  "Bad request (Obsidian not configured or invalid session ID)"
+// @Failure      404  {object}  map[string]string  "Session not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /ai/chat/session/export/obsidian [post]
+func HandleExportSessionToObsidian(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ExportToObsidianRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.SessionID <= 0 {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	vaultPath, err := h.ObsidianVaultPath()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, markdown, status, err := exportSession(h, req.SessionID, req.ExportOptions)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	filename := utils.SanitizeFilename("Chat - " + session.Title)
+	filePath := filepath.Join(vaultPath, filename+".md")
+	if err := os.WriteFile(filePath, []byte(markdown), 0644); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to write file to Obsidian vault: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"success":   "true",
+		"file_path": filePath,
+		"message":   "Chat exported to Obsidian successfully",
+	})
+}
+
+// exportSession renders a chat session as Markdown. On error it returns the HTTP status to reply with.
+func exportSession(h *core.Handler, sessionID int64, opts ExportOptions) (*database.ChatSession, string, int, error) {
+	session, err := h.DB.GetChatSession(sessionID)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("Failed to get session: %v", err)
+	}
+	if session == nil {
+		return nil, "", http.StatusNotFound, errors.New("Session not found")
+	}
+	messages, err := h.DB.GetChatMessages(sessionID)
+	if err != nil {
+		return nil, "", http.StatusInternalServerError, fmt.Errorf("Failed to get messages: %v", err)
+	}
+
+	var meta *articleMetadata
+	if opts.IncludeArticle && session.ArticleID > 0 {
+		// The article may have been cleaned up since a pinned session was created
+		meta = &articleMetadata{Title: session.ArticleTitle, URL: session.ArticleURL}
+		if article, err := h.DB.GetArticleByID(session.ArticleID); err == nil {
+			meta.Title, meta.URL = article.Title, article.URL
+			meta.Feed, meta.Published = article.FeedTitle, article.PublishedAt
+		}
+	}
+	return session, sessionMarkdown(session, messages, meta, opts), http.StatusOK, nil
+}
+
+// articleMetadata is the article a chat session is about, as included in exports
+type articleMetadata struct {
+	Title     string
+	URL       string
+	Feed      string
+	Published time.Time
+}
+
+// sessionMarkdown renders a chat session as a Markdown document with YAML front matter. The
+// article metadata, or the sources of an archive session, are included when opts asks for them.
+func sessionMarkdown(session *database.ChatSession, messages []database.ChatMessage, article *articleMetadata, opts ExportOptions) string {
+	var sb strings.Builder
+
+	sb.WriteString("---\n")
+	sb.WriteString(fmt.Sprintf("title: %s\n", strconv.Quote(session.Title)))
+	if article != nil && article.Title != "" {
+		sb.WriteString(fmt.Sprintf("article: %s\n", strconv.Quote(article.Title)))
+	}
+	sb.WriteString(fmt.Sprintf("created: %q\n", session.CreatedAt.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("updated: %q\n", session.UpdatedAt.Format(time.RFC3339)))
+	sb.WriteString("tags: [rss, chat]\n")
+	sb.WriteString("---\n\n")
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", session.Title))
+
+	if article != nil {
+		if article.URL != "" {
+			sb.WriteString(fmt.Sprintf("**Article:** [%s](%s)  \n", article.Title, article.URL))
+		} else {
+			sb.WriteString(fmt.Sprintf("**Article:** %s  \n", article.Title))
+		}
+		if article.Feed != "" {
+			sb.WriteString(fmt.Sprintf("**Feed:** %s  \n", article.Feed))
+		}
+		if !article.Published.IsZero() {
+			sb.WriteString(fmt.Sprintf("**Published:** %s  \n", article.Published.Format("2006-01-02 15:04")))
+		}
+		sb.WriteString("\n")
+	} else if opts.IncludeArticle && len(session.Sources) > 0 {
+		sb.WriteString("**Sources:**\n\n")
+		for _, source := range session.Sources {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", source.Type, source.Value))
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, msg := range messages {
+		if msg.Role == "user" {
+			sb.WriteString("## You\n\n")
+		} else {
+			sb.WriteString("## Assistant\n\n")
+		}
+		if opts.IncludeThinking && strings.TrimSpace(msg.Thinking) != "" {
+			// An Obsidian callout, shown as a quote by other Markdown renderers
+			sb.WriteString("> [!note]- Thinking\n")
+			for _, line := range strings.Split(strings.TrimSpace(msg.Thinking), "\n") {
+				sb.WriteString("> " + line + "\n")
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString(strings.TrimSpace(msg.Content))
+		sb.WriteString("\n\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
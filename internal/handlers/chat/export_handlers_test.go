@@ -0,0 +1,87 @@
+package chat
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestChatHistory_PinSearchAndExport(t *testing.T) {
+	h := setupHandler(t, "http://127.0.0.1:1")
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "Energy", URL: "https://energy.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	battery := addArticle(t, h.DB, feedID, "Battery breakthrough", "Solid state cells", time.Now())
+	wind := addArticle(t, h.DB, feedID, "Wind farm opens", "Offshore turbines", time.Now())
+
+	pinnedID, _ := h.DB.CreateChatSession(battery, "Batteries")
+	h.DB.CreateChatMessage(pinnedID, "user", "How dense are solid state cells?", "")
+	h.DB.CreateChatMessage(pinnedID, "assistant", "About twice the energy density.", "Comparing with lithium-ion")
+	otherID, _ := h.DB.CreateChatSession(wind, "Wind")
+	h.DB.CreateChatMessage(otherID, "user", "How many turbines?", "")
+
+	rr := httptest.NewRecorder()
+	HandlePinSession(h, rr, httptest.NewRequest(http.MethodPost, "/ai/chat/session/pin?session_id=1", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), `"pinned":true`) {
+		t.Fatalf("pin session: expected the pinned session, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// Search across sessions
+	rr = httptest.NewRecorder()
+	HandleSearchMessages(h, rr, httptest.NewRequest(http.MethodGet, "/ai/chat/search?q=energy+DENSITY", nil))
+	var matches []database.ChatMessageMatch
+	json.NewDecoder(rr.Body).Decode(&matches)
+	if len(matches) != 1 || matches[0].SessionID != pinnedID || matches[0].ArticleTitle != "Battery breakthrough" {
+		t.Fatalf("expected the assistant answer in the battery session, got %+v", matches)
+	}
+
+	// Cleaning up the articles keeps the pinned session only
+	if _, err := h.DB.DeleteAllArticles(); err != nil {
+		t.Fatalf("DeleteAllArticles error: %v", err)
+	}
+	rr = httptest.NewRecorder()
+	HandleListSessions(h, rr, httptest.NewRequest(http.MethodGet, "/ai/chat/sessions?scope=all", nil))
+	var sessions []database.ChatSession
+	json.NewDecoder(rr.Body).Decode(&sessions)
+	if len(sessions) != 1 || sessions[0].ID != pinnedID || sessions[0].MessageCount != 2 {
+		t.Fatalf("expected only the pinned session to be kept, got %+v", sessions)
+	}
+
+	// Export with the article metadata saved with the session and the thinking
+	rr = httptest.NewRecorder()
+	HandleExportSession(h, rr, httptest.NewRequest(http.MethodGet, "/ai/chat/session/export?session_id=1&include_article=true&include_thinking=true", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("export: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	doc := rr.Body.String()
+	for _, want := range []string{
+		"title: \"Batteries\"",
+		"**Article:** [Battery breakthrough](https://example.com/Battery breakthrough)",
+		"## You\n\nHow dense are solid state cells?",
+		"> [!note]- Thinking\n> Comparing with lithium-ion",
+		"## Assistant",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("expected the export to contain %q:\n%s", want, doc)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	HandleExportSession(h, rr, httptest.NewRequest(http.MethodGet, "/ai/chat/session/export?session_id=1", nil))
+	if doc := rr.Body.String(); strings.Contains(doc, "Thinking") || strings.Contains(doc, "**Article:**") {
+		t.Errorf("expected an export without thinking and article metadata:\n%s", doc)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleExportSessionToObsidian(h, rr, httptest.NewRequest(http.MethodPost, "/ai/chat/session/export/obsidian", strings.NewReader(`{"session_id":1}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without Obsidian configured, got %d", rr.Code)
+	}
+}
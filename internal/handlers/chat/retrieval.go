@@ -0,0 +1,337 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/semantic"
+	"MrRSS/internal/summary"
+)
+
+// Archive retrieval modes: how articles relevant to each question are found
+const (
+	RetrievalNone     = ""
+	RetrievalKeyword  = "keyword"
+	RetrievalSemantic = "semantic"
+	RetrievalAuto     = "auto" // Semantic when embeddings are enabled, keyword otherwise
+)
+
+const (
+	// maxContextArticles caps the articles given to the AI for one question
+	maxContextArticles = 12
+	// maxContextChars caps the article text given to the AI for one question
+	maxContextChars = 36000
+	// minArticleChars is the text kept per article however many articles are in context
+	minArticleChars = 1500
+	// semanticCandidates is the number of semantic matches filtered down to the chat's scope
+	semanticCandidates = 60
+	// maxKeywordTerms caps the keywords of a question used for keyword retrieval
+	maxKeywordTerms = 8
+)
+
+// errInvalidScope is returned for chat sources or retrieval modes that don't exist
+var errInvalidScope = errors.New("invalid chat scope")
+
+// questionStopWords are words common in questions about feeds that don't help find articles
+var questionStopWords = map[string]bool{
+	"feed": true, "feeds": true, "article": true, "articles": true, "news": true,
+	"say": true, "said": true, "says": true, "tell": true, "write": true, "wrote": true,
+	"summarize": true, "summary": true, "please": true, "today": true, "yesterday": true,
+	"week": true, "month": true, "year": true, "recent": true, "recently": true, "latest": true,
+	"any": true, "there": true, "anything": true, "think": true, "know": true,
+}
+
+// Citation is an article cited by a chat answer
+type Citation struct {
+	ArticleID int64  `json:"article_id"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	FeedTitle string `json:"feed_title,omitempty"`
+}
+
+// chatScope is what an archive chat draws its context from: attached articles, and the
+// feeds, categories, tags and time window that articles are listed or retrieved from
+type chatScope struct {
+	articleIDs []int64
+	articles   database.ArticleScope
+	retrieval  string
+}
+
+// isArchive reports whether the chat goes beyond the single article of a classic chat
+func (s chatScope) isArchive() bool {
+	return len(s.articleIDs) > 0 || s.articles.HasSources() || s.retrieval != RetrievalNone
+}
+
+// resolveChatScope returns the scope of a chat request: that of its archive session, if any,
+// or the one described by the request itself
+func resolveChatScope(h *core.Handler, req ChatRequest) (chatScope, error) {
+	sources := req.sources()
+	retrieval, days := req.Retrieval, req.RetrievalDays
+
+	if req.SessionID > 0 {
+		session, err := h.DB.GetChatSession(req.SessionID)
+		if err != nil {
+			return chatScope{}, err
+		}
+		if session != nil && session.ArticleID == 0 {
+			sources, retrieval, days = session.Sources, session.Retrieval, session.RetrievalDays
+		}
+	}
+
+	return newChatScope(sources, retrieval, days)
+}
+
+// newChatScope validates chat sources and a retrieval mode and builds the scope they describe
+func newChatScope(sources []database.ChatSource, retrieval string, days int) (chatScope, error) {
+	switch retrieval {
+	case RetrievalNone, RetrievalKeyword, RetrievalSemantic, RetrievalAuto:
+	default:
+		return chatScope{}, fmt.Errorf("%w: unknown retrieval mode %q", errInvalidScope, retrieval)
+	}
+
+	scope := chatScope{retrieval: retrieval}
+	for _, source := range sources {
+		switch source.Type {
+		case database.ChatSourceArticle, database.ChatSourceFeed:
+			id, err := strconv.ParseInt(source.Value, 10, 64)
+			if err != nil || id <= 0 {
+				return chatScope{}, fmt.Errorf("%w: invalid %s ID %q", errInvalidScope, source.Type, source.Value)
+			}
+			if source.Type == database.ChatSourceArticle {
+				scope.articleIDs = append(scope.articleIDs, id)
+			} else {
+				scope.articles.FeedIDs = append(scope.articles.FeedIDs, id)
+			}
+		case database.ChatSourceCategory:
+			if strings.TrimSpace(source.Value) == "" {
+				return chatScope{}, fmt.Errorf("%w: empty category", errInvalidScope)
+			}
+			scope.articles.Categories = append(scope.articles.Categories, source.Value)
+		case database.ChatSourceTopic, database.ChatSourceEntity:
+			value := strings.TrimSpace(source.Value)
+			if value == "" {
+				return chatScope{}, fmt.Errorf("%w: empty %s", errInvalidScope, source.Type)
+			}
+			scope.articles.Tags = append(scope.articles.Tags, database.ScopeTag{Kind: source.Type, Value: value})
+		default:
+			return chatScope{}, fmt.Errorf("%w: unknown source type %q", errInvalidScope, source.Type)
+		}
+	}
+
+	if days > 0 {
+		scope.articles.Since = time.Now().AddDate(0, 0, -days)
+	}
+	return scope, nil
+}
+
+// gatherArticles returns the articles to answer a question with: the attached articles,
+// then the articles retrieved for the question or, without retrieval, the newest articles
+// of the attached feeds, categories and tags
+func gatherArticles(ctx context.Context, h *core.Handler, scope chatScope, question string) ([]models.Article, error) {
+	articles, err := attachedArticles(h, scope.articleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := maxContextArticles - len(articles)
+	if remaining <= 0 {
+		return articles[:maxContextArticles], nil
+	}
+
+	var more []models.Article
+	switch {
+	case scope.retrieval != RetrievalNone:
+		more, err = retrieveArticles(ctx, h, scope, question, remaining)
+	case scope.articles.HasSources():
+		more, err = h.DB.GetRecentArticlesInScope(scope.articles, remaining)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool, len(articles))
+	for _, a := range articles {
+		seen[a.ID] = true
+	}
+	for _, a := range more {
+		if !seen[a.ID] && len(articles) < maxContextArticles {
+			seen[a.ID] = true
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// attachedArticles loads articles by ID, in the order given
+func attachedArticles(h *core.Handler, ids []int64) ([]models.Article, error) {
+	loaded, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]models.Article, len(loaded))
+	for _, a := range loaded {
+		byID[a.ID] = a
+	}
+
+	articles := make([]models.Article, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
+// retrieveArticles finds up to limit articles in scope relevant to the question.
+// Semantic retrieval falls back to keywords when it fails in auto mode.
+func retrieveArticles(ctx context.Context, h *core.Handler, scope chatScope, question string, limit int) ([]models.Article, error) {
+	service := semantic.NewService(h.DB, h.AITracker)
+	useSemantic := scope.retrieval == RetrievalSemantic || (scope.retrieval == RetrievalAuto && service.Enabled())
+
+	if useSemantic {
+		articles, err := semanticArticles(ctx, h, service, scope.articles, question, limit)
+		if err == nil || scope.retrieval == RetrievalSemantic || ctx.Err() != nil {
+			return articles, err
+		}
+		log.Printf("Semantic retrieval failed, falling back to keywords: %v", err)
+	}
+
+	return h.DB.SearchArticlesByKeywords(questionTerms(question), scope.articles, limit)
+}
+
+// questionTerms returns the keywords of a question to search articles for, with the
+// singular of English plurals so that "batteries" finds articles about a "battery"
+func questionTerms(question string) []string {
+	var terms []string
+	count := 0
+	for _, keyword := range summary.Keywords(question) {
+		if questionStopWords[keyword] {
+			continue
+		}
+		if count == maxKeywordTerms {
+			break
+		}
+		count++
+		terms = append(terms, keyword)
+
+		switch {
+		case len(keyword) > 4 && strings.HasSuffix(keyword, "ies"):
+			terms = append(terms, strings.TrimSuffix(keyword, "ies")+"y")
+		case len(keyword) > 3 && strings.HasSuffix(keyword, "s") && !strings.HasSuffix(keyword, "ss"):
+			terms = append(terms, strings.TrimSuffix(keyword, "s"))
+		}
+	}
+	return terms
+}
+
+// semanticArticles returns the semantic matches of the question that lie in scope
+func semanticArticles(ctx context.Context, h *core.Handler, service *semantic.Service, scope database.ArticleScope, question string, limit int) ([]models.Article, error) {
+	matches, err := service.Search(ctx, question, semanticCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	inScope, err := h.DB.GetArticleIDsInScope(ids, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]models.Article, 0, limit)
+	for _, match := range matches {
+		if !inScope[match.ID] {
+			continue
+		}
+		articles = append(articles, match.Article)
+		if len(articles) == limit {
+			break
+		}
+	}
+	return articles, nil
+}
+
+// buildArchiveContext builds the system message that gives the AI the articles to answer from
+func buildArchiveContext(h *core.Handler, articles []models.Article) string {
+	var sb strings.Builder
+	sb.WriteString("You answer questions about articles from the user's RSS feeds. ")
+	if len(articles) == 0 {
+		sb.WriteString("No articles matched the question. Say that the feeds contain nothing about it instead of answering from general knowledge.")
+		return sb.String()
+	}
+	sb.WriteString("Answer using only the articles below. Cite every article you use with its reference in square brackets, e.g. [#12]. ")
+	sb.WriteString("If the articles don't answer the question, say so.\n\nToday is ")
+	sb.WriteString(time.Now().Format("2006-01-02"))
+	sb.WriteString(".\n\n")
+
+	perArticle := maxContextChars / len(articles)
+	if perArticle < minArticleChars {
+		perArticle = minArticleChars
+	}
+
+	for _, a := range articles {
+		content, _, err := h.DB.GetArticleContent(a.ID)
+		if err != nil {
+			log.Printf("Failed to get content of article %d: %v", a.ID, err)
+		}
+		text := []rune(semantic.ArticleText("", a.Summary, content))
+		if len(text) > perArticle {
+			text = append(text[:perArticle], '…')
+		}
+
+		fmt.Fprintf(&sb, "[#%d] %s\n", a.ID, a.Title)
+		fmt.Fprintf(&sb, "Feed: %s | Published: %s | URL: %s\n", a.FeedTitle, a.PublishedAt.Format("2006-01-02"), a.URL)
+		sb.WriteString(string(text))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+var (
+	citationGroupPattern = regexp.MustCompile(`\[(#\d+(?:\s*,\s*#\d+)*)\]`)
+	citationIDPattern    = regexp.MustCompile(`\d+`)
+)
+
+// extractCitations returns the articles an answer cites as [#id] or [#id, #id], in order of
+// first citation; references to articles that were not in context are ignored
+func extractCitations(response string, articles []models.Article) []Citation {
+	byID := make(map[int64]models.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	seen := make(map[int64]bool)
+	var citations []Citation
+	for _, group := range citationGroupPattern.FindAllStringSubmatch(response, -1) {
+		for _, idStr := range citationIDPattern.FindAllString(group[1], -1) {
+			id, _ := strconv.ParseInt(idStr, 10, 64)
+			a, ok := byID[id]
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			citations = append(citations, Citation{ArticleID: a.ID, Title: a.Title, URL: a.URL, FeedTitle: a.FeedTitle})
+		}
+	}
+	return citations
+}
+
+// citationIDs returns the article IDs of citations
+func citationIDs(citations []Citation) []int64 {
+	ids := make([]int64, len(citations))
+	for i, c := range citations {
+		ids[i] = c.ArticleID
+	}
+	return ids
+}
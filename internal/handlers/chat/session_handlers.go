@@ -6,14 +6,20 @@ import (
 	"net/http"
 	"strconv"
 
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
 	"MrRSS/internal/utils"
 )
 
-// CreateSessionRequest represents the request to create a new chat session
+// CreateSessionRequest represents the request to create a new chat session.
+// A session is either about one article (ArticleID) or an archive session over sources
+// and/or retrieved articles.
 type CreateSessionRequest struct {
-	ArticleID int64  `json:"article_id"`
-	Title     string `json:"title"`
+	ArticleID     int64                 `json:"article_id"`
+	Title         string                `json:"title"`
+	Sources       []database.ChatSource `json:"sources,omitempty"`
+	Retrieval     string                `json:"retrieval,omitempty"`      // "", "keyword", "semantic" or "auto"
+	RetrievalDays int                   `json:"retrieval_days,omitempty"` // Only use articles published in the last N days, 0 for all
 }
 
 // UpdateSessionRequest represents the request to update a chat session
@@ -23,11 +29,14 @@ type UpdateSessionRequest struct {
 
 // HandleListSessions handles GET requests to list all chat sessions for an article
 // @Summary      List chat sessions
-// @Description  Get all chat sessions for a specific article
+// @Description  Get all chat sessions for a specific article, the archive sessions with scope=archive, or the sessions of every article and the archive with scope=all
 // @Tags         chat
 // @Accept       json
 // @Produce      json
-// @Param        article_id  query     int64   true  "Article ID"
+// @Param        article_id  query     int64   false  "Article ID (required unless scope=archive or scope=all)"
+// @Param        scope       query     string  false  "archive to list sessions across articles, all to list every session"
+// @Param        limit       query     int     false  "Maximum number of sessions with scope=all (default 50)"
+// @Param        offset      query     int     false  "Number of sessions to skip with scope=all"
 // @Success      200  {array}   database.ChatSession  "List of chat sessions"
 // @Failure      400  {object}  map[string]string  "Bad request (missing or invalid article_id)"
 // @Failure      500  {object}  map[string]string  "Internal server error"
@@ -38,17 +47,40 @@ func HandleListSessions(h *core.Handler, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Get article_id from query parameter
-	articleIDStr := r.URL.Query().Get("article_id")
-	if articleIDStr == "" {
-		http.Error(w, "Missing article_id parameter", http.StatusBadRequest)
+	if r.URL.Query().Get("scope") == "all" {
+		limit, offset := 50, 0
+		if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+			limit = l
+		}
+		if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o > 0 {
+			offset = o
+		}
+		sessions, err := h.DB.GetAllChatSessions(limit, offset)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to get sessions: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sessions)
 		return
 	}
 
-	articleID, err := strconv.ParseInt(articleIDStr, 10, 64)
-	if err != nil {
-		http.Error(w, "Invalid article_id", http.StatusBadRequest)
-		return
+	// Archive sessions are stored without an article
+	var articleID int64
+	if r.URL.Query().Get("scope") != "archive" {
+		// Get article_id from query parameter
+		articleIDStr := r.URL.Query().Get("article_id")
+		if articleIDStr == "" {
+			http.Error(w, "Missing article_id parameter", http.StatusBadRequest)
+			return
+		}
+
+		var err error
+		articleID, err = strconv.ParseInt(articleIDStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid article_id", http.StatusBadRequest)
+			return
+		}
 	}
 
 	sessions, err := h.DB.GetChatSessionsByArticle(articleID)
@@ -63,13 +95,13 @@ func HandleListSessions(h *core.Handler, w http.ResponseWriter, r *http.Request)
 
 // HandleCreateSession handles POST requests to create a new chat session
 // @Summary      Create chat session
-// @Description  Create a new chat session for an article
+// @Description  Create a new chat session for an article, or an archive session over attached articles, feeds and categories and/or retrieved articles
 // @Tags         chat
 // @Accept       json
 // @Produce      json
-// @Param        request  body      chat.CreateSessionRequest  true  "Session creation request (article_id, title)"
+// @Param        request  body      chat.CreateSessionRequest  true  "Session creation request (article_id or sources/retrieval, title)"
 // @Success      200  {object}  database.ChatSession  "Created chat session"
-// @Failure      400  {object}  map[string]string  "Bad request (missing article_id)"
+// @Failure      400  {object}  map[string]string  "Bad request (missing article_id, invalid sources or retrieval mode)"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /chat/sessions [post]
 func HandleCreateSession(h *core.Handler, w http.ResponseWriter, r *http.Request) {
@@ -84,10 +116,17 @@ func HandleCreateSession(h *core.Handler, w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if req.ArticleID == 0 {
+	archive := len(req.Sources) > 0 || req.Retrieval != RetrievalNone
+	if req.ArticleID == 0 && !archive {
 		http.Error(w, "Missing article_id", http.StatusBadRequest)
 		return
 	}
+	if archive {
+		if _, err := newChatScope(req.Sources, req.Retrieval, req.RetrievalDays); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	// Generate default title if not provided
 	title := req.Title
@@ -95,7 +134,13 @@ func HandleCreateSession(h *core.Handler, w http.ResponseWriter, r *http.Request
 		title = "New Chat"
 	}
 
-	sessionID, err := h.DB.CreateChatSession(req.ArticleID, title)
+	var sessionID int64
+	var err error
+	if archive {
+		sessionID, err = h.DB.CreateArchiveChatSession(title, req.Sources, req.Retrieval, req.RetrievalDays)
+	} else {
+		sessionID, err = h.DB.CreateChatSession(req.ArticleID, title)
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to create session: %v", err), http.StatusInternalServerError)
 		return
@@ -296,13 +341,14 @@ func HandleListMessages(h *core.Handler, w http.ResponseWriter, r *http.Request)
 
 	// Convert markdown to HTML for assistant messages
 	type MessageWithHTML struct {
-		ID        int64  `json:"id"`
-		SessionID int64  `json:"session_id"`
-		Role      string `json:"role"`
-		Content   string `json:"content"`
-		HTML      string `json:"html,omitempty"` // Pre-rendered HTML for assistant messages
-		Thinking  string `json:"thinking,omitempty"`
-		CreatedAt string `json:"created_at"`
+		ID        int64   `json:"id"`
+		SessionID int64   `json:"session_id"`
+		Role      string  `json:"role"`
+		Content   string  `json:"content"`
+		HTML      string  `json:"html,omitempty"` // Pre-rendered HTML for assistant messages
+		Thinking  string  `json:"thinking,omitempty"`
+		Citations []int64 `json:"citations,omitempty"` // IDs of the articles an archive answer cites
+		CreatedAt string  `json:"created_at"`
 	}
 
 	result := make([]MessageWithHTML, len(messages))
@@ -313,6 +359,7 @@ func HandleListMessages(h *core.Handler, w http.ResponseWriter, r *http.Request)
 			Role:      msg.Role,
 			Content:   msg.Content,
 			Thinking:  msg.Thinking,
+			Citations: msg.Citations,
 			CreatedAt: msg.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		}
 		// Generate HTML for assistant messages
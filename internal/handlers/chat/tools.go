@@ -0,0 +1,407 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
+	"MrRSS/internal/semantic"
+)
+
+const (
+	// maxToolRounds caps the tool calling rounds of one chat answer
+	maxToolRounds = 6
+	// maxToolSearchResults caps the articles a search_articles call returns
+	maxToolSearchResults = 20
+	// maxToolArticleIDs caps the articles one call may change
+	maxToolArticleIDs = 50
+	// toolSummaryChars is the summary length of articles returned by search_articles
+	toolSummaryChars = 300
+)
+
+// errTooManyToolRounds is returned when the AI keeps calling tools instead of answering
+var errTooManyToolRounds = errors.New("too many tool calls")
+
+// ChatAction is a tool the AI called while answering, reported to the client so it can
+// refresh what the tool changed
+type ChatAction struct {
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// toolsInstruction tells the AI how to use the chat toolset
+const toolsInstruction = `You can use tools to search the user's RSS articles, read them and change their state (read, favorite, read later) or create rules.
+Search before answering questions about articles that are not in the conversation. Only change articles or create rules when the user asks for it, and say what you changed.
+Refer to articles as [#id]. Today is %s.`
+
+// articleIDsSchema is the JSON schema of a list of article IDs
+var articleIDsSchema = map[string]interface{}{
+	"type":        "array",
+	"items":       map[string]interface{}{"type": "integer"},
+	"description": "IDs of the articles",
+}
+
+// chatTools returns the tools offered to chat when ai_chat_tools_enabled is set
+func chatTools() []ai.Tool {
+	return []ai.Tool{
+		{
+			Name:        "search_articles",
+			Description: "Search the user's articles by topic, newest and most relevant first. Without a query, lists the newest articles.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"query":       map[string]interface{}{"type": "string", "description": "Topic or keywords to search for"},
+					"days":        map[string]interface{}{"type": "integer", "description": "Only articles published in the last N days, e.g. 2 for yesterday and today"},
+					"unread_only": map[string]interface{}{"type": "boolean", "description": "Only unread articles"},
+					"limit":       map[string]interface{}{"type": "integer", "description": fmt.Sprintf("Maximum number of articles (default 10, max %d)", maxToolSearchResults)},
+				},
+			},
+		},
+		{
+			Name:        "get_article_content",
+			Description: "Get the full text of an article",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"article_id": map[string]interface{}{"type": "integer"}},
+				"required":   []string{"article_id"},
+			},
+		},
+		{
+			Name:        "mark_read",
+			Description: "Mark articles as read or unread",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"article_ids": articleIDsSchema,
+					"read":        map[string]interface{}{"type": "boolean", "description": "false to mark as unread (default true)"},
+				},
+				"required": []string{"article_ids"},
+			},
+		},
+		{
+			Name:        "set_favorite",
+			Description: "Add articles to or remove them from favorites",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"article_ids": articleIDsSchema,
+					"favorite":    map[string]interface{}{"type": "boolean", "description": "false to remove from favorites (default true)"},
+				},
+				"required": []string{"article_ids"},
+			},
+		},
+		{
+			Name:        "add_to_read_later",
+			Description: "Add articles to the read later list",
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"article_ids": articleIDsSchema},
+				"required":   []string{"article_ids"},
+			},
+		},
+		{
+			Name:        "create_rule",
+			Description: "Create a rule that applies actions to new articles matching all its conditions. The rule is created disabled; tell the user to review and enable it in the rules settings.",
+			Parameters: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string"},
+					"conditions": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"field":    map[string]interface{}{"type": "string", "enum": rules.ConditionFields},
+								"operator": map[string]interface{}{"type": "string", "enum": []string{"contains", "exact", "regex"}, "description": "For article_title"},
+								"value":    map[string]interface{}{"type": "string", "description": "Text to match, \"true\"/\"false\" for is_* fields, YYYY-MM-DD for dates, a score from 0 to 100 for relevance fields, a topic or entity name for topic and entity"},
+								"negate":   map[string]interface{}{"type": "boolean"},
+							},
+							"required": []string{"field", "value"},
+						},
+					},
+					"actions": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]interface{}{"type": "string", "enum": rules.ActionNames},
+					},
+				},
+				"required": []string{"name", "actions"},
+			},
+		},
+	}
+}
+
+// chatToolsEnabled reports whether chat may call tools
+func chatToolsEnabled(h *core.Handler) bool {
+	enabled, _ := h.DB.GetSetting("ai_chat_tools_enabled")
+	return enabled == "true"
+}
+
+// answerWithTools asks the AI with the chat toolset, running the tools it calls and sending
+// their results back until it answers. onAction, if set, is called after each tool call.
+// Once a tool has run, errors are final so that another profile doesn't repeat its changes.
+func answerWithTools(ctx context.Context, h *core.Handler, client *ai.Client, turn *chatTurn, onAction func(ChatAction) error) (ai.ResponseResult, error) {
+	var exchanges []ai.ToolExchange
+	for round := 0; round < maxToolRounds; round++ {
+		result, err := client.RequestWithToolsContext(ctx, turn.messagesMap, turn.tools, exchanges)
+		if err == nil && len(result.ToolCalls) == 0 {
+			return result, nil
+		}
+		if err != nil {
+			if len(turn.actions) > 0 {
+				return result, aiprofile.Final(err)
+			}
+			return result, err
+		}
+
+		exchange := ai.ToolExchange{Content: result.Content, Calls: result.ToolCalls}
+		for _, call := range result.ToolCalls {
+			output, err := runTool(ctx, h, turn, call)
+			action := ChatAction{Tool: call.Name, Arguments: call.Arguments}
+			if err != nil {
+				action.Error = err.Error()
+				output = toolJSON(map[string]string{"error": err.Error()})
+			}
+			turn.actions = append(turn.actions, action)
+			exchange.Results = append(exchange.Results, output)
+
+			if onAction != nil {
+				if err := onAction(action); err != nil {
+					return ai.ResponseResult{}, aiprofile.Final(err)
+				}
+			}
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return ai.ResponseResult{}, aiprofile.Final(errTooManyToolRounds)
+}
+
+// runTool runs a tool call and returns its result as JSON for the AI
+func runTool(ctx context.Context, h *core.Handler, turn *chatTurn, call ai.ToolCall) (string, error) {
+	switch call.Name {
+	case "search_articles":
+		var args struct {
+			Query      string `json:"query"`
+			Days       int    `json:"days"`
+			UnreadOnly bool   `json:"unread_only"`
+			Limit      int    `json:"limit"`
+		}
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return searchArticlesTool(ctx, h, turn, args.Query, args.Days, args.UnreadOnly, args.Limit)
+
+	case "get_article_content":
+		var args struct {
+			ArticleID int64 `json:"article_id"`
+		}
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return articleContentTool(h, turn, args.ArticleID)
+
+	case "mark_read", "set_favorite", "add_to_read_later":
+		args := struct {
+			ArticleIDs []int64 `json:"article_ids"`
+			Read       *bool   `json:"read"`
+			Favorite   *bool   `json:"favorite"`
+		}{}
+		if err := json.Unmarshal(call.Arguments, &args); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		return updateArticlesTool(h, call.Name, args.ArticleIDs, args.Read, args.Favorite)
+
+	case "create_rule":
+		var rule rules.Rule
+		if err := json.Unmarshal(call.Arguments, &rule); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		// The user reviews and enables the rule in the rules settings
+		rule.Enabled = false
+		rule, err := rules.NewEngine(h.DB).AddRule(rule)
+		if err != nil {
+			return "", err
+		}
+		return toolJSON(map[string]interface{}{"created": true, "rule_id": rule.ID, "enabled": false}), nil
+	}
+	return "", fmt.Errorf("unknown tool %q", call.Name)
+}
+
+// toolArticle is an article as returned to the AI by search_articles
+type toolArticle struct {
+	ID        int64  `json:"id"`
+	Title     string `json:"title"`
+	Feed      string `json:"feed"`
+	Published string `json:"published"`
+	Read      bool   `json:"read"`
+	Favorite  bool   `json:"favorite"`
+	ReadLater bool   `json:"read_later"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+// searchArticlesTool finds articles like archive chat retrieval, newest first without a query
+func searchArticlesTool(ctx context.Context, h *core.Handler, turn *chatTurn, query string, days int, unreadOnly bool, limit int) (string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxToolSearchResults {
+		limit = maxToolSearchResults
+	}
+
+	scope := chatScope{retrieval: RetrievalAuto}
+	if days > 0 {
+		scope.articles.Since = time.Now().AddDate(0, 0, -days)
+	}
+
+	// Unread articles are filtered afterwards, so look at more candidates
+	candidates := limit
+	if unreadOnly {
+		candidates = limit * 3
+	}
+
+	var articles []models.Article
+	var err error
+	if strings.TrimSpace(query) == "" {
+		articles, err = h.DB.GetRecentArticlesInScope(scope.articles, candidates)
+	} else {
+		articles, err = retrieveArticles(ctx, h, scope, query, candidates)
+	}
+	if err != nil && !errors.Is(err, semantic.ErrDisabled) {
+		return "", err
+	}
+
+	results := []toolArticle{}
+	for _, a := range articles {
+		if unreadOnly && a.IsRead {
+			continue
+		}
+		if len(results) == limit {
+			break
+		}
+		summary := []rune(semantic.ArticleText("", a.Summary, ""))
+		if len(summary) > toolSummaryChars {
+			summary = append(summary[:toolSummaryChars], '…')
+		}
+		results = append(results, toolArticle{
+			ID:        a.ID,
+			Title:     a.Title,
+			Feed:      a.FeedTitle,
+			Published: a.PublishedAt.Format("2006-01-02 15:04"),
+			Read:      a.IsRead,
+			Favorite:  a.IsFavorite,
+			ReadLater: a.IsReadLater,
+			Summary:   string(summary),
+		})
+		turn.addArticle(a)
+	}
+	return toolJSON(results), nil
+}
+
+// articleContentTool returns the text of an article: its extracted full text if available or
+// fetchable, otherwise the content of its feed entry
+func articleContentTool(h *core.Handler, turn *chatTurn, articleID int64) (string, error) {
+	article, err := h.DB.GetArticleByID(articleID)
+	if err != nil {
+		return "", fmt.Errorf("article %d not found", articleID)
+	}
+
+	content, found, err := h.DB.GetArticleFullText(articleID)
+	if err != nil || !found {
+		content = ""
+		if enabled, _ := h.DB.GetSetting("full_text_fetch_enabled"); enabled == "true" && article.URL != "" {
+			if content, err = h.FetchFullArticleContent(article.URL); err == nil {
+				if err := h.SaveArticleFullText(articleID, content); err != nil {
+					log.Printf("Error caching full article content: %v", err)
+				}
+			} else {
+				log.Printf("Error fetching full article content: %v", err)
+				content = ""
+			}
+		}
+	}
+	if content == "" {
+		if content, _, err = h.GetArticleContent(articleID); err != nil {
+			log.Printf("Error getting article content: %v", err)
+		}
+	}
+
+	turn.addArticle(*article)
+	return toolJSON(map[string]interface{}{
+		"id":        article.ID,
+		"title":     article.Title,
+		"url":       article.URL,
+		"published": article.PublishedAt.Format("2006-01-02 15:04"),
+		"content":   semantic.ArticleText("", article.Summary, content),
+	}), nil
+}
+
+// updateArticlesTool changes the read, favorite or read later state of articles
+func updateArticlesTool(h *core.Handler, tool string, ids []int64, read, favorite *bool) (string, error) {
+	if len(ids) == 0 {
+		return "", fmt.Errorf("no article IDs given")
+	}
+	if len(ids) > maxToolArticleIDs {
+		return "", fmt.Errorf("at most %d articles can be changed at once", maxToolArticleIDs)
+	}
+
+	updated := 0
+	var failed []int64
+	for _, id := range ids {
+		var syncReq *database.SyncRequest
+		var err error
+		switch tool {
+		case "mark_read":
+			syncReq, err = h.DB.MarkArticleReadWithSync(id, read == nil || *read)
+		case "set_favorite":
+			syncReq, err = h.DB.SetArticleFavoriteWithSync(id, favorite == nil || *favorite)
+		case "add_to_read_later":
+			syncReq, err = h.DB.SetArticleReadLaterWithSync(id, true)
+		}
+		if err != nil {
+			log.Printf("Chat tool %s failed for article %d: %v", tool, id, err)
+			failed = append(failed, id)
+			continue
+		}
+		if syncReq != nil {
+			go h.SyncArticleStatus(syncReq)
+		}
+		updated++
+	}
+	return toolJSON(map[string]interface{}{"updated": updated, "failed": failed}), nil
+}
+
+// toolJSON encodes a tool result
+func toolJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return `{"error":"failed to encode result"}`
+	}
+	return string(data)
+}
+
+// addArticle adds an article the AI has seen through a tool to the articles it may cite
+func (t *chatTurn) addArticle(article models.Article) {
+	for _, a := range t.articles {
+		if a.ID == article.ID {
+			return
+		}
+	}
+	t.articles = append(t.articles, article)
+}
+
+// withTools offers the chat toolset to the turn, with instructions in a system message
+func (t *chatTurn) withTools() {
+	t.tools = chatTools()
+	instruction := ChatMessage{Role: "system", Content: fmt.Sprintf(toolsInstruction, time.Now().Format("2006-01-02 (Monday)"))}
+	t.messages = append([]ChatMessage{instruction}, t.messages...)
+}
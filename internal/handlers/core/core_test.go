@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"MrRSS/internal/database"
 	"MrRSS/internal/feed"
@@ -29,3 +32,36 @@ func TestNewHandler_ConstructsHandler(t *testing.T) {
 		t.Fatal("DiscoveryService should be initialized")
 	}
 }
+
+func TestRequestContext_CancelledOnAppShutdown(t *testing.T) {
+	h := &Handler{}
+	appCtx, shutdown := context.WithCancel(context.Background())
+	h.SetAppContext(appCtx)
+
+	ctx, cancel := h.RequestContext(httptest.NewRequest("GET", "/", nil), time.Minute)
+	defer cancel()
+	if ctx.Err() != nil {
+		t.Fatal("context should not be done before shutdown")
+	}
+
+	shutdown()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context was not cancelled on shutdown")
+	}
+}
+
+func TestRequestContext_CancelledWithRequest(t *testing.T) {
+	h := &Handler{}
+	reqCtx, disconnect := context.WithCancel(context.Background())
+	r := httptest.NewRequest("GET", "/", nil).WithContext(reqCtx)
+
+	ctx, cancel := h.RequestContext(r, time.Minute)
+	defer cancel()
+
+	disconnect()
+	if ctx.Err() == nil {
+		t.Fatal("context should be cancelled when the client disconnects")
+	}
+}
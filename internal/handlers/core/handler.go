@@ -3,26 +3,29 @@
 package core
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
 	"MrRSS/internal/aiusage"
 	"MrRSS/internal/cache"
+	"MrRSS/internal/classify"
 	"MrRSS/internal/database"
 	"MrRSS/internal/discovery"
 	"MrRSS/internal/feed"
+	"MrRSS/internal/jobs"
+	"MrRSS/internal/keywords"
 	"MrRSS/internal/models"
 	"MrRSS/internal/statistics"
 	"MrRSS/internal/translation"
 	"MrRSS/internal/utils"
 
-	"codeberg.org/readeck/go-readability/v2"
-
 	"github.com/mmcdole/gofeed"
 )
 
@@ -53,11 +56,15 @@ type Handler struct {
 	App              interface{}         // Wails app instance for browser integration (interface{} to avoid import in server mode)
 	ContentCache     *cache.ContentCache // Cache for article content
 	Stats            *statistics.Service // Statistics tracking service
+	Jobs             *jobs.Worker        // Background translation and summary jobs
 
 	// Discovery state tracking for polling-based progress
 	DiscoveryMu          sync.RWMutex
 	SingleDiscoveryState *DiscoveryState
 	BatchDiscoveryState  *DiscoveryState
+
+	// appCtx is cancelled when the application shuts down
+	appCtx context.Context
 }
 
 // NewHandler creates a new Handler with the given dependencies.
@@ -70,6 +77,13 @@ func NewHandler(db *database.DB, fetcher *feed.Fetcher, translator translation.T
 		DiscoveryService: discovery.NewService(),
 		ContentCache:     cache.NewContentCache(100, 30*time.Minute), // Cache up to 100 articles for 30 minutes
 		Stats:            statistics.NewService(db),
+		appCtx:           context.Background(),
+	}
+	h.Jobs = jobs.NewWorker(db, translator, h.AITracker)
+
+	if fetcher != nil {
+		fetcher.SetClassifier(classify.NewService(db, h.AITracker))
+		fetcher.SetJobQueue(h.Jobs)
 	}
 
 	return h
@@ -94,11 +108,56 @@ func (h *Handler) SetApp(app interface{}) {
 	h.App = app
 }
 
+// SetAppContext sets the application lifetime context.
+// Requests using RequestContext are cancelled when it is done.
+func (h *Handler) SetAppContext(ctx context.Context) {
+	h.appCtx = ctx
+}
+
+// RequestContext returns a context for long-running work on behalf of r, such as AI calls.
+// It is cancelled when the client disconnects, the timeout expires or the application shuts down.
+func (h *Handler) RequestContext(r *http.Request, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	if h.appCtx == nil {
+		return ctx, cancel
+	}
+
+	stop := context.AfterFunc(h.appCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 // Statistics returns the statistics service
 func (h *Handler) Statistics() *statistics.Service {
 	return h.Stats
 }
 
+// ObsidianVaultPath returns the directory of the Obsidian vault that notes are exported to, or an
+// error saying why export to Obsidian is not possible
+func (h *Handler) ObsidianVaultPath() (string, error) {
+	if enabled, _ := h.DB.GetSetting("obsidian_enabled"); enabled != "true" {
+		return "", errors.New("Obsidian integration is not enabled")
+	}
+
+	// Get vault path (required for direct file access)
+	vaultPath, _ := h.DB.GetSetting("obsidian_vault_path")
+	if vaultPath == "" {
+		return "", errors.New("Obsidian vault path is not configured")
+	}
+
+	// Validate vault path exists and is a directory
+	if info, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return "", errors.New("Obsidian vault path does not exist")
+	} else if err != nil {
+		return "", err
+	} else if !info.IsDir() {
+		return "", errors.New("Obsidian vault path is not a directory")
+	}
+	return vaultPath, nil
+}
+
 // GetArticleContent fetches article content with caching
 // Returns (content, wasCached, error)
 func (h *Handler) GetArticleContent(articleID int64) (string, bool, error) {
@@ -167,21 +226,28 @@ func (h *Handler) GetArticleContent(articleID int64) (string, bool, error) {
 }
 
 // FetchFullArticleContent fetches the full article content from the original URL using readability.
+// Per-site extraction rules for the URL's domain are applied.
 func (h *Handler) FetchFullArticleContent(url string) (string, error) {
-	// Use FromURL which handles the HTTP request internally
-	article, err := readability.FromURL(url, 30*time.Second)
-	if err != nil {
-		return "", fmt.Errorf("readability parse: %w", err)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 90*time.Second)
+	defer cancel()
 
-	// Render the article content as HTML
-	var buf bytes.Buffer
-	err = article.RenderHTML(&buf)
+	result, err := h.Fetcher.GetFullTextExtractor().Extract(ctx, url)
 	if err != nil {
-		return "", fmt.Errorf("render HTML: %w", err)
+		return "", err
 	}
+	return result.Content, nil
+}
 
-	return buf.String(), nil
+// SaveArticleFullText caches the full text of an article and extracts its keywords again, as
+// those extracted when it was saved only saw the feed's excerpt
+func (h *Handler) SaveArticleFullText(articleID int64, content string) error {
+	if err := h.DB.SetArticleFullText(articleID, content); err != nil {
+		return err
+	}
+	if _, err := keywords.NewService(h.DB).ExtractArticle(articleID); err != nil {
+		log.Printf("Error extracting keywords for article %d: %v", articleID, err)
+	}
+	return nil
 }
 
 // findMatchingFeedItem finds the best matching feed item for an article using multiple criteria
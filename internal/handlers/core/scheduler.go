@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"MrRSS/internal/cache"
+	"MrRSS/internal/semantic"
 	"MrRSS/internal/utils"
 )
 
@@ -35,6 +36,15 @@ func (h *Handler) StartBackgroundScheduler(ctx context.Context) {
 		}
 	}()
 
+	// Embed new articles for semantic search, independent of the refresh mode
+	go semantic.NewService(h.DB, h.AITracker).Run(ctx, 10*time.Minute)
+
+	// Retrain the relevance model and rescore unread articles as reading behaviour changes
+	go h.Fetcher.Relevance().Run(ctx, 30*time.Minute)
+
+	// Translate titles and summarize new articles of opted-in feeds, resuming interrupted jobs
+	go h.Jobs.Run(ctx, time.Minute)
+
 	// Start the scheduler based on refresh mode
 	refreshMode, _ := h.DB.GetSetting("refresh_mode")
 
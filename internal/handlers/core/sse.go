@@ -0,0 +1,48 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SSEWriter writes Server-Sent Events to an HTTP response, flushing after every event.
+// Responses that can't be flushed still receive all events when the handler returns.
+type SSEWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewSSEWriter writes the event stream headers and returns a writer for the events
+func NewSSEWriter(w http.ResponseWriter) *SSEWriter {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	s := &SSEWriter{w: w, flusher: flusher}
+	s.flush()
+	return s
+}
+
+// Send writes an event with data encoded as JSON
+func (s *SSEWriter) Send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+// flush sends buffered events to the client if the response supports it
+func (s *SSEWriter) flush() {
+	if s.flusher != nil {
+		s.flusher.Flush()
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"context"
+	"log"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/freshrss"
+)
+
+// SyncArticleStatus immediately syncs an article's status change to FreshRSS, enqueueing it for
+// the next global sync when that fails. Run it in a background goroutine.
+func (h *Handler) SyncArticleStatus(syncReq *database.SyncRequest) {
+	// Check if FreshRSS is enabled and configured
+	enabled, _ := h.DB.GetSetting("freshrss_enabled")
+	if enabled != "true" {
+		return
+	}
+
+	serverURL, username, password, err := h.DB.GetFreshRSSConfig()
+	if err != nil || serverURL == "" || username == "" || password == "" {
+		log.Printf("[Immediate Sync] FreshRSS not configured, skipping sync")
+		return
+	}
+
+	// Create sync service
+	syncService := freshrss.NewBidirectionalSyncService(serverURL, username, password, h.DB)
+
+	// Perform immediate sync
+	ctx := context.Background()
+	err = syncService.SyncArticleStatus(ctx, syncReq.ArticleID, syncReq.ArticleURL, syncReq.Action)
+	if err != nil {
+		log.Printf("[Immediate Sync] Failed for article %d: %v", syncReq.ArticleID, err)
+		// Enqueue for retry during next global sync
+		_ = h.DB.EnqueueSyncChange(syncReq.ArticleID, syncReq.ArticleURL, syncReq.Action)
+		log.Printf("[Immediate Sync] Enqueued article %d for retry", syncReq.ArticleID)
+	} else {
+		log.Printf("[Immediate Sync] Success for article %d: %s", syncReq.ArticleID, syncReq.Action)
+	}
+}
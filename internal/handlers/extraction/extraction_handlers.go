@@ -0,0 +1,181 @@
+// Package extraction contains HTTP handlers for per-site full-text extraction rules.
+package extraction
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/fulltext"
+	"MrRSS/internal/handlers/core"
+)
+
+// testExtractionTimeout bounds a rule preview, including follow-up pages
+const testExtractionTimeout = 90 * time.Second
+
+var (
+	errDomainRequired  = errors.New("domain is required")
+	errInvalidMaxPages = errors.New("max_pages must not be negative")
+)
+
+// HandleExtractionRules lists (GET) or saves (POST) extraction rules.
+// @Summary      List or save extraction rules
+// @Description  GET returns all per-site extraction rules. POST creates a rule, or updates it when id is set.
+// @Tags         extraction
+// @Accept       json
+// @Produce      json
+// @Param        rule  body      database.ExtractionRule  false  "Rule to save (POST only)"
+// @Success      200  {object}  map[string]interface{}  "Rules list (GET) or saved rule ID (POST)"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid domain or selector)"
+// @Failure      404  {object}  map[string]string  "Rule not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /extraction-rules [get]
+// @Router       /extraction-rules [post]
+func HandleExtractionRules(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := h.DB.GetExtractionRules()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(rules)
+
+	case http.MethodPost:
+		var rule database.ExtractionRule
+		if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := validateRule(&rule); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.DB.SaveExtractionRule(&rule)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Rule not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDeleteExtractionRule deletes an extraction rule.
+// @Summary      Delete extraction rule
+// @Description  Delete a per-site extraction rule by ID
+// @Tags         extraction
+// @Produce      json
+// @Param        id   query     int64   true  "Rule ID"
+// @Success      200  {object}  map[string]bool  "Success"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid rule ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /extraction-rules/delete [post]
+func HandleDeleteExtractionRule(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.DeleteExtractionRule(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// HandleTestExtraction previews the extraction of a URL.
+// @Summary      Test extraction rule
+// @Description  Extract the full text of a URL with a draft rule, or with the saved rule for its domain when no rule is given
+// @Tags         extraction
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object  true  "URL and optional draft rule (url, rule)"
+// @Success      200  {object}  fulltext.Result  "Extraction result"
+// @Failure      400  {object}  map[string]string  "Bad request (missing URL or invalid selector)"
+// @Failure      502  {object}  map[string]string  "Extraction failed"
+// @Router       /extraction-rules/test [post]
+func HandleTestExtraction(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		URL  string                   `json:"url"`
+		Rule *database.ExtractionRule `json:"rule"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.URL == "" {
+		http.Error(w, "URL is required", http.StatusBadRequest)
+		return
+	}
+	if req.Rule != nil {
+		if err := validateSelectors(req.Rule); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), testExtractionTimeout)
+	defer cancel()
+
+	extractor := h.Fetcher.GetFullTextExtractor()
+	var result *fulltext.Result
+	var err error
+	if req.Rule != nil {
+		result, err = extractor.ExtractWithRule(ctx, req.URL, req.Rule)
+	} else {
+		result, err = extractor.Extract(ctx, req.URL)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// validateRule checks a rule before it is saved
+func validateRule(rule *database.ExtractionRule) error {
+	if database.NormalizeRuleDomain(rule.Domain) == "" {
+		return errDomainRequired
+	}
+	if rule.MaxPages < 0 {
+		return errInvalidMaxPages
+	}
+	return validateSelectors(rule)
+}
+
+// validateSelectors checks that all selectors of a rule can be parsed
+func validateSelectors(rule *database.ExtractionRule) error {
+	for _, value := range []string{rule.KeepSelectors, rule.StripSelectors, rule.NextPageSelector} {
+		if err := fulltext.ValidateSelectors(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
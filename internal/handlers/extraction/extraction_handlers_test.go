@@ -0,0 +1,133 @@
+package extraction
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"MrRSS/internal/database"
+	ff "MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+)
+
+func setupHandler(t *testing.T) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	return core.NewHandler(db, ff.NewFetcher(db), nil)
+}
+
+func TestHandleExtractionRules_SaveListDelete(t *testing.T) {
+	h := setupHandler(t)
+
+	body, _ := json.Marshal(database.ExtractionRule{Domain: "www.example.com", KeepSelectors: "article", Enabled: true})
+	rr := httptest.NewRecorder()
+	HandleExtractionRules(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("save: expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var saved struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&saved); err != nil || saved.ID == 0 {
+		t.Fatalf("save: unexpected response (err %v)", err)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleExtractionRules(h, rr, httptest.NewRequest(http.MethodGet, "/extraction-rules", nil))
+	var rules []database.ExtractionRule
+	if err := json.NewDecoder(rr.Body).Decode(&rules); err != nil {
+		t.Fatalf("list: decode error: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Domain != "example.com" {
+		t.Fatalf("list: unexpected rules %+v", rules)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleDeleteExtractionRule(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules/delete?id="+strconv.FormatInt(saved.ID, 10), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("delete: expected 200 got %d", rr.Code)
+	}
+	if rules, _ := h.DB.GetExtractionRules(); len(rules) != 0 {
+		t.Errorf("expected no rules after delete, got %d", len(rules))
+	}
+}
+
+func TestHandleExtractionRules_Validation(t *testing.T) {
+	h := setupHandler(t)
+
+	tests := map[string]database.ExtractionRule{
+		"missing domain":   {KeepSelectors: "article"},
+		"invalid selector": {Domain: "example.com", StripSelectors: "div[["},
+		"invalid xpath":    {Domain: "example.com", NextPageSelector: "//a[@rel="},
+		"negative pages":   {Domain: "example.com", MaxPages: -1},
+	}
+	for name, rule := range tests {
+		body, _ := json.Marshal(rule)
+		rr := httptest.NewRecorder()
+		HandleExtractionRules(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules", bytes.NewReader(body)))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400 got %d", name, rr.Code)
+		}
+	}
+
+	body, _ := json.Marshal(database.ExtractionRule{ID: 42, Domain: "example.com"})
+	rr := httptest.NewRecorder()
+	HandleExtractionRules(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules", bytes.NewReader(body)))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("updating a missing rule: expected 404 got %d", rr.Code)
+	}
+}
+
+func TestHandleTestExtraction_DraftRule(t *testing.T) {
+	h := setupHandler(t)
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body><div class="ad">Ad</div><main><p>Hello from the article.</p></main></body></html>`))
+	}))
+	defer site.Close()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"url":  site.URL + "/post",
+		"rule": database.ExtractionRule{KeepSelectors: "main"},
+	})
+	rr := httptest.NewRecorder()
+	HandleTestExtraction(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules/test", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var result struct {
+		Content string   `json:"content"`
+		Pages   []string `json:"pages"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if result.Content != "<main><p>Hello from the article.</p></main>" || len(result.Pages) != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestHandleTestExtraction_BadRequest(t *testing.T) {
+	h := setupHandler(t)
+
+	rr := httptest.NewRecorder()
+	HandleTestExtraction(h, rr, httptest.NewRequest(http.MethodPost, "/extraction-rules/test", bytes.NewReader([]byte(`{}`))))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("missing URL: expected 400 got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleTestExtraction(h, rr, httptest.NewRequest(http.MethodGet, "/extraction-rules/test", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: expected 405 got %d", rr.Code)
+	}
+}
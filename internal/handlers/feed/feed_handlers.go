@@ -71,6 +71,11 @@ func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		XPathItemUid        string `json:"xpath_item_uid"`
 		ArticleViewMode     string `json:"article_view_mode"`
 		AutoExpandContent   string `json:"auto_expand_content"`
+		FullTextOnIngest    *bool  `json:"full_text_on_ingest"`
+		BackgroundTranslate *bool  `json:"background_translate"`
+		BackgroundSummarize *bool  `json:"background_summarize"`
+		SummaryPromptID     *int64 `json:"summary_prompt_id"`
+		TranslationPromptID *int64 `json:"translation_prompt_id"`
 		// Email/Newsletter fields
 		EmailAddress    string `json:"email_address"`
 		EmailIMAPServer string `json:"email_imap_server"`
@@ -134,6 +139,33 @@ func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if req.FullTextOnIngest != nil {
+		if err := h.DB.SetFeedFullTextOnIngest(feedID, *req.FullTextOnIngest); err != nil {
+			http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if req.BackgroundTranslate != nil || req.BackgroundSummarize != nil {
+		translate := req.BackgroundTranslate != nil && *req.BackgroundTranslate
+		summarize := req.BackgroundSummarize != nil && *req.BackgroundSummarize
+		if err := h.DB.SetFeedBackgroundJobs(feedID, translate, summarize); err != nil {
+			http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if req.SummaryPromptID != nil || req.TranslationPromptID != nil {
+		var summaryPromptID, translationPromptID int64
+		if req.SummaryPromptID != nil {
+			summaryPromptID = *req.SummaryPromptID
+		}
+		if req.TranslationPromptID != nil {
+			translationPromptID = *req.TranslationPromptID
+		}
+		if err := h.DB.SetFeedPromptTemplates(feedID, summaryPromptID, translationPromptID); err != nil {
+			http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 
 	// Immediately fetch articles for the newly added feed in background
 	go func() {
@@ -206,6 +238,11 @@ func HandleUpdateFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		XPathItemUid        string `json:"xpath_item_uid"`
 		ArticleViewMode     string `json:"article_view_mode"`
 		AutoExpandContent   string `json:"auto_expand_content"`
+		FullTextOnIngest    *bool  `json:"full_text_on_ingest"`
+		BackgroundTranslate *bool  `json:"background_translate"`
+		BackgroundSummarize *bool  `json:"background_summarize"`
+		SummaryPromptID     *int64 `json:"summary_prompt_id"`
+		TranslationPromptID *int64 `json:"translation_prompt_id"`
 		// Email/Newsletter fields
 		EmailAddress    string `json:"email_address"`
 		EmailIMAPServer string `json:"email_imap_server"`
@@ -267,6 +304,50 @@ func HandleUpdateFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if req.FullTextOnIngest != nil {
+		if err := h.DB.SetFeedFullTextOnIngest(req.ID, *req.FullTextOnIngest); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if req.BackgroundTranslate != nil || req.BackgroundSummarize != nil {
+		// Options left out of the request keep their current value
+		current, err := h.DB.GetFeedByID(req.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		translate, summarize := current.BackgroundTranslate, current.BackgroundSummarize
+		if req.BackgroundTranslate != nil {
+			translate = *req.BackgroundTranslate
+		}
+		if req.BackgroundSummarize != nil {
+			summarize = *req.BackgroundSummarize
+		}
+		if err := h.DB.SetFeedBackgroundJobs(req.ID, translate, summarize); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if req.SummaryPromptID != nil || req.TranslationPromptID != nil {
+		// Templates left out of the request keep their current value
+		current, err := h.DB.GetFeedByID(req.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		summaryPromptID, translationPromptID := current.SummaryPromptID, current.TranslationPromptID
+		if req.SummaryPromptID != nil {
+			summaryPromptID = *req.SummaryPromptID
+		}
+		if req.TranslationPromptID != nil {
+			translationPromptID = *req.TranslationPromptID
+		}
+		if err := h.DB.SetFeedPromptTemplates(req.ID, summaryPromptID, translationPromptID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -0,0 +1,138 @@
+package feed
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+)
+
+// Defaults for the feed health report
+const (
+	defaultStaleDays = 30
+	defaultSlowMs    = 10000
+)
+
+// HandleFeedHealth returns a report of broken, stale and slow feeds.
+// @Summary      Get feed health report
+// @Description  List feeds that keep failing or were paused, feeds without new articles for N days, and slow feeds
+// @Tags         feeds
+// @Accept       json
+// @Produce      json
+// @Param        stale_days  query     int  false  "Days without new articles before a feed is stale (default 30)"
+// @Param        slow_ms     query     int  false  "Average fetch duration in milliseconds before a feed is slow (default 10000)"
+// @Success      200  {object}  feed.FeedHealthReport  "Feed health report"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid parameters)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /feeds/health [get]
+func HandleFeedHealth(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	staleDays, ok := parsePositiveInt(r.URL.Query().Get("stale_days"), defaultStaleDays)
+	if !ok {
+		http.Error(w, "Invalid stale_days", http.StatusBadRequest)
+		return
+	}
+	slowMs, ok := parsePositiveInt(r.URL.Query().Get("slow_ms"), defaultSlowMs)
+	if !ok {
+		http.Error(w, "Invalid slow_ms", http.StatusBadRequest)
+		return
+	}
+
+	report, err := feed.BuildFeedHealthReport(h.DB, staleDays, time.Duration(slowMs)*time.Millisecond)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// HandleFeedFetchHistory returns the recent fetch attempts of a feed.
+// @Summary      Get feed fetch history
+// @Description  Get the most recent fetch attempts of a feed (timestamp, HTTP status, duration, item count, error class)
+// @Tags         feeds
+// @Accept       json
+// @Produce      json
+// @Param        feed_id  query     int64  true   "Feed ID"
+// @Param        limit    query     int    false  "Maximum number of entries (default 50)"
+// @Success      200  {array}   database.FetchHistoryEntry  "Fetch history, newest first"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid feed ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /feeds/health/history [get]
+func HandleFeedFetchHistory(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	feedID, err := strconv.ParseInt(r.URL.Query().Get("feed_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	limit, ok := parsePositiveInt(r.URL.Query().Get("limit"), 50)
+	if !ok {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.DB.GetFetchHistory(feedID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// HandleResumeFeed resumes scheduled refreshes of a paused feed.
+// @Summary      Resume a paused feed
+// @Description  Resume scheduled refreshes of a feed that was paused, such as after it answered HTTP 410 Gone. Its refresh interval is unchanged.
+// @Tags         feeds
+// @Accept       json
+// @Produce      json
+// @Param        feed_id  query     int64  true  "Feed ID"
+// @Success      200  {object}  map[string]bool  "Success status"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid feed ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /feeds/health/resume [post]
+func HandleResumeFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	feedID, err := strconv.ParseInt(r.URL.Query().Get("feed_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.DB.ClearFeedPause(feedID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// parsePositiveInt parses an optional positive integer query parameter
+func parsePositiveInt(value string, defaultValue int) (int, bool) {
+	if value == "" {
+		return defaultValue, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,99 @@
+package feed_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"MrRSS/internal/database"
+	ff "MrRSS/internal/feed"
+	fh "MrRSS/internal/handlers/feed"
+	"MrRSS/internal/models"
+)
+
+func TestHandleFeedHealth_ReturnsReport(t *testing.T) {
+	h := setupHandler(t)
+
+	id, err := h.DB.AddFeed(&models.Feed{Title: "broken", URL: "http://x/broken"})
+	if err != nil {
+		t.Fatalf("add feed: %v", err)
+	}
+	for i := 0; i < ff.BrokenFailureThreshold; i++ {
+		if _, err := h.DB.RecordFeedFailure(id); err != nil {
+			t.Fatalf("record failure: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/feeds/health?stale_days=7&slow_ms=500", nil)
+	w := httptest.NewRecorder()
+	fh.HandleFeedHealth(h, w, req)
+
+	res := w.Result()
+	if res.StatusCode != 200 {
+		t.Fatalf("expected 200 OK, got %d", res.StatusCode)
+	}
+	var report ff.FeedHealthReport
+	if err := json.NewDecoder(res.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.StaleDays != 7 || report.SlowThreshold != 500 {
+		t.Errorf("expected parameters to be applied, got stale_days=%d slow_threshold_ms=%d", report.StaleDays, report.SlowThreshold)
+	}
+	if len(report.Broken) != 1 || report.Broken[0].FeedID != id {
+		t.Errorf("expected feed %d to be broken, got %+v", id, report.Broken)
+	}
+}
+
+func TestHandleFeedHealth_InvalidParams(t *testing.T) {
+	h := setupHandler(t)
+
+	for _, query := range []string{"stale_days=abc", "slow_ms=-1"} {
+		req := httptest.NewRequest("GET", "/api/feeds/health?"+query, nil)
+		w := httptest.NewRecorder()
+		fh.HandleFeedHealth(h, w, req)
+		if w.Code != 400 {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/api/feeds/health", nil)
+	w := httptest.NewRecorder()
+	fh.HandleFeedHealth(h, w, req)
+	if w.Code != 405 {
+		t.Errorf("expected 405 for POST, got %d", w.Code)
+	}
+}
+
+func TestHandleFeedFetchHistory(t *testing.T) {
+	h := setupHandler(t)
+
+	id, err := h.DB.AddFeed(&models.Feed{Title: "a", URL: "http://x/a"})
+	if err != nil {
+		t.Fatalf("add feed: %v", err)
+	}
+	if err := h.DB.RecordFetchAttempt(database.FetchHistoryEntry{FeedID: id, HTTPStatus: 404, ErrorClass: "http_4xx"}); err != nil {
+		t.Fatalf("record attempt: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/api/feeds/health/history?feed_id=abc", nil)
+	w := httptest.NewRecorder()
+	fh.HandleFeedFetchHistory(h, w, req)
+	if w.Code != 400 {
+		t.Errorf("expected 400 for invalid feed ID, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/feeds/health/history?feed_id="+strconv.FormatInt(id, 10), nil)
+	w = httptest.NewRecorder()
+	fh.HandleFeedFetchHistory(h, w, req)
+	if w.Code != 200 {
+		t.Fatalf("expected 200 OK, got %d", w.Code)
+	}
+	var history []database.FetchHistoryEntry
+	if err := json.NewDecoder(w.Body).Decode(&history); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(history) != 1 || history[0].HTTPStatus != 404 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
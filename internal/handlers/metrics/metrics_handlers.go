@@ -0,0 +1,109 @@
+// Package metrics provides the Prometheus-compatible /metrics endpoint.
+package metrics
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"MrRSS/internal/cache"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/metrics"
+	"MrRSS/internal/utils"
+)
+
+// HandleMetrics exposes application metrics in the Prometheus text exposition format.
+// @Summary      Prometheus metrics
+// @Description  Returns fetch, queue, storage, AI and translation metrics in Prometheus text format
+// @Tags         metrics
+// @Produce      plain
+// @Success      200  {string}  string  "Prometheus text exposition"
+// @Router       /metrics [get]
+func HandleMetrics(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	// Counters and histograms recorded throughout the application
+	metrics.Default.WriteText(w)
+
+	// Gauges computed at scrape time
+	writeTaskGauges(h, w)
+	writeStorageGauges(h, w)
+	writeAIGauges(h, w)
+	writeTranslationCacheGauges(h, w)
+}
+
+// writeTaskGauges writes the feed refresh queue and pool sizes
+func writeTaskGauges(h *core.Handler, w io.Writer) {
+	if h.Fetcher == nil || h.Fetcher.GetTaskManager() == nil {
+		return
+	}
+
+	tm := h.Fetcher.GetTaskManager()
+	stats := tm.GetStats()
+
+	metrics.WriteGauge(w, "mrrss_refresh_queue_size", "Number of feeds waiting in the refresh queue.", float64(stats.QueueTaskCount))
+	metrics.WriteGauge(w, "mrrss_refresh_pool_size", "Number of feeds currently being refreshed.", float64(stats.PoolTaskCount))
+	metrics.WriteGauge(w, "mrrss_refresh_pool_capacity", "Maximum number of concurrent feed refreshes.", float64(tm.GetPoolCapacity()))
+	metrics.WriteGauge(w, "mrrss_refresh_immediate_tasks", "Number of article-click triggered refreshes in progress.", float64(stats.ArticleClickCount))
+}
+
+// writeStorageGauges writes database, media cache and FreshRSS sync queue sizes
+func writeStorageGauges(h *core.Handler, w io.Writer) {
+	if sizeMB, err := h.DB.GetDatabaseSizeMB(); err == nil {
+		metrics.WriteGauge(w, "mrrss_database_size_bytes", "Size of the SQLite database in bytes.", sizeMB*1024*1024)
+	} else {
+		log.Printf("metrics: failed to get database size: %v", err)
+	}
+
+	if cacheDir, err := utils.GetMediaCacheDir(); err == nil {
+		if mediaCache, err := cache.NewMediaCache(cacheDir); err == nil {
+			if size, err := mediaCache.GetCacheSize(); err == nil {
+				metrics.WriteGauge(w, "mrrss_media_cache_size_bytes", "Size of the media cache in bytes.", float64(size))
+			}
+		}
+	}
+
+	if pending, err := h.DB.GetPendingSyncCount(); err == nil {
+		metrics.WriteGauge(w, "mrrss_freshrss_sync_queue_pending", "Number of pending FreshRSS sync queue items.", float64(pending))
+	}
+}
+
+// writeAIGauges writes the persisted AI usage counter and configured limit
+func writeAIGauges(h *core.Handler, w io.Writer) {
+	if h.AITracker == nil {
+		return
+	}
+
+	if usage, err := h.AITracker.GetCurrentUsage(); err == nil {
+		metrics.WriteGauge(w, "mrrss_ai_usage_tokens", "Accumulated AI token usage counted against the limit.", float64(usage))
+	}
+	if limit, err := h.AITracker.GetUsageLimit(); err == nil {
+		metrics.WriteGauge(w, "mrrss_ai_usage_limit_tokens", "Configured AI token usage limit (0 = unlimited).", float64(limit))
+	}
+}
+
+// writeTranslationCacheGauges writes the translation cache hit ratio, from the same counts as the
+// translation cache statistics
+func writeTranslationCacheGauges(h *core.Handler, w io.Writer) {
+	stats, err := h.DB.GetTranslationCacheStats()
+	if err != nil {
+		log.Printf("metrics: failed to get translation cache stats: %v", err)
+		return
+	}
+
+	var hits, lookups int64
+	for _, s := range stats {
+		hits += s.Hits
+		lookups += s.Hits + s.Misses
+	}
+	ratio := 0.0
+	if lookups > 0 {
+		ratio = float64(hits) / float64(lookups)
+	}
+	metrics.WriteGauge(w, "mrrss_translation_cache_hit_ratio", "Fraction of translation cache lookups served from cache.", ratio)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+)
+
+func setupHandler(t *testing.T) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	return core.NewHandler(db, nil, nil)
+}
+
+func TestHandleMetrics_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/metrics", nil)
+	rr := httptest.NewRecorder()
+
+	HandleMetrics(nil, rr, req)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected %d got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+}
+
+func TestHandleMetrics_TextExposition(t *testing.T) {
+	h := setupHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rr := httptest.NewRecorder()
+
+	HandleMetrics(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE mrrss_feed_fetch_total counter",
+		"# TYPE mrrss_database_size_bytes gauge",
+		"# TYPE mrrss_ai_usage_tokens gauge",
+		"# TYPE mrrss_freshrss_sync_queue_pending gauge",
+		"# TYPE mrrss_translation_cache_hit_ratio gauge",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
@@ -49,15 +49,25 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	case http.MethodGet:
 		aiApiKey := safeGetEncryptedSetting(h, "ai_api_key")
 		aiChatEnabled := safeGetSetting(h, "ai_chat_enabled")
+		aiChatToolsEnabled := safeGetSetting(h, "ai_chat_tools_enabled")
+		aiClassificationEnabled := safeGetSetting(h, "ai_classification_enabled")
+		aiClassificationTopics := safeGetSetting(h, "ai_classification_topics")
 		aiCustomHeaders := safeGetSetting(h, "ai_custom_headers")
+		aiEmbeddingEnabled := safeGetSetting(h, "ai_embedding_enabled")
+		aiEmbeddingEndpoint := safeGetSetting(h, "ai_embedding_endpoint")
+		aiEmbeddingModel := safeGetSetting(h, "ai_embedding_model")
 		aiEndpoint := safeGetSetting(h, "ai_endpoint")
 		aiModel := safeGetSetting(h, "ai_model")
 		aiSummaryPrompt := safeGetSetting(h, "ai_summary_prompt")
+		aiTranslationGoogleFallback := safeGetSetting(h, "ai_translation_google_fallback")
 		aiTranslationPrompt := safeGetSetting(h, "ai_translation_prompt")
 		aiUsageLimit := safeGetSetting(h, "ai_usage_limit")
 		aiUsageTokens := safeGetSetting(h, "ai_usage_tokens")
 		autoCleanupEnabled := safeGetSetting(h, "auto_cleanup_enabled")
 		autoShowAllContent := safeGetSetting(h, "auto_show_all_content")
+		backgroundJobsConcurrency := safeGetSetting(h, "background_jobs_concurrency")
+		backgroundSummarizeCategories := safeGetSetting(h, "background_summarize_categories")
+		backgroundTranslateCategories := safeGetSetting(h, "background_translate_categories")
 		baiduAppId := safeGetSetting(h, "baidu_app_id")
 		baiduSecretKey := safeGetEncryptedSetting(h, "baidu_secret_key")
 		closeToTray := safeGetSetting(h, "close_to_tray")
@@ -84,6 +94,7 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		freshrssServerUrl := safeGetSetting(h, "freshrss_server_url")
 		freshrssSyncOnStartup := safeGetSetting(h, "freshrss_sync_on_startup")
 		freshrssUsername := safeGetSetting(h, "freshrss_username")
+		fullArticleTranslation := safeGetSetting(h, "full_article_translation")
 		fullTextFetchEnabled := safeGetSetting(h, "full_text_fetch_enabled")
 		googleTranslateEndpoint := safeGetSetting(h, "google_translate_endpoint")
 		hoverMarkAsRead := safeGetSetting(h, "hover_mark_as_read")
@@ -91,6 +102,8 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		language := safeGetSetting(h, "language")
 		lastGlobalRefresh := safeGetSetting(h, "last_global_refresh")
 		lastNetworkTest := safeGetSetting(h, "last_network_test")
+		libretranslateApiKey := safeGetEncryptedSetting(h, "libretranslate_api_key")
+		libretranslateEndpoint := safeGetSetting(h, "libretranslate_endpoint")
 		maxArticleAgeDays := safeGetSetting(h, "max_article_age_days")
 		maxCacheSizeMb := safeGetSetting(h, "max_cache_size_mb")
 		maxConcurrentRefreshes := safeGetSetting(h, "max_concurrent_refreshes")
@@ -139,15 +152,25 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{
 			"ai_api_key":                       aiApiKey,
 			"ai_chat_enabled":                  aiChatEnabled,
+			"ai_chat_tools_enabled":            aiChatToolsEnabled,
+			"ai_classification_enabled":        aiClassificationEnabled,
+			"ai_classification_topics":         aiClassificationTopics,
 			"ai_custom_headers":                aiCustomHeaders,
+			"ai_embedding_enabled":             aiEmbeddingEnabled,
+			"ai_embedding_endpoint":            aiEmbeddingEndpoint,
+			"ai_embedding_model":               aiEmbeddingModel,
 			"ai_endpoint":                      aiEndpoint,
 			"ai_model":                         aiModel,
 			"ai_summary_prompt":                aiSummaryPrompt,
+			"ai_translation_google_fallback":   aiTranslationGoogleFallback,
 			"ai_translation_prompt":            aiTranslationPrompt,
 			"ai_usage_limit":                   aiUsageLimit,
 			"ai_usage_tokens":                  aiUsageTokens,
 			"auto_cleanup_enabled":             autoCleanupEnabled,
 			"auto_show_all_content":            autoShowAllContent,
+			"background_jobs_concurrency":      backgroundJobsConcurrency,
+			"background_summarize_categories":  backgroundSummarizeCategories,
+			"background_translate_categories":  backgroundTranslateCategories,
 			"baidu_app_id":                     baiduAppId,
 			"baidu_secret_key":                 baiduSecretKey,
 			"close_to_tray":                    closeToTray,
@@ -174,6 +197,7 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			"freshrss_server_url":              freshrssServerUrl,
 			"freshrss_sync_on_startup":         freshrssSyncOnStartup,
 			"freshrss_username":                freshrssUsername,
+			"full_article_translation":         fullArticleTranslation,
 			"full_text_fetch_enabled":          fullTextFetchEnabled,
 			"google_translate_endpoint":        googleTranslateEndpoint,
 			"hover_mark_as_read":               hoverMarkAsRead,
@@ -181,6 +205,8 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			"language":                         language,
 			"last_global_refresh":              lastGlobalRefresh,
 			"last_network_test":                lastNetworkTest,
+			"libretranslate_api_key":           libretranslateApiKey,
+			"libretranslate_endpoint":          libretranslateEndpoint,
 			"max_article_age_days":             maxArticleAgeDays,
 			"max_cache_size_mb":                maxCacheSizeMb,
 			"max_concurrent_refreshes":         maxConcurrentRefreshes,
@@ -231,15 +257,25 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			AIAPIKey                      string `json:"ai_api_key"`
 			AIChatEnabled                 string `json:"ai_chat_enabled"`
+			AIChatToolsEnabled            string `json:"ai_chat_tools_enabled"`
+			AIClassificationEnabled       string `json:"ai_classification_enabled"`
+			AIClassificationTopics        string `json:"ai_classification_topics"`
 			AICustomHeaders               string `json:"ai_custom_headers"`
+			AIEmbeddingEnabled            string `json:"ai_embedding_enabled"`
+			AIEmbeddingEndpoint           string `json:"ai_embedding_endpoint"`
+			AIEmbeddingModel              string `json:"ai_embedding_model"`
 			AIEndpoint                    string `json:"ai_endpoint"`
 			AIModel                       string `json:"ai_model"`
 			AISummaryPrompt               string `json:"ai_summary_prompt"`
+			AITranslationGoogleFallback   string `json:"ai_translation_google_fallback"`
 			AITranslationPrompt           string `json:"ai_translation_prompt"`
 			AIUsageLimit                  string `json:"ai_usage_limit"`
 			AIUsageTokens                 string `json:"ai_usage_tokens"`
 			AutoCleanupEnabled            string `json:"auto_cleanup_enabled"`
 			AutoShowAllContent            string `json:"auto_show_all_content"`
+			BackgroundJobsConcurrency     string `json:"background_jobs_concurrency"`
+			BackgroundSummarizeCategories string `json:"background_summarize_categories"`
+			BackgroundTranslateCategories string `json:"background_translate_categories"`
 			BaiduAppId                    string `json:"baidu_app_id"`
 			BaiduSecretKey                string `json:"baidu_secret_key"`
 			CloseToTray                   string `json:"close_to_tray"`
@@ -266,6 +302,7 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			FreshRSSServerUrl             string `json:"freshrss_server_url"`
 			FreshRSSSyncOnStartup         string `json:"freshrss_sync_on_startup"`
 			FreshRSSUsername              string `json:"freshrss_username"`
+			FullArticleTranslation        string `json:"full_article_translation"`
 			FullTextFetchEnabled          string `json:"full_text_fetch_enabled"`
 			GoogleTranslateEndpoint       string `json:"google_translate_endpoint"`
 			HoverMarkAsRead               string `json:"hover_mark_as_read"`
@@ -273,6 +310,8 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			Language                      string `json:"language"`
 			LastGlobalRefresh             string `json:"last_global_refresh"`
 			LastNetworkTest               string `json:"last_network_test"`
+			LibretranslateAPIKey          string `json:"libretranslate_api_key"`
+			LibretranslateEndpoint        string `json:"libretranslate_endpoint"`
 			MaxArticleAgeDays             string `json:"max_article_age_days"`
 			MaxCacheSizeMb                string `json:"max_cache_size_mb"`
 			MaxConcurrentRefreshes        string `json:"max_concurrent_refreshes"`
@@ -333,10 +372,34 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("ai_chat_enabled", req.AIChatEnabled)
 		}
 
+		if req.AIChatToolsEnabled != "" {
+			h.DB.SetSetting("ai_chat_tools_enabled", req.AIChatToolsEnabled)
+		}
+
+		if req.AIClassificationEnabled != "" {
+			h.DB.SetSetting("ai_classification_enabled", req.AIClassificationEnabled)
+		}
+
+		if req.AIClassificationTopics != "" {
+			h.DB.SetSetting("ai_classification_topics", req.AIClassificationTopics)
+		}
+
 		if req.AICustomHeaders != "" {
 			h.DB.SetSetting("ai_custom_headers", req.AICustomHeaders)
 		}
 
+		if req.AIEmbeddingEnabled != "" {
+			h.DB.SetSetting("ai_embedding_enabled", req.AIEmbeddingEnabled)
+		}
+
+		if req.AIEmbeddingEndpoint != "" {
+			h.DB.SetSetting("ai_embedding_endpoint", req.AIEmbeddingEndpoint)
+		}
+
+		if req.AIEmbeddingModel != "" {
+			h.DB.SetSetting("ai_embedding_model", req.AIEmbeddingModel)
+		}
+
 		if req.AIEndpoint != "" {
 			h.DB.SetSetting("ai_endpoint", req.AIEndpoint)
 		}
@@ -349,6 +412,10 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("ai_summary_prompt", req.AISummaryPrompt)
 		}
 
+		if req.AITranslationGoogleFallback != "" {
+			h.DB.SetSetting("ai_translation_google_fallback", req.AITranslationGoogleFallback)
+		}
+
 		if req.AITranslationPrompt != "" {
 			h.DB.SetSetting("ai_translation_prompt", req.AITranslationPrompt)
 		}
@@ -369,6 +436,18 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("auto_show_all_content", req.AutoShowAllContent)
 		}
 
+		if req.BackgroundJobsConcurrency != "" {
+			h.DB.SetSetting("background_jobs_concurrency", req.BackgroundJobsConcurrency)
+		}
+
+		if req.BackgroundSummarizeCategories != "" {
+			h.DB.SetSetting("background_summarize_categories", req.BackgroundSummarizeCategories)
+		}
+
+		if req.BackgroundTranslateCategories != "" {
+			h.DB.SetSetting("background_translate_categories", req.BackgroundTranslateCategories)
+		}
+
 		if req.BaiduAppId != "" {
 			h.DB.SetSetting("baidu_app_id", req.BaiduAppId)
 		}
@@ -496,6 +575,10 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("freshrss_username", req.FreshRSSUsername)
 		}
 
+		if req.FullArticleTranslation != "" {
+			h.DB.SetSetting("full_article_translation", req.FullArticleTranslation)
+		}
+
 		if req.FullTextFetchEnabled != "" {
 			h.DB.SetSetting("full_text_fetch_enabled", req.FullTextFetchEnabled)
 		}
@@ -524,6 +607,16 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("last_network_test", req.LastNetworkTest)
 		}
 
+		if err := h.DB.SetEncryptedSetting("libretranslate_api_key", req.LibretranslateAPIKey); err != nil {
+			log.Printf("Failed to save libretranslate_api_key: %v", err)
+			http.Error(w, "Failed to save libretranslate_api_key", http.StatusInternalServerError)
+			return
+		}
+
+		if req.LibretranslateEndpoint != "" {
+			h.DB.SetSetting("libretranslate_endpoint", req.LibretranslateEndpoint)
+		}
+
 		if req.MaxArticleAgeDays != "" {
 			h.DB.SetSetting("max_article_age_days", req.MaxArticleAgeDays)
 		}
@@ -712,15 +805,25 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		// Re-fetch all settings after save to return updated values
 		aiApiKey := safeGetEncryptedSetting(h, "ai_api_key")
 		aiChatEnabled := safeGetSetting(h, "ai_chat_enabled")
+		aiChatToolsEnabled := safeGetSetting(h, "ai_chat_tools_enabled")
+		aiClassificationEnabled := safeGetSetting(h, "ai_classification_enabled")
+		aiClassificationTopics := safeGetSetting(h, "ai_classification_topics")
 		aiCustomHeaders := safeGetSetting(h, "ai_custom_headers")
+		aiEmbeddingEnabled := safeGetSetting(h, "ai_embedding_enabled")
+		aiEmbeddingEndpoint := safeGetSetting(h, "ai_embedding_endpoint")
+		aiEmbeddingModel := safeGetSetting(h, "ai_embedding_model")
 		aiEndpoint := safeGetSetting(h, "ai_endpoint")
 		aiModel := safeGetSetting(h, "ai_model")
 		aiSummaryPrompt := safeGetSetting(h, "ai_summary_prompt")
+		aiTranslationGoogleFallback := safeGetSetting(h, "ai_translation_google_fallback")
 		aiTranslationPrompt := safeGetSetting(h, "ai_translation_prompt")
 		aiUsageLimit := safeGetSetting(h, "ai_usage_limit")
 		aiUsageTokens := safeGetSetting(h, "ai_usage_tokens")
 		autoCleanupEnabled := safeGetSetting(h, "auto_cleanup_enabled")
 		autoShowAllContent := safeGetSetting(h, "auto_show_all_content")
+		backgroundJobsConcurrency := safeGetSetting(h, "background_jobs_concurrency")
+		backgroundSummarizeCategories := safeGetSetting(h, "background_summarize_categories")
+		backgroundTranslateCategories := safeGetSetting(h, "background_translate_categories")
 		baiduAppId := safeGetSetting(h, "baidu_app_id")
 		baiduSecretKey := safeGetEncryptedSetting(h, "baidu_secret_key")
 		closeToTray := safeGetSetting(h, "close_to_tray")
@@ -747,6 +850,7 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		freshrssServerUrl := safeGetSetting(h, "freshrss_server_url")
 		freshrssSyncOnStartup := safeGetSetting(h, "freshrss_sync_on_startup")
 		freshrssUsername := safeGetSetting(h, "freshrss_username")
+		fullArticleTranslation := safeGetSetting(h, "full_article_translation")
 		fullTextFetchEnabled := safeGetSetting(h, "full_text_fetch_enabled")
 		googleTranslateEndpoint := safeGetSetting(h, "google_translate_endpoint")
 		hoverMarkAsRead := safeGetSetting(h, "hover_mark_as_read")
@@ -754,6 +858,8 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		language := safeGetSetting(h, "language")
 		lastGlobalRefresh := safeGetSetting(h, "last_global_refresh")
 		lastNetworkTest := safeGetSetting(h, "last_network_test")
+		libretranslateApiKey := safeGetEncryptedSetting(h, "libretranslate_api_key")
+		libretranslateEndpoint := safeGetSetting(h, "libretranslate_endpoint")
 		maxArticleAgeDays := safeGetSetting(h, "max_article_age_days")
 		maxCacheSizeMb := safeGetSetting(h, "max_cache_size_mb")
 		maxConcurrentRefreshes := safeGetSetting(h, "max_concurrent_refreshes")
@@ -802,15 +908,25 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(map[string]string{
 			"ai_api_key":                       aiApiKey,
 			"ai_chat_enabled":                  aiChatEnabled,
+			"ai_chat_tools_enabled":            aiChatToolsEnabled,
+			"ai_classification_enabled":        aiClassificationEnabled,
+			"ai_classification_topics":         aiClassificationTopics,
 			"ai_custom_headers":                aiCustomHeaders,
+			"ai_embedding_enabled":             aiEmbeddingEnabled,
+			"ai_embedding_endpoint":            aiEmbeddingEndpoint,
+			"ai_embedding_model":               aiEmbeddingModel,
 			"ai_endpoint":                      aiEndpoint,
 			"ai_model":                         aiModel,
 			"ai_summary_prompt":                aiSummaryPrompt,
+			"ai_translation_google_fallback":   aiTranslationGoogleFallback,
 			"ai_translation_prompt":            aiTranslationPrompt,
 			"ai_usage_limit":                   aiUsageLimit,
 			"ai_usage_tokens":                  aiUsageTokens,
 			"auto_cleanup_enabled":             autoCleanupEnabled,
 			"auto_show_all_content":            autoShowAllContent,
+			"background_jobs_concurrency":      backgroundJobsConcurrency,
+			"background_summarize_categories":  backgroundSummarizeCategories,
+			"background_translate_categories":  backgroundTranslateCategories,
 			"baidu_app_id":                     baiduAppId,
 			"baidu_secret_key":                 baiduSecretKey,
 			"close_to_tray":                    closeToTray,
@@ -837,6 +953,7 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			"freshrss_server_url":              freshrssServerUrl,
 			"freshrss_sync_on_startup":         freshrssSyncOnStartup,
 			"freshrss_username":                freshrssUsername,
+			"full_article_translation":         fullArticleTranslation,
 			"full_text_fetch_enabled":          fullTextFetchEnabled,
 			"google_translate_endpoint":        googleTranslateEndpoint,
 			"hover_mark_as_read":               hoverMarkAsRead,
@@ -844,6 +961,8 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			"language":                         language,
 			"last_global_refresh":              lastGlobalRefresh,
 			"last_network_test":                lastNetworkTest,
+			"libretranslate_api_key":           libretranslateApiKey,
+			"libretranslate_endpoint":          libretranslateEndpoint,
 			"max_article_age_days":             maxArticleAgeDays,
 			"max_cache_size_mb":                maxCacheSizeMb,
 			"max_concurrent_refreshes":         maxConcurrentRefreshes,
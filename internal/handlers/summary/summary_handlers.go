@@ -2,23 +2,42 @@ package summary
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aitasks"
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/prompts"
 	"MrRSS/internal/summary"
 	"MrRSS/internal/utils"
 )
 
+// summaryTimeout bounds an AI summary, streamed or not
+const summaryTimeout = 2 * time.Minute
+
+// summarizeRequest is the request body of the summarize endpoints
+type summarizeRequest struct {
+	ArticleID int64  `json:"article_id"`
+	Length    string `json:"length"`            // "short", "medium", "long"
+	Style     string `json:"style"`             // "standard", "tldr", "bullets", "quotes", "eli5"
+	Content   string `json:"content,omitempty"` // Optional: use provided content instead of fetching from DB
+}
+
 // HandleSummarizeArticle generates a summary for an article's content.
 // @Summary      Summarize article
-// @Description  Generate a summary for an article's content (uses local algorithm or AI based on settings)
+// @Description  Generate a summary for an article's content in a style (uses local algorithm or AI based on settings). Summaries are stored per style, length, language and provider, and regenerated when the article content changed.
 // @Tags         summary
 // @Accept       json
 // @Produce      json
-// @Param        request  body      object  true  "Summarize request (article_id, length, content)"
+// @Param        request  body      object  true  "Summarize request (article_id, length, style, content)"
 // @Success      200  {object}  map[string]interface{}  "Summary result (summary, html, sentence_count, is_too_short, cached, limit_reached, thinking)"
-// @Failure      400  {object}  map[string]string  "Bad request (invalid length parameter)"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid length or style parameter)"
 // @Failure      500  {object}  map[string]string  "Internal server error"
 // @Router       /summarize [post]
 func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Request) {
@@ -27,47 +46,22 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	var req struct {
-		ArticleID int64  `json:"article_id"`
-		Length    string `json:"length"`            // "short", "medium", "long"
-		Content   string `json:"content,omitempty"` // Optional: use provided content instead of fetching from DB
-	}
-
+	var req summarizeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Validate length parameter
-	summaryLength := summary.Medium
-	switch req.Length {
-	case "short":
-		summaryLength = summary.Short
-	case "long":
-		summaryLength = summary.Long
-	case "medium", "":
-		summaryLength = summary.Medium
-	default:
+	// Validate length and style parameters
+	summaryLength, ok := parseSummaryLength(req.Length)
+	if !ok {
 		http.Error(w, "Invalid length parameter. Use 'short', 'medium', or 'long'", http.StatusBadRequest)
 		return
 	}
-
-	// Check if article already has a cached summary in database
-	// If content is provided (for on-the-fly summarization), skip this check
-	if req.Content == "" {
-		article, err := h.DB.GetArticleByID(req.ArticleID)
-		if err == nil && article.Summary != "" && article.Summary != "<no content>" {
-			// Article has a cached summary, convert it to HTML and return
-			htmlSummary := utils.ConvertMarkdownToHTML(article.Summary)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"summary":        article.Summary,
-				"html":           htmlSummary,
-				"sentence_count": 0, // We don't store this in DB
-				"is_too_short":   false,
-				"cached":         true,
-			})
-			return
-		}
+	style, ok := parseSummaryStyle(req.Style)
+	if !ok {
+		http.Error(w, "Invalid style parameter. Use 'standard', 'tldr', 'bullets', 'quotes', or 'eli5'", http.StatusBadRequest)
+		return
 	}
 
 	// Get the article content
@@ -79,18 +73,17 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	}
 
 	if content == "" {
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"summary":      "",
-			"is_too_short": true,
-			"error":        "No content available for this article",
-		})
+		json.NewEncoder(w).Encode(noContentResponse())
 		return
 	}
 
-	// Get summary provider from settings (with default)
-	provider, err := h.DB.GetSetting("summary_provider")
-	if err != nil || provider == "" {
-		provider = "local" // Default to local algorithm
+	provider := getSummaryProvider(h)
+	contentHash := summary.ContentHash(content)
+
+	// Check if the article already has a summary of the requested variant in the database
+	if cached := cachedSummaryResponse(h, req, summaryVariant(h, req, summaryLength, style, provider), contentHash); cached != nil {
+		json.NewEncoder(w).Encode(cached)
+		return
 	}
 
 	var result summary.SummaryResult
@@ -98,77 +91,265 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	limitReached := false
 
 	if provider == "ai" {
-		// Check if AI usage limit is reached - fallback to local if so
-		if h.AITracker.IsLimitReached() {
-			log.Printf("AI usage limit reached, falling back to local summarization")
-			limitReached = true
-			summarizer := summary.NewSummarizer()
-			result = summarizer.Summarize(content, summaryLength)
+		ctx, cancel := h.RequestContext(r, summaryTimeout)
+		defer cancel()
+
+		// Try the profiles routed to summaries in order
+		aiResult, err := aitasks.NewRunner(h.DB, h.AITracker).Summarize(ctx, req.ArticleID, content, summaryLength, style)
+		if r.Context().Err() != nil {
+			// The client went away, don't cache a fallback summary nobody asked for
+			return
+		}
+		if err != nil {
+			log.Printf("Error generating AI summary, falling back to local: %v", err)
+			// Fallback to local algorithm on any AI error
+			summarizer := newLocalSummarizer(h, req)
+			result = summarizer.SummarizeStyle(content, summaryLength, style)
 			usedFallback = true
+			limitReached = errors.Is(err, aiprofile.ErrLimitReached)
 		} else {
-			// Use AI summarization (API key is optional for some providers)
-			apiKey, err := h.DB.GetEncryptedSetting("ai_api_key")
-			// Some AI providers don't require API keys, so we proceed regardless
-			log.Printf("Using AI summarization (API key: %s)", func() string {
-				if apiKey != "" {
-					return "configured"
-				}
-				return "not configured (using keyless provider)"
-			}())
-
-			// Apply rate limiting for AI requests
-			h.AITracker.WaitForRateLimit()
-
-			// Get global AI settings
-			endpoint, _ := h.DB.GetSetting("ai_endpoint")
-			model, _ := h.DB.GetSetting("ai_model")
-			systemPrompt, _ := h.DB.GetSetting("ai_summary_prompt")
-			customHeaders, _ := h.DB.GetSetting("ai_custom_headers")
-			language, _ := h.DB.GetSetting("language")
-
-			aiSummarizer := summary.NewAISummarizerWithDB(apiKey, endpoint, model, h.DB)
-			if systemPrompt != "" {
-				aiSummarizer.SetSystemPrompt(systemPrompt)
-			}
-			if customHeaders != "" {
-				aiSummarizer.SetCustomHeaders(customHeaders)
-			}
-			if language != "" {
-				aiSummarizer.SetLanguage(language)
-			}
-			aiResult, err := aiSummarizer.Summarize(content, summaryLength)
-			if err != nil {
-				log.Printf("Error generating AI summary, falling back to local: %v", err)
-				// Fallback to local algorithm on any AI error
-				summarizer := summary.NewSummarizer()
-				result = summarizer.Summarize(content, summaryLength)
-				usedFallback = true
-			} else {
-				result = aiResult
-				// Track AI usage only on success
-				h.AITracker.TrackSummary(content, result.Summary)
-				// Track statistics
-				_ = h.DB.IncrementStat("ai_summary")
-			}
+			result = aiResult
 		}
 	} else {
 		// Use local algorithm
-		summarizer := summary.NewSummarizer()
-		result = summarizer.Summarize(content, summaryLength)
+		summarizer := newLocalSummarizer(h, req)
+		result = summarizer.SummarizeStyle(content, summaryLength, style)
 	}
 
-	// Cache the summary in the database
-	if err := h.DB.UpdateArticleSummary(req.ArticleID, result.Summary); err != nil {
-		log.Printf("Failed to cache summary for article %d: %v", req.ArticleID, err)
-		// Don't fail the request if caching fails
+	// Cache the summary in the database, as a local one if the AI summary failed
+	if usedFallback {
+		provider = "local"
 	}
+	saveSummary(h, req, summaryVariant(h, req, summaryLength, style, provider), contentHash, result)
 
-	// Convert markdown summary to HTML (for all summaries, not just AI)
-	htmlSummary := utils.ConvertMarkdownToHTML(result.Summary)
+	json.NewEncoder(w).Encode(summaryResponse(result, limitReached, usedFallback))
+}
+
+// HandleSummarizeArticleStream generates a summary like HandleSummarizeArticle, streaming
+// AI output as Server-Sent Events: "delta" events carry content and thinking deltas,
+// a final "done" event carries the same fields as the non-streaming response, and an
+// "error" event reports a failure after output has started.
+// @Summary      Summarize article (streaming)
+// @Description  Generate a summary and stream AI output as Server-Sent Events (delta, done, error)
+// @Tags         summary
+// @Accept       json
+// @Produce      text/event-stream
+// @Param        request  body      object  true  "Summarize request (article_id, length, style, content)"
+// @Success      200  {string}  string  "Event stream"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid length or style parameter)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/summarize/stream [post]
+func HandleSummarizeArticleStream(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req summarizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summaryLength, ok := parseSummaryLength(req.Length)
+	if !ok {
+		http.Error(w, "Invalid length parameter. Use 'short', 'medium', or 'long'", http.StatusBadRequest)
+		return
+	}
+	style, ok := parseSummaryStyle(req.Style)
+	if !ok {
+		http.Error(w, "Invalid style parameter. Use 'standard', 'tldr', 'bullets', 'quotes', or 'eli5'", http.StatusBadRequest)
+		return
+	}
+
+	content, err := getArticleContent(h, req.ArticleID, req.Content)
+	if err != nil {
+		log.Printf("Error getting article content for summary: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if content == "" {
+		core.NewSSEWriter(w).Send("done", noContentResponse())
+		return
+	}
+
+	provider := getSummaryProvider(h)
+	contentHash := summary.ContentHash(content)
+
+	if cached := cachedSummaryResponse(h, req, summaryVariant(h, req, summaryLength, style, provider), contentHash); cached != nil {
+		core.NewSSEWriter(w).Send("done", cached)
+		return
+	}
+
+	localSummary := func(limitReached, usedFallback bool) {
+		result := newLocalSummarizer(h, req).SummarizeStyle(content, summaryLength, style)
+		saveSummary(h, req, summaryVariant(h, req, summaryLength, style, "local"), contentHash, result)
+		core.NewSSEWriter(w).Send("done", summaryResponse(result, limitReached, usedFallback))
+	}
+
+	if provider != "ai" {
+		localSummary(false, false)
+		return
+	}
+	ctx, cancel := h.RequestContext(r, summaryTimeout)
+	defer cancel()
 
+	// The event stream starts with the first delta, so errors before any output
+	// can still fall back to the local algorithm
+	var events *core.SSEWriter
+	result, err := aitasks.NewRunner(h.DB, h.AITracker).SummarizeStream(ctx, req.ArticleID, content, summaryLength, style, func(chunk ai.StreamChunk) error {
+		if events == nil {
+			events = core.NewSSEWriter(w)
+		}
+		return events.Send("delta", chunk)
+	})
+	if err != nil {
+		if events == nil {
+			log.Printf("Error generating AI summary, falling back to local: %v", err)
+			localSummary(errors.Is(err, aiprofile.ErrLimitReached), true)
+			return
+		}
+		log.Printf("AI summary stream failed: %v", err)
+		events.Send("error", map[string]string{"error": "AI summary failed"})
+		return
+	}
+	if events == nil {
+		events = core.NewSSEWriter(w)
+	}
+
+	saveSummary(h, req, summaryVariant(h, req, summaryLength, style, provider), contentHash, result)
+
+	events.Send("done", summaryResponse(result, false, false))
+}
+
+// parseSummaryLength converts the length parameter, reporting whether it is valid
+func parseSummaryLength(length string) (summary.SummaryLength, bool) {
+	switch length {
+	case "short":
+		return summary.Short, true
+	case "long":
+		return summary.Long, true
+	case "medium", "":
+		return summary.Medium, true
+	default:
+		return "", false
+	}
+}
+
+// parseSummaryStyle converts the style parameter, reporting whether it is valid
+func parseSummaryStyle(style string) (summary.SummaryStyle, bool) {
+	if style == "" {
+		return summary.Standard, true
+	}
+	for _, s := range summary.Styles {
+		if string(s) == style {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// summaryVariant returns the variant of an article's summary made by provider. AI summaries are
+// written in the user's language, and apart for each prompt template; local summaries are
+// extracted from the article, in its language.
+func summaryVariant(h *core.Handler, req summarizeRequest, length summary.SummaryLength, style summary.SummaryStyle, provider string) database.SummaryVariant {
+	variant := database.SummaryVariant{Style: string(style), Length: string(length), Provider: provider}
+	if provider == "ai" {
+		variant.Language, _ = h.DB.GetSetting("language")
+		if variant.Language == "" {
+			variant.Language = "en" // The AI summarizer's default
+		}
+		variant.Provider = prompts.ForArticle(h.DB, req.ArticleID, database.PromptKindSummary).CacheProvider(provider)
+	} else if req.Content == "" {
+		if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil {
+			variant.Language = article.Language
+		}
+	}
+	return variant
+}
+
+// cachedSummaryResponse returns the response for an article's stored summary of a variant, or nil
+// if there is none or it was generated from other content than the current one.
+// If content is provided (for on-the-fly summarization), the cache is skipped.
+func cachedSummaryResponse(h *core.Handler, req summarizeRequest, variant database.SummaryVariant, contentHash string) map[string]interface{} {
+	if req.Content != "" {
+		return nil
+	}
+
+	cached := ""
+	stored, err := h.DB.GetArticleSummary(req.ArticleID, variant)
+	if err != nil {
+		log.Printf("Error getting summary of article %d: %v", req.ArticleID, err)
+		return nil
+	}
+	if stored != nil {
+		if stored.ContentHash != contentHash {
+			// The content changed since, such as after fetching the full text
+			return nil
+		}
+		cached = stored.Summary
+	} else if variant.Style == string(summary.Standard) {
+		// Summaries cached before variants were stored are only known as the article's summary
+		summaries, err := h.DB.GetArticleSummaries(req.ArticleID)
+		if err != nil || len(summaries) > 0 {
+			return nil
+		}
+		if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil {
+			cached = article.Summary
+		}
+	}
+	if cached == "" || cached == "<no content>" {
+		return nil
+	}
+
+	// Article has a cached summary, convert it to HTML and return
+	return map[string]interface{}{
+		"summary":        cached,
+		"html":           utils.ConvertMarkdownToHTML(cached),
+		"sentence_count": 0, // We don't store this in DB
+		"is_too_short":   false,
+		"cached":         true,
+	}
+}
+
+// saveSummary stores a generated summary as its variant. Standard summaries are also cached as the
+// article's summary, which is shown in the article list.
+func saveSummary(h *core.Handler, req summarizeRequest, variant database.SummaryVariant, contentHash string, result summary.SummaryResult) {
+	if variant.Style == string(summary.Standard) {
+		if err := h.DB.UpdateArticleSummary(req.ArticleID, result.Summary); err != nil {
+			// Don't fail the request if caching fails
+			log.Printf("Failed to cache summary for article %d: %v", req.ArticleID, err)
+		}
+	}
+	if result.Summary == "" {
+		return
+	}
+	if err := h.DB.SaveArticleSummary(database.ArticleSummary{
+		ArticleID:      req.ArticleID,
+		SummaryVariant: variant,
+		ContentHash:    contentHash,
+		Summary:        result.Summary,
+	}); err != nil {
+		log.Printf("Failed to store %s summary for article %d: %v", variant.Style, req.ArticleID, err)
+	}
+}
+
+// noContentResponse is returned when an article has no content to summarize
+func noContentResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"summary":      "",
+		"is_too_short": true,
+		"error":        "No content available for this article",
+	}
+}
+
+// summaryResponse builds the response for a generated summary
+func summaryResponse(result summary.SummaryResult, limitReached, usedFallback bool) map[string]interface{} {
+	// Convert markdown summary to HTML (for all summaries, not just AI)
 	response := map[string]interface{}{
 		"summary":        result.Summary,
-		"html":           htmlSummary,
+		"html":           utils.ConvertMarkdownToHTML(result.Summary),
 		"sentence_count": result.SentenceCount,
 		"is_too_short":   result.IsTooShort,
 		"limit_reached":  limitReached,
@@ -177,8 +358,29 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	if usedFallback {
 		response["used_fallback"] = true
 	}
+	return response
+}
 
-	json.NewEncoder(w).Encode(response)
+// getSummaryProvider returns the configured summary provider (with default)
+func getSummaryProvider(h *core.Handler) string {
+	provider, err := h.DB.GetSetting("summary_provider")
+	if err != nil || provider == "" {
+		return "local" // Default to local algorithm
+	}
+	return provider
+}
+
+// newLocalSummarizer creates a local summarizer for the article's content, using the language
+// detected when the article was saved unless other content was provided
+func newLocalSummarizer(h *core.Handler, req summarizeRequest) *summary.Summarizer {
+	if req.Content != "" {
+		return summary.NewSummarizer()
+	}
+	article, err := h.DB.GetArticleByID(req.ArticleID)
+	if err != nil {
+		return summary.NewSummarizer()
+	}
+	return summary.NewSummarizerForLanguage(article.Language)
 }
 
 // getArticleContent fetches the content of an article by ID, or uses provided content
@@ -193,6 +395,63 @@ func getArticleContent(h *core.Handler, articleID int64, providedContent string)
 	return content, err
 }
 
+// storedSummary is a stored summary of an article, as listed to the client
+type storedSummary struct {
+	database.ArticleSummary
+	HTML  string `json:"html"`
+	Stale bool   `json:"stale"` // Generated from other content than the article's current content
+}
+
+// HandleArticleSummaries lists the stored summaries of an article.
+// @Summary      List article summaries
+// @Description  List the stored summaries of an article in every style, length, language and provider, flagging those generated from other content than the current one as stale
+// @Tags         summary
+// @Produce      json
+// @Param        article_id  query     int64  true  "Article ID"
+// @Success      200  {array}   summary.storedSummary  "Stored summaries, most recent first"
+// @Failure      400  {object}  map[string]string  "Bad request (missing or invalid article_id)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/summaries [get]
+func HandleArticleSummaries(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("article_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article_id", http.StatusBadRequest)
+		return
+	}
+
+	summaries, err := h.DB.GetArticleSummaries(articleID)
+	if err != nil {
+		log.Printf("Error getting summaries of article %d: %v", articleID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Without content at hand, summaries can't be told stale
+	contentHash := ""
+	if len(summaries) > 0 {
+		if content, found, err := h.DB.GetArticleContent(articleID); err == nil && found {
+			contentHash = summary.ContentHash(content)
+		}
+	}
+
+	result := make([]storedSummary, 0, len(summaries))
+	for _, s := range summaries {
+		result = append(result, storedSummary{
+			ArticleSummary: s,
+			HTML:           utils.ConvertMarkdownToHTML(s.Summary),
+			Stale:          contentHash != "" && s.ContentHash != contentHash,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 // HandleClearSummaries clears all cached summaries from the database.
 // @Summary      Clear all summaries
 // @Description  Clear all cached article summaries from the database
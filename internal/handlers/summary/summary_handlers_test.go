@@ -3,10 +3,12 @@ package summary
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
@@ -114,3 +116,151 @@ func (m *mockParser) ParseURL(url string) (*gofeed.Feed, error) {
 func (m *mockParser) ParseURLWithContext(url string, ctx context.Context) (*gofeed.Feed, error) {
 	return &gofeed.Feed{Items: m.items}, nil
 }
+
+// longContent is long enough to be summarized
+var longContent = strings.Repeat("The quick brown fox jumps over the lazy dog near the river bank. ", 10)
+
+func setupStreamHandler(t *testing.T, settings map[string]string) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db init failed: %v", err)
+	}
+	for key, value := range settings {
+		if err := db.SetSetting(key, value); err != nil {
+			t.Fatalf("SetSetting(%s) failed: %v", key, err)
+		}
+	}
+	return core.NewHandler(db, feed.NewFetcher(db), nil)
+}
+
+func TestHandleSummarizeArticleStream_Local(t *testing.T) {
+	h := setupStreamHandler(t, map[string]string{"summary_provider": "local"})
+
+	payload := []byte(fmt.Sprintf(`{"article_id": 1, "length": "short", "content": %q}`, longContent))
+	rr := httptest.NewRecorder()
+	HandleSummarizeArticleStream(h, rr, httptest.NewRequest(http.MethodPost, "/articles/summarize/stream", bytes.NewReader(payload)))
+
+	if ct := rr.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected event stream, got %q", ct)
+	}
+	body := rr.Body.String()
+	if strings.Contains(body, "event: delta") || !strings.Contains(body, "event: done") {
+		t.Errorf("expected a single done event for local summaries, got %q", body)
+	}
+}
+
+func TestHandleSummarizeArticleStream_AI(t *testing.T) {
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Loopback endpoints are treated as Ollama, which streams newline-delimited JSON
+		fmt.Fprint(w, `{"response":"<think>short</think>A fox","done":false}`+"\n")
+		fmt.Fprint(w, `{"response":" jumps.","done":true}`+"\n")
+	}))
+	defer aiServer.Close()
+
+	h := setupStreamHandler(t, map[string]string{
+		"summary_provider": "ai",
+		"ai_endpoint":      aiServer.URL,
+		"ai_model":         "llama3",
+	})
+
+	payload := []byte(fmt.Sprintf(`{"article_id": 1, "length": "short", "content": %q}`, longContent))
+	rr := httptest.NewRecorder()
+	HandleSummarizeArticleStream(h, rr, httptest.NewRequest(http.MethodPost, "/articles/summarize/stream", bytes.NewReader(payload)))
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "event: delta\ndata: {\"content\":\"A fox\",\"thinking\":\"short\"}") {
+		t.Errorf("expected a delta with separated thinking, got %q", body)
+	}
+	if !strings.Contains(body, `"summary":"A fox jumps."`) {
+		t.Errorf("expected the done event to carry the full summary, got %q", body)
+	}
+}
+
+func TestHandleSummarizeArticleStream_FallbackBeforeOutput(t *testing.T) {
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer aiServer.Close()
+
+	h := setupStreamHandler(t, map[string]string{
+		"summary_provider": "ai",
+		"ai_endpoint":      aiServer.URL,
+		"ai_model":         "llama3",
+	})
+
+	payload := []byte(fmt.Sprintf(`{"article_id": 1, "length": "short", "content": %q}`, longContent))
+	rr := httptest.NewRecorder()
+	HandleSummarizeArticleStream(h, rr, httptest.NewRequest(http.MethodPost, "/articles/summarize/stream", bytes.NewReader(payload)))
+
+	body := rr.Body.String()
+	if !strings.Contains(body, "event: done") || !strings.Contains(body, `"used_fallback":true`) {
+		t.Errorf("expected a local fallback summary, got %q", body)
+	}
+}
+
+func TestHandleSummarizeArticle_StoredVariants(t *testing.T) {
+	h := setupStreamHandler(t, map[string]string{"summary_provider": "local"})
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "T", URL: "http://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	if err := h.DB.SaveArticle(&models.Article{FeedID: feedID, Title: "A", URL: "http://example.com/a", PublishedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveArticle failed: %v", err)
+	}
+	if err := h.DB.SetArticleContent(1, longContent); err != nil {
+		t.Fatalf("SetArticleContent failed: %v", err)
+	}
+
+	summarize := func(style string) map[string]interface{} {
+		t.Helper()
+		payload := []byte(fmt.Sprintf(`{"article_id": 1, "length": "short", "style": %q}`, style))
+		rr := httptest.NewRecorder()
+		HandleSummarizeArticle(h, rr, httptest.NewRequest(http.MethodPost, "/articles/summarize", bytes.NewReader(payload)))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("summarize %s: expected 200 got %d: %s", style, rr.Code, rr.Body.String())
+		}
+		var result map[string]interface{}
+		json.NewDecoder(rr.Body).Decode(&result)
+		return result
+	}
+
+	if result := summarize("bullets"); result["cached"] == true || !strings.HasPrefix(result["summary"].(string), "- ") {
+		t.Fatalf("expected a generated bulleted summary, got %v", result)
+	}
+	if result := summarize("bullets"); result["cached"] != true {
+		t.Errorf("expected the stored bulleted summary, got %v", result)
+	}
+	if result := summarize(""); result["cached"] == true || strings.HasPrefix(result["summary"].(string), "- ") {
+		t.Errorf("expected a generated standard summary, got %v", result)
+	}
+
+	// Fetching the full text makes the stored summaries stale
+	if err := h.DB.SetArticleFullText(1, longContent+" The fox then rests under the old oak tree."); err != nil {
+		t.Fatalf("SetArticleFullText failed: %v", err)
+	}
+	if result := summarize("bullets"); result["cached"] == true {
+		t.Errorf("expected the stale bulleted summary to be regenerated, got %v", result)
+	}
+
+	rr := httptest.NewRecorder()
+	HandleArticleSummaries(h, rr, httptest.NewRequest(http.MethodGet, "/articles/summaries?article_id=1", nil))
+	var summaries []storedSummary
+	json.NewDecoder(rr.Body).Decode(&summaries)
+	stale := map[string]bool{}
+	for _, s := range summaries {
+		stale[s.Style] = s.Stale
+	}
+	if len(summaries) != 2 || stale["bullets"] || !stale["standard"] {
+		t.Errorf("expected a fresh bulleted and a stale standard summary, got %+v", summaries)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleSummarizeArticle(h, rr, httptest.NewRequest(http.MethodPost, "/articles/summarize", strings.NewReader(`{"article_id": 1, "style": "haiku"}`)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown style, got %d", rr.Code)
+	}
+}
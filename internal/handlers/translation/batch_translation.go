@@ -0,0 +1,225 @@
+package translation
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"MrRSS/internal/aitasks"
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/prompts"
+	"MrRSS/internal/translation"
+)
+
+// maxBatchTitles bounds the number of titles translated in one batch request
+const maxBatchTitles = 100
+
+// HandleTranslateArticles translates the titles of several articles at once.
+// @Summary      Translate article titles in batch
+// @Description  Translate the titles of several articles to the target language in as few requests as the translation provider allows
+// @Tags         translation
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object  true  "Translation request (articles: [{article_id, title}], target_language)"
+// @Success      200  {object}  map[string]interface{}  "Translation results (translations: [{article_id, translated_title, skipped}], limit_reached)"
+// @Failure      400  {object}  map[string]string  "Bad request (missing required fields or too many articles)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/translate-batch [post]
+func HandleTranslateArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Articles []struct {
+			ArticleID int64  `json:"article_id"`
+			Title     string `json:"title"`
+		} `json:"articles"`
+		TargetLang string `json:"target_language"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Articles) == 0 || req.TargetLang == "" {
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+	if len(req.Articles) > maxBatchTitles {
+		http.Error(w, fmt.Sprintf("Too many articles, at most %d can be translated at once", maxBatchTitles), http.StatusBadRequest)
+		return
+	}
+
+	// Titles already in the target language are kept as they are
+	ids := make([]int64, len(req.Articles))
+	for i, article := range req.Articles {
+		ids[i] = article.ArticleID
+	}
+	articles := loadArticles(h, ids)
+	translated := make([]string, len(req.Articles))
+	var titles []string
+	var indexes []int
+	for i, article := range req.Articles {
+		if article.Title == "" {
+			continue
+		}
+		if !shouldTranslate(articles[article.ArticleID].Language, article.Title, req.TargetLang) {
+			translated[i] = article.Title
+			continue
+		}
+		titles = append(titles, article.Title)
+		indexes = append(indexes, i)
+	}
+
+	var limitReached bool
+	if len(titles) > 0 {
+		provider, _ := h.DB.GetSetting("translation_provider")
+
+		var results []string
+		var err error
+		if provider == "ai" {
+			articleIDs := make([]int64, len(indexes))
+			for j, i := range indexes {
+				articleIDs[j] = req.Articles[i].ArticleID
+			}
+			results, limitReached, err = translateTitlesWithAI(h, r, titles, translationPrompts(h, articles, articleIDs), req.TargetLang)
+			if r.Context().Err() != nil {
+				// The client went away, nobody is waiting for the translations
+				return
+			}
+		} else {
+			results, err = translation.TranslateBatch(r.Context(), h.Translator, titles, req.TargetLang)
+		}
+
+		if err != nil {
+			log.Printf("Error translating %d article titles: %v", len(titles), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for j, i := range indexes {
+			translated[i] = results[j]
+		}
+	}
+
+	type titleTranslation struct {
+		ArticleID       int64  `json:"article_id"`
+		TranslatedTitle string `json:"translated_title"`
+		Skipped         bool   `json:"skipped"` // The title was already in the target language
+	}
+	translations := make([]titleTranslation, 0, len(req.Articles))
+	for i, article := range req.Articles {
+		if translated[i] == "" {
+			continue
+		}
+		if err := h.DB.UpdateArticleTranslation(article.ArticleID, translated[i]); err != nil {
+			log.Printf("Error updating article translation: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		translations = append(translations, titleTranslation{
+			ArticleID:       article.ArticleID,
+			TranslatedTitle: translated[i],
+			Skipped:         translated[i] == article.Title,
+		})
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"translations":  translations,
+		"limit_reached": limitReached,
+	})
+}
+
+// loadArticles returns articles keyed by ID. Articles are missing when they can't be loaded.
+func loadArticles(h *core.Handler, ids []int64) map[int64]models.Article {
+	byID := make(map[int64]models.Article, len(ids))
+	articles, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		log.Printf("Error getting articles: %v", err)
+		return byID
+	}
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+	return byID
+}
+
+// translationPrompts returns the translation prompt template applying to each article, nil for
+// the articles without one
+func translationPrompts(h *core.Handler, articles map[int64]models.Article, ids []int64) []*prompts.Prompt {
+	byFeed := make(map[int64]*prompts.Prompt)
+	result := make([]*prompts.Prompt, len(ids))
+	for i, id := range ids {
+		article, ok := articles[id]
+		if !ok {
+			continue
+		}
+		prompt, ok := byFeed[article.FeedID]
+		if !ok {
+			if feed, err := h.DB.GetFeedByID(article.FeedID); err == nil {
+				prompt = prompts.ForFeed(h.DB, feed, database.PromptKindTranslation, prompts.Vars{Feed: feed.Title})
+			}
+			byFeed[article.FeedID] = prompt
+		}
+		result[i] = prompt
+	}
+	return result
+}
+
+// translateTitlesWithAI translates titles like translateBatchWithAI, in one batch per distinct
+// translation prompt. It reports whether an AI usage limit caused a fallback.
+func translateTitlesWithAI(h *core.Handler, r *http.Request, titles []string, titlePrompts []*prompts.Prompt, targetLang string) ([]string, bool, error) {
+	type batch struct {
+		prompt  *prompts.Prompt
+		indexes []int
+	}
+	var batches []*batch
+	byPrompt := make(map[string]*batch)
+	for i, prompt := range titlePrompts {
+		// Templates using {{feed}} give a different prompt per feed
+		key := ""
+		if prompt != nil {
+			key = prompt.CacheProvider("ai") + "\n" + prompt.Render(targetLang)
+		}
+		b, ok := byPrompt[key]
+		if !ok {
+			b = &batch{prompt: prompt}
+			byPrompt[key] = b
+			batches = append(batches, b)
+		}
+		b.indexes = append(b.indexes, i)
+	}
+
+	results := make([]string, len(titles))
+	var limitReached bool
+	for _, b := range batches {
+		texts := make([]string, len(b.indexes))
+		for j, i := range b.indexes {
+			texts[j] = titles[i]
+		}
+		translated, reached, err := translateBatchWithAI(h, r, texts, targetLang, b.prompt)
+		if err != nil {
+			return nil, false, err
+		}
+		limitReached = limitReached || reached
+		for j, i := range b.indexes {
+			results[i] = translated[j]
+		}
+	}
+	return results, limitReached, nil
+}
+
+// translateBatchWithAI translates texts with the AI profiles routed to translation, in failover
+// order. It reports whether an AI usage limit caused the fallback to Google Translate.
+func translateBatchWithAI(h *core.Handler, r *http.Request, texts []string, targetLang string, prompt *prompts.Prompt) ([]string, bool, error) {
+	ctx, cancel := h.RequestContext(r, translationTimeout)
+	defer cancel()
+
+	translated, outcome, err := aitasks.NewRunner(h.DB, h.AITracker).WithGoogleFallback().TranslateBatch(ctx, texts, targetLang, prompt)
+	return translated, outcome.LimitReached, err
+}
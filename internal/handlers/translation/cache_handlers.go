@@ -0,0 +1,257 @@
+package translation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/translation"
+)
+
+// maxTMXImportSize caps the size of imported TMX documents
+const maxTMXImportSize = 64 << 20
+
+// HandleTranslationCacheStats returns the size and hit rate of the translation cache.
+// @Summary      Get translation cache statistics
+// @Description  Get the number of cached translations, manual corrections, hits, hit rate and size per provider and target language. The hit rate counts lookups that found no cached translation as misses.
+// @Tags         translation
+// @Produce      json
+// @Success      200  {array}   models.TranslationCacheStats  "Cache statistics"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/cache [get]
+func HandleTranslationCacheStats(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats, err := h.DB.GetTranslationCacheStats()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(stats)
+}
+
+// HandleTranslationCacheEntries lists (GET) or corrects (POST) cached translations.
+// @Summary      List or correct cached translations
+// @Description  GET returns cached translations, most recent first, filtered by provider, target language and a text searched in the source and translation. POST replaces a cached translation with a manual correction, which is kept when the text is translated again.
+// @Tags         translation
+// @Accept       json
+// @Produce      json
+// @Param        provider  query     string  false  "Translation provider (GET only)"
+// @Param        lang      query     string  false  "Target language (GET only)"
+// @Param        q         query     string  false  "Text to search for (GET only)"
+// @Param        limit     query     int     false  "Maximum number of entries (GET only, default 50)"
+// @Param        offset    query     int     false  "Number of entries to skip (GET only)"
+// @Param        request   body      object  false  "Correction with id and translated_text (POST only)"
+// @Success      200  {object}  map[string]interface{}  "Cached translations (GET) or success (POST)"
+// @Failure      400  {object}  map[string]string  "Bad request (missing id or translation)"
+// @Failure      404  {object}  map[string]string  "Entry not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/cache/entries [get]
+// @Router       /translation/cache/entries [post]
+func HandleTranslationCacheEntries(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil || limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if offset < 0 {
+			offset = 0
+		}
+
+		entries, err := h.DB.GetTranslationCacheEntries(query.Get("provider"), query.Get("lang"), query.Get("q"), limit, offset)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(entries)
+
+	case http.MethodPost:
+		var req struct {
+			ID             int64  `json:"id"`
+			TranslatedText string `json:"translated_text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.ID <= 0 || strings.TrimSpace(req.TranslatedText) == "" {
+			http.Error(w, "An entry ID and a translation are required", http.StatusBadRequest)
+			return
+		}
+
+		err := h.DB.UpdateCachedTranslation(req.ID, req.TranslatedText)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Entry not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error correcting cached translation %d: %v", req.ID, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDeleteTranslationCache deletes cached translations.
+// @Summary      Delete cached translations
+// @Description  Delete one cached translation by ID, or every cached translation of a provider and/or target language, manual corrections included. Without parameters the whole cache is cleared.
+// @Tags         translation
+// @Produce      json
+// @Param        id        query     int64   false  "Entry ID"
+// @Param        provider  query     string  false  "Translation provider"
+// @Param        lang      query     string  false  "Target language"
+// @Success      200  {object}  map[string]interface{}  "Number of deleted translations"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid entry ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/cache/delete [post]
+func HandleDeleteTranslationCache(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if idStr := query.Get("id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+			return
+		}
+		if err := h.DB.DeleteCachedTranslation(id); err != nil {
+			log.Printf("Error deleting cached translation %d: %v", id, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "deleted": 1})
+		return
+	}
+
+	deleted, err := h.DB.DeleteTranslationCache(query.Get("provider"), query.Get("lang"))
+	if err != nil {
+		log.Printf("Error deleting cached translations: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "deleted": deleted})
+}
+
+// HandleExportTranslationCache exports cached translations as TMX.
+// @Summary      Export translation cache
+// @Description  Export the cached translations of a provider and/or target language, or the whole cache, as a TMX 1.4 document
+// @Tags         translation
+// @Produce      xml
+// @Param        provider  query     string  false  "Translation provider"
+// @Param        lang      query     string  false  "Target language"
+// @Success      200  {string}  string  "TMX document"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/cache/export [get]
+func HandleExportTranslationCache(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	entries, err := h.DB.GetTranslationCacheEntries(query.Get("provider"), query.Get("lang"), "", 0, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-tmx+xml")
+	w.Header().Set("Content-Disposition", "attachment; filename=translation-cache.tmx")
+	if err := translation.WriteTMX(w, entries); err != nil {
+		log.Printf("Error exporting translation cache: %v", err)
+	}
+}
+
+// HandleImportTranslationCache imports cached translations from TMX.
+// @Summary      Import translation cache
+// @Description  Import translations from a TMX document, uploaded as the file form field or as the raw body. Translations are cached for the provider given as parameter, else the one recorded in the document, else the current provider. Manual corrections in the cache are kept.
+// @Tags         translation
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file      formData  file    false  "TMX file to import"
+// @Param        provider  query     string  false  "Provider to cache the translations for"
+// @Success      200  {object}  map[string]interface{}  "Number of translations read and imported"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid TMX document)"
+// @Failure      413  {object}  map[string]string  "TMX document larger than 64 MB"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/cache/import [post]
+func HandleImportTranslationCache(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxTMXImportSize)
+	var file io.Reader = r.Body
+	if strings.Contains(r.Header.Get("Content-Type"), "multipart/form-data") {
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			writeTMXImportError(w, err)
+			return
+		}
+		defer f.Close()
+		file = f
+	}
+
+	entries, err := translation.ReadTMX(file)
+	if err != nil {
+		writeTMXImportError(w, err)
+		return
+	}
+
+	provider := r.URL.Query().Get("provider")
+	current, _ := h.DB.GetSetting("translation_provider")
+	if current == "" {
+		current = "google"
+	}
+	for i := range entries {
+		if provider != "" {
+			entries[i].Provider = provider
+		} else if entries[i].Provider == "" {
+			entries[i].Provider = current
+		}
+	}
+
+	imported, err := h.DB.ImportCachedTranslations(entries)
+	if err != nil {
+		log.Printf("Error importing translation cache: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success":  true,
+		"read":     len(entries),
+		"imported": imported,
+	})
+}
+
+// writeTMXImportError writes the error of reading an uploaded TMX document, telling apart
+// documents larger than maxTMXImportSize from invalid ones
+func writeTMXImportError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, fmt.Sprintf("TMX document is larger than the limit of %d MB", maxTMXImportSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
@@ -0,0 +1,168 @@
+package translation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"MrRSS/internal/aitasks"
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/prompts"
+	"MrRSS/internal/translation"
+)
+
+// contentTranslationTimeout bounds the translation of an article's body, which takes several requests
+const contentTranslationTimeout = 5 * time.Minute
+
+// HandleTranslateArticleContent translates an article's body segment by segment.
+// @Summary      Translate article content
+// @Description  Translate the cached content of an article block by block, preserving markup and links. Segments are cached so that re-fetched articles reuse prior work, and the translated body is stored until the content changes.
+// @Tags         translation
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object  true  "Translation request (article_id, target_language, force)"
+// @Success      200  {object}  map[string]interface{}  "Translation result (content, bilingual, segments, translated, cached, skipped, limit_reached)"
+// @Failure      400  {object}  map[string]string  "Bad request (missing required fields)"
+// @Failure      404  {object}  map[string]string  "Article content not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /articles/translate-content [post]
+func HandleTranslateArticleContent(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID  int64  `json:"article_id"`
+		TargetLang string `json:"target_language"`
+		Force      bool   `json:"force"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.ArticleID == 0 || req.TargetLang == "" {
+		http.Error(w, "Missing required fields", http.StatusBadRequest)
+		return
+	}
+
+	content, _, err := h.GetArticleContent(req.ArticleID)
+	if err != nil {
+		log.Printf("Error getting content of article %d: %v", req.ArticleID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if content == "" {
+		http.Error(w, "Article content not found", http.StatusNotFound)
+		return
+	}
+
+	// Articles already in the target language are returned as they are
+	if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil && translation.SameLanguage(article.Language, req.TargetLang) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"content":       content,
+			"bilingual":     content,
+			"cached":        false,
+			"skipped":       true,
+			"limit_reached": false,
+		})
+		return
+	}
+
+	provider, _ := h.DB.GetSetting("translation_provider")
+	sourceHash := hashContent(content)
+
+	// AI translations are stored apart for each prompt template
+	useAI := provider == "ai"
+	var prompt *prompts.Prompt
+	if useAI {
+		prompt = prompts.ForArticle(h.DB, req.ArticleID, database.PromptKindTranslation)
+		provider = prompt.CacheProvider(provider)
+	}
+
+	// Reuse the stored translation while the content and provider are unchanged
+	if !req.Force {
+		stored, err := h.DB.GetArticleTranslation(req.ArticleID, req.TargetLang)
+		if err != nil {
+			log.Printf("Error getting translation of article %d: %v", req.ArticleID, err)
+		} else if stored != nil && stored.SourceHash == sourceHash && stored.Provider == provider {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"content":       stored.Content,
+				"bilingual":     stored.Bilingual,
+				"cached":        true,
+				"limit_reached": false,
+			})
+			return
+		}
+	}
+
+	var result *translation.ArticleTranslation
+	var limitReached bool
+
+	if useAI {
+		result, provider, limitReached, err = translateContentWithAI(h, r, content, req.TargetLang, req.Force, prompt)
+		if r.Context().Err() != nil {
+			// The client went away, nobody is waiting for the translation
+			return
+		}
+	} else {
+		segmentTranslator := translation.NewSegmentTranslator(h.Translator, h.DB, provider)
+		segmentTranslator.SetRefresh(req.Force)
+		result, err = segmentTranslator.TranslateHTML(r.Context(), content, req.TargetLang)
+	}
+
+	if err != nil {
+		log.Printf("Error translating content of article %d: %v", req.ArticleID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Stored with the provider that made it, so a fallback translation is not reused as an AI one
+	if err := h.DB.SaveArticleTranslation(database.ArticleTranslation{
+		ArticleID:  req.ArticleID,
+		TargetLang: req.TargetLang,
+		SourceHash: sourceHash,
+		Provider:   provider,
+		Content:    result.Content,
+		Bilingual:  result.Bilingual,
+	}); err != nil {
+		// The translation is still returned, it is only translated again next time
+		log.Printf("Error saving translation of article %d: %v", req.ArticleID, err)
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"content":       result.Content,
+		"bilingual":     result.Bilingual,
+		"segments":      result.Segments,
+		"translated":    result.Translated,
+		"cached":        false,
+		"limit_reached": limitReached,
+	})
+}
+
+// translateContentWithAI translates an article's body with the AI profiles routed to translation,
+// in failover order. It also returns the provider the translation is stored under, and reports
+// whether an AI usage limit caused the fallback to Google Translate.
+func translateContentWithAI(h *core.Handler, r *http.Request, content, targetLang string, refresh bool, prompt *prompts.Prompt) (*translation.ArticleTranslation, string, bool, error) {
+	ctx, cancel := h.RequestContext(r, contentTranslationTimeout)
+	defer cancel()
+
+	result, outcome, err := aitasks.NewRunner(h.DB, h.AITracker).WithGoogleFallback().TranslateHTML(ctx, content, targetLang, refresh, prompt)
+	provider := outcome.Provider
+	if provider == aitasks.ProviderAI {
+		provider = prompt.CacheProvider(provider)
+	}
+	return result, provider, outcome.LimitReached, err
+}
+
+// hashContent returns the hash of an article's content, to tell when a stored translation is stale
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
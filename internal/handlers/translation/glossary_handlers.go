@@ -0,0 +1,101 @@
+package translation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+)
+
+// HandleGlossary lists (GET) or saves (POST) translation glossary entries.
+// @Summary      List or save glossary entries
+// @Description  GET returns the translation glossary. POST creates an entry, or updates it when id is set; cached translations of texts containing the term are invalidated.
+// @Tags         translation
+// @Accept       json
+// @Produce      json
+// @Param        entry  body      models.GlossaryEntry  false  "Entry to save (POST only)"
+// @Success      200  {object}  map[string]interface{}  "Glossary entries (GET) or saved entry ID (POST)"
+// @Failure      400  {object}  map[string]string  "Bad request (missing term or translation)"
+// @Failure      404  {object}  map[string]string  "Entry not found"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/glossary [get]
+// @Router       /translation/glossary [post]
+func HandleGlossary(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		entries, err := h.DB.GetGlossaryEntries()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(entries)
+
+	case http.MethodPost:
+		var entry models.GlossaryEntry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if strings.TrimSpace(entry.Term) == "" {
+			http.Error(w, "Term is required", http.StatusBadRequest)
+			return
+		}
+		if !entry.Protected && (strings.TrimSpace(entry.Translation) == "" || entry.TargetLang == "") {
+			http.Error(w, "Translated terms need a translation and a target language", http.StatusBadRequest)
+			return
+		}
+
+		id, err := h.DB.SaveGlossaryEntry(&entry)
+		if err == sql.ErrNoRows {
+			http.Error(w, "Entry not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Printf("Error saving glossary entry: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"id":      id,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDeleteGlossaryEntry deletes a translation glossary entry.
+// @Summary      Delete glossary entry
+// @Description  Delete a translation glossary entry by ID, invalidating cached translations of texts containing its term
+// @Tags         translation
+// @Produce      json
+// @Param        id   query     int64   true  "Entry ID"
+// @Success      200  {object}  map[string]bool  "Success"
+// @Failure      400  {object}  map[string]string  "Bad request (invalid entry ID)"
+// @Failure      500  {object}  map[string]string  "Internal server error"
+// @Router       /translation/glossary/delete [post]
+func HandleDeleteGlossaryEntry(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid entry ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.DeleteGlossaryEntry(id); err != nil {
+		log.Printf("Error deleting glossary entry %d: %v", id, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
@@ -4,13 +4,20 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
+	"MrRSS/internal/aitasks"
 	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/prompts"
 	"MrRSS/internal/translation"
 	"MrRSS/internal/utils"
 )
 
+// translationTimeout bounds a single translation request
+const translationTimeout = 2 * time.Minute
+
 // HandleTranslateArticle translates an article's title.
 // @Summary      Translate article title
 // @Description  Translate an article's title to the target language (uses AI or Google based on settings)
@@ -45,8 +52,11 @@ func HandleTranslateArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	}
 
 	// Step 1: Pre-translation language detection to avoid unnecessary API calls
-	detector := translation.GetLanguageDetector()
-	if !detector.ShouldTranslate(req.Title, req.TargetLang) {
+	var language string
+	if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil {
+		language = article.Language
+	}
+	if !shouldTranslate(language, req.Title, req.TargetLang) {
 		// Text is already in target language, return original title
 		log.Printf("Article %d title is already in target language %s, skipping translation", req.ArticleID, req.TargetLang)
 		if err := h.DB.UpdateArticleTranslation(req.ArticleID, req.Title); err != nil {
@@ -72,31 +82,11 @@ func HandleTranslateArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	var limitReached = false
 
 	if isAIProvider {
-		// Check if AI usage limit is reached
-		if h.AITracker.IsLimitReached() {
-			log.Printf("AI usage limit reached, falling back to Google Translate")
-			limitReached = true
-			// Fallback to Google Translate
-			googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
-			translatedTitle, err = translation.TranslateMarkdownPreservingStructure(req.Title, googleTranslator, req.TargetLang)
-		} else {
-			// Apply rate limiting for AI requests
-			h.AITracker.WaitForRateLimit()
-
-			// Use markdown-preserving translation for better list structure
-			translatedTitle, err = translation.TranslateMarkdownAIPrompt(req.Title, h.Translator, req.TargetLang)
-
-			// If AI fails, fallback to Google Translate
-			if err != nil {
-				log.Printf("AI translation failed, falling back to Google Translate: %v", err)
-				googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
-				translatedTitle, err = translation.TranslateMarkdownPreservingStructure(req.Title, googleTranslator, req.TargetLang)
-			}
-
-			// Track AI usage only on success (whether AI or fallback)
-			if err == nil {
-				h.AITracker.TrackTranslation(req.Title, translatedTitle)
-			}
+		prompt := prompts.ForArticle(h.DB, req.ArticleID, database.PromptKindTranslation)
+		translatedTitle, limitReached, err = translateWithAI(h, r, req.Title, req.TargetLang, prompt)
+		if r.Context().Err() != nil {
+			// The client went away, nobody is waiting for the translation
+			return
 		}
 	} else {
 		// Non-AI provider, use markdown-preserving translation
@@ -226,30 +216,10 @@ func HandleTranslateText(h *core.Handler, w http.ResponseWriter, r *http.Request
 	var err error
 
 	if isAIProvider {
-		// Check if AI usage limit is reached
-		if h.AITracker.IsLimitReached() {
-			log.Printf("AI usage limit reached, falling back to Google Translate")
-			// Fallback to Google Translate
-			googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
-			translatedText, err = translation.TranslateMarkdownPreservingStructure(req.Text, googleTranslator, req.TargetLang)
-		} else {
-			// Apply rate limiting for AI requests
-			h.AITracker.WaitForRateLimit()
-
-			// Use markdown-preserving translation for better list structure
-			translatedText, err = translation.TranslateMarkdownAIPrompt(req.Text, h.Translator, req.TargetLang)
-
-			// If AI fails, fallback to Google Translate
-			if err != nil {
-				log.Printf("AI translation failed, falling back to Google Translate: %v", err)
-				googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
-				translatedText, err = translation.TranslateMarkdownPreservingStructure(req.Text, googleTranslator, req.TargetLang)
-			}
-
-			// Track AI usage only on success (whether AI or fallback)
-			if err == nil {
-				h.AITracker.TrackTranslation(req.Text, translatedText)
-			}
+		translatedText, _, err = translateWithAI(h, r, req.Text, req.TargetLang, nil)
+		if r.Context().Err() != nil {
+			// The client went away, nobody is waiting for the translation
+			return
 		}
 	} else {
 		// Non-AI provider, use markdown-preserving translation
@@ -285,6 +255,27 @@ func HandleTranslateText(h *core.Handler, w http.ResponseWriter, r *http.Request
 	})
 }
 
+// shouldTranslate reports whether an article's text needs translating to targetLang, using the
+// language detected when the article was saved and detecting the text's language if there is none
+func shouldTranslate(articleLanguage, text, targetLang string) bool {
+	if articleLanguage != "" {
+		return !translation.SameLanguage(articleLanguage, targetLang)
+	}
+	return translation.GetLanguageDetector().ShouldTranslate(text, targetLang)
+}
+
+// translateWithAI translates text with the AI profiles routed to translation, in failover order,
+// following the prompt template when it is set. It reports whether an AI usage limit caused the
+// fallback to Google Translate.
+func translateWithAI(h *core.Handler, r *http.Request, text, targetLang string, prompt *prompts.Prompt) (string, bool, error) {
+	// Cancel the AI request if the client goes away or the app shuts down
+	ctx, cancel := h.RequestContext(r, translationTimeout)
+	defer cancel()
+
+	translated, outcome, err := aitasks.NewRunner(h.DB, h.AITracker).WithGoogleFallback().Translate(ctx, text, targetLang, prompt)
+	return translated, outcome.LimitReached, err
+}
+
 // HandleResetAIUsage resets the AI usage counter.
 // @Summary      Reset AI usage counter
 // @Description  Reset the AI usage token counter to zero
@@ -312,11 +303,11 @@ func HandleResetAIUsage(h *core.Handler, w http.ResponseWriter, r *http.Request)
 
 // HandleGetAIUsage returns the current AI usage statistics.
 // @Summary      Get AI usage statistics
-// @Description  Get current AI usage (tokens used, limit, and whether limit is reached)
+// @Description  Get current AI usage (tokens used, limit, whether limit is reached, and daily/monthly budgets with their current usage)
 // @Tags         translation
 // @Accept       json
 // @Produce      json
-// @Success      200  {object}  map[string]interface{}  "AI usage stats (usage, limit, limit_reached)"
+// @Success      200  {object}  map[string]interface{}  "AI usage stats (usage, limit, limit_reached, budgets)"
 // @Router       /ai/usage [get]
 func HandleGetAIUsage(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -326,11 +317,16 @@ func HandleGetAIUsage(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 
 	usage, _ := h.AITracker.GetCurrentUsage()
 	limit, _ := h.AITracker.GetUsageLimit()
+	budgets, err := h.AITracker.BudgetStatuses()
+	if err != nil {
+		log.Printf("Error getting AI budgets: %v", err)
+	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"usage":         usage,
 		"limit":         limit,
 		"limit_reached": h.AITracker.IsLimitReached(),
+		"budgets":       budgets,
 	})
 }
 
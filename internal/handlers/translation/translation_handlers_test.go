@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/cache"
 	"MrRSS/internal/database"
 	corepkg "MrRSS/internal/handlers/core"
 	transpkg "MrRSS/internal/translation"
@@ -128,3 +132,178 @@ func TestHandleClearTranslations(t *testing.T) {
 		t.Fatalf("expected 0 translations remaining, got %d", count)
 	}
 }
+
+func TestHandleTranslateArticleContent(t *testing.T) {
+	db := setupDB(t)
+
+	res, err := db.Exec("INSERT INTO articles (feed_id, title, url, published_at) VALUES (1, 't', 'u', datetime('now'))")
+	if err != nil {
+		t.Fatalf("insert article failed: %v", err)
+	}
+	id, _ := res.LastInsertId()
+	if err := db.SetArticleContent(id, `<p>This is the first paragraph in English with <a href="https://example.com">a link</a></p>`); err != nil {
+		t.Fatalf("SetArticleContent failed: %v", err)
+	}
+
+	h := &corepkg.Handler{DB: db, Translator: transpkg.NewMockTranslator(), ContentCache: cache.NewContentCache(10, time.Minute)}
+
+	translate := func() map[string]interface{} {
+		t.Helper()
+		b, _ := json.Marshal(map[string]interface{}{"article_id": id, "target_language": "es"})
+		req := httptest.NewRequest(http.MethodPost, "/api/articles/translate-content", bytes.NewReader(b))
+		rr := httptest.NewRecorder()
+		HandleTranslateArticleContent(h, rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := translate()
+	expected := `<p>[ES] This is the first paragraph in English with <a href="https://example.com">a link</a></p>`
+	if resp["content"] != expected || resp["cached"] != false {
+		t.Fatalf("unexpected translation: %v", resp)
+	}
+	if bilingual, _ := resp["bilingual"].(string); !strings.Contains(bilingual, `<div class="translation-text">[ES] This is`) {
+		t.Errorf("unexpected bilingual content: %v", bilingual)
+	}
+
+	// The stored translation is returned while the content is unchanged
+	if resp := translate(); resp["content"] != expected || resp["cached"] != true {
+		t.Errorf("expected the stored translation, got %v", resp)
+	}
+}
+
+func TestHandleTranslateArticleContent_IgnoresFallbackTranslation(t *testing.T) {
+	aiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"message": map[string]string{"role": "assistant", "content": "Este es el primer párrafo"},
+			"done":    true,
+		})
+	}))
+	defer aiServer.Close()
+
+	db := setupDB(t)
+	db.SetSetting("translation_provider", "ai")
+	db.SetSetting("ai_endpoint", aiServer.URL)
+	db.SetSetting("ai_model", "test")
+
+	res, err := db.Exec("INSERT INTO articles (feed_id, title, url, published_at) VALUES (1, 't', 'u', datetime('now'))")
+	if err != nil {
+		t.Fatalf("insert article failed: %v", err)
+	}
+	id, _ := res.LastInsertId()
+	content := `<p>This is the first paragraph in English</p>`
+	if err := db.SetArticleContent(id, content); err != nil {
+		t.Fatalf("SetArticleContent failed: %v", err)
+	}
+	// A translation made by the Google Translate fallback is not served as an AI translation
+	if err := db.SaveArticleTranslation(database.ArticleTranslation{
+		ArticleID: id, TargetLang: "es", SourceHash: hashContent(content), Provider: "google", Content: "<p>Google</p>",
+	}); err != nil {
+		t.Fatalf("SaveArticleTranslation failed: %v", err)
+	}
+
+	h := &corepkg.Handler{DB: db, AITracker: aiusage.NewTracker(db), ContentCache: cache.NewContentCache(10, time.Minute)}
+	b, _ := json.Marshal(map[string]interface{}{"article_id": id, "target_language": "es"})
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/translate-content", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+	HandleTranslateArticleContent(h, rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if resp["content"] != "<p>Este es el primer párrafo</p>" || resp["cached"] != false {
+		t.Fatalf("expected an AI translation, got %v", resp)
+	}
+
+	stored, err := db.GetArticleTranslation(id, "es")
+	if err != nil || stored == nil || stored.Provider != "ai" {
+		t.Errorf("expected the AI translation to be stored, got %+v, %v", stored, err)
+	}
+}
+
+func TestHandleTranslateArticles(t *testing.T) {
+	db := setupDB(t)
+
+	if _, err := db.Exec("INSERT INTO feeds (id, title, url, description) VALUES (1, 'Feed', 'https://example.com/feed', '')"); err != nil {
+		t.Fatalf("insert feed failed: %v", err)
+	}
+	var ids []int64
+	for i, language := range []string{"en", "", "es", ""} {
+		res, err := db.Exec("INSERT INTO articles (feed_id, title, url, published_at, language) VALUES (1, 't', ?, datetime('now'), ?)", i, language)
+		if err != nil {
+			t.Fatalf("insert article failed: %v", err)
+		}
+		id, _ := res.LastInsertId()
+		ids = append(ids, id)
+	}
+
+	h := &corepkg.Handler{DB: db, Translator: transpkg.NewMockTranslator()}
+
+	body := map[string]interface{}{
+		"target_language": "es",
+		"articles": []map[string]interface{}{
+			{"article_id": ids[0], "title": "This is the first article title in English"},
+			{"article_id": ids[1], "title": ""},
+			{"article_id": ids[2], "title": "Barcelona 2 - 1 Madrid"},
+			{"article_id": ids[3], "title": "This is another article title in English"},
+		},
+	}
+	b, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/translate-batch", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	HandleTranslateArticles(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Translations []struct {
+			ArticleID       int64  `json:"article_id"`
+			TranslatedTitle string `json:"translated_title"`
+			Skipped         bool   `json:"skipped"`
+		} `json:"translations"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	// The article detected in Spanish is skipped without detecting its title again
+	if len(resp.Translations) != 3 || resp.Translations[1].ArticleID != ids[2] || !resp.Translations[1].Skipped ||
+		resp.Translations[2].ArticleID != ids[3] ||
+		resp.Translations[2].TranslatedTitle != "[ES] This is another article title in English" {
+		t.Fatalf("unexpected translations: %+v", resp.Translations)
+	}
+
+	var stored string
+	if err := db.QueryRow("SELECT translated_title FROM articles WHERE id = ?", ids[0]).Scan(&stored); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if stored != "[ES] This is the first article title in English" {
+		t.Fatalf("db value mismatch: got %v", stored)
+	}
+}
+
+func TestHandleImportTranslationCache_TooLarge(t *testing.T) {
+	h := &corepkg.Handler{DB: setupDB(t)}
+
+	body := `<tmx version="1.4"><body>` + strings.Repeat(" ", maxTMXImportSize) + `</body></tmx>`
+	req := httptest.NewRequest(http.MethodPost, "/translation/cache/import", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	HandleImportTranslationCache(h, rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,399 @@
+// Package jobs translates the titles and summarizes new articles in the background.
+//
+// When articles of feeds or categories that opted in are saved, a job per article and task
+// is queued in the database, so work left over at shutdown resumes on the next start. A
+// worker runs due jobs a few at a time. AI requests wait for the usage tracker's rate limit,
+// jobs are deferred while a usage limit or budget is reached, and jobs that fail are retried
+// with exponential backoff until they run out of attempts. Summary jobs of articles whose
+// content is not cached yet are retried a few hours later.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aitasks"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/prompts"
+	"MrRSS/internal/summary"
+	"MrRSS/internal/translation"
+)
+
+const (
+	// defaultConcurrency is the number of jobs run at once unless configured otherwise
+	defaultConcurrency = 2
+	// maxConcurrency caps the configured concurrency
+	maxConcurrency = 8
+	// maxAttempts is the number of times a job is tried before it is marked as failed
+	maxAttempts = 5
+	// baseRetryDelay is the delay before the first retry, doubled for every further one
+	baseRetryDelay = time.Minute
+	// maxRetryDelay caps the delay between retries
+	maxRetryDelay = time.Hour
+	// limitDelay is how long jobs wait while an AI usage limit or budget is reached
+	limitDelay = time.Hour
+	// noContentDelay is how long summary jobs wait for an article's content to be cached
+	noContentDelay = 6 * time.Hour
+	// jobTimeout bounds a single job
+	jobTimeout = 2 * time.Minute
+	// progressJobs caps the running and failed jobs listed in the progress
+	progressJobs = 10
+)
+
+// errNoContent is returned by summary jobs of articles whose content is not cached yet
+var errNoContent = errors.New("article content is not cached yet")
+
+// Worker queues and runs background jobs
+type Worker struct {
+	db         *database.DB
+	translator translation.Translator // Translator for non-AI providers
+	ai         *aitasks.Runner
+	wake       chan struct{}
+}
+
+// Progress describes the background job queue
+type Progress struct {
+	Pending  int64                         `json:"pending"`
+	Running  int64                         `json:"running"`
+	Failed   int64                         `json:"failed"`
+	Counts   []database.BackgroundJobCount `json:"counts"`   // Per kind and status
+	Active   []database.BackgroundJob      `json:"active"`   // Jobs running now
+	Failures []database.BackgroundJob      `json:"failures"` // Most recent failed jobs
+}
+
+// NewWorker creates a background job worker. Titles are translated with translator unless the
+// translation provider is AI, and AI usage is recorded with tracker.
+func NewWorker(db *database.DB, translator translation.Translator, tracker *aiusage.Tracker) *Worker {
+	return &Worker{
+		db:         db,
+		translator: translator,
+		ai:         aitasks.NewRunner(db, tracker),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// EnqueueArticles queues the background jobs for newly saved articles of a feed, depending on
+// the feed's and its category's opt-ins. It returns the number of jobs queued.
+func (w *Worker) EnqueueArticles(feed models.Feed, articles []models.Article) (int, error) {
+	if len(articles) == 0 {
+		return 0, nil
+	}
+
+	var translate, summarize []int64
+	translateOn := w.settingEnabled("translation_enabled") &&
+		(feed.BackgroundTranslate || InCategories(feed.Category, w.setting("background_translate_categories")))
+	summarizeOn := w.settingEnabled("summary_enabled") &&
+		(feed.BackgroundSummarize || InCategories(feed.Category, w.setting("background_summarize_categories")))
+	for _, article := range articles {
+		if translateOn && article.TranslatedTitle == "" && article.Title != "" {
+			translate = append(translate, article.ID)
+		}
+		if summarizeOn && article.Summary == "" {
+			summarize = append(summarize, article.ID)
+		}
+	}
+
+	queued, err := w.db.EnqueueBackgroundJobs(database.JobTranslateTitle, translate)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.db.EnqueueBackgroundJobs(database.JobSummarize, summarize)
+	queued += n
+	if queued > 0 {
+		w.Wake()
+	}
+	return int(queued), err
+}
+
+// InCategories reports whether a feed category is one of a list of categories, one per line
+// or separated by commas, or a subcategory of one
+func InCategories(category, list string) bool {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return false
+	}
+	for _, c := range strings.FieldsFunc(list, func(r rune) bool { return r == '\n' || r == ',' }) {
+		c = strings.ToLower(strings.Trim(strings.TrimSpace(c), "/"))
+		if c != "" && (category == c || strings.HasPrefix(category, c+"/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Wake makes a running worker look for due jobs now instead of at its next interval
+func (w *Worker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run resumes jobs interrupted by the last shutdown, then runs due jobs whenever jobs are
+// queued and every interval until ctx is done
+func (w *Worker) Run(ctx context.Context, interval time.Duration) {
+	if n, err := w.db.ResetRunningBackgroundJobs(); err != nil {
+		log.Printf("Failed to resume background jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("Resuming %d interrupted background jobs", n)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n := w.RunPending(ctx); n > 0 {
+			log.Printf("Finished %d background jobs", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+// RunPending runs the jobs that are due, a few at a time, until none are left.
+// It returns the number of jobs that finished.
+func (w *Worker) RunPending(ctx context.Context) int {
+	finished := 0
+	for ctx.Err() == nil {
+		jobs, err := w.db.ClaimBackgroundJobs(w.concurrency())
+		if err != nil {
+			log.Printf("Failed to claim background jobs: %v", err)
+			break
+		}
+		if len(jobs) == 0 {
+			break
+		}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, job := range jobs {
+			wg.Add(1)
+			go func(job database.BackgroundJob) {
+				defer wg.Done()
+				jobCtx, cancel := context.WithTimeout(ctx, jobTimeout)
+				err := w.run(jobCtx, job)
+				cancel()
+				if w.finish(ctx, job, err) {
+					mu.Lock()
+					finished++
+					mu.Unlock()
+				}
+			}(job)
+		}
+		wg.Wait()
+	}
+	return finished
+}
+
+// Progress returns the state of the job queue
+func (w *Worker) Progress() (Progress, error) {
+	var p Progress
+	counts, err := w.db.GetBackgroundJobCounts()
+	if err != nil {
+		return p, err
+	}
+	p.Counts = counts
+	for _, c := range counts {
+		switch c.Status {
+		case database.JobPending:
+			p.Pending += c.Count
+		case database.JobRunning:
+			p.Running += c.Count
+		case database.JobFailed:
+			p.Failed += c.Count
+		}
+	}
+	if p.Active, err = w.db.GetBackgroundJobs(database.JobRunning, progressJobs); err != nil {
+		return p, err
+	}
+	if p.Failures, err = w.db.GetBackgroundJobs(database.JobFailed, progressJobs); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// finish removes a job that succeeded and puts one that didn't back in the queue, to retry
+// later or as failed when it is out of attempts. It reports whether the job finished.
+func (w *Worker) finish(ctx context.Context, job database.BackgroundJob, err error) bool {
+	var updateErr error
+	switch {
+	case err == nil:
+		updateErr = w.db.CompleteBackgroundJob(job.ID)
+	case ctx.Err() != nil:
+		// Interrupted by shutdown, not the job's fault
+		updateErr = w.db.RescheduleBackgroundJob(job.ID, job.Attempts, job.LastError, time.Now())
+	case errors.Is(err, aiprofile.ErrLimitReached):
+		updateErr = w.db.RescheduleBackgroundJob(job.ID, job.Attempts, err.Error(), time.Now().Add(limitDelay))
+	default:
+		attempts := job.Attempts + 1
+		delay := RetryDelay(attempts)
+		if errors.Is(err, errNoContent) {
+			delay = noContentDelay
+		}
+		if attempts >= maxAttempts {
+			log.Printf("Background job %s for article %d failed: %v", job.Kind, job.ArticleID, err)
+			updateErr = w.db.FailBackgroundJob(job.ID, attempts, err.Error())
+		} else {
+			updateErr = w.db.RescheduleBackgroundJob(job.ID, attempts, err.Error(), time.Now().Add(delay))
+		}
+	}
+	if updateErr != nil {
+		log.Printf("Failed to update background job %d: %v", job.ID, updateErr)
+	}
+	return err == nil
+}
+
+// RetryDelay returns the delay before retrying a job that has failed attempts times
+func RetryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// run runs a job. Jobs of deleted articles, or whose work was done in the meantime, succeed.
+func (w *Worker) run(ctx context.Context, job database.BackgroundJob) error {
+	article, err := w.db.GetArticleByID(job.ArticleID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch job.Kind {
+	case database.JobTranslateTitle:
+		return w.translateTitle(ctx, article)
+	case database.JobSummarize:
+		return w.summarize(ctx, article)
+	default:
+		return fmt.Errorf("unknown background job kind %q", job.Kind)
+	}
+}
+
+// translateTitle translates an article's title to the target language, like the title
+// translation requested by the client
+func (w *Worker) translateTitle(ctx context.Context, article *models.Article) error {
+	targetLang := w.setting("target_language")
+	if article.TranslatedTitle != "" || article.Title == "" || targetLang == "" || !w.settingEnabled("translation_enabled") {
+		return nil
+	}
+
+	if !needsTranslation(article, targetLang) {
+		// Titles already in the target language are kept as they are
+		return w.db.UpdateArticleTranslation(article.ID, article.Title)
+	}
+
+	var translated string
+	var err error
+	if w.setting("translation_provider") == "ai" {
+		prompt := prompts.ForArticle(w.db, article.ID, database.PromptKindTranslation)
+		translated, _, err = w.ai.Translate(ctx, article.Title, targetLang, prompt)
+	} else {
+		translated, err = translation.TranslateMarkdownPreservingStructure(article.Title, translation.BindContext(ctx, w.translator), targetLang)
+	}
+	if err != nil {
+		return err
+	}
+	return w.db.UpdateArticleTranslation(article.ID, translated)
+}
+
+// needsTranslation reports whether an article's title needs translating to targetLang, using the
+// language detected when the article was saved and detecting the title's language if there is none
+func needsTranslation(article *models.Article, targetLang string) bool {
+	if article.Language != "" {
+		return !translation.SameLanguage(article.Language, targetLang)
+	}
+	return translation.GetLanguageDetector().ShouldTranslate(article.Title, targetLang)
+}
+
+// summarize generates the summary of an article from its cached content, like the summary
+// requested by the client. Articles without cached content are retried later, as their content
+// is cached when they are opened or their full text is fetched.
+func (w *Worker) summarize(ctx context.Context, article *models.Article) error {
+	if article.Summary != "" || !w.settingEnabled("summary_enabled") {
+		return nil
+	}
+	content, found, err := w.db.GetArticleContent(article.ID)
+	if err != nil {
+		return err
+	}
+	if !found || strings.TrimSpace(content) == "" {
+		return errNoContent
+	}
+
+	length := summaryLength(w.setting("summary_length"))
+	variant := database.SummaryVariant{Style: string(summary.Standard), Length: string(length)}
+	var result summary.SummaryResult
+	if w.setting("summary_provider") == "ai" {
+		if result, err = w.ai.Summarize(ctx, article.ID, content, length, summary.Standard); err != nil {
+			return err
+		}
+		variant.Language = w.setting("language")
+		if variant.Language == "" {
+			variant.Language = "en"
+		}
+		variant.Provider = prompts.ForArticle(w.db, article.ID, database.PromptKindSummary).CacheProvider("ai")
+	} else {
+		result = summary.NewSummarizerForLanguage(article.Language).Summarize(content, length)
+		variant.Language, variant.Provider = article.Language, "local"
+	}
+	if result.Summary == "" {
+		return nil
+	}
+	if err := w.db.SaveArticleSummary(database.ArticleSummary{
+		ArticleID:      article.ID,
+		SummaryVariant: variant,
+		ContentHash:    summary.ContentHash(content),
+		Summary:        result.Summary,
+	}); err != nil {
+		return err
+	}
+	return w.db.UpdateArticleSummary(article.ID, result.Summary)
+}
+
+// summaryLength converts the summary_length setting, medium by default
+func summaryLength(setting string) summary.SummaryLength {
+	switch setting {
+	case "short":
+		return summary.Short
+	case "long":
+		return summary.Long
+	default:
+		return summary.Medium
+	}
+}
+
+// concurrency returns the configured number of jobs run at once
+func (w *Worker) concurrency() int {
+	n, err := strconv.Atoi(w.setting("background_jobs_concurrency"))
+	if err != nil || n < 1 {
+		return defaultConcurrency
+	}
+	return min(n, maxConcurrency)
+}
+
+func (w *Worker) setting(key string) string {
+	value, _ := w.db.GetSetting(key)
+	return value
+}
+
+func (w *Worker) settingEnabled(key string) bool {
+	return w.setting(key) == "true"
+}
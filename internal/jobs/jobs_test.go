@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+// fakeTranslator prefixes texts with the target language, or fails while err is set
+type fakeTranslator struct {
+	calls int
+	err   error
+}
+
+func (f *fakeTranslator) Translate(text, targetLang string) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	return targetLang + ": " + text, nil
+}
+
+func setupWorker(t *testing.T) (*Worker, *database.DB, *fakeTranslator) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	db.SetSetting("translation_enabled", "true")
+	db.SetSetting("translation_provider", "google")
+	db.SetSetting("target_language", "de")
+	db.SetSetting("summary_enabled", "true")
+	db.SetSetting("summary_provider", "local")
+
+	translator := &fakeTranslator{}
+	return NewWorker(db, translator, aiusage.NewTracker(db)), db, translator
+}
+
+func saveArticles(t *testing.T, db *database.DB, feed *models.Feed, titles ...string) []models.Article {
+	t.Helper()
+	feedID, err := db.AddFeed(feed)
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	feed.ID = feedID
+	if err := db.SetFeedBackgroundJobs(feedID, feed.BackgroundTranslate, feed.BackgroundSummarize); err != nil {
+		t.Fatalf("SetFeedBackgroundJobs error: %v", err)
+	}
+	for i, title := range titles {
+		if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: title, URL: fmt.Sprintf("%s/%d", feed.URL, i), PublishedAt: time.Now(), Language: "en"}); err != nil {
+			t.Fatalf("SaveArticle error: %v", err)
+		}
+	}
+	articles, err := db.GetArticles("", feedID, "", false, len(titles), 0)
+	if err != nil || len(articles) != len(titles) {
+		t.Fatalf("expected %d articles, got %d (%v)", len(titles), len(articles), err)
+	}
+	return articles
+}
+
+func TestInCategories(t *testing.T) {
+	tests := []struct {
+		category, list string
+		want           bool
+	}{
+		{"Tech", "tech", true},
+		{"Tech/AI", "News, Tech", true},
+		{"Technology", "Tech", false},
+		{"", "Tech", false},
+		{"Tech", "", false},
+		{"Tech/AI", "news\ntech/ai/", true},
+	}
+	for _, tt := range tests {
+		if got := InCategories(tt.category, tt.list); got != tt.want {
+			t.Errorf("InCategories(%q, %q) = %v, want %v", tt.category, tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	if RetryDelay(1) != time.Minute || RetryDelay(3) != 4*time.Minute || RetryDelay(20) != time.Hour {
+		t.Errorf("unexpected retry delays: %v %v %v", RetryDelay(1), RetryDelay(3), RetryDelay(20))
+	}
+}
+
+func TestWorker_EnqueueAndRun(t *testing.T) {
+	w, db, translator := setupWorker(t)
+	db.SetSetting("background_summarize_categories", "Science")
+
+	optedIn := saveArticles(t, db, &models.Feed{Title: "Space", URL: "https://example.com/space", Category: "Science/Space", BackgroundTranslate: true}, "Rocket launch")
+	other := saveArticles(t, db, &models.Feed{Title: "Other", URL: "https://example.com/other"}, "Ignored")
+
+	if n, _ := w.EnqueueArticles(models.Feed{}, other); n != 0 {
+		t.Errorf("expected no jobs for a feed that didn't opt in, got %d", n)
+	}
+	feed, _ := db.GetFeedByID(optedIn[0].FeedID)
+	n, err := w.EnqueueArticles(*feed, optedIn)
+	if err != nil || n != 2 {
+		t.Fatalf("expected a translation and a summary job, got %d (%v)", n, err)
+	}
+
+	content := strings.Repeat("The rocket launched from the coast this morning. ", 20)
+	if err := db.SetArticleContent(optedIn[0].ID, content); err != nil {
+		t.Fatalf("SetArticleContent error: %v", err)
+	}
+
+	if finished := w.RunPending(context.Background()); finished != 2 {
+		t.Fatalf("expected 2 finished jobs, got %d", finished)
+	}
+	article, _ := db.GetArticleByID(optedIn[0].ID)
+	if article.TranslatedTitle != "de: Rocket launch" || article.Summary == "" {
+		t.Errorf("expected a translated title and a summary, got %q and %q", article.TranslatedTitle, article.Summary)
+	}
+	if translator.calls != 1 {
+		t.Errorf("expected 1 translation, got %d", translator.calls)
+	}
+
+	progress, err := w.Progress()
+	if err != nil || progress.Pending != 0 || progress.Failed != 0 {
+		t.Errorf("expected an empty queue, got %+v (%v)", progress, err)
+	}
+}
+
+func TestWorker_RetriesProviderErrors(t *testing.T) {
+	w, db, translator := setupWorker(t)
+	articles := saveArticles(t, db, &models.Feed{Title: "News", URL: "https://example.com/news", BackgroundTranslate: true}, "Headline")
+	feed, _ := db.GetFeedByID(articles[0].FeedID)
+	if _, err := w.EnqueueArticles(*feed, articles); err != nil {
+		t.Fatalf("EnqueueArticles error: %v", err)
+	}
+
+	translator.err = errors.New("provider returned status: 503")
+	if finished := w.RunPending(context.Background()); finished != 0 {
+		t.Fatalf("expected the job not to finish, got %d", finished)
+	}
+
+	// The job waits for its retry delay before it runs again
+	pending, err := db.GetBackgroundJobs(database.JobPending, 10)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected the job to be queued again, got %+v (%v)", pending, err)
+	}
+	job := pending[0]
+	if job.Attempts != 1 || !strings.Contains(job.LastError, "503") || time.Until(job.RunAfter) < 50*time.Second {
+		t.Errorf("expected a retry after backoff, got %+v", job)
+	}
+	if finished := w.RunPending(context.Background()); finished != 0 || translator.calls != 1 {
+		t.Errorf("expected the job to wait for its retry delay, got %d calls", translator.calls)
+	}
+
+	// A job out of attempts is marked as failed
+	job.Attempts = maxAttempts - 1
+	if w.finish(context.Background(), job, translator.err) {
+		t.Error("expected a failed job not to be reported as finished")
+	}
+	progress, _ := w.Progress()
+	if progress.Failed != 1 || len(progress.Failures) != 1 || progress.Failures[0].ArticleTitle != "Headline" {
+		t.Errorf("expected the job to be failed, got %+v", progress)
+	}
+}
+
+func TestWorker_WaitsForContentToSummarize(t *testing.T) {
+	w, db, _ := setupWorker(t)
+	articles := saveArticles(t, db, &models.Feed{Title: "News", URL: "https://example.com/news", BackgroundSummarize: true}, "Headline")
+	feed, _ := db.GetFeedByID(articles[0].FeedID)
+	if _, err := w.EnqueueArticles(*feed, articles); err != nil {
+		t.Fatalf("EnqueueArticles error: %v", err)
+	}
+
+	if finished := w.RunPending(context.Background()); finished != 0 {
+		t.Fatalf("expected the job not to finish without content, got %d", finished)
+	}
+	pending, err := db.GetBackgroundJobs(database.JobPending, 10)
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("expected the job to be queued again, got %+v (%v)", pending, err)
+	}
+	if job := pending[0]; job.LastError != errNoContent.Error() || time.Until(job.RunAfter) < noContentDelay-time.Minute {
+		t.Errorf("expected the job to wait for the article's content, got %+v", job)
+	}
+}
+
+func TestWorker_ResumesInterruptedJobs(t *testing.T) {
+	w, db, _ := setupWorker(t)
+	articles := saveArticles(t, db, &models.Feed{Title: "News", URL: "https://example.com/news", BackgroundTranslate: true}, "Headline")
+	feed, _ := db.GetFeedByID(articles[0].FeedID)
+	if _, err := w.EnqueueArticles(*feed, articles); err != nil {
+		t.Fatalf("EnqueueArticles error: %v", err)
+	}
+
+	// A job claimed by a run that never finished, as after a crash
+	if jobs, err := db.ClaimBackgroundJobs(10); err != nil || len(jobs) != 1 {
+		t.Fatalf("expected a claimed job, got %+v (%v)", jobs, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx, time.Hour)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		article, _ := db.GetArticleByID(articles[0].ID)
+		if article.TranslatedTitle == "de: Headline" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the interrupted job to be resumed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}
@@ -0,0 +1,127 @@
+// Package keywords extracts key phrases from articles, offline, and stores the best ones per
+// article for trending terms and keyword rules.
+//
+// Phrases are extracted with summary.KeyPhrases from an article's title and content: RAKE for
+// languages separating words with spaces, TextRank over tokens for Chinese, Japanese and Thai.
+package keywords
+
+import (
+	"fmt"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/summary"
+)
+
+const (
+	// keywordsPerArticle is the number of keywords stored per article
+	keywordsPerArticle = 10
+	// maxTextChars caps the text key phrases are extracted from per article
+	maxTextChars = 20000
+)
+
+// Extract returns the key phrases of a document, best first
+func Extract(d database.KeywordDocument) []database.ArticleKeyword {
+	text := summary.PlainText(d.Content)
+	if runes := []rune(text); len(runes) > maxTextChars {
+		text = string(runes[:maxTextChars])
+	}
+	// The title is its own sentence so that its phrases don't run into the content
+	text = strings.TrimSpace(d.Title) + ".\n" + text
+
+	phrases := summary.NewSummarizerForLanguage(d.Language).KeyPhrases(text, keywordsPerArticle)
+	keywords := make([]database.ArticleKeyword, len(phrases))
+	for i, p := range phrases {
+		keywords[i] = database.ArticleKeyword{Keyword: p.Phrase, Score: p.Score}
+	}
+	return keywords
+}
+
+// Service extracts and stores the keywords of articles
+type Service struct {
+	db *database.DB
+}
+
+// NewService creates a keywords service
+func NewService(db *database.DB) *Service {
+	return &Service{db: db}
+}
+
+// ExtractArticles extracts and stores the keywords of articles and sets their Keywords,
+// returning how many articles have keywords
+func (s *Service) ExtractArticles(articles []models.Article) (int, error) {
+	if len(articles) == 0 {
+		return 0, nil
+	}
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	documents, err := s.db.GetKeywordDocuments(ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles for keywords: %w", err)
+	}
+	extracted, err := s.extract(documents)
+	if err != nil {
+		return 0, err
+	}
+	for i := range articles {
+		if keywords, ok := extracted[articles[i].ID]; ok {
+			articles[i].Keywords = keywordStrings(keywords)
+		}
+	}
+	return len(extracted), nil
+}
+
+// ExtractArticle extracts and stores the keywords of one article
+func (s *Service) ExtractArticle(articleID int64) ([]database.ArticleKeyword, error) {
+	documents, err := s.db.GetKeywordDocuments([]int64{articleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load article for keywords: %w", err)
+	}
+	extracted, err := s.extract(documents)
+	if err != nil {
+		return nil, err
+	}
+	if keywords, ok := extracted[articleID]; ok {
+		return keywords, nil
+	}
+	return []database.ArticleKeyword{}, nil
+}
+
+// ExtractMissing extracts the keywords of up to limit articles that have none yet, newest
+// first, returning how many articles have keywords
+func (s *Service) ExtractMissing(limit int) (int, error) {
+	documents, err := s.db.GetArticlesWithoutKeywords(limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load articles without keywords: %w", err)
+	}
+	extracted, err := s.extract(documents)
+	return len(extracted), err
+}
+
+// extract extracts and stores the keywords of documents, keyed by article ID. Documents
+// without any key phrase are left out.
+func (s *Service) extract(documents []database.KeywordDocument) (map[int64][]database.ArticleKeyword, error) {
+	extracted := make(map[int64][]database.ArticleKeyword, len(documents))
+	for _, d := range documents {
+		keywords := Extract(d)
+		if len(keywords) == 0 {
+			continue
+		}
+		if err := s.db.SaveArticleKeywords(d.ArticleID, keywords); err != nil {
+			return extracted, err
+		}
+		extracted[d.ArticleID] = keywords
+	}
+	return extracted, nil
+}
+
+func keywordStrings(keywords []database.ArticleKeyword) []string {
+	values := make([]string, len(keywords))
+	for i, k := range keywords {
+		values[i] = k.Keyword
+	}
+	return values
+}
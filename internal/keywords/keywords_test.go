@@ -0,0 +1,28 @@
+package keywords
+
+import (
+	"testing"
+
+	"MrRSS/internal/database"
+)
+
+func TestExtract(t *testing.T) {
+	keywords := Extract(database.KeywordDocument{
+		ArticleID: 1,
+		Language:  "en",
+		Title:     "Solid state batteries near production",
+		Content:   `<p>Toyota says its <b>solid state batteries</b> will reach production in 2027.</p><p>Solid state batteries promise faster charging for electric cars.</p>`,
+	})
+	if len(keywords) == 0 || keywords[0].Keyword != "solid state batteries" || keywords[0].Score != 1 {
+		t.Fatalf("expected \"solid state batteries\" first, got %+v", keywords)
+	}
+	for _, k := range keywords {
+		if k.Keyword == "production toyota" || k.Keyword == "2027" {
+			t.Errorf("expected the title not to run into the content and no numbers, got %q", k.Keyword)
+		}
+	}
+
+	if keywords := Extract(database.KeywordDocument{ArticleID: 2}); len(keywords) != 0 {
+		t.Errorf("expected no keywords for an empty article, got %+v", keywords)
+	}
+}
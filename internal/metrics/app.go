@@ -0,0 +1,48 @@
+package metrics
+
+// Application-wide metric families. Gauges (queue sizes, database size, etc.)
+// are computed at scrape time by the /metrics handler instead of being stored here.
+var (
+	// FeedFetchTotal counts feed fetch attempts by feed type and result ("success" or "error")
+	FeedFetchTotal = NewCounterVec(
+		"mrrss_feed_fetch_total",
+		"Total number of feed fetch attempts.",
+		"type", "result",
+	)
+
+	// FeedFetchDuration observes how long feed fetch attempts take, by feed type
+	FeedFetchDuration = NewHistogramVec(
+		"mrrss_feed_fetch_duration_seconds",
+		"Duration of feed fetch attempts in seconds.",
+		DefaultBuckets,
+		"type",
+	)
+
+	// ArticlesSavedTotal counts articles newly inserted into the database
+	ArticlesSavedTotal = NewCounterVec(
+		"mrrss_articles_saved_total",
+		"Total number of new articles saved to the database.",
+	)
+
+	// AIRequestsTotal counts AI requests by feature ("chat", "summary", "translation")
+	AIRequestsTotal = NewCounterVec(
+		"mrrss_ai_requests_total",
+		"Total number of AI requests.",
+		"feature",
+	)
+
+	// AITokensTotal accumulates AI token usage by feature and whether the counts are estimated
+	// ("true") or reported by the provider ("false")
+	AITokensTotal = NewCounterVec(
+		"mrrss_ai_tokens_total",
+		"Total AI tokens consumed, as reported by the provider or estimated when it reported none.",
+		"feature", "estimated",
+	)
+
+	// TranslationCacheLookupsTotal counts translation cache lookups by provider and result ("hit" or "miss")
+	TranslationCacheLookupsTotal = NewCounterVec(
+		"mrrss_translation_cache_lookups_total",
+		"Total number of translation cache lookups.",
+		"provider", "result",
+	)
+)
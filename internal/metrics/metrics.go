@@ -0,0 +1,312 @@
+// Package metrics provides lightweight Prometheus-compatible instrumentation.
+// Counters and histograms are registered on a package-level registry and rendered
+// in the Prometheus text exposition format by the /metrics endpoint.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the default histogram buckets (in seconds) used for latencies
+var DefaultBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// collector is implemented by every metric family that can render itself
+type collector interface {
+	metricName() string
+	write(w io.Writer)
+}
+
+// Registry holds a set of metric families
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]collector
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Default is the registry used by package-level constructors
+var Default = NewRegistry()
+
+// register adds a collector, returning the existing one if the name is taken
+func (r *Registry) register(c collector) collector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.collectors[c.metricName()]; ok {
+		return existing
+	}
+	r.collectors[c.metricName()] = c
+	return c
+}
+
+// WriteText renders all registered metric families sorted by name
+func (r *Registry) WriteText(w io.Writer) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.RUnlock()
+
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec creates a counter and registers it on the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// NewCounterVec creates a counter and registers it on the registry
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]float64),
+	}
+	return r.register(c).(*CounterVec)
+}
+
+// Inc increments the counter for the given label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increments the counter for the given label values by delta (negative values are ignored)
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := joinLabelValues(labelValues)
+
+	c.mu.Lock()
+	c.values[key] += delta
+	c.mu.Unlock()
+}
+
+// Value returns the current value for the given label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[joinLabelValues(labelValues)]
+}
+
+// Sum returns the total of all label combinations whose label at index matches value.
+// An index of -1 sums every series.
+func (c *CounterVec) Sum(index int, value string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var total float64
+	for key, v := range c.values {
+		if index < 0 {
+			total += v
+			continue
+		}
+		if parts := splitLabelValues(key); index < len(parts) && parts[index] == value {
+			total += v
+		}
+	}
+	return total
+}
+
+func (c *CounterVec) metricName() string { return c.name }
+
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	keys := sortedKeys(c.values)
+	values := make([]float64, len(keys))
+	for i, key := range keys {
+		values[i] = c.values[key]
+	}
+	c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for i, key := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, splitLabelValues(key), "", ""), formatValue(values[i]))
+	}
+}
+
+// HistogramVec samples observations into cumulative buckets partitioned by labels
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // Per-bucket counts (non-cumulative)
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram and registers it on the default registry
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewHistogramVec creates a histogram and registers it on the registry
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+	return r.register(h).(*HistogramVec)
+}
+
+// Observe records a single observation for the given label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := joinLabelValues(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, upper := range h.buckets {
+		if value <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) metricName() string { return h.name }
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		labelValues := splitLabelValues(key)
+
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, labelValues, "le", formatValue(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, labelValues, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, labelValues, "", ""), s.count)
+	}
+}
+
+// WriteGauge renders a single unlabelled gauge sample.
+// Gauges are computed at scrape time by the caller rather than stored in the registry.
+func WriteGauge(w io.Writer, name, help string, value float64) {
+	writeHeader(w, name, help, "gauge")
+	fmt.Fprintf(w, "%s %s\n", name, formatValue(value))
+}
+
+// labelSeparator joins label values into a map key; it cannot appear in valid UTF-8 text
+const labelSeparator = "\xff"
+
+func joinLabelValues(values []string) string {
+	return strings.Join(values, labelSeparator)
+}
+
+func splitLabelValues(key string) []string {
+	return strings.Split(key, labelSeparator)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// formatLabels renders {name="value",...}, optionally appending an extra label (used for "le")
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	var parts []string
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(value)))
+	}
+	if extraName != "" {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func escapeHelp(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func escapeLabelValue(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
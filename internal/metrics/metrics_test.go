@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec_WriteText(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_requests_total", "Test requests.", "kind", "result")
+
+	c.Inc("rss", "success")
+	c.Inc("rss", "success")
+	c.Add(3, "email", "error")
+	c.Add(-1, "email", "error") // ignored
+
+	if got := c.Value("rss", "success"); got != 2 {
+		t.Errorf("Value(rss, success) = %v, want 2", got)
+	}
+	if got := c.Sum(1, "error"); got != 3 {
+		t.Errorf("Sum(error) = %v, want 3", got)
+	}
+	if got := c.Sum(-1, ""); got != 5 {
+		t.Errorf("Sum(all) = %v, want 5", got)
+	}
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# HELP test_requests_total Test requests.",
+		"# TYPE test_requests_total counter",
+		`test_requests_total{kind="email",result="error"} 3`,
+		`test_requests_total{kind="rss",result="success"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCounterVec_Unlabelled(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounterVec("test_saved_total", "Saved.")
+	c.Add(4)
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	if !strings.Contains(buf.String(), "test_saved_total 4\n") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestHistogramVec_WriteText(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("test_duration_seconds", "Durations.", []float64{1, 5}, "kind")
+
+	h.Observe(0.5, "rss")
+	h.Observe(3, "rss")
+	h.Observe(10, "rss")
+
+	var buf bytes.Buffer
+	r.WriteText(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE test_duration_seconds histogram",
+		`test_duration_seconds_bucket{kind="rss",le="1"} 1`,
+		`test_duration_seconds_bucket{kind="rss",le="5"} 2`,
+		`test_duration_seconds_bucket{kind="rss",le="+Inf"} 3`,
+		`test_duration_seconds_sum{kind="rss"} 13.5`,
+		`test_duration_seconds_count{kind="rss"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistry_DuplicateNameReturnsExisting(t *testing.T) {
+	r := NewRegistry()
+	a := r.NewCounterVec("dup_total", "First.")
+	b := r.NewCounterVec("dup_total", "Second.")
+	if a != b {
+		t.Fatal("expected registering the same name twice to return the existing counter")
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	got := escapeLabelValue("a\"b\\c\nd")
+	want := `a\"b\\c\nd`
+	if got != want {
+		t.Errorf("escapeLabelValue = %q, want %q", got, want)
+	}
+}
+
+func TestWriteGauge(t *testing.T) {
+	var buf bytes.Buffer
+	WriteGauge(&buf, "test_queue_size", "Queue size.", 7)
+	want := "# HELP test_queue_size Queue size.\n# TYPE test_queue_size gauge\ntest_queue_size 7\n"
+	if buf.String() != want {
+		t.Errorf("WriteGauge output = %q, want %q", buf.String(), want)
+	}
+}
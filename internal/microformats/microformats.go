@@ -0,0 +1,302 @@
+// Package microformats parses IndieWeb h-feed/h-entry microformats2 markup
+// from HTML pages into gofeed items.
+//
+// Only the properties that map onto feed items are supported (name, url, uid,
+// published, updated, summary, content, author, photo and category), which is
+// enough for the blogs that publish h-feed markup instead of an RSS feed.
+package microformats
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// FeedType is the feed type of subscriptions whose articles are read from h-entry markup on an
+// HTML page, and of discovered blogs that only publish such markup
+const FeedType = "HTML+Microformats"
+
+// ErrNoEntries is returned when a page does not contain any h-entry elements
+var ErrNoEntries = errors.New("no h-entry elements found")
+
+// maxImpliedNameLength limits names derived from the entry text when p-name is missing
+const maxImpliedNameLength = 100
+
+// nestedRootSelector matches microformat roots whose properties belong to them and
+// not to the enclosing entry (comments, replies, author cards, ...)
+const nestedRootSelector = ".h-entry, .h-cite, .h-card, .h-event, .h-feed"
+
+// timeLayouts are the date formats accepted for dt-published and dt-updated
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse reads an HTML page and returns its h-feed as a gofeed.Feed.
+// pageURL is used to resolve relative links.
+func Parse(r io.Reader, pageURL string) (*gofeed.Feed, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(doc, pageURL)
+}
+
+// HasEntries reports whether the document contains h-entry markup
+func HasEntries(doc *goquery.Document) bool {
+	return doc.Find(".h-entry").Length() > 0
+}
+
+// ParseDocument converts the h-feed of an already parsed HTML document into a gofeed.Feed.
+// Pages without an explicit h-feed root are treated as an implied feed of all
+// top-level h-entry elements.
+func ParseDocument(doc *goquery.Document, pageURL string) (*gofeed.Feed, error) {
+	base, _ := url.Parse(pageURL)
+	if base != nil {
+		// Respect <base href> like a browser would
+		if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+			if baseHref, err := base.Parse(href); err == nil {
+				base = baseHref
+			}
+		}
+	}
+
+	root := doc.Selection
+	feed := &gofeed.Feed{
+		Link:     pageURL,
+		FeedType: "h-feed",
+		Items:    make([]*gofeed.Item, 0),
+	}
+
+	if hfeed := doc.Find(".h-feed").First(); hfeed.Length() > 0 {
+		root = hfeed
+		feed.Title = textValue(findProperty(hfeed, "p-name"))
+		feed.Description = textValue(findProperty(hfeed, "p-summary"))
+		if author := findProperty(hfeed, "p-author"); author.Length() > 0 {
+			feed.Authors = []*gofeed.Person{parseAuthor(author, base)}
+		}
+		if photo := findProperty(hfeed, "u-photo"); photo.Length() > 0 {
+			feed.Image = &gofeed.Image{URL: urlValue(photo, base)}
+		}
+	}
+	if feed.Title == "" {
+		feed.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+
+	root.Find(".h-entry").Each(func(_ int, entry *goquery.Selection) {
+		// Nested entries (e.g. replies inside a post) are part of their parent
+		if entry.ParentsFiltered(".h-entry").Length() > 0 {
+			return
+		}
+		feed.Items = append(feed.Items, parseEntry(entry, base))
+	})
+
+	if len(feed.Items) == 0 {
+		return nil, ErrNoEntries
+	}
+	return feed, nil
+}
+
+// parseEntry converts a single h-entry element into a gofeed.Item
+func parseEntry(entry *goquery.Selection, base *url.URL) *gofeed.Item {
+	item := &gofeed.Item{}
+
+	if content := findProperty(entry, "e-content"); content.Length() > 0 {
+		if html, err := content.Html(); err == nil {
+			item.Content = strings.TrimSpace(html)
+		}
+	}
+	item.Description = textValue(findProperty(entry, "p-summary"))
+
+	if link := findProperty(entry, "u-url"); link.Length() > 0 {
+		item.Link = urlValue(link, base)
+	} else if a := entry.Find("a[href]").First(); a.Length() > 0 {
+		// Implied u-url: the first link of the entry
+		item.Link = urlValue(a, base)
+	}
+
+	item.Title = textValue(findProperty(entry, "p-name"))
+	if item.Title == "" {
+		item.Title = impliedName(entry, item)
+	}
+
+	if uid := findProperty(entry, "u-uid"); uid.Length() > 0 {
+		item.GUID = urlValue(uid, base)
+	}
+	if item.GUID == "" {
+		item.GUID = item.Link
+	}
+
+	if published := findProperty(entry, "dt-published"); published.Length() > 0 {
+		item.Published = datetimeValue(published)
+		item.PublishedParsed = parseTime(item.Published)
+	}
+	if updated := findProperty(entry, "dt-updated"); updated.Length() > 0 {
+		item.Updated = datetimeValue(updated)
+		item.UpdatedParsed = parseTime(item.Updated)
+	}
+
+	if author := findProperty(entry, "p-author"); author.Length() > 0 {
+		person := parseAuthor(author, base)
+		item.Author = person
+		item.Authors = []*gofeed.Person{person}
+	}
+
+	if photo := findProperty(entry, "u-photo"); photo.Length() > 0 {
+		item.Image = &gofeed.Image{URL: urlValue(photo, base)}
+	}
+
+	findProperties(entry, "p-category").Each(func(_ int, category *goquery.Selection) {
+		if value := textValue(category); value != "" {
+			item.Categories = append(item.Categories, value)
+		}
+	})
+
+	return item
+}
+
+// impliedName derives a title for entries without p-name (typically notes)
+func impliedName(entry *goquery.Selection, item *gofeed.Item) string {
+	if heading := entry.Find("h1, h2, h3").First(); heading.Length() > 0 {
+		if name := strings.TrimSpace(heading.Text()); name != "" {
+			return name
+		}
+	}
+
+	text := item.Description
+	if text == "" {
+		text = textValue(findProperty(entry, "e-content"))
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) > maxImpliedNameLength {
+		runes := []rune(text)
+		text = strings.TrimSpace(string(runes[:maxImpliedNameLength])) + "…"
+	}
+	return text
+}
+
+// parseAuthor reads a p-author property, which is either plain text or an embedded h-card
+func parseAuthor(author *goquery.Selection, base *url.URL) *gofeed.Person {
+	person := &gofeed.Person{}
+	if author.HasClass("h-card") {
+		person.Name = textValue(author.Find(".p-name").First())
+		if email := author.Find(".u-email").First(); email.Length() > 0 {
+			person.Email = strings.TrimPrefix(urlValue(email, nil), "mailto:")
+		}
+		if person.Name == "" {
+			if img := author.Find("img[alt]").First(); img.Length() > 0 {
+				person.Name = strings.TrimSpace(img.AttrOr("alt", ""))
+			}
+		}
+	}
+	if person.Name == "" {
+		person.Name = textValue(author)
+	}
+	return person
+}
+
+// findProperty returns the first element with the given property class that belongs to root
+func findProperty(root *goquery.Selection, class string) *goquery.Selection {
+	return findProperties(root, class).First()
+}
+
+// findProperties returns all elements with the given property class that belong to root,
+// skipping properties of nested microformats. A nested root can itself be a property
+// of root (e.g. <div class="p-author h-card">), so the element itself is kept.
+func findProperties(root *goquery.Selection, class string) *goquery.Selection {
+	rootNode := root.Get(0)
+	return root.Find("." + class).FilterFunction(func(_ int, sel *goquery.Selection) bool {
+		parents := sel.ParentsFiltered(nestedRootSelector)
+		return parents.Length() == 0 || parents.Get(0) == rootNode
+	})
+}
+
+// textValue returns the text of a p-* property, honouring value-bearing attributes
+func textValue(sel *goquery.Selection) string {
+	if sel.Length() == 0 {
+		return ""
+	}
+	switch goquery.NodeName(sel) {
+	case "abbr", "link":
+		if title, ok := sel.Attr("title"); ok {
+			return strings.TrimSpace(title)
+		}
+	case "data", "input":
+		if value, ok := sel.Attr("value"); ok {
+			return strings.TrimSpace(value)
+		}
+	case "img", "area":
+		if alt, ok := sel.Attr("alt"); ok {
+			return strings.TrimSpace(alt)
+		}
+	}
+	return strings.TrimSpace(sel.Text())
+}
+
+// urlValue returns the absolute URL of a u-* property
+func urlValue(sel *goquery.Selection, base *url.URL) string {
+	var raw string
+	switch goquery.NodeName(sel) {
+	case "a", "area", "link":
+		raw = sel.AttrOr("href", "")
+	case "img", "audio", "video", "source", "iframe":
+		raw = sel.AttrOr("src", "")
+	case "object":
+		raw = sel.AttrOr("data", "")
+	case "data", "input":
+		raw = sel.AttrOr("value", "")
+	case "abbr":
+		raw = sel.AttrOr("title", "")
+	}
+	if raw == "" {
+		raw = sel.Text()
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" || base == nil {
+		return raw
+	}
+	if resolved, err := base.Parse(raw); err == nil {
+		return resolved.String()
+	}
+	return raw
+}
+
+// datetimeValue returns the raw value of a dt-* property
+func datetimeValue(sel *goquery.Selection) string {
+	for _, attr := range []string{"datetime", "title", "value"} {
+		if value, ok := sel.Attr(attr); ok && strings.TrimSpace(value) != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	// Value class pattern: <time class="dt-published"><span class="value">...</span></time>
+	if value := sel.Find(".value").First(); value.Length() > 0 {
+		return datetimeValue(value)
+	}
+	return strings.TrimSpace(sel.Text())
+}
+
+// parseTime parses a microformats date, returning nil for unknown formats
+func parseTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
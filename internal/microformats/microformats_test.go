@@ -0,0 +1,114 @@
+package microformats
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const hfeedPage = `<!DOCTYPE html>
+<html><head><title>Page Title</title></head>
+<body>
+<div class="h-feed">
+  <h1 class="p-name">Jane's Notes</h1>
+  <p class="p-author h-card"><a class="u-url p-name" href="/">Jane Doe</a></p>
+  <article class="h-entry">
+    <h2><a class="p-name u-url" href="/posts/hello">Hello World</a></h2>
+    <time class="dt-published" datetime="2024-03-01T10:00:00+01:00">March 1</time>
+    <div class="p-author h-card"><img class="u-photo" src="/me.jpg" alt="Jane"><span class="p-name">Jane</span></div>
+    <p class="p-summary">A first post.</p>
+    <div class="e-content"><p>Hello <b>world</b></p>
+      <div class="h-cite"><a class="u-url p-name" href="/quoted">Quoted post</a></div>
+    </div>
+    <a class="p-category" href="/tags/go">go</a>
+    <a class="p-category" href="/tags/web">web</a>
+    <data class="u-uid" value="tag:example.com,2024:hello"></data>
+  </article>
+  <article class="h-entry">
+    <div class="e-content">Just a short note without a name that is used as the implied title</div>
+    <a class="u-url" href="https://example.com/notes/2"><time class="dt-published" datetime="2024-03-02">2 Mar</time></a>
+  </article>
+</div>
+</body></html>`
+
+func TestParse_HFeed(t *testing.T) {
+	feed, err := Parse(strings.NewReader(hfeedPage), "https://example.com/blog/")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if feed.Title != "Jane's Notes" {
+		t.Errorf("feed title = %q", feed.Title)
+	}
+	if len(feed.Authors) != 1 || feed.Authors[0].Name != "Jane Doe" {
+		t.Errorf("unexpected feed authors: %+v", feed.Authors)
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(feed.Items))
+	}
+
+	first := feed.Items[0]
+	if first.Title != "Hello World" || first.Link != "https://example.com/posts/hello" {
+		t.Errorf("unexpected first item title/link: %q %q", first.Title, first.Link)
+	}
+	if first.GUID != "tag:example.com,2024:hello" {
+		t.Errorf("GUID = %q", first.GUID)
+	}
+	want := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	if first.PublishedParsed == nil || !first.PublishedParsed.Equal(want) {
+		t.Errorf("PublishedParsed = %v, want %v", first.PublishedParsed, want)
+	}
+	if first.Author == nil || first.Author.Name != "Jane" {
+		t.Errorf("unexpected author: %+v", first.Author)
+	}
+	if first.Image != nil {
+		t.Errorf("author photo must not be used as the entry photo, got %+v", first.Image)
+	}
+	if first.Description != "A first post." || !strings.Contains(first.Content, "<b>world</b>") {
+		t.Errorf("unexpected description/content: %q %q", first.Description, first.Content)
+	}
+	if strings.Join(first.Categories, ",") != "go,web" {
+		t.Errorf("categories = %v", first.Categories)
+	}
+
+	second := feed.Items[1]
+	if !strings.HasPrefix(second.Title, "Just a short note") {
+		t.Errorf("expected implied name from content, got %q", second.Title)
+	}
+	if second.GUID != "https://example.com/notes/2" {
+		t.Errorf("expected GUID to fall back to the URL, got %q", second.GUID)
+	}
+	if second.PublishedParsed == nil || second.PublishedParsed.Format("2006-01-02") != "2024-03-02" {
+		t.Errorf("unexpected date: %v", second.PublishedParsed)
+	}
+}
+
+func TestParse_ImpliedFeedAndNestedEntries(t *testing.T) {
+	page := `<html><head><title>Notes</title></head><body>
+		<div class="h-entry"><a class="u-url p-name" href="a.html">A</a>
+			<div class="h-entry"><span class="p-name">Reply</span></div>
+		</div>
+		<div class="h-entry"><a class="u-url p-name" href="b.html">B</a></div>
+	</body></html>`
+
+	feed, err := Parse(strings.NewReader(page), "https://example.com/dir/index.html")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if feed.Title != "Notes" {
+		t.Errorf("expected title from <title>, got %q", feed.Title)
+	}
+	if len(feed.Items) != 2 {
+		t.Fatalf("nested entries must not become items, got %d items", len(feed.Items))
+	}
+	if feed.Items[1].Link != "https://example.com/dir/b.html" {
+		t.Errorf("relative link not resolved: %q", feed.Items[1].Link)
+	}
+}
+
+func TestParse_NoEntries(t *testing.T) {
+	_, err := Parse(strings.NewReader("<html><body><p>nothing</p></body></html>"), "https://example.com")
+	if !errors.Is(err, ErrNoEntries) {
+		t.Errorf("expected ErrNoEntries, got %v", err)
+	}
+}
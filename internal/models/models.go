@@ -21,7 +21,7 @@ type Feed struct {
 	RefreshInterval    int       `json:"refresh_interval"`      // Custom refresh interval in minutes (0 = use global, -1 = intelligent, -2 = never, >0 = custom minutes)
 	IsImageMode        bool      `json:"is_image_mode"`         // Whether this feed is for image gallery mode
 	// XPath support for HTML/XML scraping
-	Type                string `json:"type"`                   // "HTML+XPath" or "XML+XPath"
+	Type                string `json:"type"`                   // "HTML+XPath", "XML+XPath", "HTML+Microformats" or "email"
 	XPathItem           string `json:"xpath_item"`             // XPath to extract feed items
 	XPathItemTitle      string `json:"xpath_item_title"`       // XPath to extract item title
 	XPathItemContent    string `json:"xpath_item_content"`     // XPath to extract item content
@@ -34,6 +34,12 @@ type Feed struct {
 	XPathItemUid        string `json:"xpath_item_uid"`         // XPath to extract item unique ID
 	ArticleViewMode     string `json:"article_view_mode"`      // Article view mode override ('global', 'webpage', 'rendered')
 	AutoExpandContent   string `json:"auto_expand_content"`    // Auto expand content mode ('global', 'enabled', 'disabled')
+	FullTextOnIngest    bool   `json:"full_text_on_ingest"`    // Extract full article text when new articles are saved
+	BackgroundTranslate bool   `json:"background_translate"`   // Translate titles of new articles in the background
+	BackgroundSummarize bool   `json:"background_summarize"`   // Summarize new articles in the background
+	SummaryPromptID     int64  `json:"summary_prompt_id"`      // Prompt template for AI summaries, 0 for the category's or default one
+	TranslationPromptID int64  `json:"translation_prompt_id"`  // Prompt template for AI translations, 0 for the category's or default one
+	Language            string `json:"language,omitempty"`     // Dominant detected language of recent articles (ISO 639-1)
 	// Email/Newsletter support
 	EmailAddress    string `json:"email_address,omitempty"`     // Email address for newsletter subscriptions
 	EmailIMAPServer string `json:"email_imap_server,omitempty"` // IMAP server address
@@ -67,7 +73,62 @@ type Article struct {
 	IsReadLater           bool      `json:"is_read_later"`
 	FeedTitle             string    `json:"feed_title,omitempty"` // Joined field
 	TranslatedTitle       string    `json:"translated_title"`
-	Summary               string    `json:"summary"`          // Cached AI-generated summary
-	UniqueID              string    `json:"unique_id"`        // Unique identifier for deduplication (title+feed_id+published_date)
-	FreshRSSItemID        string    `json:"freshrss_item_id"` // FreshRSS/Google Reader item ID for API operations
+	Summary               string    `json:"summary"`                   // Cached AI-generated summary
+	UniqueID              string    `json:"unique_id"`                 // Unique identifier for deduplication (title+feed_id+published_date)
+	FreshRSSItemID        string    `json:"freshrss_item_id"`          // FreshRSS/Google Reader item ID for API operations
+	RelevanceScore        *float64  `json:"relevance_score,omitempty"` // Learned relevance from 0 to 100, nil until scored
+	Topics                []string  `json:"topics,omitempty"`          // AI classification into the user's topics
+	Entities              []string  `json:"entities,omitempty"`        // People, organizations, products and places the article is about
+	Keywords              []string  `json:"keywords,omitempty"`        // Key phrases extracted from the article, best first
+	WhyItMatters          string    `json:"why_it_matters,omitempty"`  // One-line AI note on why the article matters
+	Language              string    `json:"language,omitempty"`        // Detected language (ISO 639-1), empty if detection failed
+	LanguageConfidence    float64   `json:"language_confidence"`       // Confidence of the language detection from 0 to 1
+}
+
+// GlossaryEntry is a term whose translation is fixed when translating to TargetLang. A protected
+// term is kept as-is instead of being translated.
+type GlossaryEntry struct {
+	ID          int64  `json:"id"`
+	TargetLang  string `json:"target_lang"` // ISO 639-1 code, empty for every language
+	Term        string `json:"term"`
+	Translation string `json:"translation"` // Unused for protected terms
+	Protected   bool   `json:"protected"`
+}
+
+// PromptTemplate is a named AI prompt with {{variable}} placeholders. Summary templates are the
+// request for a summary, translation templates the instructions (such as the tone) for translating,
+// and chat action templates quick prompts sent from the article chat.
+type PromptTemplate struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	Kind       string `json:"kind"` // "summary", "translation" or "chat_action"
+	Template   string `json:"template"`
+	Categories string `json:"categories"` // Feed categories the template applies to, comma separated
+	IsDefault  bool   `json:"is_default"` // Applies to feeds without a more specific template
+}
+
+// TranslationCacheEntry is a cached translation of a text by a provider. An edited entry is a
+// manual correction, which the provider's translations no longer replace.
+type TranslationCacheEntry struct {
+	ID             int64  `json:"id"`
+	SourceTextHash string `json:"-"` // Cache lookup key of the source text
+	SourceText     string `json:"source_text"`
+	TargetLang     string `json:"target_lang"`
+	TranslatedText string `json:"translated_text"`
+	Provider       string `json:"provider"`
+	Hits           int64  `json:"hits"`
+	Edited         bool   `json:"edited"`
+	CreatedAt      string `json:"created_at"`
+}
+
+// TranslationCacheStats summarizes the cached translations of a provider to one language
+type TranslationCacheStats struct {
+	Provider   string  `json:"provider"`
+	TargetLang string  `json:"target_lang"`
+	Entries    int64   `json:"entries"`
+	Edited     int64   `json:"edited"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`   // Lookups that found no translation
+	HitRate    float64 `json:"hit_rate"` // Hits over all lookups
+	Bytes      int64   `json:"bytes"`    // Size of the source and translated texts
 }
@@ -0,0 +1,260 @@
+// Package prompts renders the AI prompt templates of the prompt library and resolves the template
+// that applies to an article from its feed's override, its category or the default template.
+package prompts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
+)
+
+// Template variables
+const (
+	VarTitle    = "title"
+	VarContent  = "content"
+	VarFeed     = "feed"
+	VarLanguage = "language"
+	VarLength   = "length"
+)
+
+// kindVariables are the variables available in the templates of each kind. Translation templates
+// are instructions shared by every text translated for a feed, so they have no article variables.
+var kindVariables = map[string][]string{
+	database.PromptKindSummary:     {VarTitle, VarContent, VarFeed, VarLanguage, VarLength},
+	database.PromptKindTranslation: {VarFeed, VarLanguage},
+	database.PromptKindChatAction:  {VarTitle, VarContent, VarFeed, VarLanguage},
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*(\w*)\s*\}\}`)
+
+// Vars are the values of the template variables
+type Vars struct {
+	Title    string
+	Content  string
+	Feed     string
+	Language string // Language name, such as "English"
+	Length   string // Approximate number of words
+}
+
+func (v Vars) value(name string) (string, bool) {
+	switch name {
+	case VarTitle:
+		return v.Title, true
+	case VarContent:
+		return v.Content, true
+	case VarFeed:
+		return v.Feed, true
+	case VarLanguage:
+		return v.Language, true
+	case VarLength:
+		return v.Length, true
+	}
+	return "", false
+}
+
+// Variables returns the variables available in the templates of kind
+func Variables(kind string) []string {
+	return kindVariables[kind]
+}
+
+// Validate returns the problems of a template of kind: unknown variables, variables the kind
+// doesn't have and unclosed placeholders. It returns nil for a valid template.
+func Validate(kind, template string) []string {
+	allowed, ok := kindVariables[kind]
+	if !ok {
+		return []string{fmt.Sprintf("unknown prompt kind %q", kind)}
+	}
+	if strings.TrimSpace(template) == "" {
+		return []string{"template is empty"}
+	}
+
+	var problems []string
+	seen := make(map[string]bool)
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		name := m[1]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, known := (Vars{}).value(name); !known {
+			problems = append(problems, fmt.Sprintf("unknown variable {{%s}}", name))
+			continue
+		}
+		if !contains(allowed, name) {
+			problems = append(problems, fmt.Sprintf("{{%s}} is not available in %s templates", name, kind))
+		}
+	}
+
+	rest := placeholderPattern.ReplaceAllString(template, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		problems = append(problems, "unclosed {{ or }} in template")
+	}
+	return problems
+}
+
+// Render replaces the variables of template with their values. Unknown variables are kept as-is.
+func Render(template string, vars Vars) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := vars.value(name); ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// Store gives the prompt templates of a kind
+type Store interface {
+	GetPromptTemplates(kind string) ([]models.PromptTemplate, error)
+}
+
+// Resolve returns the template of kind applying to a feed: the feed's own template, else the
+// template of its most specific category, else the default template of kind. It returns nil when
+// none applies, or feed is nil and there is no default.
+func Resolve(store Store, feed *models.Feed, kind string) *models.PromptTemplate {
+	templates, err := store.GetPromptTemplates(kind)
+	if err != nil || len(templates) == 0 {
+		return nil
+	}
+
+	if feed != nil {
+		override := feed.SummaryPromptID
+		if kind == database.PromptKindTranslation {
+			override = feed.TranslationPromptID
+		}
+		for i := range templates {
+			if override > 0 && templates[i].ID == override {
+				return &templates[i]
+			}
+		}
+
+		var best *models.PromptTemplate
+		bestDepth := 0
+		for i := range templates {
+			if depth := categoryDepth(feed.Category, templates[i].Categories); depth > bestDepth {
+				best, bestDepth = &templates[i], depth
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+
+	for i := range templates {
+		if templates[i].IsDefault {
+			return &templates[i]
+		}
+	}
+	return nil
+}
+
+// categoryDepth returns the number of levels of the most specific category of list, comma or
+// newline separated, that category is or is a subcategory of, 0 if there is none
+func categoryDepth(category, list string) int {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if category == "" {
+		return 0
+	}
+	depth := 0
+	for _, c := range strings.FieldsFunc(list, func(r rune) bool { return r == '\n' || r == ',' }) {
+		c = strings.ToLower(strings.Trim(strings.TrimSpace(c), "/"))
+		if c != "" && (category == c || strings.HasPrefix(category, c+"/")) {
+			if d := strings.Count(c, "/") + 1; d > depth {
+				depth = d
+			}
+		}
+	}
+	return depth
+}
+
+// ArticleStore gives the articles and feeds that templates are resolved for
+type ArticleStore interface {
+	Store
+	GetArticleByID(id int64) (*models.Article, error)
+	GetFeedByID(id int64) (*models.Feed, error)
+}
+
+// Prompt is a template resolved for an article, with the article's variables
+type Prompt struct {
+	Template models.PromptTemplate
+	Vars     Vars
+}
+
+// ForArticle returns the template of kind applying to an article, see Resolve, or nil if none does
+func ForArticle(store ArticleStore, articleID int64, kind string) *Prompt {
+	article, err := store.GetArticleByID(articleID)
+	if err != nil {
+		return nil
+	}
+	feed, err := store.GetFeedByID(article.FeedID)
+	if err != nil {
+		return nil
+	}
+	return ForFeed(store, feed, kind, Vars{Title: article.Title, Feed: feed.Title})
+}
+
+// ForFeed returns the template of kind applying to a feed, see Resolve, with vars, or nil if none does
+func ForFeed(store Store, feed *models.Feed, kind string, vars Vars) *Prompt {
+	template := Resolve(store, feed, kind)
+	if template == nil {
+		return nil
+	}
+	return &Prompt{Template: *template, Vars: vars}
+}
+
+// Render renders the prompt with the language named from a language code
+func (p *Prompt) Render(language string) string {
+	vars := p.Vars
+	vars.Language = translation.LanguageName(language)
+	return Render(p.Template.Template, vars)
+}
+
+// SummaryPrompt returns the builder of the request for a summary in language from the text to
+// summarize and the target number of words. The text is appended to templates without {{content}}.
+func (p *Prompt) SummaryPrompt(language string) func(text string, targetWords int) string {
+	return func(text string, targetWords int) string {
+		vars := p.Vars
+		vars.Content = text
+		vars.Length = strconv.Itoa(targetWords)
+		vars.Language = translation.LanguageName(language)
+		prompt := Render(p.Template.Template, vars)
+		if !usesVariable(p.Template.Template, VarContent) {
+			prompt += "\n\n" + text
+		}
+		return prompt
+	}
+}
+
+// CacheProvider returns the translation cache provider of translations made by provider with the
+// prompt, so that they are cached apart from translations made without it and dropped when the
+// template changes. p may be nil.
+func (p *Prompt) CacheProvider(provider string) string {
+	if p == nil {
+		return provider
+	}
+	return provider + "#" + strconv.FormatInt(p.Template.ID, 10)
+}
+
+// usesVariable reports whether template has a placeholder for the variable name
+func usesVariable(template, name string) bool {
+	for _, m := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
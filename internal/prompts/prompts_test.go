@@ -0,0 +1,104 @@
+package prompts
+
+import (
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+type memoryStore []models.PromptTemplate
+
+func (s memoryStore) GetPromptTemplates(kind string) ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+	for _, t := range s {
+		if t.Kind == kind {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		kind     string
+		template string
+		problems int
+	}{
+		{database.PromptKindSummary, "Summarize {{title}} in {{ language }} with {{length}} words: {{content}}", 0},
+		{database.PromptKindTranslation, "Translate for {{feed}} readers in {{language}}.", 0},
+		{database.PromptKindTranslation, "Translate {{title}}.", 1},
+		{database.PromptKindChatAction, "Explain {{topic}}", 1},
+		{database.PromptKindChatAction, "Explain {{title}", 1},
+		{database.PromptKindChatAction, "  ", 1},
+		{"poem", "Write a poem", 1},
+	}
+	for _, tt := range tests {
+		if problems := Validate(tt.kind, tt.template); len(problems) != tt.problems {
+			t.Errorf("Validate(%q, %q) = %v, want %d problems", tt.kind, tt.template, problems, tt.problems)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := Render("{{title}} from {{ feed }} {{unknown}}", Vars{Title: "Hello", Feed: "News"})
+	if want := "Hello from News {{unknown}}"; got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	store := memoryStore{
+		{ID: 1, Kind: database.PromptKindSummary, Template: "default", IsDefault: true},
+		{ID: 2, Kind: database.PromptKindSummary, Template: "tech", Categories: "Tech"},
+		{ID: 3, Kind: database.PromptKindSummary, Template: "hardware", Categories: "News, tech/hardware"},
+		{ID: 4, Kind: database.PromptKindSummary, Template: "override"},
+		{ID: 5, Kind: database.PromptKindTranslation, Template: "formal"},
+	}
+
+	tests := []struct {
+		name string
+		feed *models.Feed
+		kind string
+		want int64
+	}{
+		{"no feed", nil, database.PromptKindSummary, 1},
+		{"uncategorized", &models.Feed{}, database.PromptKindSummary, 1},
+		{"category", &models.Feed{Category: "Tech/Software"}, database.PromptKindSummary, 2},
+		{"deepest category", &models.Feed{Category: "Tech/Hardware/GPUs"}, database.PromptKindSummary, 3},
+		{"feed override", &models.Feed{Category: "Tech", SummaryPromptID: 4}, database.PromptKindSummary, 4},
+		{"override of another kind", &models.Feed{SummaryPromptID: 5}, database.PromptKindSummary, 1},
+		{"translation override", &models.Feed{TranslationPromptID: 5}, database.PromptKindTranslation, 5},
+		{"no default", &models.Feed{}, database.PromptKindTranslation, 0},
+	}
+	for _, tt := range tests {
+		var got int64
+		if template := Resolve(store, tt.feed, tt.kind); template != nil {
+			got = template.ID
+		}
+		if got != tt.want {
+			t.Errorf("%s: Resolve() = template %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummaryPrompt(t *testing.T) {
+	p := &Prompt{
+		Template: models.PromptTemplate{ID: 7, Template: "Summarize {{title}} in {{language}}, about {{length}} words."},
+		Vars:     Vars{Title: "Launch"},
+	}
+	got := p.SummaryPrompt("de")("The rocket launched.", 50)
+	if want := "Summarize Launch in German, about 50 words.\n\nThe rocket launched."; got != want {
+		t.Errorf("SummaryPrompt() = %q, want %q", got, want)
+	}
+
+	p.Template.Template = "Text: {{content}}"
+	if got := p.SummaryPrompt("en")("Body", 50); strings.Count(got, "Body") != 1 {
+		t.Errorf("expected the text once in a template using {{content}}, got %q", got)
+	}
+
+	if p.CacheProvider("ai") != "ai#7" || (*Prompt)(nil).CacheProvider("ai") != "ai" {
+		t.Error("unexpected cache providers")
+	}
+}
@@ -0,0 +1,273 @@
+// Package relevance learns which articles the user cares about from their own behaviour and
+// scores articles by how likely they are to be of interest.
+//
+// The model is a weighted naive Bayes classifier over the tokens of an article's title, summary
+// and content, plus its feed. Favorites, read later, reads and time spent reading are positive
+// examples; hidden articles, articles left within seconds and unread articles that were passed
+// over are negative ones. Scores range from 0 to 100, with 50 meaning no preference either way.
+package relevance
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/summary"
+)
+
+const (
+	// trainingLimit caps the number of articles the model is trained on, newest first
+	trainingLimit = 5000
+	// skippedAfter is how long an article stays unread before it counts as passed over
+	skippedAfter = 14 * 24 * time.Hour
+	// minExamples is the number of positive and of negative examples needed to score articles
+	minExamples = 5
+	// maxTextChars caps the text tokenized per article
+	maxTextChars = 2000
+	// retrainInterval is how long a trained model is used before it is trained again
+	retrainInterval = time.Hour
+	// rescoreLimit is the number of most recent unread articles rescored after training
+	rescoreLimit = 2000
+
+	// Dwell times, in seconds, that mark an article as bounced, read or read closely
+	bounceSeconds = 5
+	readSeconds   = 20
+	closeSeconds  = 120
+)
+
+// Model is a trained relevance model
+type Model struct {
+	positive   map[string]float64 // Weight of positive examples containing each token
+	negative   map[string]float64 // Weight of negative examples containing each token
+	posTotal   float64
+	negTotal   float64
+	vocabulary float64
+
+	Positives int       `json:"positives"`
+	Negatives int       `json:"negatives"`
+	TrainedAt time.Time `json:"trained_at"`
+}
+
+// Train builds a model from labelled articles. Articles without a signal are ignored.
+func Train(documents []database.RelevanceDocument, now time.Time) *Model {
+	m := &Model{
+		positive:  make(map[string]float64),
+		negative:  make(map[string]float64),
+		TrainedAt: now,
+	}
+	skippedBefore := now.Add(-skippedAfter)
+
+	vocabulary := make(map[string]bool)
+	for _, d := range documents {
+		weight := Label(d, skippedBefore)
+		if weight == 0 {
+			continue
+		}
+		counts, total := m.positive, &m.posTotal
+		if weight > 0 {
+			m.Positives++
+		} else {
+			counts, total = m.negative, &m.negTotal
+			weight = -weight
+			m.Negatives++
+		}
+		for _, token := range Tokens(d) {
+			counts[token] += weight
+			*total += weight
+			vocabulary[token] = true
+		}
+	}
+	m.vocabulary = float64(len(vocabulary))
+	return m
+}
+
+// Ready reports whether the model has seen enough positive and negative examples to score articles
+func (m *Model) Ready() bool {
+	return m != nil && m.Positives >= minExamples && m.Negatives >= minExamples
+}
+
+// Score returns the relevance of an article from 0 to 100
+func (m *Model) Score(d database.RelevanceDocument) float64 {
+	var logOdds float64
+	var n int
+	for _, token := range Tokens(d) {
+		pos, neg := m.positive[token], m.negative[token]
+		if pos == 0 && neg == 0 {
+			continue // Unseen tokens carry no preference
+		}
+		logOdds += math.Log((pos+1)/(m.posTotal+m.vocabulary)) - math.Log((neg+1)/(m.negTotal+m.vocabulary))
+		n++
+	}
+	if n == 0 {
+		return 50
+	}
+	// Dampen long texts so that the number of tokens doesn't push scores to the extremes
+	logOdds /= math.Sqrt(float64(n))
+
+	score := 100 / (1 + math.Exp(-logOdds))
+	return math.Round(score*10) / 10
+}
+
+// Label returns the training weight of an article: positive for interest, negative for
+// disinterest and 0 for no signal. Unread articles published before skippedBefore were passed over.
+func Label(d database.RelevanceDocument, skippedBefore time.Time) float64 {
+	if d.IsHidden {
+		return -3
+	}
+
+	var weight float64
+	if d.IsFavorite {
+		weight += 3
+	}
+	if d.IsReadLater {
+		weight += 2
+	}
+	switch {
+	case d.DwellSeconds >= closeSeconds:
+		weight += 2
+	case d.DwellSeconds >= readSeconds:
+		weight++
+	case d.DwellSeconds > 0 && d.DwellSeconds < bounceSeconds && weight == 0:
+		return -1
+	}
+	if d.IsRead && weight == 0 {
+		// Reads without dwell time may be bulk "mark all as read", a weak signal
+		weight = 0.5
+	}
+	if weight == 0 && !d.IsRead && !d.PublishedAt.IsZero() && d.PublishedAt.Before(skippedBefore) {
+		weight = -1
+	}
+	return weight
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Tokens returns the distinct tokens of an article's title, summary and content, and its feed
+func Tokens(d database.RelevanceDocument) []string {
+	content := tagPattern.ReplaceAllString(d.Content, " ")
+	content = strings.Join(strings.Fields(html.UnescapeString(content)), " ")
+	if runes := []rune(content); len(runes) > maxTextChars {
+		content = string(runes[:maxTextChars])
+	}
+
+	tokens := summary.Keywords(d.Title + "\n" + d.Summary + "\n" + content)
+	return append(tokens, fmt.Sprintf("feed:%d", d.FeedID))
+}
+
+// Service trains the relevance model and scores articles with it
+type Service struct {
+	db    *database.DB
+	now   func() time.Time
+	mu    sync.Mutex // Guards model, held while training so that concurrent callers train once
+	model *Model
+}
+
+// NewService creates a relevance service
+func NewService(db *database.DB) *Service {
+	return &Service{db: db, now: time.Now}
+}
+
+// Train trains the model on the current archive and uses it from now on
+func (s *Service) Train() (*Model, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.train()
+}
+
+// Model returns the current model, training it when there is none or it is out of date
+func (s *Service) Model() (*Model, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.model != nil && s.now().Sub(s.model.TrainedAt) < retrainInterval {
+		return s.model, nil
+	}
+	return s.train()
+}
+
+// train trains a model and replaces the current one, s.mu must be held
+func (s *Service) train() (*Model, error) {
+	now := s.now()
+	documents, err := s.db.GetRelevanceTrainingSet(now.Add(-skippedAfter), trainingLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load relevance training set: %w", err)
+	}
+	s.model = Train(documents, now)
+	return s.model, nil
+}
+
+// ScoreArticles scores articles, sets their RelevanceScore and stores the scores.
+// Nothing is scored until the model has enough examples.
+func (s *Service) ScoreArticles(articles []models.Article) error {
+	model, err := s.Model()
+	if err != nil || !model.Ready() || len(articles) == 0 {
+		return err
+	}
+
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	documents, err := s.db.GetRelevanceDocuments(ids)
+	if err != nil {
+		return err
+	}
+	scores := scoreDocuments(model, documents)
+	for i := range articles {
+		if score, ok := scores[articles[i].ID]; ok {
+			articles[i].RelevanceScore = &score
+		}
+	}
+	return s.db.SetRelevanceScores(scores)
+}
+
+// RescoreUnread trains the model and rescores the most recent unread articles with it,
+// returning how many were scored
+func (s *Service) RescoreUnread() (int, error) {
+	model, err := s.Train()
+	if err != nil || !model.Ready() {
+		return 0, err
+	}
+	documents, err := s.db.GetUnreadRelevanceDocuments(rescoreLimit)
+	if err != nil {
+		return 0, err
+	}
+	scores := scoreDocuments(model, documents)
+	return len(scores), s.db.SetRelevanceScores(scores)
+}
+
+// Run retrains the model and rescores unread articles every interval until ctx is done,
+// so that scores follow changes in behaviour
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.RescoreUnread(); err != nil {
+			log.Printf("Failed to update relevance scores: %v", err)
+		} else if n > 0 {
+			log.Printf("Updated relevance scores of %d unread articles", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func scoreDocuments(model *Model, documents []database.RelevanceDocument) map[int64]float64 {
+	scores := make(map[int64]float64, len(documents))
+	for _, d := range documents {
+		scores[d.ArticleID] = model.Score(d)
+	}
+	return scores
+}
@@ -0,0 +1,124 @@
+package relevance
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestLabel(t *testing.T) {
+	now := time.Now()
+	skippedBefore := now.Add(-skippedAfter)
+	old := now.AddDate(0, -1, 0)
+
+	cases := []struct {
+		name     string
+		document database.RelevanceDocument
+		want     float64
+	}{
+		{"hidden", database.RelevanceDocument{IsHidden: true, IsFavorite: true}, -3},
+		{"favorite and read later", database.RelevanceDocument{IsFavorite: true, IsReadLater: true}, 5},
+		{"read closely", database.RelevanceDocument{IsRead: true, DwellSeconds: 300}, 2},
+		{"read", database.RelevanceDocument{IsRead: true, DwellSeconds: 30}, 1},
+		{"bulk marked read", database.RelevanceDocument{IsRead: true}, 0.5},
+		{"bounced", database.RelevanceDocument{IsRead: true, DwellSeconds: 2}, -1},
+		{"bounced favorite", database.RelevanceDocument{IsFavorite: true, DwellSeconds: 2}, 3},
+		{"passed over", database.RelevanceDocument{PublishedAt: old}, -1},
+		{"new and unread", database.RelevanceDocument{PublishedAt: now}, 0},
+	}
+	for _, c := range cases {
+		if got := Label(c.document, skippedBefore); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+// trainingSet returns articles where everything about Go is liked and everything about celebrities is hidden
+func trainingSet(n int) []database.RelevanceDocument {
+	var documents []database.RelevanceDocument
+	for i := 0; i < n; i++ {
+		documents = append(documents,
+			database.RelevanceDocument{FeedID: 1, Title: fmt.Sprintf("Go compiler release %d", i), Summary: "Generics and goroutines", IsFavorite: true},
+			database.RelevanceDocument{FeedID: 2, Title: fmt.Sprintf("Celebrity gossip %d", i), Summary: "Red carpet fashion", IsHidden: true},
+		)
+	}
+	return documents
+}
+
+func TestTrainAndScore(t *testing.T) {
+	model := Train(trainingSet(minExamples-1), time.Now())
+	if model.Ready() {
+		t.Fatalf("expected a model with %d examples per class not to be ready", minExamples-1)
+	}
+
+	model = Train(trainingSet(minExamples), time.Now())
+	if !model.Ready() || model.Positives != minExamples || model.Negatives != minExamples {
+		t.Fatalf("expected a ready model, got %+v", model)
+	}
+
+	liked := model.Score(database.RelevanceDocument{FeedID: 3, Title: "New goroutines scheduler in the Go compiler"})
+	disliked := model.Score(database.RelevanceDocument{FeedID: 3, Title: "Celebrity fashion on the red carpet"})
+	unknown := model.Score(database.RelevanceDocument{FeedID: 3, Title: "Quarterly weather outlook"})
+	if liked <= 70 || disliked >= 30 || unknown != 50 {
+		t.Errorf("unexpected scores: liked %v, disliked %v, unknown %v", liked, disliked, unknown)
+	}
+	if fromFeed := model.Score(database.RelevanceDocument{FeedID: 1, Title: "Quarterly weather outlook"}); fromFeed <= 50 {
+		t.Errorf("expected an article from a liked feed to score above 50, got %v", fromFeed)
+	}
+}
+
+func TestService_ScoreArticles(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	feedID, err := db.AddFeed(&models.Feed{Title: "Mixed", URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	for i, d := range trainingSet(minExamples) {
+		res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, summary, published_at, is_favorite, is_hidden, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			feedID, d.Title, fmt.Sprintf("https://example.com/%d", i), d.Summary, time.Now(), d.IsFavorite, d.IsHidden, fmt.Sprint(i))
+		if err != nil {
+			t.Fatalf("insert article error: %v", err)
+		}
+		if i == 0 {
+			id, _ := res.LastInsertId()
+			if err := db.RecordArticleDwell(id, 200); err != nil {
+				t.Fatalf("RecordArticleDwell error: %v", err)
+			}
+		}
+	}
+	if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: "Go compiler gets faster goroutines", URL: "https://example.com/new", PublishedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveArticle error: %v", err)
+	}
+
+	articles, err := db.GetArticles("unread", feedID, "", false, 1, 0)
+	if err != nil || len(articles) != 1 {
+		t.Fatalf("expected the new article, got %+v (%v)", articles, err)
+	}
+
+	service := NewService(db)
+	if err := service.ScoreArticles(articles); err != nil {
+		t.Fatalf("ScoreArticles error: %v", err)
+	}
+	if articles[0].RelevanceScore == nil || *articles[0].RelevanceScore <= 70 {
+		t.Fatalf("expected a high score to be set, got %v", articles[0].RelevanceScore)
+	}
+	stored, err := db.GetArticleByID(articles[0].ID)
+	if err != nil || stored.RelevanceScore == nil || *stored.RelevanceScore != *articles[0].RelevanceScore {
+		t.Errorf("expected the score to be stored, got %+v (%v)", stored, err)
+	}
+
+	// The favorites are unread too, only the hidden articles are left out
+	if n, err := service.RescoreUnread(); err != nil || n != minExamples+1 {
+		t.Errorf("expected the unread articles to be rescored, got %d (%v)", n, err)
+	}
+}
@@ -3,8 +3,10 @@ package rules
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -67,6 +69,16 @@ type Rule struct {
 	Position   int         `json:"position"` // Execution order (0 = first)
 }
 
+// ConditionFields lists the article fields conditions can test
+var ConditionFields = []string{
+	"feed_name", "feed_category", "article_title", "feed_type", "is_freshrss_feed", "is_image_mode_feed",
+	"published_after", "published_before", "is_read", "is_favorite", "is_hidden", "is_read_later",
+	"relevance_above", "relevance_below", "topic", "entity", "language", "keyword",
+}
+
+// ActionNames lists the actions rules can apply
+var ActionNames = []string{"favorite", "unfavorite", "hide", "unhide", "mark_read", "mark_unread", "read_later", "remove_read_later"}
+
 // Engine handles rule application
 type Engine struct {
 	db *database.DB
@@ -97,6 +109,26 @@ func (e *Engine) ApplyRulesToArticles(articles []models.Article) (int, error) {
 	// Rules without a position field (backward compatibility) are treated as position 0
 	sortRulesByPosition(rules)
 
+	// Load topics and entities if a rule matches on them
+	for _, rule := range rules {
+		if rule.Enabled && usesTags(rule.Conditions) {
+			if err := e.db.AttachArticleClassifications(articles); err != nil {
+				return 0, err
+			}
+			break
+		}
+	}
+
+	// Load keywords if a rule matches on them
+	for _, rule := range rules {
+		if rule.Enabled && usesKeywords(rule.Conditions) {
+			if err := e.db.AttachArticleKeywords(articles); err != nil {
+				return 0, err
+			}
+			break
+		}
+	}
+
 	// Get feeds for category and title lookup
 	feeds, err := e.db.GetFeeds()
 	if err != nil {
@@ -143,6 +175,68 @@ func (e *Engine) ApplyRulesToArticles(articles []models.Article) (int, error) {
 	return affected, nil
 }
 
+// AddRule validates a rule and appends it to the saved rules, after the existing ones.
+// The rule is given an ID and position; it is applied to articles fetched from then on.
+func (e *Engine) AddRule(rule Rule) (Rule, error) {
+	if strings.TrimSpace(rule.Name) == "" {
+		return Rule{}, fmt.Errorf("rule name is required")
+	}
+	if len(rule.Actions) == 0 {
+		return Rule{}, fmt.Errorf("rule has no actions")
+	}
+	for _, action := range rule.Actions {
+		if !contains(ActionNames, action) {
+			return Rule{}, fmt.Errorf("unknown action %q", action)
+		}
+	}
+	for _, condition := range rule.Conditions {
+		if !contains(ConditionFields, condition.Field) {
+			return Rule{}, fmt.Errorf("unknown condition field %q", condition.Field)
+		}
+	}
+
+	var rules []Rule
+	if rulesJSON, _ := e.db.GetSetting("rules"); rulesJSON != "" {
+		if err := json.Unmarshal([]byte(rulesJSON), &rules); err != nil {
+			return Rule{}, fmt.Errorf("failed to parse saved rules: %w", err)
+		}
+	}
+
+	// IDs are millisecond timestamps, like the ones the rule editor assigns
+	rule.ID = time.Now().UnixMilli()
+	for _, existing := range rules {
+		if existing.ID >= rule.ID {
+			rule.ID = existing.ID + 1
+		}
+		if existing.Position >= rule.Position {
+			rule.Position = existing.Position + 1
+		}
+	}
+	for i := range rule.Conditions {
+		rule.Conditions[i].ID = int64(i + 1)
+	}
+
+	rules = append(rules, rule)
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return Rule{}, err
+	}
+	if err := e.db.SetSetting("rules", string(rulesJSON)); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // ApplyRule applies a single rule to all matching articles.
 // Uses batch processing with a reasonable limit to avoid memory issues.
 func (e *Engine) ApplyRule(rule Rule) (int, error) {
@@ -153,6 +247,17 @@ func (e *Engine) ApplyRule(rule Rule) (int, error) {
 		return 0, err
 	}
 
+	if usesTags(rule.Conditions) {
+		if err := e.db.AttachArticleClassifications(articles); err != nil {
+			return 0, err
+		}
+	}
+	if usesKeywords(rule.Conditions) {
+		if err := e.db.AttachArticleKeywords(articles); err != nil {
+			return 0, err
+		}
+	}
+
 	// Get feeds for category and title lookup
 	feeds, err := e.db.GetFeeds()
 	if err != nil {
@@ -329,6 +434,35 @@ func evaluateCondition(article models.Article, condition Condition, feedCategori
 			result = article.IsReadLater == wantReadLater
 		}
 
+	case "relevance_above", "relevance_below":
+		// Value is a score from 0 to 100, articles that aren't scored yet never match
+		threshold, err := strconv.ParseFloat(condition.Value, 64)
+		if condition.Value == "" || err != nil {
+			result = true
+		} else if article.RelevanceScore == nil {
+			result = false
+		} else if condition.Field == "relevance_above" {
+			result = *article.RelevanceScore >= threshold
+		} else {
+			result = *article.RelevanceScore < threshold
+		}
+
+	case "topic", "entity":
+		// Matches articles classified with any of the selected topics or entities
+		tags := article.Topics
+		if condition.Field == "entity" {
+			tags = article.Entities
+		}
+		result = MatchTags(tags, condition.Values, condition.Value)
+
+	case "language":
+		// Matches articles detected in any of the selected languages, undetected articles never match
+		result = MatchTags([]string{article.Language}, condition.Values, condition.Value)
+
+	case "keyword":
+		// Matches articles with an extracted keyword containing the value or any of the selected values
+		result = MatchKeywords(article.Keywords, condition.Values, condition.Value)
+
 	default:
 		result = true
 	}
@@ -356,6 +490,71 @@ func matchMultiSelect(fieldValue string, values []string, singleValue string) bo
 	return true
 }
 
+// MatchTags checks if any of an article's tags equals one of the selected values, ignoring
+// case. The article filter matches topics, entities and languages the same way.
+func MatchTags(tags []string, values []string, singleValue string) bool {
+	if len(values) == 0 {
+		if singleValue == "" {
+			return true
+		}
+		values = []string{singleValue}
+	}
+	for _, tag := range tags {
+		for _, val := range values {
+			if strings.EqualFold(tag, val) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchKeywords checks if any of an article's keywords contains one of the selected values,
+// ignoring case. The article filter matches keywords the same way.
+func MatchKeywords(keywords []string, values []string, singleValue string) bool {
+	if len(values) == 0 && singleValue == "" {
+		return true
+	}
+	for _, keyword := range keywords {
+		if matchMultiSelect(keyword, values, singleValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTagField reports whether a condition field matches on the topics or entities of articles,
+// which are only loaded when a condition needs them
+func IsTagField(field string) bool {
+	return field == "topic" || field == "entity"
+}
+
+// usesTags reports whether conditions match on topics or entities
+func usesTags(conditions []Condition) bool {
+	for _, condition := range conditions {
+		if IsTagField(condition.Field) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKeywordField reports whether a condition field matches on the extracted keywords of
+// articles, which are only loaded when a condition needs them
+func IsKeywordField(field string) bool {
+	return field == "keyword"
+}
+
+// usesKeywords reports whether conditions match on extracted keywords
+func usesKeywords(conditions []Condition) bool {
+	for _, condition := range conditions {
+		if IsKeywordField(condition.Field) {
+			return true
+		}
+	}
+	return false
+}
+
 // applyAction applies an action to an article with FreshRSS sync if enabled
 func (e *Engine) applyAction(articleID int64, action string) error {
 	var syncReq *database.SyncRequest
@@ -100,3 +100,141 @@ func TestEngine_ApplyRule(t *testing.T) {
 		t.Errorf("Expected 0 articles to be processed, got %d", count)
 	}
 }
+
+func TestEngine_AddRule(t *testing.T) {
+	engine := setupTestEngine(t)
+
+	existing, _ := json.Marshal([]Rule{{ID: 5, Name: "Existing", Actions: []string{"hide"}, Position: 3}})
+	engine.db.SetSetting("rules", string(existing))
+
+	rule, err := engine.AddRule(Rule{
+		Name:       "Kubernetes",
+		Enabled:    true,
+		Conditions: []Condition{{Field: "article_title", Operator: "contains", Value: "kubernetes"}},
+		Actions:    []string{"read_later"},
+	})
+	if err != nil {
+		t.Fatalf("AddRule error: %v", err)
+	}
+	if rule.ID <= 5 || rule.Position != 4 || rule.Conditions[0].ID != 1 {
+		t.Errorf("unexpected rule: %+v", rule)
+	}
+
+	var saved []Rule
+	rulesJSON, _ := engine.db.GetSetting("rules")
+	json.Unmarshal([]byte(rulesJSON), &saved)
+	if len(saved) != 2 || saved[1].Name != "Kubernetes" {
+		t.Errorf("expected the rule to be appended, got %+v", saved)
+	}
+
+	invalid := []Rule{
+		{Actions: []string{"hide"}},
+		{Name: "No actions"},
+		{Name: "Bad action", Actions: []string{"delete"}},
+		{Name: "Bad field", Actions: []string{"hide"}, Conditions: []Condition{{Field: "author"}}},
+	}
+	for _, r := range invalid {
+		if _, err := engine.AddRule(r); err == nil {
+			t.Errorf("expected %q to be rejected", r.Name)
+		}
+	}
+}
+
+func TestEvaluateCondition_Relevance(t *testing.T) {
+	score := 72.5
+	scored := models.Article{ID: 1, RelevanceScore: &score}
+	unscored := models.Article{ID: 2}
+
+	cases := []struct {
+		article   models.Article
+		condition Condition
+		want      bool
+	}{
+		{scored, Condition{Field: "relevance_above", Value: "70"}, true},
+		{scored, Condition{Field: "relevance_above", Value: "72.5"}, true},
+		{scored, Condition{Field: "relevance_above", Value: "80"}, false},
+		{scored, Condition{Field: "relevance_below", Value: "80"}, true},
+		{scored, Condition{Field: "relevance_below", Value: "70"}, false},
+		{scored, Condition{Field: "relevance_below", Value: ""}, true},
+		{unscored, Condition{Field: "relevance_above", Value: "10"}, false},
+		{unscored, Condition{Field: "relevance_below", Value: "90"}, false},
+	}
+	for _, c := range cases {
+		got := evaluateCondition(c.article, c.condition, nil, nil, nil, nil, nil)
+		if got != c.want {
+			t.Errorf("%s %s on article %d: expected %v, got %v", c.condition.Field, c.condition.Value, c.article.ID, c.want, got)
+		}
+	}
+}
+
+func TestEvaluateCondition_Tags(t *testing.T) {
+	article := models.Article{ID: 1, Topics: []string{"Space", "Science"}, Entities: []string{"NASA"}}
+	unclassified := models.Article{ID: 2}
+
+	cases := []struct {
+		article   models.Article
+		condition Condition
+		want      bool
+	}{
+		{article, Condition{Field: "topic", Values: []string{"sports", "space"}}, true},
+		{article, Condition{Field: "topic", Values: []string{"Sports"}}, false},
+		{article, Condition{Field: "topic", Values: []string{"Sports"}, Negate: true}, true},
+		{article, Condition{Field: "entity", Value: "nasa"}, true},
+		{article, Condition{Field: "entity", Value: "NAS"}, false},
+		{article, Condition{Field: "entity"}, true},
+		{unclassified, Condition{Field: "topic", Values: []string{"Space"}}, false},
+	}
+	for _, c := range cases {
+		got := evaluateCondition(c.article, c.condition, nil, nil, nil, nil, nil)
+		if got != c.want {
+			t.Errorf("%s %v%s on article %d: expected %v, got %v", c.condition.Field, c.condition.Values, c.condition.Value, c.article.ID, c.want, got)
+		}
+	}
+}
+
+func TestEvaluateCondition_Language(t *testing.T) {
+	article := models.Article{ID: 1, Language: "fr"}
+	undetected := models.Article{ID: 2}
+
+	cases := []struct {
+		article   models.Article
+		condition Condition
+		want      bool
+	}{
+		{article, Condition{Field: "language", Values: []string{"en", "fr"}}, true},
+		{article, Condition{Field: "language", Values: []string{"en"}}, false},
+		{article, Condition{Field: "language", Values: []string{"en"}, Negate: true}, true},
+		{article, Condition{Field: "language"}, true},
+		{undetected, Condition{Field: "language", Values: []string{"en"}}, false},
+	}
+	for _, c := range cases {
+		got := evaluateCondition(c.article, c.condition, nil, nil, nil, nil, nil)
+		if got != c.want {
+			t.Errorf("%s %v on article %d: expected %v, got %v", c.condition.Field, c.condition.Values, c.article.ID, c.want, got)
+		}
+	}
+}
+
+func TestEvaluateCondition_Keyword(t *testing.T) {
+	article := models.Article{ID: 1, Keywords: []string{"solid state batteries", "electric cars"}}
+	noKeywords := models.Article{ID: 2}
+
+	cases := []struct {
+		article   models.Article
+		condition Condition
+		want      bool
+	}{
+		{article, Condition{Field: "keyword", Value: "Battery"}, false},
+		{article, Condition{Field: "keyword", Value: "Batteries"}, true},
+		{article, Condition{Field: "keyword", Values: []string{"hydrogen", "electric car"}}, true},
+		{article, Condition{Field: "keyword", Value: "hydrogen", Negate: true}, true},
+		{article, Condition{Field: "keyword"}, true},
+		{noKeywords, Condition{Field: "keyword", Value: "batteries"}, false},
+	}
+	for _, c := range cases {
+		got := evaluateCondition(c.article, c.condition, nil, nil, nil, nil, nil)
+		if got != c.want {
+			t.Errorf("%s %v%s on article %d: expected %v, got %v", c.condition.Field, c.condition.Values, c.condition.Value, c.article.ID, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+// Package semantic embeds articles as vectors and finds related articles by cosine similarity.
+//
+// A background indexer embeds the title, summary and cached content of articles that have
+// no embedding for the configured model yet. Search embeds a query the same way and ranks
+// the archive by similarity, so related coverage is found even when the wording differs.
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/aiprofile"
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// Feature is the name embedding usage is recorded under in the usage ledger and budgets
+const Feature = "embedding"
+
+const (
+	// maxTextChars caps the text embedded per article, keeping requests within model limits
+	maxTextChars = 6000
+	// maxBatchesPerRun bounds the work of one indexer run, new articles are picked up by the next one
+	maxBatchesPerRun = 20
+	// requestTimeout is the timeout of one embeddings request
+	requestTimeout = 60 * time.Second
+)
+
+// ErrDisabled is returned when semantic search is used while embeddings are disabled
+var ErrDisabled = errors.New("semantic search is disabled")
+
+// Embedder turns texts into embedding vectors, see ai.Client.Embed
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Match is an article found by semantic search with its similarity to the query
+type Match struct {
+	models.Article
+	Score float64 `json:"score"`
+}
+
+// Status describes the progress of the embedding index
+type Status struct {
+	Enabled bool   `json:"enabled"`
+	Model   string `json:"model"`
+	Indexed int64  `json:"indexed"`
+}
+
+// Service indexes and searches article embeddings
+type Service struct {
+	db          *database.DB
+	tracker     *aiusage.Tracker
+	newEmbedder func(profile database.AIProfile) Embedder
+}
+
+// NewService creates a semantic search service. Usage is recorded with tracker, which may be nil.
+func NewService(db *database.DB, tracker *aiusage.Tracker) *Service {
+	s := &Service{db: db, tracker: tracker}
+	s.newEmbedder = s.newClient
+	return s
+}
+
+// Enabled reports whether embeddings are enabled in the settings
+func (s *Service) Enabled() bool {
+	enabled, _ := s.db.GetSetting("ai_embedding_enabled")
+	return enabled == "true"
+}
+
+// Profile returns the AI profile used for embeddings: the global AI settings with the
+// embedding model, and the embedding endpoint when one is configured
+func (s *Service) Profile() database.AIProfile {
+	profile := aiprofile.DefaultProfile(s.db)
+	if endpoint, _ := s.db.GetSetting("ai_embedding_endpoint"); strings.TrimSpace(endpoint) != "" {
+		profile.Endpoint = strings.TrimSpace(endpoint)
+	}
+	model, _ := s.db.GetSetting("ai_embedding_model")
+	profile.Model = strings.TrimSpace(model)
+	return profile
+}
+
+// Status returns the state of the embedding index
+func (s *Service) Status() (Status, error) {
+	profile := s.Profile()
+	indexed, err := s.db.CountArticleEmbeddings(profile.Model)
+	if err != nil {
+		return Status{}, err
+	}
+	return Status{Enabled: s.Enabled(), Model: profile.Model, Indexed: indexed}, nil
+}
+
+// Run indexes new articles every interval until ctx is done.
+// Nothing is indexed while embeddings are disabled.
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if s.Enabled() {
+			s.indexNew(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// indexNew drops stale embeddings and embeds pending articles, at most maxBatchesPerRun batches
+func (s *Service) indexNew(ctx context.Context) {
+	if removed, err := s.db.PruneArticleEmbeddings(s.Profile().Model); err != nil {
+		log.Printf("Failed to prune article embeddings: %v", err)
+	} else if removed > 0 {
+		log.Printf("Removed %d stale article embeddings", removed)
+	}
+
+	total := 0
+	for i := 0; i < maxBatchesPerRun; i++ {
+		n, err := s.IndexPending(ctx, 32)
+		total += n
+		if err != nil {
+			log.Printf("Failed to embed articles: %v", err)
+			break
+		}
+		if n == 0 {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("Embedded %d articles for semantic search", total)
+	}
+}
+
+// IndexPending embeds up to batchSize articles that have no embedding for the current model
+// and returns how many were embedded
+func (s *Service) IndexPending(ctx context.Context, batchSize int) (int, error) {
+	profile := s.Profile()
+	if profile.Model == "" {
+		return 0, fmt.Errorf("no embedding model configured")
+	}
+	if s.tracker != nil && s.tracker.IsFeatureLimitReached(Feature) {
+		return 0, aiprofile.ErrLimitReached
+	}
+
+	candidates, err := s.db.GetArticlesWithoutEmbedding(profile.Model, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Articles without any text get an empty vector so they are not retried
+	var texts []string
+	var ids []int64
+	for _, c := range candidates {
+		text := ArticleText(c.Title, c.Summary, c.Content)
+		if text == "" {
+			if err := s.db.SaveArticleEmbedding(c.ArticleID, profile.Model, nil); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		texts = append(texts, text)
+		ids = append(ids, c.ArticleID)
+	}
+	if len(texts) == 0 {
+		return len(candidates), nil
+	}
+
+	vectors, err := s.embed(ctx, profile, texts)
+	if err != nil {
+		return 0, err
+	}
+	for i, vector := range vectors {
+		if err := s.db.SaveArticleEmbedding(ids[i], profile.Model, vector); err != nil {
+			return i, err
+		}
+	}
+	return len(candidates), nil
+}
+
+// Search returns the articles most similar to a free-text query
+func (s *Service) Search(ctx context.Context, query string, limit int) ([]Match, error) {
+	if !s.Enabled() {
+		return nil, ErrDisabled
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []Match{}, nil
+	}
+
+	profile := s.Profile()
+	if s.tracker != nil && s.tracker.IsFeatureLimitReached(Feature) {
+		return nil, aiprofile.ErrLimitReached
+	}
+	vectors, err := s.embed(ctx, profile, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	return s.rank(profile.Model, vectors[0], 0, limit)
+}
+
+// Similar returns the articles most similar to an already embedded article ("more like this")
+func (s *Service) Similar(articleID int64, limit int) ([]Match, error) {
+	if !s.Enabled() {
+		return nil, ErrDisabled
+	}
+
+	model := s.Profile().Model
+	vector, found, err := s.db.GetArticleEmbedding(articleID, model)
+	if err != nil {
+		return nil, err
+	}
+	if !found || len(vector) == 0 {
+		return []Match{}, nil
+	}
+	return s.rank(model, vector, articleID, limit)
+}
+
+// rank scores every embedded article against vector and returns the best limit matches,
+// leaving out the article excludeID
+func (s *Service) rank(model string, vector []float32, excludeID int64, limit int) ([]Match, error) {
+	type scored struct {
+		id    int64
+		score float64
+	}
+	var scores []scored
+	err := s.db.ForEachArticleEmbedding(model, func(articleID int64, candidate []float32) {
+		if articleID == excludeID || len(candidate) == 0 {
+			return
+		}
+		scores = append(scores, scored{articleID, Cosine(vector, candidate)})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].score > scores[j].score })
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+
+	ids := make([]int64, len(scores))
+	for i, sc := range scores {
+		ids[i] = sc.id
+	}
+	articles, err := s.db.GetArticlesByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]models.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	matches := make([]Match, 0, len(scores))
+	for _, sc := range scores {
+		if article, ok := byID[sc.id]; ok {
+			matches = append(matches, Match{Article: article, Score: sc.score})
+		}
+	}
+	return matches, nil
+}
+
+// embed requests embeddings for texts and records the usage
+func (s *Service) embed(ctx context.Context, profile database.AIProfile, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	ctx, collector := ai.WithUsageCollector(ctx)
+
+	vectors, err := s.newEmbedder(profile).Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(vectors))
+	}
+
+	if s.tracker != nil {
+		var estimate ai.TokenUsage
+		for _, text := range texts {
+			estimate.InputTokens += aiusage.EstimateTokens(text)
+		}
+		tokens := s.tracker.RecordCalls(Feature, profile.Name, collector.Calls(), estimate)
+		aiprofile.RecordUsage(s.db, profile, tokens)
+	}
+	return vectors, nil
+}
+
+// newClient creates an AI client for a profile, using the global proxy if configured
+func (s *Service) newClient(profile database.AIProfile) Embedder {
+	config := aiprofile.ClientConfig(profile, requestTimeout)
+	httpClient, err := utils.CreateHTTPClientWithProxy(s.db, requestTimeout)
+	if err != nil {
+		log.Printf("Failed to create HTTP client with proxy: %v", err)
+		return ai.NewClient(config)
+	}
+	return ai.NewClientWithHTTPClient(config, httpClient)
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// ArticleText builds the text embedded for an article from its title, summary and HTML content,
+// truncated to maxTextChars
+func ArticleText(title, articleSummary, content string) string {
+	var parts []string
+	for _, part := range []string{title, articleSummary, content} {
+		part = tagPattern.ReplaceAllString(part, " ")
+		part = strings.Join(strings.Fields(html.UnescapeString(part)), " ")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	text := strings.Join(parts, "\n\n")
+
+	runes := []rune(text)
+	if len(runes) > maxTextChars {
+		text = string(runes[:maxTextChars])
+	}
+	return text
+}
+
+// Cosine returns the cosine similarity of two vectors, or 0 if they differ in length or are zero
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,134 @@
+package semantic
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+)
+
+// keywordEmbedder embeds texts by the presence of a few topic words
+type keywordEmbedder struct {
+	calls int
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		text = strings.ToLower(text)
+		vector := make([]float32, 3)
+		for j, topic := range []string{"space", "football", "election"} {
+			if strings.Contains(text, topic) {
+				vector[j] = 1
+			}
+		}
+		vectors[i] = vector
+	}
+	return vectors, nil
+}
+
+func setupService(t *testing.T) (*Service, *database.DB, *keywordEmbedder) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	db.SetSetting("ai_embedding_enabled", "true")
+	db.SetSetting("ai_embedding_model", "test-embed")
+
+	embedder := &keywordEmbedder{}
+	s := NewService(db, aiusage.NewTracker(db))
+	s.newEmbedder = func(database.AIProfile) Embedder { return embedder }
+	return s, db, embedder
+}
+
+func insertArticle(t *testing.T, db *database.DB, feedID int64, title, summary string) int64 {
+	t.Helper()
+	res, err := db.Exec(`INSERT INTO articles (feed_id, title, url, summary, published_at, unique_id) VALUES (?, ?, ?, ?, datetime('now'), ?)`,
+		feedID, title, "https://example.com/"+title, summary, title)
+	if err != nil {
+		t.Fatalf("insert article error: %v", err)
+	}
+	id, _ := res.LastInsertId()
+	return id
+}
+
+func TestSearchAndSimilar(t *testing.T) {
+	s, db, embedder := setupService(t)
+
+	res, err := db.Exec(`INSERT INTO feeds (title, url) VALUES ('Feed', 'https://example.com/feed')`)
+	if err != nil {
+		t.Fatalf("insert feed error: %v", err)
+	}
+	feedID, _ := res.LastInsertId()
+	rocket := insertArticle(t, db, feedID, "Rocket reaches orbit", "A new launch to space")
+	insertArticle(t, db, feedID, "Cup final", "The football season ends")
+	station := insertArticle(t, db, feedID, "Station crew returns", "Astronauts back from space")
+
+	n, err := s.IndexPending(context.Background(), 10)
+	if err != nil || n != 3 {
+		t.Fatalf("IndexPending: n=%d err=%v", n, err)
+	}
+	if n, _ := s.IndexPending(context.Background(), 10); n != 0 {
+		t.Errorf("expected nothing left to index, got %d", n)
+	}
+
+	matches, err := s.Search(context.Background(), "space news", 2)
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Score < 0.99 || matches[1].Score < 0.99 {
+		t.Errorf("expected both space articles to match, got %+v", matches)
+	}
+
+	similar, err := s.Similar(rocket, 5)
+	if err != nil {
+		t.Fatalf("Similar error: %v", err)
+	}
+	if len(similar) != 2 || similar[0].ID != station || similar[0].FeedTitle != "Feed" {
+		t.Errorf("expected the station article first, got %+v", similar)
+	}
+	for _, m := range similar {
+		if m.ID == rocket {
+			t.Errorf("expected the article itself to be excluded")
+		}
+	}
+	if embedder.calls != 2 {
+		t.Errorf("expected one call for indexing and one for the query, got %d", embedder.calls)
+	}
+
+	db.SetSetting("ai_embedding_enabled", "false")
+	if _, err := s.Search(context.Background(), "space", 2); err != ErrDisabled {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+}
+
+func TestArticleText(t *testing.T) {
+	text := ArticleText("Title", "", "<p>Hello&nbsp;<b>world</b></p>\n\n<p>again</p>")
+	if text != "Title\n\nHello world again" {
+		t.Errorf("unexpected text: %q", text)
+	}
+	if long := ArticleText(strings.Repeat("é", maxTextChars+10), "", ""); len([]rune(long)) != maxTextChars {
+		t.Errorf("expected text to be truncated to %d runes, got %d", maxTextChars, len([]rune(long)))
+	}
+}
+
+func TestCosine(t *testing.T) {
+	if got := Cosine([]float32{1, 0}, []float32{1, 0}); math.Abs(got-1) > 1e-9 {
+		t.Errorf("identical vectors: got %f", got)
+	}
+	if got := Cosine([]float32{1, 0}, []float32{0, 1}); got != 0 {
+		t.Errorf("orthogonal vectors: got %f", got)
+	}
+	if got := Cosine([]float32{1, 0}, []float32{1, 0, 0}); got != 0 {
+		t.Errorf("mismatched lengths: got %f", got)
+	}
+}
@@ -2,6 +2,7 @@ package statistics
 
 import (
 	"log"
+	"sort"
 	"time"
 
 	"MrRSS/internal/database"
@@ -21,6 +22,8 @@ type DB interface {
 	GetDailyStatsForPeriod(startDate, endDate string) (map[string]map[string]int, error)
 	GetTotalStats() (map[string]int, error)
 	GetAvailableMonths() ([]string, error)
+	GetAIUsageBreakdown(startDate, endDate string) ([]database.AIUsageBreakdown, error)
+	GetDailyAIUsage(startDate, endDate string) (map[string]map[string]int64, error)
 	WaitForReady()
 }
 
@@ -46,6 +49,26 @@ type StatSummary struct {
 	HasPrevious  bool                      `json:"has_previous"`
 	HasNext      bool                      `json:"has_next"`
 	DisplayLabel string                    `json:"display_label"`
+	AIUsage      *AIUsageSummary           `json:"ai_usage,omitempty"`
+}
+
+// AIUsageSummary breaks down the AI usage ledger of a period
+type AIUsageSummary struct {
+	Totals     AIUsageGroup                `json:"totals"`
+	ByFeature  []AIUsageGroup              `json:"by_feature"`
+	ByProvider []AIUsageGroup              `json:"by_provider"`
+	ByModel    []AIUsageGroup              `json:"by_model"`
+	Details    []database.AIUsageBreakdown `json:"details"`
+	DailyData  map[string]map[string]int64 `json:"daily_data,omitempty"` // date -> feature -> tokens
+}
+
+// AIUsageGroup is the aggregated AI usage of one feature, provider or model
+type AIUsageGroup struct {
+	Key          string  `json:"key"`
+	Calls        int64   `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Cost         float64 `json:"cost"`
 }
 
 // NewService creates a new statistics service
@@ -152,6 +175,11 @@ func (s *Service) GetStatistics(period StatPeriod, offset int) (*StatSummary, er
 			return nil, err
 		}
 
+		aiUsage, err := s.GetAIUsage("", "")
+		if err != nil {
+			return nil, err
+		}
+
 		return &StatSummary{
 			Period:       PeriodAll,
 			StartDate:    "",
@@ -161,6 +189,7 @@ func (s *Service) GetStatistics(period StatPeriod, offset int) (*StatSummary, er
 			HasPrevious:  false,
 			HasNext:      false,
 			DisplayLabel: "总计",
+			AIUsage:      aiUsage,
 		}, nil
 
 	default:
@@ -193,12 +222,18 @@ func (s *Service) GetStatistics(period StatPeriod, offset int) (*StatSummary, er
 		return nil, err
 	}
 
+	aiUsage, err := s.GetAIUsage(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &StatSummary{
 		Period:       period,
 		StartDate:    startDate,
 		EndDate:      endDate,
 		Totals:       totals,
 		DailyData:    dailyData,
+		AIUsage:      aiUsage,
 		CanNavigate:  true,
 		HasPrevious:  hasPrevious,
 		HasNext:      hasNext,
@@ -220,6 +255,11 @@ func (s *Service) GetStatisticsForRange(startDate, endDate string) (*StatSummary
 		return nil, err
 	}
 
+	aiUsage, err := s.GetAIUsage(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
 	startParsed, _ := time.Parse("2006-01-02", startDate)
 	endParsed, _ := time.Parse("2006-01-02", endDate)
 
@@ -229,6 +269,7 @@ func (s *Service) GetStatisticsForRange(startDate, endDate string) (*StatSummary
 		EndDate:      endDate,
 		Totals:       totals,
 		DailyData:    dailyData,
+		AIUsage:      aiUsage,
 		CanNavigate:  false,
 		HasPrevious:  false,
 		HasNext:      false,
@@ -236,6 +277,57 @@ func (s *Service) GetStatisticsForRange(startDate, endDate string) (*StatSummary
 	}, nil
 }
 
+// GetAIUsage breaks down the AI usage ledger for a date range by feature, provider and model.
+// Empty dates leave the range open.
+func (s *Service) GetAIUsage(startDate, endDate string) (*AIUsageSummary, error) {
+	details, err := s.db.GetAIUsageBreakdown(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &AIUsageSummary{Details: details}
+	summary.ByFeature = groupAIUsage(details, func(b database.AIUsageBreakdown) string { return b.Feature })
+	summary.ByProvider = groupAIUsage(details, func(b database.AIUsageBreakdown) string { return b.Provider })
+	summary.ByModel = groupAIUsage(details, func(b database.AIUsageBreakdown) string { return b.Model })
+	for _, group := range summary.ByFeature {
+		summary.Totals.Calls += group.Calls
+		summary.Totals.InputTokens += group.InputTokens
+		summary.Totals.OutputTokens += group.OutputTokens
+		summary.Totals.Cost += group.Cost
+	}
+
+	if startDate != "" {
+		summary.DailyData, err = s.db.GetDailyAIUsage(startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// groupAIUsage sums breakdown rows by key, ordered by total tokens
+func groupAIUsage(details []database.AIUsageBreakdown, key func(database.AIUsageBreakdown) string) []AIUsageGroup {
+	groups := make([]AIUsageGroup, 0)
+	index := make(map[string]int)
+	for _, b := range details {
+		k := key(b)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, AIUsageGroup{Key: k})
+		}
+		groups[i].Calls += b.Calls
+		groups[i].InputTokens += b.InputTokens
+		groups[i].OutputTokens += b.OutputTokens
+		groups[i].Cost += b.Cost
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].InputTokens+groups[i].OutputTokens > groups[j].InputTokens+groups[j].OutputTokens
+	})
+	return groups
+}
+
 // GetAllTimeStats retrieves all-time statistics
 func (s *Service) GetAllTimeStats() (map[string]int, error) {
 	return s.db.GetTotalStats()
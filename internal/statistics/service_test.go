@@ -0,0 +1,55 @@
+package statistics
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+)
+
+func TestGetStatistics_IncludesAIUsage(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	defer db.Close()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+
+	for _, record := range []database.AIUsageRecord{
+		{Feature: "chat", Provider: "openai", Model: "gpt-4o-mini", InputTokens: 100, OutputTokens: 20, Cost: 0.01},
+		{Feature: "translation", Provider: "openai", Model: "gpt-4o-mini", InputTokens: 500, OutputTokens: 500, Cost: 0.05},
+		{Feature: "translation", Provider: "ollama", Model: "llama3", InputTokens: 50, OutputTokens: 50},
+	} {
+		if err := db.AddAIUsageRecord(&record); err != nil {
+			t.Fatalf("AddAIUsageRecord error: %v", err)
+		}
+	}
+
+	summary, err := NewService(db).GetStatistics(PeriodMonth, 0)
+	if err != nil {
+		t.Fatalf("GetStatistics error: %v", err)
+	}
+	usage := summary.AIUsage
+	if usage == nil {
+		t.Fatal("expected an AI usage breakdown")
+	}
+	if usage.Totals.Calls != 3 || usage.Totals.InputTokens != 650 || usage.Totals.OutputTokens != 570 {
+		t.Errorf("unexpected totals: %+v", usage.Totals)
+	}
+	if len(usage.ByFeature) != 2 || usage.ByFeature[0].Key != "translation" || usage.ByFeature[0].Calls != 2 {
+		t.Errorf("unexpected per-feature usage: %+v", usage.ByFeature)
+	}
+	if len(usage.ByProvider) != 2 || usage.ByProvider[0].Key != "openai" || usage.ByProvider[0].InputTokens != 600 {
+		t.Errorf("unexpected per-provider usage: %+v", usage.ByProvider)
+	}
+	if usage.DailyData[time.Now().Format("2006-01-02")]["chat"] != 120 {
+		t.Errorf("unexpected daily usage: %v", usage.DailyData)
+	}
+
+	all, err := NewService(db).GetStatistics(PeriodAll, 0)
+	if err != nil || all.AIUsage == nil || len(all.AIUsage.ByModel) != 2 {
+		t.Errorf("expected the all-time summary to include AI usage, got %+v (%v)", all.AIUsage, err)
+	}
+}
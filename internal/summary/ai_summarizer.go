@@ -1,6 +1,7 @@
 package summary
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -20,6 +21,8 @@ type AISummarizer struct {
 	CustomHeaders string
 	Language      string // User's language setting (e.g., "en", "zh")
 	client        *ai.Client
+	userPrompt    func(text string, targetWords int) string
+	style         SummaryStyle
 }
 
 // DBInterface defines the minimal database interface needed for proxy settings
@@ -30,22 +33,7 @@ type DBInterface interface {
 
 // CreateHTTPClientWithProxy creates an HTTP client with global proxy settings if enabled
 func CreateHTTPClientWithProxy(db DBInterface, timeout time.Duration) (*http.Client, error) {
-	var proxyURL string
-
-	// Check if global proxy is enabled
-	proxyEnabled, _ := db.GetSetting("proxy_enabled")
-	if proxyEnabled == "true" {
-		// Build proxy URL from global settings
-		proxyType, _ := db.GetSetting("proxy_type")
-		proxyHost, _ := db.GetSetting("proxy_host")
-		proxyPort, _ := db.GetSetting("proxy_port")
-		proxyUsername, _ := db.GetEncryptedSetting("proxy_username")
-		proxyPassword, _ := db.GetEncryptedSetting("proxy_password")
-		proxyURL = utils.BuildProxyURL(proxyType, proxyHost, proxyPort, proxyUsername, proxyPassword)
-	}
-
-	// Create HTTP client with or without proxy
-	return utils.CreateHTTPClient(proxyURL, timeout)
+	return utils.CreateHTTPClientWithProxy(db, timeout)
 }
 
 // NewAISummarizer creates a new AI summarizer with the given credentials.
@@ -128,6 +116,17 @@ func (s *AISummarizer) SetCustomHeaders(headers string) {
 	s.recreateClient()
 }
 
+// SetUserPrompt makes the summarizer request summaries with the prompt built from the cleaned
+// text and the target number of words, such as a prompt template, instead of the default request.
+func (s *AISummarizer) SetUserPrompt(build func(text string, targetWords int) string) {
+	s.userPrompt = build
+}
+
+// SetStyle sets the style of the summaries, standard by default
+func (s *AISummarizer) SetStyle(style SummaryStyle) {
+	s.style = style
+}
+
 // SetLanguage sets the language for the summarizer.
 // If language is empty, it keeps the current language setting.
 func (s *AISummarizer) SetLanguage(language string) {
@@ -158,6 +157,35 @@ func (s *AISummarizer) getDefaultSystemPrompt() string {
 	return "You are a helpful AI assistant that creates clear, well-formatted summaries. When listing items, features, or points, prefer using bullet points or numbered lists to organize the content. Make the summary scannable and easy to read."
 }
 
+// getStyleInstruction returns the localized instruction for the summary style, empty for standard summaries.
+func (s *AISummarizer) getStyleInstruction() string {
+	zh := strings.HasPrefix(s.Language, "zh")
+	switch s.style {
+	case TLDR:
+		if zh {
+			return "只用一到两句话概括文章的核心要点（TL;DR），不要使用列表。"
+		}
+		return "Write a TL;DR: the gist of the article in one or two sentences, without lists."
+	case Bullets:
+		if zh {
+			return "将摘要写成项目符号列表，每个要点一行，不要添加引言或结语。"
+		}
+		return "Write the summary as a bulleted list of key points, one point per line, without introduction or conclusion."
+	case Quotes:
+		if zh {
+			return "从文章中逐字摘录最重要的几句话，每句作为一个 Markdown 引用块，不要改写。"
+		}
+		return "Quote the most important sentences of the article verbatim, each as a Markdown blockquote, without rewording them."
+	case ELI5:
+		if zh {
+			return "像给五岁孩子讲解一样，用简单的词语和短句解释文章内容，避免术语。"
+		}
+		return "Explain the article like I'm five: use simple words and short sentences, and avoid jargon."
+	default:
+		return ""
+	}
+}
+
 // getUserPrompt generates a localized user prompt with target language specification.
 func (s *AISummarizer) getUserPrompt(targetWords int, text string) string {
 	// Check if language starts with "zh" to handle locale codes like "zh", "zh-CN", "zh-TW", etc.
@@ -170,38 +198,81 @@ func (s *AISummarizer) getUserPrompt(targetWords int, text string) string {
 // Summarize generates a summary of the given text using an OpenAI-compatible API.
 // Automatically detects and adapts to different API formats (Gemini, OpenAI, Ollama).
 func (s *AISummarizer) Summarize(text string, length SummaryLength) (SummaryResult, error) {
+	return s.SummarizeWithContext(context.Background(), text, length)
+}
+
+// SummarizeWithContext generates a summary like Summarize. The AI request is aborted when ctx is done.
+func (s *AISummarizer) SummarizeWithContext(ctx context.Context, text string, length SummaryLength) (SummaryResult, error) {
+	systemPrompt, userPrompt, tooShort := s.buildPrompts(text, length)
+	if tooShort != nil {
+		return *tooShort, nil
+	}
+
+	// Use the universal client which handles format detection automatically
+	result, err := s.client.RequestWithThinkingContext(ctx, systemPrompt, userPrompt)
+	if err != nil {
+		return SummaryResult{}, err
+	}
+
+	// Extract thinking content using shared utility
+	thinking := ai.ExtractThinking(result.Content)
+	summary := ai.RemoveThinkingTags(result.Content)
+
+	return newAISummaryResult(summary, thinking), nil
+}
+
+// SummarizeStream generates a summary like Summarize, calling onChunk with each
+// content or thinking delta as the AI produces it.
+func (s *AISummarizer) SummarizeStream(ctx context.Context, text string, length SummaryLength, onChunk ai.StreamCallback) (SummaryResult, error) {
+	systemPrompt, userPrompt, tooShort := s.buildPrompts(text, length)
+	if tooShort != nil {
+		return *tooShort, nil
+	}
+
+	result, err := s.client.StreamWithThinking(ctx, systemPrompt, userPrompt, onChunk)
+	if err != nil {
+		return SummaryResult{}, err
+	}
+
+	return newAISummaryResult(result.Content, result.Thinking), nil
+}
+
+// buildPrompts builds the system and user prompts for summarizing text.
+// If the text is too short to summarize, the result to return instead is set.
+func (s *AISummarizer) buildPrompts(text string, length SummaryLength) (string, string, *SummaryResult) {
 	// Clean the text first
 	cleanedText := cleanText(text)
 
 	// Check if text is too short
 	if len(cleanedText) < MinContentLength {
-		return SummaryResult{
+		return "", "", &SummaryResult{
 			Summary:    cleanedText,
 			IsTooShort: true,
-		}, nil
+		}
 	}
 
-	targetWords := getTargetWordCount(length)
+	targetWords := TargetWordCount(length)
 
 	// Use custom system prompt if provided, otherwise use default
 	systemPrompt := s.SystemPrompt
 	if systemPrompt == "" {
 		systemPrompt = s.getDefaultSystemPrompt()
 	}
+	// The style applies to custom and template prompts alike
+	if instruction := s.getStyleInstruction(); instruction != "" {
+		systemPrompt += "\n\n" + instruction
+	}
 
-	// Generate localized user prompt with target language specification
-	userPrompt := s.getUserPrompt(targetWords, cleanedText)
-
-	// Use the universal client which handles format detection automatically
-	result, err := s.client.RequestWithThinking(systemPrompt, userPrompt)
-	if err != nil {
-		return SummaryResult{}, err
+	if s.userPrompt != nil {
+		return systemPrompt, s.userPrompt(cleanedText, targetWords), nil
 	}
 
-	// Extract thinking content using shared utility
-	thinking := ai.ExtractThinking(result.Content)
-	summary := ai.RemoveThinkingTags(result.Content)
+	// Generate localized user prompt with target language specification
+	return systemPrompt, s.getUserPrompt(targetWords, cleanedText), nil
+}
 
+// newAISummaryResult builds the result for an AI generated summary
+func newAISummaryResult(summary, thinking string) SummaryResult {
 	// Count sentences in the summary
 	sentences := splitSentences(summary)
 
@@ -210,5 +281,5 @@ func (s *AISummarizer) Summarize(text string, length SummaryLength) (SummaryResu
 		Thinking:      thinking,
 		SentenceCount: len(sentences),
 		IsTooShort:    false,
-	}, nil
+	}
 }
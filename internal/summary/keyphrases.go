@@ -0,0 +1,200 @@
+package summary
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxPhraseWords is the number of words of the longest key phrase
+	maxPhraseWords = 3
+	// cooccurrenceWindow is the number of following tokens a token is linked to in the TextRank graph
+	cooccurrenceWindow = 3
+)
+
+// KeyPhrase is a key phrase of a text with its score, from 0 to 1 relative to the best phrase
+type KeyPhrase struct {
+	Phrase string  `json:"phrase"`
+	Score  float64 `json:"score"`
+}
+
+// KeyPhrases returns up to limit key phrases of text, best first, in lowercase.
+//
+// Languages separating words with spaces are scored with RAKE: candidates are runs of up to
+// three words delimited by stopwords and punctuation, each word scores its degree over its
+// frequency, and a phrase scores the sum of its words every time it occurs. Chinese, Japanese
+// and Thai are scored with TextRank over the co-occurrence graph of their tokens. Phrases
+// contained in a better phrase are left out.
+func (s *Summarizer) KeyPhrases(text string, limit int) []KeyPhrase {
+	text = strings.ToLower(cleanText(text))
+
+	var scores map[string]float64
+	switch {
+	case s.language == "zh" || s.language == "ja" || s.language == "th" || (s.language == "" && containsHan(text)):
+		scores = textRankPhrases(tokenizeLanguage(text, s.language))
+	default:
+		scores = rakePhrases(text, s.language)
+	}
+	return topPhrases(scores, limit)
+}
+
+// rakePhrases scores the candidate phrases of lowercase text in a language with RAKE
+func rakePhrases(text, lang string) map[string]float64 {
+	var phrases [][]string
+	var current []string
+	var word strings.Builder
+
+	endPhrase := func() {
+		if len(current) > 0 && len(current) <= maxPhraseWords {
+			phrases = append(phrases, current)
+		}
+		current = nil
+	}
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := strings.Trim(word.String(), "-")
+		word.Reset()
+		// Stopwords, short words and numbers delimit phrases
+		if utf8.RuneCountInString(w) < 3 || isStopWordIn(w, lang) || isNumber(w) {
+			endPhrase()
+			return
+		}
+		current = append(current, w)
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word.WriteRune(r)
+		case r == '-' && word.Len() > 0:
+			// Hyphenated words are kept whole
+			word.WriteRune(r)
+		case unicode.IsSpace(r):
+			endWord()
+		default:
+			endWord()
+			endPhrase()
+		}
+	}
+	endWord()
+	endPhrase()
+
+	frequency := make(map[string]float64)
+	degree := make(map[string]float64)
+	for _, phrase := range phrases {
+		for _, w := range phrase {
+			frequency[w]++
+			degree[w] += float64(len(phrase))
+		}
+	}
+
+	scores := make(map[string]float64)
+	for _, phrase := range phrases {
+		score := 0.0
+		for _, w := range phrase {
+			score += degree[w] / frequency[w]
+		}
+		scores[strings.Join(phrase, " ")] += score
+	}
+	return scores
+}
+
+// textRankPhrases scores tokens with TextRank over the graph linking tokens that occur within
+// cooccurrenceWindow of each other. Single characters are left out.
+func textRankPhrases(tokens []string) map[string]float64 {
+	neighbors := make(map[string]map[string]bool)
+	for i, t := range tokens {
+		if utf8.RuneCountInString(t) < 2 {
+			continue
+		}
+		if neighbors[t] == nil {
+			neighbors[t] = make(map[string]bool)
+		}
+		for j := i + 1; j < len(tokens) && j <= i+cooccurrenceWindow; j++ {
+			u := tokens[j]
+			if u == t || utf8.RuneCountInString(u) < 2 {
+				continue
+			}
+			if neighbors[u] == nil {
+				neighbors[u] = make(map[string]bool)
+			}
+			neighbors[t][u] = true
+			neighbors[u][t] = true
+		}
+	}
+
+	scores := make(map[string]float64, len(neighbors))
+	for t := range neighbors {
+		scores[t] = 1.0
+	}
+
+	// Damping factor and iterations, as for sentences
+	d := 0.85
+	for iter := 0; iter < 30; iter++ {
+		newScores := make(map[string]float64, len(scores))
+		for t, linked := range neighbors {
+			sum := 0.0
+			for u := range linked {
+				sum += scores[u] / float64(len(neighbors[u]))
+			}
+			newScores[t] = (1 - d) + d*sum
+		}
+		scores = newScores
+	}
+	return scores
+}
+
+// topPhrases returns up to limit of the scored phrases, best first, leaving out phrases
+// contained in a better one. Scores are normalized to the best phrase. A limit of 0 or less
+// returns all phrases.
+func topPhrases(scores map[string]float64, limit int) []KeyPhrase {
+	candidates := make([]KeyPhrase, 0, len(scores))
+	for phrase, score := range scores {
+		candidates = append(candidates, KeyPhrase{Phrase: phrase, Score: score})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Phrase < candidates[j].Phrase
+	})
+
+	var phrases []KeyPhrase
+	for _, candidate := range candidates {
+		if limit > 0 && len(phrases) >= limit {
+			break
+		}
+		contained := false
+		for _, p := range phrases {
+			if strings.Contains(" "+p.Phrase+" ", " "+candidate.Phrase+" ") {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			phrases = append(phrases, candidate)
+		}
+	}
+
+	if len(phrases) > 0 && phrases[0].Score > 0 {
+		best := phrases[0].Score
+		for i := range phrases {
+			phrases[i].Score /= best
+		}
+	}
+	return phrases
+}
+
+// isNumber reports whether word is made of digits only
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -4,14 +4,14 @@ import (
 	"math"
 )
 
-// calculateTFIDF computes TF-IDF scores for each sentence
-func calculateTFIDF(sentences []string) []float64 {
+// calculateTFIDF computes TF-IDF scores for each sentence in a language (empty if unknown)
+func calculateTFIDF(sentences []string, lang string) []float64 {
 	// Build document frequency map
 	docFreq := make(map[string]int)
 	allTerms := make([]map[string]int, len(sentences))
 
 	for i, sentence := range sentences {
-		terms := tokenize(sentence)
+		terms := tokenizeLanguage(sentence, lang)
 		termFreq := make(map[string]int)
 		seenTerms := make(map[string]bool)
 
@@ -63,8 +63,8 @@ func calculateTFIDF(sentences []string) []float64 {
 	return scores
 }
 
-// calculateTextRank computes TextRank scores using sentence similarity
-func calculateTextRank(sentences []string) []float64 {
+// calculateTextRank computes TextRank scores using sentence similarity in a language (empty if unknown)
+func calculateTextRank(sentences []string, lang string) []float64 {
 	n := len(sentences)
 	if n == 0 {
 		return []float64{}
@@ -78,7 +78,7 @@ func calculateTextRank(sentences []string) []float64 {
 
 	for i := 0; i < n; i++ {
 		for j := i + 1; j < n; j++ {
-			sim := sentenceSimilarity(sentences[i], sentences[j])
+			sim := sentenceSimilarity(sentences[i], sentences[j], lang)
 			similarity[i][j] = sim
 			similarity[j][i] = sim
 		}
@@ -135,9 +135,9 @@ func calculateTextRank(sentences []string) []float64 {
 }
 
 // sentenceSimilarity calculates similarity between two sentences using word overlap
-func sentenceSimilarity(s1, s2 string) float64 {
-	words1 := tokenize(s1)
-	words2 := tokenize(s2)
+func sentenceSimilarity(s1, s2, lang string) float64 {
+	words1 := tokenizeLanguage(s1, lang)
+	words2 := tokenizeLanguage(s2, lang)
 
 	if len(words1) == 0 || len(words2) == 0 {
 		return 0
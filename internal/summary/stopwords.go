@@ -0,0 +1,93 @@
+package summary
+
+// englishStopWords and chineseStopWords apply to text in any language, as articles often mix
+// English or Chinese terms into other languages
+var (
+	englishStopWords = newStopWords(
+		"the", "a", "an", "and", "or", "but", "in", "on", "at", "to", "for", "of",
+		"with", "by", "from", "as", "is", "was", "are", "were", "been", "be", "have", "has",
+		"had", "do", "does", "did", "will", "would", "could", "should", "may", "might", "must",
+		"shall", "can", "this", "that", "these", "those", "it", "its", "they", "them",
+		"their", "what", "which", "who", "whom", "whose", "where", "when", "why", "how",
+		"all", "each", "every", "both", "few", "more", "most", "other", "some", "such",
+		"than", "too", "very", "just", "only", "own", "same", "so", "not", "also",
+		"into", "about", "your", "you", "our", "his", "her", "my", "we", "he", "she",
+		"over", "out", "up", "down", "then", "now",
+	)
+
+	// Extended Chinese stopwords for better accuracy
+	chineseStopWords = newStopWords(
+		"的", "了", "和", "是", "在", "有", "这", "个", "我", "不", "人", "都",
+		"一", "他", "就", "们", "上", "也", "你", "说", "着", "对", "为", "与",
+		"而", "等", "被", "把", "让", "给", "向", "从", "到", "之", "于", "或",
+		"因", "但", "却", "即", "若", "虽", "所", "以", "如", "则", "其", "它",
+		"她", "这个", "那个", "什么", "怎么", "为什么", "哪个", "哪些", "这些", "那些",
+		"可以", "能够", "已经", "正在", "将要", "可能", "应该", "必须", "需要", "没有",
+		"因为", "所以", "但是", "而且", "或者", "如果", "虽然", "然", "此", "彼",
+		"自己", "我们", "你们", "他们", "它们", "这里", "那里", "哪里", "任何", "某些",
+		"每个", "很", "非常", "十分", "比较", "更", "最", "太", "又", "再", "还",
+	)
+)
+
+// languageStopWords are the stopwords of other languages by ISO 639-1 code, applied to text
+// known to be in that language
+var languageStopWords = map[string]map[string]bool{
+	"de": newStopWords(
+		"der", "die", "das", "den", "dem", "des", "ein", "eine", "einen", "einem", "einer", "eines",
+		"und", "oder", "aber", "nicht", "mit", "von", "auf", "für", "ist", "sind", "war", "waren",
+		"wird", "werden", "wurde", "hat", "haben", "hatte", "sich", "auch", "als", "aus", "bei",
+		"nach", "noch", "nur", "wie", "wenn", "dass", "sie", "ich", "wir", "ihr", "sein", "seine",
+		"ihre", "diese", "dieser", "dieses", "durch", "über", "unter", "vor", "zum", "zur", "mehr",
+		"kann", "können", "schon", "sehr", "man", "was", "wer", "dort", "hier", "jetzt",
+	),
+	"fr": newStopWords(
+		"les", "des", "une", "est", "sont", "était", "être", "avec", "pour", "par", "dans", "sur",
+		"que", "qui", "quoi", "dont", "mais", "pas", "plus", "aux", "ces", "cette", "son", "sa",
+		"ses", "leur", "leurs", "nous", "vous", "ils", "elle", "elles", "lui", "été", "avoir",
+		"ont", "fait", "comme", "tout", "tous", "aussi", "entre", "sans", "sous", "vers", "chez",
+		"très", "peut", "encore", "même", "après", "avant", "depuis", "ainsi", "alors",
+	),
+	"es": newStopWords(
+		"los", "las", "del", "una", "uno", "unos", "unas", "que", "por", "para", "con", "sin",
+		"sobre", "entre", "como", "más", "pero", "sus", "este", "esta", "estos", "estas", "ese",
+		"esa", "eso", "ser", "está", "están", "son", "fue", "han", "hay", "sido", "también",
+		"muy", "cuando", "donde", "quien", "porque", "desde", "hasta", "todo", "todos", "ya",
+		"les", "nos", "ella", "ellos", "puede", "según", "durante", "tras",
+	),
+	"pt": newStopWords(
+		"os", "as", "uma", "uns", "umas", "que", "por", "para", "com", "sem", "sobre", "entre",
+		"como", "mais", "mas", "seu", "sua", "seus", "suas", "este", "esta", "esse", "essa",
+		"isso", "ser", "está", "estão", "são", "foi", "tem", "têm", "também", "muito", "quando",
+		"onde", "quem", "porque", "desde", "até", "todo", "todos", "nos", "ela", "eles", "elas",
+		"pode", "pela", "pelo", "pelas", "pelos", "dos", "das", "num", "numa", "após",
+	),
+	"it": newStopWords(
+		"il", "gli", "una", "uno", "del", "della", "dei", "delle", "degli", "che", "per", "con",
+		"non", "sono", "era", "essere", "stato", "anche", "come", "più", "suo", "sua", "suoi",
+		"questo", "questa", "quello", "quella", "nel", "nella", "nei", "alla", "alle", "agli",
+		"dal", "dalla", "sul", "sulla", "tra", "fra", "hanno", "molto", "quando", "dove", "chi",
+		"perché", "tutto", "tutti", "lui", "lei", "loro", "può", "dopo", "prima",
+	),
+	"nl": newStopWords(
+		"het", "een", "van", "voor", "met", "aan", "ook", "niet", "maar", "zijn", "was", "waren",
+		"wordt", "worden", "werd", "heeft", "hebben", "had", "die", "dat", "deze", "dit", "als",
+		"bij", "naar", "nog", "wel", "dan", "wat", "wie", "waar", "hoe", "zich", "hun", "haar",
+		"hij", "zij", "wij", "jij", "uit", "over", "onder", "door", "tot", "kan", "meer", "zeer",
+	),
+	"ru": newStopWords(
+		"и", "в", "не", "на", "что", "как", "по", "это", "из", "за", "от", "для", "его", "она",
+		"они", "оно", "но", "был", "была", "были", "было", "быть", "уже", "или", "так", "все",
+		"всё", "её", "их", "при", "также", "чтобы", "который", "которая", "которые",
+		"этот", "эта", "эти", "того", "этого", "если", "когда", "где", "есть", "может", "только",
+		"еще", "ещё", "после", "более", "очень", "между", "под", "над", "через",
+	),
+}
+
+// newStopWords returns a set of stopwords
+func newStopWords(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
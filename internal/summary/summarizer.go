@@ -8,15 +8,30 @@ import (
 )
 
 // Summarizer provides text summarization capabilities
-type Summarizer struct{}
+type Summarizer struct {
+	language string // ISO 639-1 code of the text, empty if unknown
+}
 
-// NewSummarizer creates a new Summarizer instance
+// NewSummarizer creates a new Summarizer instance for text of unknown language
 func NewSummarizer() *Summarizer {
 	return &Summarizer{}
 }
 
+// NewSummarizerForLanguage creates a Summarizer for text in a language, given as an ISO 639-1
+// code, which picks its tokenizer and stopwords. An empty language is treated as unknown.
+func NewSummarizerForLanguage(language string) *Summarizer {
+	return &Summarizer{language: language}
+}
+
 // Summarize generates a summary of the given text using combined TF-IDF and TextRank scoring
 func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult {
+	return s.SummarizeStyle(text, length, Standard)
+}
+
+// SummarizeStyle generates a summary like Summarize in a style. A TL;DR is the best scoring
+// sentence, bullets and quotes list the selected sentences. Rewording is beyond an extractive
+// summary, so an ELI5 summary is the standard one.
+func (s *Summarizer) SummarizeStyle(text string, length SummaryLength, style SummaryStyle) SummaryResult {
 	// Clean the text
 	cleanedText := cleanText(text)
 
@@ -40,11 +55,11 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 		}
 	}
 
-	// Check if text is primarily Chinese
-	isChinese := isChineseText(cleanedText)
+	// Check if text is primarily Chinese or Japanese, whose length is counted in characters
+	isChinese := s.language == "zh" || s.language == "ja" || (s.language == "" && isChineseText(cleanedText))
 
 	// Get target word/character count based on length setting
-	targetCount := getTargetWordCount(length)
+	targetCount := TargetWordCount(length)
 
 	// Score sentences using combined TF-IDF and TextRank
 	scoredSentences := s.scoreSentences(sentences)
@@ -58,6 +73,10 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 	var selectedSentences []scoredSentence
 	currentCount := 0
 
+	if style == TLDR {
+		// The best scoring sentence is the gist of the text
+		scoredSentences = scoredSentences[:1]
+	}
 	for _, sent := range scoredSentences {
 		sentCount := countWordsOrChars(sent.text, isChinese)
 		if currentCount+sentCount <= targetCount || len(selectedSentences) == 0 {
@@ -82,19 +101,31 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 	}
 
 	return SummaryResult{
-		Summary:       strings.Join(summaryParts, " "),
+		Summary:       joinSentences(summaryParts, style),
 		SentenceCount: len(selectedSentences),
 		IsTooShort:    false,
 	}
 }
 
+// joinSentences formats the selected sentences of a summary in a style, as Markdown
+func joinSentences(sentences []string, style SummaryStyle) string {
+	switch style {
+	case Bullets:
+		return "- " + strings.Join(sentences, "\n- ")
+	case Quotes:
+		return "> " + strings.Join(sentences, "\n\n> ")
+	default:
+		return strings.Join(sentences, " ")
+	}
+}
+
 // scoreSentences calculates scores for each sentence using combined TF-IDF and TextRank
 func (s *Summarizer) scoreSentences(sentences []string) []scoredSentence {
 	// Calculate TF-IDF scores
-	tfidfScores := calculateTFIDF(sentences)
+	tfidfScores := calculateTFIDF(sentences, s.language)
 
 	// Calculate TextRank scores
-	textRankScores := calculateTextRank(sentences)
+	textRankScores := calculateTextRank(sentences, s.language)
 
 	// Calculate average sentence length for penalty calculation
 	totalLen := 0
@@ -165,6 +165,44 @@ func TestTokenize(t *testing.T) {
 	}
 }
 
+func TestTokenizeLanguage(t *testing.T) {
+	contains := func(tokens []string, want string) bool {
+		for _, token := range tokens {
+			if token == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	// German stopwords are only removed from text known to be German
+	german := "Die Regierung und die Wirtschaft"
+	if tokens := tokenizeLanguage(german, "de"); contains(tokens, "die") || contains(tokens, "und") || !contains(tokens, "regierung") {
+		t.Errorf("expected German stopwords to be removed, got %v", tokens)
+	}
+	if tokens := tokenizeLanguage(german, ""); !contains(tokens, "die") {
+		t.Errorf("expected German stopwords to be kept for unknown languages, got %v", tokens)
+	}
+
+	// Japanese is split into bigrams, dropping those made only of hiragana
+	tokens := tokenizeLanguage("東京の天気です", "ja")
+	if !contains(tokens, "東京") || !contains(tokens, "天気") || contains(tokens, "です") {
+		t.Errorf("unexpected Japanese tokens: %v", tokens)
+	}
+
+	// Chinese is segmented into words
+	if tokens := tokenizeLanguage("自然语言处理技术", "zh"); !contains(tokens, "技术") {
+		t.Errorf("unexpected Chinese tokens: %v", tokens)
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	keywords := Keywords("The battery, the BATTERY and the grid")
+	if len(keywords) != 2 || keywords[0] != "battery" || keywords[1] != "grid" {
+		t.Errorf("expected distinct keywords in order, got %v", keywords)
+	}
+}
+
 func TestIsStopWord(t *testing.T) {
 	stopWords := []string{"the", "a", "an", "and", "or", "in", "on", "at", "的", "了", "和"}
 	nonStopWords := []string{"computer", "algorithm", "processing", "模型", "技术"}
@@ -188,8 +226,8 @@ func TestSentenceSimilarity(t *testing.T) {
 	s2 := "Natural language processing is essential"
 	s3 := "Cooking recipes are delicious"
 
-	sim12 := sentenceSimilarity(s1, s2)
-	sim13 := sentenceSimilarity(s1, s3)
+	sim12 := sentenceSimilarity(s1, s2, "")
+	sim13 := sentenceSimilarity(s1, s3, "")
 
 	if sim12 <= sim13 {
 		t.Errorf("Similar sentences should have higher similarity: sim(%q, %q)=%f <= sim(%q, %q)=%f",
@@ -204,7 +242,7 @@ func TestCalculateTFIDF(t *testing.T) {
 		"Natural language processing uses machine learning.",
 	}
 
-	scores := calculateTFIDF(sentences)
+	scores := calculateTFIDF(sentences, "")
 
 	if len(scores) != len(sentences) {
 		t.Errorf("Expected %d scores, got %d", len(sentences), len(scores))
@@ -225,7 +263,7 @@ func TestCalculateTextRank(t *testing.T) {
 		"Natural language processing uses machine learning.",
 	}
 
-	scores := calculateTextRank(sentences)
+	scores := calculateTextRank(sentences, "")
 
 	if len(scores) != len(sentences) {
 		t.Errorf("Expected %d scores, got %d", len(sentences), len(scores))
@@ -256,3 +294,58 @@ func TestSummarize_SingleSentence(t *testing.T) {
 		t.Error("Expected IsTooShort to be true for single sentence")
 	}
 }
+
+func TestSummarizeStyle(t *testing.T) {
+	text := `Natural language processing is a field of artificial intelligence. It focuses on the interaction between computers and humans using natural language. The ultimate goal is to enable computers to understand, interpret, and generate human language. NLP combines computational linguistics with machine learning and deep learning. Applications include machine translation, sentiment analysis, and text summarization. Modern NLP uses transformer models that have revolutionized the field. These models can process text more effectively than previous approaches. The field continues to advance rapidly with new techniques being developed.`
+	s := NewSummarizer()
+
+	if result := s.SummarizeStyle(text, Long, TLDR); result.SentenceCount != 1 {
+		t.Errorf("expected a TL;DR of one sentence, got %d: %q", result.SentenceCount, result.Summary)
+	}
+
+	bullets := s.SummarizeStyle(text, Medium, Bullets)
+	if lines := strings.Split(bullets.Summary, "\n"); len(lines) != bullets.SentenceCount || !strings.HasPrefix(lines[0], "- ") {
+		t.Errorf("expected a bullet per sentence, got %q", bullets.Summary)
+	}
+
+	quotes := s.SummarizeStyle(text, Medium, Quotes)
+	if strings.Count(quotes.Summary, "> ") != quotes.SentenceCount {
+		t.Errorf("expected a quote per sentence, got %q", quotes.Summary)
+	}
+
+	if got, want := s.SummarizeStyle(text, Medium, ELI5).Summary, s.Summarize(text, Medium).Summary; got != want {
+		t.Errorf("expected the standard summary for ELI5, got %q", got)
+	}
+}
+
+func TestKeyPhrases(t *testing.T) {
+	text := `Solid state batteries could double the range of electric cars. Toyota says its solid state batteries will reach production in 2027. Electric cars from other makers still use lithium-ion cells, but solid state batteries promise faster charging.`
+	phrases := NewSummarizerForLanguage("en").KeyPhrases(text, 5)
+
+	if len(phrases) != 5 {
+		t.Fatalf("expected 5 key phrases, got %v", phrases)
+	}
+	if phrases[0].Phrase != "solid state batteries" || phrases[0].Score != 1 {
+		t.Errorf("expected \"solid state batteries\" first with a score of 1, got %v", phrases[0])
+	}
+	for i, p := range phrases {
+		if p.Phrase == "batteries" || p.Phrase == "2027" {
+			t.Errorf("expected no phrase contained in a better one or number, got %q", p.Phrase)
+		}
+		if i > 0 && p.Score > phrases[i-1].Score {
+			t.Errorf("expected phrases best first, got %v", phrases)
+		}
+	}
+
+	if phrases := NewSummarizer().KeyPhrases("", 5); len(phrases) != 0 {
+		t.Errorf("expected no key phrases for empty text, got %v", phrases)
+	}
+}
+
+func TestKeyPhrases_Chinese(t *testing.T) {
+	text := "人工智能正在改变医疗行业。医院使用人工智能分析医学影像，人工智能还能帮助医生诊断疾病。"
+	phrases := NewSummarizerForLanguage("zh").KeyPhrases(text, 5)
+	if len(phrases) == 0 || phrases[0].Phrase != "人工智能" {
+		t.Errorf("expected \"人工智能\" first, got %v", phrases)
+	}
+}
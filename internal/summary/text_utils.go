@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-ego/gse"
 )
@@ -24,6 +25,11 @@ func getSegmenter() *gse.Segmenter {
 	return &segmenter
 }
 
+// PlainText returns the text of HTML content as it is summarized
+func PlainText(html string) string {
+	return cleanText(html)
+}
+
 // cleanText removes HTML tags and normalizes whitespace
 func cleanText(text string) string {
 	// Remove HTML tags
@@ -82,114 +88,160 @@ func splitSentences(text string) []string {
 // tokenize splits text into lowercase tokens, removing stopwords
 // Uses gse for Chinese word segmentation for better accuracy
 func tokenize(text string) []string {
+	return tokenizeLanguage(text, "")
+}
+
+// tokenizeLanguage splits text in a language (ISO 639-1, empty if unknown) into lowercase tokens,
+// removing the language's stopwords. Chinese, and text of unknown language containing Chinese
+// characters, is segmented into words with gse. Japanese and Thai, which don't separate words
+// with spaces, are split into character bigrams. Other languages are split on non-letters.
+func tokenizeLanguage(text, lang string) []string {
 	// Convert to lowercase for English
 	text = strings.ToLower(text)
 
-	// Check if text contains Chinese characters
-	hasChinese := false
+	switch {
+	case lang == "zh" || (lang == "" && containsHan(text)):
+		return tokenizeChinese(text, lang)
+	case lang == "ja" || lang == "th":
+		return tokenizeBigrams(text, lang)
+	default:
+		return tokenizeWords(text, lang)
+	}
+}
+
+// containsHan reports whether text contains Chinese characters
+func containsHan(text string) bool {
 	for _, r := range text {
 		if unicode.Is(unicode.Han, r) {
-			hasChinese = true
-			break
+			return true
 		}
 	}
+	return false
+}
 
+// tokenizeChinese segments Chinese text into words with gse
+func tokenizeChinese(text, lang string) []string {
 	var tokens []string
-
-	if hasChinese {
-		// Use gse for Chinese text segmentation
-		seg := getSegmenter()
-		segments := seg.Cut(text, true) // true = search mode for better recall
-
-		for _, word := range segments {
-			word = strings.TrimSpace(word)
-			// Skip empty strings, stopwords, and very short words
-			if len(word) > 0 && !isStopWord(word) {
-				// For Chinese, single characters can be meaningful
-				// For English, require at least 2 characters
-				isChinese := false
-				for _, r := range word {
-					if unicode.Is(unicode.Han, r) {
-						isChinese = true
-						break
-					}
-				}
-				if isChinese || len(word) > 2 {
-					tokens = append(tokens, word)
-				}
-			}
-		}
-	} else {
-		// Use simple tokenization for non-Chinese text
-		var currentWord strings.Builder
-
-		for _, r := range text {
-			if unicode.IsLetter(r) || unicode.IsDigit(r) {
-				currentWord.WriteRune(r)
-			} else {
-				if currentWord.Len() > 0 {
-					word := currentWord.String()
-					// Skip stopwords and very short words
-					if len(word) > 2 && !isStopWord(word) {
-						tokens = append(tokens, word)
-					}
-					currentWord.Reset()
-				}
+	seg := getSegmenter()
+	segments := seg.Cut(text, true) // true = search mode for better recall
+
+	for _, word := range segments {
+		word = strings.TrimSpace(word)
+		// Skip empty strings, stopwords, and very short words
+		if len(word) > 0 && !isStopWordIn(word, lang) {
+			// For Chinese, single characters can be meaningful
+			// For English, require at least 2 characters
+			if containsHan(word) || len(word) > 2 {
+				tokens = append(tokens, word)
 			}
 		}
+	}
+	return tokens
+}
 
-		// Don't forget the last word
+// tokenizeWords splits text on characters other than letters and digits
+func tokenizeWords(text, lang string) []string {
+	var tokens []string
+	var currentWord strings.Builder
+
+	addWord := func() {
 		if currentWord.Len() > 0 {
 			word := currentWord.String()
-			if len(word) > 2 && !isStopWord(word) {
+			// Skip stopwords and very short words
+			if len(word) > 2 && !isStopWordIn(word, lang) {
 				tokens = append(tokens, word)
 			}
+			currentWord.Reset()
 		}
 	}
 
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			currentWord.WriteRune(r)
+		} else {
+			addWord()
+		}
+	}
+	// Don't forget the last word
+	addWord()
+
 	return tokens
 }
 
+// tokenizeBigrams splits runs of Japanese or Thai characters into overlapping character bigrams,
+// a common stand-in for word segmentation, and other runs of letters and digits into words.
+// Bigrams made only of hiragana are mostly particles and inflections and are dropped.
+func tokenizeBigrams(text, lang string) []string {
+	var tokens []string
+	var run []rune
+
+	addRun := func() {
+		if len(run) == 0 {
+			return
+		}
+		if !isBigramRune(run[0]) {
+			tokens = append(tokens, tokenizeWords(string(run), lang)...)
+		} else if len(run) == 1 {
+			if !isHiragana(run[0]) {
+				tokens = append(tokens, string(run))
+			}
+		} else {
+			for i := 0; i+1 < len(run); i++ {
+				if isHiragana(run[i]) && isHiragana(run[i+1]) {
+					continue
+				}
+				tokens = append(tokens, string(run[i:i+2]))
+			}
+		}
+		run = run[:0]
+	}
+
+	for _, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+		// A run ends on separators and where it switches between bigram and word characters
+		if !isWordRune || (len(run) > 0 && isBigramRune(r) != isBigramRune(run[0]) && !unicode.IsMark(r)) {
+			addRun()
+		}
+		if isWordRune {
+			run = append(run, r)
+		}
+	}
+	addRun()
+
+	return tokens
+}
+
+// isBigramRune reports whether r is written without spaces between words and is split into bigrams
+func isBigramRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai)
+}
+
+// isHiragana reports whether r is a hiragana character
+func isHiragana(r rune) bool {
+	return unicode.Is(unicode.Hiragana, r)
+}
+
+// Keywords returns the distinct keywords of text in order of appearance: lowercase tokens
+// of at least two characters without stopwords, with Chinese text segmented into words
+func Keywords(text string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, token := range tokenize(text) {
+		if utf8.RuneCountInString(token) < 2 || seen[token] {
+			continue
+		}
+		seen[token] = true
+		keywords = append(keywords, token)
+	}
+	return keywords
+}
+
 // isStopWord checks if a word is a common stopword (English and Chinese)
 func isStopWord(word string) bool {
-	stopWords := map[string]bool{
-		// English stopwords
-		"the": true, "a": true, "an": true, "and": true, "or": true, "but": true,
-		"in": true, "on": true, "at": true, "to": true, "for": true, "of": true,
-		"with": true, "by": true, "from": true, "as": true, "is": true, "was": true,
-		"are": true, "were": true, "been": true, "be": true, "have": true, "has": true,
-		"had": true, "do": true, "does": true, "did": true, "will": true, "would": true,
-		"could": true, "should": true, "may": true, "might": true, "must": true,
-		"shall": true, "can": true, "this": true, "that": true, "these": true,
-		"those": true, "it": true, "its": true, "they": true, "them": true,
-		"their": true, "what": true, "which": true, "who": true, "whom": true,
-		"whose": true, "where": true, "when": true, "why": true, "how": true,
-		"all": true, "each": true, "every": true, "both": true, "few": true,
-		"more": true, "most": true, "other": true, "some": true, "such": true,
-		"than": true, "too": true, "very": true, "just": true, "only": true,
-		"own": true, "same": true, "so": true, "not": true, "also": true,
-		"into": true, "about": true, "your": true, "you": true, "our": true,
-		"his": true, "her": true, "my": true, "we": true, "he": true, "she": true,
-		"over": true, "out": true, "up": true, "down": true, "then": true, "now": true,
-		// Extended Chinese stopwords for better accuracy
-		"的": true, "了": true, "和": true, "是": true, "在": true, "有": true,
-		"这": true, "个": true, "我": true, "不": true, "人": true, "都": true,
-		"一": true, "他": true, "就": true, "们": true, "上": true, "也": true,
-		"你": true, "说": true, "着": true, "对": true, "为": true, "与": true,
-		"而": true, "等": true, "被": true, "把": true, "让": true, "给": true,
-		"向": true, "从": true, "到": true, "之": true, "于": true, "或": true,
-		"因": true, "但": true, "却": true, "即": true, "若": true, "虽": true,
-		"所": true, "以": true, "如": true, "则": true, "其": true, "它": true,
-		"她": true, "这个": true, "那个": true, "什么": true, "怎么": true,
-		"为什么": true, "哪个": true, "哪些": true, "这些": true, "那些": true,
-		"可以": true, "能够": true, "已经": true, "正在": true, "将要": true,
-		"可能": true, "应该": true, "必须": true, "需要": true, "没有": true,
-		"因为": true, "所以": true, "但是": true, "而且": true, "或者": true,
-		"如果": true, "虽然": true, "然": true, "此": true, "彼": true,
-		"自己": true, "我们": true, "你们": true, "他们": true, "它们": true,
-		"这里": true, "那里": true, "哪里": true, "任何": true, "某些": true,
-		"每个": true, "很": true, "非常": true, "十分": true, "比较": true,
-		"更": true, "最": true, "太": true, "又": true, "再": true, "还": true,
-	}
-	return stopWords[word]
+	return englishStopWords[word] || chineseStopWords[word]
+}
+
+// isStopWordIn checks if a word is a common stopword in English, Chinese or the given language
+func isStopWordIn(word, lang string) bool {
+	return isStopWord(word) || languageStopWords[lang][word]
 }
@@ -14,6 +14,25 @@ const (
 	Long SummaryLength = "long"
 )
 
+// SummaryStyle represents the form of a summary
+type SummaryStyle string
+
+const (
+	// Standard is a prose summary
+	Standard SummaryStyle = "standard"
+	// TLDR is a one or two sentence gist
+	TLDR SummaryStyle = "tldr"
+	// Bullets lists the key points
+	Bullets SummaryStyle = "bullets"
+	// Quotes collects the most important sentences verbatim
+	Quotes SummaryStyle = "quotes"
+	// ELI5 explains the article in plain words
+	ELI5 SummaryStyle = "eli5"
+)
+
+// Styles lists the summary styles, standard first
+var Styles = []SummaryStyle{Standard, TLDR, Bullets, Quotes, ELI5}
+
 // MinContentLength is the minimum text length required for meaningful summarization
 const MinContentLength = 200
 
@@ -1,6 +1,8 @@
 package summary
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"strings"
 	"unicode"
 )
@@ -23,8 +25,8 @@ func isChineseText(text string) bool {
 	return float64(chineseCount)/float64(totalCount) > 0.3
 }
 
-// getTargetWordCount returns the target word count based on length setting
-func getTargetWordCount(length SummaryLength) int {
+// TargetWordCount returns the target word count based on length setting
+func TargetWordCount(length SummaryLength) int {
 	switch length {
 	case Short:
 		return ShortTargetWords
@@ -35,13 +37,20 @@ func getTargetWordCount(length SummaryLength) int {
 	}
 }
 
-// countWordsOrChars counts words for English or characters for Chinese
+// ContentHash identifies the content a summary is generated from, so that stored summaries of
+// content that changed since, such as after fetching the full text, are detected as stale
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// countWordsOrChars counts words for English or characters for Chinese and Japanese
 func countWordsOrChars(text string, isChinese bool) int {
 	if isChinese {
-		// Count Chinese characters
+		// Count Chinese characters and Japanese kana
 		count := 0
 		for _, r := range text {
-			if unicode.Is(unicode.Han, r) {
+			if isCJKRune(r) {
 				count++
 			}
 		}
@@ -49,7 +58,7 @@ func countWordsOrChars(text string, isChinese bool) int {
 		englishWords := 0
 		inWord := false
 		for _, r := range text {
-			if unicode.IsLetter(r) && !unicode.Is(unicode.Han, r) {
+			if unicode.IsLetter(r) && !isCJKRune(r) {
 				if !inWord {
 					englishWords++
 					inWord = true
@@ -64,3 +73,8 @@ func countWordsOrChars(text string, isChinese bool) int {
 	words := strings.Fields(text)
 	return len(words)
 }
+
+// isCJKRune reports whether r is a Chinese character or Japanese kana
+func isCJKRune(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
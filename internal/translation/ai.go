@@ -1,7 +1,9 @@
 package translation
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -117,21 +119,40 @@ func (t *AITranslator) SetCustomHeaders(headers string) {
 // Translate translates text to the target language using an OpenAI-compatible API.
 // Automatically detects and adapts to different API formats (Gemini, OpenAI, Ollama).
 func (t *AITranslator) Translate(text, targetLang string) (string, error) {
+	return t.TranslateWithContext(context.Background(), text, targetLang)
+}
+
+// TranslateWithContext translates text like Translate. The AI request is aborted when ctx is done.
+func (t *AITranslator) TranslateWithContext(ctx context.Context, text, targetLang string) (string, error) {
+	return t.translate(ctx, text, targetLang, nil)
+}
+
+// TranslateWithGlossary translates text like TranslateWithContext, instructing the model to
+// follow the glossary entries that occur in text
+func (t *AITranslator) TranslateWithGlossary(ctx context.Context, text, targetLang string, glossary Glossary) (string, error) {
+	return t.translate(ctx, text, targetLang, glossary.Matching(text))
+}
+
+// translate translates text with the glossary's instructions added to the system prompt
+func (t *AITranslator) translate(ctx context.Context, text, targetLang string, glossary Glossary) (string, error) {
 	if text == "" {
 		return "", nil
 	}
 
-	langName := getLanguageName(targetLang)
+	langName := LanguageName(targetLang)
 
 	// Use custom system prompt if provided, otherwise use default
 	systemPrompt := t.SystemPrompt
 	if systemPrompt == "" {
 		systemPrompt = "You are a translator. Translate the given text accurately. Output ONLY the translated text, nothing else."
 	}
+	if len(glossary) > 0 {
+		systemPrompt += "\n\n" + glossary.instructions()
+	}
 	userPrompt := fmt.Sprintf("Translate to %s:\n%s", langName, text)
 
 	// Use the universal client which handles format detection automatically
-	result, err := t.client.RequestWithThinking(systemPrompt, userPrompt)
+	result, err := t.client.RequestWithThinkingContext(ctx, systemPrompt, userPrompt)
 	if err != nil {
 		return "", err
 	}
@@ -142,8 +163,131 @@ func (t *AITranslator) Translate(text, targetLang string) (string, error) {
 	return translated, nil
 }
 
-// getLanguageName converts a language code to a human-readable name.
-func getLanguageName(code string) string {
+// Limits for the texts sent to the model in one batch request
+const (
+	aiBatchMaxTexts = 25
+	aiBatchMaxChars = 6000
+)
+
+// batchSystemPrompt asks for the translations of numbered texts as JSON
+const batchSystemPrompt = `You are a translator. Translate each numbered text accurately and independently.
+Keep placeholders and markers such as [[1]], [[/1]] and [[2/]] exactly as they are.
+Reply with JSON: {"translations": [{"id": <number of the text>, "text": "<translation>"}]}, one entry per text.`
+
+// batchResponseSchema is the JSON schema of a batch translation response
+var batchResponseSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"translations": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id":   map[string]interface{}{"type": "integer"},
+					"text": map[string]interface{}{"type": "string"},
+				},
+				"required":             []string{"id", "text"},
+				"additionalProperties": false,
+			},
+		},
+	},
+	"required":             []string{"translations"},
+	"additionalProperties": false,
+}
+
+// TranslateBatch translates texts with one request per batch of numbered texts, answered as JSON.
+// Texts the model leaves out are translated on their own.
+func (t *AITranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	return t.translateBatch(ctx, texts, targetLang, nil)
+}
+
+// TranslateBatchWithGlossary translates texts like TranslateBatch, instructing the model to
+// follow the glossary entries that occur in each batch
+func (t *AITranslator) TranslateBatchWithGlossary(ctx context.Context, texts []string, targetLang string, glossary Glossary) ([]string, error) {
+	return t.translateBatch(ctx, texts, targetLang, glossary)
+}
+
+// translateBatch translates texts in batches, applying the glossary entries that occur in them
+func (t *AITranslator) translateBatch(ctx context.Context, texts []string, targetLang string, glossary Glossary) ([]string, error) {
+	results := make([]string, len(texts))
+	indexes := nonEmpty(texts)
+	pending := make([]string, len(indexes))
+	for j, i := range indexes {
+		pending[j] = texts[i]
+	}
+
+	for _, r := range batchRanges(pending, aiBatchMaxTexts, aiBatchMaxChars) {
+		batch := pending[r[0]:r[1]]
+		translations, err := t.translateNumbered(ctx, batch, targetLang, glossary.Matching(batch...))
+		if err != nil {
+			return nil, err
+		}
+		for j, translated := range translations {
+			i := indexes[r[0]+j]
+			if translated == "" {
+				if translated, err = t.translate(ctx, texts[i], targetLang, glossary.Matching(texts[i])); err != nil {
+					return nil, err
+				}
+			}
+			results[i] = translated
+		}
+	}
+	return results, nil
+}
+
+// translateNumbered translates texts in one request, returning an empty string for the texts
+// missing from the response. A response that isn't valid JSON leaves them all missing.
+func (t *AITranslator) translateNumbered(ctx context.Context, texts []string, targetLang string, glossary Glossary) ([]string, error) {
+	translations := make([]string, len(texts))
+	if len(texts) == 1 {
+		return translations, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Translate to %s:\n", LanguageName(targetLang))
+	for i, text := range texts {
+		fmt.Fprintf(&b, "\n[%d] %s", i+1, text)
+	}
+
+	systemPrompt := batchSystemPrompt
+	if t.SystemPrompt != "" {
+		systemPrompt = t.SystemPrompt + "\n\n" + batchSystemPrompt
+	}
+	if len(glossary) > 0 {
+		systemPrompt += "\n\n" + glossary.instructions()
+	}
+	result, err := t.client.RequestWithConfigContext(ctx, ai.RequestConfig{
+		Model:          t.Model,
+		SystemPrompt:   systemPrompt,
+		UserPrompt:     b.String(),
+		Temperature:    0.3,
+		MaxTokens:      4096,
+		ResponseFormat: ai.JSONSchemaFormat("translations", batchResponseSchema),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Translations []struct {
+			ID   int    `json:"id"`
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := ai.DecodeJSON(result.Content, &response); err != nil {
+		log.Printf("AI batch translation returned invalid JSON, translating texts one by one: %v", err)
+		return translations, nil
+	}
+	for _, translation := range response.Translations {
+		if translation.ID >= 1 && translation.ID <= len(texts) {
+			translations[translation.ID-1] = strings.TrimSpace(translation.Text)
+		}
+	}
+	return translations, nil
+}
+
+// LanguageName converts a language code to a human-readable name.
+func LanguageName(code string) string {
 	langNames := map[string]string{
 		"en": "English",
 		"zh": "Chinese",
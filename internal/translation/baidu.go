@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"context"
 	"crypto/md5"
 	"crypto/rand"
 	"encoding/hex"
@@ -9,9 +10,13 @@ import (
 	"math/big"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
+// baiduMaxQueryBytes bounds the size of a batched Baidu query, below the API's limit of about 6000 bytes
+const baiduMaxQueryBytes = 5000
+
 // BaiduTranslator implements translation using the Baidu Translate API.
 type BaiduTranslator struct {
 	AppID     string
@@ -52,41 +57,92 @@ func (t *BaiduTranslator) Translate(text, targetLang string) (string, error) {
 		return "", nil
 	}
 
+	translations, err := t.translateQuery(context.Background(), text, targetLang)
+	if err != nil {
+		return "", err
+	}
+	// Baidu returns one translation per line of the text
+	return strings.Join(translations, "\n"), nil
+}
+
+// TranslateBatch translates texts joined by newlines, in one request per baiduMaxQueryBytes,
+// as Baidu translates each line of a query separately. Texts spanning several lines are
+// translated on their own, their translated lines joined again.
+func (t *BaiduTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	results := make([]string, len(texts))
+	var lines []string
+	var lineIndexes []int
+	for _, i := range nonEmpty(texts) {
+		if strings.Contains(texts[i], "\n") {
+			translations, err := t.translateQuery(ctx, texts[i], targetLang)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = strings.Join(translations, "\n")
+			continue
+		}
+		lines = append(lines, texts[i])
+		lineIndexes = append(lineIndexes, i)
+	}
+
+	for _, r := range batchRanges(lines, len(lines), baiduMaxQueryBytes) {
+		translations, err := t.translateQuery(ctx, strings.Join(lines[r[0]:r[1]], "\n"), targetLang)
+		if err != nil {
+			return nil, err
+		}
+		if len(translations) != r[1]-r[0] {
+			return nil, fmt.Errorf("baidu api returned %d translations for %d texts", len(translations), r[1]-r[0])
+		}
+		for j, translation := range translations {
+			results[lineIndexes[r[0]+j]] = translation
+		}
+	}
+	return results, nil
+}
+
+// translateQuery translates a query with the Baidu Translate API, returning one translation per line
+func (t *BaiduTranslator) translateQuery(ctx context.Context, query, targetLang string) ([]string, error) {
 	// Baidu API uses different language codes
 	baiduLang := mapToBaiduLang(targetLang)
 
 	// Generate cryptographically secure random salt
 	n, err := rand.Int(rand.Reader, big.NewInt(1000000000))
 	if err != nil {
-		return "", fmt.Errorf("failed to generate salt: %w", err)
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
 	}
 	salt := n.String()
 
 	// Generate sign: md5(appid+q+salt+key)
 	// Note: MD5 is used here because it's required by the Baidu Translate API specification.
 	// This is not for security purposes but for API signature verification.
-	signStr := t.AppID + text + salt + t.SecretKey
+	signStr := t.AppID + query + salt + t.SecretKey
 	hash := md5.Sum([]byte(signStr))
 	sign := hex.EncodeToString(hash[:])
 
 	// Build request URL
 	apiURL := "https://fanyi-api.baidu.com/api/trans/vip/translate"
 	data := url.Values{}
-	data.Set("q", text)
+	data.Set("q", query)
 	data.Set("from", "auto")
 	data.Set("to", baiduLang)
 	data.Set("appid", t.AppID)
 	data.Set("salt", salt)
 	data.Set("sign", sign)
 
-	resp, err := t.client.PostForm(apiURL, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("baidu api request failed: %w", err)
+		return nil, fmt.Errorf("failed to create baidu request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("baidu api request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("baidu api returned status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("baidu api returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -99,18 +155,22 @@ func (t *BaiduTranslator) Translate(text, targetLang string) (string, error) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode baidu response: %w", err)
+		return nil, fmt.Errorf("failed to decode baidu response: %w", err)
 	}
 
 	if result.ErrorCode != "" && result.ErrorCode != "52000" {
-		return "", fmt.Errorf("baidu api error: %s - %s", result.ErrorCode, result.ErrorMsg)
+		return nil, fmt.Errorf("baidu api error: %s - %s", result.ErrorCode, result.ErrorMsg)
 	}
 
-	if len(result.TransResult) > 0 {
-		return result.TransResult[0].Dst, nil
+	if len(result.TransResult) == 0 {
+		return nil, fmt.Errorf("no translation found in baidu response")
 	}
 
-	return "", fmt.Errorf("no translation found in baidu response")
+	translations := make([]string, len(result.TransResult))
+	for i, r := range result.TransResult {
+		translations[i] = r.Dst
+	}
+	return translations, nil
 }
 
 // mapToBaiduLang maps standard language codes to Baidu's language codes.
@@ -0,0 +1,98 @@
+package translation
+
+import (
+	"context"
+	"sync"
+)
+
+// batchConcurrency bounds the concurrent requests when a translator has no batch support
+const batchConcurrency = 4
+
+// BatchTranslator is implemented by translators that can translate several texts in one request
+type BatchTranslator interface {
+	Translator
+	// TranslateBatch translates texts to targetLang, returning the translations in the same order.
+	// Empty texts translate to empty strings.
+	TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error)
+}
+
+// TranslateBatch translates texts with translator, natively if it is a BatchTranslator and
+// otherwise with one request per text, a few at a time. The translations are returned in the
+// same order as texts.
+func TranslateBatch(ctx context.Context, translator Translator, texts []string, targetLang string) ([]string, error) {
+	if bound, ok := translator.(*boundTranslator); ok {
+		translator = bound.translator
+	}
+	if bt, ok := translator.(BatchTranslator); ok {
+		return bt.TranslateBatch(ctx, texts, targetLang)
+	}
+	return translateConcurrently(ctx, translator, texts, targetLang)
+}
+
+// translateConcurrently translates texts one request per text, at most batchConcurrency at a time.
+// The first error cancels the remaining requests.
+func translateConcurrently(ctx context.Context, translator Translator, texts []string, targetLang string) ([]string, error) {
+	results := make([]string, len(texts))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	semaphore := make(chan struct{}, batchConcurrency)
+
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, text string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			translated, err := translateWithContext(ctx, translator, text, targetLang)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = translated
+		}(i, text)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// batchRanges splits n texts into consecutive ranges of at most maxTexts texts and, past the
+// first text of a range, at most maxChars characters
+func batchRanges(texts []string, maxTexts, maxChars int) [][2]int {
+	var ranges [][2]int
+	for start := 0; start < len(texts); {
+		end, chars := start, 0
+		for end < len(texts) && end-start < maxTexts && (end == start || chars+len(texts[end]) <= maxChars) {
+			chars += len(texts[end])
+			end++
+		}
+		ranges = append(ranges, [2]int{start, end})
+		start = end
+	}
+	return ranges
+}
+
+// nonEmpty returns the indexes of the texts that are not empty
+func nonEmpty(texts []string) []int {
+	indexes := make([]int, 0, len(texts))
+	for i, text := range texts {
+		if text != "" {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
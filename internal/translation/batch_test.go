@@ -0,0 +1,166 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"MrRSS/internal/ai"
+)
+
+// countingTranslator prefixes texts with the language and counts its calls
+type countingTranslator struct {
+	calls atomic.Int32
+	fail  string // Text to fail on
+}
+
+func (c *countingTranslator) Translate(text, targetLang string) (string, error) {
+	c.calls.Add(1)
+	if text == c.fail {
+		return "", errors.New("translation failed")
+	}
+	return targetLang + ":" + text, nil
+}
+
+// jsonResponse returns an HTTP response with a JSON body
+func jsonResponse(body string) *http.Response {
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{"Content-Type": {"application/json"}}}
+}
+
+func TestTranslateBatch_Fallback(t *testing.T) {
+	translator := &countingTranslator{}
+	texts := []string{"one", "", "two", "three", "four", "five"}
+
+	results, err := TranslateBatch(context.Background(), translator, texts, "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch error: %v", err)
+	}
+	if strings.Join(results, "|") != "fr:one||fr:two|fr:three|fr:four|fr:five" {
+		t.Errorf("unexpected results: %q", results)
+	}
+	if translator.calls.Load() != 5 {
+		t.Errorf("expected one call per non-empty text, got %d", translator.calls.Load())
+	}
+
+	translator.fail = "three"
+	if _, err := TranslateBatch(context.Background(), translator, texts, "fr"); err == nil {
+		t.Errorf("expected the failed text to fail the batch")
+	}
+}
+
+func TestCachedTranslator_TranslateBatch(t *testing.T) {
+	translator := &countingTranslator{}
+	cache := memoryCache{}
+	cached := NewCachedTranslator(translator, cache, "test")
+
+	if _, err := cached.Translate("one", "fr"); err != nil {
+		t.Fatalf("Translate error: %v", err)
+	}
+	results, err := cached.TranslateBatch(context.Background(), []string{"one", "two", "two", "three"}, "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch error: %v", err)
+	}
+	if strings.Join(results, "|") != "fr:one|fr:two|fr:two|fr:three" {
+		t.Errorf("unexpected results: %q", results)
+	}
+	if translator.calls.Load() != 3 {
+		t.Errorf("expected only the uncached, distinct texts to be translated, got %d calls", translator.calls.Load())
+	}
+	if len(cache) != 3 {
+		t.Errorf("expected the new translations to be cached, got %d entries", len(cache))
+	}
+}
+
+func TestDeepLTranslateBatch(t *testing.T) {
+	translator := NewDeepLTranslator("apikey")
+	requests := 0
+	translator.client = &http.Client{Transport: rtFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		body, _ := io.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		var translations []string
+		for _, text := range form["text"] {
+			translations = append(translations, fmt.Sprintf(`{"text":"%s!"}`, text))
+		}
+		return jsonResponse(`{"translations":[` + strings.Join(translations, ",") + `]}`), nil
+	}), Timeout: 5 * time.Second}
+
+	texts := make([]string, deeplMaxTexts+2)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+	texts[1] = ""
+
+	results, err := translator.TranslateBatch(context.Background(), texts, "de")
+	if err != nil {
+		t.Fatalf("TranslateBatch error: %v", err)
+	}
+	if requests != 2 || results[0] != "text 0!" || results[1] != "" || results[len(texts)-1] != fmt.Sprintf("text %d!", len(texts)-1) {
+		t.Errorf("unexpected results after %d requests: %q", requests, results)
+	}
+}
+
+func TestBaiduTranslateBatch(t *testing.T) {
+	translator := NewBaiduTranslator("appid", "secret")
+	requests := 0
+	translator.client = &http.Client{Transport: rtFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		body, _ := io.ReadAll(req.Body)
+		form, _ := url.ParseQuery(string(body))
+		var results []map[string]string
+		for _, line := range strings.Split(form.Get("q"), "\n") {
+			results = append(results, map[string]string{"src": line, "dst": "zh:" + line})
+		}
+		encoded, _ := json.Marshal(map[string]interface{}{"trans_result": results})
+		return jsonResponse(string(encoded)), nil
+	}), Timeout: 5 * time.Second}
+
+	results, err := translator.TranslateBatch(context.Background(), []string{"one", "two\nlines", "", "three"}, "zh")
+	if err != nil {
+		t.Fatalf("TranslateBatch error: %v", err)
+	}
+	if strings.Join(results, "|") != "zh:one|zh:two\nzh:lines||zh:three" || requests != 2 {
+		t.Errorf("unexpected results after %d requests: %q", requests, results)
+	}
+
+	if result, err := translator.Translate("four\nfive", "zh"); err != nil || result != "zh:four\nzh:five" {
+		t.Errorf("expected every line of a text to be translated, got %q (%v)", result, err)
+	}
+}
+
+func TestAITranslateBatch(t *testing.T) {
+	translator := NewAITranslator("apikey", "https://api.test", "m1")
+	requests := 0
+	translator.client = ai.NewClientWithHTTPClient(ai.ClientConfig{
+		APIKey:   "apikey",
+		Endpoint: "https://api.test",
+		Model:    "m1",
+		Timeout:  5 * time.Second,
+	}, &http.Client{Transport: rtFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		body, _ := io.ReadAll(req.Body)
+		content := `Bonjour`
+		if strings.Contains(string(body), "[2] World") {
+			// The second text is left out and translated on its own
+			content = "```json\n{\"translations\":[{\"id\":1,\"text\":\"Salut\"},{\"id\":3,\"text\":\"Au revoir\"}]}\n```"
+		}
+		encoded, _ := json.Marshal(map[string]interface{}{"choices": []interface{}{map[string]interface{}{"message": map[string]string{"content": content}}}})
+		return jsonResponse(string(encoded)), nil
+	}), Timeout: 5 * time.Second})
+
+	results, err := translator.TranslateBatch(context.Background(), []string{"Hello", "World", "Goodbye"}, "fr")
+	if err != nil {
+		t.Fatalf("TranslateBatch error: %v", err)
+	}
+	if strings.Join(results, "|") != "Salut|Bonjour|Au revoir" || requests != 2 {
+		t.Errorf("unexpected results after %d requests: %q", requests, results)
+	}
+}
@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"log"
@@ -30,6 +31,11 @@ func NewCachedTranslator(translator Translator, cache TranslationCache, provider
 
 // Translate translates text, using cache when available
 func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
+	return ct.TranslateWithContext(context.Background(), text, targetLang)
+}
+
+// TranslateWithContext translates text like Translate, cancelling the translation request when ctx is done
+func (ct *CachedTranslator) TranslateWithContext(ctx context.Context, text, targetLang string) (string, error) {
 	if text == "" {
 		return "", nil
 	}
@@ -45,7 +51,7 @@ func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
 	}
 
 	// Not in cache, perform translation
-	translated, err := ct.translator.Translate(text, targetLang)
+	translated, err := translateWithContext(ctx, ct.translator, text, targetLang)
 	if err != nil {
 		return "", err
 	}
@@ -61,6 +67,53 @@ func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
 	return translated, nil
 }
 
+// TranslateBatch translates texts like TranslateWithContext, sending the texts missing from the
+// cache to the translator in one batch
+func (ct *CachedTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	results := make([]string, len(texts))
+
+	// Identical texts are translated once
+	pending := make(map[string][]int)
+	var misses []string
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		if indexes, ok := pending[text]; ok {
+			pending[text] = append(indexes, i)
+			continue
+		}
+		if ct.cache != nil {
+			if cached, found, err := ct.cache.GetCachedTranslation(hashText(text), targetLang, ct.provider); err == nil && found {
+				results[i] = cached
+				continue
+			}
+		}
+		pending[text] = []int{i}
+		misses = append(misses, text)
+	}
+	if len(misses) == 0 {
+		return results, nil
+	}
+
+	translations, err := TranslateBatch(ctx, ct.translator, misses, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	for j, text := range misses {
+		for _, i := range pending[text] {
+			results[i] = translations[j]
+		}
+		if ct.cache != nil {
+			if cacheErr := ct.cache.SetCachedTranslation(hashText(text), text, targetLang, translations[j], ct.provider); cacheErr != nil {
+				// Log but don't fail - caching is optional
+				log.Printf("Warning: failed to cache translation: %v", cacheErr)
+			}
+		}
+	}
+	return results, nil
+}
+
 // hashText creates a SHA256 hash of the text for cache lookup
 func hashText(text string) string {
 	h := sha256.New()
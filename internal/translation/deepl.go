@@ -2,21 +2,41 @@ package translation
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
+// deeplMaxTexts is the number of texts the DeepL API accepts in one request
+const deeplMaxTexts = 50
+
 type DeepLTranslator struct {
 	APIKey   string
 	Endpoint string // Custom endpoint for deeplx self-hosted service
 	client   *http.Client
 	db       DBInterface
+
+	glossaryMu sync.Mutex
+	glossaries map[string]deeplGlossary // By language pair, see glossaryFor
+}
+
+// deeplGlossary is a glossary created on the DeepL account
+type deeplGlossary struct {
+	id         string // Empty if the glossary couldn't be created
+	name       string
+	sourceLang string
 }
 
+// deeplGlossaryPrefix starts the names of the glossaries created by MrRSS
+const deeplGlossaryPrefix = "MrRSS "
+
 // NewDeepLTranslator creates a new DeepL Translator
 // db is optional - if nil, no proxy will be used
 func NewDeepLTranslator(apiKey string) *DeepLTranslator {
@@ -78,24 +98,110 @@ func (t *DeepLTranslator) Translate(text, targetLang string) (string, error) {
 	}
 
 	// Standard DeepL API
-	apiURL := "https://api.deepl.com/v2/translate"
+	translations, err := t.translateTexts(context.Background(), []string{text}, targetLang, nil)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateWithGlossary translates text like Translate with a DeepL glossary, see TranslateBatchWithGlossary
+func (t *DeepLTranslator) TranslateWithGlossary(ctx context.Context, text, targetLang string, glossary Glossary) (string, error) {
+	translations, err := t.TranslateBatchWithGlossary(ctx, []string{text}, targetLang, glossary)
+	if err != nil {
+		return "", err
+	}
+	return translations[0], nil
+}
+
+// TranslateBatchWithGlossary translates texts like TranslateBatch with a DeepL glossary for the
+// language pair. DeepL glossaries need the source language, which is detected from texts; the
+// glossary's terms are masked instead when it is unknown, for deeplx, and when DeepL rejects the glossary.
+func (t *DeepLTranslator) TranslateBatchWithGlossary(ctx context.Context, texts []string, targetLang string, glossary Glossary) ([]string, error) {
+	if t.Endpoint != "" {
+		return translateBatchMasked(ctx, t, texts, targetLang, glossary)
+	}
+
+	sourceLang := GetLanguageDetector().DetectLanguage(strings.Join(texts, "\n"))
+	if sourceLang == "" || SameLanguage(sourceLang, targetLang) {
+		return translateBatchMasked(ctx, t, texts, targetLang, glossary)
+	}
+	dg := t.glossaryFor(ctx, sourceLang, targetLang, glossary)
+	if dg.id == "" {
+		return translateBatchMasked(ctx, t, texts, targetLang, glossary)
+	}
+	return t.translateChunks(ctx, texts, targetLang, &dg)
+}
+
+// TranslateBatch translates texts with one DeepL request per deeplMaxTexts texts.
+// deeplx has no batch support, so texts are translated one by one there.
+func (t *DeepLTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	if t.Endpoint != "" {
+		return translateConcurrently(ctx, t, texts, targetLang)
+	}
+
+	return t.translateChunks(ctx, texts, targetLang, nil)
+}
+
+// translateChunks translates texts with one request per deeplMaxTexts texts, using glossary if it isn't nil
+func (t *DeepLTranslator) translateChunks(ctx context.Context, texts []string, targetLang string, glossary *deeplGlossary) ([]string, error) {
+	results := make([]string, len(texts))
+	indexes := nonEmpty(texts)
+	for start := 0; start < len(indexes); start += deeplMaxTexts {
+		chunk := indexes[start:min(start+deeplMaxTexts, len(indexes))]
+		batch := make([]string, len(chunk))
+		for j, i := range chunk {
+			batch[j] = texts[i]
+		}
+
+		translations, err := t.translateTexts(ctx, batch, targetLang, glossary)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range chunk {
+			results[i] = translations[j]
+		}
+	}
+	return results, nil
+}
+
+// apiURL returns the URL of a DeepL API path, on the free API for free account keys
+func (t *DeepLTranslator) apiURL(path string) string {
 	if strings.HasSuffix(t.APIKey, ":fx") {
-		apiURL = "https://api-free.deepl.com/v2/translate"
+		return "https://api-free.deepl.com/v2" + path
 	}
+	return "https://api.deepl.com/v2" + path
+}
+
+// translateTexts translates texts in one request to the DeepL API, using glossary if it isn't nil
+func (t *DeepLTranslator) translateTexts(ctx context.Context, texts []string, targetLang string, glossary *deeplGlossary) ([]string, error) {
+	apiURL := t.apiURL("/translate")
 
 	data := url.Values{}
 	data.Set("auth_key", t.APIKey)
-	data.Set("text", text)
+	for _, text := range texts {
+		data.Add("text", text)
+	}
 	data.Set("target_lang", strings.ToUpper(targetLang))
+	if glossary != nil {
+		data.Set("source_lang", strings.ToUpper(glossary.sourceLang))
+		data.Set("glossary_id", glossary.id)
+	}
 
-	resp, err := t.client.PostForm(apiURL, data)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("deepl api returned status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("deepl api returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -105,14 +211,135 @@ func (t *DeepLTranslator) Translate(text, targetLang string) (string, error) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Translations) == 0 {
+		return nil, fmt.Errorf("no translation found")
+	}
+	if len(result.Translations) != len(texts) {
+		return nil, fmt.Errorf("deepl api returned %d translations for %d texts", len(result.Translations), len(texts))
+	}
+
+	translations := make([]string, len(texts))
+	for i, translation := range result.Translations {
+		translations[i] = translation.Text
+	}
+	return translations, nil
+}
+
+// glossaryFor returns the DeepL glossary of the language pair with the glossary's entries, creating
+// it if there is none. Glossaries created for previous versions of the entries are deleted.
+// A glossary DeepL rejects is not requested again until the entries change.
+func (t *DeepLTranslator) glossaryFor(ctx context.Context, sourceLang, targetLang string, glossary Glossary) deeplGlossary {
+	sourceLang, targetLang = normalizeLangCode(sourceLang), normalizeLangCode(targetLang)
+	pair := sourceLang + "-" + targetLang
+	name := deeplGlossaryPrefix + pair + " " + hashText(glossary.tsv())[:16]
+
+	t.glossaryMu.Lock()
+	defer t.glossaryMu.Unlock()
+	if dg, ok := t.glossaries[pair]; ok && dg.name == name {
+		return dg
+	}
+
+	dg := deeplGlossary{name: name, sourceLang: sourceLang}
+	existing, err := t.listGlossaries(ctx)
+	if err != nil {
+		log.Printf("Failed to list DeepL glossaries, masking glossary terms instead: %v", err)
+		return dg
+	}
+	for _, g := range existing {
+		switch {
+		case g.Name == name && dg.id == "":
+			dg.id = g.GlossaryID
+		case strings.HasPrefix(g.Name, deeplGlossaryPrefix+pair+" "):
+			if err := t.deleteGlossary(ctx, g.GlossaryID); err != nil {
+				log.Printf("Failed to delete outdated DeepL glossary %s: %v", g.Name, err)
+			}
+		}
+	}
+	if dg.id == "" {
+		if dg.id, err = t.createGlossary(ctx, name, sourceLang, targetLang, glossary.tsv()); err != nil {
+			log.Printf("Failed to create DeepL glossary %s, masking glossary terms instead: %v", name, err)
+		}
+	}
+
+	if t.glossaries == nil {
+		t.glossaries = make(map[string]deeplGlossary)
+	}
+	t.glossaries[pair] = dg
+	return dg
+}
+
+// deeplGlossaryInfo describes a glossary in DeepL API responses
+type deeplGlossaryInfo struct {
+	GlossaryID string `json:"glossary_id"`
+	Name       string `json:"name"`
+}
+
+// listGlossaries returns the glossaries of the DeepL account
+func (t *DeepLTranslator) listGlossaries(ctx context.Context) ([]deeplGlossaryInfo, error) {
+	var result struct {
+		Glossaries []deeplGlossaryInfo `json:"glossaries"`
+	}
+	if err := t.glossaryRequest(ctx, http.MethodGet, "/glossaries", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Glossaries, nil
+}
+
+// createGlossary creates a glossary from tab-separated entries and returns its ID
+func (t *DeepLTranslator) createGlossary(ctx context.Context, name, sourceLang, targetLang, entries string) (string, error) {
+	data := url.Values{}
+	data.Set("name", name)
+	data.Set("source_lang", sourceLang)
+	data.Set("target_lang", targetLang)
+	data.Set("entries", entries)
+	data.Set("entries_format", "tsv")
+
+	var created deeplGlossaryInfo
+	if err := t.glossaryRequest(ctx, http.MethodPost, "/glossaries", data, &created); err != nil {
 		return "", err
 	}
+	if created.GlossaryID == "" {
+		return "", fmt.Errorf("deepl api returned no glossary id")
+	}
+	return created.GlossaryID, nil
+}
+
+// deleteGlossary deletes a glossary from the DeepL account
+func (t *DeepLTranslator) deleteGlossary(ctx context.Context, id string) error {
+	return t.glossaryRequest(ctx, http.MethodDelete, "/glossaries/"+url.PathEscape(id), nil, nil)
+}
+
+// glossaryRequest sends a request to the DeepL glossary API, decoding the response into result if it isn't nil
+func (t *DeepLTranslator) glossaryRequest(ctx context.Context, method, path string, data url.Values, result interface{}) error {
+	var body io.Reader
+	if data != nil {
+		body = strings.NewReader(data.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.apiURL(path), body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+t.APIKey)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
-	if len(result.Translations) > 0 {
-		return result.Translations[0].Text, nil
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 
-	return "", fmt.Errorf("no translation found")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("deepl api returned status: %d", resp.StatusCode)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
 }
 
 // translateWithDeeplx handles translation using deeplx self-hosted service
@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"strings"
@@ -22,9 +23,10 @@ type CacheProvider interface {
 // DynamicTranslator is a translator that dynamically selects the translation provider
 // based on user settings. It creates the appropriate translator at translation time.
 type DynamicTranslator struct {
-	settings SettingsProvider
-	cache    CacheProvider
-	mu       sync.RWMutex
+	settings   SettingsProvider
+	cache      CacheProvider
+	glossaries GlossaryProvider
+	mu         sync.RWMutex
 	// Cache the current translator to avoid recreating it for every translation
 	cachedTranslator    Translator
 	cachedProvider      string
@@ -56,8 +58,21 @@ func NewDynamicTranslatorWithCache(settings SettingsProvider, cache CacheProvide
 	}
 }
 
+// SetGlossaries applies the glossaries of glossaries to every translation, see WithGlossary
+func (t *DynamicTranslator) SetGlossaries(glossaries GlossaryProvider) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.glossaries = glossaries
+	t.cachedTranslator = nil
+}
+
 // Translate translates text using the currently configured translation provider.
 func (t *DynamicTranslator) Translate(text, targetLang string) (string, error) {
+	return t.TranslateWithContext(context.Background(), text, targetLang)
+}
+
+// TranslateWithContext translates text like Translate, cancelling the translation request when ctx is done
+func (t *DynamicTranslator) TranslateWithContext(ctx context.Context, text, targetLang string) (string, error) {
 	if text == "" {
 		return "", nil
 	}
@@ -70,10 +85,26 @@ func (t *DynamicTranslator) Translate(text, targetLang string) (string, error) {
 	// Wrap with caching if cache is available
 	if t.cache != nil {
 		cachedTranslator := NewCachedTranslator(translator, t.cache, provider)
-		return cachedTranslator.Translate(text, targetLang)
+		return cachedTranslator.TranslateWithContext(ctx, text, targetLang)
 	}
 
-	return translator.Translate(text, targetLang)
+	return translateWithContext(ctx, translator, text, targetLang)
+}
+
+// TranslateBatch translates texts using the currently configured translation provider,
+// in as few requests as the provider allows
+func (t *DynamicTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	translator, provider, err := t.getTranslatorWithProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap with caching if cache is available
+	if t.cache != nil {
+		return NewCachedTranslator(translator, t.cache, provider).TranslateBatch(ctx, texts, targetLang)
+	}
+
+	return TranslateBatch(ctx, translator, texts, targetLang)
 }
 
 // getTranslatorWithProvider returns the appropriate translator and provider name based on current settings.
@@ -95,6 +126,9 @@ func (t *DynamicTranslator) getTranslatorWithProvider() (Translator, string, err
 	case "baidu":
 		appID, _ = t.settings.GetSetting("baidu_app_id")
 		secretKey, _ = t.settings.GetEncryptedSetting("baidu_secret_key")
+	case "libretranslate":
+		apiKey, _ = t.settings.GetEncryptedSetting("libretranslate_api_key")
+		endpoint, _ = t.settings.GetSetting("libretranslate_endpoint")
 	case "ai":
 		apiKey, _ = t.settings.GetEncryptedSetting("ai_api_key")
 		endpoint, _ = t.settings.GetSetting("ai_endpoint")
@@ -159,6 +193,12 @@ func (t *DynamicTranslator) getTranslatorWithProvider() (Translator, string, err
 			return nil, "", fmt.Errorf("Baidu App ID and Secret Key are required")
 		}
 		translator = NewBaiduTranslator(appID, secretKey)
+	case "libretranslate":
+		// The API key is optional, most self-hosted servers don't require one
+		if endpoint == "" {
+			return nil, "", fmt.Errorf("LibreTranslate endpoint is required")
+		}
+		translator = NewLibreTranslateTranslatorWithDB(endpoint, apiKey, t.settings)
 	case "ai":
 		// Allow empty API key for local endpoints (e.g., Ollama)
 		if apiKey == "" && !isLocalEndpoint(endpoint) {
@@ -192,6 +232,9 @@ func (t *DynamicTranslator) getTranslatorWithProvider() (Translator, string, err
 	default:
 		translator = NewGoogleFreeTranslator()
 	}
+	if t.glossaries != nil {
+		translator = WithGlossary(translator, t.glossaries)
+	}
 
 	// Cache the translator
 	t.cachedTranslator = translator
@@ -212,7 +255,8 @@ func (t *DynamicTranslator) getTranslatorWithProvider() (Translator, string, err
 }
 
 // isLocalEndpoint checks if an endpoint URL points to a local service (localhost, 127.0.0.1, etc.)
-// This allows using empty API keys for local LLM services like Ollama
+// This allows using empty API keys for local LLM services like Ollama, and lets local
+// LibreTranslate servers bypass the proxy
 func isLocalEndpoint(endpointURL string) bool {
 	if endpointURL == "" {
 		return false
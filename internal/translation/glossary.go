@@ -0,0 +1,293 @@
+package translation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"MrRSS/internal/models"
+)
+
+// GlossaryProvider returns the glossary entries that apply when translating to a language
+type GlossaryProvider interface {
+	GetGlossary(targetLang string) ([]models.GlossaryEntry, error)
+}
+
+// GlossaryTranslator is implemented by translators that apply a glossary natively instead of
+// having its terms masked with placeholders
+type GlossaryTranslator interface {
+	Translator
+	TranslateWithGlossary(ctx context.Context, text, targetLang string, glossary Glossary) (string, error)
+	TranslateBatchWithGlossary(ctx context.Context, texts []string, targetLang string, glossary Glossary) ([]string, error)
+}
+
+// Glossary is the terminology applied when translating to one language. Terms match
+// case-sensitively and only as whole words.
+type Glossary []models.GlossaryEntry
+
+// NewGlossary returns the glossary of entries, dropping empty terms and keeping the first entry
+// of a term defined more than once
+func NewGlossary(entries []models.GlossaryEntry) Glossary {
+	seen := make(map[string]bool, len(entries))
+	glossary := make(Glossary, 0, len(entries))
+	for _, entry := range entries {
+		entry.Term = strings.TrimSpace(entry.Term)
+		if entry.Term == "" || seen[entry.Term] {
+			continue
+		}
+		seen[entry.Term] = true
+		glossary = append(glossary, entry)
+	}
+	return glossary
+}
+
+// Matching returns the entries whose term occurs in any of texts
+func (g Glossary) Matching(texts ...string) Glossary {
+	var matching Glossary
+	for _, entry := range g {
+		for _, text := range texts {
+			if containsTerm(text, entry.Term) {
+				matching = append(matching, entry)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// instructions tells an AI model how to translate the glossary's terms
+func (g Glossary) instructions() string {
+	var b strings.Builder
+	b.WriteString("Apply this glossary exactly, wherever the term occurs:")
+	for _, entry := range g {
+		if entry.Protected || entry.Translation == "" {
+			fmt.Fprintf(&b, "\n- %q: keep as-is, do not translate", entry.Term)
+		} else {
+			fmt.Fprintf(&b, "\n- %q: translate as %q", entry.Term, entry.Translation)
+		}
+	}
+	return b.String()
+}
+
+// tsv returns the glossary as tab-separated term and translation pairs, one per line. Terms
+// that can't be represented are left out.
+func (g Glossary) tsv() string {
+	var lines []string
+	for _, entry := range g {
+		target := glossaryTarget(entry)
+		if strings.ContainsAny(entry.Term, "\t\r\n") || strings.ContainsAny(target, "\t\r\n") {
+			continue
+		}
+		lines = append(lines, entry.Term+"\t"+target)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mask replaces the glossary terms in text with numbered placeholders, longest terms first,
+// and returns the text with what each placeholder stands for in the translation
+func (g Glossary) mask(text string) (string, []string) {
+	matching := g.Matching(text)
+	if len(matching) == 0 {
+		return text, nil
+	}
+	sort.SliceStable(matching, func(i, j int) bool { return len(matching[i].Term) > len(matching[j].Term) })
+
+	var b strings.Builder
+	var replacements []string
+	numbers := make(map[string]int)
+	for i := 0; i < len(text); {
+		matched := false
+		for _, entry := range matching {
+			if !termAt(text, i, entry.Term) {
+				continue
+			}
+			n, ok := numbers[entry.Term]
+			if !ok {
+				replacements = append(replacements, glossaryTarget(entry))
+				n = len(replacements)
+				numbers[entry.Term] = n
+			}
+			fmt.Fprintf(&b, "[[T%d]]", n)
+			i += len(entry.Term)
+			matched = true
+			break
+		}
+		if !matched {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			b.WriteString(text[i : i+size])
+			i += size
+		}
+	}
+	return b.String(), replacements
+}
+
+// glossaryTarget returns what an entry's term becomes in the translation
+func glossaryTarget(entry models.GlossaryEntry) string {
+	if entry.Protected || entry.Translation == "" {
+		return entry.Term
+	}
+	return entry.Translation
+}
+
+// placeholderPattern matches a glossary placeholder, tolerating the spaces translators add
+var placeholderPattern = regexp.MustCompile(`\[\[\s*[Tt]\s*(\d+)\s*\]\]`)
+
+// unmask replaces the placeholders in a translation with what they stand for
+func unmask(translated string, replacements []string) string {
+	if len(replacements) == 0 {
+		return translated
+	}
+	return placeholderPattern.ReplaceAllStringFunc(translated, func(placeholder string) string {
+		n, _ := strconv.Atoi(placeholderPattern.FindStringSubmatch(placeholder)[1])
+		if n < 1 || n > len(replacements) {
+			return placeholder
+		}
+		return replacements[n-1]
+	})
+}
+
+// onlyPlaceholders reports whether masked text has nothing left to translate
+func onlyPlaceholders(masked string) bool {
+	return strings.TrimSpace(placeholderPattern.ReplaceAllString(masked, "")) == ""
+}
+
+// containsTerm reports whether term occurs in text as a whole word
+func containsTerm(text, term string) bool {
+	if term == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		if termAt(text, offset+i, term) {
+			return true
+		}
+		offset += i + 1
+	}
+}
+
+// termAt reports whether term occurs at byte i of text without being part of a longer word
+func termAt(text string, i int, term string) bool {
+	if !strings.HasPrefix(text[i:], term) {
+		return false
+	}
+	if first, _ := utf8.DecodeRuneInString(term); isWordRune(first) {
+		if prev, _ := utf8.DecodeLastRuneInString(text[:i]); isWordRune(prev) {
+			return false
+		}
+	}
+	if last, _ := utf8.DecodeLastRuneInString(term); isWordRune(last) {
+		if next, _ := utf8.DecodeRuneInString(text[i+len(term):]); isWordRune(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// isWordRune reports whether r continues a word. Scripts written without spaces have no word
+// boundaries, so their terms match anywhere.
+func isWordRune(r rune) bool {
+	if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+		return false
+	}
+	return !unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai)
+}
+
+// translateMasked translates text with the glossary's terms masked by placeholders, for
+// translators without native glossary support
+func translateMasked(ctx context.Context, translator Translator, text, targetLang string, glossary Glossary) (string, error) {
+	masked, replacements := glossary.mask(text)
+	if onlyPlaceholders(masked) {
+		return unmask(masked, replacements), nil
+	}
+	translated, err := translateWithContext(ctx, translator, masked, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return unmask(translated, replacements), nil
+}
+
+// translateBatchMasked translates texts like translateMasked, in as few requests as translator allows
+func translateBatchMasked(ctx context.Context, translator Translator, texts []string, targetLang string, glossary Glossary) ([]string, error) {
+	masked := make([]string, len(texts))
+	pending := make([]string, len(texts))
+	replacements := make([][]string, len(texts))
+	for i, text := range texts {
+		masked[i], replacements[i] = glossary.mask(text)
+		if !onlyPlaceholders(masked[i]) {
+			pending[i] = masked[i]
+		}
+	}
+
+	translations, err := TranslateBatch(ctx, translator, pending, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	for i := range translations {
+		if pending[i] == "" {
+			translations[i] = masked[i]
+		}
+		translations[i] = unmask(translations[i], replacements[i])
+	}
+	return translations, nil
+}
+
+// WithGlossary returns a translator applying the glossary of the target language to every
+// translation: natively when translator is a GlossaryTranslator, otherwise by masking the
+// glossary's terms with placeholders the translator leaves untouched
+func WithGlossary(translator Translator, glossaries GlossaryProvider) Translator {
+	return &glossaryTranslator{translator: translator, glossaries: glossaries}
+}
+
+// glossaryTranslator applies the user's glossaries around another translator
+type glossaryTranslator struct {
+	translator Translator
+	glossaries GlossaryProvider
+}
+
+// Translate translates text, applying the glossary of targetLang
+func (gt *glossaryTranslator) Translate(text, targetLang string) (string, error) {
+	return gt.TranslateWithContext(context.Background(), text, targetLang)
+}
+
+// TranslateWithContext translates text like Translate, cancelling the translation request when ctx is done
+func (gt *glossaryTranslator) TranslateWithContext(ctx context.Context, text, targetLang string) (string, error) {
+	glossary := gt.glossary(targetLang)
+	if len(glossary.Matching(text)) == 0 {
+		return translateWithContext(ctx, gt.translator, text, targetLang)
+	}
+	if native, ok := gt.translator.(GlossaryTranslator); ok {
+		return native.TranslateWithGlossary(ctx, text, targetLang, glossary)
+	}
+	return translateMasked(ctx, gt.translator, text, targetLang, glossary)
+}
+
+// TranslateBatch translates texts like TranslateWithContext, in as few requests as the translator allows
+func (gt *glossaryTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	glossary := gt.glossary(targetLang)
+	if len(glossary.Matching(texts...)) == 0 {
+		return TranslateBatch(ctx, gt.translator, texts, targetLang)
+	}
+	if native, ok := gt.translator.(GlossaryTranslator); ok {
+		return native.TranslateBatchWithGlossary(ctx, texts, targetLang, glossary)
+	}
+	return translateBatchMasked(ctx, gt.translator, texts, targetLang, glossary)
+}
+
+// glossary loads the glossary of targetLang. A glossary that can't be loaded is not applied.
+func (gt *glossaryTranslator) glossary(targetLang string) Glossary {
+	entries, err := gt.glossaries.GetGlossary(targetLang)
+	if err != nil {
+		log.Printf("Warning: failed to load the translation glossary: %v", err)
+		return nil
+	}
+	return NewGlossary(entries)
+}
@@ -0,0 +1,169 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/ai"
+	"MrRSS/internal/models"
+)
+
+// staticGlossaries returns the same glossary for every language
+type staticGlossaries []models.GlossaryEntry
+
+func (g staticGlossaries) GetGlossary(targetLang string) ([]models.GlossaryEntry, error) {
+	return g, nil
+}
+
+var testGlossary = NewGlossary([]models.GlossaryEntry{
+	{Term: "Visual Studio Code", Protected: true},
+	{Term: "Visual Studio", Translation: "VS"},
+	{Term: "Go", Protected: true},
+	{Term: "pull request", Translation: "合并请求"},
+	{Term: "", Translation: "ignored"},
+	{Term: "Go", Translation: "duplicate"},
+})
+
+func TestGlossary_Matching(t *testing.T) {
+	if len(testGlossary) != 4 {
+		t.Fatalf("expected empty and duplicate terms to be dropped, got %+v", testGlossary)
+	}
+
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Written in Go.", []string{"Go"}},
+		{"Google and gopher", nil},
+		{"Going further", nil},
+		{"用Go语言写的", []string{"Go"}},
+		{"Open a pull request in Visual Studio Code", []string{"Visual Studio Code", "Visual Studio", "pull request"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, entry := range testGlossary.Matching(tt.text) {
+			got = append(got, entry.Term)
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Matching(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestGlossary_MaskAndUnmask(t *testing.T) {
+	masked, replacements := testGlossary.mask("Visual Studio Code and Visual Studio: a pull request in Go, another pull request")
+	want := "[[T1]] and [[T2]]: a [[T3]] in [[T4]], another [[T3]]"
+	if masked != want {
+		t.Fatalf("mask = %q, want %q", masked, want)
+	}
+
+	// Translators may add spaces inside the placeholders
+	translated := "[[T1]] und [[ T2 ]]: ein [[t3]] in [[T4]], noch ein [[T3]] [[T9]]"
+	got := unmask(translated, replacements)
+	if got != "Visual Studio Code und VS: ein 合并请求 in Go, noch ein 合并请求 [[T9]]" {
+		t.Errorf("unmask = %q", got)
+	}
+}
+
+func TestWithGlossary_Masking(t *testing.T) {
+	var sent []string
+	translator := WithGlossary(&TestTranslator{TranslateFunc: func(text, targetLang string) (string, error) {
+		sent = append(sent, text)
+		return "<" + text + ">", nil
+	}}, staticGlossaries(testGlossary))
+
+	got, err := translator.Translate("Open a pull request", "zh")
+	if err != nil || got != "<Open a 合并请求>" {
+		t.Errorf("expected the glossary translation, got %q (%v)", got, err)
+	}
+	if got, _ := translator.Translate("Go", "zh"); got != "Go" {
+		t.Errorf("expected a text of protected terms only to be kept, got %q", got)
+	}
+	if got, _ := translator.Translate("Hello", "zh"); got != "<Hello>" {
+		t.Errorf("expected texts without terms to be translated as-is, got %q", got)
+	}
+	if strings.Join(sent, "|") != "Open a [[T1]]|Hello" {
+		t.Errorf("unexpected texts sent to the translator: %q", sent)
+	}
+
+	results, err := TranslateBatch(context.Background(), translator, []string{"Go", "", "Learn Go"}, "de")
+	if err != nil || strings.Join(results, "|") != "Go||<Learn Go>" {
+		t.Errorf("unexpected batch results: %q (%v)", results, err)
+	}
+
+	// The markdown structure is kept around masked terms
+	markdown, err := TranslateMarkdownPreservingStructure("- Go\n- pull request review", translator, "zh")
+	if err != nil || markdown != "- Go\n- <合并请求 review>" {
+		t.Errorf("unexpected markdown translation: %q (%v)", markdown, err)
+	}
+}
+
+func TestAITranslator_GlossaryInstructions(t *testing.T) {
+	translator := NewAITranslator("apikey", "https://api.test", "m1")
+	var prompts []string
+	translator.client = ai.NewClientWithHTTPClient(ai.ClientConfig{
+		APIKey:   "apikey",
+		Endpoint: "https://api.test",
+		Model:    "m1",
+		Timeout:  5 * time.Second,
+	}, &http.Client{Transport: rtFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		prompts = append(prompts, string(body))
+		encoded, _ := json.Marshal(map[string]interface{}{"choices": []interface{}{map[string]interface{}{"message": map[string]string{"content": "Ouvrez une PR"}}}})
+		return jsonResponse(string(encoded)), nil
+	}), Timeout: 5 * time.Second})
+
+	got, err := WithGlossary(translator, staticGlossaries(testGlossary)).Translate("Open a pull request", "fr")
+	if err != nil || got != "Ouvrez une PR" {
+		t.Fatalf("unexpected translation %q (%v)", got, err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], `translate as \"合并请求\"`) || strings.Contains(prompts[0], "Visual Studio") {
+		t.Errorf("expected the matching glossary entries in the prompt, got %v", prompts)
+	}
+}
+
+func TestDeepLTranslator_Glossary(t *testing.T) {
+	translator := NewDeepLTranslator("apikey")
+	var created, deleted, translated url.Values
+	translator.client = &http.Client{Transport: rtFunc(func(req *http.Request) (*http.Response, error) {
+		var form url.Values
+		if req.Body != nil {
+			body, _ := io.ReadAll(req.Body)
+			form, _ = url.ParseQuery(string(body))
+		}
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == "/v2/glossaries":
+			return jsonResponse(`{"glossaries":[{"glossary_id":"old","name":"MrRSS en-de 0000000000000000"},{"glossary_id":"other","name":"Team glossary"}]}`), nil
+		case req.Method == http.MethodPost && req.URL.Path == "/v2/glossaries":
+			created = form
+			return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(`{"glossary_id":"g1"}`))}, nil
+		case req.Method == http.MethodDelete:
+			deleted = url.Values{"path": {req.URL.Path}}
+			return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(strings.NewReader(""))}, nil
+		default:
+			translated = form
+			return jsonResponse(`{"translations":[{"text":"Öffne einen Pull Request"}]}`), nil
+		}
+	}), Timeout: 5 * time.Second}
+
+	text := "Open a pull request and review the changes before merging them into the main branch"
+	got, err := WithGlossary(translator, staticGlossaries(testGlossary)).Translate(text, "de")
+	if err != nil || got != "Öffne einen Pull Request" {
+		t.Fatalf("unexpected translation %q (%v)", got, err)
+	}
+	if created.Get("source_lang") != "en" || created.Get("target_lang") != "de" || !strings.Contains(created.Get("entries"), "pull request\t合并请求") {
+		t.Errorf("unexpected glossary creation: %v", created)
+	}
+	if deleted.Get("path") != "/v2/glossaries/old" {
+		t.Errorf("expected the outdated glossary to be deleted, got %v", deleted)
+	}
+	if translated.Get("glossary_id") != "g1" || translated.Get("source_lang") != "EN" || translated.Get("text") != text {
+		t.Errorf("expected the text to be translated with the glossary, got %v", translated)
+	}
+}
@@ -3,6 +3,7 @@ package translation
 import (
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/abadojack/whatlanggo"
 )
@@ -31,14 +32,22 @@ func GetLanguageDetector() *LanguageDetector {
 // Returns the ISO 639-1 language code (e.g., "en", "zh", "ja")
 // Returns empty string if detection fails or confidence is too low
 func (ld *LanguageDetector) DetectLanguage(text string) string {
+	lang, _ := ld.DetectLanguageWithConfidence(text)
+	return lang
+}
+
+// DetectLanguageWithConfidence detects the language of the given text like DetectLanguage,
+// also returning the confidence of the detection from 0 to 1
+// Returns an empty string and zero confidence if detection fails or confidence is too low
+func (ld *LanguageDetector) DetectLanguageWithConfidence(text string) (string, float64) {
 	if text == "" {
-		return ""
+		return "", 0
 	}
 
 	// Clean text for better detection
 	text = strings.TrimSpace(text)
 	if len(text) < 3 {
-		return ""
+		return "", 0
 	}
 
 	// Remove HTML tags if present
@@ -66,12 +75,33 @@ func (ld *LanguageDetector) DetectLanguage(text string) string {
 
 	// Check confidence level - only accept high confidence detections
 	if info.Confidence < 0.5 {
-		return ""
+		return "", 0
 	}
 
 	// Convert whatlanggo Lang to ISO 639-1 code
 	detectedCode := whatlangToISOCode(info.Lang)
-	return detectedCode
+	if detectedCode == "" {
+		return "", 0
+	}
+	return detectedCode, info.Confidence
+}
+
+// articleSampleBytes bounds the article text used by DetectArticleLanguage
+const articleSampleBytes = 2000
+
+// DetectArticleLanguage detects the language of an article from its title and the start of its
+// content, which may be HTML, returning the language with its confidence like DetectLanguageWithConfidence
+func (ld *LanguageDetector) DetectArticleLanguage(title, content string) (string, float64) {
+	text := title + "\n" + content
+	if len(text) > articleSampleBytes {
+		// Don't cut a multi-byte character in half
+		n := articleSampleBytes
+		for n > 0 && !utf8.RuneStart(text[n]) {
+			n--
+		}
+		text = text[:n]
+	}
+	return ld.DetectLanguageWithConfidence(text)
 }
 
 // ShouldTranslate determines if translation is needed based on language detection
@@ -242,6 +272,12 @@ func whatlangToISOCode(lang whatlanggo.Lang) string {
 	return ""
 }
 
+// SameLanguage reports whether a detected language code is the target language, ignoring regions.
+// An empty (undetected) language is never the same as the target.
+func SameLanguage(lang, targetLang string) bool {
+	return lang != "" && normalizeLangCode(lang) == normalizeLangCode(targetLang)
+}
+
 // normalizeLangCode normalizes language codes (e.g., "zh-CN" -> "zh", "en-US" -> "en")
 func normalizeLangCode(code string) string {
 	code = strings.ToLower(strings.TrimSpace(code))
@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -180,6 +181,48 @@ func TestLanguageDetector_ShouldTranslate(t *testing.T) {
 	}
 }
 
+func TestLanguageDetector_DetectLanguageWithConfidence(t *testing.T) {
+	detector := GetLanguageDetector()
+
+	lang, confidence := detector.DetectLanguageWithConfidence("This is a test article about technology and programming.")
+	if lang != "en" || confidence < 0.5 || confidence > 1 {
+		t.Errorf("DetectLanguageWithConfidence() = %q, %v, want en with a confidence from 0.5 to 1", lang, confidence)
+	}
+
+	lang, confidence = detector.DetectLanguageWithConfidence("ab")
+	if lang != "" || confidence != 0 {
+		t.Errorf("DetectLanguageWithConfidence() = %q, %v, want no detection for short text", lang, confidence)
+	}
+}
+
+func TestLanguageDetector_DetectArticleLanguage(t *testing.T) {
+	detector := GetLanguageDetector()
+
+	lang, confidence := detector.DetectArticleLanguage("Der Staat und die Wirtschaft", "<p>Die Regierung hat am Montag neue Regeln für die Wirtschaft vorgestellt, die ab dem nächsten Jahr gelten sollen.</p>")
+	if lang != "de" || confidence <= 0 {
+		t.Errorf("DetectArticleLanguage() = %q, %v, want de with a confidence", lang, confidence)
+	}
+
+	// Long content is cut on a character boundary
+	content := strings.Repeat("这是一篇关于技术和编程的测试文章。", 200)
+	if lang, _ := detector.DetectArticleLanguage("技术", content); lang != "zh" {
+		t.Errorf("DetectArticleLanguage() = %q, want zh", lang)
+	}
+
+	if lang, confidence := detector.DetectArticleLanguage("", ""); lang != "" || confidence != 0 {
+		t.Errorf("DetectArticleLanguage() = %q, %v, want no detection for empty text", lang, confidence)
+	}
+}
+
+func TestSameLanguage(t *testing.T) {
+	if !SameLanguage("zh", "zh-CN") || !SameLanguage("en", "EN") {
+		t.Errorf("expected languages to match regardless of region and case")
+	}
+	if SameLanguage("", "en") || SameLanguage("fr", "en") {
+		t.Errorf("expected undetected and different languages not to match")
+	}
+}
+
 func TestNormalizeLangCode(t *testing.T) {
 	tests := []struct {
 		input string
@@ -0,0 +1,208 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limits for the texts sent to LibreTranslate in one request
+const (
+	libreTranslateMaxTexts = 50
+	libreTranslateMaxChars = 5000
+)
+
+// LibreTranslateTranslator translates with a LibreTranslate server, usually self-hosted.
+// The source language is detected by the server.
+type LibreTranslateTranslator struct {
+	Endpoint string
+	APIKey   string // Optional, only needed by servers that require keys
+	client   *http.Client
+
+	languagesMu sync.Mutex
+	languages   []LibreTranslateLanguage // Discovered on first use
+}
+
+// LibreTranslateLanguage is a language a LibreTranslate server translates from, with the
+// languages it translates it to
+type LibreTranslateLanguage struct {
+	Code    string   `json:"code"`
+	Name    string   `json:"name"`
+	Targets []string `json:"targets"`
+}
+
+// NewLibreTranslateTranslator creates a LibreTranslate translator for the server at endpoint
+func NewLibreTranslateTranslator(endpoint, apiKey string) *LibreTranslateTranslator {
+	return &LibreTranslateTranslator{
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		APIKey:   apiKey,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// NewLibreTranslateTranslatorWithDB creates a LibreTranslate translator with proxy support.
+// Local servers are reached directly, bypassing the proxy.
+func NewLibreTranslateTranslatorWithDB(endpoint, apiKey string, db DBInterface) *LibreTranslateTranslator {
+	translator := NewLibreTranslateTranslator(endpoint, apiKey)
+	if isLocalEndpoint(endpoint) {
+		return translator
+	}
+	if client, err := CreateHTTPClientWithProxy(db, 30*time.Second); err == nil {
+		translator.client = client
+	}
+	return translator
+}
+
+// Translate translates text to targetLang
+func (t *LibreTranslateTranslator) Translate(text, targetLang string) (string, error) {
+	return t.TranslateWithContext(context.Background(), text, targetLang)
+}
+
+// TranslateWithContext translates text like Translate, cancelling the request when ctx is done
+func (t *LibreTranslateTranslator) TranslateWithContext(ctx context.Context, text, targetLang string) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	target, err := t.targetCode(ctx, targetLang)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		TranslatedText string `json:"translatedText"`
+	}
+	if err := t.translateRequest(ctx, text, target, &result); err != nil {
+		return "", err
+	}
+	return result.TranslatedText, nil
+}
+
+// TranslateBatch translates texts with one request per batch, as LibreTranslate accepts a list of texts
+func (t *LibreTranslateTranslator) TranslateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	results := make([]string, len(texts))
+	indexes := nonEmpty(texts)
+	if len(indexes) == 0 {
+		return results, nil
+	}
+	target, err := t.targetCode(ctx, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make([]string, len(indexes))
+	for j, i := range indexes {
+		pending[j] = texts[i]
+	}
+	for _, r := range batchRanges(pending, libreTranslateMaxTexts, libreTranslateMaxChars) {
+		batch := pending[r[0]:r[1]]
+		var result struct {
+			TranslatedText []string `json:"translatedText"`
+		}
+		if err := t.translateRequest(ctx, batch, target, &result); err != nil {
+			return nil, err
+		}
+		if len(result.TranslatedText) != len(batch) {
+			return nil, fmt.Errorf("libretranslate returned %d translations for %d texts", len(result.TranslatedText), len(batch))
+		}
+		for j, translated := range result.TranslatedText {
+			results[indexes[r[0]+j]] = translated
+		}
+	}
+	return results, nil
+}
+
+// translateRequest posts q, a text or a list of texts, to /translate and decodes the response into result
+func (t *LibreTranslateTranslator) translateRequest(ctx context.Context, q interface{}, target string, result interface{}) error {
+	body := map[string]interface{}{
+		"q":      q,
+		"source": "auto",
+		"target": target,
+		"format": "text",
+	}
+	if t.APIKey != "" {
+		body["api_key"] = t.APIKey
+	}
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal libretranslate request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint+"/translate", bytes.NewReader(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create libretranslate request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return t.do(req, result)
+}
+
+// Languages returns the languages the server supports, discovering them on first use
+func (t *LibreTranslateTranslator) Languages(ctx context.Context) ([]LibreTranslateLanguage, error) {
+	t.languagesMu.Lock()
+	defer t.languagesMu.Unlock()
+	if t.languages != nil {
+		return t.languages, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.Endpoint+"/languages", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create libretranslate request: %w", err)
+	}
+	var languages []LibreTranslateLanguage
+	if err := t.do(req, &languages); err != nil {
+		return nil, err
+	}
+	t.languages = languages
+	return languages, nil
+}
+
+// targetCode returns the server's code for targetLang, matching regional variants such as
+// "zh-Hans" for "zh". Without a language list the code is sent as-is.
+func (t *LibreTranslateTranslator) targetCode(ctx context.Context, targetLang string) (string, error) {
+	languages, err := t.Languages(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return targetLang, nil
+	}
+
+	for _, language := range languages {
+		if strings.EqualFold(language.Code, targetLang) {
+			return language.Code, nil
+		}
+	}
+	for _, language := range languages {
+		if normalizeLangCode(language.Code) == normalizeLangCode(targetLang) {
+			return language.Code, nil
+		}
+	}
+	return "", fmt.Errorf("libretranslate server does not support language %q", targetLang)
+}
+
+// do sends a request and decodes the JSON response into result, reporting the server's error message
+func (t *LibreTranslateTranslator) do(req *http.Request, result interface{}) error {
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("libretranslate request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiError struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiError) == nil && apiError.Error != "" {
+			return fmt.Errorf("libretranslate returned status %d: %s", resp.StatusCode, apiError.Error)
+		}
+		return fmt.Errorf("libretranslate returned status: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode libretranslate response: %w", err)
+	}
+	return nil
+}
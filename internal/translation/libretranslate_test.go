@@ -0,0 +1,105 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newLibreTranslateServer starts a LibreTranslate stand-in translating to English and Simplified
+// Chinese, which prefixes texts with the target language
+func newLibreTranslateServer(t *testing.T, apiKey string) (*httptest.Server, *atomic.Int32) {
+	var languageRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/languages":
+			languageRequests.Add(1)
+			w.Write([]byte(`[{"code":"en","name":"English","targets":["en","zh-Hans"]},{"code":"zh-Hans","name":"Chinese","targets":["en","zh-Hans"]}]`))
+		case "/translate":
+			var req struct {
+				Q      interface{} `json:"q"`
+				Source string      `json:"source"`
+				Target string      `json:"target"`
+				APIKey string      `json:"api_key"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.APIKey != apiKey {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"Invalid API key"}`))
+				return
+			}
+			if req.Source != "auto" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			switch q := req.Q.(type) {
+			case string:
+				json.NewEncoder(w).Encode(map[string]interface{}{"translatedText": req.Target + ":" + q})
+			case []interface{}:
+				translated := make([]string, len(q))
+				for i, text := range q {
+					translated[i] = req.Target + ":" + text.(string)
+				}
+				json.NewEncoder(w).Encode(map[string]interface{}{"translatedText": translated})
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &languageRequests
+}
+
+func TestLibreTranslateTranslator(t *testing.T) {
+	server, languageRequests := newLibreTranslateServer(t, "")
+	translator := NewLibreTranslateTranslator(server.URL+"/", "")
+
+	got, err := translator.Translate("Hello", "zh")
+	if err != nil || got != "zh-Hans:Hello" {
+		t.Errorf("expected zh to map to the server's zh-Hans, got %q (%v)", got, err)
+	}
+	results, err := translator.TranslateBatch(context.Background(), []string{"One", "", "Two"}, "en")
+	if err != nil || strings.Join(results, "|") != "en:One||en:Two" {
+		t.Errorf("unexpected batch results %q (%v)", results, err)
+	}
+	if languageRequests.Load() != 1 {
+		t.Errorf("expected the languages to be discovered once, got %d requests", languageRequests.Load())
+	}
+
+	if _, err := translator.Translate("Hello", "fr"); err == nil || !strings.Contains(err.Error(), "does not support") {
+		t.Errorf("expected an error for a language the server lacks, got %v", err)
+	}
+}
+
+func TestLibreTranslateTranslator_APIKey(t *testing.T) {
+	server, _ := newLibreTranslateServer(t, "secret")
+
+	if _, err := NewLibreTranslateTranslator(server.URL, "").Translate("Hello", "en"); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Errorf("expected the server's error message, got %v", err)
+	}
+	if got, err := NewLibreTranslateTranslator(server.URL, "secret").Translate("Hello", "en"); err != nil || got != "en:Hello" {
+		t.Errorf("unexpected translation %q (%v)", got, err)
+	}
+}
+
+func TestDynamicTranslator_LibreTranslate(t *testing.T) {
+	server, _ := newLibreTranslateServer(t, "")
+
+	// The local server is reached directly although the proxy is unreachable
+	translator := NewDynamicTranslator(&mockSettingsProvider{settings: map[string]string{
+		"translation_provider":    "libretranslate",
+		"libretranslate_endpoint": server.URL,
+		"proxy_enabled":           "true",
+		"proxy_type":              "http",
+		"proxy_host":              "127.0.0.1",
+		"proxy_port":              "1",
+	}})
+	got, err := translator.Translate("Hello", "zh")
+	if err != nil || got != "zh-Hans:Hello" {
+		t.Errorf("unexpected translation %q (%v)", got, err)
+	}
+}
@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"context"
 	"regexp"
 	"strings"
 )
@@ -117,8 +118,15 @@ func TranslateMarkdownAIPrompt(markdown string, translator Translator, targetLan
 		return "", nil
 	}
 
+	// Look through a bound context to the underlying translator
+	ctx := context.Background()
+	underlying := translator
+	if bound, ok := translator.(*boundTranslator); ok {
+		ctx, underlying = bound.ctx, bound.translator
+	}
+
 	// For AI translation, use a specialized prompt that emphasizes structure preservation
-	aiTranslator, ok := translator.(*AITranslator)
+	aiTranslator, ok := underlying.(*AITranslator)
 	if !ok {
 		// Not an AI translator, use standard preservation
 		return TranslateMarkdownPreservingStructure(markdown, translator, targetLang)
@@ -148,7 +156,7 @@ Output (to Chinese):
 	aiTranslator.SetSystemPrompt(structurePrompt)
 
 	// Translate
-	result, err := aiTranslator.Translate(markdown, targetLang)
+	result, err := aiTranslator.TranslateWithContext(ctx, markdown, targetLang)
 
 	// Restore original prompt
 	aiTranslator.SetSystemPrompt(originalPrompt)
@@ -0,0 +1,495 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Limits for batching segments into one translation request
+const (
+	maxBatchSegments = 20
+	maxBatchChars    = 4000
+)
+
+// segmentSeparator separates the segments of a batch in a translation request
+const segmentSeparator = "\n\n"
+
+// SegmentSystemPrompt is the AI system prompt for translating batches of article segments
+const SegmentSystemPrompt = `You are a translator. Translate the given text accurately.
+CRITICAL RULES:
+1. Paragraphs are separated by blank lines. Output the same number of paragraphs in the same order.
+2. Keep markers such as [[1]], [[/1]] and [[2/]] exactly as they are, around the words they enclose.
+3. Output ONLY the translated text, nothing else.`
+
+// markerPattern matches the markers that stand in for inline markup while a segment is
+// translated: [[n]]…[[/n]] wraps the text of a link or inline tag, [[n/]] an element kept as-is
+var markerPattern = regexp.MustCompile(`\[\[(/?)(\d+)(/?)\]\]`)
+
+// blankLinePattern matches the blank lines between translated segments
+var blankLinePattern = regexp.MustCompile(`\n\s*\n`)
+
+// inlineTags are the elements whose text is translated as part of the surrounding segment
+var inlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "data": true,
+	"del": true, "dfn": true, "em": true, "font": true, "i": true, "ins": true, "label": true,
+	"mark": true, "q": true, "s": true, "small": true, "span": true, "strong": true, "sub": true,
+	"sup": true, "time": true, "u": true,
+}
+
+// keptTags are inline elements kept untranslated within a segment
+var keptTags = map[string]bool{
+	"audio": true, "br": true, "button": true, "canvas": true, "code": true, "embed": true,
+	"iframe": true, "img": true, "input": true, "kbd": true, "math": true, "object": true,
+	"picture": true, "samp": true, "svg": true, "var": true, "video": true, "wbr": true,
+}
+
+// skippedTags are block elements whose content is never translated
+var skippedTags = map[string]bool{
+	"pre": true, "script": true, "style": true, "noscript": true, "template": true,
+	"textarea": true, "select": true,
+}
+
+// cellTags are elements whose translation goes inside them, like the reader does for lists and tables
+var cellTags = map[string]bool{"li": true, "td": true, "th": true, "dt": true, "dd": true}
+
+// ArticleTranslation is an HTML article body translated segment by segment
+type ArticleTranslation struct {
+	Content    string // Body with each segment replaced by its translation
+	Bilingual  string // Body with each segment followed by its translation
+	Segments   int    // Number of translatable segments
+	Translated int    // Segments sent to the translator, the rest came from the cache
+}
+
+// SegmentTranslator translates HTML article bodies one block-level segment at a time,
+// preserving inline markup and links and caching each segment on its own, so that a
+// re-fetched article only sends the segments that changed
+type SegmentTranslator struct {
+	translator Translator
+	cache      TranslationCache
+	provider   string
+	refresh    bool
+}
+
+// NewSegmentTranslator creates a segment translator. cache may be nil.
+func NewSegmentTranslator(translator Translator, cache TranslationCache, provider string) *SegmentTranslator {
+	return &SegmentTranslator{
+		translator: translator,
+		cache:      cache,
+		provider:   provider,
+	}
+}
+
+// SetRefresh sets whether segments are translated again instead of being read from the cache
+func (st *SegmentTranslator) SetRefresh(refresh bool) {
+	st.refresh = refresh
+}
+
+// segment is a run of inline sibling nodes translated together
+type segment struct {
+	parent   *html.Node
+	nodes    []*html.Node
+	text     string       // Text with markers in place of inline markup
+	elements []*html.Node // Elements referenced by the markers, numbered from 1
+}
+
+// TranslateHTML translates an HTML article body to targetLang
+func (st *SegmentTranslator) TranslateHTML(ctx context.Context, content, targetLang string) (*ArticleTranslation, error) {
+	root, err := parseFragment(content)
+	if err != nil {
+		return nil, err
+	}
+	segments := collectSegments(root, nil)
+
+	texts := make([]string, len(segments))
+	for i, seg := range segments {
+		texts[i] = seg.text
+	}
+	translations, translated, err := st.translateSegments(ctx, texts, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bilingual view is built on a second copy of the tree, as the first one is rewritten
+	bilingualRoot, err := parseFragment(content)
+	if err != nil {
+		return nil, err
+	}
+	bilingualSegments := collectSegments(bilingualRoot, nil)
+
+	for i, seg := range segments {
+		if translations[i] == seg.text {
+			continue
+		}
+		seg.replace(translations[i])
+		bilingualSegments[i].appendTranslation(translations[i])
+	}
+
+	result := &ArticleTranslation{Segments: len(segments), Translated: translated}
+	if result.Content, err = renderChildren(root); err != nil {
+		return nil, err
+	}
+	if result.Bilingual, err = renderChildren(bilingualRoot); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// translateSegments translates texts, reusing cached segments. It reports how many segments
+// were sent to the translator.
+func (st *SegmentTranslator) translateSegments(ctx context.Context, texts []string, targetLang string) ([]string, int, error) {
+	results := make([]string, len(texts))
+	detector := GetLanguageDetector()
+
+	// Identical segments, such as repeated captions, are translated once
+	pending := make(map[string][]int)
+	var order []string
+	for i, text := range texts {
+		if _, ok := pending[text]; ok {
+			pending[text] = append(pending[text], i)
+			continue
+		}
+		if !st.refresh {
+			if !detector.ShouldTranslate(stripMarkers(text), targetLang) {
+				results[i] = text
+				continue
+			}
+			if st.cache != nil {
+				if cached, found, err := st.cache.GetCachedTranslation(hashText(text), targetLang, st.provider); err == nil && found {
+					results[i] = cached
+					continue
+				}
+			}
+		}
+		pending[text] = []int{i}
+		order = append(order, text)
+	}
+
+	for _, r := range batchRanges(order, maxBatchSegments, maxBatchChars) {
+		batch := order[r[0]:r[1]]
+
+		translations, err := st.translateBatch(ctx, batch, targetLang)
+		if err != nil {
+			return nil, 0, err
+		}
+		for j, text := range batch {
+			for _, i := range pending[text] {
+				results[i] = translations[j]
+			}
+			if st.cache != nil {
+				if err := st.cache.SetCachedTranslation(hashText(text), text, targetLang, translations[j], st.provider); err != nil {
+					// Log but don't fail - caching is optional
+					log.Printf("Warning: failed to cache segment translation: %v", err)
+				}
+			}
+		}
+	}
+	return results, len(order), nil
+}
+
+// translateBatch translates several segments in one request, falling back to one request per
+// segment if the translator doesn't keep the segments apart
+func (st *SegmentTranslator) translateBatch(ctx context.Context, texts []string, targetLang string) ([]string, error) {
+	if len(texts) > 1 {
+		translated, err := translateWithContext(ctx, st.translator, strings.Join(texts, segmentSeparator), targetLang)
+		if err != nil {
+			return nil, err
+		}
+		parts := splitSegments(translated)
+		if len(parts) == len(texts) {
+			return parts, nil
+		}
+		log.Printf("Translation returned %d segments for %d, translating them one by one", len(parts), len(texts))
+	}
+
+	results := make([]string, len(texts))
+	for i, text := range texts {
+		translated, err := translateWithContext(ctx, st.translator, text, targetLang)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = strings.Join(strings.Fields(translated), " ")
+	}
+	return results, nil
+}
+
+// splitSegments splits a translated batch back into its segments
+func splitSegments(text string) []string {
+	var parts []string
+	for _, part := range blankLinePattern.Split(strings.TrimSpace(text), -1) {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// parseFragment parses an HTML fragment into the children of a body element
+func parseFragment(content string) (*html.Node, error) {
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse article content: %w", err)
+	}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+	return root, nil
+}
+
+// collectSegments appends the segments under n in document order. Each run of inline nodes
+// between block elements is a segment; block elements are searched in turn.
+func collectSegments(n *html.Node, segments []*segment) []*segment {
+	var run []*html.Node
+	flush := func() {
+		if seg := newSegment(n, run); seg != nil {
+			segments = append(segments, seg)
+		}
+		run = nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isInline(c) {
+			run = append(run, c)
+			continue
+		}
+		flush()
+		if c.Type == html.ElementNode && !skippedTags[c.Data] {
+			segments = collectSegments(c, segments)
+		}
+	}
+	flush()
+	return segments
+}
+
+// isInline reports whether n can be translated as part of a segment
+func isInline(n *html.Node) bool {
+	switch n.Type {
+	case html.TextNode, html.CommentNode:
+		return true
+	case html.ElementNode:
+		if isKept(n) {
+			return true
+		}
+		if !inlineTags[n.Data] {
+			return false
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if !isInline(c) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isKept reports whether an inline element is kept untranslated, like images, code and formulas
+func isKept(n *html.Node) bool {
+	if keptTags[n.Data] {
+		return true
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == "class" && strings.Contains(attr.Val, "katex") {
+			return true
+		}
+	}
+	return false
+}
+
+// newSegment encodes a run of inline nodes, or returns nil if it has no text to translate
+func newSegment(parent *html.Node, nodes []*html.Node) *segment {
+	if len(nodes) == 0 {
+		return nil
+	}
+	seg := &segment{parent: parent, nodes: nodes}
+	var b strings.Builder
+	var encode func(n *html.Node)
+	encode = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+		case html.ElementNode:
+			seg.elements = append(seg.elements, n)
+			id := len(seg.elements)
+			if isKept(n) {
+				fmt.Fprintf(&b, "[[%d/]]", id)
+				return
+			}
+			fmt.Fprintf(&b, "[[%d]]", id)
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				encode(c)
+			}
+			fmt.Fprintf(&b, "[[/%d]]", id)
+		}
+	}
+	for _, n := range nodes {
+		encode(n)
+	}
+
+	seg.text = strings.Join(strings.Fields(b.String()), " ")
+	if !strings.ContainsFunc(stripMarkers(seg.text), unicode.IsLetter) {
+		return nil
+	}
+	return seg
+}
+
+// stripMarkers removes the inline markup markers from a segment
+func stripMarkers(text string) string {
+	return markerPattern.ReplaceAllString(text, "")
+}
+
+// restore turns a translated segment back into nodes, putting the inline markup back in
+// place. If the markers were lost or mangled, the translation is kept as plain text.
+func (seg *segment) restore(translated string) []*html.Node {
+	container := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	if !seg.restoreInto(container, translated) {
+		container = &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+		container.AppendChild(&html.Node{Type: html.TextNode, Data: strings.Join(strings.Fields(stripMarkers(translated)), " ")})
+	}
+
+	var nodes []*html.Node
+	for c := container.FirstChild; c != nil; {
+		next := c.NextSibling
+		container.RemoveChild(c)
+		nodes = append(nodes, c)
+		c = next
+	}
+	return nodes
+}
+
+// restoreInto appends the nodes of a translated segment to container, reporting whether the
+// markers were balanced
+func (seg *segment) restoreInto(container *html.Node, translated string) bool {
+	stack := []*html.Node{container}
+	var open []int
+	used := make(map[int]bool)
+	appendText := func(text string) {
+		if text != "" {
+			stack[len(stack)-1].AppendChild(&html.Node{Type: html.TextNode, Data: text})
+		}
+	}
+
+	pos := 0
+	for _, m := range markerPattern.FindAllStringSubmatchIndex(translated, -1) {
+		appendText(translated[pos:m[0]])
+		pos = m[1]
+
+		closing, selfClosing := m[3] > m[2], m[7] > m[6]
+		id, _ := strconv.Atoi(translated[m[4]:m[5]])
+		if id < 1 || id > len(seg.elements) {
+			return false
+		}
+		el := seg.elements[id-1]
+		switch {
+		case closing && !selfClosing:
+			if len(open) == 0 || open[len(open)-1] != id {
+				return false
+			}
+			open = open[:len(open)-1]
+			stack = stack[:len(stack)-1]
+		case selfClosing && !closing:
+			if !isKept(el) || used[id] {
+				return false
+			}
+			used[id] = true
+			stack[len(stack)-1].AppendChild(cloneNode(el, true))
+		case !closing && !selfClosing:
+			if isKept(el) || used[id] {
+				return false
+			}
+			used[id] = true
+			wrapper := cloneNode(el, false)
+			stack[len(stack)-1].AppendChild(wrapper)
+			stack = append(stack, wrapper)
+			open = append(open, id)
+		default:
+			return false
+		}
+	}
+	appendText(translated[pos:])
+	return len(open) == 0
+}
+
+// replace replaces the segment's nodes with its translation
+func (seg *segment) replace(translated string) {
+	first := seg.nodes[0]
+	for _, n := range seg.restore(translated) {
+		seg.parent.InsertBefore(n, first)
+	}
+	for _, n := range seg.nodes {
+		seg.parent.RemoveChild(n)
+	}
+}
+
+// appendTranslation inserts the segment's translation after it, placed and classed the way
+// the reader places paragraph translations so that its styles and translation-only mode apply
+func (seg *segment) appendTranslation(translated string) {
+	translation := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, n := range seg.restore(translated) {
+		translation.AppendChild(n)
+	}
+
+	class := "translation-text"
+	parent, before := seg.parent, seg.nodes[len(seg.nodes)-1].NextSibling
+	if cellTags[seg.parent.Data] {
+		// Lists and table cells keep the translation inside the same item
+		class += " translation-inline"
+	} else {
+		if inBlockquote(seg.parent) {
+			class += " translation-blockquote"
+		}
+		// Paragraphs and headings are followed by their translation
+		if seg.parent.Parent != nil && seg.parent.DataAtom != atom.Body &&
+			seg.parent.FirstChild == seg.nodes[0] && seg.parent.LastChild == seg.nodes[len(seg.nodes)-1] {
+			parent, before = seg.parent.Parent, seg.parent.NextSibling
+		}
+	}
+	translation.Attr = []html.Attribute{{Key: "class", Val: class}}
+	parent.InsertBefore(translation, before)
+}
+
+// inBlockquote reports whether n is or is inside a blockquote
+func inBlockquote(n *html.Node) bool {
+	for ; n != nil; n = n.Parent {
+		if n.DataAtom == atom.Blockquote {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneNode copies an element, with its children if deep is set
+func cloneNode(n *html.Node, deep bool) *html.Node {
+	clone := &html.Node{
+		Type:      n.Type,
+		DataAtom:  n.DataAtom,
+		Data:      n.Data,
+		Namespace: n.Namespace,
+		Attr:      append([]html.Attribute(nil), n.Attr...),
+	}
+	if deep {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			clone.AppendChild(cloneNode(c, true))
+		}
+	}
+	return clone
+}
+
+// renderChildren renders the children of n as HTML
+func renderChildren(n *html.Node) (string, error) {
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if err := html.Render(&buf, c); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
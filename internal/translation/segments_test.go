@@ -0,0 +1,122 @@
+package translation
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// upperTranslator "translates" text by upper-casing it, which leaves markers intact
+type upperTranslator struct {
+	calls  int
+	merged bool // Join the segments of a batch into one
+}
+
+func (u *upperTranslator) Translate(text, targetLang string) (string, error) {
+	u.calls++
+	if u.merged {
+		text = strings.ReplaceAll(text, segmentSeparator, " ")
+	}
+	return strings.ToUpper(text), nil
+}
+
+// memoryCache is an in-memory TranslationCache
+type memoryCache map[string]string
+
+func (m memoryCache) GetCachedTranslation(sourceTextHash, targetLang, provider string) (string, bool, error) {
+	translated, ok := m[sourceTextHash+targetLang+provider]
+	return translated, ok, nil
+}
+
+func (m memoryCache) SetCachedTranslation(sourceTextHash, sourceText, targetLang, translatedText, provider string) error {
+	m[sourceTextHash+targetLang+provider] = translatedText
+	return nil
+}
+
+const segmentArticle = `<h2>Launch day</h2>
+<p>The rocket left <a href="https://example.com/pad">the pad</a> on time.<br/>Crowds <em>cheered</em>.</p>
+<ul><li>First stage landed</li><li>Second stage <code>S2</code> reached orbit</li></ul>
+<blockquote><p>We are going back to the moon</p></blockquote>
+<pre><code>launch --now</code></pre>
+<p><img src="https://example.com/rocket.jpg"/></p>`
+
+func TestSegmentTranslator_TranslateHTML(t *testing.T) {
+	translator := &upperTranslator{}
+	cache := memoryCache{}
+	st := NewSegmentTranslator(translator, cache, "test")
+
+	result, err := st.TranslateHTML(context.Background(), segmentArticle, "zh")
+	if err != nil {
+		t.Fatalf("TranslateHTML error: %v", err)
+	}
+	if result.Segments != 5 || result.Translated != 5 || translator.calls != 1 {
+		t.Errorf("expected 5 segments in one batch, got %+v with %d calls", result, translator.calls)
+	}
+
+	for _, want := range []string{
+		`<h2>LAUNCH DAY</h2>`,
+		`<p>THE ROCKET LEFT <a href="https://example.com/pad">THE PAD</a> ON TIME.<br/>CROWDS <em>CHEERED</em>.</p>`,
+		`<li>SECOND STAGE <code>S2</code> REACHED ORBIT</li>`,
+		`<pre><code>launch --now</code></pre>`,
+		`<p><img src="https://example.com/rocket.jpg"/></p>`,
+	} {
+		if !strings.Contains(result.Content, want) {
+			t.Errorf("translated content is missing %s:\n%s", want, result.Content)
+		}
+	}
+
+	for _, want := range []string{
+		`<h2>Launch day</h2><div class="translation-text">LAUNCH DAY</div>`,
+		`<li>First stage landed<div class="translation-text translation-inline">FIRST STAGE LANDED</div></li>`,
+		`<p>We are going back to the moon</p><div class="translation-text translation-blockquote">WE ARE GOING BACK TO THE MOON</div>`,
+	} {
+		if !strings.Contains(result.Bilingual, want) {
+			t.Errorf("bilingual content is missing %s:\n%s", want, result.Bilingual)
+		}
+	}
+
+	// A re-fetched article only translates the segments that changed
+	changed := strings.Replace(segmentArticle, "First stage landed", "First stage was lost", 1)
+	result, err = st.TranslateHTML(context.Background(), changed, "zh")
+	if err != nil {
+		t.Fatalf("TranslateHTML error: %v", err)
+	}
+	if result.Translated != 1 || translator.calls != 2 || !strings.Contains(result.Content, "FIRST STAGE WAS LOST") {
+		t.Errorf("expected only the changed segment to be translated, got %+v with %d calls", result, translator.calls)
+	}
+
+	// Refreshing translates everything again
+	st.SetRefresh(true)
+	if result, err = st.TranslateHTML(context.Background(), changed, "zh"); err != nil || result.Translated != 5 {
+		t.Errorf("expected every segment to be translated again, got %+v (%v)", result, err)
+	}
+}
+
+func TestSegmentTranslator_MergedBatch(t *testing.T) {
+	translator := &upperTranslator{merged: true}
+	st := NewSegmentTranslator(translator, nil, "test")
+
+	result, err := st.TranslateHTML(context.Background(), `<p>First paragraph</p><p>Second paragraph</p>`, "zh")
+	if err != nil {
+		t.Fatalf("TranslateHTML error: %v", err)
+	}
+	if result.Content != `<p>FIRST PARAGRAPH</p><p>SECOND PARAGRAPH</p>` || translator.calls != 3 {
+		t.Errorf("expected the segments to be translated one by one, got %q with %d calls", result.Content, translator.calls)
+	}
+}
+
+func TestSegmentRestore_MangledMarkers(t *testing.T) {
+	root, err := parseFragment(`<p>Read <a href="https://example.com">the docs</a> first</p>`)
+	if err != nil {
+		t.Fatalf("parseFragment error: %v", err)
+	}
+	segments := collectSegments(root, nil)
+	if len(segments) != 1 || segments[0].text != "Read [[1]]the docs[[/1]] first" {
+		t.Fatalf("unexpected segments: %+v", segments)
+	}
+
+	segments[0].replace("Lisez [[/1]]la doc[[1]] d'abord")
+	if got, _ := renderChildren(root); got != "<p>Lisez la doc d&#39;abord</p>" {
+		t.Errorf("expected plain text for mangled markers, got %q", got)
+	}
+}
@@ -0,0 +1,183 @@
+package translation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// TMX (Translation Memory eXchange) properties recording how MrRSS cached a translation
+const (
+	tmxPropProvider = "x-provider"
+	tmxPropEdited   = "x-edited"
+)
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Units   []tmxUnit `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	TMF                 string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+type tmxUnit struct {
+	SrcLang      string       `xml:"srclang,attr,omitempty"`
+	CreationDate string       `xml:"creationdate,attr,omitempty"`
+	Props        []tmxProp    `xml:"prop"`
+	Variants     []tmxVariant `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxVariant struct {
+	Lang       string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	LegacyLang string `xml:"lang,attr,omitempty"` // TMX 1.1 used lang instead of xml:lang
+	Seg        string `xml:"seg"`
+}
+
+func (v tmxVariant) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+func (u tmxUnit) prop(name string) string {
+	for _, p := range u.Props {
+		if p.Type == name {
+			return strings.TrimSpace(p.Value)
+		}
+	}
+	return ""
+}
+
+// CacheKey returns the key under which the translation cache stores the translations of text
+func CacheKey(text string) string {
+	return hashText(text)
+}
+
+// WriteTMX writes cached translations to w as a TMX 1.4 document, one translation unit per entry.
+// The source language of each unit is detected from its text, "und" when it can't be.
+func WriteTMX(w io.Writer, entries []models.TranslationCacheEntry) error {
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "MrRSS",
+			CreationToolVersion: "1",
+			SegType:             "block",
+			TMF:                 "MrRSS translation cache",
+			AdminLang:           "en",
+			SrcLang:             "*all*",
+			DataType:            "plaintext",
+		},
+		Units: make([]tmxUnit, 0, len(entries)),
+	}
+
+	detector := GetLanguageDetector()
+	for _, e := range entries {
+		srcLang := detector.DetectLanguage(e.SourceText)
+		if srcLang == "" {
+			srcLang = "und"
+		}
+		unit := tmxUnit{
+			SrcLang: srcLang,
+			Props:   []tmxProp{{Type: tmxPropProvider, Value: e.Provider}},
+			Variants: []tmxVariant{
+				{Lang: srcLang, Seg: e.SourceText},
+				{Lang: e.TargetLang, Seg: e.TranslatedText},
+			},
+		}
+		if created, err := time.Parse("2006-01-02 15:04:05", e.CreatedAt); err == nil {
+			unit.CreationDate = created.UTC().Format("20060102T150405Z")
+		}
+		if e.Edited {
+			unit.Props = append(unit.Props, tmxProp{Type: tmxPropEdited, Value: "true"})
+		}
+		doc.Units = append(doc.Units, unit)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ReadTMX reads the translations of a TMX document. Each translation unit gives an entry per
+// variant in a language other than its source language, found from the unit's or the header's
+// srclang, or the first variant. Languages are reduced to their primary subtag ("de-DE" to "de"),
+// and the provider is only set when the unit records it.
+func ReadTMX(r io.Reader) ([]models.TranslationCacheEntry, error) {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid TMX document: %w", err)
+	}
+
+	entries := make([]models.TranslationCacheEntry, 0, len(doc.Units))
+	for _, unit := range doc.Units {
+		if len(unit.Variants) < 2 {
+			continue
+		}
+		srcLang := unit.SrcLang
+		if srcLang == "" {
+			srcLang = doc.Header.SrcLang
+		}
+		src := 0
+		for i, v := range unit.Variants {
+			if strings.EqualFold(v.lang(), srcLang) {
+				src = i
+				break
+			}
+		}
+		source := unit.Variants[src].Seg
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+
+		for i, v := range unit.Variants {
+			lang := tmxPrimaryLang(v.lang())
+			if i == src || lang == "" || strings.TrimSpace(v.Seg) == "" {
+				continue
+			}
+			entries = append(entries, models.TranslationCacheEntry{
+				SourceTextHash: CacheKey(source),
+				SourceText:     source,
+				TargetLang:     lang,
+				TranslatedText: v.Seg,
+				Provider:       unit.prop(tmxPropProvider),
+				Edited:         unit.prop(tmxPropEdited) == "true",
+			})
+		}
+	}
+	return entries, nil
+}
+
+// tmxPrimaryLang returns the lowercase primary subtag of a language tag
+func tmxPrimaryLang(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return strings.ToLower(tag)
+}
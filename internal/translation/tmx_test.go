@@ -0,0 +1,74 @@
+package translation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestTMXRoundTrip(t *testing.T) {
+	entries := []models.TranslationCacheEntry{
+		{SourceText: "The government announced on Monday that the new railway line between the two cities will open next spring", TargetLang: "de", TranslatedText: "Die Regierung <b>kündigte</b> am Montag an, dass die neue Bahnstrecke im Frühjahr öffnet", Provider: "deepl", Edited: true, CreatedAt: "2026-01-02 03:04:05"},
+		{SourceText: "Breaking news & more", TargetLang: "zh", TranslatedText: "突发新闻", Provider: "google"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTMX(&buf, entries); err != nil {
+		t.Fatalf("WriteTMX error: %v", err)
+	}
+	doc := buf.String()
+	for _, want := range []string{`<tmx version="1.4">`, `xml:lang="de"`, `srclang="en"`, `creationdate="20260102T030405Z"`, `&lt;b&gt;kündigte`} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("expected the document to contain %q:\n%s", want, doc)
+		}
+	}
+
+	read, err := ReadTMX(&buf)
+	if err != nil {
+		t.Fatalf("ReadTMX error: %v", err)
+	}
+	if len(read) != len(entries) {
+		t.Fatalf("expected %d entries, got %+v", len(entries), read)
+	}
+	for i, e := range read {
+		want := entries[i]
+		if e.SourceText != want.SourceText || e.TargetLang != want.TargetLang || e.TranslatedText != want.TranslatedText ||
+			e.Provider != want.Provider || e.Edited != want.Edited || e.SourceTextHash != CacheKey(want.SourceText) {
+			t.Errorf("entry %d = %+v, want %+v", i, e, want)
+		}
+	}
+}
+
+func TestReadTMX_ExternalDocument(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<tmx version="1.4">
+  <header creationtool="CAT" creationtoolversion="2" segtype="sentence" o-tmf="cat" adminlang="en-US" srclang="EN-US" datatype="plaintext"/>
+  <body>
+    <tu>
+      <tuv xml:lang="de-DE"><seg>Hallo Welt</seg></tuv>
+      <tuv xml:lang="en-US"><seg>Hello world</seg></tuv>
+      <tuv xml:lang="fr-FR"><seg>Bonjour le monde</seg></tuv>
+    </tu>
+    <tu>
+      <tuv lang="EN-US"><seg>Only source</seg></tuv>
+    </tu>
+  </body>
+</tmx>`
+
+	entries, err := ReadTMX(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("ReadTMX error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	if entries[0].SourceText != "Hello world" || entries[0].TargetLang != "de" || entries[1].TargetLang != "fr" || entries[0].Provider != "" {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	if _, err := ReadTMX(strings.NewReader("not xml")); err == nil {
+		t.Error("expected an error for an invalid document")
+	}
+}
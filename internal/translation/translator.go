@@ -2,6 +2,7 @@ package translation
 
 import (
 	"MrRSS/internal/utils"
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -13,6 +14,40 @@ type Translator interface {
 	Translate(text, targetLang string) (string, error)
 }
 
+// ContextTranslator is implemented by translators whose requests can be cancelled
+type ContextTranslator interface {
+	Translator
+	TranslateWithContext(ctx context.Context, text, targetLang string) (string, error)
+}
+
+// BindContext returns a Translator whose requests are cancelled when ctx is done.
+// Translators that don't support cancellation stop before starting a request once ctx is done.
+func BindContext(ctx context.Context, translator Translator) Translator {
+	return &boundTranslator{ctx: ctx, translator: translator}
+}
+
+// boundTranslator translates with a fixed context
+type boundTranslator struct {
+	ctx        context.Context
+	translator Translator
+}
+
+// Translate translates text using the bound context
+func (b *boundTranslator) Translate(text, targetLang string) (string, error) {
+	return translateWithContext(b.ctx, b.translator, text, targetLang)
+}
+
+// translateWithContext translates text with translator, passing ctx on if it supports cancellation
+func translateWithContext(ctx context.Context, translator Translator, text, targetLang string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if ct, ok := translator.(ContextTranslator); ok {
+		return ct.TranslateWithContext(ctx, text, targetLang)
+	}
+	return translator.Translate(text, targetLang)
+}
+
 // DBInterface defines the minimal database interface needed for proxy settings
 type DBInterface interface {
 	GetSetting(key string) (string, error)
@@ -21,22 +56,7 @@ type DBInterface interface {
 
 // CreateHTTPClientWithProxy creates an HTTP client with global proxy settings if enabled
 func CreateHTTPClientWithProxy(db DBInterface, timeout time.Duration) (*http.Client, error) {
-	var proxyURL string
-
-	// Check if global proxy is enabled
-	proxyEnabled, _ := db.GetSetting("proxy_enabled")
-	if proxyEnabled == "true" {
-		// Build proxy URL from global settings
-		proxyType, _ := db.GetSetting("proxy_type")
-		proxyHost, _ := db.GetSetting("proxy_host")
-		proxyPort, _ := db.GetSetting("proxy_port")
-		proxyUsername, _ := db.GetEncryptedSetting("proxy_username")
-		proxyPassword, _ := db.GetEncryptedSetting("proxy_password")
-		proxyURL = utils.BuildProxyURL(proxyType, proxyHost, proxyPort, proxyUsername, proxyPassword)
-	}
-
-	// Create HTTP client with or without proxy
-	return utils.CreateHTTPClient(proxyURL, timeout)
+	return utils.CreateHTTPClientWithProxy(db, timeout)
 }
 
 // MockTranslator is a simple translator for demonstration
@@ -99,9 +99,9 @@ func TestGetLanguageName(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := getLanguageName(tt.code)
+		result := LanguageName(tt.code)
 		if result != tt.expected {
-			t.Errorf("getLanguageName(%s) = %s, want %s", tt.code, result, tt.expected)
+			t.Errorf("LanguageName(%s) = %s, want %s", tt.code, result, tt.expected)
 		}
 	}
 }
@@ -161,6 +161,12 @@ func TestDynamicTranslator_RequiresCredentials(t *testing.T) {
 			settings: map[string]string{"translation_provider": "baidu", "baidu_app_id": "id"},
 			wantErr:  true, // No secret key
 		},
+		{
+			name:     "libretranslate_no_endpoint",
+			provider: "libretranslate",
+			settings: map[string]string{"translation_provider": "libretranslate", "libretranslate_api_key": "key"},
+			wantErr:  true, // No endpoint
+		},
 		{
 			name:     "ai_no_key",
 			provider: "ai",
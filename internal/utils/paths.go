@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 )
 
@@ -170,3 +171,22 @@ func IsWindows() bool {
 func IsMacOS() bool {
 	return runtime.GOOS == "darwin"
 }
+
+// SanitizeFilename creates a safe filename from a title
+func SanitizeFilename(title string) string {
+	// Replace invalid filename characters
+	invalidChars := []string{"<", ">", ":", "\"", "|", "?", "*", "\\", "/"}
+	result := title
+
+	for _, char := range invalidChars {
+		result = strings.ReplaceAll(result, char, "_")
+	}
+
+	// Trim spaces and limit length
+	result = strings.TrimSpace(result)
+	if len(result) > 100 {
+		result = result[:100]
+	}
+
+	return result
+}
@@ -27,6 +27,32 @@ func BuildProxyURL(proxyType, proxyHost, proxyPort, username, password string) s
 	return fmt.Sprintf("%s://%s%s:%s", proxyType, auth, proxyHost, proxyPort)
 }
 
+// ProxySettings reads the global proxy settings
+type ProxySettings interface {
+	GetSetting(key string) (string, error)
+	GetEncryptedSetting(key string) (string, error)
+}
+
+// CreateHTTPClientWithProxy creates an HTTP client with global proxy settings if enabled
+func CreateHTTPClientWithProxy(settings ProxySettings, timeout time.Duration) (*http.Client, error) {
+	var proxyURL string
+
+	// Check if global proxy is enabled
+	proxyEnabled, _ := settings.GetSetting("proxy_enabled")
+	if proxyEnabled == "true" {
+		// Build proxy URL from global settings
+		proxyType, _ := settings.GetSetting("proxy_type")
+		proxyHost, _ := settings.GetSetting("proxy_host")
+		proxyPort, _ := settings.GetSetting("proxy_port")
+		proxyUsername, _ := settings.GetEncryptedSetting("proxy_username")
+		proxyPassword, _ := settings.GetEncryptedSetting("proxy_password")
+		proxyURL = BuildProxyURL(proxyType, proxyHost, proxyPort, proxyUsername, proxyPassword)
+	}
+
+	// Create HTTP client with or without proxy
+	return CreateHTTPClient(proxyURL, timeout)
+}
+
 // CreateHTTPClient creates an HTTP client with optional proxy support
 // This is the canonical implementation with proper TLS config and connection pooling
 func CreateHTTPClient(proxyURL string, timeout time.Duration) (*http.Client, error) {
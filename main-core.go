@@ -18,6 +18,7 @@ import (
 	"syscall"
 	"time"
 
+	"MrRSS/internal/cli"
 	"MrRSS/internal/database"
 	"MrRSS/internal/feed"
 	aihandlers "MrRSS/internal/handlers/ai"
@@ -27,9 +28,11 @@ import (
 	handlers "MrRSS/internal/handlers/core"
 	customcss "MrRSS/internal/handlers/custom_css"
 	discovery "MrRSS/internal/handlers/discovery"
+	extraction "MrRSS/internal/handlers/extraction"
 	feedhandlers "MrRSS/internal/handlers/feed"
 	freshrssHandler "MrRSS/internal/handlers/freshrss"
 	media "MrRSS/internal/handlers/media"
+	metricshandlers "MrRSS/internal/handlers/metrics"
 	networkhandlers "MrRSS/internal/handlers/network"
 	opml "MrRSS/internal/handlers/opml"
 	rsshubHandler "MrRSS/internal/handlers/rsshub"
@@ -84,7 +87,7 @@ type CombinedHandler struct {
 }
 
 func (h *CombinedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if strings.HasPrefix(r.URL.Path, "/api/") {
+	if strings.HasPrefix(r.URL.Path, "/api/") || r.URL.Path == "/metrics" {
 		h.apiMux.ServeHTTP(w, r)
 		return
 	}
@@ -92,6 +95,11 @@ func (h *CombinedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	// Run a command-line subcommand (e.g. "feeds list") instead of the application
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Main(os.Args[1:]))
+	}
+
 	// Parse flags
 	flag.BoolFunc("server", "Run in headless server mode", func(s string) error {
 		v, err := strconv.ParseBool(s)
@@ -161,6 +169,7 @@ func main() {
 	log.Println("Database initialized successfully")
 
 	translator := translation.NewDynamicTranslatorWithCache(db, db)
+	translator.SetGlossaries(db)
 	fetcher := feed.NewFetcher(db)
 	h := handlers.NewHandler(db, fetcher, translator)
 
@@ -182,6 +191,9 @@ func main() {
 	apiMux.HandleFunc("/api/feeds/discover-all/clear", func(w http.ResponseWriter, r *http.Request) { discovery.HandleClearBatchDiscovery(h, w, r) })
 	apiMux.HandleFunc("/api/feeds/reorder", func(w http.ResponseWriter, r *http.Request) { feedhandlers.HandleReorderFeed(h, w, r) })
 	apiMux.HandleFunc("/api/feeds/test-imap", func(w http.ResponseWriter, r *http.Request) { feedhandlers.HandleTestIMAPConnection(h, w, r) })
+	apiMux.HandleFunc("/api/feeds/health", func(w http.ResponseWriter, r *http.Request) { feedhandlers.HandleFeedHealth(h, w, r) })
+	apiMux.HandleFunc("/api/feeds/health/history", func(w http.ResponseWriter, r *http.Request) { feedhandlers.HandleFeedFetchHistory(h, w, r) })
+	apiMux.HandleFunc("/api/feeds/health/resume", func(w http.ResponseWriter, r *http.Request) { feedhandlers.HandleResumeFeed(h, w, r) })
 	apiMux.HandleFunc("/api/articles", func(w http.ResponseWriter, r *http.Request) { article.HandleArticles(h, w, r) })
 	apiMux.HandleFunc("/api/articles/images", func(w http.ResponseWriter, r *http.Request) { article.HandleImageGalleryArticles(h, w, r) })
 	apiMux.HandleFunc("/api/articles/filter", func(w http.ResponseWriter, r *http.Request) { article.HandleFilteredArticles(h, w, r) })
@@ -192,11 +204,31 @@ func main() {
 	apiMux.HandleFunc("/api/articles/content-cache-info", func(w http.ResponseWriter, r *http.Request) { article.HandleGetArticleContentCacheInfo(h, w, r) })
 	apiMux.HandleFunc("/api/articles/translate", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleTranslateArticle(h, w, r) })
 	apiMux.HandleFunc("/api/articles/translate-text", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleTranslateText(h, w, r) })
+	apiMux.HandleFunc("/api/articles/translate-batch", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleTranslateArticles(h, w, r) })
+	apiMux.HandleFunc("/api/articles/translate-content", func(w http.ResponseWriter, r *http.Request) {
+		translationhandlers.HandleTranslateArticleContent(h, w, r)
+	})
 	apiMux.HandleFunc("/api/articles/clear-translations", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleClearTranslations(h, w, r) })
 	apiMux.HandleFunc("/api/ai-usage", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleGetAIUsage(h, w, r) })
 	apiMux.HandleFunc("/api/ai-usage/reset", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleResetAIUsage(h, w, r) })
 	apiMux.HandleFunc("/api/translation/test-custom", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleTestCustomTranslation(h, w, r) })
+	apiMux.HandleFunc("/api/translation/glossary", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleGlossary(h, w, r) })
+	apiMux.HandleFunc("/api/translation/glossary/delete", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleDeleteGlossaryEntry(h, w, r) })
+	apiMux.HandleFunc("/api/translation/cache", func(w http.ResponseWriter, r *http.Request) { translationhandlers.HandleTranslationCacheStats(h, w, r) })
+	apiMux.HandleFunc("/api/translation/cache/entries", func(w http.ResponseWriter, r *http.Request) {
+		translationhandlers.HandleTranslationCacheEntries(h, w, r)
+	})
+	apiMux.HandleFunc("/api/translation/cache/delete", func(w http.ResponseWriter, r *http.Request) {
+		translationhandlers.HandleDeleteTranslationCache(h, w, r)
+	})
+	apiMux.HandleFunc("/api/translation/cache/export", func(w http.ResponseWriter, r *http.Request) {
+		translationhandlers.HandleExportTranslationCache(h, w, r)
+	})
+	apiMux.HandleFunc("/api/translation/cache/import", func(w http.ResponseWriter, r *http.Request) {
+		translationhandlers.HandleImportTranslationCache(h, w, r)
+	})
 	apiMux.HandleFunc("/api/ai-chat", func(w http.ResponseWriter, r *http.Request) { chat.HandleAIChat(h, w, r) })
+	apiMux.HandleFunc("/api/ai-chat/stream", func(w http.ResponseWriter, r *http.Request) { chat.HandleAIChatStream(h, w, r) })
 	apiMux.HandleFunc("/api/ai/chat/sessions/delete-all", func(w http.ResponseWriter, r *http.Request) { chat.HandleDeleteAllSessions(h, w, r) })
 	apiMux.HandleFunc("/api/ai/chat/sessions", func(w http.ResponseWriter, r *http.Request) { chat.HandleListSessions(h, w, r) })
 	apiMux.HandleFunc("/api/ai/chat/session/create", func(w http.ResponseWriter, r *http.Request) { chat.HandleCreateSession(h, w, r) })
@@ -214,11 +246,31 @@ func main() {
 	})
 	apiMux.HandleFunc("/api/ai/chat/messages", func(w http.ResponseWriter, r *http.Request) { chat.HandleListMessages(h, w, r) })
 	apiMux.HandleFunc("/api/ai/chat/message/delete", func(w http.ResponseWriter, r *http.Request) { chat.HandleDeleteMessage(h, w, r) })
+	apiMux.HandleFunc("/api/ai/chat/search", func(w http.ResponseWriter, r *http.Request) { chat.HandleSearchMessages(h, w, r) })
+	apiMux.HandleFunc("/api/ai/chat/session/pin", func(w http.ResponseWriter, r *http.Request) { chat.HandlePinSession(h, w, r) })
+	apiMux.HandleFunc("/api/ai/chat/session/export", func(w http.ResponseWriter, r *http.Request) { chat.HandleExportSession(h, w, r) })
+	apiMux.HandleFunc("/api/ai/chat/session/export/obsidian", func(w http.ResponseWriter, r *http.Request) { chat.HandleExportSessionToObsidian(h, w, r) })
 	apiMux.HandleFunc("/api/ai/test", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleTestAIConfig(h, w, r) })
 	apiMux.HandleFunc("/api/ai/test/info", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleGetAITestInfo(h, w, r) })
+	apiMux.HandleFunc("/api/ai/profiles", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleAIProfiles(h, w, r) })
+	apiMux.HandleFunc("/api/ai/profiles/delete", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleDeleteAIProfile(h, w, r) })
+	apiMux.HandleFunc("/api/ai/profiles/reset-usage", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleResetAIProfileUsage(h, w, r) })
+	apiMux.HandleFunc("/api/ai/prompts", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandlePromptTemplates(h, w, r) })
+	apiMux.HandleFunc("/api/ai/prompts/delete", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleDeletePromptTemplate(h, w, r) })
+	apiMux.HandleFunc("/api/ai/prompts/preview", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandlePreviewPromptTemplate(h, w, r) })
+	apiMux.HandleFunc("/api/ai/routes", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleAIRoutes(h, w, r) })
+	apiMux.HandleFunc("/api/ai/budgets", func(w http.ResponseWriter, r *http.Request) { aihandlers.HandleAIBudgets(h, w, r) })
 	apiMux.HandleFunc("/api/articles/toggle-hide", func(w http.ResponseWriter, r *http.Request) { article.HandleToggleHideArticle(h, w, r) })
+	apiMux.HandleFunc("/api/articles/dwell", func(w http.ResponseWriter, r *http.Request) { article.HandleRecordArticleDwell(h, w, r) })
+	apiMux.HandleFunc("/api/articles/tags", func(w http.ResponseWriter, r *http.Request) { article.HandleArticleTags(h, w, r) })
 	apiMux.HandleFunc("/api/articles/toggle-read-later", func(w http.ResponseWriter, r *http.Request) { article.HandleToggleReadLater(h, w, r) })
 	apiMux.HandleFunc("/api/articles/content", func(w http.ResponseWriter, r *http.Request) { article.HandleGetArticleContent(h, w, r) })
+	apiMux.HandleFunc("/api/articles/semantic-search", func(w http.ResponseWriter, r *http.Request) { article.HandleSemanticSearch(h, w, r) })
+	apiMux.HandleFunc("/api/articles/keywords", func(w http.ResponseWriter, r *http.Request) { article.HandleArticleKeywords(h, w, r) })
+	apiMux.HandleFunc("/api/keywords/trending", func(w http.ResponseWriter, r *http.Request) { article.HandleTrendingKeywords(h, w, r) })
+	apiMux.HandleFunc("/api/keywords/extract", func(w http.ResponseWriter, r *http.Request) { article.HandleExtractKeywords(h, w, r) })
+	apiMux.HandleFunc("/api/articles/semantic-status", func(w http.ResponseWriter, r *http.Request) { article.HandleSemanticStatus(h, w, r) })
+	apiMux.HandleFunc("/api/articles/similar", func(w http.ResponseWriter, r *http.Request) { article.HandleSimilarArticles(h, w, r) })
 	apiMux.HandleFunc("/api/articles/fetch-full", func(w http.ResponseWriter, r *http.Request) { article.HandleFetchFullArticle(h, w, r) })
 	apiMux.HandleFunc("/api/articles/extract-images", func(w http.ResponseWriter, r *http.Request) { article.HandleExtractAllImages(h, w, r) })
 	apiMux.HandleFunc("/api/articles/unread-counts", func(w http.ResponseWriter, r *http.Request) { article.HandleGetUnreadCounts(h, w, r) })
@@ -226,12 +278,15 @@ func main() {
 	apiMux.HandleFunc("/api/articles/mark-all-read", func(w http.ResponseWriter, r *http.Request) { article.HandleMarkAllAsRead(h, w, r) })
 	apiMux.HandleFunc("/api/articles/clear-read-later", func(w http.ResponseWriter, r *http.Request) { article.HandleClearReadLater(h, w, r) })
 	apiMux.HandleFunc("/api/articles/summarize", func(w http.ResponseWriter, r *http.Request) { summary.HandleSummarizeArticle(h, w, r) })
+	apiMux.HandleFunc("/api/articles/summarize/stream", func(w http.ResponseWriter, r *http.Request) { summary.HandleSummarizeArticleStream(h, w, r) })
+	apiMux.HandleFunc("/api/articles/summaries", func(w http.ResponseWriter, r *http.Request) { summary.HandleArticleSummaries(h, w, r) })
 	apiMux.HandleFunc("/api/articles/clear-summaries", func(w http.ResponseWriter, r *http.Request) { summary.HandleClearSummaries(h, w, r) })
 	apiMux.HandleFunc("/api/articles/export/obsidian", func(w http.ResponseWriter, r *http.Request) { article.HandleExportToObsidian(h, w, r) })
 	apiMux.HandleFunc("/api/settings", func(w http.ResponseWriter, r *http.Request) { settings.HandleSettings(h, w, r) })
 	apiMux.HandleFunc("/api/refresh", func(w http.ResponseWriter, r *http.Request) { article.HandleRefresh(h, w, r) })
 	apiMux.HandleFunc("/api/progress", func(w http.ResponseWriter, r *http.Request) { article.HandleProgress(h, w, r) })
 	apiMux.HandleFunc("/api/progress/task-details", func(w http.ResponseWriter, r *http.Request) { article.HandleTaskDetails(h, w, r) })
+	apiMux.HandleFunc("/api/progress/background-jobs/retry", func(w http.ResponseWriter, r *http.Request) { article.HandleRetryBackgroundJobs(h, w, r) })
 	apiMux.HandleFunc("/api/opml/import", func(w http.ResponseWriter, r *http.Request) { opml.HandleOPMLImport(h, w, r) })
 	apiMux.HandleFunc("/api/opml/export", func(w http.ResponseWriter, r *http.Request) { opml.HandleOPMLExport(h, w, r) })
 	apiMux.HandleFunc("/api/opml/import-dialog", func(w http.ResponseWriter, r *http.Request) { opml.HandleOPMLImportDialog(h, w, r) })
@@ -241,6 +296,9 @@ func main() {
 	apiMux.HandleFunc("/api/install-update", func(w http.ResponseWriter, r *http.Request) { update.HandleInstallUpdate(h, w, r) })
 	apiMux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) { update.HandleVersion(h, w, r) })
 	apiMux.HandleFunc("/api/rules/apply", func(w http.ResponseWriter, r *http.Request) { rules.HandleApplyRule(h, w, r) })
+	apiMux.HandleFunc("/api/extraction-rules", func(w http.ResponseWriter, r *http.Request) { extraction.HandleExtractionRules(h, w, r) })
+	apiMux.HandleFunc("/api/extraction-rules/delete", func(w http.ResponseWriter, r *http.Request) { extraction.HandleDeleteExtractionRule(h, w, r) })
+	apiMux.HandleFunc("/api/extraction-rules/test", func(w http.ResponseWriter, r *http.Request) { extraction.HandleTestExtraction(h, w, r) })
 	apiMux.HandleFunc("/api/scripts/dir", func(w http.ResponseWriter, r *http.Request) { script.HandleGetScriptsDir(h, w, r) })
 	apiMux.HandleFunc("/api/scripts/open", func(w http.ResponseWriter, r *http.Request) { script.HandleOpenScriptsDir(h, w, r) })
 	apiMux.HandleFunc("/api/scripts/list", func(w http.ResponseWriter, r *http.Request) { script.HandleListScripts(h, w, r) })
@@ -276,6 +334,8 @@ func main() {
 	})
 	apiMux.HandleFunc("/api/statistics/all-time", func(w http.ResponseWriter, r *http.Request) { stathandlers.HandleGetAllTimeStatistics(h, w, r) })
 	apiMux.HandleFunc("/api/statistics/available-months", func(w http.ResponseWriter, r *http.Request) { stathandlers.HandleGetAvailableMonths(h, w, r) })
+	// Prometheus metrics
+	apiMux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) { metricshandlers.HandleMetrics(h, w, r) })
 
 	// Swagger Documentation - Serve swagger.json file
 	apiMux.HandleFunc("/docs/SERVER_MODE/swagger.json", func(w http.ResponseWriter, r *http.Request) {
@@ -305,6 +365,8 @@ func main() {
 	// Start background scheduler
 	// Use a context that we can cancel on shutdown
 	bgCtx, bgCancel := context.WithCancel(context.Background())
+	// Cancel in-flight AI requests on shutdown
+	h.SetAppContext(bgCtx)
 
 	log.Println("Starting background scheduler...")
 	go h.StartBackgroundScheduler(bgCtx)